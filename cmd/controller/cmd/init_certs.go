@@ -7,10 +7,10 @@ import (
 
 	webhookConfig "github.com/flyteorg/flytepropeller/pkg/webhook/config"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 
 	corev1 "k8s.io/api/core/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -84,7 +84,7 @@ func runCertsCmd(ctx context.Context, propellerCfg *config.Config, cfg *webhookC
 		return err
 	}
 
-	kubeClient, _, err := getKubeConfig(ctx, propellerCfg)
+	kubeClient, _, err := getKubeConfig(ctx, propellerCfg, nil)
 	if err != nil {
 		return err
 	}
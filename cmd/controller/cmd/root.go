@@ -4,6 +4,7 @@ package cmd
 import (
 	"context"
 	"flag"
+	"net/http"
 	"os"
 	"runtime"
 	"runtime/pprof"
@@ -14,6 +15,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"k8s.io/klog"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	config2 "github.com/flyteorg/flytepropeller/pkg/controller/config"
@@ -24,10 +26,11 @@ import (
 	"github.com/flyteorg/flytestdlib/version"
 
 	"github.com/flyteorg/flytestdlib/config"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/profutils"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/pflag"
 
 	"github.com/spf13/cobra"
@@ -37,9 +40,13 @@ import (
 
 	restclient "k8s.io/client-go/rest"
 
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	clientset "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned"
 	informers "github.com/flyteorg/flytepropeller/pkg/client/informers/externalversions"
 	"github.com/flyteorg/flytepropeller/pkg/controller"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+	"github.com/flyteorg/flytepropeller/pkg/controller/tracing"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytepropeller/pkg/signals"
 )
 
@@ -116,12 +123,18 @@ func logAndExit(err error) {
 	os.Exit(-1)
 }
 
-func getKubeConfig(_ context.Context, cfg *config2.Config) (*kubernetes.Clientset, *restclient.Config, error) {
+// Builds the client of the control plane cluster. Its requests are recorded in the given metrics, if any.
+func getKubeConfig(_ context.Context, cfg *config2.Config, metrics *controller.KubeAPIMetrics) (*kubernetes.Clientset, *restclient.Config, error) {
+	return buildKubeConfig(cfg.KubeConfigPath, cfg.MasterURL, cfg.KubeConfig, metrics)
+}
+
+func buildKubeConfig(kubeConfigPath, masterURL string, clientCfg config2.KubeClientConfig,
+	metrics *controller.KubeAPIMetrics) (*kubernetes.Clientset, *restclient.Config, error) {
 	var kubecfg *restclient.Config
 	var err error
-	if cfg.KubeConfigPath != "" {
-		kubeConfigPath := os.ExpandEnv(cfg.KubeConfigPath)
-		kubecfg, err = clientcmd.BuildConfigFromFlags(cfg.MasterURL, kubeConfigPath)
+	if kubeConfigPath != "" {
+		kubeConfigPath = os.ExpandEnv(kubeConfigPath)
+		kubecfg, err = clientcmd.BuildConfigFromFlags(masterURL, kubeConfigPath)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "Error building kubeconfig")
 		}
@@ -132,9 +145,12 @@ func getKubeConfig(_ context.Context, cfg *config2.Config) (*kubernetes.Clientse
 		}
 	}
 
-	kubecfg.QPS = cfg.KubeConfig.QPS
-	kubecfg.Burst = cfg.KubeConfig.Burst
-	kubecfg.Timeout = cfg.KubeConfig.Timeout.Duration
+	kubecfg.QPS = clientCfg.QPS
+	kubecfg.Burst = clientCfg.Burst
+	kubecfg.Timeout = clientCfg.Timeout.Duration
+	if metrics != nil {
+		metrics.InstrumentConfig(kubecfg)
+	}
 
 	kubeClient, err := kubernetes.NewForConfig(kubecfg)
 	if err != nil {
@@ -143,6 +159,36 @@ func getKubeConfig(_ context.Context, cfg *config2.Config) (*kubernetes.Clientse
 	return kubeClient, kubecfg, err
 }
 
+// Returns the client config for the cluster task resources are launched in, which is the control plane cluster unless a
+// data plane cluster is configured. Probes for all clusters are registered with the health checker.
+func getTaskKubeConfig(ctx context.Context, cfg *config2.Config, kubeClient kubernetes.Interface, kubecfg *restclient.Config,
+	healthChecker *controller.ClusterHealthChecker, scope promutils.Scope) (*restclient.Config, error) {
+
+	if !cfg.DataPlane.Enabled {
+		return kubecfg, nil
+	}
+
+	if cfg.DataPlane.KubeConfigPath == "" {
+		return nil, errors.Errorf("a kube-config is required for data plane cluster [%v]", cfg.DataPlane.Name)
+	}
+
+	if cfg.DataPlane.HealthCheckInterval.Duration <= 0 {
+		return nil, errors.Errorf("health check interval must be positive, found [%v]", cfg.DataPlane.HealthCheckInterval.Duration)
+	}
+
+	logger.Infof(ctx, "Launching task resources on data plane cluster [%v]", cfg.DataPlane.Name)
+	dataPlaneMetrics := controller.NewKubeAPIMetrics(scope.NewSubScope(safeMetricName(cfg.DataPlane.Name)).NewSubScope("kube_api"))
+	dataPlaneClient, dataPlaneKubecfg, err := buildKubeConfig(cfg.DataPlane.KubeConfigPath, cfg.DataPlane.MasterURL,
+		cfg.DataPlane.KubeConfig, dataPlaneMetrics)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error building client for data plane cluster [%v]", cfg.DataPlane.Name)
+	}
+
+	healthChecker.AddCluster("control-plane", controller.NewKubeHealthProbe(kubeClient), scope)
+	healthChecker.AddCluster(cfg.DataPlane.Name, controller.NewKubeHealthProbe(dataPlaneClient), scope)
+	return dataPlaneKubecfg, nil
+}
+
 func sharedInformerOptions(cfg *config2.Config) []informers.SharedInformerOption {
 	opts := []informers.SharedInformerOption{
 		informers.WithTweakListOptions(func(options *v1.ListOptions) {
@@ -166,7 +212,10 @@ func executeRootCmd(cfg *config2.Config) {
 	// set up signals so we handle the first shutdown signal gracefully
 	ctx := signals.SetupSignalHandler(baseCtx)
 
-	kubeClient, kubecfg, err := getKubeConfig(ctx, cfg)
+	// Add the propeller subscope because the MetricsPrefix only has "flyte:" to get uniform collection of metrics.
+	propellerScope := promutils.NewScope(cfg.MetricsPrefix).NewSubScope("propeller").NewSubScope(safeMetricName(cfg.LimitNamespace))
+
+	kubeClient, kubecfg, err := getKubeConfig(ctx, cfg, controller.NewKubeAPIMetrics(propellerScope.NewSubScope("kube_api")))
 	if err != nil {
 		logger.Fatalf(ctx, "Error building kubernetes clientset: %s", err.Error())
 	}
@@ -176,14 +225,45 @@ func executeRootCmd(cfg *config2.Config) {
 		logger.Fatalf(ctx, "Error building example clientset: %s", err.Error())
 	}
 
+	if cfg.CRD.Validate || cfg.CRD.AutoRegister {
+		crdClient, err := client.New(kubecfg, client.Options{})
+		if err != nil {
+			logger.Fatalf(ctx, "Error building client to check the FlyteWorkflow CRD: %s", err.Error())
+		}
+
+		if err := controller.EnsureFlyteWorkflowCRD(ctx, cfg.CRD, crdClient); err != nil {
+			logger.Fatalf(ctx, "Invalid FlyteWorkflow CRD: %s", err.Error())
+		}
+	}
+
 	opts := sharedInformerOptions(cfg)
 	flyteworkflowInformerFactory := informers.NewSharedInformerFactoryWithOptions(flyteworkflowClient, cfg.WorkflowReEval.Duration, opts...)
 
-	// Add the propeller subscope because the MetricsPrefix only has "flyte:" to get uniform collection of metrics.
-	propellerScope := promutils.NewScope(cfg.MetricsPrefix).NewSubScope("propeller").NewSubScope(safeMetricName(cfg.LimitNamespace))
+	healthChecker := controller.NewClusterHealthChecker(cfg.DataPlane.HealthCheckInterval.Duration)
+	taskKubecfg, err := getTaskKubeConfig(ctx, cfg, kubeClient, kubecfg, healthChecker, propellerScope.NewSubScope("clusters"))
+	if err != nil {
+		logger.Fatalf(ctx, "Error building data plane clientset: %s", err.Error())
+	}
+
+	clientBuilder := executors.NewFallbackClientBuilder()
+	var handlers map[string]http.Handler
+	if cfg.DataPlane.Enabled {
+		// The FlyteWorkflow owners of task resources only exist in the control plane cluster.
+		clientBuilder.WithoutOwnerReferences(v1alpha1.FlyteWorkflowKind)
+		handlers = map[string]http.Handler{"/clusters": healthChecker}
+		healthChecker.Run(ctx)
+	}
+
+	if tracingCfg := tracing.GetConfig(); tracingCfg.Enabled {
+		// The metrics endpoint of the profiling server does not negotiate the OpenMetrics format, which is the only one
+		// that carries the exemplars linking the latency histograms to the traces of executions.
+		http.Handle(tracingCfg.OpenMetricsPath, promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+			EnableOpenMetrics: true,
+		}))
+	}
 
 	go func() {
-		err := profutils.StartProfilingServerWithDefaultHandlers(ctx, cfg.ProfilerPort.Port, nil)
+		err := profutils.StartProfilingServerWithDefaultHandlers(ctx, cfg.ProfilerPort.Port, handlers)
 		if err != nil {
 			logger.Panicf(ctx, "Failed to Start profiling and metrics server. Error: %v", err)
 		}
@@ -194,10 +274,10 @@ func executeRootCmd(cfg *config2.Config) {
 		limitNamespace = cfg.LimitNamespace
 	}
 
-	mgr, err := manager.New(kubecfg, manager.Options{
+	mgr, err := manager.New(taskKubecfg, manager.Options{
 		Namespace:     limitNamespace,
 		SyncPeriod:    &cfg.DownstreamEval.Duration,
-		ClientBuilder: executors.NewFallbackClientBuilder(),
+		ClientBuilder: clientBuilder,
 	})
 	if err != nil {
 		logger.Fatalf(ctx, "Failed to initialize controller run-time manager. Error: %v", err)
@@ -225,6 +305,15 @@ func executeRootCmd(cfg *config2.Config) {
 		logger.Fatalf(ctx, "Failed to start Controller, nil controller received.")
 	}
 
+	if cfg.Autoscaling.Enabled {
+		// The profiling server serves the handlers registered with the default mux, including ones registered after it started.
+		http.Handle(controller.AutoscalingPath, c.LoadReporter())
+	}
+
+	if handler := c.ExecutionEventHandler(); handler != nil {
+		http.Handle(launchplan.GetAdminConfig().Watch.Path, handler)
+	}
+
 	go flyteworkflowInformerFactory.Start(ctx.Done())
 
 	if err = c.Run(ctx); err != nil {
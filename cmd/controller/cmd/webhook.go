@@ -15,15 +15,16 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/flyteorg/flytepropeller/pkg/controller"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/signals"
 	"github.com/flyteorg/flytepropeller/pkg/webhook"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/profutils"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -105,14 +106,14 @@ func runWebhook(origContext context.Context, propellerCfg *config.Config, cfg *w
 
 	fmt.Println(string(raw))
 
-	kubeClient, kubecfg, err := getKubeConfig(ctx, propellerCfg)
+	// Add the propeller subscope because the MetricsPrefix only has "flyte:" to get uniform collection of metrics.
+	propellerScope := promutils.NewScope(cfg.MetricsPrefix).NewSubScope("propeller").NewSubScope(safeMetricName(propellerCfg.LimitNamespace))
+
+	kubeClient, kubecfg, err := getKubeConfig(ctx, propellerCfg, controller.NewKubeAPIMetrics(propellerScope.NewSubScope("kube_api")))
 	if err != nil {
 		return err
 	}
 
-	// Add the propeller subscope because the MetricsPrefix only has "flyte:" to get uniform collection of metrics.
-	propellerScope := promutils.NewScope(cfg.MetricsPrefix).NewSubScope("propeller").NewSubScope(safeMetricName(propellerCfg.LimitNamespace))
-
 	go func() {
 		err := profutils.StartProfilingServerWithDefaultHandlers(ctx, propellerCfg.ProfilerPort.Port, nil)
 		if err != nil {
@@ -150,6 +151,13 @@ func runWebhook(origContext context.Context, propellerCfg *config.Config, cfg *w
 		logger.Fatalf(ctx, "Failed to register webhook with manager. Error: %v", err)
 	}
 
+	if cfg.SecretRefresh.Enabled {
+		refresher := webhook.NewSecretRefresher(cfg.SecretRefresh, mgr.GetClient(), propellerScope.NewSubScope("secret_refresh"))
+		if err := mgr.Add(refresher); err != nil {
+			logger.Fatalf(ctx, "Failed to add the secret refresher to manager. Error: %v", err)
+		}
+	}
+
 	logger.Infof(ctx, "Starting controller-runtime manager")
 	return mgr.Start(ctx)
 }
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/grpcclient"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task"
+	catalogConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+	datacatalogClient "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog/datacatalog"
+)
+
+const taskFileKey = "task-path"
+
+// The datacatalog API has no call to delete artifacts or tags.
+var errEvictionUnsupported = fmt.Errorf("the catalog does not support deleting artifacts or tags; to stop serving " +
+	"the artifact, bump the cache version of the task or set a max cache age below the age of the artifact, " +
+	"e.g. with the cache_max_age key of the task config")
+
+// cacheLookupClient is the subset of the datacatalog client used to look up cached outputs.
+type cacheLookupClient interface {
+	GetDataset(ctx context.Context, key catalog.Key) (*datacatalog.Dataset, error)
+	GetArtifactByTag(ctx context.Context, tagName string, dataset *datacatalog.Dataset) (*datacatalog.Artifact, error)
+}
+
+type CacheOpts struct {
+	*RootOptions
+	configFile string
+	taskFile   string
+	inputsPath string
+	format     format
+}
+
+type CacheCheckOpts struct {
+	*CacheOpts
+	offline bool
+}
+
+func NewCacheCommand(opts *RootOptions) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspects the catalog cache of tasks",
+	}
+
+	cacheCmd.AddCommand(NewCacheCheckCommand(opts))
+	cacheCmd.AddCommand(NewCacheEvictCommand(opts))
+	return cacheCmd
+}
+
+// Adds the flags that identify a task execution to the command.
+func (c *CacheOpts) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&c.configFile, "config", "", "Path to the propeller config file that defines the catalog configuration.")
+	cmd.Flags().StringVarP(&c.taskFile, taskFileKey, "t", "", "Path of the task template file.")
+	cmd.Flags().StringVarP(&c.inputsPath, inputsKey, "i", "", "Path to inputs file.")
+	cmd.Flags().StringVarP(&c.format, formatKey, "f", formatProto, "Format of the provided files. Supported formats: proto (default), json, yaml")
+}
+
+func NewCacheCheckCommand(opts *RootOptions) *cobra.Command {
+
+	checkOpts := &CacheCheckOpts{
+		CacheOpts: &CacheOpts{RootOptions: opts},
+	}
+
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Reports whether executing a task with the given inputs would hit the catalog cache",
+		Long: `computes the dataset and tag the outputs of the task execution are cached under, the same way propeller does, and
+looks them up in the configured catalog. Nothing is written to the catalog, which makes it safe for debugging unexpected
+cache misses.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requiredFlags(cmd, taskFileKey); err != nil {
+				return err
+			}
+
+			return checkOpts.checkCache(context.Background())
+		},
+	}
+
+	checkOpts.addFlags(checkCmd)
+	checkCmd.Flags().BoolVar(&checkOpts.offline, "offline", false, "Only computes the dataset and tag, without querying the catalog.")
+
+	return checkCmd
+}
+
+func NewCacheEvictCommand(opts *RootOptions) *cobra.Command {
+
+	evictOpts := &CacheOpts{RootOptions: opts}
+
+	evictCmd := &cobra.Command{
+		Use:   "evict",
+		Short: "Resolves the cached outputs of executing a task with the given inputs, so that they can be evicted",
+		Long: `looks up the artifact that serves the cache hits of the task execution and prints it along with the execution
+that produced it. The datacatalog API has no call to delete artifacts or tags, so the command does not delete the entry
+and fails with the ways to stop it from being served.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requiredFlags(cmd, taskFileKey); err != nil {
+				return err
+			}
+
+			return evictOpts.evictCache(context.Background())
+		},
+	}
+
+	evictOpts.addFlags(evictCmd)
+	return evictCmd
+}
+
+// cacheLookup is where the outputs of a task execution are cached in the catalog.
+type cacheLookup struct {
+	key            catalog.Key
+	datasetID      *datacatalog.DatasetID
+	tag            string
+	maxCacheAge    time.Duration
+	hasMaxCacheAge bool
+}
+
+// Computes the dataset and tag the outputs of executing the task with the given inputs are cached under.
+func newCacheLookup(ctx context.Context, tk *core.TaskTemplate, inputs *core.LiteralMap, cfg *catalogConfig.Config) (cacheLookup, error) {
+	if tk.GetId() == nil || tk.GetInterface() == nil {
+		return cacheLookup{}, fmt.Errorf("task template must have an id and an interface")
+	}
+
+	if inputs == nil {
+		inputs = &core.LiteralMap{}
+	}
+
+	key, err := task.NewCatalogKey(ctx, tk, task.NewStaticInputReader(inputs))
+	if err != nil {
+		return cacheLookup{}, errors.Wrapf(err, "Failed to generate catalog key.")
+	}
+
+	datasetID, err := datacatalogClient.GenerateDatasetIDForTask(ctx, key)
+	if err != nil {
+		return cacheLookup{}, errors.Wrapf(err, "Failed to generate dataset id.")
+	}
+
+	tag, err := datacatalogClient.GenerateArtifactTagName(ctx, inputs)
+	if err != nil {
+		return cacheLookup{}, errors.Wrapf(err, "Failed to generate artifact tag.")
+	}
+
+	maxCacheAge, hasMaxCacheAge := task.GetTaskMaxCacheAge(ctx, tk, cfg)
+	if !hasMaxCacheAge {
+		maxCacheAge = cfg.MaxCacheAge.Duration
+	}
+
+	return cacheLookup{
+		key:            key,
+		datasetID:      datasetID,
+		tag:            tag,
+		maxCacheAge:    maxCacheAge,
+		hasMaxCacheAge: hasMaxCacheAge,
+	}, nil
+}
+
+// Returns the artifact that serves the cache hits of the task execution, regardless of its age.
+func (l cacheLookup) resolveArtifact(ctx context.Context, client cacheLookupClient) (*datacatalog.Artifact, error) {
+	dataset, err := client.GetDataset(ctx, l.key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get dataset.")
+	}
+
+	artifact, err := client.GetArtifactByTag(datacatalogClient.WithMaxCacheAge(ctx, 0), l.tag, dataset)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get artifact.")
+	}
+
+	return artifact, nil
+}
+
+// Looks up the cached outputs in the catalog and returns a description of the result. Only failures to reach the
+// catalog are returned as errors, cache misses are part of the description.
+func (l cacheLookup) check(ctx context.Context, client cacheLookupClient) (string, error) {
+	dataset, err := client.GetDataset(ctx, l.key)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "MISS: the dataset does not exist, no execution of this task version has been cached", nil
+		}
+
+		return "", errors.Wrapf(err, "Failed to get dataset.")
+	}
+
+	if l.hasMaxCacheAge {
+		ctx = datacatalogClient.WithMaxCacheAge(ctx, l.maxCacheAge)
+	}
+
+	artifact, err := client.GetArtifactByTag(ctx, l.tag, dataset)
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Code() == codes.NotFound {
+			return fmt.Sprintf("MISS: no artifact is tagged with the hash of the inputs (%v)", s.Message()), nil
+		}
+
+		return "", errors.Wrapf(err, "Failed to get artifact.")
+	}
+
+	createdAt, err := ptypes.Timestamp(artifact.GetCreatedAt())
+	if err != nil {
+		return fmt.Sprintf("HIT: artifact [%v]", artifact.GetId()), nil
+	}
+
+	return fmt.Sprintf("HIT: artifact [%v] created at [%v]", artifact.GetId(), createdAt.Format(time.RFC3339)), nil
+}
+
+// Loads the config, the task template and the inputs and computes where the outputs of the execution are cached.
+func (c *CacheOpts) loadCacheLookup(ctx context.Context) (cacheLookup, *core.TaskTemplate, error) {
+	if c.configFile != "" {
+		if _, err := loadPropellerConfig(ctx, c.configFile); err != nil {
+			return cacheLookup{}, nil, err
+		}
+	}
+
+	raw, err := ioutil.ReadFile(c.taskFile)
+	if err != nil {
+		return cacheLookup{}, nil, err
+	}
+
+	tk := &core.TaskTemplate{}
+	if err := unmarshal(raw, c.format, tk); err != nil {
+		return cacheLookup{}, nil, errors.Wrapf(err, "Failed to unmarshal task template.")
+	}
+
+	var inputs *core.LiteralMap
+	if c.inputsPath != "" {
+		inputs, err = loadInputs(c.inputsPath, c.format)
+		if err != nil {
+			return cacheLookup{}, nil, errors.Wrapf(err, "Failed to load inputs.")
+		}
+	}
+
+	lookup, err := newCacheLookup(ctx, tk, inputs, catalogConfig.GetConfig())
+	if err != nil {
+		return cacheLookup{}, nil, err
+	}
+
+	fmt.Printf("Cache key mode: %v\n", catalogConfig.GetConfig().CacheKeyMode)
+	fmt.Printf("Dataset:        %v/%v/%v/%v\n", lookup.datasetID.GetProject(), lookup.datasetID.GetDomain(),
+		lookup.datasetID.GetName(), lookup.datasetID.GetVersion())
+	fmt.Printf("Tag:            %v\n", lookup.tag)
+	fmt.Printf("Max cache age:  %v\n", lookup.maxCacheAge)
+	return lookup, tk, nil
+}
+
+// Creates a client of the configured datacatalog.
+func newCacheLookupClient(ctx context.Context) (*datacatalogClient.CatalogClient, error) {
+	cfg := catalogConfig.GetConfig()
+	if cfg.Type != catalogConfig.DataCatalogType {
+		return nil, fmt.Errorf("catalog type [%v] cannot be queried, only [%v] is supported", cfg.Type, catalogConfig.DataCatalogType)
+	}
+
+	client, err := datacatalogClient.NewDataCatalog(ctx, cfg.Endpoint, cfg.Insecure, cfg.MaxCacheAge.Duration,
+		grpcclient.NewFactory(grpcclient.GetConfig()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create catalog client.")
+	}
+
+	return client, nil
+}
+
+func (c *CacheCheckOpts) checkCache(ctx context.Context) error {
+	lookup, tk, err := c.loadCacheLookup(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !tk.GetMetadata().GetDiscoverable() {
+		fmt.Println("Warning: the task is not discoverable, its executions are never looked up in the catalog.")
+	}
+
+	if c.offline {
+		return nil
+	}
+
+	client, err := newCacheLookupClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := lookup.check(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result)
+	return nil
+}
+
+func (c *CacheOpts) evictCache(ctx context.Context) error {
+	lookup, _, err := c.loadCacheLookup(ctx)
+	if err != nil {
+		return err
+	}
+
+	client, err := newCacheLookupClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	artifact, err := lookup.resolveArtifact(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Artifact:       %v\n", artifact.GetId())
+	for k, v := range artifact.GetMetadata().GetKeyMap() {
+		fmt.Printf("  %v: %v\n", k, v)
+	}
+
+	return errEvictionUnsupported
+}
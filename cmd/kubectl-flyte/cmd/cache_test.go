@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	catalogConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+)
+
+type fakeCacheLookupClient struct {
+	datasetErr  error
+	artifactErr error
+	tag         string
+}
+
+func (f *fakeCacheLookupClient) GetDataset(_ context.Context, _ catalog.Key) (*datacatalog.Dataset, error) {
+	if f.datasetErr != nil {
+		return nil, f.datasetErr
+	}
+
+	return &datacatalog.Dataset{}, nil
+}
+
+func (f *fakeCacheLookupClient) GetArtifactByTag(_ context.Context, tagName string, _ *datacatalog.Dataset) (*datacatalog.Artifact, error) {
+	f.tag = tagName
+	if f.artifactErr != nil {
+		return nil, f.artifactErr
+	}
+
+	return &datacatalog.Artifact{Id: "artifact"}, nil
+}
+
+func TestCacheLookup(t *testing.T) {
+	ctx := context.TODO()
+	tk := &core.TaskTemplate{
+		Id:       &core.Identifier{Project: "p", Domain: "d", Name: "task", Version: "v"},
+		Metadata: &core.TaskMetadata{Discoverable: true, DiscoveryVersion: "1"},
+		Interface: &core.TypedInterface{
+			Inputs: createVariableMap(map[string]*core.Variable{"x": {Type: &core.LiteralType{
+				Type: &core.LiteralType_Simple{Simple: core.SimpleType_INTEGER}}}}),
+		},
+	}
+
+	inputs := &core.LiteralMap{Literals: map[string]*core.Literal{"x": coreutils.MustMakeLiteral(1)}}
+	cfg := &catalogConfig.Config{
+		MaxCacheAge:     config.Duration{Duration: time.Hour},
+		TaskMaxCacheAge: map[string]config.Duration{},
+	}
+
+	lookup, err := newCacheLookup(ctx, tk, inputs, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "flyte_task-task", lookup.datasetID.GetName())
+	assert.Equal(t, "p", lookup.datasetID.GetProject())
+	assert.Contains(t, lookup.datasetID.GetVersion(), "1-")
+	assert.NotEmpty(t, lookup.tag)
+	assert.Equal(t, time.Hour, lookup.maxCacheAge)
+	assert.False(t, lookup.hasMaxCacheAge)
+
+	other, err := newCacheLookup(ctx, tk, &core.LiteralMap{Literals: map[string]*core.Literal{"x": coreutils.MustMakeLiteral(2)}}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, lookup.datasetID.String(), other.datasetID.String())
+	assert.NotEqual(t, lookup.tag, other.tag)
+
+	t.Run("hit", func(t *testing.T) {
+		client := &fakeCacheLookupClient{}
+		result, err := lookup.check(ctx, client)
+		assert.NoError(t, err)
+		assert.Contains(t, result, "HIT: artifact [artifact]")
+		assert.Equal(t, lookup.tag, client.tag)
+	})
+
+	t.Run("missing dataset", func(t *testing.T) {
+		result, err := lookup.check(ctx, &fakeCacheLookupClient{datasetErr: status.Error(codes.NotFound, "not found")})
+		assert.NoError(t, err)
+		assert.Contains(t, result, "MISS: the dataset does not exist")
+	})
+
+	t.Run("expired artifact", func(t *testing.T) {
+		result, err := lookup.check(ctx, &fakeCacheLookupClient{artifactErr: status.Error(codes.NotFound, "Artifact over age limit")})
+		assert.NoError(t, err)
+		assert.Contains(t, result, "Artifact over age limit")
+	})
+
+	t.Run("catalog unavailable", func(t *testing.T) {
+		_, err := lookup.check(ctx, &fakeCacheLookupClient{datasetErr: status.Error(codes.Unavailable, "down")})
+		assert.Error(t, err)
+	})
+
+	t.Run("resolve artifact", func(t *testing.T) {
+		artifact, err := lookup.resolveArtifact(ctx, &fakeCacheLookupClient{})
+		assert.NoError(t, err)
+		assert.Equal(t, "artifact", artifact.GetId())
+
+		_, err = lookup.resolveArtifact(ctx, &fakeCacheLookupClient{artifactErr: status.Error(codes.NotFound, "not found")})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing interface", func(t *testing.T) {
+		_, err := newCacheLookup(ctx, &core.TaskTemplate{Id: tk.Id}, nil, cfg)
+		assert.Error(t, err)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"time"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -27,6 +28,7 @@ const (
 	protofileKey   = "proto-path"
 	formatKey      = "format"
 	executionIDKey = "execution-id"
+	scheduledAtKey = "scheduled-time"
 	inputsKey      = "input-path"
 	annotationsKey = "annotations"
 )
@@ -45,6 +47,7 @@ type CreateOpts struct {
 	*RootOptions
 	format      format
 	execID      string
+	scheduledAt string
 	inputsPath  string
 	protoFile   string
 	annotations *stringMapValue
@@ -77,6 +80,8 @@ func NewCreateCommand(opts *RootOptions) *cobra.Command {
 	createCmd.Flags().StringVarP(&createOpts.protoFile, protofileKey, "p", "", "Path of the workflow package proto-buffer file to be uploaded")
 	createCmd.Flags().StringVarP(&createOpts.format, formatKey, "f", formatProto, "Format of the provided file. Supported formats: proto (default), json, yaml")
 	createCmd.Flags().StringVarP(&createOpts.execID, executionIDKey, "", "", "Execution Id of the Workflow to create.")
+	createCmd.Flags().StringVarP(&createOpts.scheduledAt, scheduledAtKey, "", "", "RFC3339 time the Workflow is scheduled for. "+
+		"Derives a deterministic execution id from the workflow and the time, so that retrying the creation is safe.")
 	createCmd.Flags().StringVarP(&createOpts.inputsPath, inputsKey, "i", "", "Path to inputs file.")
 	createOpts.annotations = newStringMapValue()
 	createCmd.Flags().VarP(createOpts.annotations, annotationsKey, "a", "Defines extra annotations to declare on the created object.")
@@ -187,6 +192,18 @@ func (c *CreateOpts) createWorkflowFromProto() error {
 		}
 	}
 
+	var scheduledAt time.Time
+	if len(c.scheduledAt) > 0 {
+		if len(c.execID) > 0 {
+			return fmt.Errorf("only one of [%v] and [%v] can be set", executionIDKey, scheduledAtKey)
+		}
+
+		scheduledAt, err = time.Parse(time.RFC3339, c.scheduledAt)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to parse scheduled time.")
+		}
+	}
+
 	var executionID *core.WorkflowExecutionIdentifier
 	if len(c.execID) > 0 {
 		executionID = &core.WorkflowExecutionIdentifier{
@@ -209,6 +226,12 @@ func (c *CreateOpts) createWorkflowFromProto() error {
 	}
 
 	if c.dryRun {
+		if !scheduledAt.IsZero() {
+			k8s.SetScheduledExecution(flyteWf, wfClosure.Workflow.Id, scheduledAt)
+			flyteWf.Name = k8s.ScheduledExecutionName(wfClosure.Workflow.Id, scheduledAt, 0)
+			flyteWf.GenerateName = ""
+		}
+
 		fmt.Printf("Dry Run mode enabled. Printing the compiled workflow.")
 		j, err := json.Marshal(flyteWf)
 		if err != nil {
@@ -219,6 +242,15 @@ func (c *CreateOpts) createWorkflowFromProto() error {
 			return errors.Wrapf(err, "Failed to marshal final workflow from json to yaml.")
 		}
 		fmt.Println(string(y))
+	} else if !scheduledAt.IsZero() {
+		// The workflow is scheduled by the identity of the workflow, as there is no launch plan.
+		wf, err := k8s.CreateScheduledFlyteWorkflow(context.TODO(), c.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(
+			c.ConfigOverrides.Context.Namespace), flyteWf, wfClosure.Workflow.Id, scheduledAt)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Flyte Workflow %v is scheduled for %v.\n", wf.Name, c.scheduledAt)
 	} else {
 		wf, err := c.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(c.ConfigOverrides.Context.Namespace).Create(context.TODO(), flyteWf, v1.CreateOptions{})
 		if err != nil {
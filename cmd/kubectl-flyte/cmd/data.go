@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+// Written by flytekit next to the outputs of tasks that render decks.
+const deckFileName = "deck.html"
+
+// lastModifiedMetadata is implemented by the metadata of stores that can tell when an object was last written.
+type lastModifiedMetadata interface {
+	LastModified() time.Time
+}
+
+type DataOpts struct {
+	*RootOptions
+	configFile string
+	json       bool
+}
+
+// nodeDataFile is a file a node reads or writes in the datastore.
+type nodeDataFile struct {
+	NodeID       v1alpha1.NodeID       `json:"nodeId"`
+	Kind         string                `json:"kind"`
+	Location     storage.DataReference `json:"location"`
+	Exists       bool                  `json:"exists"`
+	SizeBytes    int64                 `json:"sizeBytes,omitempty"`
+	LastModified *time.Time            `json:"lastModified,omitempty"`
+}
+
+func NewDataCommand(opts *RootOptions) *cobra.Command {
+
+	dataOpts := &DataOpts{
+		RootOptions: opts,
+	}
+
+	dataCmd := &cobra.Command{
+		Use:   "data [opts] <workflow_name>",
+		Short: "Lists the inputs, outputs, errors, futures and decks of every node of a workflow in the datastore",
+		Long: `reads the data dirs of the nodes from the status of the workflow and looks up the files nodes read and write
+there, with their sizes. It only needs access to the objects themselves, so it can be used to debug the data of a
+workflow without permissions to list the bucket. Timestamps are only shown for stores that report them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dataOpts.describeData(context.Background(), args[0])
+		},
+	}
+
+	dataCmd.Flags().StringVar(&dataOpts.configFile, "config", "", "Path to the propeller config file that defines the storage configuration.")
+	dataCmd.Flags().BoolVar(&dataOpts.json, "json", false, "Prints the files as JSON instead of a table.")
+
+	return dataCmd
+}
+
+func (d *DataOpts) describeData(ctx context.Context, name string) error {
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 {
+		d.ConfigOverrides.Context.Namespace = parts[0]
+		name = parts[1]
+	}
+
+	store, err := loadPropellerConfig(ctx, d.configFile)
+	if err != nil {
+		return err
+	}
+
+	w, err := d.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(d.ConfigOverrides.Context.Namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	files, err := describeNodeData(ctx, store, w.Status.NodeStatus)
+	if err != nil {
+		return err
+	}
+
+	return printNodeData(os.Stdout, files, d.json)
+}
+
+// Returns the files of the nodes with the given statuses, including the nodes of their subworkflows and branches, sorted
+// by node.
+func describeNodeData(ctx context.Context, store *storage.DataStore, statuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) (
+	[]nodeDataFile, error) {
+
+	keys := make([]v1alpha1.NodeID, 0, len(statuses))
+	for key := range statuses {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	var files []nodeDataFile
+	for _, key := range keys {
+		s := statuses[key]
+		// The statuses of sub-nodes are keyed by the id of the sub-node suffixed with the attempt of their parent.
+		nodeID := v1alpha1.StripAttemptSuffix(key)
+		nodeFiles := make([]nodeDataFile, 0, 5)
+		if inputsRef := s.GetInputsRef(); len(inputsRef) > 0 {
+			nodeFiles = append(nodeFiles, nodeDataFile{NodeID: nodeID, Kind: "inputs", Location: inputsRef})
+		} else if dataDir := s.GetDataDir(); len(dataDir) > 0 {
+			nodeFiles = append(nodeFiles, nodeDataFile{NodeID: nodeID, Kind: "inputs", Location: v1alpha1.GetInputsFile(dataDir)})
+		}
+
+		if outputDir := s.GetOutputDir(); len(outputDir) > 0 {
+			for _, f := range [][2]string{
+				{"outputs", ioutils.OutputsSuffix},
+				{"error", ioutils.ErrorsSuffix},
+				{"futures", ioutils.FuturesSuffix},
+				{"deck", deckFileName},
+			} {
+				ref, err := store.ConstructReference(ctx, outputDir, f[1])
+				if err != nil {
+					return nil, err
+				}
+
+				nodeFiles = append(nodeFiles, nodeDataFile{NodeID: nodeID, Kind: f[0], Location: ref})
+			}
+		}
+
+		for _, file := range nodeFiles {
+			metadata, err := store.Head(ctx, file.Location)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up the %v of node [%v] at [%v]: %v", file.Kind, nodeID, file.Location, err)
+			}
+
+			if file.Exists = metadata.Exists(); file.Exists {
+				file.SizeBytes = metadata.Size()
+				if m, ok := metadata.(lastModifiedMetadata); ok {
+					lastModified := m.LastModified()
+					file.LastModified = &lastModified
+				}
+			}
+
+			files = append(files, file)
+		}
+
+		subFiles, err := describeNodeData(ctx, store, s.SubNodeStatus)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, subFiles...)
+	}
+
+	return files, nil
+}
+
+func printNodeData(out io.Writer, files []nodeDataFile, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(files)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tKIND\tSIZE\tMODIFIED\tLOCATION")
+	for _, f := range files {
+		size, modified := "missing", "-"
+		if f.Exists {
+			size = fmt.Sprintf("%d", f.SizeBytes)
+		}
+
+		if f.LastModified != nil {
+			modified = f.LastModified.UTC().Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", f.NodeID, f.Kind, size, modified, f.Location)
+	}
+
+	return tw.Flush()
+}
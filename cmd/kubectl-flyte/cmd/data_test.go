@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func TestDescribeNodeData(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	assert.NoError(t, store.WriteProtobuf(ctx, "s3://bucket/n1/inputs.pb", storage.Options{}, &core.LiteralMap{
+		Literals: map[string]*core.Literal{"x": {}}}))
+	assert.NoError(t, store.WriteProtobuf(ctx, "s3://bucket/n1/0/outputs.pb", storage.Options{}, &core.LiteralMap{
+		Literals: map[string]*core.Literal{"y": {}}}))
+
+	statuses := map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+		"n1": {DataDir: "s3://bucket/n1", OutputDir: "s3://bucket/n1/0"},
+		"n0": {
+			InputsRef: "s3://bucket/n1/inputs.pb",
+			SubNodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				"sub@0": {DataDir: "s3://bucket/sub"},
+			},
+		},
+	}
+
+	files, err := describeNodeData(ctx, store, statuses)
+	assert.NoError(t, err)
+	if !assert.Len(t, files, 7) {
+		return
+	}
+
+	assert.Equal(t, nodeDataFile{NodeID: "n0", Kind: "inputs", Location: "s3://bucket/n1/inputs.pb", Exists: true,
+		SizeBytes: files[0].SizeBytes}, files[0])
+	assert.True(t, files[0].SizeBytes > 0)
+	assert.Equal(t, nodeDataFile{NodeID: "sub", Kind: "inputs", Location: "s3://bucket/sub/inputs.pb"}, files[1])
+	assert.Equal(t, "n1", files[2].NodeID)
+	assert.True(t, files[2].Exists)
+	assert.Equal(t, "outputs", files[3].Kind)
+	assert.True(t, files[3].Exists)
+	for _, f := range files[4:] {
+		assert.False(t, f.Exists)
+	}
+
+	assert.Equal(t, []string{"error", "futures", "deck"}, []string{files[4].Kind, files[5].Kind, files[6].Kind})
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printNodeData(out, files, false))
+	assert.Contains(t, out.String(), "missing")
+	assert.Contains(t, out.String(), "s3://bucket/n1/0/deck.html")
+}
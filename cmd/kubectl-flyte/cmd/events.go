@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/spf13/cobra"
+
+	controllerConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/eventstore"
+)
+
+type EventsReplayOpts struct {
+	*RootOptions
+	configFile string
+}
+
+func NewEventsCommand(opts *RootOptions) *cobra.Command {
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Manages the events stored in the metadata store of clusters that cannot reach admin",
+	}
+
+	eventsCmd.AddCommand(NewEventsReplayCommand(opts))
+	return eventsCmd
+}
+
+func NewEventsReplayCommand(opts *RootOptions) *cobra.Command {
+
+	replayOpts := &EventsReplayOpts{
+		RootOptions: opts,
+	}
+
+	replayCmd := &cobra.Command{
+		Use:   "replay [opts] <project>/<domain>/<name>...",
+		Short: "Sends the events stored in the metadata store for the given executions to admin",
+		Long: `reads the events that propeller stored in the metadata store, while the event store was enabled, and sends them
+to the admin configured in the propeller config file. Events admin already has are skipped, and the progress of every
+execution is checkpointed, so replays can be resumed and repeated safely.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replayOpts.replayEvents(context.Background(), args)
+		},
+	}
+
+	replayCmd.Flags().StringVar(&replayOpts.configFile, "config", "", "Path to the propeller config file that defines the storage, event store and admin configuration.")
+
+	return replayCmd
+}
+
+func parseExecutionID(execID string) (*core.WorkflowExecutionIdentifier, error) {
+	parts := strings.Split(execID, "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("execution [%v] should be formatted as <project>/<domain>/<name>", execID)
+	}
+
+	return &core.WorkflowExecutionIdentifier{Project: parts[0], Domain: parts[1], Name: parts[2]}, nil
+}
+
+func (r *EventsReplayOpts) replayEvents(ctx context.Context, execIDs []string) error {
+	ids := make([]*core.WorkflowExecutionIdentifier, 0, len(execIDs))
+	for _, execID := range execIDs {
+		id, err := parseExecutionID(execID)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, id)
+	}
+
+	store, err := loadPropellerConfig(ctx, r.configFile)
+	if err != nil {
+		return err
+	}
+
+	// Events are replayed to admin, regardless of the event sink propeller is configured with.
+	adminCfg := *events.GetConfig(ctx)
+	adminCfg.Type = events.EventSinkAdmin
+	sink, err := events.ConstructEventSink(ctx, &adminCfg)
+	if err != nil {
+		return err
+	}
+
+	replayer, err := eventstore.NewReplayer(ctx, store, controllerConfig.GetConfig().MetadataPrefix, eventstore.GetConfig(), sink)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		result, err := replayer.Replay(ctx, id)
+		fmt.Printf("Execution [%v/%v/%v]: sent [%v], skipped [%v], previously replayed [%v]\n", id.Project, id.Domain,
+			id.Name, result.Sent, result.Skipped, result.Previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
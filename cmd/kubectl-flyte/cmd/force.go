@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+const reasonKey = "reason"
+
+type ForceOpts struct {
+	*RootOptions
+	reason      string
+	requestedBy string
+	outputsURI  string
+	yes         bool
+}
+
+func NewForceCommand(opts *RootOptions) *cobra.Command {
+	forceCmd := &cobra.Command{
+		Use:   "force",
+		Short: "Forces a running node that is stuck to fail or succeed",
+		Long: `aborts a running node, e.g. one whose external resource is in a state it will never recover from, and moves it
+to the requested phase. The request is recorded in the flyte.org/force-nodes annotation of the workflow and is applied
+by propeller on its next evaluation of the workflow, which emits an event naming who forced the node and why.`,
+	}
+
+	forceCmd.AddCommand(newForcePhaseCommand(opts, v1alpha1.ForcedNodePhaseFailed))
+	forceCmd.AddCommand(newForcePhaseCommand(opts, v1alpha1.ForcedNodePhaseSucceeded))
+	return forceCmd
+}
+
+func newForcePhaseCommand(opts *RootOptions, phase v1alpha1.ForcedNodePhase) *cobra.Command {
+	forceOpts := &ForceOpts{RootOptions: opts}
+
+	use := "fail"
+	if phase == v1alpha1.ForcedNodePhaseSucceeded {
+		use = "succeed"
+	}
+
+	cmd := &cobra.Command{
+		Use:   use + " [opts] <workflow_name> <node_id>",
+		Short: fmt.Sprintf("Forces a running node to %v", use),
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requiredFlags(cmd, reasonKey); err != nil {
+				return err
+			}
+
+			return forceOpts.forceNode(context.Background(), args[0], args[1], phase)
+		},
+	}
+
+	cmd.Flags().StringVar(&forceOpts.reason, reasonKey, "", "Why the node is forced, recorded in the event that audits it.")
+	cmd.Flags().StringVar(&forceOpts.requestedBy, "requested-by", os.Getenv("USER"), "Operator forcing the node.")
+	cmd.Flags().BoolVarP(&forceOpts.yes, "yes", "y", false, "Applies the request; without it the request is only printed.")
+	if phase == v1alpha1.ForcedNodePhaseSucceeded {
+		cmd.Flags().StringVarP(&forceOpts.outputsURI, "outputs", "o", "",
+			"Outputs file to copy as the outputs of the node; required if downstream nodes consume its outputs.")
+	}
+
+	return cmd
+}
+
+func (f *ForceOpts) forceNode(ctx context.Context, name, nodeID string, phase v1alpha1.ForcedNodePhase) error {
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 {
+		f.ConfigOverrides.Context.Namespace = parts[0]
+		name = parts[1]
+	}
+
+	request := v1alpha1.NodeForceRequest{
+		Phase:       phase,
+		OutputsURI:  v1alpha1.DataReference(f.outputsURI),
+		Reason:      f.reason,
+		RequestedBy: f.requestedBy,
+	}
+
+	client := f.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(f.ConfigOverrides.Context.Namespace)
+	w, err := client.Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := addNodeForceRequest(w, nodeID, request); err != nil {
+		return err
+	}
+
+	if !f.yes {
+		fmt.Printf("Node [%v] of workflow [%v] would be forced to %v by [%v]: %v\nRe-run with --yes to apply.\n",
+			nodeID, name, phase, request.RequestedBy, request.Reason)
+		return nil
+	}
+
+	// The update fails if the workflow changed since it was read, rather than overwriting the changes.
+	if _, err := client.Update(ctx, w, v1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Node [%v] of workflow [%v] will be forced to %v on the next evaluation of the workflow.\n", nodeID, name, phase)
+	return nil
+}
+
+// Adds the request to force the node to the force nodes annotation of the workflow. Only nodes of the workflow that
+// started and have not completed yet can be forced, nodes of subworkflows and branches are forced through their parent.
+func addNodeForceRequest(w *v1alpha1.FlyteWorkflow, nodeID string, request v1alpha1.NodeForceRequest) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	if w.GetExecutionStatus().IsTerminated() {
+		return fmt.Errorf("workflow [%v] has already completed", w.GetName())
+	}
+
+	s, found := w.Status.NodeStatus[nodeID]
+	if !found {
+		return fmt.Errorf("node [%v] has not started", nodeID)
+	}
+
+	if s.GetPhase() == v1alpha1.NodePhaseNotYetStarted || v1alpha1.IsPhaseTerminal(s.GetPhase()) {
+		return fmt.Errorf("node [%v] is in phase [%v], only running nodes can be forced", nodeID, s.GetPhase().String())
+	}
+
+	annotations := w.GetAnnotations()
+	requests, err := v1alpha1.GetNodeForceRequests(annotations)
+	if err != nil {
+		return err
+	}
+
+	if requests == nil {
+		requests = make(map[string]v1alpha1.NodeForceRequest)
+	}
+
+	requests[nodeID] = request
+	raw, err := json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[v1alpha1.ForceNodesAnnotation] = string(raw)
+	w.SetAnnotations(annotations)
+	return nil
+}
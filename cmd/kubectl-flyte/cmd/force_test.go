@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func TestAddNodeForceRequest(t *testing.T) {
+	newWorkflow := func() *v1alpha1.FlyteWorkflow {
+		return &v1alpha1.FlyteWorkflow{
+			ObjectMeta: v1.ObjectMeta{Name: "wf"},
+			Status: v1alpha1.WorkflowStatus{
+				Phase: v1alpha1.WorkflowPhaseRunning,
+				NodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+					"n1": {Phase: v1alpha1.NodePhaseRunning},
+					"n2": {Phase: v1alpha1.NodePhaseQueued},
+					"n3": {Phase: v1alpha1.NodePhaseSucceeded},
+				},
+			},
+		}
+	}
+
+	failed := v1alpha1.NodeForceRequest{Phase: v1alpha1.ForcedNodePhaseFailed, Reason: "stuck", RequestedBy: "op"}
+	succeeded := v1alpha1.NodeForceRequest{Phase: v1alpha1.ForcedNodePhaseSucceeded, OutputsURI: "s3://bucket/outputs.pb",
+		Reason: "done manually", RequestedBy: "op"}
+
+	t.Run("added", func(t *testing.T) {
+		w := newWorkflow()
+		assert.NoError(t, addNodeForceRequest(w, "n1", failed))
+		assert.NoError(t, addNodeForceRequest(w, "n2", succeeded))
+		requests, err := v1alpha1.GetNodeForceRequests(w.GetAnnotations())
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]v1alpha1.NodeForceRequest{"n1": failed, "n2": succeeded}, requests)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		w := newWorkflow()
+		assert.Error(t, addNodeForceRequest(w, "n3", failed))
+		assert.Error(t, addNodeForceRequest(w, "n4", failed))
+		assert.Error(t, addNodeForceRequest(w, "n1", v1alpha1.NodeForceRequest{Phase: v1alpha1.ForcedNodePhaseFailed}))
+		assert.Empty(t, w.GetAnnotations())
+
+		w.Status.Phase = v1alpha1.WorkflowPhaseFailed
+		assert.Error(t, addNodeForceRequest(w, "n1", failed))
+	})
+}
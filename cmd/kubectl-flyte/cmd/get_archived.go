@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gotree "github.com/DiSiqueira/GoTree"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/flyteorg/flytepropeller/cmd/kubectl-flyte/cmd/printers"
+	"github.com/flyteorg/flytepropeller/pkg/controller/archival"
+)
+
+type GetArchivedOpts struct {
+	*RootOptions
+	configFile string
+	raw        bool
+}
+
+func NewGetArchivedCommand(opts *RootOptions) *cobra.Command {
+
+	getArchivedOpts := &GetArchivedOpts{
+		RootOptions: opts,
+	}
+
+	getArchivedCmd := &cobra.Command{
+		Use:   "get-archived [opts] <workflow_name>",
+		Short: "Gets a single workflow that was archived before it was garbage collected",
+		Long:  `reads the archived workflow from the datastore configured in the propeller config file`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getArchivedOpts.getArchivedWorkflow(context.Background(), args[0])
+		},
+	}
+
+	getArchivedCmd.Flags().StringVar(&getArchivedOpts.configFile, "config", "", "Path to the propeller config file that defines the storage and archival configuration.")
+	getArchivedCmd.Flags().BoolVarP(&getArchivedOpts.raw, "raw", "r", false, "Print the full archived workflow as yaml.")
+
+	return getArchivedCmd
+}
+
+func (g *GetArchivedOpts) getArchivedWorkflow(ctx context.Context, name string) error {
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 {
+		g.ConfigOverrides.Context.Namespace = parts[0]
+		name = parts[1]
+	}
+
+	store, err := loadPropellerConfig(ctx, g.configFile)
+	if err != nil {
+		return err
+	}
+
+	basePrefix, err := archival.GetArchivePrefix(ctx, store, archival.GetConfig())
+	if err != nil {
+		return err
+	}
+
+	w, err := archival.Load(ctx, store, basePrefix, g.ConfigOverrides.Context.Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if g.raw {
+		j, err := json.Marshal(w)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to marshal archived workflow.")
+		}
+
+		y, err := yaml.JSONToYAML(j)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to marshal archived workflow from json to yaml.")
+		}
+
+		fmt.Println(string(y))
+		return nil
+	}
+
+	wp := printers.WorkflowPrinter{}
+	tree := gotree.New("Archived Workflow")
+	w.DataReferenceConstructor = storage.URLPathConstructor{}
+	if err := wp.Print(ctx, tree, w); err != nil {
+		return err
+	}
+
+	fmt.Print(tree.Print())
+	return nil
+}
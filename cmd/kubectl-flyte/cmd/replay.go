@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/archival"
+	"github.com/flyteorg/flytepropeller/pkg/controller/replay"
+)
+
+type ReplayOpts struct {
+	*RootOptions
+	configFile string
+	archived   bool
+	rounds     int
+}
+
+func NewReplayCommand(opts *RootOptions) *cobra.Command {
+
+	replayOpts := &ReplayOpts{
+		RootOptions: opts,
+	}
+
+	replayCmd := &cobra.Command{
+		Use:   "replay [opts] <workflow_name>",
+		Short: "Re-executes the evaluation of a workflow in memory and prints the decisions taken for every node",
+		Long: `loads a live or archived workflow and runs the workflow executor against fake clients. Data is read from
+the configured datastore, but nothing is written to it or to the cluster.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replayOpts.replayWorkflow(context.Background(), args[0])
+		},
+	}
+
+	replayCmd.Flags().StringVar(&replayOpts.configFile, "config", "", "Path to the propeller config file that defines the storage, plugin and executor configuration.")
+	replayCmd.Flags().BoolVarP(&replayOpts.archived, "archived", "a", false, "Replay an archived workflow instead of a live one.")
+	replayCmd.Flags().IntVarP(&replayOpts.rounds, "rounds", "r", 10, "Maximum number of evaluation rounds to run.")
+
+	return replayCmd
+}
+
+func (r *ReplayOpts) replayWorkflow(ctx context.Context, name string) error {
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 {
+		r.ConfigOverrides.Context.Namespace = parts[0]
+		name = parts[1]
+	}
+
+	store, err := loadPropellerConfig(ctx, r.configFile)
+	if err != nil {
+		return err
+	}
+
+	var w *v1alpha1.FlyteWorkflow
+	if r.archived {
+		basePrefix, err := archival.GetArchivePrefix(ctx, store, archival.GetConfig())
+		if err != nil {
+			return err
+		}
+
+		w, err = archival.Load(ctx, store, basePrefix, r.ConfigOverrides.Context.Namespace, name)
+		if err != nil {
+			return err
+		}
+	} else {
+		w, err = r.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(r.ConfigOverrides.Context.Namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	harness, err := replay.NewHarness(ctx, store, promutils.NewScope("replay"))
+	if err != nil {
+		return err
+	}
+
+	decisions, final, err := harness.Replay(ctx, w, r.rounds)
+	for _, d := range decisions {
+		fmt.Println(d.String())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Workflow [%v] is in phase [%v] after replaying.\n", final.GetName(), final.Status.Phase.String())
+	return nil
+}
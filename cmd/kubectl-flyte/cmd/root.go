@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/flyteorg/flytestdlib/config/viper"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/version"
 	"github.com/spf13/pflag"
 	"k8s.io/client-go/kubernetes"
@@ -17,6 +16,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	flyteclient "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +24,7 @@ func init() {
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	err := flag.CommandLine.Parse([]string{})
 	if err != nil {
-		logger.Error(context.TODO(), "Error in initializing: %v", err)
+		logger.Errorf(context.TODO(), "Error in initializing: %v", err)
 		os.Exit(-1)
 	}
 }
@@ -96,9 +96,15 @@ func NewFlyteCommand() *cobra.Command {
 
 	command.AddCommand(NewDeleteCommand(rootOpts))
 	command.AddCommand(NewGetCommand(rootOpts))
+	command.AddCommand(NewGetArchivedCommand(rootOpts))
+	command.AddCommand(NewReplayCommand(rootOpts))
 	command.AddCommand(NewVisualizeCommand(rootOpts))
 	command.AddCommand(NewCreateCommand(rootOpts))
 	command.AddCommand(NewCompileCommand(rootOpts))
+	command.AddCommand(NewCacheCommand(rootOpts))
+	command.AddCommand(NewEventsCommand(rootOpts))
+	command.AddCommand(NewForceCommand(rootOpts))
+	command.AddCommand(NewDataCommand(rootOpts))
 
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	loadingRules.DefaultClientConfig = &clientcmd.DefaultClientConfig
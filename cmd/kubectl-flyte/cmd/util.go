@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/config/viper"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -16,3 +22,22 @@ func requiredFlags(cmd *cobra.Command, flags ...string) error {
 
 	return nil
 }
+
+// Loads the propeller config file and creates the datastore it configures.
+func loadPropellerConfig(ctx context.Context, configFile string) (*storage.DataStore, error) {
+	configAccessor := viper.NewAccessor(config.Options{
+		StrictMode:  false,
+		SearchPaths: []string{configFile},
+	})
+
+	if err := configAccessor.UpdateConfig(ctx); err != nil {
+		return nil, errors.Wrapf(err, "Failed to load config.")
+	}
+
+	store, err := storage.NewDataStore(storage.GetConfig(), promutils.NewScope("kubectl_flyte"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create datastore.")
+	}
+
+	return store, nil
+}
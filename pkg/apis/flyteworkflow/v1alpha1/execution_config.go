@@ -24,9 +24,33 @@ type ExecutionConfig struct {
 	MaxParallelism uint32
 	// Defines execution behavior for processing nodes.
 	RecoveryExecution WorkflowExecutionIdentifier
+	// Defines a scratch volume that is attached to all pods of the execution.
+	ScratchVolume *ScratchVolume `json:",omitempty"`
+	// Runs cached tasks without looking up their cached outputs, and replaces the cached outputs with theirs.
+	OverwriteCache bool `json:",omitempty"`
 }
 
 type TaskPluginOverride struct {
 	PluginIDs             []string
 	MissingPluginBehavior admin.PluginOverride_MissingPluginBehavior
 }
+
+type ScratchVolumeType = string
+
+const (
+	// A node local emptyDir volume. It is limited by the ephemeral storage available on the node.
+	ScratchVolumeTypeEmptyDir ScratchVolumeType = "emptyDir"
+	// A generic ephemeral volume, backed by a PVC that is created for and deleted with each pod.
+	ScratchVolumeTypeEphemeral ScratchVolumeType = "ephemeral"
+	// A PVC that is created for each node attempt by propeller and deleted once the attempt is finalized or aborted.
+	ScratchVolumeTypePersistent ScratchVolumeType = "persistent"
+)
+
+// ScratchVolume is a volume for large intermediate data that tasks write to local disk instead of round tripping it
+// through the object store. Unless it is persistent, the volume lives as long as the pod it is attached to.
+type ScratchVolume struct {
+	Type ScratchVolumeType
+	// Size of the volume as a K8s quantity, e.g. 10Gi. It is the size limit of an emptyDir volume or the requested
+	// storage of an ephemeral or persistent volume.
+	Size string
+}
@@ -271,6 +271,16 @@ type MutableNodeStatus interface {
 	UpdatePhase(phase NodePhase, occurredAt metav1.Time, reason string, err *core.ExecutionError)
 	IncrementAttempts() uint32
 	IncrementSystemFailures() uint32
+	AddAttemptResourceUsage(usage AttemptResourceUsage)
+	SetResourceEscalation(escalation *ResourceEscalation)
+	SetInlinedOutputs(outputs *core.LiteralMap)
+	SetInputsRef(ref DataReference)
+	SetInputsHash(hash string)
+	SetForced(force *NodeForce)
+	SetOperatorSkipped(skip *NodeSkip)
+	SetFanOutBackpressure(backpressure *FanOutBackpressure)
+	SetPredicateSkipped()
+	SetReadyAt(readyAt metav1.Time)
 	SetCached()
 	ResetDirty()
 
@@ -288,6 +298,7 @@ type MutableNodeStatus interface {
 	ClearDynamicNodeStatus()
 	ClearLastAttemptStartedAt()
 	ClearSubNodeStatus()
+	ClearSubNodeStatusForRetry()
 }
 
 type ExecutionTimeInfo interface {
@@ -304,6 +315,7 @@ type ExecutableNodeStatus interface {
 	ExecutionTimeInfo
 	GetPhase() NodePhase
 	GetQueuedAt() *metav1.Time
+	GetReadyAt() *metav1.Time
 	GetLastAttemptStartedAt() *metav1.Time
 	GetParentNodeID() *NodeID
 	GetParentTaskID() *core.TaskExecutionIdentifier
@@ -315,8 +327,17 @@ type ExecutableNodeStatus interface {
 	GetSystemFailures() uint32
 	GetWorkflowNodeStatus() ExecutableWorkflowNodeStatus
 	GetTaskNodeStatus() ExecutableTaskNodeStatus
+	GetResourceUsage() []AttemptResourceUsage
+	GetResourceEscalation() *ResourceEscalation
+	GetInlinedOutputs() *core.LiteralMap
+	GetInputsRef() DataReference
+	GetInputsHash() string
+	GetForced() *NodeForce
+	GetOperatorSkipped() *NodeSkip
+	GetFanOutBackpressure() *FanOutBackpressure
 
 	IsCached() bool
+	IsPredicateSkipped() bool
 }
 
 type ExecutableSubWorkflowNodeStatus interface {
@@ -336,6 +357,8 @@ type ExecutableTaskNodeStatus interface {
 	GetPluginStateVersion() uint32
 	GetBarrierClockTick() uint32
 	GetLastPhaseUpdatedAt() time.Time
+	GetStalledReason() string
+	GetReservationOwner() string
 }
 
 type MutableTaskNodeStatus interface {
@@ -347,6 +370,8 @@ type MutableTaskNodeStatus interface {
 	SetPluginState([]byte)
 	SetPluginStateVersion(uint32)
 	SetBarrierClockTick(tick uint32)
+	SetStalledReason(reason string)
+	SetReservationOwner(owner string)
 }
 
 // Interface for a Child Workflow Node
@@ -377,6 +402,11 @@ type ExecutableNode interface {
 	GetActiveDeadline() *time.Duration
 	IsInterruptible() *bool
 	GetName() string
+	GetWhen() *core.BooleanExpression
+	GetSkippedUpstreamPolicy() SkippedUpstreamPolicy
+	GetLabels() map[string]string
+	GetAnnotations() map[string]string
+	GetStartDelay() *StartDelay
 }
 
 // Interface for the Workflow p. This is the mutable portion for a Workflow
@@ -395,6 +425,8 @@ type ExecutableWorkflowStatus interface {
 	IncFailedAttempts()
 	SetMessage(msg string)
 	ConstructNodeDataDir(ctx context.Context, name NodeID) (storage.DataReference, error)
+	GetEventCheckpoint() *WorkflowEventCheckpoint
+	SetEventCheckpoint(checkpoint *WorkflowEventCheckpoint)
 }
 
 type NodeGetter interface {
@@ -436,6 +468,7 @@ type Meta interface {
 	GetOwnerReference() metav1.OwnerReference
 	GetNamespace() string
 	GetCreationTimestamp() metav1.Time
+	GetScheduledAt() *metav1.Time
 	GetAnnotations() map[string]string
 	GetLabels() map[string]string
 	GetName() string
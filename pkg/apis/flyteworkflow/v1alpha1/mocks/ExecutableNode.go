@@ -5,11 +5,10 @@ package mocks
 import (
 	time "time"
 
+	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	mock "github.com/stretchr/testify/mock"
-
 	v1 "k8s.io/api/core/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 )
 
 // ExecutableNode is an autogenerated mock type for the ExecutableNode type
@@ -51,6 +50,40 @@ func (_m *ExecutableNode) GetActiveDeadline() *time.Duration {
 	return r0
 }
 
+type ExecutableNode_GetAnnotations struct {
+	*mock.Call
+}
+
+func (_m ExecutableNode_GetAnnotations) Return(_a0 map[string]string) *ExecutableNode_GetAnnotations {
+	return &ExecutableNode_GetAnnotations{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNode) OnGetAnnotations() *ExecutableNode_GetAnnotations {
+	c := _m.On("GetAnnotations")
+	return &ExecutableNode_GetAnnotations{Call: c}
+}
+
+func (_m *ExecutableNode) OnGetAnnotationsMatch(matchers ...interface{}) *ExecutableNode_GetAnnotations {
+	c := _m.On("GetAnnotations", matchers...)
+	return &ExecutableNode_GetAnnotations{Call: c}
+}
+
+// GetAnnotations provides a mock function with given fields:
+func (_m *ExecutableNode) GetAnnotations() map[string]string {
+	ret := _m.Called()
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func() map[string]string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableNode_GetBranchNode struct {
 	*mock.Call
 }
@@ -251,6 +284,40 @@ func (_m *ExecutableNode) GetKind() v1alpha1.NodeKind {
 	return r0
 }
 
+type ExecutableNode_GetLabels struct {
+	*mock.Call
+}
+
+func (_m ExecutableNode_GetLabels) Return(_a0 map[string]string) *ExecutableNode_GetLabels {
+	return &ExecutableNode_GetLabels{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNode) OnGetLabels() *ExecutableNode_GetLabels {
+	c := _m.On("GetLabels")
+	return &ExecutableNode_GetLabels{Call: c}
+}
+
+func (_m *ExecutableNode) OnGetLabelsMatch(matchers ...interface{}) *ExecutableNode_GetLabels {
+	c := _m.On("GetLabels", matchers...)
+	return &ExecutableNode_GetLabels{Call: c}
+}
+
+// GetLabels provides a mock function with given fields:
+func (_m *ExecutableNode) GetLabels() map[string]string {
+	ret := _m.Called()
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func() map[string]string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableNode_GetName struct {
 	*mock.Call
 }
@@ -385,6 +452,72 @@ func (_m *ExecutableNode) GetRetryStrategy() *v1alpha1.RetryStrategy {
 	return r0
 }
 
+type ExecutableNode_GetSkippedUpstreamPolicy struct {
+	*mock.Call
+}
+
+func (_m ExecutableNode_GetSkippedUpstreamPolicy) Return(_a0 string) *ExecutableNode_GetSkippedUpstreamPolicy {
+	return &ExecutableNode_GetSkippedUpstreamPolicy{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNode) OnGetSkippedUpstreamPolicy() *ExecutableNode_GetSkippedUpstreamPolicy {
+	c := _m.On("GetSkippedUpstreamPolicy")
+	return &ExecutableNode_GetSkippedUpstreamPolicy{Call: c}
+}
+
+func (_m *ExecutableNode) OnGetSkippedUpstreamPolicyMatch(matchers ...interface{}) *ExecutableNode_GetSkippedUpstreamPolicy {
+	c := _m.On("GetSkippedUpstreamPolicy", matchers...)
+	return &ExecutableNode_GetSkippedUpstreamPolicy{Call: c}
+}
+
+// GetSkippedUpstreamPolicy provides a mock function with given fields:
+func (_m *ExecutableNode) GetSkippedUpstreamPolicy() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+type ExecutableNode_GetStartDelay struct {
+	*mock.Call
+}
+
+func (_m ExecutableNode_GetStartDelay) Return(_a0 *v1alpha1.StartDelay) *ExecutableNode_GetStartDelay {
+	return &ExecutableNode_GetStartDelay{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNode) OnGetStartDelay() *ExecutableNode_GetStartDelay {
+	c := _m.On("GetStartDelay")
+	return &ExecutableNode_GetStartDelay{Call: c}
+}
+
+func (_m *ExecutableNode) OnGetStartDelayMatch(matchers ...interface{}) *ExecutableNode_GetStartDelay {
+	c := _m.On("GetStartDelay", matchers...)
+	return &ExecutableNode_GetStartDelay{Call: c}
+}
+
+// GetStartDelay provides a mock function with given fields:
+func (_m *ExecutableNode) GetStartDelay() *v1alpha1.StartDelay {
+	ret := _m.Called()
+
+	var r0 *v1alpha1.StartDelay
+	if rf, ok := ret.Get(0).(func() *v1alpha1.StartDelay); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.StartDelay)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableNode_GetTaskID struct {
 	*mock.Call
 }
@@ -419,6 +552,40 @@ func (_m *ExecutableNode) GetTaskID() *string {
 	return r0
 }
 
+type ExecutableNode_GetWhen struct {
+	*mock.Call
+}
+
+func (_m ExecutableNode_GetWhen) Return(_a0 *core.BooleanExpression) *ExecutableNode_GetWhen {
+	return &ExecutableNode_GetWhen{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNode) OnGetWhen() *ExecutableNode_GetWhen {
+	c := _m.On("GetWhen")
+	return &ExecutableNode_GetWhen{Call: c}
+}
+
+func (_m *ExecutableNode) OnGetWhenMatch(matchers ...interface{}) *ExecutableNode_GetWhen {
+	c := _m.On("GetWhen", matchers...)
+	return &ExecutableNode_GetWhen{Call: c}
+}
+
+// GetWhen provides a mock function with given fields:
+func (_m *ExecutableNode) GetWhen() *core.BooleanExpression {
+	ret := _m.Called()
+
+	var r0 *core.BooleanExpression
+	if rf, ok := ret.Get(0).(func() *core.BooleanExpression); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BooleanExpression)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableNode_GetWorkflowNode struct {
 	*mock.Call
 }
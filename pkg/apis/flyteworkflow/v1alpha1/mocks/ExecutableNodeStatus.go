@@ -6,13 +6,10 @@ import (
 	context "context"
 
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
-	mock "github.com/stretchr/testify/mock"
-
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	storage "github.com/flyteorg/flytestdlib/storage"
-
+	mock "github.com/stretchr/testify/mock"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 )
 
 // ExecutableNodeStatus is an autogenerated mock type for the ExecutableNodeStatus type
@@ -20,6 +17,11 @@ type ExecutableNodeStatus struct {
 	mock.Mock
 }
 
+// AddAttemptResourceUsage provides a mock function with given fields: usage
+func (_m *ExecutableNodeStatus) AddAttemptResourceUsage(usage v1alpha1.AttemptResourceUsage) {
+	_m.Called(usage)
+}
+
 // ClearDynamicNodeStatus provides a mock function with given fields:
 func (_m *ExecutableNodeStatus) ClearDynamicNodeStatus() {
 	_m.Called()
@@ -35,6 +37,11 @@ func (_m *ExecutableNodeStatus) ClearSubNodeStatus() {
 	_m.Called()
 }
 
+// ClearSubNodeStatusForRetry provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) ClearSubNodeStatusForRetry() {
+	_m.Called()
+}
+
 // ClearTaskStatus provides a mock function with given fields:
 func (_m *ExecutableNodeStatus) ClearTaskStatus() {
 	_m.Called()
@@ -211,6 +218,172 @@ func (_m *ExecutableNodeStatus) GetExecutionError() *core.ExecutionError {
 	return r0
 }
 
+type ExecutableNodeStatus_GetInlinedOutputs struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetInlinedOutputs) Return(_a0 *core.LiteralMap) *ExecutableNodeStatus_GetInlinedOutputs {
+	return &ExecutableNodeStatus_GetInlinedOutputs{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetInlinedOutputs() *ExecutableNodeStatus_GetInlinedOutputs {
+	c := _m.On("GetInlinedOutputs")
+	return &ExecutableNodeStatus_GetInlinedOutputs{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetInlinedOutputsMatch(matchers ...interface{}) *ExecutableNodeStatus_GetInlinedOutputs {
+	c := _m.On("GetInlinedOutputs", matchers...)
+	return &ExecutableNodeStatus_GetInlinedOutputs{Call: c}
+}
+
+// GetInlinedOutputs provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetInlinedOutputs() *core.LiteralMap {
+	ret := _m.Called()
+
+	var r0 *core.LiteralMap
+	if rf, ok := ret.Get(0).(func() *core.LiteralMap); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.LiteralMap)
+		}
+	}
+
+	return r0
+}
+
+type ExecutableNodeStatus_GetInputsRef struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetInputsRef) Return(_a0 storage.DataReference) *ExecutableNodeStatus_GetInputsRef {
+	return &ExecutableNodeStatus_GetInputsRef{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetInputsRef() *ExecutableNodeStatus_GetInputsRef {
+	c := _m.On("GetInputsRef")
+	return &ExecutableNodeStatus_GetInputsRef{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetInputsRefMatch(matchers ...interface{}) *ExecutableNodeStatus_GetInputsRef {
+	c := _m.On("GetInputsRef", matchers...)
+	return &ExecutableNodeStatus_GetInputsRef{Call: c}
+}
+
+// GetInputsRef provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetInputsRef() storage.DataReference {
+	ret := _m.Called()
+
+	var r0 storage.DataReference
+	if rf, ok := ret.Get(0).(func() storage.DataReference); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(storage.DataReference)
+	}
+
+	return r0
+}
+
+type ExecutableNodeStatus_GetFanOutBackpressure struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetFanOutBackpressure) Return(_a0 *v1alpha1.FanOutBackpressure) *ExecutableNodeStatus_GetFanOutBackpressure {
+	return &ExecutableNodeStatus_GetFanOutBackpressure{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetFanOutBackpressure() *ExecutableNodeStatus_GetFanOutBackpressure {
+	c := _m.On("GetFanOutBackpressure")
+	return &ExecutableNodeStatus_GetFanOutBackpressure{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetFanOutBackpressureMatch(matchers ...interface{}) *ExecutableNodeStatus_GetFanOutBackpressure {
+	c := _m.On("GetFanOutBackpressure", matchers...)
+	return &ExecutableNodeStatus_GetFanOutBackpressure{Call: c}
+}
+
+// GetFanOutBackpressure provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetFanOutBackpressure() *v1alpha1.FanOutBackpressure {
+	ret := _m.Called()
+
+	var r0 *v1alpha1.FanOutBackpressure
+	if rf, ok := ret.Get(0).(func() *v1alpha1.FanOutBackpressure); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.FanOutBackpressure)
+		}
+	}
+
+	return r0
+}
+
+type ExecutableNodeStatus_GetForced struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetForced) Return(_a0 *v1alpha1.NodeForce) *ExecutableNodeStatus_GetForced {
+	return &ExecutableNodeStatus_GetForced{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetForced() *ExecutableNodeStatus_GetForced {
+	c := _m.On("GetForced")
+	return &ExecutableNodeStatus_GetForced{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetForcedMatch(matchers ...interface{}) *ExecutableNodeStatus_GetForced {
+	c := _m.On("GetForced", matchers...)
+	return &ExecutableNodeStatus_GetForced{Call: c}
+}
+
+// GetForced provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetForced() *v1alpha1.NodeForce {
+	ret := _m.Called()
+
+	var r0 *v1alpha1.NodeForce
+	if rf, ok := ret.Get(0).(func() *v1alpha1.NodeForce); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.NodeForce)
+		}
+	}
+
+	return r0
+}
+
+type ExecutableNodeStatus_GetInputsHash struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetInputsHash) Return(_a0 string) *ExecutableNodeStatus_GetInputsHash {
+	return &ExecutableNodeStatus_GetInputsHash{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetInputsHash() *ExecutableNodeStatus_GetInputsHash {
+	c := _m.On("GetInputsHash")
+	return &ExecutableNodeStatus_GetInputsHash{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetInputsHashMatch(matchers ...interface{}) *ExecutableNodeStatus_GetInputsHash {
+	c := _m.On("GetInputsHash", matchers...)
+	return &ExecutableNodeStatus_GetInputsHash{Call: c}
+}
+
+// GetInputsHash provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetInputsHash() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 type ExecutableNodeStatus_GetLastAttemptStartedAt struct {
 	*mock.Call
 }
@@ -345,6 +518,40 @@ func (_m *ExecutableNodeStatus) GetNodeExecutionStatus(ctx context.Context, id s
 	return r0
 }
 
+type ExecutableNodeStatus_GetOperatorSkipped struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetOperatorSkipped) Return(_a0 *v1alpha1.NodeSkip) *ExecutableNodeStatus_GetOperatorSkipped {
+	return &ExecutableNodeStatus_GetOperatorSkipped{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetOperatorSkipped() *ExecutableNodeStatus_GetOperatorSkipped {
+	c := _m.On("GetOperatorSkipped")
+	return &ExecutableNodeStatus_GetOperatorSkipped{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetOperatorSkippedMatch(matchers ...interface{}) *ExecutableNodeStatus_GetOperatorSkipped {
+	c := _m.On("GetOperatorSkipped", matchers...)
+	return &ExecutableNodeStatus_GetOperatorSkipped{Call: c}
+}
+
+// GetOperatorSkipped provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetOperatorSkipped() *v1alpha1.NodeSkip {
+	ret := _m.Called()
+
+	var r0 *v1alpha1.NodeSkip
+	if rf, ok := ret.Get(0).(func() *v1alpha1.NodeSkip); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.NodeSkip)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableNodeStatus_GetOrCreateBranchStatus struct {
 	*mock.Call
 }
@@ -647,6 +854,108 @@ func (_m *ExecutableNodeStatus) GetQueuedAt() *v1.Time {
 	return r0
 }
 
+type ExecutableNodeStatus_GetReadyAt struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetReadyAt) Return(_a0 *v1.Time) *ExecutableNodeStatus_GetReadyAt {
+	return &ExecutableNodeStatus_GetReadyAt{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetReadyAt() *ExecutableNodeStatus_GetReadyAt {
+	c := _m.On("GetReadyAt")
+	return &ExecutableNodeStatus_GetReadyAt{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetReadyAtMatch(matchers ...interface{}) *ExecutableNodeStatus_GetReadyAt {
+	c := _m.On("GetReadyAt", matchers...)
+	return &ExecutableNodeStatus_GetReadyAt{Call: c}
+}
+
+// GetReadyAt provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetReadyAt() *v1.Time {
+	ret := _m.Called()
+
+	var r0 *v1.Time
+	if rf, ok := ret.Get(0).(func() *v1.Time); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Time)
+		}
+	}
+
+	return r0
+}
+
+type ExecutableNodeStatus_GetResourceEscalation struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetResourceEscalation) Return(_a0 *v1alpha1.ResourceEscalation) *ExecutableNodeStatus_GetResourceEscalation {
+	return &ExecutableNodeStatus_GetResourceEscalation{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetResourceEscalation() *ExecutableNodeStatus_GetResourceEscalation {
+	c := _m.On("GetResourceEscalation")
+	return &ExecutableNodeStatus_GetResourceEscalation{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetResourceEscalationMatch(matchers ...interface{}) *ExecutableNodeStatus_GetResourceEscalation {
+	c := _m.On("GetResourceEscalation", matchers...)
+	return &ExecutableNodeStatus_GetResourceEscalation{Call: c}
+}
+
+// GetResourceEscalation provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetResourceEscalation() *v1alpha1.ResourceEscalation {
+	ret := _m.Called()
+
+	var r0 *v1alpha1.ResourceEscalation
+	if rf, ok := ret.Get(0).(func() *v1alpha1.ResourceEscalation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.ResourceEscalation)
+		}
+	}
+
+	return r0
+}
+
+type ExecutableNodeStatus_GetResourceUsage struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_GetResourceUsage) Return(_a0 []v1alpha1.AttemptResourceUsage) *ExecutableNodeStatus_GetResourceUsage {
+	return &ExecutableNodeStatus_GetResourceUsage{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnGetResourceUsage() *ExecutableNodeStatus_GetResourceUsage {
+	c := _m.On("GetResourceUsage")
+	return &ExecutableNodeStatus_GetResourceUsage{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnGetResourceUsageMatch(matchers ...interface{}) *ExecutableNodeStatus_GetResourceUsage {
+	c := _m.On("GetResourceUsage", matchers...)
+	return &ExecutableNodeStatus_GetResourceUsage{Call: c}
+}
+
+// GetResourceUsage provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) GetResourceUsage() []v1alpha1.AttemptResourceUsage {
+	ret := _m.Called()
+
+	var r0 []v1alpha1.AttemptResourceUsage
+	if rf, ok := ret.Get(0).(func() []v1alpha1.AttemptResourceUsage); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1alpha1.AttemptResourceUsage)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableNodeStatus_GetStartedAt struct {
 	*mock.Call
 }
@@ -1011,6 +1320,38 @@ func (_m *ExecutableNodeStatus) IsDirty() bool {
 	return r0
 }
 
+type ExecutableNodeStatus_IsPredicateSkipped struct {
+	*mock.Call
+}
+
+func (_m ExecutableNodeStatus_IsPredicateSkipped) Return(_a0 bool) *ExecutableNodeStatus_IsPredicateSkipped {
+	return &ExecutableNodeStatus_IsPredicateSkipped{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableNodeStatus) OnIsPredicateSkipped() *ExecutableNodeStatus_IsPredicateSkipped {
+	c := _m.On("IsPredicateSkipped")
+	return &ExecutableNodeStatus_IsPredicateSkipped{Call: c}
+}
+
+func (_m *ExecutableNodeStatus) OnIsPredicateSkippedMatch(matchers ...interface{}) *ExecutableNodeStatus_IsPredicateSkipped {
+	c := _m.On("IsPredicateSkipped", matchers...)
+	return &ExecutableNodeStatus_IsPredicateSkipped{Call: c}
+}
+
+// IsPredicateSkipped provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) IsPredicateSkipped() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // ResetDirty provides a mock function with given fields:
 func (_m *ExecutableNodeStatus) ResetDirty() {
 	_m.Called()
@@ -1026,6 +1367,36 @@ func (_m *ExecutableNodeStatus) SetDataDir(_a0 storage.DataReference) {
 	_m.Called(_a0)
 }
 
+// SetInlinedOutputs provides a mock function with given fields: outputs
+func (_m *ExecutableNodeStatus) SetInlinedOutputs(outputs *core.LiteralMap) {
+	_m.Called(outputs)
+}
+
+// SetFanOutBackpressure provides a mock function with given fields: backpressure
+func (_m *ExecutableNodeStatus) SetFanOutBackpressure(backpressure *v1alpha1.FanOutBackpressure) {
+	_m.Called(backpressure)
+}
+
+// SetForced provides a mock function with given fields: force
+func (_m *ExecutableNodeStatus) SetForced(force *v1alpha1.NodeForce) {
+	_m.Called(force)
+}
+
+// SetInputsHash provides a mock function with given fields: hash
+func (_m *ExecutableNodeStatus) SetInputsHash(hash string) {
+	_m.Called(hash)
+}
+
+// SetInputsRef provides a mock function with given fields: ref
+func (_m *ExecutableNodeStatus) SetInputsRef(ref storage.DataReference) {
+	_m.Called(ref)
+}
+
+// SetOperatorSkipped provides a mock function with given fields: skip
+func (_m *ExecutableNodeStatus) SetOperatorSkipped(skip *v1alpha1.NodeSkip) {
+	_m.Called(skip)
+}
+
 // SetOutputDir provides a mock function with given fields: d
 func (_m *ExecutableNodeStatus) SetOutputDir(d storage.DataReference) {
 	_m.Called(d)
@@ -1041,6 +1412,21 @@ func (_m *ExecutableNodeStatus) SetParentTaskID(t *core.TaskExecutionIdentifier)
 	_m.Called(t)
 }
 
+// SetPredicateSkipped provides a mock function with given fields:
+func (_m *ExecutableNodeStatus) SetPredicateSkipped() {
+	_m.Called()
+}
+
+// SetReadyAt provides a mock function with given fields: readyAt
+func (_m *ExecutableNodeStatus) SetReadyAt(readyAt v1.Time) {
+	_m.Called(readyAt)
+}
+
+// SetResourceEscalation provides a mock function with given fields: escalation
+func (_m *ExecutableNodeStatus) SetResourceEscalation(escalation *v1alpha1.ResourceEscalation) {
+	_m.Called(escalation)
+}
+
 // UpdatePhase provides a mock function with given fields: phase, occurredAt, reason, err
 func (_m *ExecutableNodeStatus) UpdatePhase(phase v1alpha1.NodePhase, occurredAt v1.Time, reason string, err *core.ExecutionError) {
 	_m.Called(phase, occurredAt, reason, err)
@@ -206,3 +206,67 @@ func (_m *ExecutableTaskNodeStatus) GetPluginStateVersion() uint32 {
 
 	return r0
 }
+
+type ExecutableTaskNodeStatus_GetReservationOwner struct {
+	*mock.Call
+}
+
+func (_m ExecutableTaskNodeStatus_GetReservationOwner) Return(_a0 string) *ExecutableTaskNodeStatus_GetReservationOwner {
+	return &ExecutableTaskNodeStatus_GetReservationOwner{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableTaskNodeStatus) OnGetReservationOwner() *ExecutableTaskNodeStatus_GetReservationOwner {
+	c := _m.On("GetReservationOwner")
+	return &ExecutableTaskNodeStatus_GetReservationOwner{Call: c}
+}
+
+func (_m *ExecutableTaskNodeStatus) OnGetReservationOwnerMatch(matchers ...interface{}) *ExecutableTaskNodeStatus_GetReservationOwner {
+	c := _m.On("GetReservationOwner", matchers...)
+	return &ExecutableTaskNodeStatus_GetReservationOwner{Call: c}
+}
+
+// GetReservationOwner provides a mock function with given fields:
+func (_m *ExecutableTaskNodeStatus) GetReservationOwner() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+type ExecutableTaskNodeStatus_GetStalledReason struct {
+	*mock.Call
+}
+
+func (_m ExecutableTaskNodeStatus_GetStalledReason) Return(_a0 string) *ExecutableTaskNodeStatus_GetStalledReason {
+	return &ExecutableTaskNodeStatus_GetStalledReason{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableTaskNodeStatus) OnGetStalledReason() *ExecutableTaskNodeStatus_GetStalledReason {
+	c := _m.On("GetStalledReason")
+	return &ExecutableTaskNodeStatus_GetStalledReason{Call: c}
+}
+
+func (_m *ExecutableTaskNodeStatus) OnGetStalledReasonMatch(matchers ...interface{}) *ExecutableTaskNodeStatus_GetStalledReason {
+	c := _m.On("GetStalledReason", matchers...)
+	return &ExecutableTaskNodeStatus_GetStalledReason{Call: c}
+}
+
+// GetStalledReason provides a mock function with given fields:
+func (_m *ExecutableTaskNodeStatus) GetStalledReason() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
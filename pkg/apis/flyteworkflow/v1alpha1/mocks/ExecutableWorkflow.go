@@ -6,13 +6,10 @@ import (
 	context "context"
 
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	mock "github.com/stretchr/testify/mock"
-
-	types "k8s.io/apimachinery/pkg/types"
-
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // ExecutableWorkflow is an autogenerated mock type for the ExecutableWorkflow type
@@ -794,6 +791,40 @@ func (_m *ExecutableWorkflow) GetRawOutputDataConfig() v1alpha1.RawOutputDataCon
 	return r0
 }
 
+type ExecutableWorkflow_GetScheduledAt struct {
+	*mock.Call
+}
+
+func (_m ExecutableWorkflow_GetScheduledAt) Return(_a0 *v1.Time) *ExecutableWorkflow_GetScheduledAt {
+	return &ExecutableWorkflow_GetScheduledAt{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableWorkflow) OnGetScheduledAt() *ExecutableWorkflow_GetScheduledAt {
+	c := _m.On("GetScheduledAt")
+	return &ExecutableWorkflow_GetScheduledAt{Call: c}
+}
+
+func (_m *ExecutableWorkflow) OnGetScheduledAtMatch(matchers ...interface{}) *ExecutableWorkflow_GetScheduledAt {
+	c := _m.On("GetScheduledAt", matchers...)
+	return &ExecutableWorkflow_GetScheduledAt{Call: c}
+}
+
+// GetScheduledAt provides a mock function with given fields:
+func (_m *ExecutableWorkflow) GetScheduledAt() *v1.Time {
+	ret := _m.Called()
+
+	var r0 *v1.Time
+	if rf, ok := ret.Get(0).(func() *v1.Time); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Time)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableWorkflow_GetSecurityContext struct {
 	*mock.Call
 }
@@ -91,6 +91,40 @@ func (_m *ExecutableWorkflowStatus) GetDataDir() storage.DataReference {
 	return r0
 }
 
+type ExecutableWorkflowStatus_GetEventCheckpoint struct {
+	*mock.Call
+}
+
+func (_m ExecutableWorkflowStatus_GetEventCheckpoint) Return(_a0 *v1alpha1.WorkflowEventCheckpoint) *ExecutableWorkflowStatus_GetEventCheckpoint {
+	return &ExecutableWorkflowStatus_GetEventCheckpoint{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutableWorkflowStatus) OnGetEventCheckpoint() *ExecutableWorkflowStatus_GetEventCheckpoint {
+	c := _m.On("GetEventCheckpoint")
+	return &ExecutableWorkflowStatus_GetEventCheckpoint{Call: c}
+}
+
+func (_m *ExecutableWorkflowStatus) OnGetEventCheckpointMatch(matchers ...interface{}) *ExecutableWorkflowStatus_GetEventCheckpoint {
+	c := _m.On("GetEventCheckpoint", matchers...)
+	return &ExecutableWorkflowStatus_GetEventCheckpoint{Call: c}
+}
+
+// GetEventCheckpoint provides a mock function with given fields:
+func (_m *ExecutableWorkflowStatus) GetEventCheckpoint() *v1alpha1.WorkflowEventCheckpoint {
+	ret := _m.Called()
+
+	var r0 *v1alpha1.WorkflowEventCheckpoint
+	if rf, ok := ret.Get(0).(func() *v1alpha1.WorkflowEventCheckpoint); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.WorkflowEventCheckpoint)
+		}
+	}
+
+	return r0
+}
+
 type ExecutableWorkflowStatus_GetExecutionError struct {
 	*mock.Call
 }
@@ -399,6 +433,11 @@ func (_m *ExecutableWorkflowStatus) SetDataDir(_a0 storage.DataReference) {
 	_m.Called(_a0)
 }
 
+// SetEventCheckpoint provides a mock function with given fields: checkpoint
+func (_m *ExecutableWorkflowStatus) SetEventCheckpoint(checkpoint *v1alpha1.WorkflowEventCheckpoint) {
+	_m.Called(checkpoint)
+}
+
 // SetMessage provides a mock function with given fields: msg
 func (_m *ExecutableWorkflowStatus) SetMessage(msg string) {
 	_m.Called(msg)
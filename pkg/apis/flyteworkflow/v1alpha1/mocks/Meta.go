@@ -4,13 +4,10 @@ package mocks
 
 import (
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	mock "github.com/stretchr/testify/mock"
-
-	types "k8s.io/apimachinery/pkg/types"
-
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // Meta is an autogenerated mock type for the Meta type
@@ -342,6 +339,40 @@ func (_m *Meta) GetRawOutputDataConfig() v1alpha1.RawOutputDataConfig {
 	return r0
 }
 
+type Meta_GetScheduledAt struct {
+	*mock.Call
+}
+
+func (_m Meta_GetScheduledAt) Return(_a0 *v1.Time) *Meta_GetScheduledAt {
+	return &Meta_GetScheduledAt{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *Meta) OnGetScheduledAt() *Meta_GetScheduledAt {
+	c := _m.On("GetScheduledAt")
+	return &Meta_GetScheduledAt{Call: c}
+}
+
+func (_m *Meta) OnGetScheduledAtMatch(matchers ...interface{}) *Meta_GetScheduledAt {
+	c := _m.On("GetScheduledAt", matchers...)
+	return &Meta_GetScheduledAt{Call: c}
+}
+
+// GetScheduledAt provides a mock function with given fields:
+func (_m *Meta) GetScheduledAt() *v1.Time {
+	ret := _m.Called()
+
+	var r0 *v1.Time
+	if rf, ok := ret.Get(0).(func() *v1.Time); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Time)
+		}
+	}
+
+	return r0
+}
+
 type Meta_GetSecurityContext struct {
 	*mock.Call
 }
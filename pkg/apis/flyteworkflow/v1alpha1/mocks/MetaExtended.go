@@ -4,13 +4,10 @@ package mocks
 
 import (
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	mock "github.com/stretchr/testify/mock"
-
-	types "k8s.io/apimachinery/pkg/types"
-
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // MetaExtended is an autogenerated mock type for the MetaExtended type
@@ -410,6 +407,40 @@ func (_m *MetaExtended) GetRawOutputDataConfig() v1alpha1.RawOutputDataConfig {
 	return r0
 }
 
+type MetaExtended_GetScheduledAt struct {
+	*mock.Call
+}
+
+func (_m MetaExtended_GetScheduledAt) Return(_a0 *v1.Time) *MetaExtended_GetScheduledAt {
+	return &MetaExtended_GetScheduledAt{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *MetaExtended) OnGetScheduledAt() *MetaExtended_GetScheduledAt {
+	c := _m.On("GetScheduledAt")
+	return &MetaExtended_GetScheduledAt{Call: c}
+}
+
+func (_m *MetaExtended) OnGetScheduledAtMatch(matchers ...interface{}) *MetaExtended_GetScheduledAt {
+	c := _m.On("GetScheduledAt", matchers...)
+	return &MetaExtended_GetScheduledAt{Call: c}
+}
+
+// GetScheduledAt provides a mock function with given fields:
+func (_m *MetaExtended) GetScheduledAt() *v1.Time {
+	ret := _m.Called()
+
+	var r0 *v1.Time
+	if rf, ok := ret.Get(0).(func() *v1.Time); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Time)
+		}
+	}
+
+	return r0
+}
+
 type MetaExtended_GetSecurityContext struct {
 	*mock.Call
 }
@@ -4,13 +4,10 @@ package mocks
 
 import (
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
-	mock "github.com/stretchr/testify/mock"
-
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	storage "github.com/flyteorg/flytestdlib/storage"
-
+	mock "github.com/stretchr/testify/mock"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 )
 
 // MutableNodeStatus is an autogenerated mock type for the MutableNodeStatus type
@@ -18,6 +15,11 @@ type MutableNodeStatus struct {
 	mock.Mock
 }
 
+// AddAttemptResourceUsage provides a mock function with given fields: usage
+func (_m *MutableNodeStatus) AddAttemptResourceUsage(usage v1alpha1.AttemptResourceUsage) {
+	_m.Called(usage)
+}
+
 // ClearDynamicNodeStatus provides a mock function with given fields:
 func (_m *MutableNodeStatus) ClearDynamicNodeStatus() {
 	_m.Called()
@@ -33,6 +35,11 @@ func (_m *MutableNodeStatus) ClearSubNodeStatus() {
 	_m.Called()
 }
 
+// ClearSubNodeStatusForRetry provides a mock function with given fields:
+func (_m *MutableNodeStatus) ClearSubNodeStatusForRetry() {
+	_m.Called()
+}
+
 // ClearTaskStatus provides a mock function with given fields:
 func (_m *MutableNodeStatus) ClearTaskStatus() {
 	_m.Called()
@@ -426,6 +433,36 @@ func (_m *MutableNodeStatus) SetDataDir(_a0 storage.DataReference) {
 	_m.Called(_a0)
 }
 
+// SetInlinedOutputs provides a mock function with given fields: outputs
+func (_m *MutableNodeStatus) SetInlinedOutputs(outputs *core.LiteralMap) {
+	_m.Called(outputs)
+}
+
+// SetFanOutBackpressure provides a mock function with given fields: backpressure
+func (_m *MutableNodeStatus) SetFanOutBackpressure(backpressure *v1alpha1.FanOutBackpressure) {
+	_m.Called(backpressure)
+}
+
+// SetForced provides a mock function with given fields: force
+func (_m *MutableNodeStatus) SetForced(force *v1alpha1.NodeForce) {
+	_m.Called(force)
+}
+
+// SetInputsHash provides a mock function with given fields: hash
+func (_m *MutableNodeStatus) SetInputsHash(hash string) {
+	_m.Called(hash)
+}
+
+// SetInputsRef provides a mock function with given fields: ref
+func (_m *MutableNodeStatus) SetInputsRef(ref storage.DataReference) {
+	_m.Called(ref)
+}
+
+// SetOperatorSkipped provides a mock function with given fields: skip
+func (_m *MutableNodeStatus) SetOperatorSkipped(skip *v1alpha1.NodeSkip) {
+	_m.Called(skip)
+}
+
 // SetOutputDir provides a mock function with given fields: d
 func (_m *MutableNodeStatus) SetOutputDir(d storage.DataReference) {
 	_m.Called(d)
@@ -441,6 +478,21 @@ func (_m *MutableNodeStatus) SetParentTaskID(t *core.TaskExecutionIdentifier) {
 	_m.Called(t)
 }
 
+// SetPredicateSkipped provides a mock function with given fields:
+func (_m *MutableNodeStatus) SetPredicateSkipped() {
+	_m.Called()
+}
+
+// SetReadyAt provides a mock function with given fields: readyAt
+func (_m *MutableNodeStatus) SetReadyAt(readyAt v1.Time) {
+	_m.Called(readyAt)
+}
+
+// SetResourceEscalation provides a mock function with given fields: escalation
+func (_m *MutableNodeStatus) SetResourceEscalation(escalation *v1alpha1.ResourceEscalation) {
+	_m.Called(escalation)
+}
+
 // UpdatePhase provides a mock function with given fields: phase, occurredAt, reason, err
 func (_m *MutableNodeStatus) UpdatePhase(phase v1alpha1.NodePhase, occurredAt v1.Time, reason string, err *core.ExecutionError) {
 	_m.Called(phase, occurredAt, reason, err)
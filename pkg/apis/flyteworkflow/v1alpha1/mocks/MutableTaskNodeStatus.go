@@ -207,6 +207,70 @@ func (_m *MutableTaskNodeStatus) GetPluginStateVersion() uint32 {
 	return r0
 }
 
+type MutableTaskNodeStatus_GetReservationOwner struct {
+	*mock.Call
+}
+
+func (_m MutableTaskNodeStatus_GetReservationOwner) Return(_a0 string) *MutableTaskNodeStatus_GetReservationOwner {
+	return &MutableTaskNodeStatus_GetReservationOwner{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *MutableTaskNodeStatus) OnGetReservationOwner() *MutableTaskNodeStatus_GetReservationOwner {
+	c := _m.On("GetReservationOwner")
+	return &MutableTaskNodeStatus_GetReservationOwner{Call: c}
+}
+
+func (_m *MutableTaskNodeStatus) OnGetReservationOwnerMatch(matchers ...interface{}) *MutableTaskNodeStatus_GetReservationOwner {
+	c := _m.On("GetReservationOwner", matchers...)
+	return &MutableTaskNodeStatus_GetReservationOwner{Call: c}
+}
+
+// GetReservationOwner provides a mock function with given fields:
+func (_m *MutableTaskNodeStatus) GetReservationOwner() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+type MutableTaskNodeStatus_GetStalledReason struct {
+	*mock.Call
+}
+
+func (_m MutableTaskNodeStatus_GetStalledReason) Return(_a0 string) *MutableTaskNodeStatus_GetStalledReason {
+	return &MutableTaskNodeStatus_GetStalledReason{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *MutableTaskNodeStatus) OnGetStalledReason() *MutableTaskNodeStatus_GetStalledReason {
+	c := _m.On("GetStalledReason")
+	return &MutableTaskNodeStatus_GetStalledReason{Call: c}
+}
+
+func (_m *MutableTaskNodeStatus) OnGetStalledReasonMatch(matchers ...interface{}) *MutableTaskNodeStatus_GetStalledReason {
+	c := _m.On("GetStalledReason", matchers...)
+	return &MutableTaskNodeStatus_GetStalledReason{Call: c}
+}
+
+// GetStalledReason provides a mock function with given fields:
+func (_m *MutableTaskNodeStatus) GetStalledReason() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 type MutableTaskNodeStatus_IsDirty struct {
 	*mock.Call
 }
@@ -268,3 +332,13 @@ func (_m *MutableTaskNodeStatus) SetPluginState(_a0 []byte) {
 func (_m *MutableTaskNodeStatus) SetPluginStateVersion(_a0 uint32) {
 	_m.Called(_a0)
 }
+
+// SetReservationOwner provides a mock function with given fields: owner
+func (_m *MutableTaskNodeStatus) SetReservationOwner(owner string) {
+	_m.Called(owner)
+}
+
+// SetStalledReason provides a mock function with given fields: reason
+func (_m *MutableTaskNodeStatus) SetStalledReason(reason string) {
+	_m.Called(reason)
+}
@@ -0,0 +1,68 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ForceNodesAnnotation is the annotation of workflows through which operators force nodes that are wedged on
+// irrecoverable external states to fail or succeed. It holds a JSON object from the IDs of the nodes, as reported in
+// their node execution events, to their NodeForceRequest.
+const ForceNodesAnnotation = "flyte.org/force-nodes"
+
+type ForcedNodePhase = string
+
+const (
+	ForcedNodePhaseSucceeded ForcedNodePhase = "Succeeded"
+	ForcedNodePhaseFailed    ForcedNodePhase = "Failed"
+)
+
+// NodeForceRequest asks for a running node to be aborted and moved to the given phase.
+type NodeForceRequest struct {
+	Phase ForcedNodePhase `json:"phase"`
+	// Outputs file the outputs of nodes forced to succeed are copied from, if the node has outputs.
+	OutputsURI  DataReference `json:"outputsUri,omitempty"`
+	Reason      string        `json:"reason"`
+	RequestedBy string        `json:"requestedBy"`
+}
+
+func (r NodeForceRequest) Validate() error {
+	switch r.Phase {
+	case ForcedNodePhaseSucceeded:
+	case ForcedNodePhaseFailed:
+		if len(r.OutputsURI) > 0 {
+			return fmt.Errorf("outputs can only be provided for nodes forced to succeed")
+		}
+	default:
+		return fmt.Errorf("nodes can only be forced to [%v] or [%v], found [%v]", ForcedNodePhaseSucceeded,
+			ForcedNodePhaseFailed, r.Phase)
+	}
+
+	if len(r.Reason) == 0 || len(r.RequestedBy) == 0 {
+		return fmt.Errorf("forcing a node requires a reason and the operator requesting it")
+	}
+
+	return nil
+}
+
+// GetNodeForceRequests returns the requests to force nodes held by the annotations of a workflow, by node ID.
+func GetNodeForceRequests(annotations map[string]string) (map[string]NodeForceRequest, error) {
+	raw, found := annotations[ForceNodesAnnotation]
+	if !found {
+		return nil, nil
+	}
+
+	requests := make(map[string]NodeForceRequest)
+	if err := json.Unmarshal([]byte(raw), &requests); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", ForceNodesAnnotation, err)
+	}
+
+	return requests, nil
+}
+
+// NodeForce records that a node was forced to a phase by an operator.
+type NodeForce struct {
+	NodeForceRequest `json:",inline"`
+	// Set once an event documenting the force has been emitted
+	Reported bool `json:"reported,omitempty"`
+}
@@ -0,0 +1,33 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeForceRequest_Validate(t *testing.T) {
+	assert.NoError(t, NodeForceRequest{Phase: ForcedNodePhaseFailed, Reason: "stuck", RequestedBy: "op"}.Validate())
+	assert.NoError(t, NodeForceRequest{Phase: ForcedNodePhaseSucceeded, OutputsURI: "s3://bucket/outputs.pb",
+		Reason: "stuck", RequestedBy: "op"}.Validate())
+	assert.Error(t, NodeForceRequest{Phase: ForcedNodePhaseFailed, OutputsURI: "s3://bucket/outputs.pb",
+		Reason: "stuck", RequestedBy: "op"}.Validate())
+	assert.Error(t, NodeForceRequest{Phase: "Skipped", Reason: "stuck", RequestedBy: "op"}.Validate())
+	assert.Error(t, NodeForceRequest{Phase: ForcedNodePhaseFailed, RequestedBy: "op"}.Validate())
+	assert.Error(t, NodeForceRequest{Phase: ForcedNodePhaseFailed, Reason: "stuck"}.Validate())
+}
+
+func TestGetNodeForceRequests(t *testing.T) {
+	requests, err := GetNodeForceRequests(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+
+	requests, err = GetNodeForceRequests(map[string]string{
+		ForceNodesAnnotation: `{"n1": {"phase": "Failed", "reason": "stuck", "requestedBy": "op"}}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]NodeForceRequest{"n1": {Phase: ForcedNodePhaseFailed, Reason: "stuck", RequestedBy: "op"}}, requests)
+
+	_, err = GetNodeForceRequests(map[string]string{ForceNodesAnnotation: "n1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SkipNodesAnnotation is the annotation of workflows through which operators skip nodes that have not started yet, e.g.
+// to bypass a broken optional step. It holds a JSON object from the IDs of the nodes, as reported in their node
+// execution events, to their NodeSkipRequest. Consumers of skipped nodes see their outputs as absent.
+const SkipNodesAnnotation = "flyte.org/skip-nodes"
+
+// NodeSkipRequest asks for a node that has not started yet to be skipped.
+type NodeSkipRequest struct {
+	Reason      string `json:"reason"`
+	RequestedBy string `json:"requestedBy"`
+}
+
+func (r NodeSkipRequest) Validate() error {
+	if len(r.Reason) == 0 || len(r.RequestedBy) == 0 {
+		return fmt.Errorf("skipping a node requires a reason and the operator requesting it")
+	}
+
+	return nil
+}
+
+// GetNodeSkipRequests returns the requests to skip nodes held by the annotations of a workflow, by node ID.
+func GetNodeSkipRequests(annotations map[string]string) (map[string]NodeSkipRequest, error) {
+	raw, found := annotations[SkipNodesAnnotation]
+	if !found {
+		return nil, nil
+	}
+
+	requests := make(map[string]NodeSkipRequest)
+	if err := json.Unmarshal([]byte(raw), &requests); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SkipNodesAnnotation, err)
+	}
+
+	return requests, nil
+}
+
+// NodeSkip records that a node was skipped by an operator.
+type NodeSkip struct {
+	NodeSkipRequest `json:",inline"`
+	// Set once an event documenting the skip has been emitted
+	Reported bool `json:"reported,omitempty"`
+}
@@ -0,0 +1,24 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNodeSkipRequests(t *testing.T) {
+	requests, err := GetNodeSkipRequests(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+
+	requests, err = GetNodeSkipRequests(map[string]string{
+		SkipNodesAnnotation: `{"n1": {"reason": "broken", "requestedBy": "op"}}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]NodeSkipRequest{"n1": {Reason: "broken", RequestedBy: "op"}}, requests)
+	assert.NoError(t, requests["n1"].Validate())
+	assert.Error(t, NodeSkipRequest{Reason: "broken"}.Validate())
+
+	_, err = GetNodeSkipRequests(map[string]string{SkipNodesAnnotation: "n1"})
+	assert.Error(t, err)
+}
@@ -7,14 +7,16 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/flyteorg/flytestdlib/storage"
 
-	"github.com/flyteorg/flytestdlib/logger"
-
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	typesv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 type MutableStruct struct {
@@ -184,6 +186,55 @@ func (in *WorkflowNodeStatus) SetWorkflowNodePhase(phase WorkflowNodePhase) {
 	}
 }
 
+// ResourceUsage captures the resources requested by a node multiplied by the time they were held for.
+type ResourceUsage struct {
+	CPUMilliCoreSeconds int64 `json:"cpuMilliSec,omitempty"`
+	MemoryMiBSeconds    int64 `json:"memMiBSec,omitempty"`
+	GPUSeconds          int64 `json:"gpuSec,omitempty"`
+}
+
+func (in *ResourceUsage) Add(other ResourceUsage) {
+	in.CPUMilliCoreSeconds += other.CPUMilliCoreSeconds
+	in.MemoryMiBSeconds += other.MemoryMiBSeconds
+	in.GPUSeconds += other.GPUSeconds
+}
+
+func (in ResourceUsage) IsZero() bool {
+	return in.CPUMilliCoreSeconds == 0 && in.MemoryMiBSeconds == 0 && in.GPUSeconds == 0
+}
+
+// AttemptResourceUsage is the resource usage of a single attempt of a node.
+type AttemptResourceUsage struct {
+	ResourceUsage `json:",inline"`
+	Attempt       uint32 `json:"attempt"`
+	// Runtime of the attempt in seconds
+	DurationSeconds int64 `json:"durationSec"`
+}
+
+// ResourceEscalation records resource requirements that were raised for all subsequent attempts of a node, e.g. after an
+// attempt was OOMKilled.
+type ResourceEscalation struct {
+	Resources typesv1.ResourceRequirements `json:"resources"`
+	// The attempt whose failure caused the latest escalation
+	Attempt uint32 `json:"attempt"`
+	Reason  string `json:"reason,omitempty"`
+	// Set once an event documenting the escalation has been emitted
+	Reported bool `json:"reported,omitempty"`
+}
+
+// FanOutBackpressure records why a dynamic node launches its sub-nodes in smaller batches than they became ready.
+type FanOutBackpressure struct {
+	Reason string `json:"reason,omitempty"`
+	// Set once an event documenting the backpressure has been emitted
+	Reported bool `json:"reported,omitempty"`
+}
+
+// NodeInputs records where the inputs of a node were stored and the hash they were stored with.
+type NodeInputs struct {
+	Ref  DataReference `json:"ref"`
+	Hash string        `json:"hash"`
+}
+
 type NodeStatus struct {
 	MutableStruct
 	Phase                NodePhase     `json:"phase"`
@@ -214,6 +265,44 @@ type NodeStatus struct {
 	// In case of Failing/Failed Phase, an execution error can be optionally associated with the Node
 	Error *ExecutionError `json:"error,omitempty"`
 
+	// Resources requested multiplied by runtime, for every completed attempt of this node.
+	ResourceUsage []AttemptResourceUsage `json:"resourceUsage,omitempty"`
+
+	// Resources to use for subsequent attempts instead of the ones specified on the node.
+	ResourceEscalation *ResourceEscalation `json:"resourceEscalation,omitempty"`
+
+	// Outputs of the node, if they are small enough to be stored in the status. Downstream nodes resolve them from here
+	// instead of reading them from the output dir.
+	InlinedOutputs *InlinedOutputs `json:"inlinedOutputs,omitempty"`
+
+	// Outputs file of the upstream node the inputs of this node were passed through from, or the inputs file of the
+	// previous attempt of this sub-node that it reuses, if the inputs were not written to the inputs file in its data dir.
+	InputsRef DataReference `json:"inputsRef,omitempty"`
+
+	// Hash of the inputs last written to the inputs file in the data dir, so that they are not written again if the node
+	// is started again with the same inputs.
+	InputsHash string `json:"inputsHash,omitempty"`
+
+	// Inputs of the sub-nodes of the previous attempt of this node, by sub-node id. The sub-nodes of a retry start from
+	// these, so that they read the inputs of the previous attempt instead of writing them again if they are unchanged.
+	PreviousSubNodeInputs map[NodeID]NodeInputs `json:"previousSubNodeInputs,omitempty"`
+
+	// Set if an operator forced the node to fail or succeed.
+	Forced *NodeForce `json:"forced,omitempty"`
+
+	// Set if an operator skipped the node before it started.
+	OperatorSkipped *NodeSkip `json:"operatorSkipped,omitempty"`
+
+	// Set while a dynamic node staggers the launch of its sub-nodes because the namespace it runs in lacks headroom.
+	FanOutBackpressure *FanOutBackpressure `json:"fanOutBackpressure,omitempty"`
+
+	// Set if the node was skipped because its when predicate evaluated to false
+	PredicateSkipped bool `json:"predicateSkipped,omitempty"`
+
+	// Time at which the upstream nodes of a node with a start delay were found complete. Persisted, so that the delay
+	// survives restarts of propeller.
+	ReadyAt *metav1.Time `json:"readyAt,omitempty"`
+
 	// Not Persisted
 	DataReferenceConstructor storage.ReferenceConstructor `json:"-"`
 }
@@ -284,9 +373,12 @@ func (in *NodeStatus) GetTaskStatus() MutableTaskNodeStatus {
 	return in.TaskNodeStatus
 }
 
+// VisitNodeStatuses visits the statuses of the sub-nodes of the current attempt of this node by the ids of the sub-nodes.
 func (in NodeStatus) VisitNodeStatuses(visitor NodeStatusVisitFn) {
-	for n, s := range in.SubNodeStatus {
-		visitor(n, s)
+	for key, s := range in.SubNodeStatus {
+		if id, attempt, scoped := splitSubNodeStatusKey(key); !scoped || attempt == in.Attempts {
+			visitor(id, s)
+		}
 	}
 }
 
@@ -314,6 +406,33 @@ func (in *NodeStatus) ClearLastAttemptStartedAt() {
 
 func (in *NodeStatus) ClearSubNodeStatus() {
 	in.SubNodeStatus = nil
+	in.PreviousSubNodeInputs = nil
+	in.SetDirty()
+}
+
+// Clears the sub-node statuses of the current attempt before this node is retried, recording the inputs they wrote so
+// that the sub-nodes of the next attempt can reuse them.
+func (in *NodeStatus) ClearSubNodeStatusForRetry() {
+	var previous map[NodeID]NodeInputs
+	for key, n := range in.SubNodeStatus {
+		if n == nil || len(n.InputsHash) == 0 {
+			continue
+		}
+
+		ref := n.InputsRef
+		if len(ref) == 0 {
+			ref = GetInputsFile(n.DataDir)
+		}
+
+		if previous == nil {
+			previous = make(map[NodeID]NodeInputs, len(in.SubNodeStatus))
+		}
+
+		previous[StripAttemptSuffix(key)] = NodeInputs{Ref: ref, Hash: n.InputsHash}
+	}
+
+	in.SubNodeStatus = nil
+	in.PreviousSubNodeInputs = previous
 	in.SetDirty()
 }
 
@@ -342,12 +461,149 @@ func (in *NodeStatus) IsCached() bool {
 	return in.Cached
 }
 
+func (in *NodeStatus) SetPredicateSkipped() {
+	in.PredicateSkipped = true
+	in.SetDirty()
+}
+
+func (in *NodeStatus) IsPredicateSkipped() bool {
+	return in.PredicateSkipped
+}
+
+func (in *NodeStatus) SetReadyAt(readyAt metav1.Time) {
+	in.ReadyAt = &readyAt
+	in.SetDirty()
+}
+
+func (in *NodeStatus) GetReadyAt() *metav1.Time {
+	return in.ReadyAt
+}
+
 func (in *NodeStatus) IncrementAttempts() uint32 {
 	in.Attempts++
 	in.SetDirty()
 	return in.Attempts
 }
 
+func (in *NodeStatus) GetResourceUsage() []AttemptResourceUsage {
+	return in.ResourceUsage
+}
+
+func (in *NodeStatus) AddAttemptResourceUsage(usage AttemptResourceUsage) {
+	in.ResourceUsage = append(in.ResourceUsage, usage)
+	in.SetDirty()
+}
+
+func (in *NodeStatus) GetResourceEscalation() *ResourceEscalation {
+	return in.ResourceEscalation
+}
+
+func (in *NodeStatus) SetResourceEscalation(escalation *ResourceEscalation) {
+	in.ResourceEscalation = escalation
+	in.SetDirty()
+}
+
+func (in *NodeStatus) GetInlinedOutputs() *core.LiteralMap {
+	if in.InlinedOutputs == nil {
+		return nil
+	}
+
+	return in.InlinedOutputs.LiteralMap
+}
+
+func (in *NodeStatus) GetInputsRef() DataReference {
+	return in.InputsRef
+}
+
+func (in *NodeStatus) SetInputsRef(ref DataReference) {
+	in.InputsRef = ref
+	in.SetDirty()
+}
+
+func (in *NodeStatus) GetInputsHash() string {
+	return in.InputsHash
+}
+
+func (in *NodeStatus) SetInputsHash(hash string) {
+	in.InputsHash = hash
+	in.SetDirty()
+}
+
+func (in *NodeStatus) GetForced() *NodeForce {
+	return in.Forced
+}
+
+func (in *NodeStatus) SetForced(force *NodeForce) {
+	in.Forced = force
+	in.SetDirty()
+}
+
+func (in *NodeStatus) GetOperatorSkipped() *NodeSkip {
+	return in.OperatorSkipped
+}
+
+func (in *NodeStatus) SetOperatorSkipped(skip *NodeSkip) {
+	in.OperatorSkipped = skip
+	in.SetDirty()
+}
+
+func (in *NodeStatus) GetFanOutBackpressure() *FanOutBackpressure {
+	return in.FanOutBackpressure
+}
+
+func (in *NodeStatus) SetFanOutBackpressure(backpressure *FanOutBackpressure) {
+	in.FanOutBackpressure = backpressure
+	in.SetDirty()
+}
+
+func (in *NodeStatus) SetInlinedOutputs(outputs *core.LiteralMap) {
+	if outputs == nil {
+		in.InlinedOutputs = nil
+	} else {
+		in.InlinedOutputs = &InlinedOutputs{LiteralMap: outputs}
+	}
+
+	in.SetDirty()
+}
+
+// GetTotalResourceUsage sums up the resource usage of all attempts of this node and all of its sub-nodes.
+func (in *NodeStatus) GetTotalResourceUsage() ResourceUsage {
+	total := ResourceUsage{}
+	for _, u := range in.ResourceUsage {
+		total.Add(u.ResourceUsage)
+	}
+
+	for _, sub := range in.SubNodeStatus {
+		total.Add(sub.GetTotalResourceUsage())
+	}
+
+	return total
+}
+
+// Counts the active leaf nodes under this node, i.e. nodes that have started but not yet reached a terminal phase, and
+// how many of those are stalled waiting on something outside of propeller's control.
+func (in *NodeStatus) countStalledNodes() (active, stalled int) {
+	if in.Phase == NodePhaseNotYetStarted || IsPhaseTerminal(in.Phase) {
+		return 0, 0
+	}
+
+	for _, sub := range in.SubNodeStatus {
+		a, s := sub.countStalledNodes()
+		active += a
+		stalled += s
+	}
+
+	if active > 0 {
+		return active, stalled
+	}
+
+	if in.TaskNodeStatus != nil && len(in.TaskNodeStatus.StalledReason) > 0 {
+		return 1, 1
+	}
+
+	return 1, 0
+}
+
 func (in *NodeStatus) IncrementSystemFailures() uint32 {
 	in.SystemFailures++
 	in.SetDirty()
@@ -429,6 +685,8 @@ func (in *NodeStatus) UpdatePhase(p NodePhase, occurredAt metav1.Time, reason st
 		n = metav1.Now()
 	}
 
+	n = notBefore(n, in.QueuedAt, in.StartedAt, in.LastAttemptStartedAt, in.LastUpdatedAt)
+
 	if err != nil {
 		in.Error = &ExecutionError{err}
 	}
@@ -543,8 +801,55 @@ func (in *NodeStatus) setEphemeralNodeExecutionStatusAttributes(ctx context.Cont
 	return nil
 }
 
+// Separates the id of a sub-node from the attempt of its parent in the keys of sub-node statuses.
+const attemptSeparator = "@"
+
+// Returns the key of the status of a sub-node. The statuses of sub-nodes are scoped by the retry attempt of this node,
+// so that the sub-nodes of an attempt never pick up the status of a previous attempt. Use VisitNodeStatuses or
+// StripAttemptSuffix to get the ids of the sub-nodes from the keys.
+//
+// NOTE: this is not backwards compatible. Versions of propeller that predate the suffix do not find the statuses stored
+// under these keys, rolling back to one of them restarts the sub-nodes of running dynamic and sub-workflow nodes.
+func (in *NodeStatus) subNodeStatusKey(id NodeID) NodeID {
+	return id + attemptSeparator + strconv.FormatUint(uint64(in.Attempts), 10)
+}
+
+// StripAttemptSuffix returns the id of the sub-node a sub-node status is stored under in the status of its parent. The
+// keys of sub-node statuses are suffixed with the attempt of the parent they belong to, keys recorded before that are
+// returned unchanged.
+func StripAttemptSuffix(key NodeID) NodeID {
+	id, _, _ := splitSubNodeStatusKey(key)
+	return id
+}
+
+// Splits the key of a sub-node status into the id of the sub-node and the attempt of its parent, if the key has one.
+func splitSubNodeStatusKey(key NodeID) (id NodeID, attempt uint32, scoped bool) {
+	i := strings.LastIndex(key, attemptSeparator)
+	if i < 0 {
+		return key, 0, false
+	}
+
+	a, err := strconv.ParseUint(key[i+len(attemptSeparator):], 10, 32)
+	if err != nil {
+		return key, 0, false
+	}
+
+	return key[:i], uint32(a), true
+}
+
 func (in *NodeStatus) GetNodeExecutionStatus(ctx context.Context, id NodeID) ExecutableNodeStatus {
-	n, ok := in.SubNodeStatus[id]
+	key := in.subNodeStatusKey(id)
+	n, ok := in.SubNodeStatus[key]
+	if !ok {
+		// Statuses recorded before they were scoped by attempt belong to the current attempt, the sub-node statuses of
+		// previous attempts were cleared when this node was retried.
+		if n, ok = in.SubNodeStatus[id]; ok {
+			delete(in.SubNodeStatus, id)
+			in.SubNodeStatus[key] = n
+			in.SetDirty()
+		}
+	}
+
 	if !ok {
 		if in.SubNodeStatus == nil {
 			in.SubNodeStatus = make(map[NodeID]*NodeStatus)
@@ -554,7 +859,13 @@ func (in *NodeStatus) GetNodeExecutionStatus(ctx context.Context, id NodeID) Exe
 			MutableStruct: MutableStruct{},
 		}
 
-		in.SubNodeStatus[id] = n
+		// The sub-node starts from the inputs of its previous attempt, they are only written again if they changed.
+		if previous, found := in.PreviousSubNodeInputs[id]; found {
+			n.InputsRef = previous.Ref
+			n.InputsHash = previous.Hash
+		}
+
+		in.SubNodeStatus[key] = n
 		in.SetDirty()
 	}
 
@@ -699,6 +1010,10 @@ type TaskNodeStatus struct {
 	PluginStateVersion uint32    `json:"psv,omitempty"`
 	BarrierClockTick   uint32    `json:"tick,omitempty"`
 	LastPhaseUpdatedAt time.Time `json:"updAt,omitempty"`
+	StalledReason      string    `json:"stalledReason,omitempty"`
+	// Owner of the catalog reservation the node waits for, if the task is cache serializable and another execution
+	// with the same cache key holds the reservation.
+	ReservationOwner string `json:"resOwner,omitempty"`
 }
 
 func (in *TaskNodeStatus) GetBarrierClockTick() uint32 {
@@ -710,6 +1025,28 @@ func (in *TaskNodeStatus) SetBarrierClockTick(tick uint32) {
 	in.SetDirty()
 }
 
+func (in *TaskNodeStatus) GetStalledReason() string {
+	return in.StalledReason
+}
+
+func (in *TaskNodeStatus) SetStalledReason(reason string) {
+	if in.StalledReason != reason {
+		in.StalledReason = reason
+		in.SetDirty()
+	}
+}
+
+func (in *TaskNodeStatus) GetReservationOwner() string {
+	return in.ReservationOwner
+}
+
+func (in *TaskNodeStatus) SetReservationOwner(owner string) {
+	if in.ReservationOwner != owner {
+		in.ReservationOwner = owner
+		in.SetDirty()
+	}
+}
+
 func (in *TaskNodeStatus) SetPluginState(s []byte) {
 	in.PluginState = s
 	in.SetDirty()
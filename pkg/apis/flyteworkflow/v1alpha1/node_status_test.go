@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/flyteorg/flytestdlib/storage"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestIsPhaseTerminal(t *testing.T) {
@@ -251,4 +253,113 @@ func TestNodeStatus_GetNodeExecutionStatus(t *testing.T) {
 		assert.Equal(t, storage.DataReference("/abc/0/xyz/0"), subsubNode.GetOutputDir())
 		assert.Equal(t, storage.DataReference("/abc/0/xyz"), subsubNode.GetDataDir())
 	})
+
+	t.Run("Retried", func(t *testing.T) {
+		n := NodeStatus{
+			SubNodeStatus:            map[NodeID]*NodeStatus{},
+			DataReferenceConstructor: storage.URLPathConstructor{},
+		}
+
+		n.GetNodeExecutionStatus(ctx, "abc").UpdatePhase(NodePhaseRunning, metav1.Now(), "", nil)
+		assert.Contains(t, n.SubNodeStatus, "abc@0")
+
+		// The sub-nodes of the next attempt don't see the statuses of the previous one.
+		n.IncrementAttempts()
+		retried := n.GetNodeExecutionStatus(ctx, "abc")
+		assert.Equal(t, NodePhaseNotYetStarted, retried.GetPhase())
+		assert.Contains(t, n.SubNodeStatus, "abc@1")
+
+		// Sub-nodes are visited by their ids.
+		var visited []NodeID
+		n.VisitNodeStatuses(func(node NodeID, _ ExecutableNodeStatus) {
+			visited = append(visited, node)
+		})
+		assert.Equal(t, []NodeID{"abc"}, visited)
+	})
+
+	t.Run("Unscoped", func(t *testing.T) {
+		n := NodeStatus{
+			Attempts: 1,
+			SubNodeStatus: map[NodeID]*NodeStatus{
+				"abc": {Phase: NodePhaseRunning},
+			},
+			DataReferenceConstructor: storage.URLPathConstructor{},
+		}
+
+		// Statuses recorded before they were scoped by attempt are moved to the key of the current attempt.
+		assert.Equal(t, NodePhaseRunning, n.GetNodeExecutionStatus(ctx, "abc").GetPhase())
+		assert.NotContains(t, n.SubNodeStatus, "abc")
+		assert.Equal(t, NodePhaseRunning, n.SubNodeStatus["abc@1"].GetPhase())
+	})
+
+	t.Run("Retried with inputs", func(t *testing.T) {
+		n := NodeStatus{
+			SubNodeStatus:            map[NodeID]*NodeStatus{},
+			DataReferenceConstructor: storage.URLPathConstructor{},
+		}
+
+		n.GetNodeExecutionStatus(ctx, "abc").SetInputsHash("hash")
+		n.GetNodeExecutionStatus(ctx, "xyz")
+		n.IncrementAttempts()
+		n.ClearSubNodeStatusForRetry()
+		assert.Empty(t, n.SubNodeStatus)
+
+		// The sub-nodes of the next attempt start from the inputs written by the previous one.
+		retried := n.GetNodeExecutionStatus(ctx, "abc")
+		assert.Equal(t, DataReference("/abc/inputs.pb"), retried.GetInputsRef())
+		assert.Equal(t, "hash", retried.GetInputsHash())
+		assert.Empty(t, n.GetNodeExecutionStatus(ctx, "xyz").GetInputsRef())
+
+		n.ClearSubNodeStatus()
+		assert.Empty(t, n.PreviousSubNodeInputs)
+	})
+}
+
+func TestStripAttemptSuffix(t *testing.T) {
+	assert.Equal(t, "abc", StripAttemptSuffix("abc@1"))
+	assert.Equal(t, "abc", StripAttemptSuffix("abc"))
+	assert.Equal(t, "a@b", StripAttemptSuffix("a@b"))
+	assert.Equal(t, "a@b", StripAttemptSuffix("a@b@12"))
+}
+
+func TestNodeStatus_GetTotalResourceUsage(t *testing.T) {
+	n := &NodeStatus{
+		SubNodeStatus: map[NodeID]*NodeStatus{
+			"sub": {
+				ResourceUsage: []AttemptResourceUsage{
+					{ResourceUsage: ResourceUsage{CPUMilliCoreSeconds: 10, GPUSeconds: 1}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, ResourceUsage{CPUMilliCoreSeconds: 10, GPUSeconds: 1}, n.GetTotalResourceUsage())
+
+	n.AddAttemptResourceUsage(AttemptResourceUsage{
+		ResourceUsage: ResourceUsage{CPUMilliCoreSeconds: 5, MemoryMiBSeconds: 20},
+		Attempt:       0,
+	})
+	assert.True(t, n.IsDirty())
+	assert.Len(t, n.GetResourceUsage(), 1)
+	assert.Equal(t, ResourceUsage{CPUMilliCoreSeconds: 15, MemoryMiBSeconds: 20, GPUSeconds: 1}, n.GetTotalResourceUsage())
+
+	w := &WorkflowStatus{NodeStatus: map[NodeID]*NodeStatus{"n": n}}
+	assert.Equal(t, ResourceUsage{CPUMilliCoreSeconds: 15, MemoryMiBSeconds: 20, GPUSeconds: 1}, w.GetTotalResourceUsage())
+}
+
+func TestNodeStatus_UpdatePhase_ClockSkew(t *testing.T) {
+	now := time.Now()
+	ahead := metav1.NewTime(now.Add(time.Hour))
+	s := &NodeStatus{}
+	s.UpdatePhase(NodePhaseQueued, ahead, "", nil)
+	s.UpdatePhase(NodePhaseRunning, metav1.NewTime(now), "", nil)
+	assert.Equal(t, ahead, *s.StartedAt)
+
+	s.UpdatePhase(NodePhaseSucceeded, metav1.NewTime(now.Add(time.Minute)), "", nil)
+	assert.Equal(t, ahead, *s.StoppedAt)
+
+	later := metav1.NewTime(now.Add(2 * time.Hour))
+	s = &NodeStatus{StartedAt: &ahead}
+	s.UpdatePhase(NodePhaseFailed, later, "", nil)
+	assert.Equal(t, later, *s.StoppedAt)
 }
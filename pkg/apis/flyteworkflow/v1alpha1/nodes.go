@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"bytes"
+	"fmt"
 	"time"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
@@ -92,6 +93,67 @@ func (in *NodeMetadata) DeepCopyInto(out *NodeMetadata) {
 	// Once we figure out the autogenerate story we can replace this
 }
 
+// SkippedUpstreamPolicy determines what happens to a node if one of its upstream nodes was skipped. It applies to all
+// the upstream nodes of the node alike, per-edge policies are not supported. The compiler never sets it, flyteidl has no
+// field for it, so it is only set on FlyteWorkflows that are created or patched directly and is validated by the node
+// executor before the node starts.
+type SkippedUpstreamPolicy = string
+
+const (
+	// The node is skipped as well, and so are its own downstream nodes.
+	SkippedUpstreamPolicySkip SkippedUpstreamPolicy = "skip"
+	// The node is executed and the outputs of skipped upstream nodes resolve to none literals, i.e. the default of
+	// optional inputs.
+	SkippedUpstreamPolicyUseDefaults SkippedUpstreamPolicy = "useDefaults"
+	// The node fails.
+	SkippedUpstreamPolicyFail SkippedUpstreamPolicy = "fail"
+)
+
+// StartDelayFrom is the time a start delay is relative to.
+type StartDelayFrom = string
+
+const (
+	// StartDelayFromUpstream delays the node from the time its upstream nodes completed.
+	StartDelayFromUpstream StartDelayFrom = "upstream"
+	// StartDelayFromWorkflow delays the node from the time the workflow was created.
+	StartDelayFromWorkflow StartDelayFrom = "workflow"
+)
+
+// StartDelay holds a node back after it is ready to run, e.g. to stagger the nodes of a fan-out that call a shared
+// service instead of starting them all at once.
+type StartDelay struct {
+	Duration v1.Duration `json:"duration"`
+	// Defaults to upstream.
+	// +optional
+	From StartDelayFrom `json:"from,omitempty"`
+}
+
+func (in *StartDelay) GetFrom() StartDelayFrom {
+	if len(in.From) == 0 {
+		return StartDelayFromUpstream
+	}
+
+	return in.From
+}
+
+// SkippedUpstreamPolicies lists all valid values of SkippedUpstreamPolicy.
+var SkippedUpstreamPolicies = []SkippedUpstreamPolicy{
+	SkippedUpstreamPolicySkip,
+	SkippedUpstreamPolicyUseDefaults,
+	SkippedUpstreamPolicyFail,
+}
+
+// ValidateSkippedUpstreamPolicy returns an error if the policy is not one of SkippedUpstreamPolicies.
+func ValidateSkippedUpstreamPolicy(policy SkippedUpstreamPolicy) error {
+	for _, known := range SkippedUpstreamPolicies {
+		if policy == known {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown skipped upstream policy [%v], expected one of %v", policy, SkippedUpstreamPolicies)
+}
+
 type NodeSpec struct {
 	ID            NodeID                        `json:"id"`
 	Name          string                        `json:"name,omitempty"`
@@ -144,6 +206,52 @@ type NodeSpec struct {
 	// The value set to True means task is OK with getting interrupted
 	// +optional
 	Interruptibe *bool `json:"interruptible,omitempty"`
+	// A predicate over the resolved inputs of the node. If it evaluates to false, the node is skipped and nodes that
+	// consume its outputs see them as absent instead of being skipped as well.
+	// +optional
+	When *BooleanExpression `json:"when,omitempty"`
+	// Determines what happens to the node if one of its upstream nodes was skipped. Defaults to skipping the node too.
+	// Applies to all upstream nodes, see SkippedUpstreamPolicy.
+	// +optional
+	SkippedUpstreamPolicy SkippedUpstreamPolicy `json:"skippedUpstreamPolicy,omitempty"`
+	// Labels to add to the resources launched for the node, on top of those of the workflow. Useful to attribute the
+	// cost of a single task or to target it with network policies.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations to add to the resources launched for the node, on top of those of the workflow.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Delays the start of the node once it is ready to run.
+	// +optional
+	StartDelay *StartDelay `json:"startDelay,omitempty"`
+}
+
+func (in *NodeSpec) GetStartDelay() *StartDelay {
+	return in.StartDelay
+}
+
+func (in *NodeSpec) GetLabels() map[string]string {
+	return in.Labels
+}
+
+func (in *NodeSpec) GetAnnotations() map[string]string {
+	return in.Annotations
+}
+
+func (in *NodeSpec) GetSkippedUpstreamPolicy() SkippedUpstreamPolicy {
+	if len(in.SkippedUpstreamPolicy) == 0 {
+		return SkippedUpstreamPolicySkip
+	}
+
+	return in.SkippedUpstreamPolicy
+}
+
+func (in *NodeSpec) GetWhen() *core.BooleanExpression {
+	if in.When == nil {
+		return nil
+	}
+
+	return in.When.BooleanExpression
 }
 
 func (in *NodeSpec) GetName() string {
@@ -0,0 +1,36 @@
+package v1alpha1
+
+import (
+	"bytes"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/golang/protobuf/jsonpb"
+)
+
+// Wrapper around the outputs of a node that are inlined into its status. core.LiteralMap contains protobuf oneofs and
+// hence needs to be wrapped by custom marshaller
+type InlinedOutputs struct {
+	*core.LiteralMap
+}
+
+func (in *InlinedOutputs) UnmarshalJSON(b []byte) error {
+	in.LiteralMap = &core.LiteralMap{}
+	return jsonpb.Unmarshal(bytes.NewReader(b), in.LiteralMap)
+}
+
+func (in *InlinedOutputs) MarshalJSON() ([]byte, error) {
+	if in == nil {
+		return nilJSON, nil
+	}
+
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, in.LiteralMap); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (in *InlinedOutputs) DeepCopyInto(out *InlinedOutputs) {
+	*out = *in
+	// The outputs are never mutated once inlined, so it is ok to share them
+}
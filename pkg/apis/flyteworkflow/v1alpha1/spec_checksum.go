@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"runtime/debug"
+)
+
+// AllowSpecMutationAnnotation lets a workflow run even though its spec no longer matches its spec checksum, e.g. after
+// an operator edited the spec of a stuck execution on purpose.
+const AllowSpecMutationAnnotation = "flyte.org/allow-spec-mutation"
+
+// SpecChecksumSerializer identifies how spec checksums are computed, i.e. the encoding of the spec and the version of
+// flyteidl it is decoded with. Fields that one version of flyteidl knows and another drops change the checksum of the
+// same spec, so a checksum is only verified by propellers built with the serializer that computed it.
+var SpecChecksumSerializer = "json-v1+flyteidl@" + flyteidlVersion()
+
+func flyteidlVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != "github.com/flyteorg/flyteidl" {
+			continue
+		}
+
+		if dep.Replace != nil && len(dep.Replace.Version) > 0 {
+			return dep.Replace.Version
+		}
+
+		return dep.Version
+	}
+
+	return "unknown"
+}
+
+// The parts of a workflow covered by its spec checksum.
+type checksummedSpec struct {
+	Spec         *WorkflowSpec                `json:"spec"`
+	SubWorkflows map[WorkflowID]*WorkflowSpec `json:"subWorkflows"`
+	Tasks        map[TaskID]*TaskSpec         `json:"tasks"`
+}
+
+// ComputeSpecChecksum returns the hex encoded sha256 of the compiled spec of the workflow, i.e. its primary workflow,
+// subworkflows and tasks. It is the same for workflows that are equal once written, as maps are serialized in order.
+func (in *FlyteWorkflow) ComputeSpecChecksum() (string, error) {
+	raw, err := json.Marshal(checksummedSpec{
+		Spec:         in.WorkflowSpec,
+		SubWorkflows: in.SubWorkflows,
+		Tasks:        in.Tasks,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ShouldVerifySpecChecksum returns false for workflows that are never considered tampered with: workflows without a
+// checksum, whose checksum was computed by another serializer, or annotated to allow mutations.
+func (in *FlyteWorkflow) ShouldVerifySpecChecksum() bool {
+	if len(in.SpecChecksum) == 0 || in.GetAnnotations()[AllowSpecMutationAnnotation] == "true" {
+		return false
+	}
+
+	return in.SpecChecksumSerializer == SpecChecksumSerializer
+}
+
+// IsSpecTampered returns true if the spec of the workflow was changed since its creation, i.e. it does not match its
+// spec checksum, see ShouldVerifySpecChecksum.
+func (in *FlyteWorkflow) IsSpecTampered() (bool, error) {
+	if !in.ShouldVerifySpecChecksum() {
+		return false, nil
+	}
+
+	checksum, err := in.ComputeSpecChecksum()
+	if err != nil {
+		return false, err
+	}
+
+	return checksum != in.SpecChecksum, nil
+}
@@ -0,0 +1,81 @@
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func TestFlyteWorkflow_IsSpecTampered(t *testing.T) {
+	j, err := ReadYamlFileAsJSON("testdata/workflowspec.yaml")
+	assert.NoError(t, err)
+	task, err := ReadYamlFileAsJSON("testdata/task.yaml")
+	assert.NoError(t, err)
+
+	read := func() *v1alpha1.FlyteWorkflow {
+		w := &v1alpha1.FlyteWorkflow{}
+		assert.NoError(t, json.Unmarshal(j, w))
+		w.Tasks = map[v1alpha1.TaskID]*v1alpha1.TaskSpec{"t1": {}}
+		assert.NoError(t, json.Unmarshal(task, w.Tasks["t1"]))
+		return w
+	}
+
+	w := read()
+	checksum, err := w.ComputeSpecChecksum()
+	assert.NoError(t, err)
+	assert.Len(t, checksum, 64)
+	w.SpecChecksum = checksum
+	w.SpecChecksumSerializer = v1alpha1.SpecChecksumSerializer
+
+	t.Run("unchanged-after-round-trip", func(t *testing.T) {
+		raw, err := json.Marshal(w)
+		assert.NoError(t, err)
+		written := &v1alpha1.FlyteWorkflow{}
+		assert.NoError(t, json.Unmarshal(raw, written))
+
+		tampered, err := written.IsSpecTampered()
+		assert.NoError(t, err)
+		assert.False(t, tampered)
+	})
+
+	t.Run("mutated", func(t *testing.T) {
+		mutated := w.DeepCopy()
+		for id := range mutated.Nodes {
+			delete(mutated.Nodes, id)
+			break
+		}
+
+		tampered, err := mutated.IsSpecTampered()
+		assert.NoError(t, err)
+		assert.True(t, tampered)
+
+		mutated.Annotations = map[string]string{v1alpha1.AllowSpecMutationAnnotation: "true"}
+		tampered, err = mutated.IsSpecTampered()
+		assert.NoError(t, err)
+		assert.False(t, tampered)
+	})
+
+	t.Run("other-serializer", func(t *testing.T) {
+		mutated := w.DeepCopy()
+		mutated.SpecChecksumSerializer = "json-v1+flyteidl@v0.0.1"
+		for id := range mutated.Nodes {
+			delete(mutated.Nodes, id)
+			break
+		}
+
+		tampered, err := mutated.IsSpecTampered()
+		assert.NoError(t, err)
+		assert.False(t, tampered)
+	})
+
+	t.Run("no-checksum", func(t *testing.T) {
+		w := read()
+		w.Nodes = nil
+		tampered, err := w.IsSpecTampered()
+		assert.NoError(t, err)
+		assert.False(t, tampered)
+	})
+}
@@ -0,0 +1,177 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SpecVersion is the version of the FlyteWorkflow spec. It is bumped whenever fields are added to or change meaning in
+// the spec, so that propellers of different versions, e.g. during a rolling upgrade, can tell whether they can run a
+// workflow.
+type SpecVersion int
+
+const (
+	// SpecVersion0 is the version of workflows written before the spec was versioned.
+	SpecVersion0 SpecVersion = iota
+	SpecVersion1
+	// SpecVersion2 adds task references, i.e. task templates offloaded to the blob store. Workflows that have any must
+	// set it as their min spec version, older propellers cannot find their tasks.
+	SpecVersion2
+	// SpecVersion3 adds closure references, i.e. workflows whose spec, subworkflows and tasks are built from a compiled
+	// workflow closure offloaded to the blob store. Workflows that have one must set it as their min spec version, older
+	// propellers find no nodes to run.
+	SpecVersion3
+	// SpecVersion4 adds spec checksums, which older propellers ignore.
+	SpecVersion4
+)
+
+const (
+	// LatestSpecVersion is the version of the spec this propeller writes and fully understands.
+	LatestSpecVersion = SpecVersion4
+	// MinSupportedSpecVersion is the oldest version of the spec this propeller can upgrade and run.
+	MinSupportedSpecVersion = SpecVersion0
+	// CompatibleSpecVersion is the oldest version a propeller must understand to run workflows of the latest version.
+	// It is only raised when a new version changes the meaning of fields older propellers know, because older
+	// propellers otherwise run newer workflows by ignoring, and preserving, the fields they do not know.
+	CompatibleSpecVersion = SpecVersion1
+)
+
+// SpecCompatibility describes whether this propeller can run a workflow, given the version of its spec.
+type SpecCompatibility int
+
+const (
+	SpecCompatible SpecCompatibility = iota
+	// The spec is older than the oldest version this propeller can upgrade.
+	SpecTooOld
+	// The spec requires a newer propeller.
+	SpecTooNew
+)
+
+// Upgrades the spec of a workflow, keyed by the version they upgrade from, by defaulting the fields the next version
+// introduced. They must be idempotent as they are applied every time a workflow is evaluated.
+var specUpgrades = map[SpecVersion]func(w *FlyteWorkflow){
+	SpecVersion0: func(w *FlyteWorkflow) {
+		// Workflows written by old admins only have the deprecated connections.
+		if w.WorkflowSpec != nil {
+			w.GetConnections()
+		}
+
+		for _, subWf := range w.SubWorkflows {
+			if subWf != nil {
+				subWf.GetConnections()
+			}
+		}
+	},
+}
+
+func (in *FlyteWorkflow) GetSpecVersion() SpecVersion {
+	return in.SpecVersion
+}
+
+func (in *FlyteWorkflow) GetMinSpecVersion() SpecVersion {
+	return in.MinSpecVersion
+}
+
+// GetSpecCompatibility returns whether this propeller can run the workflow.
+func (in *FlyteWorkflow) GetSpecCompatibility() SpecCompatibility {
+	if in.GetSpecVersion() < MinSupportedSpecVersion {
+		return SpecTooOld
+	}
+
+	if in.GetMinSpecVersion() > LatestSpecVersion {
+		return SpecTooNew
+	}
+
+	return SpecCompatible
+}
+
+// UpgradeSpec defaults the fields that were added to the spec after the version the workflow was written with, so that
+// it can be run as a workflow of the latest version. The spec version is left untouched, it records the writer.
+func (in *FlyteWorkflow) UpgradeSpec() {
+	for v := in.GetSpecVersion(); v < LatestSpecVersion; v++ {
+		if upgrade, ok := specUpgrades[v]; ok {
+			upgrade(in)
+		}
+	}
+}
+
+// flyteWorkflowJSON has the fields of a FlyteWorkflow without its json methods.
+type flyteWorkflowJSON FlyteWorkflow
+
+// The json keys of the top-level fields of a FlyteWorkflow.
+var knownFlyteWorkflowFields = jsonFieldNames(reflect.TypeOf(FlyteWorkflow{}))
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	res := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		if name == "" && f.Anonymous {
+			for inlined := range jsonFieldNames(f.Type) {
+				res[inlined] = true
+			}
+		} else if name == "" {
+			res[f.Name] = true
+		} else {
+			res[name] = true
+		}
+	}
+
+	return res
+}
+
+func (in *FlyteWorkflow) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, (*flyteWorkflowJSON)(in)); err != nil {
+		return err
+	}
+
+	// Workflows written with the latest version only have known fields, skip looking for unknown ones.
+	in.UnknownFields = nil
+	if in.GetSpecVersion() == LatestSpecVersion {
+		return nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	for name := range knownFlyteWorkflowFields {
+		delete(fields, name)
+	}
+
+	if len(fields) > 0 {
+		in.UnknownFields = fields
+	}
+
+	return nil
+}
+
+func (in *FlyteWorkflow) MarshalJSON() ([]byte, error) {
+	raw, err := json.Marshal((*flyteWorkflowJSON)(in))
+	if err != nil || len(in.UnknownFields) == 0 {
+		return raw, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	for name, value := range in.UnknownFields {
+		if _, found := fields[name]; !found {
+			fields[name] = value
+		}
+	}
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,75 @@
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func TestFlyteWorkflow_UnknownFields(t *testing.T) {
+	t.Run("preserved-for-other-versions", func(t *testing.T) {
+		raw := []byte(`{"kind":"FlyteWorkflow","metadata":{"name":"wf"},"spec":{"id":"wf"},"executionId":{},"tasks":{},` +
+			`"specVersion":5,"minSpecVersion":1,"newerField":{"a":1}}`)
+		w := &v1alpha1.FlyteWorkflow{}
+		assert.NoError(t, json.Unmarshal(raw, w))
+		assert.Equal(t, "wf", w.GetName())
+		assert.Equal(t, v1alpha1.SpecVersion(5), w.GetSpecVersion())
+		assert.Equal(t, map[string]json.RawMessage{"newerField": json.RawMessage(`{"a":1}`)}, w.UnknownFields)
+		assert.Equal(t, w.UnknownFields, w.DeepCopy().UnknownFields)
+
+		written, err := json.Marshal(w)
+		assert.NoError(t, err)
+		fields := map[string]json.RawMessage{}
+		assert.NoError(t, json.Unmarshal(written, &fields))
+		assert.Equal(t, json.RawMessage(`{"a":1}`), fields["newerField"])
+		assert.Equal(t, json.RawMessage(`"FlyteWorkflow"`), fields["kind"])
+		assert.Contains(t, fields, "spec")
+	})
+
+	t.Run("ignored-for-latest-version", func(t *testing.T) {
+		raw := []byte(`{"spec":{"id":"wf"},"executionId":{},"tasks":{},"specVersion":4,"droppedField":true}`)
+		w := &v1alpha1.FlyteWorkflow{}
+		assert.NoError(t, json.Unmarshal(raw, w))
+		assert.Nil(t, w.UnknownFields)
+
+		written, err := json.Marshal(w)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(written), "droppedField")
+	})
+}
+
+func TestFlyteWorkflow_GetSpecCompatibility(t *testing.T) {
+	assert.Equal(t, v1alpha1.SpecCompatible, (&v1alpha1.FlyteWorkflow{}).GetSpecCompatibility())
+	assert.Equal(t, v1alpha1.SpecCompatible, (&v1alpha1.FlyteWorkflow{
+		SpecVersion:    v1alpha1.LatestSpecVersion + 1,
+		MinSpecVersion: v1alpha1.LatestSpecVersion,
+	}).GetSpecCompatibility())
+	assert.Equal(t, v1alpha1.SpecTooNew, (&v1alpha1.FlyteWorkflow{
+		SpecVersion:    v1alpha1.LatestSpecVersion + 1,
+		MinSpecVersion: v1alpha1.LatestSpecVersion + 1,
+	}).GetSpecCompatibility())
+	assert.Equal(t, v1alpha1.SpecTooOld, (&v1alpha1.FlyteWorkflow{
+		SpecVersion: v1alpha1.MinSupportedSpecVersion - 1,
+	}).GetSpecCompatibility())
+}
+
+func TestFlyteWorkflow_UpgradeSpec(t *testing.T) {
+	deprecated := v1alpha1.DeprecatedConnections{
+		DownstreamEdges: map[v1alpha1.NodeID][]v1alpha1.NodeID{"n1": {"n2"}},
+		UpstreamEdges:   map[v1alpha1.NodeID][]v1alpha1.NodeID{"n2": {"n1"}},
+	}
+	w := &v1alpha1.FlyteWorkflow{
+		WorkflowSpec: &v1alpha1.WorkflowSpec{DeprecatedConnections: deprecated},
+		SubWorkflows: map[v1alpha1.WorkflowID]*v1alpha1.WorkflowSpec{
+			"sub": {DeprecatedConnections: deprecated},
+		},
+	}
+
+	w.UpgradeSpec()
+	assert.Equal(t, deprecated.DownstreamEdges, w.Connections.Downstream)
+	assert.Equal(t, deprecated.UpstreamEdges, w.SubWorkflows["sub"].Connections.Upstream)
+	assert.Equal(t, v1alpha1.SpecVersion0, w.GetSpecVersion())
+}
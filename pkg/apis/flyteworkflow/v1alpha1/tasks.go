@@ -37,3 +37,28 @@ func (in *TaskSpec) UnmarshalJSON(b []byte) error {
 	in.TaskTemplate = &core.TaskTemplate{}
 	return jsonpb.Unmarshal(bytes.NewReader(b), in.TaskTemplate)
 }
+
+// TaskReference points at a task template that was offloaded to the blob store instead of being embedded in the
+// workflow, which keeps the CRDs of workflows with many tasks small. Nodes read the template from the URI and verify it
+// against the checksum.
+type TaskReference struct {
+	ID   *Identifier   `json:"id"`
+	Type TaskType      `json:"type"`
+	URI  DataReference `json:"uri"`
+	// Hex encoded sha256 of the serialized task template.
+	Checksum string `json:"checksum"`
+}
+
+func (in *TaskReference) TaskType() TaskType {
+	return in.Type
+}
+
+// CoreTask only returns the id and the type of the task. The rest of the template has to be read from the URI.
+func (in *TaskReference) CoreTask() *core.TaskTemplate {
+	tmpl := &core.TaskTemplate{Type: in.Type}
+	if in.ID != nil {
+		tmpl.Id = in.ID.Identifier
+	}
+
+	return tmpl
+}
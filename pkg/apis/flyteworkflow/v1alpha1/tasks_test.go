@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,3 +19,24 @@ func TestTaskSpec(t *testing.T) {
 	assert.NotNil(t, task.CoreTask())
 	assert.Equal(t, "demo", task.TaskType())
 }
+
+func TestFlyteWorkflow_GetTask_Reference(t *testing.T) {
+	w := &v1alpha1.FlyteWorkflow{
+		TaskReferences: map[v1alpha1.TaskID]*v1alpha1.TaskReference{
+			"t1": {
+				ID:   &v1alpha1.Identifier{Identifier: &core.Identifier{Name: "t1"}},
+				Type: "container",
+				URI:  "s3://bucket/t1.pb",
+			},
+		},
+	}
+
+	task, err := w.GetTask("t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "container", task.TaskType())
+	assert.Equal(t, "t1", task.CoreTask().Id.Name)
+	assert.Equal(t, w.TaskReferences, w.DeepCopy().TaskReferences)
+
+	_, err = w.GetTask("t2")
+	assert.Error(t, err)
+}
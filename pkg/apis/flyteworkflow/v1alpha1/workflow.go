@@ -18,6 +18,20 @@ import (
 const StartNodeID = "start-node"
 const EndNodeID = "end-node"
 
+// SystemNodeID is a reserved node id. Bindings to its outputs resolve to values provided by the system at runtime
+// instead of the outputs of an upstream node.
+const SystemNodeID = "system-node"
+
+const (
+	// The time the execution was created, as a datetime.
+	SystemVarKickoffTime = "kickoff_time"
+	// The time a scheduled execution was scheduled for, as a datetime. Executions that were not launched by a schedule
+	// resolve it to the kickoff time.
+	SystemVarScheduledTime = "scheduled_time"
+	// The name of the execution, as a string.
+	SystemVarExecutionID = "execution_id"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -39,6 +53,9 @@ type FlyteWorkflow struct {
 	NodeDefaults NodeDefaults `json:"node-defaults,omitempty"`
 	// Specifies the time when the workflow has been accepted into the system.
 	AcceptedAt *metav1.Time `json:"acceptedAt,omitempty"`
+	// Specifies the time the execution was scheduled for, if it was launched by a schedule.
+	// +optional
+	ScheduledAt *metav1.Time `json:"scheduledAt,omitempty"`
 	// [DEPRECATED] ServiceAccountName is the name of the ServiceAccount to use to run this pod.
 	// [DEPRECATED] More info: https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/
 	// [DEPRECATED] +optional
@@ -52,6 +69,32 @@ type FlyteWorkflow struct {
 	RawOutputDataConfig RawOutputDataConfig `json:"rawOutputDataConfig,omitempty"`
 	// Workflow-execution specifications and overrides
 	ExecutionConfig ExecutionConfig `json:"executionConfig,omitempty"`
+	// Problems the compiler found in the workflow and its subworkflows that do not prevent it from executing.
+	// +optional
+	CompilationWarnings []CompilationWarning `json:"compilationWarnings,omitempty"`
+	// Version of the spec the workflow was written with, workflows written before the spec was versioned have none.
+	// +optional
+	SpecVersion SpecVersion `json:"specVersion,omitempty"`
+	// Oldest spec version a propeller must understand to run the workflow.
+	// +optional
+	MinSpecVersion SpecVersion `json:"minSpecVersion,omitempty"`
+	// Tasks whose templates were offloaded to the blob store instead of being embedded in Tasks. Workflows that have
+	// any require spec version 2.
+	// +optional
+	TaskReferences map[TaskID]*TaskReference `json:"taskReferences,omitempty"`
+	// Compiled workflow closure the spec, subworkflows and tasks of the workflow are built from, instead of being
+	// embedded in the workflow. Workflows that have one require spec version 3.
+	// +optional
+	ClosureReference *WorkflowClosureReference `json:"closureReference,omitempty"`
+	// Hex encoded sha256 of the compiled spec of the workflow, as of its creation. Propeller warns about workflows whose
+	// spec no longer matches it, or fails them if configured to, unless they are annotated to allow it. Requires spec
+	// version 4.
+	// +optional
+	SpecChecksum string `json:"specChecksum,omitempty"`
+	// Serializer the spec checksum was computed with, see SpecChecksumSerializer. Checksums computed with another one,
+	// e.g. by an admin built with another version of flyteidl, are not verified.
+	// +optional
+	SpecChecksumSerializer string `json:"specChecksumSerializer,omitempty"`
 
 	// non-Serialized fields (these will not get written to etcd)
 	// As of 2020-07, the only real implementation of this interface is a URLPathConstructor, which is just an empty
@@ -59,6 +102,27 @@ type FlyteWorkflow struct {
 	// so that it can be used downstream without any confusion.
 	// This field is here because it's easier to put it here than pipe through a new object through all of propeller.
 	DataReferenceConstructor storage.ReferenceConstructor `json:"-"`
+	// Top-level fields of a workflow written with another spec version that this propeller does not know. They are
+	// written back as they were read so that updating the workflow does not drop them for the propeller that wrote them.
+	UnknownFields map[string]json.RawMessage `json:"-"`
+}
+
+// WorkflowClosureReference points at the compiled workflow closure of a workflow that was offloaded to the blob store,
+// which spares admin from converting the closure and keeps the CRDs of large workflows small. Propeller trusts the
+// closure as compiled by admin and only verifies it against the checksum.
+type WorkflowClosureReference struct {
+	URI DataReference `json:"uri"`
+	// Hex encoded sha256 of the serialized closure.
+	Checksum string `json:"checksum"`
+}
+
+// CompilationWarning is a problem the compiler found in a workflow, which does not prevent the workflow from executing.
+type CompilationWarning struct {
+	// Id of the workflow, or subworkflow, the node belongs to.
+	WorkflowID WorkflowID `json:"workflowId"`
+	NodeID     NodeID     `json:"nodeId"`
+	Code       string     `json:"code"`
+	Message    string     `json:"message"`
 }
 
 func (in *FlyteWorkflow) GetSecurityContext() core.SecurityContext {
@@ -72,6 +136,10 @@ func (in *FlyteWorkflow) GetEventVersion() EventVersion {
 	return EventVersion0
 }
 
+func (in *FlyteWorkflow) GetScheduledAt() *metav1.Time {
+	return in.ScheduledAt
+}
+
 func (in *FlyteWorkflow) GetExecutionConfig() ExecutionConfig {
 	return in.ExecutionConfig
 }
@@ -107,6 +175,10 @@ func (in *FlyteWorkflow) GetOwnerReference() metav1.OwnerReference {
 func (in *FlyteWorkflow) GetTask(id TaskID) (ExecutableTask, error) {
 	t, ok := in.Tasks[id]
 	if !ok {
+		if ref, found := in.TaskReferences[id]; found {
+			return ref, nil
+		}
+
 		return nil, errors.Errorf("Unable to find task with Id [%v]", id)
 	}
 	return t, nil
@@ -1,14 +1,16 @@
 package v1alpha1
 
 import (
+	"bytes"
 	"context"
 	"strconv"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
-	"github.com/flyteorg/flytestdlib/logger"
 
 	"github.com/flyteorg/flytestdlib/storage"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 const maxMessageSize = 1024
@@ -35,18 +37,97 @@ type WorkflowStatus struct {
 	// Stores the Error during the Execution of the Workflow. It is optional and usually associated with Failing/Failed state only
 	Error *ExecutionError `json:"error,omitempty"`
 
+	// The data key that encrypts the futures of this execution, if encryption is enabled.
+	DataKey *DataKeyReference `json:"dataKey,omitempty"`
+
+	// The last workflow event the control plane accepted for this execution.
+	EventCheckpoint *WorkflowEventCheckpoint `json:"eventCheckpoint,omitempty"`
+
+	// Number of evaluation rounds whose status was written. Unlike the timestamps, which are taken from the clocks of
+	// the propeller replicas that evaluated the workflow, it orders the rounds even if the clocks are skewed.
+	Round uint64 `json:"round,omitempty"`
+
+	// The last graph snapshot of the workflow written to the datastore, if graph snapshots are enabled.
+	GraphSnapshot *GraphSnapshotReference `json:"graphSnapshot,omitempty"`
+
+	// The checksum of the modified spec of the workflow that was last reported, if workflows whose spec no longer
+	// matches its checksum are only warned about. Each modification is reported once.
+	SpecMismatchChecksum string `json:"specMismatchChecksum,omitempty"`
+
 	// non-Serialized fields
 	DataReferenceConstructor storage.ReferenceConstructor `json:"-"`
 }
 
+// DataKeyReference holds a data key encrypted with a master key (envelope encryption). The plaintext data key is never
+// stored.
+type DataKeyReference struct {
+	// ID of the master key that encrypted the data key.
+	MasterKeyID string `json:"masterKeyId"`
+	// The encrypted data key.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// GraphSnapshotReference locates the graph snapshot of a workflow, a description of its nodes, edges and their current
+// phases that external UIs can render without parsing the FlyteWorkflow.
+type GraphSnapshotReference struct {
+	Location DataReference `json:"location"`
+	// Hash of the phases the snapshot describes, so that it is only written again when one of them changes.
+	Hash string `json:"hash"`
+}
+
+// WorkflowEventCheckpoint records the phase of the last workflow event the control plane accepted. Events are only
+// recorded for phases other than the checkpointed one, so that a workflow that goes through several phases reporting the
+// same event phase, or that is evaluated again before its phase changes, does not record the event twice.
+type WorkflowEventCheckpoint struct {
+	Phase      core.WorkflowExecution_Phase `json:"phase"`
+	RecordedAt metav1.Time                  `json:"recordedAt"`
+}
+
+func (in *WorkflowEventCheckpoint) Equals(other *WorkflowEventCheckpoint) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+
+	return in.Phase == other.Phase
+}
+
+func (in *DataKeyReference) Equals(other *DataKeyReference) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+
+	return in.MasterKeyID == other.MasterKeyID && bytes.Equal(in.Ciphertext, other.Ciphertext)
+}
+
 func IsWorkflowPhaseTerminal(p WorkflowPhase) bool {
 	return p == WorkflowPhaseFailed || p == WorkflowPhaseSuccess || p == WorkflowPhaseAborted
 }
 
+func (in *WorkflowStatus) GetEventCheckpoint() *WorkflowEventCheckpoint {
+	return in.EventCheckpoint
+}
+
+func (in *WorkflowStatus) SetEventCheckpoint(checkpoint *WorkflowEventCheckpoint) {
+	in.EventCheckpoint = checkpoint
+}
+
 func (in *WorkflowStatus) SetMessage(msg string) {
 	in.Message = msg
 }
 
+// Returns the time to record a status change at, i.e. t unless a time recorded earlier in the same status is after it,
+// e.g. because it was taken by a propeller replica whose clock is ahead. This keeps the timestamps of a status ordered,
+// so that the durations computed from them are never negative.
+func notBefore(t metav1.Time, earlier ...*metav1.Time) metav1.Time {
+	for _, e := range earlier {
+		if e != nil && t.Before(e) {
+			t = *e
+		}
+	}
+
+	return t
+}
+
 func (in *WorkflowStatus) UpdatePhase(p WorkflowPhase, msg string, err *core.ExecutionError) {
 	in.Phase = p
 	in.Message = msg
@@ -54,7 +135,7 @@ func (in *WorkflowStatus) UpdatePhase(p WorkflowPhase, msg string, err *core.Exe
 		in.Message = msg[:maxMessageSize]
 	}
 
-	n := metav1.Now()
+	n := notBefore(metav1.Now(), in.StartedAt, in.LastUpdatedAt)
 	if in.StartedAt == nil {
 		in.StartedAt = &n
 	}
@@ -77,6 +158,11 @@ func (in *WorkflowStatus) GetExecutionError() *core.ExecutionError {
 	return nil
 }
 
+// IncRound records that the status of another round is about to be written.
+func (in *WorkflowStatus) IncRound() {
+	in.Round++
+}
+
 func (in *WorkflowStatus) IncFailedAttempts() {
 	in.FailedAttempts++
 }
@@ -101,6 +187,33 @@ func (in *WorkflowStatus) IsTerminated() bool {
 	return in.Phase == WorkflowPhaseSuccess || in.Phase == WorkflowPhaseFailed || in.Phase == WorkflowPhaseAborted
 }
 
+// GetTotalResourceUsage sums up the resource usage of all the nodes in the workflow, including sub-nodes.
+func (in *WorkflowStatus) GetTotalResourceUsage() ResourceUsage {
+	total := ResourceUsage{}
+	for _, n := range in.NodeStatus {
+		total.Add(n.GetTotalResourceUsage())
+	}
+
+	return total
+}
+
+// IsStalled returns true if the workflow is still running and every active node is stalled, e.g. waiting on resource
+// quota or an image pull. Re-evaluating such a workflow does not make progress until the external condition clears.
+func (in *WorkflowStatus) IsStalled() bool {
+	if in.IsTerminated() {
+		return false
+	}
+
+	active, stalled := 0, 0
+	for _, n := range in.NodeStatus {
+		a, s := n.countStalledNodes()
+		active += a
+		stalled += s
+	}
+
+	return active > 0 && active == stalled
+}
+
 func (in *WorkflowStatus) GetMessage() string {
 	return in.Message
 }
@@ -139,7 +252,7 @@ func (in *WorkflowStatus) GetNodeExecutionStatus(ctx context.Context, id NodeID)
 
 	dataDir, err := in.ConstructNodeDataDir(ctx, id)
 	if err != nil {
-		logger.Errorf(ctx, "Failed to construct data dir for node [%v], exec id [%v]", id)
+		logger.Errorf(ctx, "Failed to construct data dir for node [%v]. Error: %v", id, err)
 		return n
 	}
 
@@ -197,6 +310,18 @@ func (in *WorkflowStatus) Equals(other *WorkflowStatus) bool {
 		return false
 	}
 
+	if !in.DataKey.Equals(other.DataKey) {
+		return false
+	}
+
+	if !in.EventCheckpoint.Equals(other.EventCheckpoint) {
+		return false
+	}
+
+	if in.SpecMismatchChecksum != other.SpecMismatchChecksum {
+		return false
+	}
+
 	if len(in.NodeStatus) != len(other.NodeStatus) {
 		return false
 	}
@@ -2,8 +2,10 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestIsWorkflowPhaseTerminal(t *testing.T) {
@@ -52,3 +54,66 @@ func TestWorkflowStatus_Equals(t *testing.T) {
 	other.OutputReference = "out"
 	assert.True(t, one.Equals(other))
 }
+
+func TestWorkflowStatus_IsStalled(t *testing.T) {
+	stalledTask := &NodeStatus{
+		Phase:          NodePhaseRunning,
+		TaskNodeStatus: &TaskNodeStatus{StalledReason: "ImagePullBackOff"},
+	}
+
+	runningTask := &NodeStatus{
+		Phase:          NodePhaseRunning,
+		TaskNodeStatus: &TaskNodeStatus{},
+	}
+
+	t.Run("no-active-nodes", func(t *testing.T) {
+		s := &WorkflowStatus{
+			Phase: WorkflowPhaseRunning,
+			NodeStatus: map[NodeID]*NodeStatus{
+				"start": {Phase: NodePhaseSucceeded},
+				"n1":    {Phase: NodePhaseNotYetStarted},
+			},
+		}
+		assert.False(t, s.IsStalled())
+	})
+
+	t.Run("all-stalled", func(t *testing.T) {
+		s := &WorkflowStatus{
+			Phase: WorkflowPhaseRunning,
+			NodeStatus: map[NodeID]*NodeStatus{
+				"start": {Phase: NodePhaseSucceeded},
+				"n1":    stalledTask,
+				"n2": {
+					Phase:         NodePhaseRunning,
+					SubNodeStatus: map[NodeID]*NodeStatus{"n2-1": stalledTask},
+				},
+			},
+		}
+		assert.True(t, s.IsStalled())
+
+		s.Phase = WorkflowPhaseAborted
+		assert.False(t, s.IsStalled())
+	})
+
+	t.Run("some-progressing", func(t *testing.T) {
+		s := &WorkflowStatus{
+			Phase: WorkflowPhaseRunning,
+			NodeStatus: map[NodeID]*NodeStatus{
+				"n1": stalledTask,
+				"n2": runningTask,
+			},
+		}
+		assert.False(t, s.IsStalled())
+	})
+}
+
+func TestWorkflowStatus_UpdatePhase_ClockSkew(t *testing.T) {
+	// Recorded by a replica whose clock is an hour ahead.
+	ahead := metav1.NewTime(time.Now().Add(time.Hour))
+	s := &WorkflowStatus{StartedAt: &ahead, LastUpdatedAt: &ahead}
+
+	s.UpdatePhase(WorkflowPhaseSuccess, "", nil)
+	assert.Equal(t, ahead, *s.StoppedAt)
+	assert.False(t, s.StoppedAt.Before(s.StartedAt))
+	assert.Equal(t, ahead, *s.LastUpdatedAt)
+}
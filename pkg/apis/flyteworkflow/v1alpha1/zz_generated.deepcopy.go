@@ -5,6 +5,8 @@
 package v1alpha1
 
 import (
+	json "encoding/json"
+
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,6 +23,23 @@ func (in *Alias) DeepCopy() *Alias {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttemptResourceUsage) DeepCopyInto(out *AttemptResourceUsage) {
+	*out = *in
+	out.ResourceUsage = in.ResourceUsage
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttemptResourceUsage.
+func (in *AttemptResourceUsage) DeepCopy() *AttemptResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(AttemptResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Binding.
 func (in *Binding) DeepCopy() *Binding {
 	if in == nil {
@@ -100,6 +119,22 @@ func (in *BranchNodeStatus) DeepCopy() *BranchNodeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompilationWarning) DeepCopyInto(out *CompilationWarning) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompilationWarning.
+func (in *CompilationWarning) DeepCopy() *CompilationWarning {
+	if in == nil {
+		return nil
+	}
+	out := new(CompilationWarning)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Connections.
 func (in *Connections) DeepCopy() *Connections {
 	if in == nil {
@@ -120,6 +155,27 @@ func (in *DeprecatedConnections) DeepCopy() *DeprecatedConnections {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataKeyReference) DeepCopyInto(out *DataKeyReference) {
+	*out = *in
+	if in.Ciphertext != nil {
+		in, out := &in.Ciphertext, &out.Ciphertext
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataKeyReference.
+func (in *DataKeyReference) DeepCopy() *DataKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DataKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicNodeStatus) DeepCopyInto(out *DynamicNodeStatus) {
 	*out = *in
@@ -162,6 +218,11 @@ func (in *ExecutionConfig) DeepCopyInto(out *ExecutionConfig) {
 		}
 	}
 	out.MaxParallelism = in.MaxParallelism
+	if in.ScratchVolume != nil {
+		in, out := &in.ScratchVolume, &out.ScratchVolume
+		*out = new(ScratchVolume)
+		**out = **in
+	}
 	return
 }
 
@@ -185,6 +246,22 @@ func (in *ExecutionError) DeepCopy() *ExecutionError {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FanOutBackpressure) DeepCopyInto(out *FanOutBackpressure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FanOutBackpressure.
+func (in *FanOutBackpressure) DeepCopy() *FanOutBackpressure {
+	if in == nil {
+		return nil
+	}
+	out := new(FanOutBackpressure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FlyteWorkflow) DeepCopyInto(out *FlyteWorkflow) {
 	*out = *in
@@ -219,6 +296,26 @@ func (in *FlyteWorkflow) DeepCopyInto(out *FlyteWorkflow) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.TaskReferences != nil {
+		in, out := &in.TaskReferences, &out.TaskReferences
+		*out = make(map[string]*TaskReference, len(*in))
+		for key, val := range *in {
+			var outVal *TaskReference
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(TaskReference)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ClosureReference != nil {
+		in, out := &in.ClosureReference, &out.ClosureReference
+		*out = new(WorkflowClosureReference)
+		**out = **in
+	}
 	if in.SubWorkflows != nil {
 		in, out := &in.SubWorkflows, &out.SubWorkflows
 		*out = make(map[string]*WorkflowSpec, len(*in))
@@ -244,14 +341,38 @@ func (in *FlyteWorkflow) DeepCopyInto(out *FlyteWorkflow) {
 		in, out := &in.AcceptedAt, &out.AcceptedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.ScheduledAt != nil {
+		in, out := &in.ScheduledAt, &out.ScheduledAt
+		*out = (*in).DeepCopy()
+	}
 
 	out.SecurityContext = in.SecurityContext
 	in.Status.DeepCopyInto(&out.Status)
 	in.RawOutputDataConfig.DeepCopyInto(&out.RawOutputDataConfig)
 	in.ExecutionConfig.DeepCopyInto(&out.ExecutionConfig)
+	if in.CompilationWarnings != nil {
+		in, out := &in.CompilationWarnings, &out.CompilationWarnings
+		*out = make([]CompilationWarning, len(*in))
+		copy(*out, *in)
+	}
 	if in.DataReferenceConstructor != nil {
 		out.DataReferenceConstructor = in.DataReferenceConstructor
 	}
+	if in.UnknownFields != nil {
+		in, out := &in.UnknownFields, &out.UnknownFields
+		*out = make(map[string]json.RawMessage, len(*in))
+		for key, val := range *in {
+			var outVal []byte
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(json.RawMessage, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	return
 }
 
@@ -316,6 +437,22 @@ func (in *Identifier) DeepCopy() *Identifier {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GraphSnapshotReference) DeepCopyInto(out *GraphSnapshotReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GraphSnapshotReference.
+func (in *GraphSnapshotReference) DeepCopy() *GraphSnapshotReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GraphSnapshotReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IfBlock) DeepCopyInto(out *IfBlock) {
 	*out = *in
@@ -338,6 +475,16 @@ func (in *IfBlock) DeepCopy() *IfBlock {
 	return out
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlinedOutputs.
+func (in *InlinedOutputs) DeepCopy() *InlinedOutputs {
+	if in == nil {
+		return nil
+	}
+	out := new(InlinedOutputs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Inputs.
 func (in *Inputs) DeepCopy() *Inputs {
 	if in == nil {
@@ -390,6 +537,88 @@ func (in *NodeMetadata) DeepCopy() *NodeMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeForce) DeepCopyInto(out *NodeForce) {
+	*out = *in
+	out.NodeForceRequest = in.NodeForceRequest
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeForce.
+func (in *NodeForce) DeepCopy() *NodeForce {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeForce)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeForceRequest) DeepCopyInto(out *NodeForceRequest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeForceRequest.
+func (in *NodeForceRequest) DeepCopy() *NodeForceRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeForceRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInputs) DeepCopyInto(out *NodeInputs) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInputs.
+func (in *NodeInputs) DeepCopy() *NodeInputs {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInputs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSkip) DeepCopyInto(out *NodeSkip) {
+	*out = *in
+	out.NodeSkipRequest = in.NodeSkipRequest
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSkip.
+func (in *NodeSkip) DeepCopy() *NodeSkip {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSkip)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSkipRequest) DeepCopyInto(out *NodeSkipRequest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSkipRequest.
+func (in *NodeSkipRequest) DeepCopy() *NodeSkipRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSkipRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSpec) DeepCopyInto(out *NodeSpec) {
 	*out = *in
@@ -477,6 +706,30 @@ func (in *NodeSpec) DeepCopyInto(out *NodeSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(BooleanExpression)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StartDelay != nil {
+		in, out := &in.StartDelay, &out.StartDelay
+		*out = new(StartDelay)
+		**out = **in
+	}
 	return
 }
 
@@ -561,6 +814,47 @@ func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 		in, out := &in.Error, &out.Error
 		*out = (*in).DeepCopy()
 	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = make([]AttemptResourceUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceEscalation != nil {
+		in, out := &in.ResourceEscalation, &out.ResourceEscalation
+		*out = new(ResourceEscalation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InlinedOutputs != nil {
+		in, out := &in.InlinedOutputs, &out.InlinedOutputs
+		*out = new(InlinedOutputs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreviousSubNodeInputs != nil {
+		in, out := &in.PreviousSubNodeInputs, &out.PreviousSubNodeInputs
+		*out = make(map[string]NodeInputs, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Forced != nil {
+		in, out := &in.Forced, &out.Forced
+		*out = new(NodeForce)
+		**out = **in
+	}
+	if in.FanOutBackpressure != nil {
+		in, out := &in.FanOutBackpressure, &out.FanOutBackpressure
+		*out = new(FanOutBackpressure)
+		**out = **in
+	}
+	if in.OperatorSkipped != nil {
+		in, out := &in.OperatorSkipped, &out.OperatorSkipped
+		*out = new(NodeSkip)
+		**out = **in
+	}
+	if in.ReadyAt != nil {
+		in, out := &in.ReadyAt, &out.ReadyAt
+		*out = (*in).DeepCopy()
+	}
 	if in.DataReferenceConstructor != nil {
 		out.DataReferenceConstructor = in.DataReferenceConstructor
 	}
@@ -597,6 +891,39 @@ func (in *RawOutputDataConfig) DeepCopy() *RawOutputDataConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceEscalation) DeepCopyInto(out *ResourceEscalation) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceEscalation.
+func (in *ResourceEscalation) DeepCopy() *ResourceEscalation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceEscalation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
+func (in *ResourceUsage) DeepCopy() *ResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetryStrategy) DeepCopyInto(out *RetryStrategy) {
 	*out = *in
@@ -618,6 +945,39 @@ func (in *RetryStrategy) DeepCopy() *RetryStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScratchVolume) DeepCopyInto(out *ScratchVolume) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScratchVolume.
+func (in *ScratchVolume) DeepCopy() *ScratchVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(ScratchVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartDelay) DeepCopyInto(out *StartDelay) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartDelay.
+func (in *StartDelay) DeepCopy() *StartDelay {
+	if in == nil {
+		return nil
+	}
+	out := new(StartDelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskExecutionIdentifier.
 func (in *TaskExecutionIdentifier) DeepCopy() *TaskExecutionIdentifier {
 	if in == nil {
@@ -659,6 +1019,59 @@ func (in *TaskSpec) DeepCopy() *TaskSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskReference) DeepCopyInto(out *TaskReference) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskReference.
+func (in *TaskReference) DeepCopy() *TaskReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowClosureReference) DeepCopyInto(out *WorkflowClosureReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowClosureReference.
+func (in *WorkflowClosureReference) DeepCopy() *WorkflowClosureReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowClosureReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowEventCheckpoint) DeepCopyInto(out *WorkflowEventCheckpoint) {
+	*out = *in
+	in.RecordedAt.DeepCopyInto(&out.RecordedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowEventCheckpoint.
+func (in *WorkflowEventCheckpoint) DeepCopy() *WorkflowEventCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowEventCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowExecutionIdentifier.
 func (in *WorkflowExecutionIdentifier) DeepCopy() *WorkflowExecutionIdentifier {
 	if in == nil {
@@ -819,6 +1232,21 @@ func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 		in, out := &in.Error, &out.Error
 		*out = (*in).DeepCopy()
 	}
+	if in.DataKey != nil {
+		in, out := &in.DataKey, &out.DataKey
+		*out = new(DataKeyReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventCheckpoint != nil {
+		in, out := &in.EventCheckpoint, &out.EventCheckpoint
+		*out = new(WorkflowEventCheckpoint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GraphSnapshot != nil {
+		in, out := &in.GraphSnapshot, &out.GraphSnapshot
+		*out = new(GraphSnapshotReference)
+		**out = **in
+	}
 	if in.DataReferenceConstructor != nil {
 		out.DataReferenceConstructor = in.DataReferenceConstructor
 	}
@@ -9,6 +9,14 @@ import (
 const (
 	StartNodeID = "start-node"
 	EndNodeID   = "end-node"
+	// Bindings to the outputs of the system node resolve to values that describe the execution at runtime.
+	SystemNodeID = "system-node"
+)
+
+const (
+	SystemVarKickoffTime   = "kickoff_time"
+	SystemVarScheduledTime = "scheduled_time"
+	SystemVarExecutionID   = "execution_id"
 )
 
 type EdgeDirection uint8
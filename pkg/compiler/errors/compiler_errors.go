@@ -84,6 +84,13 @@ const (
 
 	// Given value is not a legal Enum value (or not part of the defined set of enum values)
 	IllegalEnumValue ErrorCode = "IllegalEnumValue"
+
+	// A node can never run, either because no path leads to it or because the branch cases leading to it are never taken.
+	// Reported as a warning, it does not prevent the workflow from executing.
+	NodeNeverRuns ErrorCode = "NodeNeverRuns"
+
+	// A workflow output is bound to a node that can never run. Reported as a warning.
+	DanglingOutput ErrorCode = "DanglingOutput"
 )
 
 func NewBranchNodeNotSpecified(branchNodeID string) *CompileError {
@@ -255,6 +262,22 @@ func NewUnreachableNodesErr(nodeID, nodes string) *CompileError {
 	)
 }
 
+func NewNodeNeverRunsWarning(nodeID, reason string) *CompileError {
+	return newError(
+		NodeNeverRuns,
+		fmt.Sprintf("Node [%v] can never run, %v.", nodeID, reason),
+		nodeID,
+	)
+}
+
+func NewDanglingOutputWarning(nodeID, outputVar string) *CompileError {
+	return newError(
+		DanglingOutput,
+		fmt.Sprintf("Workflow output [%v] is bound to node [%v], which can never run.", outputVar, nodeID),
+		nodeID,
+	)
+}
+
 func NewUnrecognizedValueErr(nodeID, value string) *CompileError {
 	return newError(
 		UnrecognizedValue,
@@ -41,6 +41,16 @@ func (err CompileError) Code() ErrorCode {
 	return err.code
 }
 
+// Gets the id of the node the compile error occurred at.
+func (err CompileError) NodeID() string {
+	return err.nodeID
+}
+
+// Gets the description of the compile error.
+func (err CompileError) Description() string {
+	return err.description
+}
+
 // Gets a readable/formatted string explaining the compile error as well as at which node it occurred.
 func (err CompileError) Error() string {
 	source := ""
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
+)
+
+const (
+	// Annotations that record the launch plan and scheduled time a FlyteWorkflow was created for. They tell a retried
+	// creation of the same scheduled execution apart from a different execution whose name collides.
+	ScheduledLaunchPlanAnnotation = "flyte.org/scheduled-launch-plan"
+	ScheduledTimeAnnotation       = "flyte.org/scheduled-time"
+)
+
+// The number of names that are tried for a scheduled execution before giving up, should the names of different
+// scheduled executions collide.
+const maxScheduledExecutionNameAttempts = 5
+
+// FlyteWorkflowCreator creates and retrieves FlyteWorkflows in a namespace. It is satisfied by the typed FlyteWorkflow
+// client.
+type FlyteWorkflowCreator interface {
+	Create(ctx context.Context, flyteWorkflow *v1alpha1.FlyteWorkflow, opts v1.CreateOptions) (*v1alpha1.FlyteWorkflow, error)
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.FlyteWorkflow, error)
+}
+
+func scheduledLaunchPlanID(launchPlan *core.Identifier) string {
+	return fmt.Sprintf("%v:%v:%v", launchPlan.GetProject(), launchPlan.GetDomain(), launchPlan.GetName())
+}
+
+func scheduledTime(scheduledAt time.Time) string {
+	return scheduledAt.UTC().Format(time.RFC3339Nano)
+}
+
+// ScheduledExecutionName derives a deterministic execution name for the execution of a launch plan at a scheduled
+// time. The version of the launch plan is not part of the name, so that a schedule keeps producing the same names
+// across versions. Each attempt yields a different name, later attempts are only used when names collide.
+func ScheduledExecutionName(launchPlan *core.Identifier, scheduledAt time.Time, attempt int) string {
+	hasher := fnv.New64a()
+	// Using 64a an error can never happen
+	_, _ = hasher.Write([]byte(scheduledLaunchPlanID(launchPlan))) // #nosec
+	_, _ = hasher.Write([]byte(scheduledTime(scheduledAt)))        // #nosec
+	if attempt > 0 {
+		_, _ = hasher.Write([]byte(fmt.Sprintf("%d", attempt))) // #nosec
+	}
+
+	// Names start with a letter so that they are valid execution names as well as K8s object names.
+	return "s" + utils.Base32Encoder.EncodeToString(hasher.Sum(nil))
+}
+
+// SetScheduledExecution records the launch plan and the scheduled time a FlyteWorkflow is created for.
+func SetScheduledExecution(wf *v1alpha1.FlyteWorkflow, launchPlan *core.Identifier, scheduledAt time.Time) {
+	if wf.Annotations == nil {
+		wf.Annotations = map[string]string{}
+	}
+
+	wf.Annotations[ScheduledLaunchPlanAnnotation] = scheduledLaunchPlanID(launchPlan)
+	wf.Annotations[ScheduledTimeAnnotation] = scheduledTime(scheduledAt)
+	t := v1.NewTime(scheduledAt)
+	wf.ScheduledAt = &t
+}
+
+// IsScheduledExecution returns true if the FlyteWorkflow was created for the execution of the launch plan at the
+// scheduled time.
+func IsScheduledExecution(wf *v1alpha1.FlyteWorkflow, launchPlan *core.Identifier, scheduledAt time.Time) bool {
+	return wf.Annotations[ScheduledLaunchPlanAnnotation] == scheduledLaunchPlanID(launchPlan) &&
+		wf.Annotations[ScheduledTimeAnnotation] == scheduledTime(scheduledAt)
+}
+
+func withScheduledExecutionName(wf *v1alpha1.FlyteWorkflow, name string) *v1alpha1.FlyteWorkflow {
+	named := wf.DeepCopy()
+	named.Name = name
+	named.GenerateName = ""
+	if named.Labels == nil {
+		named.Labels = map[string]string{}
+	}
+
+	named.Labels[ExecutionIDLabel] = name
+	if named.ExecutionID.WorkflowExecutionIdentifier != nil {
+		named.ExecutionID.Name = name
+	}
+
+	return named
+}
+
+// CreateScheduledFlyteWorkflow creates the FlyteWorkflow for the execution of a launch plan at a scheduled time under a
+// deterministic name. Creating the same scheduled execution again returns the existing FlyteWorkflow, which makes it
+// safe for schedulers to retry. If the name is taken by a different execution, the next name is tried.
+func CreateScheduledFlyteWorkflow(ctx context.Context, creator FlyteWorkflowCreator, wf *v1alpha1.FlyteWorkflow,
+	launchPlan *core.Identifier, scheduledAt time.Time) (*v1alpha1.FlyteWorkflow, error) {
+
+	for attempt := 0; attempt < maxScheduledExecutionNameAttempts; attempt++ {
+		named := withScheduledExecutionName(wf, ScheduledExecutionName(launchPlan, scheduledAt, attempt))
+		SetScheduledExecution(named, launchPlan, scheduledAt)
+
+		created, err := creator.Create(ctx, named, v1.CreateOptions{})
+		if err == nil {
+			return created, nil
+		} else if !k8serrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+
+		existing, err := creator.Get(ctx, named.Name, v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		if IsScheduledExecution(existing, launchPlan, scheduledAt) {
+			return existing, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find an unused name for the execution of [%v] scheduled at [%v] after [%d] attempts",
+		scheduledLaunchPlanID(launchPlan), scheduledTime(scheduledAt), maxScheduledExecutionNameAttempts)
+}
@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/fake"
+)
+
+func TestScheduledExecutionName(t *testing.T) {
+	lp := &core.Identifier{Project: "p", Domain: "d", Name: "daily", Version: "v1"}
+	scheduledAt := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	name := ScheduledExecutionName(lp, scheduledAt, 0)
+	assert.Len(t, name, 14)
+	assert.Equal(t, name, ScheduledExecutionName(&core.Identifier{Project: "p", Domain: "d", Name: "daily", Version: "v2"},
+		scheduledAt.In(time.FixedZone("X", 3600)), 0))
+	assert.NotEqual(t, name, ScheduledExecutionName(lp, scheduledAt.Add(time.Hour), 0))
+	assert.NotEqual(t, name, ScheduledExecutionName(&core.Identifier{Project: "p", Domain: "d", Name: "hourly"}, scheduledAt, 0))
+	assert.NotEqual(t, name, ScheduledExecutionName(lp, scheduledAt, 1))
+}
+
+func TestCreateScheduledFlyteWorkflow(t *testing.T) {
+	ctx := context.TODO()
+	lp := &core.Identifier{Project: "p", Domain: "d", Name: "daily"}
+	scheduledAt := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	newWorkflow := func() *v1alpha1.FlyteWorkflow {
+		return &v1alpha1.FlyteWorkflow{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace:    "ns",
+				GenerateName: "p-d-daily-",
+			},
+		}
+	}
+
+	t.Run("idempotent", func(t *testing.T) {
+		creator := fake.NewSimpleClientset().FlyteworkflowV1alpha1().FlyteWorkflows("ns")
+
+		created, err := CreateScheduledFlyteWorkflow(ctx, creator, newWorkflow(), lp, scheduledAt)
+		assert.NoError(t, err)
+		assert.Equal(t, ScheduledExecutionName(lp, scheduledAt, 0), created.Name)
+		assert.Empty(t, created.GenerateName)
+		assert.Equal(t, created.Name, created.Labels[ExecutionIDLabel])
+		assert.True(t, IsScheduledExecution(created, lp, scheduledAt))
+		assert.True(t, scheduledAt.Equal(created.ScheduledAt.Time))
+
+		retried, err := CreateScheduledFlyteWorkflow(ctx, creator, newWorkflow(), lp, scheduledAt)
+		assert.NoError(t, err)
+		assert.Equal(t, created.Name, retried.Name)
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		creator := fake.NewSimpleClientset().FlyteworkflowV1alpha1().FlyteWorkflows("ns")
+
+		// A different execution that took the first name.
+		other := newWorkflow()
+		other.Name = ScheduledExecutionName(lp, scheduledAt, 0)
+		_, err := creator.Create(ctx, other, v1.CreateOptions{})
+		assert.NoError(t, err)
+
+		created, err := CreateScheduledFlyteWorkflow(ctx, creator, newWorkflow(), lp, scheduledAt)
+		assert.NoError(t, err)
+		assert.Equal(t, ScheduledExecutionName(lp, scheduledAt, 1), created.Name)
+	})
+}
@@ -1,8 +1,6 @@
 package k8s
 
 import (
-	"math"
-
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
@@ -85,7 +83,3 @@ func toBindingValueArray(bindings []*core.Binding) []*v1alpha1.Binding {
 
 	return res
 }
-
-func minInt(i, j int) int {
-	return int(math.Min(float64(i), float64(j)))
-}
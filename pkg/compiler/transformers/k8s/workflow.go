@@ -9,6 +9,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/compiler/common"
 	"github.com/flyteorg/flytepropeller/pkg/compiler/errors"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/validators"
 	"github.com/flyteorg/flytepropeller/pkg/utils"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -137,8 +138,8 @@ func generateName(wfID *core.Identifier, execID *core.WorkflowExecutionIdentifie
 			wfID.Name,
 		)
 
-		// TODO: this is a hack until we figure out how to restrict generated names. K8s has a limitation of 63 chars
-		wid = wid[:minInt(32, len(wid))]
+		// K8s limits names and labels to 63 chars, generated names leave room for the random suffix.
+		wid = utils.TruncateWithHash(wid, 32)
 		return "", fmt.Sprintf("%v-", wid), wid, nil
 	} else {
 		return "", "", "", fmt.Errorf("expected param not set. wfID or execID must be non-nil values")
@@ -155,22 +156,8 @@ func BuildFlyteWorkflow(wfClosure *core.CompiledWorkflowClosure, inputs *core.Li
 		return nil, errs
 	}
 
-	primarySpec, err := buildFlyteWorkflowSpec(wfClosure.Primary, wfClosure.Tasks, errs.NewScope())
-	if err != nil {
-		errs.Collect(errors.NewWorkflowBuildError(err))
-		return nil, errs
-	}
-	subwfs := make(map[v1alpha1.WorkflowID]*v1alpha1.WorkflowSpec, len(wfClosure.SubWorkflows))
-	for _, subWf := range wfClosure.SubWorkflows {
-		spec, err := buildFlyteWorkflowSpec(subWf, wfClosure.Tasks, errs.NewScope())
-		if err != nil {
-			errs.Collect(errors.NewWorkflowBuildError(err))
-		} else {
-			subwfs[subWf.Template.Id.String()] = spec
-		}
-	}
-
-	if errs.HasErrors() {
+	primarySpec, subwfs, ok := buildWorkflowSpecs(wfClosure, errs)
+	if !ok {
 		return nil, errs
 	}
 
@@ -200,13 +187,16 @@ func BuildFlyteWorkflow(wfClosure *core.CompiledWorkflowClosure, inputs *core.Li
 			Namespace: namespace,
 			Labels:    map[string]string{},
 		},
-		Inputs:       &v1alpha1.Inputs{LiteralMap: inputs},
-		WorkflowSpec: primarySpec,
-		SubWorkflows: subwfs,
-		Tasks:        buildTasks(tasks, errs.NewScope()),
-		NodeDefaults: v1alpha1.NodeDefaults{Interruptible: interruptible},
+		Inputs:         &v1alpha1.Inputs{LiteralMap: inputs},
+		WorkflowSpec:   primarySpec,
+		SubWorkflows:   subwfs,
+		Tasks:          buildTasks(tasks, errs.NewScope()),
+		NodeDefaults:   v1alpha1.NodeDefaults{Interruptible: interruptible},
+		SpecVersion:    v1alpha1.LatestSpecVersion,
+		MinSpecVersion: v1alpha1.CompatibleSpecVersion,
 	}
 
+	var err error
 	obj.ObjectMeta.Name, obj.ObjectMeta.GenerateName, obj.ObjectMeta.Labels[ExecutionIDLabel], err =
 		generateName(wf.GetId(), executionID)
 
@@ -214,6 +204,12 @@ func BuildFlyteWorkflow(wfClosure *core.CompiledWorkflowClosure, inputs *core.Li
 		errs.Collect(errors.NewWorkflowBuildError(err))
 	}
 	obj.ObjectMeta.Labels[WorkflowNameLabel] = utils.SanitizeLabelValue(WorkflowNameFromID(primarySpec.ID))
+	obj.CompilationWarnings = buildCompilationWarnings(wfClosure)
+	// Workflows whose spec cannot be serialized fail when they are written, they are left without a checksum here.
+	if checksum, err := obj.ComputeSpecChecksum(); err == nil {
+		obj.SpecChecksum = checksum
+		obj.SpecChecksumSerializer = v1alpha1.SpecChecksumSerializer
+	}
 
 	if obj.Nodes == nil || obj.Connections.Downstream == nil {
 		// If we come here, we'd better have an error generated earlier. Otherwise, add one to make sure build fails.
@@ -234,6 +230,77 @@ func BuildFlyteWorkflow(wfClosure *core.CompiledWorkflowClosure, inputs *core.Li
 	return obj, nil
 }
 
+// Builds the specs of the primary workflow and the subworkflows of the closure. Errors are collected in errs.
+func buildWorkflowSpecs(wfClosure *core.CompiledWorkflowClosure, errs errors.CompileErrors) (
+	primarySpec *v1alpha1.WorkflowSpec, subwfs map[v1alpha1.WorkflowID]*v1alpha1.WorkflowSpec, ok bool) {
+
+	primarySpec, err := buildFlyteWorkflowSpec(wfClosure.Primary, wfClosure.Tasks, errs.NewScope())
+	if err != nil {
+		errs.Collect(errors.NewWorkflowBuildError(err))
+		return nil, nil, false
+	}
+
+	subwfs = make(map[v1alpha1.WorkflowID]*v1alpha1.WorkflowSpec, len(wfClosure.SubWorkflows))
+	for _, subWf := range wfClosure.SubWorkflows {
+		spec, err := buildFlyteWorkflowSpec(subWf, wfClosure.Tasks, errs.NewScope())
+		if err != nil {
+			errs.Collect(errors.NewWorkflowBuildError(err))
+		} else {
+			subwfs[subWf.Template.Id.String()] = spec
+		}
+	}
+
+	return primarySpec, subwfs, !errs.HasErrors()
+}
+
+// Fills in the spec, subworkflows and tasks of the workflow from its compiled closure, e.g. one that admin offloaded to
+// the blob store instead of embedding it in the CRD. The rest of the workflow is left unchanged. Returned error, if not
+// nil, is of type errors.CompilerErrors.
+func HydrateFlyteWorkflow(wfClosure *core.CompiledWorkflowClosure, w *v1alpha1.FlyteWorkflow) error {
+	errs := errors.NewCompileErrors()
+	if wfClosure == nil || wfClosure.Primary == nil {
+		errs.Collect(errors.NewValueRequiredErr("root", "wfClosure"))
+		return errs
+	}
+
+	primarySpec, subwfs, ok := buildWorkflowSpecs(wfClosure, errs)
+	if !ok {
+		return errs
+	}
+
+	tasks := buildTasks(wfClosure.Tasks, errs.NewScope())
+	if errs.HasErrors() {
+		return errs
+	}
+
+	w.WorkflowSpec = primarySpec
+	w.SubWorkflows = subwfs
+	w.Tasks = tasks
+	return nil
+}
+
+// Collects the warnings for nodes that can never run in the primary workflow and its subworkflows.
+func buildCompilationWarnings(wfClosure *core.CompiledWorkflowClosure) []v1alpha1.CompilationWarning {
+	var res []v1alpha1.CompilationWarning
+	collect := func(workflowID v1alpha1.WorkflowID, wf *core.CompiledWorkflow) {
+		for _, w := range validators.ValidateNodesCanRun(wf) {
+			res = append(res, v1alpha1.CompilationWarning{
+				WorkflowID: workflowID,
+				NodeID:     w.NodeID(),
+				Code:       string(w.Code()),
+				Message:    w.Description(),
+			})
+		}
+	}
+
+	collect(WorkflowIDAsString(wfClosure.Primary.Template.Id), wfClosure.Primary)
+	for _, subWf := range wfClosure.SubWorkflows {
+		collect(subWf.Template.Id.String(), subWf)
+	}
+
+	return res
+}
+
 func toMapOfLists(connections map[string]*core.ConnectionSet_IdList) map[string][]string {
 	res := make(map[string][]string, len(connections))
 	for key, val := range connections {
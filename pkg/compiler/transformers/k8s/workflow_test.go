@@ -221,7 +221,27 @@ func TestGenerateName(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Empty(t, name)
-		assert.Equal(t, "myproject-development-workflowso-", generateName)
+		assert.Equal(t, "myproject-development-wo-wqsejwq-", generateName)
+	})
+
+	t.Run("wfIDs too long differ", func(t *testing.T) {
+		_, generateName1, label1, err := generateName(&core.Identifier{
+			Name:    "workflowsomethingsomethingsomething",
+			Project: "myproject",
+			Domain:  "development",
+		}, nil)
+		assert.NoError(t, err)
+
+		_, generateName2, label2, err := generateName(&core.Identifier{
+			Name:    "workflowsomethingsomethingelse",
+			Project: "myproject",
+			Domain:  "development",
+		}, nil)
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, generateName1, generateName2)
+		assert.NotEqual(t, label1, label2)
+		assert.Len(t, label1, 32)
 	})
 
 	t.Run("execID full", func(t *testing.T) {
@@ -276,6 +296,7 @@ func TestBuildFlyteWorkflow_withBranch(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, wf)
 	assert.Len(t, wf.Nodes, 8)
+	assert.Empty(t, wf.CompilationWarnings)
 
 	s := sets.NewString("start-node", "end-node", "n0", "n0-n0", "n0-n1", "n0-n2", "n0-n0-n0", "n0-n0-n0-n0", "n0-n0-n0-n1")
 
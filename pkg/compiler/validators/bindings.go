@@ -55,6 +55,10 @@ func validateBinding(w c.WorkflowBuilder, nodeID c.NodeID, nodeParam string, bin
 
 		errs.Collect(errors.NewMismatchingBindingsErr(nodeID, nodeParam, expectedType.String(), binding.GetMap().String()))
 	case *flyte.BindingData_Promise:
+		if binding.GetPromise().GetNodeId() == c.SystemNodeID {
+			return validateSystemBinding(nodeID, nodeParam, binding.GetPromise(), expectedType, errs.NewScope())
+		}
+
 		if upNode, found := validateNodeID(w, binding.GetPromise().NodeId, errs.NewScope()); found {
 			v, err := typing.ParseVarName(binding.GetPromise().GetVar())
 			if err != nil {
@@ -114,6 +118,32 @@ func validateBinding(w c.WorkflowBuilder, nodeID c.NodeID, nodeParam string, bin
 	return nil, nil, !errs.HasErrors()
 }
 
+// The types of the variables that the system node provides.
+var systemVariableTypes = map[string]*flyte.LiteralType{
+	c.SystemVarKickoffTime:   {Type: &flyte.LiteralType_Simple{Simple: flyte.SimpleType_DATETIME}},
+	c.SystemVarScheduledTime: {Type: &flyte.LiteralType_Simple{Simple: flyte.SimpleType_DATETIME}},
+	c.SystemVarExecutionID:   {Type: &flyte.LiteralType_Simple{Simple: flyte.SimpleType_STRING}},
+}
+
+// Validates a binding to a variable of the system node. Such bindings do not introduce an upstream node.
+func validateSystemBinding(nodeID c.NodeID, nodeParam string, promise *flyte.OutputReference,
+	expectedType *flyte.LiteralType, errs errors.CompileErrors) (
+	resolvedType *flyte.LiteralType, upstreamNodes []c.NodeID, ok bool) {
+
+	sourceType, found := systemVariableTypes[promise.GetVar()]
+	if !found {
+		errs.Collect(errors.NewVariableNameNotFoundErr(nodeID, c.SystemNodeID, promise.GetVar()))
+		return nil, nil, !errs.HasErrors()
+	}
+
+	if !AreTypesCastable(sourceType, expectedType) {
+		errs.Collect(errors.NewMismatchingTypesErr(nodeID, nodeParam, sourceType.String(), expectedType.String()))
+		return nil, nil, !errs.HasErrors()
+	}
+
+	return sourceType, []c.NodeID{}, true
+}
+
 func ValidateBindings(w c.WorkflowBuilder, node c.Node, bindings []*flyte.Binding, params *flyte.VariableMap,
 	validateParamTypes bool, edgeDirection c.EdgeDirection, errs errors.CompileErrors) (resolved *flyte.VariableMap, ok bool) {
 
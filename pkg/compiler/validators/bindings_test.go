@@ -264,4 +264,63 @@ func TestValidateBindings(t *testing.T) {
 			assert.NoError(t, compileErrors)
 		}
 	})
+
+	t.Run("System promises", func(t *testing.T) {
+		n := &mocks.NodeBuilder{}
+		n.OnGetId().Return("node1")
+
+		wf := &mocks.WorkflowBuilder{}
+
+		newBindings := func(varName string) []*core.Binding {
+			return []*core.Binding{
+				{
+					Var: "x",
+					Binding: &core.BindingData{
+						Value: &core.BindingData_Promise{
+							Promise: &core.OutputReference{
+								Var:    varName,
+								NodeId: c.SystemNodeID,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		newVars := func(t core.SimpleType) *core.VariableMap {
+			return &core.VariableMap{
+				Variables: map[string]*core.Variable{
+					"x": {
+						Type: &core.LiteralType{Type: &core.LiteralType_Simple{Simple: t}},
+					},
+				},
+			}
+		}
+
+		t.Run("kickoff time", func(t *testing.T) {
+			compileErrors := compilerErrors.NewCompileErrors()
+			resolved, ok := ValidateBindings(wf, n, newBindings(c.SystemVarKickoffTime), newVars(core.SimpleType_DATETIME), true,
+				c.EdgeDirectionBidirectional, compileErrors)
+			assert.True(t, ok)
+			assert.False(t, compileErrors.HasErrors())
+			assert.Equal(t, core.SimpleType_DATETIME, resolved.Variables["x"].GetType().GetSimple())
+			wf.AssertNotCalled(t, "AddExecutionEdge", mock.Anything, mock.Anything)
+		})
+
+		t.Run("mismatching type", func(t *testing.T) {
+			compileErrors := compilerErrors.NewCompileErrors()
+			_, ok := ValidateBindings(wf, n, newBindings(c.SystemVarExecutionID), newVars(core.SimpleType_INTEGER), true,
+				c.EdgeDirectionBidirectional, compileErrors)
+			assert.False(t, ok)
+			assert.True(t, compileErrors.HasErrors())
+		})
+
+		t.Run("unknown variable", func(t *testing.T) {
+			compileErrors := compilerErrors.NewCompileErrors()
+			_, ok := ValidateBindings(wf, n, newBindings("start_time"), newVars(core.SimpleType_DATETIME), true,
+				c.EdgeDirectionBidirectional, compileErrors)
+			assert.False(t, ok)
+			assert.True(t, compileErrors.HasErrors())
+		})
+	})
 }
@@ -0,0 +1,239 @@
+package validators
+
+import (
+	"strings"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/golang/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	c "github.com/flyteorg/flytepropeller/pkg/compiler/common"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/errors"
+)
+
+// Compares two primitives of the same type. ok is false if the primitives cannot be ordered.
+func comparePrimitives(l, r *core.Primitive) (cmp int, ok bool) {
+	order := func(less, greater bool) int {
+		if less {
+			return -1
+		} else if greater {
+			return 1
+		}
+
+		return 0
+	}
+
+	switch lv := l.GetValue().(type) {
+	case *core.Primitive_Integer:
+		if rv, isInt := r.GetValue().(*core.Primitive_Integer); isInt {
+			return order(lv.Integer < rv.Integer, lv.Integer > rv.Integer), true
+		}
+	case *core.Primitive_FloatValue:
+		if rv, isFloat := r.GetValue().(*core.Primitive_FloatValue); isFloat {
+			return order(lv.FloatValue < rv.FloatValue, lv.FloatValue > rv.FloatValue), true
+		}
+	case *core.Primitive_StringValue:
+		if rv, isString := r.GetValue().(*core.Primitive_StringValue); isString {
+			return strings.Compare(lv.StringValue, rv.StringValue), true
+		}
+	}
+
+	return 0, false
+}
+
+// Evaluates a comparison of two constants. known is false if the comparison depends on the node inputs or cannot be
+// decided at compile time.
+func evaluateConstantComparison(expr *core.ComparisonExpression) (value, known bool) {
+	l, r := expr.GetLeftValue().GetPrimitive(), expr.GetRightValue().GetPrimitive()
+	if l == nil || r == nil {
+		return false, false
+	}
+
+	switch expr.GetOperator() {
+	case core.ComparisonExpression_EQ:
+		return proto.Equal(l, r), true
+	case core.ComparisonExpression_NEQ:
+		return !proto.Equal(l, r), true
+	}
+
+	cmp, ok := comparePrimitives(l, r)
+	if !ok {
+		return false, false
+	}
+
+	switch expr.GetOperator() {
+	case core.ComparisonExpression_GT:
+		return cmp > 0, true
+	case core.ComparisonExpression_GTE:
+		return cmp >= 0, true
+	case core.ComparisonExpression_LT:
+		return cmp < 0, true
+	case core.ComparisonExpression_LTE:
+		return cmp <= 0, true
+	}
+
+	return false, false
+}
+
+// Evaluates the parts of a boolean expression that only compare constants. known is false if the value of the
+// expression depends on the node inputs.
+func evaluateConstantExpression(expr *core.BooleanExpression) (value, known bool) {
+	if expr.GetComparison() != nil {
+		return evaluateConstantComparison(expr.GetComparison())
+	}
+
+	conjunction := expr.GetConjunction()
+	if conjunction == nil {
+		return false, false
+	}
+
+	l, lKnown := evaluateConstantExpression(conjunction.GetLeftExpression())
+	r, rKnown := evaluateConstantExpression(conjunction.GetRightExpression())
+	if conjunction.GetOperator() == core.ConjunctionExpression_OR {
+		if (lKnown && l) || (rKnown && r) {
+			return true, true
+		}
+
+		return false, lKnown && rKnown
+	}
+
+	if (lKnown && !l) || (rKnown && !r) {
+		return false, true
+	}
+
+	return true, lKnown && rKnown
+}
+
+// Collects warnings for the cases of the branch node that are never taken, and of the branch nodes nested in the cases
+// that may be taken. Returns false if none of the cases nor the else node can ever run.
+func validateBranchCasesCanRun(node *core.Node, warnings *[]*errors.CompileError) (canRun bool) {
+	ifElse := node.GetBranchNode().GetIfElse()
+	if ifElse == nil {
+		return true
+	}
+
+	cases := append([]*core.IfBlock{ifElse.GetCase()}, ifElse.GetOther()...)
+	alwaysTaken := false
+	for _, ifBlock := range cases {
+		thenNode := ifBlock.GetThenNode()
+		if thenNode == nil {
+			continue
+		}
+
+		if alwaysTaken {
+			*warnings = append(*warnings, errors.NewNodeNeverRunsWarning(thenNode.GetId(),
+				"an earlier case of its branch is always taken"))
+			continue
+		}
+
+		value, known := evaluateConstantExpression(ifBlock.GetCondition())
+		if known && !value {
+			*warnings = append(*warnings, errors.NewNodeNeverRunsWarning(thenNode.GetId(),
+				"the condition of its branch case is always false"))
+			continue
+		}
+
+		alwaysTaken = known
+		canRun = validateNodeCanRun(thenNode, warnings) || canRun
+	}
+
+	if elseNode := ifElse.GetElseNode(); elseNode != nil {
+		if alwaysTaken {
+			*warnings = append(*warnings, errors.NewNodeNeverRunsWarning(elseNode.GetId(),
+				"a case of its branch is always taken"))
+		} else {
+			canRun = validateNodeCanRun(elseNode, warnings) || canRun
+		}
+	}
+
+	return canRun
+}
+
+// Returns false if the node can never run to completion, which is only known for branch nodes that never take a case.
+func validateNodeCanRun(node *core.Node, warnings *[]*errors.CompileError) bool {
+	if node.GetBranchNode() == nil {
+		return true
+	}
+
+	return validateBranchCasesCanRun(node, warnings)
+}
+
+// Collects the ids of the nodes the binding reads outputs from.
+func collectPromiseNodeIDs(binding *core.BindingData, nodeIDs sets.String) {
+	switch b := binding.GetValue().(type) {
+	case *core.BindingData_Promise:
+		nodeIDs.Insert(b.Promise.GetNodeId())
+	case *core.BindingData_Collection:
+		for _, item := range b.Collection.GetBindings() {
+			collectPromiseNodeIDs(item, nodeIDs)
+		}
+	case *core.BindingData_Map:
+		for _, item := range b.Map.GetBindings() {
+			collectPromiseNodeIDs(item, nodeIDs)
+		}
+	}
+}
+
+// ValidateNodesCanRun returns warnings for the nodes of a compiled workflow that can never run and for the workflow
+// outputs bound to them. A node never runs if there is no path to it from the start node, if it is the case of a branch
+// that is never taken, or if it depends on a node that never runs. Branch conditions are only evaluated when they
+// compare constants. None of these prevent the workflow from executing, so they are not reported as compile errors.
+func ValidateNodesCanRun(wf *core.CompiledWorkflow) []*errors.CompileError {
+	warnings := make([]*errors.CompileError, 0)
+	downstream := wf.GetConnections().GetDownstream()
+
+	reachable := sets.NewString(c.StartNodeID)
+	queue := []string{c.StartNodeID}
+	for len(queue) > 0 {
+		nodeID := queue[0]
+		queue = queue[1:]
+		for _, next := range downstream[nodeID].GetIds() {
+			if !reachable.Has(next) {
+				reachable.Insert(next)
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	neverRuns := sets.NewString()
+	for _, node := range wf.GetTemplate().GetNodes() {
+		if !reachable.Has(node.GetId()) {
+			neverRuns.Insert(node.GetId())
+			warnings = append(warnings, errors.NewNodeNeverRunsWarning(node.GetId(),
+				"there is no path to it from the start node"))
+		} else if !validateNodeCanRun(node, &warnings) {
+			neverRuns.Insert(node.GetId())
+			warnings = append(warnings, errors.NewNodeNeverRunsWarning(node.GetId(),
+				"none of its branch cases is ever taken"))
+		}
+	}
+
+	// Nodes that depend on a node that never runs are never run either.
+	queue = neverRuns.List()
+	for len(queue) > 0 {
+		nodeID := queue[0]
+		queue = queue[1:]
+		for _, next := range sets.NewString(downstream[nodeID].GetIds()...).List() {
+			if next == c.EndNodeID || neverRuns.Has(next) {
+				continue
+			}
+
+			neverRuns.Insert(next)
+			queue = append(queue, next)
+			warnings = append(warnings, errors.NewNodeNeverRunsWarning(next,
+				"it depends on node ["+nodeID+"], which never runs"))
+		}
+	}
+
+	for _, output := range wf.GetTemplate().GetOutputs() {
+		nodeIDs := sets.NewString()
+		collectPromiseNodeIDs(output.GetBinding(), nodeIDs)
+		for _, nodeID := range nodeIDs.List() {
+			if neverRuns.Has(nodeID) {
+				warnings = append(warnings, errors.NewDanglingOutputWarning(nodeID, output.GetVar()))
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,150 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/stretchr/testify/assert"
+
+	c "github.com/flyteorg/flytepropeller/pkg/compiler/common"
+	compilerErrors "github.com/flyteorg/flytepropeller/pkg/compiler/errors"
+)
+
+func createIntegerOperand(val int64) *core.Operand {
+	return &core.Operand{Val: &core.Operand_Primitive{Primitive: coreutils.MustMakePrimitive(val)}}
+}
+
+func createComparison(left, right *core.Operand, op core.ComparisonExpression_Operator) *core.BooleanExpression {
+	return &core.BooleanExpression{Expr: &core.BooleanExpression_Comparison{Comparison: &core.ComparisonExpression{
+		LeftValue: left, RightValue: right, Operator: op,
+	}}}
+}
+
+func createConjunction(left, right *core.BooleanExpression, op core.ConjunctionExpression_LogicalOperator) *core.BooleanExpression {
+	return &core.BooleanExpression{Expr: &core.BooleanExpression_Conjunction{Conjunction: &core.ConjunctionExpression{
+		LeftExpression: left, RightExpression: right, Operator: op,
+	}}}
+}
+
+func TestEvaluateConstantExpression(t *testing.T) {
+	varOperand := &core.Operand{Val: &core.Operand_Var{Var: "x"}}
+	alwaysTrue := createComparison(createIntegerOperand(1), createIntegerOperand(2), core.ComparisonExpression_LT)
+	alwaysFalse := createComparison(createIntegerOperand(1), createIntegerOperand(2), core.ComparisonExpression_GTE)
+	unknown := createComparison(varOperand, createIntegerOperand(2), core.ComparisonExpression_EQ)
+
+	tests := []struct {
+		name  string
+		expr  *core.BooleanExpression
+		value bool
+		known bool
+	}{
+		{"true comparison", alwaysTrue, true, true},
+		{"false comparison", alwaysFalse, false, true},
+		{"variable", unknown, false, false},
+		{"equal booleans", createComparison(createBooleanOperand(true), createBooleanOperand(true), core.ComparisonExpression_EQ), true, true},
+		{"ordered booleans", createComparison(createBooleanOperand(true), createBooleanOperand(false), core.ComparisonExpression_GT), false, false},
+		{"and with false", createConjunction(unknown, alwaysFalse, core.ConjunctionExpression_AND), false, true},
+		{"and with true", createConjunction(unknown, alwaysTrue, core.ConjunctionExpression_AND), true, false},
+		{"or with true", createConjunction(unknown, alwaysTrue, core.ConjunctionExpression_OR), true, true},
+		{"or with false", createConjunction(alwaysFalse, alwaysFalse, core.ConjunctionExpression_OR), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, known := evaluateConstantExpression(tt.expr)
+			assert.Equal(t, tt.known, known)
+			if known {
+				assert.Equal(t, tt.value, value)
+			}
+		})
+	}
+}
+
+func TestValidateNodesCanRun(t *testing.T) {
+	alwaysFalse := createComparison(createIntegerOperand(1), createIntegerOperand(2), core.ComparisonExpression_GT)
+	alwaysTrue := createComparison(createIntegerOperand(1), createIntegerOperand(2), core.ComparisonExpression_LT)
+	taskNode := func(id string) *core.Node {
+		return &core.Node{Id: id, Target: &core.Node_TaskNode{TaskNode: &core.TaskNode{}}}
+	}
+
+	branchNode := func(id string, cases []*core.IfBlock, elseNode *core.Node) *core.Node {
+		ifElse := &core.IfElseBlock{Case: cases[0], Other: cases[1:]}
+		if elseNode != nil {
+			ifElse.Default = &core.IfElseBlock_ElseNode{ElseNode: elseNode}
+		} else {
+			ifElse.Default = &core.IfElseBlock_Error{Error: &core.Error{Message: "no case"}}
+		}
+
+		return &core.Node{Id: id, Target: &core.Node_BranchNode{BranchNode: &core.BranchNode{IfElse: ifElse}}}
+	}
+
+	promise := func(nodeID string) *core.BindingData {
+		return &core.BindingData{Value: &core.BindingData_Promise{Promise: &core.OutputReference{NodeId: nodeID, Var: "o"}}}
+	}
+
+	idList := func(ids ...string) *core.ConnectionSet_IdList {
+		return &core.ConnectionSet_IdList{Ids: ids}
+	}
+
+	wf := &core.CompiledWorkflow{
+		Template: &core.WorkflowTemplate{
+			Nodes: []*core.Node{
+				taskNode("n0"),
+				branchNode("n1", []*core.IfBlock{
+					{Condition: alwaysFalse, ThenNode: taskNode("n1-a")},
+					{Condition: alwaysTrue, ThenNode: taskNode("n1-b")},
+					{Condition: alwaysTrue, ThenNode: taskNode("n1-c")},
+				}, taskNode("n1-d")),
+				branchNode("n2", []*core.IfBlock{
+					{Condition: alwaysFalse, ThenNode: taskNode("n2-a")},
+				}, nil),
+				taskNode("n3"),
+				taskNode("n4"),
+			},
+			Outputs: []*core.Binding{
+				{Var: "o1", Binding: promise("n1")},
+				{Var: "o2", Binding: &core.BindingData{Value: &core.BindingData_Collection{Collection: &core.BindingDataCollection{
+					Bindings: []*core.BindingData{promise("n0"), promise("n3")},
+				}}}},
+			},
+		},
+		Connections: &core.ConnectionSet{Downstream: map[string]*core.ConnectionSet_IdList{
+			c.StartNodeID: idList("n0", "n1", "n2"),
+			"n0":          idList(c.EndNodeID),
+			"n1":          idList(c.EndNodeID),
+			"n2":          idList("n3"),
+			"n3":          idList(c.EndNodeID),
+			"n4":          idList(c.EndNodeID),
+		}},
+	}
+
+	warnings := ValidateNodesCanRun(wf)
+	found := make(map[string]compilerErrors.ErrorCode, len(warnings))
+	for _, w := range warnings {
+		found[w.NodeID()] = w.Code()
+	}
+
+	assert.Equal(t, map[string]compilerErrors.ErrorCode{
+		"n1-a": compilerErrors.NodeNeverRuns,
+		"n1-c": compilerErrors.NodeNeverRuns,
+		"n1-d": compilerErrors.NodeNeverRuns,
+		"n2-a": compilerErrors.NodeNeverRuns,
+		"n2":   compilerErrors.NodeNeverRuns,
+		"n4":   compilerErrors.NodeNeverRuns,
+		"n3":   compilerErrors.DanglingOutput,
+	}, found)
+
+	// n3 is reported both as a node that never runs and as the node the output is bound to.
+	assert.Len(t, warnings, 8)
+	assert.Contains(t, warnings[len(warnings)-1].Description(), "Workflow output [o2] is bound to node [n3]")
+
+	t.Run("no warnings", func(t *testing.T) {
+		assert.Empty(t, ValidateNodesCanRun(&core.CompiledWorkflow{
+			Template: &core.WorkflowTemplate{Nodes: []*core.Node{taskNode("n0")}},
+			Connections: &core.ConnectionSet{Downstream: map[string]*core.ConnectionSet_IdList{
+				c.StartNodeID: idList("n0"),
+			}},
+		}))
+	})
+}
@@ -155,6 +155,11 @@ func (w workflowBuilder) AddEdges(n c.NodeBuilder, edgeDirection c.EdgeDirection
 		fallthrough
 	case c.EdgeDirectionBidirectional:
 		for _, upNode := range n.GetUpstreamNodeIds() {
+			if _, found := w.Nodes[upNode]; !found {
+				errs.Collect(errors.NewNodeReferenceNotFoundErr(n.GetId(), upNode))
+				continue
+			}
+
 			w.AddExecutionEdge(upNode, n.GetId())
 		}
 	}
@@ -210,6 +215,11 @@ func (w workflowBuilder) ValidateWorkflow(fg *flyteWorkflow, errs errors.Compile
 
 	// Add and validate all other nodes
 	for _, n := range checkpoint {
+		if topLevelNodes.Has(n.Id) {
+			errs.Collect(errors.NewDuplicateIDFoundErr(n.Id))
+			continue
+		}
+
 		topLevelNodes.Insert(n.Id)
 		if node, addOk := wf.AddNode(wf.GetOrCreateNodeBuilder(n), errs.NewScope()); addOk {
 			v.ValidateNode(&wf, node, false /* validateConditionTypes */, errs.NewScope())
@@ -0,0 +1,20 @@
+//go:build go1.18
+// +build go1.18
+
+package compiler_test
+
+import (
+	"testing"
+)
+
+// FuzzCompileWorkflow explores generated workflows beyond the seeds covered by TestCompileWorkflow_Properties.
+// Run it with: go test ./pkg/compiler -run '^$' -fuzz FuzzCompileWorkflow
+func FuzzCompileWorkflow(f *testing.F) {
+	for seed := int64(0); seed < 10; seed++ {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		checkCompilerProperties(t, seed)
+	})
+}
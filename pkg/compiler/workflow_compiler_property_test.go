@@ -0,0 +1,230 @@
+package compiler_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/compiler"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/common"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/controller/replay"
+)
+
+const (
+	propertyTaskType  = "property-test"
+	maxGeneratedNodes = 8
+)
+
+// The kinds of defects the generator may inject into an otherwise valid workflow.
+const (
+	defectNone = iota
+	defectCycle
+	defectUnknownUpstream
+	defectWrongInputType
+	defectUnknownTask
+	defectDuplicateNode
+	defectUnknownOutput
+	defectMissingInput
+	defectNilTarget
+	defectCount
+)
+
+var (
+	harness     *replay.Harness
+	harnessErr  error
+	harnessOnce sync.Once
+)
+
+func init() {
+	// Every generated task succeeds immediately, so that a compiled workflow can only fail to terminate if the
+	// executor gets stuck on its structure.
+	pluginmachinery.PluginRegistry().RegisterCorePlugin(pluginCore.PluginEntry{
+		ID:                  propertyTaskType,
+		RegisteredTaskTypes: []pluginCore.TaskType{propertyTaskType},
+		LoadPlugin: func(_ context.Context, _ pluginCore.SetupContext) (pluginCore.Plugin, error) {
+			return succeedingPlugin{}, nil
+		},
+	})
+}
+
+type succeedingPlugin struct{}
+
+func (succeedingPlugin) GetID() string {
+	return propertyTaskType
+}
+
+func (succeedingPlugin) GetProperties() pluginCore.PluginProperties {
+	return pluginCore.PluginProperties{}
+}
+
+func (succeedingPlugin) Handle(_ context.Context, _ pluginCore.TaskExecutionContext) (pluginCore.Transition, error) {
+	return pluginCore.DoTransition(pluginCore.PhaseInfoSuccess(nil)), nil
+}
+
+func (succeedingPlugin) Abort(_ context.Context, _ pluginCore.TaskExecutionContext) error {
+	return nil
+}
+
+func (succeedingPlugin) Finalize(_ context.Context, _ pluginCore.TaskExecutionContext) error {
+	return nil
+}
+
+func getHarness(t testing.TB) *replay.Harness {
+	harnessOnce.Do(func() {
+		store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		if err != nil {
+			harnessErr = err
+			return
+		}
+
+		harness, harnessErr = replay.NewHarness(context.TODO(), store, promutils.NewTestScope())
+	})
+
+	require.NoError(t, harnessErr)
+	return harness
+}
+
+func integerType() *core.LiteralType {
+	return &core.LiteralType{Type: &core.LiteralType_Simple{Simple: core.SimpleType_INTEGER}}
+}
+
+func generatedTask() *core.TaskTemplate {
+	return &core.TaskTemplate{
+		Id:       &core.Identifier{ResourceType: core.ResourceType_TASK, Name: "task"},
+		Type:     propertyTaskType,
+		Metadata: &core.TaskMetadata{},
+		Interface: &core.TypedInterface{
+			Inputs:  &core.VariableMap{Variables: map[string]*core.Variable{"x": {Type: integerType()}}},
+			Outputs: &core.VariableMap{Variables: map[string]*core.Variable{}},
+		},
+		Target: &core.TaskTemplate_Container{Container: &core.Container{Image: "image://", Command: []string{"cmd"}}},
+	}
+}
+
+func constantBinding(v interface{}) *core.BindingData {
+	return &core.BindingData{Value: &core.BindingData_Scalar{Scalar: coreutils.MustMakeLiteral(v).GetScalar()}}
+}
+
+// Generates a random workflow of task nodes from the seed. Nodes only depend on nodes created before them, so without a
+// defect the workflow is a valid DAG. The same seed always produces the same workflow and defect.
+func generateWorkflow(seed int64) (*core.WorkflowTemplate, int) {
+	r := rand.New(rand.NewSource(seed)) // #nosec
+	defect := defectNone
+	if r.Intn(2) == 0 {
+		defect = 1 + r.Intn(defectCount-1)
+	}
+
+	taskID := generatedTask().Id
+	nodeCount := 1 + r.Intn(maxGeneratedNodes)
+	nodes := make([]*core.Node, 0, nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		n := &core.Node{
+			Id:       fmt.Sprintf("n%d", i),
+			Metadata: &core.NodeMetadata{Name: fmt.Sprintf("n%d", i)},
+			Target: &core.Node_TaskNode{
+				TaskNode: &core.TaskNode{Reference: &core.TaskNode_ReferenceId{ReferenceId: taskID}},
+			},
+			Inputs: []*core.Binding{{Var: "x", Binding: constantBinding(int64(i))}},
+		}
+
+		for j := 0; j < i; j++ {
+			if r.Intn(3) == 0 {
+				n.UpstreamNodeIds = append(n.UpstreamNodeIds, fmt.Sprintf("n%d", j))
+			}
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	victim := nodes[r.Intn(len(nodes))]
+	switch defect {
+	case defectCycle:
+		first, last := nodes[0], nodes[len(nodes)-1]
+		first.UpstreamNodeIds = append(first.UpstreamNodeIds, last.Id)
+		if first != last {
+			last.UpstreamNodeIds = append(last.UpstreamNodeIds, first.Id)
+		}
+	case defectUnknownUpstream:
+		victim.UpstreamNodeIds = append(victim.UpstreamNodeIds, "missing")
+	case defectWrongInputType:
+		victim.Inputs[0].Binding = constantBinding("not-an-integer")
+	case defectUnknownTask:
+		victim.GetTaskNode().Reference = &core.TaskNode_ReferenceId{
+			ReferenceId: &core.Identifier{ResourceType: core.ResourceType_TASK, Name: "missing"},
+		}
+	case defectDuplicateNode:
+		victim.Id = nodes[0].Id
+		if victim == nodes[0] {
+			nodes = append(nodes, &core.Node{Id: victim.Id, Target: victim.Target, Inputs: victim.Inputs})
+		}
+	case defectUnknownOutput:
+		victim.Inputs[0].Binding = &core.BindingData{Value: &core.BindingData_Promise{
+			Promise: &core.OutputReference{NodeId: nodes[0].Id, Var: "y"},
+		}}
+	case defectMissingInput:
+		victim.Inputs = nil
+	case defectNilTarget:
+		victim.Target = nil
+	}
+
+	return &core.WorkflowTemplate{
+		Id: &core.Identifier{ResourceType: core.ResourceType_WORKFLOW, Name: "generated"},
+		Interface: &core.TypedInterface{
+			Inputs:  &core.VariableMap{Variables: map[string]*core.Variable{}},
+			Outputs: &core.VariableMap{Variables: map[string]*core.Variable{}},
+		},
+		Nodes: nodes,
+	}, defect
+}
+
+// Compiles the workflow generated from the seed and checks the properties every workflow must have: compiling never
+// panics, valid workflows compile and compiled workflows run to completion.
+func checkCompilerProperties(t *testing.T, seed int64) {
+	wf, defect := generateWorkflow(seed)
+	task, err := compiler.CompileTask(generatedTask())
+	require.NoError(t, err)
+
+	var closure *core.CompiledWorkflowClosure
+	require.NotPanics(t, func() {
+		closure, err = compiler.CompileWorkflow(wf, []*core.WorkflowTemplate{}, []*core.CompiledTask{task},
+			[]common.InterfaceProvider{})
+	}, "compiling workflow generated from seed [%d] panicked", seed)
+
+	if defect == defectNone {
+		require.NoError(t, err, "workflow generated from seed [%d] is valid", seed)
+	}
+
+	if err != nil {
+		return
+	}
+
+	execID := &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: fmt.Sprintf("seed-%d", seed)}
+	flyteWf, err := k8s.BuildFlyteWorkflow(closure, &core.LiteralMap{}, execID, "ns")
+	require.NoError(t, err, "seed [%d]", seed)
+
+	// Every round either starts or completes at least one node, anything beyond that means the executor is stuck.
+	maxRounds := 2*(len(flyteWf.Nodes)+1) + 5
+	_, final, err := getHarness(t).Replay(context.TODO(), flyteWf, maxRounds)
+	require.NoError(t, err, "seed [%d]", seed)
+	assert.Equal(t, v1alpha1.WorkflowPhaseSuccess, final.GetExecutionStatus().GetPhase(),
+		"workflow generated from seed [%d] did not complete within [%d] rounds", seed, maxRounds)
+}
+
+func TestCompileWorkflow_Properties(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		checkCompilerProperties(t, seed)
+	}
+}
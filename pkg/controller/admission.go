@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const workflowTooLargeErrorCode = "WorkflowTooLarge"
+
+// WorkflowEstimate is how large a workflow is expected to grow while it executes, estimated before it starts.
+type WorkflowEstimate struct {
+	// Nodes the workflow executes, the nodes of a subworkflow are counted once for every node that runs it.
+	Nodes int
+	// Nodes that run a dynamic task, each is expected to expand into more nodes when it runs.
+	DynamicNodes int
+	// Size of the FlyteWorkflow as it is stored before it starts.
+	SerializedBytes int
+	// Size the statuses of the nodes add to the FlyteWorkflow once they have all run, including the expanded ones.
+	StatusBytes int
+}
+
+// TotalBytes is the size the FlyteWorkflow is expected to grow to.
+func (e WorkflowEstimate) TotalBytes() int {
+	return e.SerializedBytes + e.StatusBytes
+}
+
+type admissionMetrics struct {
+	Warned   prometheus.Counter
+	Rejected prometheus.Counter
+}
+
+// AdmissionController estimates the size of workflows before they start, and fails those that would exceed the
+// configured thresholds, e.g. the size etcd can store, before they execute any node. It only warns if so configured.
+type AdmissionController struct {
+	cfg     config.AdmissionConfig
+	metrics admissionMetrics
+}
+
+// Estimate estimates how large the workflow grows while it executes.
+func (a *AdmissionController) Estimate(w *v1alpha1.FlyteWorkflow) (WorkflowEstimate, error) {
+	raw, err := json.Marshal(w)
+	if err != nil {
+		return WorkflowEstimate{}, err
+	}
+
+	estimate := WorkflowEstimate{SerializedBytes: len(raw)}
+	if w.WorkflowSpec != nil {
+		a.countNodes(w, w.WorkflowSpec, &estimate, map[v1alpha1.WorkflowID]bool{})
+	}
+
+	expanded := estimate.Nodes + estimate.DynamicNodes*a.cfg.DynamicNodeExpansion
+	estimate.StatusBytes = expanded * a.cfg.NodeStatusBytes
+	return estimate, nil
+}
+
+// Counts the nodes of the workflow spec, and those of the subworkflows its nodes run. Subworkflows that run themselves
+// are only counted once.
+func (a *AdmissionController) countNodes(w *v1alpha1.FlyteWorkflow, spec *v1alpha1.WorkflowSpec, estimate *WorkflowEstimate,
+	visiting map[v1alpha1.WorkflowID]bool) {
+
+	for _, n := range spec.Nodes {
+		estimate.Nodes++
+		if taskID := n.GetTaskID(); taskID != nil {
+			if task, ok := w.Tasks[*taskID]; ok && task != nil && a.isDynamic(task.TaskType()) {
+				estimate.DynamicNodes++
+			}
+		}
+
+		if n.WorkflowNode == nil || n.WorkflowNode.GetSubWorkflowRef() == nil {
+			continue
+		}
+
+		subID := *n.WorkflowNode.GetSubWorkflowRef()
+		sub, ok := w.SubWorkflows[subID]
+		if !ok || sub == nil || visiting[subID] {
+			continue
+		}
+
+		visiting[subID] = true
+		a.countNodes(w, sub, estimate, visiting)
+		delete(visiting, subID)
+	}
+}
+
+func (a *AdmissionController) isDynamic(taskType v1alpha1.TaskType) bool {
+	for _, t := range a.cfg.DynamicTaskTypes {
+		if t == taskType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Admit checks the estimate of a workflow that is about to start against the thresholds. It returns the error to fail
+// the workflow with if it must be rejected, or nil if it is admitted.
+func (a *AdmissionController) Admit(ctx context.Context, w *v1alpha1.FlyteWorkflow) *core.ExecutionError {
+	estimate, err := a.Estimate(w)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to estimate the size of the workflow, admitting it. Error: %v", err)
+		return nil
+	}
+
+	logger.Debugf(ctx, "Estimated the workflow to [%d] nodes, [%d] of them dynamic, and [%d] bytes",
+		estimate.Nodes, estimate.DynamicNodes, estimate.TotalBytes())
+
+	var msg string
+	switch {
+	case a.cfg.MaxNodes > 0 && estimate.Nodes > a.cfg.MaxNodes:
+		msg = fmt.Sprintf("Workflow has [%d] nodes, more than the maximum of [%d].", estimate.Nodes, a.cfg.MaxNodes)
+	case a.cfg.MaxSizeBytes > 0 && estimate.TotalBytes() > a.cfg.MaxSizeBytes:
+		msg = fmt.Sprintf("Workflow is estimated to grow to [%d] bytes while executing, more than the maximum of [%d]. "+
+			"Consider running some of its subworkflows as launch plans.", estimate.TotalBytes(), a.cfg.MaxSizeBytes)
+	default:
+		return nil
+	}
+
+	if a.cfg.Action != config.AdmissionActionReject {
+		logger.Warnf(ctx, "Admitting a workflow over the thresholds: %s", msg)
+		a.metrics.Warned.Inc()
+		return nil
+	}
+
+	logger.Warnf(ctx, "Rejecting the workflow: %s", msg)
+	a.metrics.Rejected.Inc()
+	return &core.ExecutionError{
+		Kind:    core.ExecutionError_USER,
+		Code:    workflowTooLargeErrorCode,
+		Message: msg,
+	}
+}
+
+func NewAdmissionController(cfg config.AdmissionConfig, scope promutils.Scope) *AdmissionController {
+	return &AdmissionController{
+		cfg: cfg,
+		metrics: admissionMetrics{
+			Warned:   scope.MustNewCounter("warned", "Number of workflows admitted over the thresholds"),
+			Rejected: scope.MustNewCounter("rejected", "Number of workflows rejected over the thresholds"),
+		},
+	}
+}
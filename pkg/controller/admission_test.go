@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
+)
+
+func newAdmissionTestWorkflow() *v1alpha1.FlyteWorkflow {
+	task, dynamicTask, sub := "t", "d", v1alpha1.WorkflowID("sub")
+	return &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{Namespace: "test", Name: "wf"},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{
+			ID: "w1",
+			Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+				"start": {ID: "start", Kind: v1alpha1.NodeKindStart},
+				"n1":    {ID: "n1", Kind: v1alpha1.NodeKindTask, TaskRef: &task},
+				"n2":    {ID: "n2", Kind: v1alpha1.NodeKindWorkflow, WorkflowNode: &v1alpha1.WorkflowNodeSpec{SubWorkflowReference: &sub}},
+				"n3":    {ID: "n3", Kind: v1alpha1.NodeKindWorkflow, WorkflowNode: &v1alpha1.WorkflowNodeSpec{SubWorkflowReference: &sub}},
+				"end":   {ID: "end", Kind: v1alpha1.NodeKindEnd},
+			},
+		},
+		SubWorkflows: map[v1alpha1.WorkflowID]*v1alpha1.WorkflowSpec{
+			sub: {
+				ID: sub,
+				Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+					"s1": {ID: "s1", Kind: v1alpha1.NodeKindTask, TaskRef: &dynamicTask},
+					// A subworkflow that runs itself is only counted once.
+					"s2": {ID: "s2", Kind: v1alpha1.NodeKindWorkflow, WorkflowNode: &v1alpha1.WorkflowNodeSpec{SubWorkflowReference: &sub}},
+				},
+			},
+		},
+		Tasks: map[v1alpha1.TaskID]*v1alpha1.TaskSpec{
+			task:        {TaskTemplate: &core.TaskTemplate{Type: "container"}},
+			dynamicTask: {TaskTemplate: &core.TaskTemplate{Type: "dynamic-task"}},
+		},
+	}
+}
+
+func TestAdmissionController_Estimate(t *testing.T) {
+	a := NewAdmissionController(config.AdmissionConfig{
+		NodeStatusBytes:      100,
+		DynamicTaskTypes:     []string{"dynamic-task"},
+		DynamicNodeExpansion: 10,
+	}, promutils.NewTestScope())
+
+	estimate, err := a.Estimate(newAdmissionTestWorkflow())
+	assert.NoError(t, err)
+	// 5 nodes, and the 2 of the subworkflow for each of the 2 nodes that run it.
+	assert.Equal(t, 9, estimate.Nodes)
+	assert.Equal(t, 2, estimate.DynamicNodes)
+	assert.Greater(t, estimate.SerializedBytes, 0)
+	assert.Equal(t, (9+2*10)*100, estimate.StatusBytes)
+	assert.Equal(t, estimate.SerializedBytes+estimate.StatusBytes, estimate.TotalBytes())
+}
+
+func TestAdmissionController_Admit(t *testing.T) {
+	ctx := context.TODO()
+	cfg := config.AdmissionConfig{
+		Action:               config.AdmissionActionReject,
+		NodeStatusBytes:      100,
+		DynamicTaskTypes:     []string{"dynamic-task"},
+		DynamicNodeExpansion: 10,
+	}
+
+	t.Run("admitted", func(t *testing.T) {
+		c := cfg
+		c.MaxNodes = 9
+		c.MaxSizeBytes = 1024 * 1024
+		assert.Nil(t, NewAdmissionController(c, promutils.NewTestScope()).Admit(ctx, newAdmissionTestWorkflow()))
+	})
+
+	t.Run("too-many-nodes", func(t *testing.T) {
+		c := cfg
+		c.MaxNodes = 8
+		execErr := NewAdmissionController(c, promutils.NewTestScope()).Admit(ctx, newAdmissionTestWorkflow())
+		if assert.NotNil(t, execErr) {
+			assert.Equal(t, workflowTooLargeErrorCode, execErr.Code)
+			assert.Equal(t, core.ExecutionError_USER, execErr.Kind)
+			assert.Contains(t, execErr.Message, "[9] nodes")
+		}
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		c := cfg
+		c.MaxSizeBytes = 2900
+		execErr := NewAdmissionController(c, promutils.NewTestScope()).Admit(ctx, newAdmissionTestWorkflow())
+		if assert.NotNil(t, execErr) {
+			assert.Equal(t, workflowTooLargeErrorCode, execErr.Code)
+			assert.Contains(t, execErr.Message, "bytes")
+		}
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		c := cfg
+		c.Action = config.AdmissionActionWarn
+		c.MaxNodes = 1
+		assert.Nil(t, NewAdmissionController(c, promutils.NewTestScope()).Admit(ctx, newAdmissionTestWorkflow()))
+	})
+}
+
+func TestPropeller_Handle_Admission(t *testing.T) {
+	ctx := context.TODO()
+	s := workflowstore.NewInMemoryWorkflowStore()
+	exec := &mockExecutor{HandleCb: func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+		assert.FailNow(t, "rejected workflows must not be executed")
+		return nil
+	}}
+	cfg := &config.Config{
+		Admission: config.AdmissionConfig{Enabled: true, Action: config.AdmissionActionReject, MaxNodes: 1},
+	}
+	p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, promutils.NewTestScope())
+
+	assert.NoError(t, s.Create(ctx, newAdmissionTestWorkflow()))
+	assert.NoError(t, p.Handle(ctx, "test", "wf"))
+
+	r, err := s.Get(ctx, "test", "wf")
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.WorkflowPhaseFailing, r.GetExecutionStatus().GetPhase())
+	assert.Equal(t, workflowTooLargeErrorCode, r.GetExecutionStatus().GetExecutionError().GetCode())
+}
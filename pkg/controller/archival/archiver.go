@@ -0,0 +1,123 @@
+// Package archival stores completed workflows, spec and status, in the datastore before the garbage collector deletes
+// them, so that executions can still be inspected after their FlyteWorkflow object is gone.
+package archival
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/pkg/errors"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const archiveObjectSuffix = ".json"
+
+type archiverMetrics struct {
+	WorkflowsArchived labeled.Counter
+	ArchiveFailures   labeled.Counter
+}
+
+// Archiver writes workflows as JSON objects named after the workflow under a per-namespace prefix.
+type Archiver struct {
+	store      *storage.DataStore
+	basePrefix storage.DataReference
+	metrics    *archiverMetrics
+}
+
+// GetArchivePrefix returns the prefix that workflows are archived under for the given config.
+func GetArchivePrefix(ctx context.Context, store *storage.DataStore, cfg *Config) (storage.DataReference, error) {
+	basePrefix := store.GetBaseContainerFQN(ctx)
+	if cfg.Prefix == "" {
+		return basePrefix, nil
+	}
+
+	return store.ConstructReference(ctx, basePrefix, cfg.Prefix)
+}
+
+// GetArchiveReference returns the location of the archived workflow with the given namespace and name.
+func GetArchiveReference(ctx context.Context, store storage.ReferenceConstructor, basePrefix storage.DataReference,
+	namespace, name string) (storage.DataReference, error) {
+
+	return store.ConstructReference(ctx, basePrefix, namespace, name+archiveObjectSuffix)
+}
+
+// Archive stores the workflow in the datastore. Archiving the same workflow again overwrites the archived object.
+func (a *Archiver) Archive(ctx context.Context, wf *v1alpha1.FlyteWorkflow) error {
+	ref, err := GetArchiveReference(ctx, a.store, a.basePrefix, wf.GetNamespace(), wf.GetName())
+	if err != nil {
+		a.metrics.ArchiveFailures.Inc(ctx)
+		return err
+	}
+
+	raw, err := json.Marshal(wf)
+	if err != nil {
+		a.metrics.ArchiveFailures.Inc(ctx)
+		return errors.Wrapf(err, "failed to marshal workflow [%v/%v]", wf.GetNamespace(), wf.GetName())
+	}
+
+	if err := a.store.WriteRaw(ctx, ref, int64(len(raw)), storage.Options{}, bytes.NewReader(raw)); err != nil {
+		a.metrics.ArchiveFailures.Inc(ctx)
+		return errors.Wrapf(err, "failed to archive workflow [%v/%v] to [%v]", wf.GetNamespace(), wf.GetName(), ref)
+	}
+
+	logger.Debugf(ctx, "Archived workflow [%v/%v] to [%v]", wf.GetNamespace(), wf.GetName(), ref)
+	a.metrics.WorkflowsArchived.Inc(ctx)
+	return nil
+}
+
+// Load reads an archived workflow from the datastore.
+func Load(ctx context.Context, store *storage.DataStore, basePrefix storage.DataReference, namespace, name string) (
+	*v1alpha1.FlyteWorkflow, error) {
+
+	ref, err := GetArchiveReference(ctx, store, basePrefix, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := store.ReadRaw(ctx, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read archived workflow from [%v]", ref)
+	}
+
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Warnf(ctx, "Failed to close reader for [%v]. Error: %v", ref, err)
+		}
+	}()
+
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read archived workflow from [%v]", ref)
+	}
+
+	wf := &v1alpha1.FlyteWorkflow{}
+	if err := json.Unmarshal(raw, wf); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal archived workflow from [%v]", ref)
+	}
+
+	return wf, nil
+}
+
+// NewArchiver creates an Archiver that stores workflows under the configured prefix.
+func NewArchiver(ctx context.Context, store *storage.DataStore, cfg *Config, scope promutils.Scope) (*Archiver, error) {
+	basePrefix, err := GetArchivePrefix(ctx, store, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Archiver{
+		store:      store,
+		basePrefix: basePrefix,
+		metrics: &archiverMetrics{
+			WorkflowsArchived: labeled.NewCounter("workflows_archived", "Number of workflows archived", scope),
+			ArchiveFailures:   labeled.NewCounter("archive_failures", "Number of workflows that failed to be archived", scope),
+		},
+	}, nil
+}
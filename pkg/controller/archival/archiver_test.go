@@ -0,0 +1,61 @@
+package archival
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func TestArchiver_Archive(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	cfg := &Config{Enabled: true, Prefix: "archive"}
+	archiver, err := NewArchiver(ctx, store, cfg, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	basePrefix, err := GetArchivePrefix(ctx, store, cfg)
+	assert.NoError(t, err)
+
+	wf := &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "exec",
+			Namespace: "ns",
+		},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{ID: "wf"},
+		Status: v1alpha1.WorkflowStatus{
+			Phase:   v1alpha1.WorkflowPhaseSuccess,
+			Message: "done",
+		},
+	}
+
+	t.Run("archive-and-load", func(t *testing.T) {
+		assert.NoError(t, archiver.Archive(ctx, wf))
+
+		archived, err := Load(ctx, store, basePrefix, "ns", "exec")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "exec", archived.Name)
+			assert.Equal(t, "wf", archived.ID)
+			assert.Equal(t, v1alpha1.WorkflowPhaseSuccess, archived.Status.Phase)
+			assert.Equal(t, "done", archived.Status.Message)
+		}
+	})
+
+	t.Run("not-archived", func(t *testing.T) {
+		_, err := Load(ctx, store, basePrefix, "ns", "other")
+		assert.Error(t, err)
+	})
+}
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NamespaceKey)
+}
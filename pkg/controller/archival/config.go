@@ -0,0 +1,33 @@
+package archival
+
+import (
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "archival"
+
+var (
+	defaultConfig = &Config{
+		Enabled: false,
+		Prefix:  "archive/workflows",
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls the archival of completed workflows. When enabled, the garbage collector stores every completed
+// workflow, spec and status, in the datastore before it deletes the FlyteWorkflow object.
+type Config struct {
+	Enabled bool   `json:"enabled" pflag:",Enables archiving completed workflows to the datastore before they are garbage collected."`
+	Prefix  string `json:"prefix" pflag:",Prefix under the base container of the datastore that workflows are archived under."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,35 @@
+package audit
+
+import (
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "audit"
+
+var (
+	defaultConfig = &Config{
+		Enabled:    false,
+		ObjectName: "audit",
+		Principal:  "propeller",
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls the audit log of workflow, node and task phase transitions. When enabled, every event that is
+// successfully recorded is also written as a JSON object under a per-execution prefix in the metadata store.
+type Config struct {
+	Enabled    bool   `json:"enabled" pflag:",Enables writing an audit log of all phase transitions to the metadata store."`
+	ObjectName string `json:"object-name" pflag:",Name of the prefix the audit records are created under; below the execution's metadata prefix."`
+	Principal  string `json:"principal" pflag:",Identity recorded as the principal for every audit record, if the event does not specify a producer."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,230 @@
+// Package audit implements a structured audit log of workflow, node and task phase transitions. Every record of the
+// audit log is written as a JSON object under a prefix per execution, stored next to the execution's metadata, so that
+// it can be used for compliance reviews and postmortems without access to the control plane.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type RecordKind = string
+
+const (
+	RecordKindWorkflow RecordKind = "workflow"
+	RecordKindNode     RecordKind = "node"
+	RecordKindTask     RecordKind = "task"
+)
+
+// Record is a single line in the audit log and describes one phase transition.
+type Record struct {
+	Kind         RecordKind `json:"kind"`
+	NodeID       string     `json:"nodeId,omitempty"`
+	RetryGroup   string     `json:"retryGroup,omitempty"`
+	TaskID       string     `json:"taskId,omitempty"`
+	RetryAttempt uint32     `json:"retryAttempt,omitempty"`
+	Phase        string     `json:"phase"`
+	PhaseVersion uint32     `json:"phaseVersion,omitempty"`
+	OccurredAt   *time.Time `json:"occurredAt,omitempty"`
+	RecordedAt   time.Time  `json:"recordedAt"`
+	ErrorCode    string     `json:"errorCode,omitempty"`
+	ErrorKind    string     `json:"errorKind,omitempty"`
+	Principal    string     `json:"principal"`
+}
+
+type sinkMetrics struct {
+	RecordsWritten labeled.Counter
+	WriteFailures  labeled.Counter
+}
+
+// eventSink decorates an events.EventSink and adds every successfully recorded event to the audit log of the execution
+// it belongs to.
+type eventSink struct {
+	events.EventSink
+	store      *storage.DataStore
+	basePrefix storage.DataReference
+	objectName string
+	principal  string
+	metrics    *sinkMetrics
+	clock      func() time.Time
+}
+
+func (s *eventSink) Sink(ctx context.Context, message proto.Message) error {
+	if err := s.EventSink.Sink(ctx, message); err != nil {
+		return err
+	}
+
+	execID, record, ok := s.toRecord(message)
+	if !ok {
+		return nil
+	}
+
+	// The audit log is best effort, a failure to write it should never block the execution from progressing.
+	if err := s.append(ctx, execID, record); err != nil {
+		s.metrics.WriteFailures.Inc(ctx)
+		logger.Warnf(ctx, "Failed to write audit record for execution [%v]. Error: %v", execID, err)
+		return nil
+	}
+
+	s.metrics.RecordsWritten.Inc(ctx)
+	return nil
+}
+
+// GetAuditReference returns the prefix the audit records of the given execution are written under.
+func GetAuditReference(ctx context.Context, store storage.ReferenceConstructor, basePrefix storage.DataReference,
+	objectName string, execID *core.WorkflowExecutionIdentifier) (storage.DataReference, error) {
+
+	return store.ConstructReference(ctx, basePrefix,
+		fmt.Sprintf("%v-%v-%v", execID.GetProject(), execID.GetDomain(), execID.GetName()), objectName)
+}
+
+// Returns the location of the object of a single record. Records are named after the time they were recorded, so that
+// listing the audit prefix of an execution returns them in order, and after the hash of their content, so that the
+// records of the same instant don't overwrite each other.
+func getRecordReference(ctx context.Context, store storage.ReferenceConstructor, auditRef storage.DataReference,
+	record Record, raw []byte) (storage.DataReference, error) {
+
+	h := fnv.New64a()
+	if _, err := h.Write(raw); err != nil {
+		return "", err
+	}
+
+	return store.ConstructReference(ctx, auditRef, fmt.Sprintf("%020d-%016x.json", record.RecordedAt.UnixNano(), h.Sum64()))
+}
+
+// Object stores do not support appends, so every record is written to an object of its own. Writing a record never
+// depends on the ones written before it, nor on how many workers evaluate the execution.
+func (s *eventSink) append(ctx context.Context, execID *core.WorkflowExecutionIdentifier, record Record) error {
+	auditRef, err := GetAuditReference(ctx, s.store, s.basePrefix, s.objectName, execID)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	ref, err := getRecordReference(ctx, s.store, auditRef, record, raw)
+	if err != nil {
+		return err
+	}
+
+	return s.store.WriteRaw(ctx, ref, int64(len(raw)), storage.Options{}, bytes.NewReader(raw))
+}
+
+func toTime(t *timestamp.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	converted, err := ptypes.Timestamp(t)
+	if err != nil {
+		return nil
+	}
+
+	return &converted
+}
+
+func (s *eventSink) principalOrDefault(producerID string) string {
+	if len(producerID) > 0 {
+		return producerID
+	}
+
+	return s.principal
+}
+
+func (s *eventSink) toRecord(message proto.Message) (*core.WorkflowExecutionIdentifier, Record, bool) {
+	record := Record{
+		RecordedAt: s.clock(),
+	}
+
+	switch e := message.(type) {
+	case *event.WorkflowExecutionEvent:
+		record.Kind = RecordKindWorkflow
+		record.Phase = e.GetPhase().String()
+		record.OccurredAt = toTime(e.GetOccurredAt())
+		record.ErrorCode = e.GetError().GetCode()
+		record.Principal = s.principalOrDefault(e.GetProducerId())
+		if e.GetError() != nil {
+			record.ErrorKind = e.GetError().GetKind().String()
+		}
+
+		return e.GetExecutionId(), record, e.GetExecutionId() != nil
+	case *event.NodeExecutionEvent:
+		record.Kind = RecordKindNode
+		record.NodeID = e.GetId().GetNodeId()
+		record.RetryGroup = e.GetRetryGroup()
+		record.Phase = e.GetPhase().String()
+		record.OccurredAt = toTime(e.GetOccurredAt())
+		record.ErrorCode = e.GetError().GetCode()
+		record.Principal = s.principalOrDefault(e.GetProducerId())
+		if e.GetError() != nil {
+			record.ErrorKind = e.GetError().GetKind().String()
+		}
+
+		return e.GetId().GetExecutionId(), record, e.GetId().GetExecutionId() != nil
+	case *event.TaskExecutionEvent:
+		record.Kind = RecordKindTask
+		record.NodeID = e.GetParentNodeExecutionId().GetNodeId()
+		record.TaskID = e.GetTaskId().GetName()
+		record.RetryAttempt = e.GetRetryAttempt()
+		record.Phase = e.GetPhase().String()
+		record.PhaseVersion = e.GetPhaseVersion()
+		record.OccurredAt = toTime(e.GetOccurredAt())
+		record.ErrorCode = e.GetError().GetCode()
+		record.Principal = s.principalOrDefault(e.GetProducerId())
+		if e.GetError() != nil {
+			record.ErrorKind = e.GetError().GetKind().String()
+		}
+
+		execID := e.GetParentNodeExecutionId().GetExecutionId()
+		return execID, record, execID != nil
+	default:
+		return nil, record, false
+	}
+}
+
+// NewEventSink wraps the given EventSink so that every successfully recorded event is also added to the audit log of
+// its execution. The audit logs are stored under the same metadata prefix that the workflow executor uses.
+func NewEventSink(ctx context.Context, sink events.EventSink, store *storage.DataStore, metadataPrefix string,
+	cfg *Config, scope promutils.Scope) (events.EventSink, error) {
+
+	basePrefix := store.GetBaseContainerFQN(ctx)
+	if metadataPrefix != "" {
+		var err error
+		basePrefix, err = store.ConstructReference(ctx, basePrefix, metadataPrefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &eventSink{
+		EventSink:  sink,
+		store:      store,
+		basePrefix: basePrefix,
+		objectName: cfg.ObjectName,
+		principal:  cfg.Principal,
+		metrics: &sinkMetrics{
+			RecordsWritten: labeled.NewCounter("records_written", "Number of audit records written", scope),
+			WriteFailures:  labeled.NewCounter("write_failures", "Number of audit records that failed to be written", scope),
+		},
+		clock: time.Now,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+var execID = &core.WorkflowExecutionIdentifier{
+	Project: "project",
+	Domain:  "domain",
+	Name:    "name",
+}
+
+// recordingStore remembers the references written through it, the memory store cannot list them.
+type recordingStore struct {
+	storage.RawStore
+	written *[]storage.DataReference
+}
+
+func (s recordingStore) WriteRaw(ctx context.Context, reference storage.DataReference, size int64, opts storage.Options,
+	raw io.Reader) error {
+
+	*s.written = append(*s.written, reference)
+	return s.RawStore.WriteRaw(ctx, reference, size, opts, raw)
+}
+
+func newRecordingStore(t *testing.T) (*storage.DataStore, *[]storage.DataReference) {
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	written := &[]storage.DataReference{}
+	rawStore := recordingStore{RawStore: store.ComposedProtobufStore, written: written}
+	return storage.NewCompositeDataStore(store.ReferenceConstructor,
+		storage.NewDefaultProtobufStore(rawStore, promutils.NewTestScope())), written
+}
+
+// Reads the records written under the audit prefix in the order of their names.
+func readRecords(t *testing.T, store *storage.DataStore, written *[]storage.DataReference, ref storage.DataReference) []Record {
+	var refs []string
+	for _, w := range *written {
+		if strings.HasPrefix(string(w), string(ref)+"/") {
+			refs = append(refs, string(w))
+		}
+	}
+
+	sort.Strings(refs)
+	var records []Record
+	for _, r := range refs {
+		reader, err := store.ReadRaw(context.TODO(), storage.DataReference(r))
+		assert.NoError(t, err)
+		raw, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.NoError(t, reader.Close())
+
+		record := Record{}
+		assert.NoError(t, json.Unmarshal(raw, &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestEventSink_Sink(t *testing.T) {
+	ctx := context.TODO()
+	store, written := newRecordingStore(t)
+	cfg := &Config{ObjectName: "audit", Principal: "tester"}
+	sink, err := NewEventSink(ctx, events.NewMockEventSink(), store, "metadata", cfg, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	// Records are named after the time they were recorded.
+	now := time.Now()
+	sink.(*eventSink).clock = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	ref, err := GetAuditReference(ctx, store, "metadata", cfg.ObjectName, execID)
+	assert.NoError(t, err)
+
+	t.Run("workflow-node-task", func(t *testing.T) {
+		assert.NoError(t, sink.Sink(ctx, &event.WorkflowExecutionEvent{
+			ExecutionId: execID,
+			Phase:       core.WorkflowExecution_RUNNING,
+			OccurredAt:  ptypes.TimestampNow(),
+		}))
+
+		assert.NoError(t, sink.Sink(ctx, &event.NodeExecutionEvent{
+			Id:         &core.NodeExecutionIdentifier{ExecutionId: execID, NodeId: "n1"},
+			Phase:      core.NodeExecution_FAILED,
+			ProducerId: "propeller-1",
+			OutputResult: &event.NodeExecutionEvent_Error{
+				Error: &core.ExecutionError{Code: "OOMKilled", Kind: core.ExecutionError_USER},
+			},
+		}))
+
+		assert.NoError(t, sink.Sink(ctx, &event.TaskExecutionEvent{
+			TaskId:                &core.Identifier{Name: "task"},
+			ParentNodeExecutionId: &core.NodeExecutionIdentifier{ExecutionId: execID, NodeId: "n1"},
+			RetryAttempt:          2,
+			Phase:                 core.TaskExecution_RUNNING,
+			PhaseVersion:          3,
+		}))
+
+		records := readRecords(t, store, written, ref)
+		if assert.Len(t, records, 3) {
+			assert.Equal(t, RecordKindWorkflow, records[0].Kind)
+			assert.Equal(t, core.WorkflowExecution_RUNNING.String(), records[0].Phase)
+			assert.NotNil(t, records[0].OccurredAt)
+			assert.Equal(t, "tester", records[0].Principal)
+
+			assert.Equal(t, RecordKindNode, records[1].Kind)
+			assert.Equal(t, "n1", records[1].NodeID)
+			assert.Equal(t, "OOMKilled", records[1].ErrorCode)
+			assert.Equal(t, core.ExecutionError_USER.String(), records[1].ErrorKind)
+			assert.Equal(t, "propeller-1", records[1].Principal)
+
+			assert.Equal(t, RecordKindTask, records[2].Kind)
+			assert.Equal(t, "task", records[2].TaskID)
+			assert.Equal(t, uint32(2), records[2].RetryAttempt)
+			assert.Equal(t, uint32(3), records[2].PhaseVersion)
+		}
+	})
+
+	t.Run("unknown-message", func(t *testing.T) {
+		assert.NoError(t, sink.Sink(ctx, &core.Identifier{}))
+		assert.Len(t, readRecords(t, store, written, ref), 3)
+	})
+}
+
+func TestEventSink_SinkFailure(t *testing.T) {
+	ctx := context.TODO()
+	store, written := newRecordingStore(t)
+	underlying := &events.MockEventSink{
+		SinkCb: func(ctx context.Context, message proto.Message) error {
+			return fmt.Errorf("failed")
+		},
+	}
+
+	cfg := &Config{ObjectName: "audit"}
+	sink, err := NewEventSink(ctx, underlying, store, "", cfg, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	assert.Error(t, sink.Sink(ctx, &event.WorkflowExecutionEvent{
+		ExecutionId: execID,
+		Phase:       core.WorkflowExecution_RUNNING,
+	}))
+
+	assert.Empty(t, *written)
+}
+
+func init() {
+	labeled.SetMetricKeys(contextutils.ProjectKey, contextutils.DomainKey, contextutils.WorkflowIDKey)
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Label that records the cohort of a workflow, so that it keeps its cohort when the canary percentage changes.
+const canaryCohortKey = "canary-cohort"
+
+type cohort = string
+
+const (
+	cohortCanary   cohort = "canary"
+	cohortBaseline cohort = "baseline"
+)
+
+type canaryMetrics struct {
+	Workflows    *prometheus.CounterVec
+	RoundTime    *promutils.StopWatchVec
+	SystemErrors *prometheus.CounterVec
+	Terminated   *prometheus.CounterVec
+}
+
+// CanaryRouter assigns workflows to the canary or the baseline cohort and evaluates the canary workflows with the new
+// code paths. It reports the same metrics for both cohorts, so that the new code paths can be compared to the old ones.
+type CanaryRouter struct {
+	cfg     config.CanaryConfig
+	metrics canaryMetrics
+}
+
+// Returns the cohort of the workflow, as recorded on it, or as picked by the hash of its execution id if it has none.
+func (r *CanaryRouter) cohort(w *v1alpha1.FlyteWorkflow) cohort {
+	if c, ok := w.GetLabels()[canaryCohortKey]; ok {
+		return c
+	}
+
+	key := w.GetName()
+	if execID := w.GetExecutionID(); execID.WorkflowExecutionIdentifier != nil {
+		key = execID.GetProject() + ":" + execID.GetDomain() + ":" + execID.GetName()
+	}
+
+	h := fnv.New32a()
+	// Writing to a hash never fails.
+	_, _ = h.Write([]byte(key))
+	if int(h.Sum32()%100) < r.cfg.Percent {
+		return cohortCanary
+	}
+
+	return cohortBaseline
+}
+
+// Route records the cohort on workflows that are about to start, and switches the canary ones to the new code paths.
+// It returns the cohort of the workflow.
+func (r *CanaryRouter) Route(ctx context.Context, w *v1alpha1.FlyteWorkflow) cohort {
+	c := r.cohort(w)
+	if w.GetExecutionStatus().GetPhase() != v1alpha1.WorkflowPhaseReady {
+		return c
+	}
+
+	if w.Labels == nil {
+		w.Labels = map[string]string{}
+	}
+
+	if _, ok := w.Labels[canaryCohortKey]; !ok {
+		w.Labels[canaryCohortKey] = c
+		r.metrics.Workflows.WithLabelValues(c).Inc()
+	}
+
+	if c != cohortCanary {
+		return c
+	}
+
+	// The event version can only change before the first event of the workflow is recorded.
+	if eventVersion := v1alpha1.EventVersion(r.cfg.EventVersion); eventVersion > w.GetEventVersion() {
+		logger.Infof(ctx, "Recording events of canary workflow with event version [%v].", eventVersion)
+		if w.WorkflowMeta == nil {
+			w.WorkflowMeta = &v1alpha1.WorkflowMeta{}
+		}
+
+		w.WorkflowMeta.EventVersion = eventVersion
+	}
+
+	return c
+}
+
+// Observe records the outcome of a round of a workflow of the given cohort.
+func (r *CanaryRouter) Observe(c cohort, start time.Time, w *v1alpha1.FlyteWorkflow, err error) {
+	r.metrics.RoundTime.WithLabelValues(c).Observe(start, time.Now())
+	if err != nil {
+		r.metrics.SystemErrors.WithLabelValues(c).Inc()
+		return
+	}
+
+	if w != nil && w.GetExecutionStatus().IsTerminated() {
+		r.metrics.Terminated.WithLabelValues(c, w.GetExecutionStatus().GetPhase().String()).Inc()
+	}
+}
+
+func NewCanaryRouter(cfg config.CanaryConfig, scope promutils.Scope) *CanaryRouter {
+	return &CanaryRouter{
+		cfg: cfg,
+		metrics: canaryMetrics{
+			Workflows:    scope.MustNewCounterVec("workflows", "Number of workflows assigned to each cohort", "cohort"),
+			RoundTime:    scope.MustNewStopWatchVec("round_time", "Time taken to evaluate a workflow in a round, per cohort", time.Millisecond, "cohort"),
+			SystemErrors: scope.MustNewCounterVec("system_error", "Number of rounds that failed with a system error, per cohort", "cohort"),
+			Terminated:   scope.MustNewCounterVec("terminated", "Number of workflows that reached a terminal phase, per cohort", "cohort", "phase"),
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+func newCanaryTestWorkflow(name string) *v1alpha1.FlyteWorkflow {
+	return &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+		},
+		ExecutionID: v1alpha1.WorkflowExecutionIdentifier{
+			WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{
+				Project: "p",
+				Domain:  "d",
+				Name:    name,
+			},
+		},
+	}
+}
+
+func TestCanaryRouter_Route(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("percentage", func(t *testing.T) {
+		r := NewCanaryRouter(config.CanaryConfig{Enabled: true, Percent: 30}, promutils.NewTestScope())
+		canaries := 0
+		for i := 0; i < 1000; i++ {
+			if r.Route(ctx, newCanaryTestWorkflow(fmt.Sprintf("wf-%d", i))) == cohortCanary {
+				canaries++
+			}
+		}
+
+		assert.InDelta(t, 300, canaries, 60)
+		assert.Equal(t, float64(canaries), testutil.ToFloat64(r.metrics.Workflows.WithLabelValues(cohortCanary)))
+	})
+
+	t.Run("all-canaries", func(t *testing.T) {
+		r := NewCanaryRouter(config.CanaryConfig{Enabled: true, Percent: 100, EventVersion: int(v1alpha1.EventVersion2)}, promutils.NewTestScope())
+		w := newCanaryTestWorkflow("wf")
+		assert.Equal(t, cohortCanary, r.Route(ctx, w))
+		assert.Equal(t, cohortCanary, w.Labels[canaryCohortKey])
+		assert.Equal(t, v1alpha1.EventVersion2, w.GetEventVersion())
+	})
+
+	t.Run("no-canaries", func(t *testing.T) {
+		r := NewCanaryRouter(config.CanaryConfig{Enabled: true, Percent: 0, EventVersion: int(v1alpha1.EventVersion2)}, promutils.NewTestScope())
+		w := newCanaryTestWorkflow("wf")
+		assert.Equal(t, cohortBaseline, r.Route(ctx, w))
+		assert.Equal(t, cohortBaseline, w.Labels[canaryCohortKey])
+		assert.Equal(t, v1alpha1.EventVersion0, w.GetEventVersion())
+	})
+
+	t.Run("sticky-cohort", func(t *testing.T) {
+		r := NewCanaryRouter(config.CanaryConfig{Enabled: true, Percent: 0, EventVersion: int(v1alpha1.EventVersion2)}, promutils.NewTestScope())
+		w := newCanaryTestWorkflow("wf")
+		w.Labels = map[string]string{canaryCohortKey: cohortCanary}
+		w.Status.Phase = v1alpha1.WorkflowPhaseRunning
+		assert.Equal(t, cohortCanary, r.Route(ctx, w))
+		// The event version of running workflows is never changed.
+		assert.Equal(t, v1alpha1.EventVersion0, w.GetEventVersion())
+	})
+
+	t.Run("higher-event-version", func(t *testing.T) {
+		r := NewCanaryRouter(config.CanaryConfig{Enabled: true, Percent: 100, EventVersion: int(v1alpha1.EventVersion1)}, promutils.NewTestScope())
+		w := newCanaryTestWorkflow("wf")
+		w.WorkflowMeta = &v1alpha1.WorkflowMeta{EventVersion: v1alpha1.EventVersion2}
+		assert.Equal(t, cohortCanary, r.Route(ctx, w))
+		assert.Equal(t, v1alpha1.EventVersion2, w.GetEventVersion())
+	})
+}
+
+func TestCanaryRouter_Observe(t *testing.T) {
+	r := NewCanaryRouter(config.CanaryConfig{Enabled: true, Percent: 50}, promutils.NewTestScope())
+	r.Observe(cohortCanary, time.Now(), nil, fmt.Errorf("failed"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.metrics.SystemErrors.WithLabelValues(cohortCanary)))
+
+	w := newCanaryTestWorkflow("wf")
+	w.Status.Phase = v1alpha1.WorkflowPhaseSuccess
+	r.Observe(cohortBaseline, time.Now(), w, nil)
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.metrics.Terminated.WithLabelValues(cohortBaseline, v1alpha1.WorkflowPhaseSuccess.String())))
+	assert.Equal(t, float64(0), testutil.ToFloat64(r.metrics.SystemErrors.WithLabelValues(cohortBaseline)))
+}
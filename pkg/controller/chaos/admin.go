@@ -0,0 +1,82 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/admin"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+)
+
+type faultyEventSink struct {
+	events.EventSink
+	injector *Injector
+}
+
+func (s faultyEventSink) Sink(ctx context.Context, message proto.Message) error {
+	if err := s.injector.Inject(ctx, FaultAdmin); err != nil {
+		return err
+	}
+
+	return s.EventSink.Sink(ctx, message)
+}
+
+// NewEventSink wraps the event sink so that sending events to admin fails at the configured percentage.
+func NewEventSink(sink events.EventSink, injector *Injector) events.EventSink {
+	return faultyEventSink{
+		EventSink: sink,
+		injector:  injector,
+	}
+}
+
+type faultyLaunchPlanExecutor struct {
+	launchplan.FlyteAdmin
+	injector *Injector
+}
+
+func (l faultyLaunchPlanExecutor) Launch(ctx context.Context, launchCtx launchplan.LaunchContext,
+	executionID *core.WorkflowExecutionIdentifier, launchPlanRef *core.Identifier, inputs *core.LiteralMap) error {
+
+	if err := l.injector.Inject(ctx, FaultAdmin); err != nil {
+		return err
+	}
+
+	return l.FlyteAdmin.Launch(ctx, launchCtx, executionID, launchPlanRef, inputs)
+}
+
+func (l faultyLaunchPlanExecutor) GetStatus(ctx context.Context, executionID *core.WorkflowExecutionIdentifier) (
+	*admin.ExecutionClosure, error) {
+
+	if err := l.injector.Inject(ctx, FaultAdmin); err != nil {
+		return nil, err
+	}
+
+	return l.FlyteAdmin.GetStatus(ctx, executionID)
+}
+
+func (l faultyLaunchPlanExecutor) Kill(ctx context.Context, executionID *core.WorkflowExecutionIdentifier, reason string) error {
+	if err := l.injector.Inject(ctx, FaultAdmin); err != nil {
+		return err
+	}
+
+	return l.FlyteAdmin.Kill(ctx, executionID, reason)
+}
+
+func (l faultyLaunchPlanExecutor) GetLaunchPlan(ctx context.Context, launchPlanRef *core.Identifier) (*admin.LaunchPlan, error) {
+	if err := l.injector.Inject(ctx, FaultAdmin); err != nil {
+		return nil, err
+	}
+
+	return l.FlyteAdmin.GetLaunchPlan(ctx, launchPlanRef)
+}
+
+// NewLaunchPlanExecutor wraps the launch plan executor so that calls to admin fail at the configured percentage.
+func NewLaunchPlanExecutor(executor launchplan.FlyteAdmin, injector *Injector) launchplan.FlyteAdmin {
+	return faultyLaunchPlanExecutor{
+		FlyteAdmin: executor,
+		injector:   injector,
+	}
+}
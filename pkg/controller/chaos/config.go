@@ -0,0 +1,36 @@
+package chaos
+
+import (
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "chaos"
+
+var (
+	defaultConfig = &Config{
+		Enabled: false,
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls fault injection for resilience testing. Every percentage is the probability, from 0 to 100, that an
+// individual call fails. Never enable this in production.
+type Config struct {
+	Enabled                      bool  `json:"enabled" pflag:",Enables injecting faults. Only meant for soak testing in staging clusters."`
+	Seed                         int64 `json:"seed" pflag:",Seed for the random fault generator. 0 seeds it from the current time."`
+	DatastoreWriteFailurePercent int   `json:"datastore-write-failure-percent" pflag:",Percentage of datastore writes that fail."`
+	KubeUpdateFailurePercent     int   `json:"kube-update-failure-percent" pflag:",Percentage of FlyteWorkflow updates that fail."`
+	AdminFailurePercent          int   `json:"admin-failure-percent" pflag:",Percentage of calls to admin (events and launch plans) that fail."`
+	RestartPercent               int   `json:"restart-percent" pflag:",Percentage of evaluation rounds after which propeller behaves as if it restarted before persisting the result."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,42 @@
+package chaos
+
+import (
+	"context"
+	"io"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+)
+
+type faultyRawStore struct {
+	storage.RawStore
+	injector *Injector
+}
+
+func (s faultyRawStore) WriteRaw(ctx context.Context, reference storage.DataReference, size int64, opts storage.Options,
+	raw io.Reader) error {
+
+	if err := s.injector.Inject(ctx, FaultDatastoreWrite); err != nil {
+		return err
+	}
+
+	return s.RawStore.WriteRaw(ctx, reference, size, opts, raw)
+}
+
+func (s faultyRawStore) CopyRaw(ctx context.Context, source, destination storage.DataReference, opts storage.Options) error {
+	if err := s.injector.Inject(ctx, FaultDatastoreWrite); err != nil {
+		return err
+	}
+
+	return s.RawStore.CopyRaw(ctx, source, destination, opts)
+}
+
+// NewDataStore wraps the datastore so that writes fail at the configured percentage. Reads are never affected.
+func NewDataStore(store *storage.DataStore, injector *Injector, scope promutils.Scope) *storage.DataStore {
+	rawStore := faultyRawStore{
+		RawStore: store.ComposedProtobufStore,
+		injector: injector,
+	}
+
+	return storage.NewCompositeDataStore(store.ReferenceConstructor, storage.NewDefaultProtobufStore(rawStore, scope))
+}
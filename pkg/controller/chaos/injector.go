@@ -0,0 +1,101 @@
+// Package chaos injects faults into the datastore, the FlyteWorkflow store, admin calls and workflow evaluation rounds
+// so that propeller's resilience can be soak tested in staging clusters. Every component is a decorator that is only
+// installed when chaos is enabled in the config.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type FaultKind string
+
+const (
+	FaultDatastoreWrite FaultKind = "datastore_write"
+	FaultKubeUpdate     FaultKind = "kube_update"
+	FaultAdmin          FaultKind = "admin"
+	FaultRestart        FaultKind = "restart"
+)
+
+// InjectedFault is the error returned by every call that failed because of an injected fault.
+type InjectedFault struct {
+	Kind FaultKind
+}
+
+func (f *InjectedFault) Error() string {
+	return fmt.Sprintf("chaos: injected [%v] fault", f.Kind)
+}
+
+// IsInjectedFault returns true if the error was produced by the injector.
+func IsInjectedFault(err error) bool {
+	_, ok := err.(*InjectedFault)
+	return ok
+}
+
+// Injector decides, based on the configured percentages, which calls fail. It is safe for concurrent use.
+type Injector struct {
+	cfg      *Config
+	lock     sync.Mutex
+	rand     *rand.Rand
+	injected *prometheus.CounterVec
+}
+
+func (i *Injector) percent(kind FaultKind) int {
+	switch kind {
+	case FaultDatastoreWrite:
+		return i.cfg.DatastoreWriteFailurePercent
+	case FaultKubeUpdate:
+		return i.cfg.KubeUpdateFailurePercent
+	case FaultAdmin:
+		return i.cfg.AdminFailurePercent
+	case FaultRestart:
+		return i.cfg.RestartPercent
+	}
+
+	return 0
+}
+
+// Inject returns an InjectedFault with the configured percentage for the kind of fault and nil otherwise.
+func (i *Injector) Inject(ctx context.Context, kind FaultKind) error {
+	percent := i.percent(kind)
+	if percent <= 0 {
+		return nil
+	}
+
+	i.lock.Lock()
+	hit := i.rand.Intn(100) < percent
+	i.lock.Unlock()
+	if !hit {
+		return nil
+	}
+
+	logger.Warnf(ctx, "Injecting [%v] fault", kind)
+	i.injected.WithLabelValues(string(kind)).Inc()
+	return &InjectedFault{Kind: kind}
+}
+
+// ShouldRestart returns true if the current evaluation round should be discarded as if propeller restarted.
+func (i *Injector) ShouldRestart(ctx context.Context) bool {
+	return i.Inject(ctx, FaultRestart) != nil
+}
+
+func NewInjector(cfg *Config, scope promutils.Scope) *Injector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &Injector{
+		cfg:      cfg,
+		rand:     rand.New(rand.NewSource(seed)), // #nosec
+		injected: scope.MustNewCounterVec("injected_faults", "Number of faults injected by kind", "kind"),
+	}
+}
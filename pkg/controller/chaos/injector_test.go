@@ -0,0 +1,102 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NamespaceKey)
+}
+
+func TestInjector(t *testing.T) {
+	ctx := context.TODO()
+	injector := NewInjector(&Config{
+		Seed:                         1,
+		DatastoreWriteFailurePercent: 100,
+		KubeUpdateFailurePercent:     0,
+		AdminFailurePercent:          50,
+	}, promutils.NewTestScope())
+
+	err := injector.Inject(ctx, FaultDatastoreWrite)
+	assert.True(t, IsInjectedFault(err))
+	assert.Equal(t, FaultDatastoreWrite, err.(*InjectedFault).Kind)
+
+	assert.NoError(t, injector.Inject(ctx, FaultKubeUpdate))
+	assert.False(t, injector.ShouldRestart(ctx))
+
+	failures := 0
+	for i := 0; i < 1000; i++ {
+		if injector.Inject(ctx, FaultAdmin) != nil {
+			failures++
+		}
+	}
+	assert.InDelta(t, 500, failures, 100)
+}
+
+func TestNewDataStore(t *testing.T) {
+	ctx := context.TODO()
+	base, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	assert.NoError(t, base.WriteRaw(ctx, "s3://bucket/existing", 1, storage.Options{}, bytes.NewReader([]byte("a"))))
+
+	store := NewDataStore(base, NewInjector(&Config{Seed: 1, DatastoreWriteFailurePercent: 100}, promutils.NewTestScope()),
+		promutils.NewTestScope())
+
+	err = store.WriteProtobuf(ctx, "s3://bucket/new", storage.Options{}, &core.Identifier{Name: "x"})
+	assert.Error(t, err)
+	assert.True(t, IsInjectedFault(err))
+	assert.True(t, IsInjectedFault(store.CopyRaw(ctx, "s3://bucket/existing", "s3://bucket/copy", storage.Options{})))
+
+	m, err := store.Head(ctx, "s3://bucket/existing")
+	assert.NoError(t, err)
+	assert.True(t, m.Exists())
+}
+
+func TestNewWorkflowStore(t *testing.T) {
+	ctx := context.TODO()
+	base := workflowstore.NewInMemoryWorkflowStore()
+	wf := &v1alpha1.FlyteWorkflow{}
+	wf.Name = "wf"
+	wf.Namespace = "ns"
+	assert.NoError(t, base.Create(ctx, wf))
+
+	store := NewWorkflowStore(base, NewInjector(&Config{Seed: 1, KubeUpdateFailurePercent: 100}, promutils.NewTestScope()))
+	_, err := store.Update(ctx, wf, workflowstore.PriorityClassCritical)
+	assert.True(t, IsInjectedFault(err))
+	_, err = store.UpdateStatus(ctx, wf, workflowstore.PriorityClassCritical)
+	assert.True(t, IsInjectedFault(err))
+
+	_, err = store.Get(ctx, "ns", "wf")
+	assert.NoError(t, err)
+}
+
+func TestAdmin(t *testing.T) {
+	ctx := context.TODO()
+	injector := NewInjector(&Config{Seed: 1, AdminFailurePercent: 100}, promutils.NewTestScope())
+
+	sink := NewEventSink(nil, injector)
+	assert.True(t, IsInjectedFault(sink.Sink(ctx, &event.WorkflowExecutionEvent{})))
+
+	base := &mocks.FlyteAdmin{}
+	base.OnInitializeMatch(mock.Anything).Return(nil)
+	launcher := NewLaunchPlanExecutor(base, injector)
+	assert.NoError(t, launcher.Initialize(ctx))
+	assert.True(t, IsInjectedFault(launcher.Kill(ctx, &core.WorkflowExecutionIdentifier{}, "reason")))
+	_, err := launcher.GetStatus(ctx, &core.WorkflowExecutionIdentifier{})
+	assert.True(t, IsInjectedFault(err))
+}
@@ -0,0 +1,41 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
+)
+
+type faultyWorkflowStore struct {
+	workflowstore.FlyteWorkflow
+	injector *Injector
+}
+
+func (s faultyWorkflowStore) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow,
+	priorityClass workflowstore.PriorityClass) (*v1alpha1.FlyteWorkflow, error) {
+
+	if err := s.injector.Inject(ctx, FaultKubeUpdate); err != nil {
+		return nil, err
+	}
+
+	return s.FlyteWorkflow.UpdateStatus(ctx, workflow, priorityClass)
+}
+
+func (s faultyWorkflowStore) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow,
+	priorityClass workflowstore.PriorityClass) (*v1alpha1.FlyteWorkflow, error) {
+
+	if err := s.injector.Inject(ctx, FaultKubeUpdate); err != nil {
+		return nil, err
+	}
+
+	return s.FlyteWorkflow.Update(ctx, workflow, priorityClass)
+}
+
+// NewWorkflowStore wraps the workflow store so that updates of FlyteWorkflow objects fail at the configured percentage.
+func NewWorkflowStore(store workflowstore.FlyteWorkflow, injector *Injector) workflowstore.FlyteWorkflow {
+	return faultyWorkflowStore{
+		FlyteWorkflow: store,
+		injector:      injector,
+	}
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/profutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// ClusterHealthProbe returns an error if the cluster it probes is not healthy.
+type ClusterHealthProbe func(ctx context.Context) error
+
+// NewKubeHealthProbe probes the health endpoint of the API server the given client talks to.
+func NewKubeHealthProbe(kubeClient kubernetes.Interface) ClusterHealthProbe {
+	return func(ctx context.Context) error {
+		return kubeClient.Discovery().RESTClient().Get().AbsPath("/healthz").Do(ctx).Error()
+	}
+}
+
+type clusterHealthMetrics struct {
+	Healthy       prometheus.Gauge
+	CheckFailures prometheus.Counter
+	CheckLatency  promutils.StopWatch
+}
+
+type clusterTarget struct {
+	name    string
+	probe   ClusterHealthProbe
+	metrics clusterHealthMetrics
+}
+
+// ClusterStatus is the last observed health of a cluster propeller talks to.
+type ClusterStatus struct {
+	Healthy     bool      `json:"healthy"`
+	Error       string    `json:"error,omitempty"`
+	LastChecked time.Time `json:"lastChecked"`
+}
+
+// ClusterHealthChecker periodically probes the clusters propeller talks to, e.g. the control plane cluster that holds
+// the FlyteWorkflow CRDs and the data plane cluster that runs the task pods, and reports their health per cluster in
+// metrics and over http.
+type ClusterHealthChecker struct {
+	interval time.Duration
+	targets  []clusterTarget
+	lock     sync.RWMutex
+	statuses map[string]ClusterStatus
+}
+
+// AddCluster registers a cluster to probe. Names are used as metric scopes and have to be unique.
+func (c *ClusterHealthChecker) AddCluster(name string, probe ClusterHealthProbe, scope promutils.Scope) {
+	clusterScope := scope.NewSubScope(strings.Replace(name, "-", "_", -1))
+	c.targets = append(c.targets, clusterTarget{
+		name:  name,
+		probe: probe,
+		metrics: clusterHealthMetrics{
+			Healthy:       clusterScope.MustNewGauge("healthy", "1 if the last health check of the cluster succeeded, 0 otherwise"),
+			CheckFailures: clusterScope.MustNewCounter("health_check_failures", "Number of failed health checks of the cluster"),
+			CheckLatency:  clusterScope.MustNewStopWatch("health_check_latency", "Latency of health checks of the cluster", time.Millisecond),
+		},
+	})
+}
+
+// Check probes all registered clusters once.
+func (c *ClusterHealthChecker) Check(ctx context.Context) {
+	for _, target := range c.targets {
+		timer := target.metrics.CheckLatency.Start()
+		err := target.probe(ctx)
+		timer.Stop()
+
+		status := ClusterStatus{Healthy: err == nil, LastChecked: time.Now()}
+		if err != nil {
+			logger.Warnf(ctx, "Health check of cluster [%v] failed. Error: %v", target.name, err)
+			status.Error = err.Error()
+			target.metrics.CheckFailures.Inc()
+			target.metrics.Healthy.Set(0)
+		} else {
+			target.metrics.Healthy.Set(1)
+		}
+
+		c.lock.Lock()
+		c.statuses[target.name] = status
+		c.lock.Unlock()
+	}
+}
+
+// Statuses returns the last observed health of every cluster that has been checked at least once.
+func (c *ClusterHealthChecker) Statuses() map[string]ClusterStatus {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	statuses := make(map[string]ClusterStatus, len(c.statuses))
+	for name, status := range c.statuses {
+		statuses[name] = status
+	}
+
+	return statuses
+}
+
+// ServeHTTP responds with the health of all clusters, with status 503 if any of them is unhealthy or not yet checked.
+func (c *ClusterHealthChecker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	statuses := c.Statuses()
+	code := http.StatusOK
+	if len(statuses) < len(c.targets) {
+		code = http.StatusServiceUnavailable
+	}
+
+	for _, status := range statuses {
+		if !status.Healthy {
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	if err := profutils.WriteJSONResponse(w, code, statuses); err != nil {
+		logger.Errorf(context.TODO(), "Failed to write cluster health response. Error: %v", err)
+	}
+}
+
+// Run checks the clusters immediately and then periodically in the background until the context is cancelled.
+func (c *ClusterHealthChecker) Run(ctx context.Context) {
+	checkerCtx := contextutils.WithGoroutineLabel(ctx, "cluster-health-checker")
+	ticker := time.NewTicker(c.interval)
+
+	go func() {
+		pprof.SetGoroutineLabels(checkerCtx)
+		defer ticker.Stop()
+		c.Check(checkerCtx)
+		for {
+			select {
+			case <-checkerCtx.Done():
+				return
+			case <-ticker.C:
+				c.Check(checkerCtx)
+			}
+		}
+	}()
+}
+
+func NewClusterHealthChecker(interval time.Duration) *ClusterHealthChecker {
+	return &ClusterHealthChecker{
+		interval: interval,
+		statuses: map[string]ClusterStatus{},
+	}
+}
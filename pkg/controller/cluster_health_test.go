@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterHealthChecker(t *testing.T) {
+	ctx := context.TODO()
+	scope := promutils.NewTestScope()
+	var dataPlaneErr error
+
+	checker := NewClusterHealthChecker(0)
+	checker.AddCluster("control-plane", func(ctx context.Context) error { return nil }, scope)
+	checker.AddCluster("data-plane", func(ctx context.Context) error { return dataPlaneErr }, scope)
+
+	serve := func() int {
+		w := httptest.NewRecorder()
+		checker.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/clusters", nil))
+		return w.Code
+	}
+
+	// Clusters that have not been checked yet are not considered healthy.
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+
+	checker.Check(ctx)
+	assert.Equal(t, http.StatusOK, serve())
+	assert.True(t, checker.Statuses()["data-plane"].Healthy)
+
+	dataPlaneErr = fmt.Errorf("connection refused")
+	checker.Check(ctx)
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+
+	statuses := checker.Statuses()
+	assert.True(t, statuses["control-plane"].Healthy)
+	assert.False(t, statuses["data-plane"].Healthy)
+	assert.Equal(t, "connection refused", statuses["data-plane"].Error)
+	assert.Equal(t, float64(0), testutil.ToFloat64(checker.targets[1].metrics.Healthy))
+	assert.Equal(t, float64(1), testutil.ToFloat64(checker.targets[1].metrics.CheckFailures))
+}
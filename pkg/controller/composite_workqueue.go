@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -128,6 +128,11 @@ func (b *BatchingWorkQueue) runSubQueueHandler(ctx context.Context) {
 
 }
 
+// SubQueueLen returns the number of items waiting in the sub-queue to be moved to the primary queue.
+func (b *BatchingWorkQueue) SubQueueLen() int {
+	return b.subQueue.Len()
+}
+
 func (b *BatchingWorkQueue) ShutdownAll() {
 	b.subQueue.ShutDown()
 	b.ShutDown()
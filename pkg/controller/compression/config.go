@@ -0,0 +1,45 @@
+package compression
+
+import (
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "compression"
+
+type Algorithm = string
+
+const (
+	// AlgorithmGzip compresses documents with gzip. It is the only algorithm supported for now, the magic bytes of the
+	// format identify compressed documents when reading them.
+	AlgorithmGzip Algorithm = "gzip"
+)
+
+var (
+	defaultConfig = &Config{
+		Enabled:        false,
+		Algorithm:      AlgorithmGzip,
+		ThresholdBytes: 64 * 1024,
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls the compression of futures and compiled workflow closures written by propeller. Compressed documents
+// are read back transparently whether compression is enabled or not, so it can be turned off again at any time. Note
+// that task containers and admin read these documents directly, they have to be able to decompress them for compression
+// to be enabled.
+type Config struct {
+	Enabled        bool      `json:"enabled" pflag:",Enables compressing futures and compiled workflow closures written by propeller."`
+	Algorithm      Algorithm `json:"algorithm" pflag:",Compression algorithm; only gzip is supported."`
+	ThresholdBytes int       `json:"threshold-bytes" pflag:",Minimum size in bytes of a serialized document for it to be compressed."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,177 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Metadata key the encoding of compressed documents is stored under.
+const ContentEncodingKey = "content-encoding"
+
+// The magic bytes of gzip streams. No valid protobuf message starts with them, 0x1f would be a field with the invalid
+// wire type 7, so uncompressed documents are never mistaken for compressed ones.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+type datastoreMetrics struct {
+	CompressedWrites  prometheus.Counter
+	DecompressedReads prometheus.Counter
+	BytesSaved        prometheus.Counter
+}
+
+// compressingProtobufStore compresses futures and compiled workflow closures larger than the threshold when writing
+// them and decompresses all compressed documents when reading them. Documents without the gzip header are read as is.
+type compressingProtobufStore struct {
+	storage.ComposedProtobufStore
+	cfg     Config
+	metrics datastoreMetrics
+}
+
+// Only futures and compiled workflow closures of dynamic nodes grow large enough to be worth compressing.
+func shouldCompress(msg proto.Message) bool {
+	switch msg.(type) {
+	case *core.DynamicJobSpec, *core.CompiledWorkflowClosure:
+		return true
+	}
+
+	return false
+}
+
+// IsCompressed returns true if the document was compressed by the compressing store.
+func IsCompressed(raw []byte) bool {
+	return bytes.HasPrefix(raw, gzipMagic)
+}
+
+// Decompress decompresses a document for which IsCompressed returns true.
+func Decompress(raw []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return ioutil.ReadAll(r)
+}
+
+func compress(raw []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, len(raw)/4))
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(raw); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s compressingProtobufStore) ReadProtobuf(ctx context.Context, reference storage.DataReference, msg proto.Message) error {
+	rc, err := s.ReadRaw(ctx, reference)
+	if err != nil && !storage.IsFailedWriteToCache(err) {
+		return fmt.Errorf("path:%v: %w", reference, err)
+	}
+
+	defer func() {
+		if err := rc.Close(); err != nil {
+			logger.Warnf(ctx, "Failed to close reference [%v]. Error: %v", reference, err)
+		}
+	}()
+
+	raw, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("readAll: %v: %w", reference, err)
+	}
+
+	if IsCompressed(raw) {
+		raw, err = Decompress(raw)
+		if err != nil {
+			return fmt.Errorf("failed to decompress [%v]: %w", reference, err)
+		}
+
+		s.metrics.DecompressedReads.Inc()
+	}
+
+	if err = proto.Unmarshal(raw, msg); err != nil {
+		return fmt.Errorf("unmarshall: %v: %w", reference, err)
+	}
+
+	return nil
+}
+
+func (s compressingProtobufStore) WriteProtobuf(ctx context.Context, reference storage.DataReference, opts storage.Options,
+	msg proto.Message) error {
+
+	if !s.cfg.Enabled || !shouldCompress(msg) {
+		return s.ComposedProtobufStore.WriteProtobuf(ctx, reference, opts, msg)
+	}
+
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if len(raw) < s.cfg.ThresholdBytes {
+		return s.WriteRaw(ctx, reference, int64(len(raw)), opts, bytes.NewReader(raw))
+	}
+
+	compressed, err := compress(raw)
+	if err != nil {
+		return fmt.Errorf("failed to compress [%v]: %w", reference, err)
+	}
+
+	// Documents that don't compress are written as is.
+	if len(compressed) >= len(raw) {
+		return s.WriteRaw(ctx, reference, int64(len(raw)), opts, bytes.NewReader(raw))
+	}
+
+	metadata := make(map[string]interface{}, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		metadata[k] = v
+	}
+
+	metadata[ContentEncodingKey] = AlgorithmGzip
+	err = s.WriteRaw(ctx, reference, int64(len(compressed)), storage.Options{Metadata: metadata}, bytes.NewReader(compressed))
+	if err != nil && !storage.IsFailedWriteToCache(err) {
+		return err
+	}
+
+	s.metrics.CompressedWrites.Inc()
+	s.metrics.BytesSaved.Add(float64(len(raw) - len(compressed)))
+	return nil
+}
+
+// NewDataStore wraps the datastore so that futures and compiled workflow closures above the configured size are
+// compressed if compression is enabled. Compressed documents are decompressed when reading them regardless of the
+// configuration.
+func NewDataStore(cfg *Config, store *storage.DataStore, scope promutils.Scope) (*storage.DataStore, error) {
+	if cfg.Enabled && cfg.Algorithm != AlgorithmGzip {
+		return nil, fmt.Errorf("unsupported compression algorithm [%v], only [%v] is supported", cfg.Algorithm, AlgorithmGzip)
+	}
+
+	protobufStore := compressingProtobufStore{
+		ComposedProtobufStore: store.ComposedProtobufStore,
+		cfg:                   *cfg,
+		metrics: datastoreMetrics{
+			CompressedWrites:  scope.MustNewCounter("compressed_writes", "Number of documents compressed before writing them"),
+			DecompressedReads: scope.MustNewCounter("decompressed_reads", "Number of compressed documents decompressed after reading them"),
+			BytesSaved:        scope.MustNewCounter("compression_bytes_saved", "Number of bytes saved by compressing documents"),
+		},
+	}
+
+	return storage.NewCompositeDataStore(store.ReferenceConstructor, protobufStore), nil
+}
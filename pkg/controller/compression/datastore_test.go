@@ -0,0 +1,114 @@
+package compression
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NamespaceKey)
+}
+
+func newDynamicJobSpec(nodes int) *core.DynamicJobSpec {
+	spec := &core.DynamicJobSpec{MinSuccesses: int64(nodes)}
+	for i := 0; i < nodes; i++ {
+		spec.Nodes = append(spec.Nodes, &core.Node{Id: "dn" + strings.Repeat("0", 20)})
+	}
+
+	return spec
+}
+
+func readRaw(t *testing.T, store *storage.DataStore, reference storage.DataReference) []byte {
+	rc, err := store.ReadRaw(context.TODO(), reference)
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, rc.Close()) }()
+
+	raw, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestCompressingDataStore(t *testing.T) {
+	ctx := context.TODO()
+	base, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	store, err := NewDataStore(&Config{Enabled: true, Algorithm: AlgorithmGzip, ThresholdBytes: 1024}, base,
+		promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	t.Run("compressed", func(t *testing.T) {
+		spec := newDynamicJobSpec(100)
+		assert.NoError(t, store.WriteProtobuf(ctx, "s3://bucket/futures.pb", storage.Options{}, spec))
+
+		raw := readRaw(t, base, "s3://bucket/futures.pb")
+		assert.True(t, IsCompressed(raw))
+		assert.Less(t, len(raw), proto.Size(spec))
+
+		read := &core.DynamicJobSpec{}
+		assert.NoError(t, store.ReadProtobuf(ctx, "s3://bucket/futures.pb", read))
+		assert.True(t, proto.Equal(spec, read))
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		spec := newDynamicJobSpec(1)
+		assert.NoError(t, store.WriteProtobuf(ctx, "s3://bucket/small.pb", storage.Options{}, spec))
+		assert.False(t, IsCompressed(readRaw(t, base, "s3://bucket/small.pb")))
+
+		read := &core.DynamicJobSpec{}
+		assert.NoError(t, base.ReadProtobuf(ctx, "s3://bucket/small.pb", read))
+		assert.True(t, proto.Equal(spec, read))
+	})
+
+	t.Run("uncompressed", func(t *testing.T) {
+		spec := newDynamicJobSpec(100)
+		assert.NoError(t, base.WriteProtobuf(ctx, "s3://bucket/plain.pb", storage.Options{}, spec))
+
+		read := &core.DynamicJobSpec{}
+		assert.NoError(t, store.ReadProtobuf(ctx, "s3://bucket/plain.pb", read))
+		assert.True(t, proto.Equal(spec, read))
+	})
+
+	t.Run("other documents", func(t *testing.T) {
+		errorDoc := &core.ErrorDocument{Error: &core.ContainerError{Message: strings.Repeat("failed", 1000)}}
+		assert.NoError(t, store.WriteProtobuf(ctx, "s3://bucket/error.pb", storage.Options{}, errorDoc))
+		assert.False(t, IsCompressed(readRaw(t, base, "s3://bucket/error.pb")))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled, err := NewDataStore(&Config{Algorithm: "zstd", ThresholdBytes: 1024}, base, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		spec := newDynamicJobSpec(100)
+		assert.NoError(t, disabled.WriteProtobuf(ctx, "s3://bucket/disabled.pb", storage.Options{}, spec))
+		assert.False(t, IsCompressed(readRaw(t, base, "s3://bucket/disabled.pb")))
+
+		// Documents compressed earlier are still read.
+		read := &core.DynamicJobSpec{}
+		assert.NoError(t, disabled.ReadProtobuf(ctx, "s3://bucket/futures.pb", read))
+		assert.True(t, proto.Equal(spec, read))
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		raw := append(append([]byte{}, gzipMagic...), 0, 1, 2)
+		assert.NoError(t, base.WriteRaw(ctx, "s3://bucket/corrupt.pb", int64(len(raw)), storage.Options{}, bytes.NewReader(raw)))
+		assert.Error(t, store.ReadProtobuf(ctx, "s3://bucket/corrupt.pb", &core.DynamicJobSpec{}))
+	})
+}
+
+func TestNewDataStore_UnsupportedAlgorithm(t *testing.T) {
+	base, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	_, err = NewDataStore(&Config{Enabled: true, Algorithm: "zstd"}, base, promutils.NewTestScope())
+	assert.Error(t, err)
+}
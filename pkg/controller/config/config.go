@@ -52,6 +52,9 @@ var (
 		DownstreamEval: config.Duration{
 			Duration: 30 * time.Second,
 		},
+		StalledWorkflowReEval: config.Duration{
+			Duration: time.Minute,
+		},
 		MaxWorkflowRetries: 10,
 		MaxTTLInHours:      23,
 		GCInterval: config.Duration{
@@ -88,6 +91,14 @@ var (
 			RenewDeadline: config.Duration{Duration: time.Second * 10},
 			RetryPeriod:   config.Duration{Duration: time.Second * 2},
 		},
+		MemoryWatchdog: MemoryWatchdogConfig{
+			Enabled:               false,
+			MemoryLimit:           "4Gi",
+			HighWatermarkPercent:  90,
+			LowWatermarkPercent:   75,
+			CheckInterval:         config.Duration{Duration: 10 * time.Second},
+			ReducedWorkersPercent: 50,
+		},
 		NodeConfig: NodeConfig{
 			DefaultDeadlines: DefaultDeadlines{
 				DefaultNodeExecutionDeadline:  config.Duration{Duration: time.Hour * 48},
@@ -96,6 +107,78 @@ var (
 			},
 			MaxNodeRetriesOnSystemFailures: 3,
 			InterruptibleFailureThreshold:  1,
+			OOMRetry: OOMRetryConfig{
+				Enabled:                 false,
+				MemoryMultiplierPercent: 200,
+				MaxMemory:               "64Gi",
+			},
+			OutputInlining: OutputInliningConfig{
+				Enabled:      false,
+				MaxSizeBytes: 1024,
+			},
+			TaskTemplateCacheSize:   1000,
+			MaxParallelTerminations: 10,
+			FanOutHeadroom: FanOutHeadroomConfig{
+				Enabled:        false,
+				MinFanOut:      50,
+				MaxPendingPods: 100,
+			},
+		},
+		DataPlane: DataPlaneConfig{
+			Enabled: false,
+			Name:    "data-plane",
+			KubeConfig: KubeClientConfig{
+				QPS:     100,
+				Burst:   25,
+				Timeout: config.Duration{Duration: 30 * time.Second},
+			},
+			HealthCheckInterval: config.Duration{Duration: 30 * time.Second},
+		},
+		OrphanSweeper: OrphanSweeperConfig{
+			Enabled:   false,
+			Interval:  config.Duration{Duration: 30 * time.Minute},
+			MinAge:    config.Duration{Duration: time.Hour},
+			Resources: []string{"v1/Pod"},
+		},
+		Autoscaling: AutoscalingConfig{
+			Enabled:         false,
+			CollectInterval: config.Duration{Duration: 10 * time.Second},
+		},
+		Canary: CanaryConfig{
+			Enabled:      false,
+			Percent:      5,
+			EventVersion: 0,
+		},
+		CRD: CRDConfig{
+			Validate:         true,
+			AutoRegister:     false,
+			EstablishTimeout: config.Duration{Duration: 30 * time.Second},
+		},
+		LogCapture: LogCaptureConfig{
+			Enabled:      false,
+			Lines:        500,
+			MaxWorkflows: 1000,
+		},
+		Admission: AdmissionConfig{
+			Enabled:              false,
+			Action:               AdmissionActionWarn,
+			MaxNodes:             10000,
+			MaxSizeBytes:         1536 * 1024,
+			NodeStatusBytes:      512,
+			DynamicTaskTypes:     []string{"dynamic-task"},
+			DynamicNodeExpansion: 10,
+		},
+		StatusDiff: StatusDiffConfig{
+			Enabled:      false,
+			Rounds:       4,
+			MaxWorkflows: 1000,
+		},
+		GraphSnapshot: GraphSnapshotConfig{
+			Enabled: false,
+			Format:  GraphSnapshotFormatJSON,
+		},
+		NamespaceMapping: NamespaceMappingConfig{
+			Template: "{{ project }}-{{ domain }}",
 		},
 		MaxStreakLength: 8, // Turbo mode is enabled by default
 		ProfilerPort: config.Port{
@@ -105,6 +188,12 @@ var (
 		MetadataPrefix:      "metadata/propeller",
 		EnableAdminLauncher: true,
 		MetricsPrefix:       "flyte",
+		MetadataStore: MetadataStoreConfig{
+			Middlewares: []string{"tenancy", "chaos", "compression", "encryption"},
+		},
+		SpecChecksum: SpecChecksumConfig{
+			FailOnMismatch: true,
+		},
 	}
 )
 
@@ -112,27 +201,182 @@ var (
 // the base configuration to start propeller
 // NOTE: when adding new fields, do not mark them as "omitempty" if it's desirable to read the value from env variables.
 type Config struct {
-	KubeConfigPath         string               `json:"kube-config" pflag:",Path to kubernetes client config file."`
-	MasterURL              string               `json:"master"`
-	Workers                int                  `json:"workers" pflag:",Number of threads to process workflows"`
-	WorkflowReEval         config.Duration      `json:"workflow-reeval-duration" pflag:",Frequency of re-evaluating workflows"`
-	DownstreamEval         config.Duration      `json:"downstream-eval-duration" pflag:",Frequency of re-evaluating downstream tasks"`
-	LimitNamespace         string               `json:"limit-namespace" pflag:",Namespaces to watch for this propeller"`
-	ProfilerPort           config.Port          `json:"prof-port" pflag:",Profiler port"`
-	MetadataPrefix         string               `json:"metadata-prefix,omitempty" pflag:",MetadataPrefix should be used if all the metadata for Flyte executions should be stored under a specific prefix in CloudStorage. If not specified, the data will be stored in the base container directly."`
-	DefaultRawOutputPrefix string               `json:"rawoutput-prefix" pflag:",a fully qualified storage path of the form s3://flyte/abc/..., where all data sandboxes should be stored."`
-	Queue                  CompositeQueueConfig `json:"queue,omitempty" pflag:",Workflow workqueue configuration, affects the way the work is consumed from the queue."`
-	MetricsPrefix          string               `json:"metrics-prefix" pflag:",An optional prefix for all published metrics."`
-	EnableAdminLauncher    bool                 `json:"enable-admin-launcher" pflag:"Enable remote Workflow launcher to Admin"`
-	MaxWorkflowRetries     int                  `json:"max-workflow-retries" pflag:"Maximum number of retries per workflow"`
-	MaxTTLInHours          int                  `json:"max-ttl-hours" pflag:"Maximum number of hours a completed workflow should be retained. Number between 1-23 hours"`
-	GCInterval             config.Duration      `json:"gc-interval" pflag:"Run periodic GC every 30 minutes"`
-	LeaderElection         LeaderElectionConfig `json:"leader-election,omitempty" pflag:",Config for leader election."`
-	PublishK8sEvents       bool                 `json:"publish-k8s-events" pflag:",Enable events publishing to K8s events API."`
-	MaxDatasetSizeBytes    int64                `json:"max-output-size-bytes" pflag:",Maximum size of outputs per task"`
-	KubeConfig             KubeClientConfig     `json:"kube-client-config" pflag:",Configuration to control the Kubernetes client"`
-	NodeConfig             NodeConfig           `json:"node-config,omitempty" pflag:",config for a workflow node"`
-	MaxStreakLength        int                  `json:"max-streak-length" pflag:",Maximum number of consecutive rounds that one propeller worker can use for one workflow - >1 => turbo-mode is enabled."`
+	KubeConfigPath         string                 `json:"kube-config" pflag:",Path to kubernetes client config file."`
+	MasterURL              string                 `json:"master"`
+	Workers                int                    `json:"workers" pflag:",Number of threads to process workflows"`
+	WorkflowReEval         config.Duration        `json:"workflow-reeval-duration" pflag:",Frequency of re-evaluating workflows"`
+	DownstreamEval         config.Duration        `json:"downstream-eval-duration" pflag:",Frequency of re-evaluating downstream tasks"`
+	StalledWorkflowReEval  config.Duration        `json:"stalled-workflow-reeval-duration" pflag:",Frequency of re-evaluating workflows whose active nodes are all stalled waiting on resource quota or image pulls. 0 disables deprioritization."`
+	LimitNamespace         string                 `json:"limit-namespace" pflag:",Namespaces to watch for this propeller"`
+	ProfilerPort           config.Port            `json:"prof-port" pflag:",Profiler port"`
+	MetadataPrefix         string                 `json:"metadata-prefix,omitempty" pflag:",MetadataPrefix should be used if all the metadata for Flyte executions should be stored under a specific prefix in CloudStorage. If not specified, the data will be stored in the base container directly."`
+	DefaultRawOutputPrefix string                 `json:"rawoutput-prefix" pflag:",a fully qualified storage path of the form s3://flyte/abc/..., where all data sandboxes should be stored."`
+	Queue                  CompositeQueueConfig   `json:"queue,omitempty" pflag:",Workflow workqueue configuration, affects the way the work is consumed from the queue."`
+	MetricsPrefix          string                 `json:"metrics-prefix" pflag:",An optional prefix for all published metrics."`
+	EnableAdminLauncher    bool                   `json:"enable-admin-launcher" pflag:"Enable remote Workflow launcher to Admin"`
+	MaxWorkflowRetries     int                    `json:"max-workflow-retries" pflag:"Maximum number of retries per workflow"`
+	MaxTTLInHours          int                    `json:"max-ttl-hours" pflag:"Maximum number of hours a completed workflow should be retained. Number between 1-23 hours"`
+	GCInterval             config.Duration        `json:"gc-interval" pflag:"Run periodic GC every 30 minutes"`
+	LeaderElection         LeaderElectionConfig   `json:"leader-election,omitempty" pflag:",Config for leader election."`
+	PublishK8sEvents       bool                   `json:"publish-k8s-events" pflag:",Enable events publishing to K8s events API."`
+	MaxDatasetSizeBytes    int64                  `json:"max-output-size-bytes" pflag:",Maximum size of outputs per task"`
+	KubeConfig             KubeClientConfig       `json:"kube-client-config" pflag:",Configuration to control the Kubernetes client"`
+	NodeConfig             NodeConfig             `json:"node-config,omitempty" pflag:",config for a workflow node"`
+	MaxStreakLength        int                    `json:"max-streak-length" pflag:",Maximum number of consecutive rounds that one propeller worker can use for one workflow - >1 => turbo-mode is enabled."`
+	MemoryWatchdog         MemoryWatchdogConfig   `json:"memory-watchdog,omitempty" pflag:",Config for shedding caches and load when propeller nears its memory limit."`
+	DataPlane              DataPlaneConfig        `json:"data-plane,omitempty" pflag:",Config for launching task resources on a remote cluster while watching workflows on this one."`
+	OrphanSweeper          OrphanSweeperConfig    `json:"orphan-sweeper,omitempty" pflag:",Config for deleting task resources whose workflow no longer exists."`
+	Autoscaling            AutoscalingConfig      `json:"autoscaling,omitempty" pflag:",Config for reporting the load of propeller to autoscalers."`
+	Canary                 CanaryConfig           `json:"canary,omitempty" pflag:",Config for evaluating a percentage of workflows with new code paths."`
+	CRD                    CRDConfig              `json:"crd,omitempty" pflag:",Config for checking the FlyteWorkflow CRD when propeller starts."`
+	LogCapture             LogCaptureConfig       `json:"log-capture,omitempty" pflag:",Config for capturing the logs of each workflow and storing them when it fails."`
+	Admission              AdmissionConfig        `json:"admission,omitempty" pflag:",Config for estimating the size of workflows before they start."`
+	StatusDiff             StatusDiffConfig       `json:"status-diff,omitempty" pflag:",Config for diffing the status of workflows across rounds to find fields that flap."`
+	GraphSnapshot          GraphSnapshotConfig    `json:"graph-snapshot,omitempty" pflag:",Config for writing a snapshot of the graph of each workflow to the datastore when its phases change."`
+	NamespaceMapping       NamespaceMappingConfig `json:"namespace-mapping,omitempty" pflag:",Config for mapping the project and domain of executions to their namespace."`
+	MetadataStore          MetadataStoreConfig    `json:"metadata-store,omitempty" pflag:",Config for the middlewares the metadata store is wrapped with."`
+	SpecChecksum           SpecChecksumConfig     `json:"spec-checksum,omitempty" pflag:",Config for workflows whose spec no longer matches its checksum."`
+}
+
+// SpecChecksumConfig controls what happens to workflows whose spec was modified after they were created, i.e. that no
+// longer match their spec checksum. They are failed with a warning event and a metric, unless configured to only warn,
+// in which case each modification is reported once.
+type SpecChecksumConfig struct {
+	FailOnMismatch bool `json:"fail-on-mismatch" pflag:",Fails workflows whose spec no longer matches its checksum instead of only warning about them."`
+}
+
+type AdmissionAction = string
+
+const (
+	// AdmissionActionWarn logs and counts the workflows over the thresholds, but runs them.
+	AdmissionActionWarn AdmissionAction = "warn"
+	// AdmissionActionReject fails the workflows over the thresholds before they start.
+	AdmissionActionReject AdmissionAction = "reject"
+)
+
+// AdmissionConfig controls the estimate of the size of workflows before they start, so that workflows that would exceed
+// the size etcd can store while they execute are failed up front, instead of after some of their nodes ran. The size is
+// estimated as the size of the FlyteWorkflow, plus NodeStatusBytes for every node it executes, nodes that run dynamic
+// tasks counting as DynamicNodeExpansion more nodes.
+type AdmissionConfig struct {
+	Enabled              bool            `json:"enabled" pflag:",Enables estimating the size of workflows before they start."`
+	Action               AdmissionAction `json:"action" pflag:",What to do with workflows over the thresholds, one of warn or reject."`
+	MaxNodes             int             `json:"max-nodes" pflag:",Maximum number of nodes a workflow executes, including those of its subworkflows. 0 disables the threshold."`
+	MaxSizeBytes         int             `json:"max-size-bytes" pflag:",Maximum size a workflow is estimated to grow to while it executes. 0 disables the threshold."`
+	NodeStatusBytes      int             `json:"node-status-bytes" pflag:",Estimated size of the status of a node once it ran."`
+	DynamicTaskTypes     []string        `json:"dynamic-task-types" pflag:",Task types that are dynamic, i.e. that expand into more nodes when they run."`
+	DynamicNodeExpansion int             `json:"dynamic-node-expansion" pflag:",Number of nodes a node that runs a dynamic task is expected to expand into."`
+}
+
+// LogCaptureConfig controls the in-memory capture of the last lines propeller logged, at any level, while evaluating each
+// workflow. The lines of a workflow that fails are written to controller_logs.txt in its data dir.
+type LogCaptureConfig struct {
+	Enabled      bool `json:"enabled" pflag:",Enables capturing the logs of each workflow."`
+	Lines        int  `json:"lines" pflag:",Number of the last log lines captured for each workflow."`
+	MaxWorkflows int  `json:"max-workflows" pflag:",Maximum number of workflows whose logs are captured, the least recently evaluated ones lose their captured logs."`
+}
+
+// StatusDiffConfig controls a debug mode that diffs the status of each workflow before and after every round, and
+// reports the fields that go back to a value they had in one of the previous rounds. Handlers that do not converge make
+// fields flap between values, and keep the workflow busy without progress.
+type StatusDiffConfig struct {
+	Enabled      bool `json:"enabled" pflag:",Enables diffing the status of workflows across rounds. It is expensive, use it for debugging only."`
+	Rounds       int  `json:"rounds" pflag:",Number of previous rounds a field is compared against to find if it flaps."`
+	MaxWorkflows int  `json:"max-workflows" pflag:",Maximum number of workflows whose previous rounds are kept, the least recently evaluated ones lose theirs."`
+}
+
+type GraphSnapshotFormat = string
+
+const (
+	// GraphSnapshotFormatJSON writes the nodes, edges and phases of the workflow as a JSON object to graph.json.
+	GraphSnapshotFormatJSON GraphSnapshotFormat = "json"
+	// GraphSnapshotFormatDOT writes the graph of the workflow in the GraphViz DOT language to graph.dot.
+	GraphSnapshotFormatDOT GraphSnapshotFormat = "dot"
+)
+
+// GraphSnapshotConfig controls the snapshot of the graph of each workflow, its nodes, the edges between them and their
+// current phases, written to the data dir of the workflow whenever one of the phases changes. The location of the
+// snapshot is recorded in the status of the workflow, so that external UIs can render live graphs without parsing the
+// FlyteWorkflow.
+type GraphSnapshotConfig struct {
+	Enabled bool                `json:"enabled" pflag:",Enables writing graph snapshots of workflows."`
+	Format  GraphSnapshotFormat `json:"format" pflag:",Format of the graph snapshots; one of json or dot."`
+}
+
+// MetadataStoreConfig controls which middlewares the metadata store is wrapped with and in which order. Each middleware
+// adds a cross-cutting feature to all reads and writes, and is only applied if its own config enables it.
+type MetadataStoreConfig struct {
+	// The first middleware is the innermost one. Middlewares that transform protobufs, like compression and encryption,
+	// have to come after the ones that only wrap raw reads and writes, like tenancy, chaos and metrics, propeller refuses
+	// to start otherwise.
+	Middlewares []string `json:"middlewares" pflag:",Middlewares to wrap the metadata store with from the innermost to the outermost; any of tenancy; chaos; metrics; compression and encryption."`
+}
+
+// NamespaceMappingConfig controls how propeller derives the namespace of the executions of a project and domain, where
+// it needs to, e.g. to find the secrets of tenants. It must match the namespace mapping of admin, which creates the
+// workflows, and thus their pods, in that namespace.
+type NamespaceMappingConfig struct {
+	Template string `json:"template" pflag:",Template of the namespace of the executions of a project and domain; e.g. {{ project }}-{{ domain }}; flyte-{{ domain }} or a static namespace."`
+}
+
+// CRDConfig controls the check of the FlyteWorkflow CRD propeller runs when it starts, so that it exits with an
+// actionable error if the CRD is missing or outdated, instead of failing to list and watch workflows.
+type CRDConfig struct {
+	Validate         bool            `json:"validate" pflag:",Checks that the FlyteWorkflow CRD is installed and serves the version propeller uses before starting."`
+	AutoRegister     bool            `json:"auto-register" pflag:",Installs the FlyteWorkflow CRD, or adds the version propeller uses to it, if it is missing. Requires permissions to manage CRDs."`
+	EstablishTimeout config.Duration `json:"establish-timeout" pflag:",Time to wait for the FlyteWorkflow CRD to be established."`
+}
+
+// CanaryConfig routes a percentage of workflows, picked by a hash of their execution id, through new code paths so that
+// big changes can be rolled out safely, by comparing the metrics of the canary workflows to those of the baseline ones.
+// Workflows are assigned to a cohort when they start and keep it, even if the percentage changes afterwards.
+type CanaryConfig struct {
+	Enabled      bool `json:"enabled" pflag:",Enables evaluating a percentage of workflows with the canary code paths."`
+	Percent      int  `json:"percent" pflag:",Percentage of the new workflows that are canaries."`
+	EventVersion int  `json:"event-version" pflag:",Event version canary workflows record their events with, unless theirs is higher. 0 keeps the event version of the workflow."`
+}
+
+// AutoscalingConfig controls how propeller reports its load, i.e. its queue depth, round latency and free workers, so
+// that its replicas or shards can be scaled on it. The load is always reported in metrics. When enabled, it is also
+// served as json on the /autoscaling path of the profiler port, which the KEDA metrics-api scaler can poll.
+type AutoscalingConfig struct {
+	Enabled         bool            `json:"enabled" pflag:",Serves the load of propeller on the /autoscaling path of the profiler port."`
+	CollectInterval config.Duration `json:"collect-interval" pflag:",How often the load of propeller is collected."`
+}
+
+// OrphanSweeperConfig controls the periodic deletion of task resources, e.g. pods, whose FlyteWorkflow no longer exists.
+// Such resources are left behind when a workflow is force deleted or its finalizer fails. Only resources owned by a
+// FlyteWorkflow through their owner reference are swept.
+type OrphanSweeperConfig struct {
+	Enabled   bool            `json:"enabled" pflag:",Enables the orphaned task resource sweeper."`
+	Interval  config.Duration `json:"interval" pflag:",How often orphaned task resources are swept."`
+	MinAge    config.Duration `json:"min-age" pflag:",Minimum age of a task resource before it can be deleted as an orphan."`
+	Resources []string        `json:"resources" pflag:",Kinds of task resources to sweep, formatted as <apiVersion>/<kind>, e.g. v1/Pod or kubeflow.org/v1/PyTorchJob."`
+	DryRun    bool            `json:"dry-run" pflag:",Only logs and counts orphaned task resources instead of deleting them."`
+}
+
+// DataPlaneConfig configures a remote cluster in which propeller launches the Kubernetes resources of tasks, e.g. pods,
+// while the FlyteWorkflow CRDs keep being watched on the cluster configured by KubeConfigPath (the control plane).
+// Task resources in the data plane carry no owner references to their workflows, so updates to them do not trigger an
+// early re-evaluation of their workflows, which are re-evaluated every DownstreamEval instead.
+type DataPlaneConfig struct {
+	Enabled             bool             `json:"enabled" pflag:",Enables launching task resources on the data plane cluster."`
+	Name                string           `json:"name" pflag:",Name of the data plane cluster, used in logs and metrics."`
+	KubeConfigPath      string           `json:"kube-config" pflag:",Path to the kubernetes client config file of the data plane cluster."`
+	MasterURL           string           `json:"master" pflag:",Master URL of the data plane cluster, overrides the one in the client config file."`
+	KubeConfig          KubeClientConfig `json:"kube-client-config" pflag:",Configuration to control the Kubernetes client of the data plane cluster."`
+	HealthCheckInterval config.Duration  `json:"health-check-interval" pflag:",How often the control plane and data plane clusters are checked for health."`
+}
+
+// MemoryWatchdogConfig controls how propeller sheds its caches and reduces its concurrency when its memory usage nears
+// the configured limit, instead of getting OOMKilled.
+type MemoryWatchdogConfig struct {
+	Enabled               bool            `json:"enabled" pflag:",Enables the memory watchdog."`
+	MemoryLimit           string          `json:"memory-limit" pflag:",Memory limit of propeller, usually the memory limit of its container."`
+	HighWatermarkPercent  int             `json:"high-watermark-percent" pflag:",Percentage of the memory limit above which caches are shed and the number of active workers is reduced."`
+	LowWatermarkPercent   int             `json:"low-watermark-percent" pflag:",Percentage of the memory limit below which all workers are active again."`
+	CheckInterval         config.Duration `json:"check-interval" pflag:",How often the memory usage is checked."`
+	ReducedWorkersPercent int             `json:"reduced-workers-percent" pflag:",Percentage of the workers that stay active while the memory usage is high."`
 }
 
 // KubeClientConfig contains the configuration used by flytepropeller to configure its internal Kubernetes Client.
@@ -184,9 +428,46 @@ type WorkqueueConfig struct {
 
 // NodeConfig contains configuration that is useful for every node execution
 type NodeConfig struct {
-	DefaultDeadlines               DefaultDeadlines `json:"default-deadlines,omitempty" pflag:",Default value for timeouts"`
-	MaxNodeRetriesOnSystemFailures int64            `json:"max-node-retries-system-failures" pflag:"2,Maximum number of retries per node for node failure due to infra issues"`
-	InterruptibleFailureThreshold  int64            `json:"interruptible-failure-threshold" pflag:"1,number of failures for a node to be still considered interruptible'"`
+	DefaultDeadlines               DefaultDeadlines     `json:"default-deadlines,omitempty" pflag:",Default value for timeouts"`
+	MaxNodeRetriesOnSystemFailures int64                `json:"max-node-retries-system-failures" pflag:"2,Maximum number of retries per node for node failure due to infra issues"`
+	InterruptibleFailureThreshold  int64                `json:"interruptible-failure-threshold" pflag:"1,number of failures for a node to be still considered interruptible'"`
+	ResourceUsageAccounting        bool                 `json:"resource-usage-accounting" pflag:",Records requested resources multiplied by runtime for every task node attempt in the workflow status."`
+	OOMRetry                       OOMRetryConfig       `json:"oom-retry,omitempty" pflag:",Config for escalating the memory of task node attempts that follow an OOMKilled attempt."`
+	OutputInlining                 OutputInliningConfig `json:"output-inlining,omitempty" pflag:",Config for inlining small task node outputs into the node status."`
+	TaskTemplateCacheSize          int                  `json:"task-template-cache-size" pflag:",Number of task templates offloaded from workflows to keep in memory."`
+	ZeroCopyInputs                 bool                 `json:"zero-copy-inputs" pflag:",Lets task nodes whose inputs are the unchanged outputs of an upstream task node read them from the outputs of that node instead of a copy."`
+	MaxParallelTerminations        int                  `json:"max-parallel-terminations" pflag:",Maximum number of nodes of a workflow to abort or finalize concurrently."`
+	FanOutHeadroom                 FanOutHeadroomConfig `json:"fan-out-headroom,omitempty" pflag:",Config for staggering the launch of the sub-nodes of large dynamic nodes when the namespace lacks headroom."`
+}
+
+// FanOutHeadroomConfig controls whether dynamic nodes check the capacity of the namespace they run in before launching
+// their task sub-nodes. A dynamic node that is short of headroom only launches as many sub-nodes as the namespace can
+// take, i.e. until the pending pods reach MaxPendingPods or a resource quota runs out of pods, and launches the rest in
+// later rounds.
+type FanOutHeadroomConfig struct {
+	Enabled        bool `json:"enabled" pflag:",Enables checking the headroom of the namespace before launching the sub-nodes of dynamic nodes."`
+	MinFanOut      int  `json:"min-fan-out" pflag:",Minimum number of task sub-nodes for a dynamic node to be checked for headroom."`
+	MaxPendingPods int  `json:"max-pending-pods" pflag:",Number of pending pods in the namespace at which dynamic nodes stop launching sub-nodes."`
+}
+
+// OutputInliningConfig controls which task node outputs are stored in the node status, so that downstream nodes can
+// resolve them without reading them from the datastore. Outputs are inlined as a whole, unless thresholds are configured
+// per literal type, in which case each output is inlined if it is no larger than the threshold of its type, e.g. to
+// inline primitives but never blobs or schemas.
+type OutputInliningConfig struct {
+	Enabled      bool  `json:"enabled" pflag:",Enables inlining task node outputs into the node status."`
+	MaxSizeBytes int64 `json:"max-size-bytes" pflag:",Maximum size of the serialized outputs to inline. Larger outputs are only stored in the datastore."`
+	// Maximum size of a serialized output to inline, per literal type: primitive, blob, schema, binary, generic, none,
+	// error, collection or map. Outputs of types without a threshold are inlined up to MaxSizeBytes, a negative threshold
+	// never inlines outputs of the type.
+	MaxSizeBytesPerType map[string]int64 `json:"max-size-bytes-per-type" pflag:"-,Maximum size of a serialized output to inline, per literal type."`
+}
+
+// OOMRetryConfig controls how the memory requests of a task node are escalated after an attempt was OOMKilled.
+type OOMRetryConfig struct {
+	Enabled                 bool   `json:"enabled" pflag:",Enables escalating the memory of the next attempt after an OOMKilled attempt."`
+	MemoryMultiplierPercent int64  `json:"memory-multiplier-percent" pflag:",Percentage of the memory requests and limits of the previous attempt to use for the next attempt, e.g. 200 doubles the memory."`
+	MaxMemory               string `json:"max-memory" pflag:",Upper bound for escalated memory requests and limits."`
 }
 
 // DefaultDeadlines contains default values for timeouts
@@ -55,6 +55,7 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "workers"), defaultConfig.Workers, "Number of threads to process workflows")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workflow-reeval-duration"), defaultConfig.WorkflowReEval.String(), "Frequency of re-evaluating workflows")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "downstream-eval-duration"), defaultConfig.DownstreamEval.String(), "Frequency of re-evaluating downstream tasks")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "stalled-workflow-reeval-duration"), defaultConfig.StalledWorkflowReEval.String(), "Frequency of re-evaluating workflows whose active nodes are all stalled waiting on resource quota or image pulls. 0 disables deprioritization.")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "limit-namespace"), defaultConfig.LimitNamespace, "Namespaces to watch for this propeller")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "prof-port"), defaultConfig.ProfilerPort.String(), "Profiler port")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "metadata-prefix"), defaultConfig.MetadataPrefix, "MetadataPrefix should be used if all the metadata for Flyte executions should be stored under a specific prefix in CloudStorage. If not specified,  the data will be stored in the base container directly.")
@@ -92,6 +93,62 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "node-config.default-deadlines.workflow-active-deadline"), defaultConfig.NodeConfig.DefaultDeadlines.DefaultWorkflowActiveDeadline.String(), "Default value of workflow timeout")
 	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "node-config.max-node-retries-system-failures"), defaultConfig.NodeConfig.MaxNodeRetriesOnSystemFailures, "Maximum number of retries per node for node failure due to infra issues")
 	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "node-config.interruptible-failure-threshold"), defaultConfig.NodeConfig.InterruptibleFailureThreshold, "number of failures for a node to be still considered interruptible'")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "node-config.resource-usage-accounting"), defaultConfig.NodeConfig.ResourceUsageAccounting, "Records requested resources multiplied by runtime for every task node attempt in the workflow status.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "node-config.oom-retry.enabled"), defaultConfig.NodeConfig.OOMRetry.Enabled, "Enables escalating the memory of the next attempt after an OOMKilled attempt.")
+	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "node-config.oom-retry.memory-multiplier-percent"), defaultConfig.NodeConfig.OOMRetry.MemoryMultiplierPercent, "Percentage of the memory requests and limits of the previous attempt to use for the next attempt,  e.g. 200 doubles the memory.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "node-config.oom-retry.max-memory"), defaultConfig.NodeConfig.OOMRetry.MaxMemory, "Upper bound for escalated memory requests and limits.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "node-config.output-inlining.enabled"), defaultConfig.NodeConfig.OutputInlining.Enabled, "Enables inlining task node outputs into the node status.")
+	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "node-config.output-inlining.max-size-bytes"), defaultConfig.NodeConfig.OutputInlining.MaxSizeBytes, "Maximum size of the serialized outputs to inline. Larger outputs are only stored in the datastore.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "node-config.task-template-cache-size"), defaultConfig.NodeConfig.TaskTemplateCacheSize, "Number of task templates offloaded from workflows to keep in memory.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "node-config.zero-copy-inputs"), defaultConfig.NodeConfig.ZeroCopyInputs, "Lets task nodes whose inputs are the unchanged outputs of an upstream task node read them from the outputs of that node instead of a copy.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "node-config.max-parallel-terminations"), defaultConfig.NodeConfig.MaxParallelTerminations, "Maximum number of nodes of a workflow to abort or finalize concurrently.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "node-config.fan-out-headroom.enabled"), defaultConfig.NodeConfig.FanOutHeadroom.Enabled, "Enables checking the headroom of the namespace before launching the sub-nodes of dynamic nodes.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "node-config.fan-out-headroom.min-fan-out"), defaultConfig.NodeConfig.FanOutHeadroom.MinFanOut, "Minimum number of task sub-nodes for a dynamic node to be checked for headroom.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "node-config.fan-out-headroom.max-pending-pods"), defaultConfig.NodeConfig.FanOutHeadroom.MaxPendingPods, "Number of pending pods in the namespace at which dynamic nodes stop launching sub-nodes.")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "max-streak-length"), defaultConfig.MaxStreakLength, "Maximum number of consecutive rounds that one propeller worker can use for one workflow - >1 => turbo-mode is enabled.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "memory-watchdog.enabled"), defaultConfig.MemoryWatchdog.Enabled, "Enables the memory watchdog.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "memory-watchdog.memory-limit"), defaultConfig.MemoryWatchdog.MemoryLimit, "Memory limit of propeller,  usually the memory limit of its container.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "memory-watchdog.high-watermark-percent"), defaultConfig.MemoryWatchdog.HighWatermarkPercent, "Percentage of the memory limit above which caches are shed and the number of active workers is reduced.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "memory-watchdog.low-watermark-percent"), defaultConfig.MemoryWatchdog.LowWatermarkPercent, "Percentage of the memory limit below which all workers are active again.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "memory-watchdog.check-interval"), defaultConfig.MemoryWatchdog.CheckInterval.String(), "How often the memory usage is checked.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "memory-watchdog.reduced-workers-percent"), defaultConfig.MemoryWatchdog.ReducedWorkersPercent, "Percentage of the workers that stay active while the memory usage is high.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "data-plane.enabled"), defaultConfig.DataPlane.Enabled, "Enables launching task resources on the data plane cluster.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "data-plane.name"), defaultConfig.DataPlane.Name, "Name of the data plane cluster,  used in logs and metrics.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "data-plane.kube-config"), defaultConfig.DataPlane.KubeConfigPath, "Path to the kubernetes client config file of the data plane cluster.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "data-plane.master"), defaultConfig.DataPlane.MasterURL, "Master URL of the data plane cluster,  overrides the one in the client config file.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "data-plane.kube-client-config.burst"), defaultConfig.DataPlane.KubeConfig.Burst, "Max burst rate for throttle. 0 defaults to 10")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "data-plane.kube-client-config.timeout"), defaultConfig.DataPlane.KubeConfig.Timeout.String(), "Max duration allowed for every request to KubeAPI before giving up. 0 implies no timeout.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "data-plane.health-check-interval"), defaultConfig.DataPlane.HealthCheckInterval.String(), "How often the control plane and data plane clusters are checked for health.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "orphan-sweeper.enabled"), defaultConfig.OrphanSweeper.Enabled, "Enables the orphaned task resource sweeper.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "orphan-sweeper.interval"), defaultConfig.OrphanSweeper.Interval.String(), "How often orphaned task resources are swept.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "orphan-sweeper.min-age"), defaultConfig.OrphanSweeper.MinAge.String(), "Minimum age of a task resource before it can be deleted as an orphan.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "orphan-sweeper.resources"), []string{}, "Kinds of task resources to sweep,  formatted as <apiVersion>/<kind>,  e.g. v1/Pod or kubeflow.org/v1/PyTorchJob.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "orphan-sweeper.dry-run"), defaultConfig.OrphanSweeper.DryRun, "Only logs and counts orphaned task resources instead of deleting them.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "autoscaling.enabled"), defaultConfig.Autoscaling.Enabled, "Serves the load of propeller on the /autoscaling path of the profiler port.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "autoscaling.collect-interval"), defaultConfig.Autoscaling.CollectInterval.String(), "How often the load of propeller is collected.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "canary.enabled"), defaultConfig.Canary.Enabled, "Enables evaluating a percentage of workflows with the canary code paths.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "canary.percent"), defaultConfig.Canary.Percent, "Percentage of the new workflows that are canaries.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "canary.event-version"), defaultConfig.Canary.EventVersion, "Event version canary workflows record their events with,  unless theirs is higher. 0 keeps the event version of the workflow.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "crd.validate"), defaultConfig.CRD.Validate, "Checks that the FlyteWorkflow CRD is installed and serves the version propeller uses before starting.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "crd.auto-register"), defaultConfig.CRD.AutoRegister, "Installs the FlyteWorkflow CRD,  or adds the version propeller uses to it,  if it is missing. Requires permissions to manage CRDs.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "crd.establish-timeout"), defaultConfig.CRD.EstablishTimeout.String(), "Time to wait for the FlyteWorkflow CRD to be established.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "log-capture.enabled"), defaultConfig.LogCapture.Enabled, "Enables capturing the logs of each workflow.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "log-capture.lines"), defaultConfig.LogCapture.Lines, "Number of the last log lines captured for each workflow.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "log-capture.max-workflows"), defaultConfig.LogCapture.MaxWorkflows, "Maximum number of workflows whose logs are captured,  the least recently evaluated ones lose their captured logs.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "admission.enabled"), defaultConfig.Admission.Enabled, "Enables estimating the size of workflows before they start.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "admission.action"), defaultConfig.Admission.Action, "What to do with workflows over the thresholds,  one of warn or reject.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "admission.max-nodes"), defaultConfig.Admission.MaxNodes, "Maximum number of nodes a workflow executes,  including those of its subworkflows. 0 disables the threshold.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "admission.max-size-bytes"), defaultConfig.Admission.MaxSizeBytes, "Maximum size a workflow is estimated to grow to while it executes. 0 disables the threshold.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "admission.node-status-bytes"), defaultConfig.Admission.NodeStatusBytes, "Estimated size of the status of a node once it ran.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "admission.dynamic-task-types"), []string{}, "Task types that are dynamic,  i.e. that expand into more nodes when they run.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "admission.dynamic-node-expansion"), defaultConfig.Admission.DynamicNodeExpansion, "Number of nodes a node that runs a dynamic task is expected to expand into.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "status-diff.enabled"), defaultConfig.StatusDiff.Enabled, "Enables diffing the status of workflows across rounds. It is expensive,  use it for debugging only.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "status-diff.rounds"), defaultConfig.StatusDiff.Rounds, "Number of previous rounds a field is compared against to find if it flaps.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "status-diff.max-workflows"), defaultConfig.StatusDiff.MaxWorkflows, "Maximum number of workflows whose previous rounds are kept,  the least recently evaluated ones lose theirs.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "graph-snapshot.enabled"), defaultConfig.GraphSnapshot.Enabled, "Enables writing graph snapshots of workflows.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "graph-snapshot.format"), defaultConfig.GraphSnapshot.Format, "Format of the graph snapshots; one of json or dot.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "namespace-mapping.template"), defaultConfig.NamespaceMapping.Template, "Template of the namespace of the executions of a project and domain; e.g. {{ project }}-{{ domain }}; flyte-{{ domain }} or a static namespace.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "metadata-store.middlewares"), []string{}, "Middlewares to wrap the metadata store with from the innermost to the outermost; any of tenancy; chaos; metrics; compression and encryption.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "spec-checksum.fail-on-mismatch"), defaultConfig.SpecChecksum.FailOnMismatch, "Fails workflows whose spec no longer matches its checksum instead of only warning about them.")
 	return cmdFlags
 }
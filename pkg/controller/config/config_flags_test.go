@@ -169,6 +169,20 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_stalled-workflow-reeval-duration", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.StalledWorkflowReEval.String()
+
+			cmdFlags.Set("stalled-workflow-reeval-duration", testValue)
+			if vString, err := cmdFlags.GetString("stalled-workflow-reeval-duration"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.StalledWorkflowReEval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 	t.Run("Test_limit-namespace", func(t *testing.T) {
 
 		t.Run("Override", func(t *testing.T) {
@@ -687,6 +701,174 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_node-config.resource-usage-accounting", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.resource-usage-accounting", testValue)
+			if vBool, err := cmdFlags.GetBool("node-config.resource-usage-accounting"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.NodeConfig.ResourceUsageAccounting)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.oom-retry.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.oom-retry.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("node-config.oom-retry.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.NodeConfig.OOMRetry.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.oom-retry.memory-multiplier-percent", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.oom-retry.memory-multiplier-percent", testValue)
+			if vInt64, err := cmdFlags.GetInt64("node-config.oom-retry.memory-multiplier-percent"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt64), &actual.NodeConfig.OOMRetry.MemoryMultiplierPercent)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.oom-retry.max-memory", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.oom-retry.max-memory", testValue)
+			if vString, err := cmdFlags.GetString("node-config.oom-retry.max-memory"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.NodeConfig.OOMRetry.MaxMemory)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.output-inlining.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.output-inlining.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("node-config.output-inlining.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.NodeConfig.OutputInlining.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.output-inlining.max-size-bytes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.output-inlining.max-size-bytes", testValue)
+			if vInt64, err := cmdFlags.GetInt64("node-config.output-inlining.max-size-bytes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt64), &actual.NodeConfig.OutputInlining.MaxSizeBytes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.task-template-cache-size", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.task-template-cache-size", testValue)
+			if vInt, err := cmdFlags.GetInt("node-config.task-template-cache-size"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.NodeConfig.TaskTemplateCacheSize)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.zero-copy-inputs", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.zero-copy-inputs", testValue)
+			if vBool, err := cmdFlags.GetBool("node-config.zero-copy-inputs"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.NodeConfig.ZeroCopyInputs)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.max-parallel-terminations", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.max-parallel-terminations", testValue)
+			if vInt, err := cmdFlags.GetInt("node-config.max-parallel-terminations"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.NodeConfig.MaxParallelTerminations)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.fan-out-headroom.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.fan-out-headroom.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("node-config.fan-out-headroom.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.NodeConfig.FanOutHeadroom.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.fan-out-headroom.min-fan-out", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.fan-out-headroom.min-fan-out", testValue)
+			if vInt, err := cmdFlags.GetInt("node-config.fan-out-headroom.min-fan-out"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.NodeConfig.FanOutHeadroom.MinFanOut)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-config.fan-out-headroom.max-pending-pods", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-config.fan-out-headroom.max-pending-pods", testValue)
+			if vInt, err := cmdFlags.GetInt("node-config.fan-out-headroom.max-pending-pods"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.NodeConfig.FanOutHeadroom.MaxPendingPods)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 	t.Run("Test_max-streak-length", func(t *testing.T) {
 
 		t.Run("Override", func(t *testing.T) {
@@ -701,4 +883,620 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_memory-watchdog.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("memory-watchdog.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("memory-watchdog.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.MemoryWatchdog.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_memory-watchdog.memory-limit", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("memory-watchdog.memory-limit", testValue)
+			if vString, err := cmdFlags.GetString("memory-watchdog.memory-limit"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.MemoryWatchdog.MemoryLimit)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_memory-watchdog.high-watermark-percent", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("memory-watchdog.high-watermark-percent", testValue)
+			if vInt, err := cmdFlags.GetInt("memory-watchdog.high-watermark-percent"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.MemoryWatchdog.HighWatermarkPercent)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_memory-watchdog.low-watermark-percent", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("memory-watchdog.low-watermark-percent", testValue)
+			if vInt, err := cmdFlags.GetInt("memory-watchdog.low-watermark-percent"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.MemoryWatchdog.LowWatermarkPercent)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_memory-watchdog.check-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.MemoryWatchdog.CheckInterval.String()
+
+			cmdFlags.Set("memory-watchdog.check-interval", testValue)
+			if vString, err := cmdFlags.GetString("memory-watchdog.check-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.MemoryWatchdog.CheckInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_memory-watchdog.reduced-workers-percent", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("memory-watchdog.reduced-workers-percent", testValue)
+			if vInt, err := cmdFlags.GetInt("memory-watchdog.reduced-workers-percent"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.MemoryWatchdog.ReducedWorkersPercent)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_data-plane.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("data-plane.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("data-plane.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.DataPlane.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_data-plane.name", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("data-plane.name", testValue)
+			if vString, err := cmdFlags.GetString("data-plane.name"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.DataPlane.Name)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_data-plane.kube-config", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("data-plane.kube-config", testValue)
+			if vString, err := cmdFlags.GetString("data-plane.kube-config"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.DataPlane.KubeConfigPath)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_data-plane.master", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("data-plane.master", testValue)
+			if vString, err := cmdFlags.GetString("data-plane.master"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.DataPlane.MasterURL)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_data-plane.kube-client-config.burst", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("data-plane.kube-client-config.burst", testValue)
+			if vInt, err := cmdFlags.GetInt("data-plane.kube-client-config.burst"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.DataPlane.KubeConfig.Burst)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_data-plane.kube-client-config.timeout", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.DataPlane.KubeConfig.Timeout.String()
+
+			cmdFlags.Set("data-plane.kube-client-config.timeout", testValue)
+			if vString, err := cmdFlags.GetString("data-plane.kube-client-config.timeout"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.DataPlane.KubeConfig.Timeout)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_data-plane.health-check-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.DataPlane.HealthCheckInterval.String()
+
+			cmdFlags.Set("data-plane.health-check-interval", testValue)
+			if vString, err := cmdFlags.GetString("data-plane.health-check-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.DataPlane.HealthCheckInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_orphan-sweeper.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("orphan-sweeper.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("orphan-sweeper.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.OrphanSweeper.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_orphan-sweeper.interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.OrphanSweeper.Interval.String()
+
+			cmdFlags.Set("orphan-sweeper.interval", testValue)
+			if vString, err := cmdFlags.GetString("orphan-sweeper.interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.OrphanSweeper.Interval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_orphan-sweeper.min-age", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.OrphanSweeper.MinAge.String()
+
+			cmdFlags.Set("orphan-sweeper.min-age", testValue)
+			if vString, err := cmdFlags.GetString("orphan-sweeper.min-age"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.OrphanSweeper.MinAge)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_orphan-sweeper.resources", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config("1,1", ",")
+
+			cmdFlags.Set("orphan-sweeper.resources", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("orphan-sweeper.resources"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.OrphanSweeper.Resources)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_orphan-sweeper.dry-run", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("orphan-sweeper.dry-run", testValue)
+			if vBool, err := cmdFlags.GetBool("orphan-sweeper.dry-run"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.OrphanSweeper.DryRun)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaling.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaling.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("autoscaling.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Autoscaling.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaling.collect-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Autoscaling.CollectInterval.String()
+
+			cmdFlags.Set("autoscaling.collect-interval", testValue)
+			if vString, err := cmdFlags.GetString("autoscaling.collect-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Autoscaling.CollectInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_canary.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("canary.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("canary.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Canary.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_canary.percent", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("canary.percent", testValue)
+			if vInt, err := cmdFlags.GetInt("canary.percent"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Canary.Percent)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_canary.event-version", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("canary.event-version", testValue)
+			if vInt, err := cmdFlags.GetInt("canary.event-version"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Canary.EventVersion)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_crd.validate", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("crd.validate", testValue)
+			if vBool, err := cmdFlags.GetBool("crd.validate"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.CRD.Validate)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_crd.auto-register", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("crd.auto-register", testValue)
+			if vBool, err := cmdFlags.GetBool("crd.auto-register"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.CRD.AutoRegister)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_crd.establish-timeout", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.CRD.EstablishTimeout.String()
+
+			cmdFlags.Set("crd.establish-timeout", testValue)
+			if vString, err := cmdFlags.GetString("crd.establish-timeout"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.CRD.EstablishTimeout)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_log-capture.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("log-capture.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("log-capture.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.LogCapture.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_log-capture.lines", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("log-capture.lines", testValue)
+			if vInt, err := cmdFlags.GetInt("log-capture.lines"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.LogCapture.Lines)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_log-capture.max-workflows", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("log-capture.max-workflows", testValue)
+			if vInt, err := cmdFlags.GetInt("log-capture.max-workflows"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.LogCapture.MaxWorkflows)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admission.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admission.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("admission.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Admission.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admission.action", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admission.action", testValue)
+			if vString, err := cmdFlags.GetString("admission.action"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Admission.Action)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admission.max-nodes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admission.max-nodes", testValue)
+			if vInt, err := cmdFlags.GetInt("admission.max-nodes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Admission.MaxNodes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admission.max-size-bytes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admission.max-size-bytes", testValue)
+			if vInt, err := cmdFlags.GetInt("admission.max-size-bytes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Admission.MaxSizeBytes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admission.node-status-bytes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admission.node-status-bytes", testValue)
+			if vInt, err := cmdFlags.GetInt("admission.node-status-bytes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Admission.NodeStatusBytes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admission.dynamic-task-types", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config("1,1", ",")
+
+			cmdFlags.Set("admission.dynamic-task-types", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("admission.dynamic-task-types"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.Admission.DynamicTaskTypes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admission.dynamic-node-expansion", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admission.dynamic-node-expansion", testValue)
+			if vInt, err := cmdFlags.GetInt("admission.dynamic-node-expansion"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Admission.DynamicNodeExpansion)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_status-diff.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("status-diff.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("status-diff.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.StatusDiff.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_status-diff.rounds", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("status-diff.rounds", testValue)
+			if vInt, err := cmdFlags.GetInt("status-diff.rounds"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.StatusDiff.Rounds)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_status-diff.max-workflows", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("status-diff.max-workflows", testValue)
+			if vInt, err := cmdFlags.GetInt("status-diff.max-workflows"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.StatusDiff.MaxWorkflows)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_graph-snapshot.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("graph-snapshot.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("graph-snapshot.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.GraphSnapshot.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_graph-snapshot.format", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("graph-snapshot.format", testValue)
+			if vString, err := cmdFlags.GetString("graph-snapshot.format"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.GraphSnapshot.Format)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_namespace-mapping.template", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("namespace-mapping.template", testValue)
+			if vString, err := cmdFlags.GetString("namespace-mapping.template"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.NamespaceMapping.Template)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_metadata-store.middlewares", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config("1,1", ",")
+
+			cmdFlags.Set("metadata-store.middlewares", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("metadata-store.middlewares"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.MetadataStore.Middlewares)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_spec-checksum.fail-on-mismatch", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("spec-checksum.fail-on-mismatch", testValue)
+			if vBool, err := cmdFlags.GetBool("spec-checksum.fail-on-mismatch"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.SpecChecksum.FailOnMismatch)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
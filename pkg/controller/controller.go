@@ -5,28 +5,41 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"runtime/pprof"
 	"time"
 
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/service"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
 
 	"github.com/flyteorg/flytestdlib/contextutils"
 	"k8s.io/apimachinery/pkg/labels"
 
+	stdConfig "github.com/flyteorg/flytestdlib/config"
 	stdErrs "github.com/flyteorg/flytestdlib/errors"
 
 	errors3 "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 
+	"github.com/flyteorg/flytepropeller/pkg/controller/archival"
+	"github.com/flyteorg/flytepropeller/pkg/controller/audit"
+	"github.com/flyteorg/flytepropeller/pkg/controller/chaos"
+	"github.com/flyteorg/flytepropeller/pkg/controller/compression"
+	"github.com/flyteorg/flytepropeller/pkg/controller/encryption"
+	"github.com/flyteorg/flytepropeller/pkg/controller/eventlimit"
+	"github.com/flyteorg/flytepropeller/pkg/controller/eventstore"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/grpcclient"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+	"github.com/flyteorg/flytepropeller/pkg/controller/signedurl"
+	"github.com/flyteorg/flytepropeller/pkg/controller/tenancy"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
 
 	"github.com/flyteorg/flyteidl/clients/go/admin"
 	"github.com/flyteorg/flyteidl/clients/go/events"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/storage"
 	"github.com/pkg/errors"
@@ -49,6 +62,8 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/recovery"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflow"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
 )
 
 const resourceLevelMonitorCycleDuration = 5 * time.Second
@@ -58,6 +73,7 @@ type metrics struct {
 	Scope            promutils.Scope
 	EnqueueCountWf   prometheus.Counter
 	EnqueueCountTask prometheus.Counter
+	StalledSkipped   prometheus.Counter
 }
 
 // Controller is the controller implementation for FlyteWorkflow resources
@@ -72,8 +88,25 @@ type Controller struct {
 	// Kubernetes API.
 	recorder      record.EventRecorder
 	metrics       *metrics
+	stalled       *StalledWorkflowThrottle
 	leaderElector *leaderelection.LeaderElector
 	levelMonitor  *ResourceLevelMonitor
+	watchdog      *MemoryWatchdog
+	orphanSweeper *OrphanSweeper
+	loadReporter  *LoadReporter
+	// Receives the execution events of child executions, nil unless watching them is enabled.
+	executionEvents http.Handler
+}
+
+// LoadReporter returns the reporter of the load of this controller.
+func (c *Controller) LoadReporter() *LoadReporter {
+	return c.loadReporter
+}
+
+// ExecutionEventHandler returns the handler of the execution events of child executions, nil if watching them is not
+// enabled.
+func (c *Controller) ExecutionEventHandler() http.Handler {
+	return c.executionEvents
 }
 
 // Runs either as a leader -if configured- or as a standalone process.
@@ -105,6 +138,15 @@ func (c *Controller) run(ctx context.Context) error {
 
 	// Start the collector process
 	c.levelMonitor.RunCollector(ctx)
+	c.loadReporter.Run(ctx)
+
+	if c.watchdog != nil {
+		c.watchdog.Run(ctx)
+	}
+
+	if c.orphanSweeper != nil {
+		c.orphanSweeper.Run(ctx)
+	}
 
 	// Start the informer factories to begin populating the informer caches
 	logger.Info(ctx, "Starting FlyteWorkflow controller")
@@ -164,6 +206,16 @@ func (c *Controller) getWorkflowUpdatesHandler() cache.ResourceEventHandler {
 		UpdateFunc: func(old, new interface{}) {
 			// TODO we might need to handle updates to the workflow itself.
 			// Initially maybe we should not support it at all
+			if oldWf, ok := old.(*v1alpha1.FlyteWorkflow); ok && c.stalled != nil {
+				if newWf, ok := new.(*v1alpha1.FlyteWorkflow); ok {
+					// Periodic resyncs deliver the same object version again, those are throttled for stalled workflows.
+					if !c.stalled.ShouldEnqueue(newWf, oldWf.ResourceVersion == newWf.ResourceVersion) {
+						logger.Debugf(context.TODO(), "Skipping resync of stalled workflow [%v]", newWf.GetK8sWorkflowID())
+						return
+					}
+				}
+			}
+
 			c.enqueueFlyteWorkflow(new)
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -184,6 +236,10 @@ func (c *Controller) getWorkflowUpdatesHandler() cache.ResourceEventHandler {
 				return
 			}
 
+			if c.stalled != nil {
+				c.stalled.Forget(key)
+			}
+
 			logger.Infof(context.TODO(), "Deletion triggered for %v", name)
 		},
 	}
@@ -284,6 +340,7 @@ func newControllerMetrics(scope promutils.Scope) *metrics {
 		Scope:            scope,
 		EnqueueCountWf:   c.WithLabelValues("wf"),
 		EnqueueCountTask: c.WithLabelValues("task"),
+		StalledSkipped:   scope.MustNewCounter("wf_stalled_enqueue_skipped", "workflow resyncs skipped because the workflow is stalled."),
 	}
 }
 
@@ -305,8 +362,29 @@ func newK8sEventRecorder(ctx context.Context, kubeclientset kubernetes.Interface
 }
 
 func getAdminClient(ctx context.Context) (client service.AdminServiceClient, err error) {
-	cfg := admin.GetConfig(ctx)
-	clients, err := admin.NewClientsetBuilder().WithConfig(cfg).Build(ctx)
+	cfg := *admin.GetConfig(ctx)
+	// The admin client secures the connection itself and dials the auth metadata service without additional dial
+	// options, so a CA bundle and static addresses, which need a resolver registered on the connection, are not supported.
+	targetCfg := grpcclient.GetConfig().GetTargetConfig(grpcclient.AdminTarget)
+	if len(targetCfg.CABundle) > 0 || len(targetCfg.Addresses) > 0 {
+		return nil, fmt.Errorf("a CA bundle and static addresses are not supported for the admin client, " +
+			"add the CA to the system CAs or use a dns:/// endpoint instead")
+	}
+
+	target, opts, err := grpcclient.NewFactory(grpcclient.GetConfig()).Target(grpcclient.AdminTarget, cfg.Endpoint.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if target != cfg.Endpoint.String() {
+		endpoint, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin endpoint [%v]. Error: %w", target, err)
+		}
+		cfg.Endpoint = stdConfig.URL{URL: *endpoint}
+	}
+
+	clients, err := admin.NewClientsetBuilder().WithConfig(&cfg).WithDialOptions(opts...).Build(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize clientset. Error: %w", err)
 	}
@@ -341,14 +419,14 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 	}
 
 	logger.Info(ctx, "Setting up event sink and recorder")
-	eventSink, err := events.ConstructEventSink(ctx, events.GetConfig(ctx))
-	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to create EventSink [%v], error %v", events.GetConfig(ctx).Type, err)
-	}
-	gc, err := NewGarbageCollector(cfg, scope, clock.RealClock{}, kubeclientset.CoreV1().Namespaces(), flytepropellerClientset.FlyteworkflowV1alpha1())
-	if err != nil {
-		logger.Errorf(ctx, "failed to initialize GC for workflows")
-		return nil, errors.Wrapf(err, "failed to initialize WF GC")
+	// When events are stored in the metadata store, their sink is created along with the metadata store below.
+	var eventSink events.EventSink
+	eventStoreCfg := eventstore.GetConfig()
+	if !eventStoreCfg.Enabled {
+		eventSink, err = events.ConstructEventSink(ctx, events.GetConfig(ctx))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create EventSink [%v], error %v", events.GetConfig(ctx).Type, err)
+		}
 	}
 
 	eventRecorder, err := newK8sEventRecorder(ctx, kubeclientset, cfg.PublishK8sEvents)
@@ -359,10 +437,11 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 	controller := &Controller{
 		metrics:    newControllerMetrics(scope),
 		recorder:   eventRecorder,
-		gc:         gc,
 		numWorkers: cfg.Workers,
 	}
 
+	controller.stalled = NewStalledWorkflowThrottle(cfg.StalledWorkflowReEval.Duration, clock.RealClock{}, controller.metrics.StalledSkipped)
+
 	lock, err := newResourceLock(kubeclientset.CoreV1(), kubeclientset.CoordinationV1(), eventRecorder, cfg.LeaderElection)
 	if err != nil {
 		logger.Errorf(ctx, "failed to initialize resource lock.")
@@ -400,6 +479,122 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 		return nil, errors.Wrapf(err, "Failed to create Metadata storage")
 	}
 
+	var faults *chaos.Injector
+	chaosCfg := chaos.GetConfig()
+	if chaosCfg.Enabled {
+		logger.Warn(ctx, "Enabling fault injection, this is only meant for resilience testing.")
+		faults = chaos.NewInjector(chaosCfg, scope.NewSubScope("chaos"))
+	}
+
+	var dataKeys DataKeyProvider
+	middlewares := map[string]DataStoreMiddleware{
+		MetadataStoreTenancy:    nil,
+		MetadataStoreChaos:      nil,
+		MetadataStoreEncryption: nil,
+		MetadataStoreMetrics: func(store *storage.DataStore) (*storage.DataStore, error) {
+			return newInstrumentedDataStore(store, scope.NewSubScope("metastore_ops")), nil
+		},
+		// Always applied, so that documents compressed while compression was enabled can still be read.
+		MetadataStoreCompression: func(store *storage.DataStore) (*storage.DataStore, error) {
+			compressionCfg := compression.GetConfig()
+			if compressionCfg.Enabled {
+				logger.Infof(ctx, "Compressing futures and workflow closures larger than [%d] bytes with [%v].",
+					compressionCfg.ThresholdBytes, compressionCfg.Algorithm)
+			}
+
+			return compression.NewDataStore(compressionCfg, store, scope.NewSubScope("compressed_metastore"))
+		},
+	}
+
+	if tenancyCfg := tenancy.GetConfig(); tenancyCfg.Enabled {
+		middlewares[MetadataStoreTenancy] = func(store *storage.DataStore) (*storage.DataStore, error) {
+			logger.Infof(ctx, "Separating the data of [%d] tenants in the metadata store.", len(tenancyCfg.Tenants))
+			return tenancy.NewDataStore(*tenancyCfg, cfg.NamespaceMapping, sCfg, store, kubeclientset.CoreV1(), scope.NewSubScope("tenancy"))
+		}
+	}
+
+	if faults != nil {
+		middlewares[MetadataStoreChaos] = func(store *storage.DataStore) (*storage.DataStore, error) {
+			return chaos.NewDataStore(store, faults, scope.NewSubScope("chaos_metastore")), nil
+		}
+	}
+
+	if encryptionCfg := encryption.GetConfig(); encryptionCfg.Enabled {
+		middlewares[MetadataStoreEncryption] = func(store *storage.DataStore) (*storage.DataStore, error) {
+			logger.Infof(ctx, "Enabling encryption of futures with master key [%v].", encryptionCfg.MasterKeyID)
+			keyManager, err := encryption.NewKeyManager(encryptionCfg)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to create key manager")
+			}
+
+			dataKeys, err = encryption.NewDataKeyProvider(encryptionCfg, keyManager, scope.NewSubScope("encryption"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to create data key provider")
+			}
+
+			return encryption.NewDataStore(store, scope.NewSubScope("encrypted_metastore")), nil
+		}
+	}
+
+	store, err = wrapDataStore(store, cfg.MetadataStore.Middlewares, middlewares)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to wrap Metadata storage")
+	}
+
+	if eventStoreCfg.Enabled {
+		logger.Info(ctx, "Storing events in the metadata store instead of sending them to admin.")
+		eventSink, err = eventstore.NewEventSink(ctx, store, cfg.MetadataPrefix, eventStoreCfg, scope.NewSubScope("event_store"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create event store sink")
+		}
+	}
+
+	// The admin launcher, before faults are injected into it, serves the execution events of child executions.
+	adminLauncher := launchPlanActor
+	if faults != nil {
+		eventSink = chaos.NewEventSink(eventSink, faults)
+		launchPlanActor = chaos.NewLaunchPlanExecutor(launchPlanActor, faults)
+	}
+
+	var archiver WorkflowArchiver
+	if archivalCfg := archival.GetConfig(); archivalCfg.Enabled {
+		logger.Info(ctx, "Enabling archival of workflows before garbage collection.")
+		archiver, err = archival.NewArchiver(ctx, store, archivalCfg, scope.NewSubScope("archival"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create workflow archiver")
+		}
+	}
+
+	controller.gc, err = NewGarbageCollector(cfg, scope, clock.RealClock{}, kubeclientset.CoreV1().Namespaces(),
+		flytepropellerClientset.FlyteworkflowV1alpha1(), archiver)
+	if err != nil {
+		logger.Errorf(ctx, "failed to initialize GC for workflows")
+		return nil, errors.Wrapf(err, "failed to initialize WF GC")
+	}
+
+	if auditCfg := audit.GetConfig(); auditCfg.Enabled {
+		logger.Info(ctx, "Enabling audit log of phase transitions.")
+		eventSink, err = audit.NewEventSink(ctx, eventSink, store, cfg.MetadataPrefix, auditCfg, scope.NewSubScope("audit"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create audit event sink")
+		}
+	}
+
+	if signedURLCfg := signedurl.GetConfig(); signedURLCfg.Enabled {
+		logger.Infof(ctx, "Enabling signed URLs of outputs in events, valid for [%v].", signedURLCfg.TTL.Duration)
+		signer, err := signedurl.NewS3Signer(signedURLCfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create URL signer")
+		}
+
+		eventSink = signedurl.NewEventSink(eventSink, signer, signedURLCfg, scope.NewSubScope("signed_urls"))
+	}
+
+	if limitCfg := eventlimit.GetConfig(); limitCfg.Enabled {
+		logger.Infof(ctx, "Enabling event rate limiting of [%v] events per second per execution.", limitCfg.Rate)
+		eventSink = eventlimit.NewEventSink(eventSink, limitCfg, scope.NewSubScope("event_limit"))
+	}
+
 	logger.Info(ctx, "Setting up Catalog client.")
 	catalogClient, err := catalog.NewCatalogClient(ctx)
 	if err != nil {
@@ -412,11 +607,45 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 	}
 	controller.workQueue = workQ
 
+	if watchCfg := launchplan.GetAdminConfig().Watch; cfg.EnableAdminLauncher && watchCfg.Enabled {
+		logger.Infof(ctx, "Watching the execution events of child executions on [%v].", watchCfg.Path)
+		token, err := launchplan.ReadWatchToken(watchCfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read the token of execution events")
+		}
+
+		controller.executionEvents, err = launchplan.NewExecutionEventHandler(adminLauncher,
+			func(ctx context.Context, id *core.WorkflowExecutionIdentifier) {
+				namespace := utils.GetNamespaceName(cfg.NamespaceMapping.Template, id.Project, id.Domain)
+				controller.workQueue.AddToSubQueue(namespace + "/" + id.Name)
+			}, token, scope.NewSubScope("execution_events"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create execution event handler")
+		}
+	}
+
 	controller.workflowStore, err = workflowstore.NewWorkflowStore(ctx, workflowstore.GetConfig(), flyteworkflowInformer.Lister(), flytepropellerClientset.FlyteworkflowV1alpha1(), scope)
 	if err != nil {
 		return nil, stdErrs.Wrapf(errors3.CausedByError, err, "failed to initialize workflow store")
 	}
 
+	shedders := []executors.CacheShedder{controller.stalled}
+	if shedder, ok := controller.workflowStore.(executors.CacheShedder); ok {
+		shedders = append(shedders, shedder)
+	}
+
+	controller.workflowStore, err = workflowstore.NewClosureHydratingStore(controller.workflowStore, store,
+		workflowstore.GetConfig().ClosureCacheSize, scope.NewSubScope("workflowstore"))
+	if err != nil {
+		return nil, stdErrs.Wrapf(errors3.CausedByError, err, "failed to initialize workflow closure store")
+	}
+
+	var restarts RestartInjector
+	if faults != nil {
+		controller.workflowStore = chaos.NewWorkflowStore(controller.workflowStore, faults)
+		restarts = faults
+	}
+
 	controller.levelMonitor = NewResourceLevelMonitor(scope.NewSubScope("collector"), flyteworkflowInformer.Lister())
 
 	nodeExecutor, err := nodes.NewExecutor(ctx, cfg.NodeConfig, store, controller.enqueueWorkflowForNodeUpdates, eventSink,
@@ -426,13 +655,67 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 		return nil, errors.Wrapf(err, "Failed to create Controller.")
 	}
 
+	if shedder, ok := nodeExecutor.(executors.CacheShedder); ok {
+		shedders = append(shedders, shedder)
+	}
+
 	workflowExecutor, err := workflow.NewExecutor(ctx, store, controller.enqueueWorkflowForNodeUpdates, eventSink, controller.recorder, cfg.MetadataPrefix, nodeExecutor, scope)
 	if err != nil {
 		return nil, err
 	}
 
-	handler := NewPropellerHandler(ctx, cfg, controller.workflowStore, workflowExecutor, scope)
+	if cfg.LogCapture.Enabled {
+		logger.Infof(ctx, "Capturing the last [%d] log lines of each workflow.", cfg.LogCapture.Lines)
+		logCapture, err := NewLogCapturingWorkflowExecutor(cfg.LogCapture, workflowExecutor, store)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create log capturing workflow executor")
+		}
+
+		workflowExecutor = logCapture
+		shedders = append(shedders, logCapture)
+	}
+
+	if cfg.StatusDiff.Enabled {
+		logger.Infof(ctx, "Diffing the status of workflows across [%d] rounds.", cfg.StatusDiff.Rounds)
+		statusDiff, err := NewStatusDiffingWorkflowExecutor(cfg.StatusDiff, workflowExecutor, scope.NewSubScope("status_diff"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create status diffing workflow executor")
+		}
+
+		workflowExecutor = statusDiff
+		shedders = append(shedders, statusDiff)
+	}
+
+	if cfg.GraphSnapshot.Enabled {
+		logger.Infof(ctx, "Writing [%v] graph snapshots of workflows.", cfg.GraphSnapshot.Format)
+		workflowExecutor, err = NewGraphSnapshottingWorkflowExecutor(cfg.GraphSnapshot, workflowExecutor, store)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create graph snapshotting workflow executor")
+		}
+	}
+
+	handler := NewPropellerHandler(ctx, cfg, controller.workflowStore, workflowExecutor, restarts, dataKeys, scope)
 	controller.workerPool = NewWorkerPool(ctx, scope, workQ, handler)
+	controller.loadReporter = NewLoadReporter(workQ, controller.workerPool, cfg.Autoscaling.CollectInterval.Duration,
+		scope.NewSubScope("load"))
+
+	if cfg.MemoryWatchdog.Enabled {
+		logger.Infof(ctx, "Enabling memory watchdog with a limit of [%v].", cfg.MemoryWatchdog.MemoryLimit)
+		controller.watchdog, err = NewMemoryWatchdog(cfg.MemoryWatchdog, cfg.Workers, controller.workerPool,
+			controller.recorder, scope.NewSubScope("watchdog"), shedders...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create memory watchdog")
+		}
+	}
+
+	if cfg.OrphanSweeper.Enabled {
+		logger.Infof(ctx, "Enabling orphaned task resource sweeper for resources %v.", cfg.OrphanSweeper.Resources)
+		controller.orphanSweeper, err = NewOrphanSweeper(cfg, kubeClient.GetClient(),
+			flytepropellerClientset.FlyteworkflowV1alpha1(), clock.RealClock{}, scope.NewSubScope("orphan_sweeper"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create orphan sweeper")
+		}
+	}
 
 	logger.Info(ctx, "Setting up event handlers")
 	// Set up an event handler for when FlyteWorkflow resources change
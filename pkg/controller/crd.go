@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const (
+	flyteWorkflowCRDName     = "flyteworkflows." + flyteworkflow.GroupName
+	crdEstablishPollInterval = time.Second
+)
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// EnsureFlyteWorkflowCRD checks that the FlyteWorkflow CRD is installed, serves the version of FlyteWorkflows propeller
+// uses and is established, so that propeller fails to start with an actionable error instead of failing to list and
+// watch workflows. If configured, it installs the CRD, or adds the version to it, when it is missing. It only warns if
+// propeller is not allowed to read CRDs.
+func EnsureFlyteWorkflowCRD(ctx context.Context, cfg config.CRDConfig, kubeClient client.Client) error {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	err := kubeClient.Get(ctx, types.NamespacedName{Name: flyteWorkflowCRDName}, crd)
+	switch {
+	case k8serrors.IsForbidden(err):
+		logger.Warnf(ctx, "Not allowed to read CRD [%s], skipping its validation. Error: %v", flyteWorkflowCRDName, err)
+		return nil
+	case k8serrors.IsNotFound(err):
+		if !cfg.AutoRegister {
+			return fmt.Errorf("the FlyteWorkflow CRD [%s] is not installed. Install it, or set crd.auto-register to let propeller install it", flyteWorkflowCRDName)
+		}
+
+		crd = newFlyteWorkflowCRD()
+		if err := kubeClient.Create(ctx, crd); err != nil {
+			return fmt.Errorf("failed to install the FlyteWorkflow CRD [%s]: %w", flyteWorkflowCRDName, err)
+		}
+
+		logger.Infof(ctx, "Installed the FlyteWorkflow CRD [%s]", flyteWorkflowCRDName)
+	case err != nil:
+		return fmt.Errorf("failed to read the FlyteWorkflow CRD [%s]: %w", flyteWorkflowCRDName, err)
+	default:
+		served, err := servesFlyteWorkflowVersion(crd)
+		if err != nil {
+			return err
+		}
+
+		if !served {
+			if !cfg.AutoRegister {
+				return fmt.Errorf("the FlyteWorkflow CRD [%s] does not serve version [%s] that propeller uses. Update it, or set crd.auto-register to let propeller update it",
+					flyteWorkflowCRDName, v1alpha1.SchemeGroupVersion.Version)
+			}
+
+			if err := serveFlyteWorkflowVersion(crd); err != nil {
+				return err
+			}
+
+			if err := kubeClient.Update(ctx, crd); err != nil {
+				return fmt.Errorf("failed to update the FlyteWorkflow CRD [%s]: %w", flyteWorkflowCRDName, err)
+			}
+
+			logger.Infof(ctx, "Updated the FlyteWorkflow CRD [%s] to serve version [%s]", flyteWorkflowCRDName, v1alpha1.SchemeGroupVersion.Version)
+		}
+	}
+
+	if !isCRDEstablished(crd) {
+		err = wait.PollImmediate(crdEstablishPollInterval, cfg.EstablishTimeout.Duration, func() (bool, error) {
+			if err := kubeClient.Get(ctx, types.NamespacedName{Name: flyteWorkflowCRDName}, crd); err != nil {
+				return false, err
+			}
+
+			return isCRDEstablished(crd), nil
+		})
+		if err != nil {
+			return fmt.Errorf("the FlyteWorkflow CRD [%s] is not established after [%v]. Check its status conditions: %w",
+				flyteWorkflowCRDName, cfg.EstablishTimeout.Duration, err)
+		}
+	}
+
+	return nil
+}
+
+func newFlyteWorkflowCRD() *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group": flyteworkflow.GroupName,
+			"names": map[string]interface{}{
+				"kind":       "FlyteWorkflow",
+				"plural":     "flyteworkflows",
+				"singular":   v1alpha1.FlyteWorkflowKind,
+				"shortNames": []interface{}{"fly"},
+			},
+			"scope":    "Namespaced",
+			"versions": []interface{}{newFlyteWorkflowCRDVersion(true)},
+		},
+	}}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName(flyteWorkflowCRDName)
+	return crd
+}
+
+// FlyteWorkflows are validated by propeller, the CRD accepts any object.
+func newFlyteWorkflowCRDVersion(storage bool) map[string]interface{} {
+	return map[string]interface{}{
+		"name":    v1alpha1.SchemeGroupVersion.Version,
+		"served":  true,
+		"storage": storage,
+		"schema": map[string]interface{}{
+			"openAPIV3Schema": map[string]interface{}{
+				"type":                                 "object",
+				"x-kubernetes-preserve-unknown-fields": true,
+			},
+		},
+	}
+}
+
+func servesFlyteWorkflowVersion(crd *unstructured.Unstructured) (bool, error) {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return false, fmt.Errorf("failed to read the versions of the FlyteWorkflow CRD [%s]: %w", flyteWorkflowCRDName, err)
+	}
+
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if ok && version["name"] == v1alpha1.SchemeGroupVersion.Version {
+			served, _ := version["served"].(bool)
+			return served, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Serves the version of FlyteWorkflows propeller uses, adding it if it is missing. It does not change the version the
+// existing FlyteWorkflows are stored as.
+func serveFlyteWorkflowVersion(crd *unstructured.Unstructured) error {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return fmt.Errorf("failed to read the versions of the FlyteWorkflow CRD [%s]: %w", flyteWorkflowCRDName, err)
+	}
+
+	found := false
+	for _, v := range versions {
+		if version, ok := v.(map[string]interface{}); ok && version["name"] == v1alpha1.SchemeGroupVersion.Version {
+			version["served"] = true
+			found = true
+		}
+	}
+
+	if !found {
+		versions = append(versions, newFlyteWorkflowCRDVersion(len(versions) == 0))
+	}
+
+	return unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions")
+}
+
+func isCRDEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok && condition["type"] == "Established" {
+			return condition["status"] == "True"
+		}
+	}
+
+	return false
+}
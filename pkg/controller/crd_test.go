@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	config2 "github.com/flyteorg/flytestdlib/config"
+	"github.com/stretchr/testify/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+type forbiddenClient struct {
+	client.Client
+}
+
+func (forbiddenClient) Get(_ context.Context, key client.ObjectKey, _ client.Object) error {
+	return k8serrors.NewForbidden(schema.GroupResource{Group: crdGVK.Group, Resource: "customresourcedefinitions"}, key.Name, nil)
+}
+
+func TestEnsureFlyteWorkflowCRD(t *testing.T) {
+	ctx := context.TODO()
+	cfg := config.CRDConfig{Validate: true, EstablishTimeout: config2.Duration{Duration: 10 * time.Millisecond}}
+	autoRegister := cfg
+	autoRegister.AutoRegister = true
+
+	established := func(crd *unstructured.Unstructured) *unstructured.Unstructured {
+		assert.NoError(t, unstructured.SetNestedSlice(crd.Object, []interface{}{
+			map[string]interface{}{"type": "Established", "status": "True"},
+		}, "status", "conditions"))
+		return crd
+	}
+
+	getCRD := func(t *testing.T, kubeClient client.Client) *unstructured.Unstructured {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		assert.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: flyteWorkflowCRDName}, crd))
+		return crd
+	}
+
+	t.Run("installed", func(t *testing.T) {
+		kubeClient := fake.NewClientBuilder().WithObjects(established(newFlyteWorkflowCRD())).Build()
+		assert.NoError(t, EnsureFlyteWorkflowCRD(ctx, cfg, kubeClient))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := EnsureFlyteWorkflowCRD(ctx, cfg, fake.NewClientBuilder().Build())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "crd.auto-register")
+	})
+
+	t.Run("missing-auto-register", func(t *testing.T) {
+		kubeClient := fake.NewClientBuilder().Build()
+		err := EnsureFlyteWorkflowCRD(ctx, autoRegister, kubeClient)
+		// The fake client never establishes CRDs.
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not established")
+
+		served, err := servesFlyteWorkflowVersion(getCRD(t, kubeClient))
+		assert.NoError(t, err)
+		assert.True(t, served)
+	})
+
+	t.Run("version-not-served", func(t *testing.T) {
+		crd := established(newFlyteWorkflowCRD())
+		assert.NoError(t, unstructured.SetNestedSlice(crd.Object, []interface{}{
+			map[string]interface{}{"name": "v1alpha0", "served": true, "storage": true},
+		}, "spec", "versions"))
+
+		err := EnsureFlyteWorkflowCRD(ctx, cfg, fake.NewClientBuilder().WithObjects(crd.DeepCopy()).Build())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not serve version [v1alpha1]")
+
+		kubeClient := fake.NewClientBuilder().WithObjects(crd.DeepCopy()).Build()
+		assert.NoError(t, EnsureFlyteWorkflowCRD(ctx, autoRegister, kubeClient))
+		versions, _, err := unstructured.NestedSlice(getCRD(t, kubeClient).Object, "spec", "versions")
+		assert.NoError(t, err)
+		if assert.Len(t, versions, 2) {
+			assert.Equal(t, true, versions[0].(map[string]interface{})["storage"])
+			assert.Equal(t, "v1alpha1", versions[1].(map[string]interface{})["name"])
+			assert.Equal(t, false, versions[1].(map[string]interface{})["storage"])
+		}
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		assert.NoError(t, EnsureFlyteWorkflowCRD(ctx, cfg, forbiddenClient{Client: fake.NewClientBuilder().Build()}))
+	})
+}
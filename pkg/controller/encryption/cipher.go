@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// Prefix of encrypted documents, so that reads can tell them apart from the plaintext ones written before encryption
+// was enabled or by task containers.
+var envelopeMagic = []byte("FLYTEENC1")
+
+// seal encrypts plaintext with AES-256-GCM. The random nonce is prepended to the ciphertext.
+func seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a ciphertext produced by seal.
+func open(key, ciphertext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// encryptDocument encrypts a serialized document with a data key and marks it as encrypted.
+func encryptDocument(dataKey, document []byte) ([]byte, error) {
+	sealed, err := seal(dataKey, document)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(make([]byte, 0, len(envelopeMagic)+len(sealed)), envelopeMagic...), sealed...), nil
+}
+
+func isEncryptedDocument(raw []byte) bool {
+	return bytes.HasPrefix(raw, envelopeMagic)
+}
+
+// decryptDocument decrypts a document produced by encryptDocument.
+func decryptDocument(dataKey, raw []byte) ([]byte, error) {
+	return open(dataKey, raw[len(envelopeMagic):])
+}
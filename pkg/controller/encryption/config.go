@@ -0,0 +1,65 @@
+// Package encryption encrypts the documents that only propeller reads, i.e. futures and the workflows compiled from
+// them, at rest with per execution data keys (envelope encryption).
+//
+// Literals, i.e. the inputs and outputs of nodes, are NOT encrypted. Task containers, admin and the UI read them
+// straight from the blob store without access to the data key of the execution, so encrypting them needs decryption
+// support in every one of those readers first. Until then, protect inputs and outputs with server side encryption of
+// the bucket.
+package encryption
+
+import (
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "encryption"
+
+type KeyManagerType = string
+
+const (
+	// KeyManagerLocal wraps data keys with master keys read from local files, e.g. mounted K8s secrets.
+	KeyManagerLocal KeyManagerType = "local"
+	// KeyManagerAWSKMS wraps data keys with AWS KMS keys.
+	KeyManagerAWSKMS KeyManagerType = "aws-kms"
+)
+
+var (
+	defaultConfig = &Config{
+		Enabled:           false,
+		KeyManager:        KeyManagerLocal,
+		LocalMasterKeys:   map[string]string{},
+		MaxCachedDataKeys: 10000,
+		DataKeyCacheTTL:   config.Duration{Duration: time.Hour},
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls the envelope encryption at rest of the documents only propeller reads, i.e. futures and the workflows
+// compiled from them. Inputs and outputs are never encrypted, see the package documentation.
+// Every execution gets its own data key, which is stored in the workflow status encrypted with a master key. Changing
+// the master key rotates the data keys of running executions, the previous master keys have to stay available until
+// those are rotated.
+type Config struct {
+	Enabled     bool           `json:"enabled" pflag:",Enables encrypting futures and compiled workflows written by propeller. Inputs and outputs are not encrypted."`
+	KeyManager  KeyManagerType `json:"key-manager" pflag:",Key manager that wraps data keys, local or aws-kms."`
+	MasterKeyID string         `json:"master-key-id" pflag:",ID of the master key new data keys are encrypted with, a key of local-master-keys or the ARN of an AWS KMS key."`
+	// Maps master key IDs to files that contain the base64 encoded 32 byte key.
+	LocalMasterKeys   map[string]string `json:"local-master-keys" pflag:"-,Paths to the files of the local master keys by their ID."`
+	AWSRegion         string            `json:"aws-region" pflag:",AWS region of the KMS keys."`
+	MaxCachedDataKeys int               `json:"max-cached-data-keys" pflag:",Maximum number of decrypted data keys kept in memory."`
+	DataKeyCacheTTL   config.Duration   `json:"data-key-cache-ttl" pflag:",Duration after which a decrypted data key is dropped from memory."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
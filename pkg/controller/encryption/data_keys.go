@@ -0,0 +1,119 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type dataKeyContextKey struct{}
+
+// WithDataKey returns a context that encrypts the documents written and decrypts the documents read through an
+// encrypting datastore with the given data key.
+func WithDataKey(ctx context.Context, dataKey []byte) context.Context {
+	return context.WithValue(ctx, dataKeyContextKey{}, dataKey)
+}
+
+// DataKeyFromContext returns the data key set by WithDataKey, if any.
+func DataKeyFromContext(ctx context.Context) ([]byte, bool) {
+	dataKey, ok := ctx.Value(dataKeyContextKey{}).([]byte)
+	return dataKey, ok
+}
+
+type dataKeyMetrics struct {
+	Generated         prometheus.Counter
+	Rotated           prometheus.Counter
+	CacheMisses       prometheus.Counter
+	DecryptionFailure prometheus.Counter
+}
+
+// DataKeyProvider manages the data keys of executions. It generates the data key of an execution on its first round,
+// keeps it encrypted in the workflow status and re-encrypts it whenever the configured master key changes.
+type DataKeyProvider struct {
+	keys        KeyManager
+	masterKeyID string
+	cache       *cache.LRUExpireCache
+	cacheTTL    time.Duration
+	metrics     dataKeyMetrics
+}
+
+func (p *DataKeyProvider) getDataKey(ctx context.Context, ref *v1alpha1.DataKeyReference) ([]byte, error) {
+	cacheKey := ref.MasterKeyID + "/" + base64.StdEncoding.EncodeToString(ref.Ciphertext)
+	if dataKey, found := p.cache.Get(cacheKey); found {
+		return dataKey.([]byte), nil
+	}
+
+	p.metrics.CacheMisses.Inc()
+	dataKey, err := p.keys.Decrypt(ctx, ref.MasterKeyID, ref.Ciphertext)
+	if err != nil {
+		p.metrics.DecryptionFailure.Inc()
+		return nil, fmt.Errorf("failed to decrypt data key with master key [%v]: %w", ref.MasterKeyID, err)
+	}
+
+	p.cache.Add(cacheKey, dataKey, p.cacheTTL)
+	return dataKey, nil
+}
+
+// WithWorkflowDataKey returns a context that carries the data key of the workflow. The data key is generated, or
+// re-encrypted with the current master key, in the status of the given workflow, which has to be persisted for the
+// change to take effect.
+func (p *DataKeyProvider) WithWorkflowDataKey(ctx context.Context, w *v1alpha1.FlyteWorkflow) (context.Context, error) {
+	ref := w.Status.DataKey
+	if ref == nil {
+		dataKey, ciphertext, err := p.keys.GenerateDataKey(ctx, p.masterKeyID)
+		if err != nil {
+			return ctx, fmt.Errorf("failed to generate data key with master key [%v]: %w", p.masterKeyID, err)
+		}
+
+		p.metrics.Generated.Inc()
+		w.Status.DataKey = &v1alpha1.DataKeyReference{MasterKeyID: p.masterKeyID, Ciphertext: ciphertext}
+		return WithDataKey(ctx, dataKey), nil
+	}
+
+	dataKey, err := p.getDataKey(ctx, ref)
+	if err != nil {
+		return ctx, err
+	}
+
+	if ref.MasterKeyID != p.masterKeyID {
+		ciphertext, err := p.keys.Encrypt(ctx, p.masterKeyID, dataKey)
+		if err != nil {
+			// The data key is still usable, rotation is retried in the next round.
+			logger.Warnf(ctx, "Failed to rotate data key from master key [%v] to [%v]. Error: %v",
+				ref.MasterKeyID, p.masterKeyID, err)
+		} else {
+			logger.Infof(ctx, "Rotated data key from master key [%v] to [%v]", ref.MasterKeyID, p.masterKeyID)
+			p.metrics.Rotated.Inc()
+			w.Status.DataKey = &v1alpha1.DataKeyReference{MasterKeyID: p.masterKeyID, Ciphertext: ciphertext}
+		}
+	}
+
+	return WithDataKey(ctx, dataKey), nil
+}
+
+func NewDataKeyProvider(cfg *Config, keys KeyManager, scope promutils.Scope) (*DataKeyProvider, error) {
+	if len(cfg.MasterKeyID) == 0 {
+		return nil, fmt.Errorf("a master key id is required for encryption")
+	}
+
+	return &DataKeyProvider{
+		keys:        keys,
+		masterKeyID: cfg.MasterKeyID,
+		cache:       cache.NewLRUExpireCache(cfg.MaxCachedDataKeys),
+		cacheTTL:    cfg.DataKeyCacheTTL.Duration,
+		metrics: dataKeyMetrics{
+			Generated:         scope.MustNewCounter("data_keys_generated", "Number of data keys generated for new executions"),
+			Rotated:           scope.MustNewCounter("data_keys_rotated", "Number of data keys re-encrypted with a new master key"),
+			CacheMisses:       scope.MustNewCounter("data_key_cache_misses", "Number of data keys that had to be decrypted by the key manager"),
+			DecryptionFailure: scope.MustNewCounter("data_key_decryption_failures", "Number of data keys that could not be decrypted"),
+		},
+	}, nil
+}
@@ -0,0 +1,132 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/compression"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type datastoreMetrics struct {
+	EncryptedWrites   prometheus.Counter
+	DecryptedReads    prometheus.Counter
+	DecryptionFailure prometheus.Counter
+}
+
+// encryptingProtobufStore encrypts futures and compiled workflows with the data key in the context when writing them and
+// decrypts all encrypted documents when reading them. Documents without the encryption marker are read as is.
+type encryptingProtobufStore struct {
+	storage.ComposedProtobufStore
+	metrics datastoreMetrics
+}
+
+// Only the documents no one but propeller reads are encrypted, i.e. futures and the workflows compiled from them.
+// Literals are never encrypted: inputs and outputs are read by task containers and admin, which have no access to the
+// data key.
+// Encrypted documents are not compressed, the size of compressed ciphertext would leak information about the plaintext.
+func shouldEncrypt(msg proto.Message) bool {
+	switch msg.(type) {
+	case *core.DynamicJobSpec, *core.CompiledWorkflowClosure:
+		return true
+	}
+
+	return false
+}
+
+func (s encryptingProtobufStore) ReadProtobuf(ctx context.Context, reference storage.DataReference, msg proto.Message) error {
+	rc, err := s.ReadRaw(ctx, reference)
+	if err != nil && !storage.IsFailedWriteToCache(err) {
+		return fmt.Errorf("path:%v: %w", reference, err)
+	}
+
+	defer func() {
+		if err := rc.Close(); err != nil {
+			logger.Warnf(ctx, "Failed to close reference [%v]. Error: %v", reference, err)
+		}
+	}()
+
+	raw, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("readAll: %v: %w", reference, err)
+	}
+
+	if isEncryptedDocument(raw) {
+		dataKey, found := DataKeyFromContext(ctx)
+		if !found {
+			s.metrics.DecryptionFailure.Inc()
+			return fmt.Errorf("no data key available to decrypt [%v]", reference)
+		}
+
+		raw, err = decryptDocument(dataKey, raw)
+		if err != nil {
+			s.metrics.DecryptionFailure.Inc()
+			return fmt.Errorf("failed to decrypt [%v]: %w", reference, err)
+		}
+
+		s.metrics.DecryptedReads.Inc()
+	}
+
+	// Documents that are not encrypted may have been compressed by the store this one wraps.
+	if compression.IsCompressed(raw) {
+		if raw, err = compression.Decompress(raw); err != nil {
+			return fmt.Errorf("failed to decompress [%v]: %w", reference, err)
+		}
+	}
+
+	if err = proto.Unmarshal(raw, msg); err != nil {
+		return fmt.Errorf("unmarshall: %v: %w", reference, err)
+	}
+
+	return nil
+}
+
+func (s encryptingProtobufStore) WriteProtobuf(ctx context.Context, reference storage.DataReference, opts storage.Options,
+	msg proto.Message) error {
+
+	dataKey, found := DataKeyFromContext(ctx)
+	if !found || !shouldEncrypt(msg) {
+		return s.ComposedProtobufStore.WriteProtobuf(ctx, reference, opts, msg)
+	}
+
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encryptDocument(dataKey, raw)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt [%v]: %w", reference, err)
+	}
+
+	err = s.WriteRaw(ctx, reference, int64(len(encrypted)), opts, bytes.NewReader(encrypted))
+	if err != nil && !storage.IsFailedWriteToCache(err) {
+		return err
+	}
+
+	s.metrics.EncryptedWrites.Inc()
+	return nil
+}
+
+// NewDataStore wraps the datastore so that futures and compiled workflows are encrypted with the data key of the execution in
+// the context, see WithDataKey.
+func NewDataStore(store *storage.DataStore, scope promutils.Scope) *storage.DataStore {
+	protobufStore := encryptingProtobufStore{
+		ComposedProtobufStore: store.ComposedProtobufStore,
+		metrics: datastoreMetrics{
+			EncryptedWrites:   scope.MustNewCounter("encrypted_writes", "Number of documents encrypted before writing them"),
+			DecryptedReads:    scope.MustNewCounter("decrypted_reads", "Number of encrypted documents decrypted after reading them"),
+			DecryptionFailure: scope.MustNewCounter("decryption_failures", "Number of encrypted documents that could not be decrypted"),
+		},
+	}
+
+	return storage.NewCompositeDataStore(store.ReferenceConstructor, protobufStore)
+}
@@ -0,0 +1,191 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/compression"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NamespaceKey)
+}
+
+func newLocalKeyManager(t *testing.T, ids ...string) KeyManager {
+	dir, err := ioutil.TempDir("", "encryption")
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	files := map[string]string{}
+	for i, id := range ids {
+		key := make([]byte, keySize)
+		key[0] = byte(i + 1)
+		files[id] = filepath.Join(dir, id)
+		assert.NoError(t, ioutil.WriteFile(files[id], []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+	}
+
+	keys, err := NewLocalKeyManager(files)
+	assert.NoError(t, err)
+	return keys
+}
+
+func TestLocalKeyManager(t *testing.T) {
+	ctx := context.TODO()
+	keys := newLocalKeyManager(t, "key-1", "key-2")
+
+	plaintext, ciphertext, err := keys.GenerateDataKey(ctx, "key-1")
+	assert.NoError(t, err)
+	assert.Len(t, plaintext, keySize)
+
+	decrypted, err := keys.Decrypt(ctx, "key-1", ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	_, err = keys.Decrypt(ctx, "key-2", ciphertext)
+	assert.Error(t, err)
+
+	_, _, err = keys.GenerateDataKey(ctx, "unknown")
+	assert.Error(t, err)
+}
+
+func TestNewLocalKeyManager_InvalidKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "short")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0600))
+	_, err = NewLocalKeyManager(map[string]string{"short": path})
+	assert.Error(t, err)
+}
+
+func TestDataKeyProvider(t *testing.T) {
+	ctx := context.TODO()
+	keys := newLocalKeyManager(t, "key-1", "key-2")
+	cfg := &Config{MasterKeyID: "key-1", MaxCachedDataKeys: 10, DataKeyCacheTTL: config.Duration{Duration: time.Hour}}
+	provider, err := NewDataKeyProvider(cfg, keys, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	w := &v1alpha1.FlyteWorkflow{}
+	keyCtx, err := provider.WithWorkflowDataKey(ctx, w)
+	assert.NoError(t, err)
+	dataKey, found := DataKeyFromContext(keyCtx)
+	assert.True(t, found)
+	if assert.NotNil(t, w.Status.DataKey) {
+		assert.Equal(t, "key-1", w.Status.DataKey.MasterKeyID)
+	}
+
+	t.Run("existing", func(t *testing.T) {
+		ref := w.Status.DataKey
+		keyCtx, err := provider.WithWorkflowDataKey(ctx, w)
+		assert.NoError(t, err)
+		existing, _ := DataKeyFromContext(keyCtx)
+		assert.Equal(t, dataKey, existing)
+		assert.Equal(t, ref, w.Status.DataKey)
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		rotating, err := NewDataKeyProvider(&Config{MasterKeyID: "key-2", MaxCachedDataKeys: 10}, keys, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		rotated := w.DeepCopy()
+		keyCtx, err := rotating.WithWorkflowDataKey(ctx, rotated)
+		assert.NoError(t, err)
+		existing, _ := DataKeyFromContext(keyCtx)
+		assert.Equal(t, dataKey, existing)
+		assert.Equal(t, "key-2", rotated.Status.DataKey.MasterKeyID)
+		assert.False(t, rotated.Status.Equals(&w.Status))
+	})
+
+	t.Run("missing master key", func(t *testing.T) {
+		_, err := NewDataKeyProvider(&Config{}, keys, promutils.NewTestScope())
+		assert.Error(t, err)
+	})
+}
+
+func TestEncryptingDataStore(t *testing.T) {
+	ctx := context.TODO()
+	base, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	store := NewDataStore(base, promutils.NewTestScope())
+
+	dataKey := make([]byte, keySize)
+	keyCtx := WithDataKey(ctx, dataKey)
+	outputs, err := coreutils.MakeLiteralMap(map[string]interface{}{"x": 1})
+	assert.NoError(t, err)
+
+	t.Run("encrypted", func(t *testing.T) {
+		closure := &core.CompiledWorkflowClosure{Primary: &core.CompiledWorkflow{Template: &core.WorkflowTemplate{
+			Id: &core.Identifier{Name: "wf"},
+		}}}
+		assert.NoError(t, store.WriteProtobuf(keyCtx, "s3://bucket/futures_compiled.pb", storage.Options{}, closure))
+
+		// The document can't be read without the data key.
+		assert.Error(t, base.ReadProtobuf(ctx, "s3://bucket/futures_compiled.pb", &core.CompiledWorkflowClosure{}))
+		assert.Error(t, store.ReadProtobuf(ctx, "s3://bucket/futures_compiled.pb", &core.CompiledWorkflowClosure{}))
+
+		read := &core.CompiledWorkflowClosure{}
+		assert.NoError(t, store.ReadProtobuf(keyCtx, "s3://bucket/futures_compiled.pb", read))
+		assert.True(t, proto.Equal(closure, read))
+	})
+
+	t.Run("literals", func(t *testing.T) {
+		// Task containers and admin read inputs and outputs without the data key.
+		assert.NoError(t, store.WriteProtobuf(keyCtx, "s3://bucket/outputs.pb", storage.Options{}, outputs))
+		read := &core.LiteralMap{}
+		assert.NoError(t, base.ReadProtobuf(ctx, "s3://bucket/outputs.pb", read))
+		assert.True(t, proto.Equal(outputs, read))
+	})
+
+	t.Run("plaintext", func(t *testing.T) {
+		assert.NoError(t, base.WriteProtobuf(ctx, "s3://bucket/plain.pb", storage.Options{}, outputs))
+		read := &core.LiteralMap{}
+		assert.NoError(t, store.ReadProtobuf(keyCtx, "s3://bucket/plain.pb", read))
+		assert.True(t, proto.Equal(outputs, read))
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		compressing, err := compression.NewDataStore(&compression.Config{Enabled: true, Algorithm: compression.AlgorithmGzip},
+			base, promutils.NewTestScope())
+		assert.NoError(t, err)
+		store := NewDataStore(compressing, promutils.NewTestScope())
+
+		// Futures are encrypted instead of compressed when a data key is available.
+		futures := &core.DynamicJobSpec{MinSuccesses: 1, Nodes: make([]*core.Node, 100)}
+		for i := range futures.Nodes {
+			futures.Nodes[i] = &core.Node{Id: "dn"}
+		}
+		assert.NoError(t, store.WriteProtobuf(ctx, "s3://bucket/futures.pb", storage.Options{}, futures))
+		assert.NoError(t, store.WriteProtobuf(keyCtx, "s3://bucket/encrypted_futures.pb", storage.Options{}, futures))
+
+		for _, reference := range []storage.DataReference{"s3://bucket/futures.pb", "s3://bucket/encrypted_futures.pb"} {
+			read := &core.DynamicJobSpec{}
+			assert.NoError(t, store.ReadProtobuf(keyCtx, reference, read))
+			assert.True(t, proto.Equal(futures, read))
+		}
+
+		assert.Error(t, base.ReadProtobuf(ctx, "s3://bucket/futures.pb", &core.DynamicJobSpec{}))
+	})
+
+	t.Run("other documents", func(t *testing.T) {
+		errorDoc := &core.ErrorDocument{Error: &core.ContainerError{Message: "failed"}}
+		assert.NoError(t, store.WriteProtobuf(keyCtx, "s3://bucket/error.pb", storage.Options{}, errorDoc))
+		assert.NoError(t, base.ReadProtobuf(ctx, "s3://bucket/error.pb", &core.ErrorDocument{}))
+	})
+}
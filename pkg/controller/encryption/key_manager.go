@@ -0,0 +1,162 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// Size of data keys and local master keys, which are used for AES-256.
+const keySize = 32
+
+// KeyManager encrypts and decrypts data keys with master keys that never leave it.
+type KeyManager interface {
+	// GenerateDataKey returns a new data key in plaintext and encrypted with the given master key.
+	GenerateDataKey(ctx context.Context, masterKeyID string) (plaintext, ciphertext []byte, err error)
+	// Encrypt encrypts a data key with the given master key.
+	Encrypt(ctx context.Context, masterKeyID string, plaintext []byte) ([]byte, error)
+	// Decrypt decrypts a data key that was encrypted with the given master key.
+	Decrypt(ctx context.Context, masterKeyID string, ciphertext []byte) ([]byte, error)
+}
+
+// localKeyManager wraps data keys with AES-256-GCM master keys that are held in memory.
+type localKeyManager struct {
+	masterKeys map[string][]byte
+}
+
+func (l localKeyManager) getMasterKey(masterKeyID string) ([]byte, error) {
+	key, found := l.masterKeys[masterKeyID]
+	if !found {
+		return nil, fmt.Errorf("unknown master key [%v]", masterKeyID)
+	}
+
+	return key, nil
+}
+
+func (l localKeyManager) GenerateDataKey(ctx context.Context, masterKeyID string) (plaintext, ciphertext []byte, err error) {
+	plaintext = make([]byte, keySize)
+	if _, err = rand.Read(plaintext); err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err = l.Encrypt(ctx, masterKeyID, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return plaintext, ciphertext, nil
+}
+
+func (l localKeyManager) Encrypt(_ context.Context, masterKeyID string, plaintext []byte) ([]byte, error) {
+	key, err := l.getMasterKey(masterKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return seal(key, plaintext)
+}
+
+func (l localKeyManager) Decrypt(_ context.Context, masterKeyID string, ciphertext []byte) ([]byte, error) {
+	key, err := l.getMasterKey(masterKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(key, ciphertext)
+}
+
+// NewLocalKeyManager reads the base64 encoded master keys from the given files, keyed by their ID.
+func NewLocalKeyManager(masterKeyFiles map[string]string) (KeyManager, error) {
+	masterKeys := make(map[string][]byte, len(masterKeyFiles))
+	for id, path := range masterKeyFiles {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read master key [%v]: %w", id, err)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return nil, fmt.Errorf("master key [%v] is not base64 encoded: %w", id, err)
+		}
+
+		if len(key) != keySize {
+			return nil, fmt.Errorf("master key [%v] has %d bytes, expected %d", id, len(key), keySize)
+		}
+
+		masterKeys[id] = key
+	}
+
+	return localKeyManager{masterKeys: masterKeys}, nil
+}
+
+// awsKMSKeyManager wraps data keys with AWS KMS keys.
+type awsKMSKeyManager struct {
+	client kmsiface.KMSAPI
+}
+
+func (a awsKMSKeyManager) GenerateDataKey(ctx context.Context, masterKeyID string) (plaintext, ciphertext []byte, err error) {
+	resp, err := a.client.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(masterKeyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp.Plaintext, resp.CiphertextBlob, nil
+}
+
+func (a awsKMSKeyManager) Encrypt(ctx context.Context, masterKeyID string, plaintext []byte) ([]byte, error) {
+	resp, err := a.client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(masterKeyID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.CiphertextBlob, nil
+}
+
+func (a awsKMSKeyManager) Decrypt(ctx context.Context, masterKeyID string, ciphertext []byte) ([]byte, error) {
+	resp, err := a.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(masterKeyID),
+		CiphertextBlob: ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Plaintext, nil
+}
+
+// NewAWSKMSKeyManager creates a KeyManager that uses AWS KMS keys in the given region as master keys. Credentials are
+// picked up from the environment.
+func NewAWSKMSKeyManager(region string) (KeyManager, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return nil, err
+	}
+
+	return awsKMSKeyManager{client: kms.New(sess)}, nil
+}
+
+// NewKeyManager creates the KeyManager configured in cfg.
+func NewKeyManager(cfg *Config) (KeyManager, error) {
+	switch cfg.KeyManager {
+	case KeyManagerLocal:
+		return NewLocalKeyManager(cfg.LocalMasterKeys)
+	case KeyManagerAWSKMS:
+		return NewAWSKMSKeyManager(cfg.AWSRegion)
+	}
+
+	return nil, fmt.Errorf("unsupported key manager [%v]", cfg.KeyManager)
+}
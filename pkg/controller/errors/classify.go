@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"context"
+	stdErrors "errors"
+
+	"github.com/flyteorg/flytestdlib/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var grpcCodes = map[codes.Code]ErrorCode{
+	codes.NotFound:           NotFound,
+	codes.AlreadyExists:      AlreadyExists,
+	codes.Aborted:            Conflict,
+	codes.PermissionDenied:   Forbidden,
+	codes.Unauthenticated:    Forbidden,
+	codes.InvalidArgument:    InvalidArgument,
+	codes.FailedPrecondition: InvalidArgument,
+	codes.OutOfRange:         InvalidArgument,
+	codes.Unimplemented:      InvalidArgument,
+	codes.ResourceExhausted:  Throttled,
+	codes.Unavailable:        Unavailable,
+	codes.DeadlineExceeded:   Timeout,
+	codes.Canceled:           Canceled,
+	codes.Internal:           Internal,
+	codes.DataLoss:           Internal,
+	codes.Unknown:            Unknown,
+}
+
+// Returns the code of an error of the kubernetes API, if it is one.
+func fromKubeError(err error) (ErrorCode, bool) {
+	if _, ok := err.(k8serrors.APIStatus); !ok {
+		return "", false
+	}
+
+	switch {
+	case k8serrors.IsNotFound(err), k8serrors.IsGone(err):
+		return NotFound, true
+	case k8serrors.IsAlreadyExists(err):
+		return AlreadyExists, true
+	case k8serrors.IsConflict(err), k8serrors.IsResourceExpired(err):
+		return Conflict, true
+	case k8serrors.IsForbidden(err), k8serrors.IsUnauthorized(err):
+		return Forbidden, true
+	case k8serrors.IsInvalid(err), k8serrors.IsBadRequest(err), k8serrors.IsMethodNotSupported(err),
+		k8serrors.IsNotAcceptable(err), k8serrors.IsUnsupportedMediaType(err):
+		return InvalidArgument, true
+	case k8serrors.IsRequestEntityTooLargeError(err):
+		return TooLarge, true
+	case k8serrors.IsTooManyRequests(err):
+		return Throttled, true
+	case k8serrors.IsServiceUnavailable(err):
+		return Unavailable, true
+	case k8serrors.IsTimeout(err), k8serrors.IsServerTimeout(err):
+		return Timeout, true
+	case k8serrors.IsInternalError(err), k8serrors.IsUnexpectedServerError(err):
+		return Internal, true
+	}
+
+	return Unknown, true
+}
+
+// Returns the code of a grpc status error, if it is one.
+func fromGrpcError(err error) (ErrorCode, bool) {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() == codes.OK {
+		return "", false
+	}
+
+	if code, found := grpcCodes[s.Code()]; found {
+		return code, true
+	}
+
+	return Unknown, true
+}
+
+// Returns the code of an error of the datastore, if it is one.
+func fromStorageError(err error) (ErrorCode, bool) {
+	switch {
+	case storage.IsNotFound(err):
+		return NotFound, true
+	case storage.IsExists(err):
+		return AlreadyExists, true
+	case storage.IsExceedsLimit(err):
+		return TooLarge, true
+	}
+
+	return "", false
+}
+
+func fromContextError(err error) (ErrorCode, bool) {
+	switch err {
+	case context.DeadlineExceeded:
+		return Timeout, true
+	case context.Canceled:
+		return Canceled, true
+	}
+
+	return "", false
+}
+
+// FromError returns the error of the controller the error is, or is caused by. Otherwise, errors of the kubernetes API,
+// grpc status errors, errors of the datastore and context errors in the chain of the error are mapped to their codes,
+// the outermost one wins. Errors that are not recognized are Unknown, and thus retryable.
+func FromError(err error) *Error {
+	type causer interface {
+		Cause() error
+	}
+
+	if err == nil {
+		return Errorf(Unknown, "")
+	}
+
+	for e := err; e != nil; {
+		if controllerErr, ok := asError(e); ok {
+			return controllerErr
+		}
+
+		for _, from := range []func(error) (ErrorCode, bool){fromContextError, fromKubeError, fromGrpcError, fromStorageError} {
+			if code, found := from(e); found {
+				return Wrapf(code, err, "")
+			}
+		}
+
+		if c, ok := e.(causer); ok {
+			e = c.Cause()
+		} else {
+			e = stdErrors.Unwrap(e)
+		}
+	}
+
+	return Wrapf(Unknown, err, "")
+}
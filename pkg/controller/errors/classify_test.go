@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	stdErrors "github.com/flyteorg/flytestdlib/errors"
+	"github.com/flyteorg/flytestdlib/storage"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestFromError(t *testing.T) {
+	resource := schema.GroupResource{Group: "flyte.lyft.com", Resource: "flyteworkflows"}
+	tests := []struct {
+		name      string
+		err       error
+		code      ErrorCode
+		retryable bool
+	}{
+		{"kube-not-found", k8serrors.NewNotFound(resource, "w1"), NotFound, false},
+		{"kube-already-exists", k8serrors.NewAlreadyExists(resource, "w1"), AlreadyExists, false},
+		{"kube-conflict", k8serrors.NewConflict(resource, "w1", fmt.Errorf("stale")), Conflict, true},
+		{"kube-forbidden", k8serrors.NewForbidden(resource, "w1", fmt.Errorf("rbac")), Forbidden, false},
+		{"kube-bad-request", k8serrors.NewBadRequest("bad"), InvalidArgument, false},
+		{"kube-too-large", k8serrors.NewRequestEntityTooLargeError("large"), TooLarge, false},
+		{"kube-too-many-requests", k8serrors.NewTooManyRequests("slow down", 1), Throttled, true},
+		{"kube-unavailable", k8serrors.NewServiceUnavailable("down"), Unavailable, true},
+		{"kube-timeout", k8serrors.NewTimeoutError("slow", 1), Timeout, true},
+		{"kube-internal", k8serrors.NewInternalError(fmt.Errorf("oops")), Internal, true},
+		{"grpc-not-found", status.Error(codes.NotFound, "missing"), NotFound, false},
+		{"grpc-invalid-argument", status.Error(codes.InvalidArgument, "bad"), InvalidArgument, false},
+		{"grpc-resource-exhausted", status.Error(codes.ResourceExhausted, "quota"), Throttled, true},
+		{"grpc-unavailable", status.Error(codes.Unavailable, "down"), Unavailable, true},
+		{"grpc-deadline-exceeded", status.Error(codes.DeadlineExceeded, "slow"), Timeout, true},
+		{"storage-not-found", pkgErrors.Wrap(os.ErrNotExist, "read"), NotFound, false},
+		{"storage-exceeds-limit", stdErrors.Errorf(storage.ErrExceedsLimit, "large"), TooLarge, false},
+		{"context-deadline", context.DeadlineExceeded, Timeout, true},
+		{"context-canceled", context.Canceled, Canceled, true},
+		{"unknown", fmt.Errorf("unrecognized"), Unknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wrapped as the handlers do.
+			for _, err := range []error{
+				tt.err,
+				pkgErrors.Wrap(tt.err, "wrapped"),
+				fmt.Errorf("wrapped: %w", tt.err),
+				stdErrors.Wrapf("SomeCode", tt.err, "wrapped"),
+			} {
+				controllerErr := FromError(err)
+				assert.Equal(t, tt.code, controllerErr.Code(), err.Error())
+				assert.Equal(t, tt.retryable, controllerErr.Retryable(), err.Error())
+				assert.Equal(t, err, controllerErr.Cause())
+			}
+		})
+	}
+}
+
+func TestFromError_Outermost(t *testing.T) {
+	// The outermost recognized error wins.
+	err := Wrapf(InvalidArgument, status.Error(codes.Unavailable, "down"), "bad spec")
+	assert.Equal(t, InvalidArgument, FromError(pkgErrors.Wrap(err, "wrapped")).Code())
+	assert.Equal(t, err, FromError(err))
+
+	err2 := status.Error(codes.Unavailable, fmt.Sprintf("%v", k8serrors.NewBadRequest("bad")))
+	assert.Equal(t, Unavailable, FromError(err2).Code())
+}
+
+func TestMarkPermanent(t *testing.T) {
+	resource := schema.GroupResource{Resource: "pods"}
+	marked := MarkPermanent(pkgErrors.Wrap(k8serrors.NewForbidden(resource, "p", fmt.Errorf("rbac")), "failed to get pod"))
+	found, ok := MarkedNonRetryable(marked)
+	assert.True(t, ok)
+	assert.Equal(t, Forbidden, found.Code())
+
+	_, ok = MarkedNonRetryable(MarkPermanent(k8serrors.NewBadRequest("invalid pod spec")))
+	assert.True(t, ok)
+
+	notFound := k8serrors.NewNotFound(resource, "p")
+	assert.Equal(t, notFound, MarkPermanent(notFound))
+	assert.Nil(t, MarkPermanent(nil))
+}
@@ -0,0 +1,49 @@
+package errors
+
+import "github.com/flyteorg/flytestdlib/errors"
+
+type ErrorCode = errors.ErrorCode
+
+// Codes of the errors of the controller, independent of the system they come from.
+const (
+	NotFound        ErrorCode = "NotFound"
+	AlreadyExists   ErrorCode = "AlreadyExists"
+	Conflict        ErrorCode = "Conflict"
+	Forbidden       ErrorCode = "Forbidden"
+	InvalidArgument ErrorCode = "InvalidArgument"
+	TooLarge        ErrorCode = "TooLarge"
+	Throttled       ErrorCode = "Throttled"
+	Unavailable     ErrorCode = "Unavailable"
+	Timeout         ErrorCode = "Timeout"
+	Canceled        ErrorCode = "Canceled"
+	Internal        ErrorCode = "Internal"
+	Unknown         ErrorCode = "Unknown"
+)
+
+// Errors with these codes are expected to go away if the call that failed is retried, possibly after backing off.
+var retryableCodes = map[ErrorCode]bool{
+	Conflict:    true,
+	Throttled:   true,
+	Unavailable: true,
+	Timeout:     true,
+	Canceled:    true,
+	Internal:    true,
+	Unknown:     true,
+}
+
+// IsRetryableCode returns whether errors with the code are expected to go away if the call that failed is retried.
+func IsRetryableCode(code ErrorCode) bool {
+	return retryableCodes[code]
+}
+
+// Errors with these codes are known not to go away if the call that failed is retried, unlike e.g. NotFound, which may
+// come from an object that is read before it is visible.
+var permanentCodes = map[ErrorCode]bool{
+	Forbidden:       true,
+	InvalidArgument: true,
+}
+
+// IsPermanentCode returns whether errors with the code are known not to go away if the call that failed is retried.
+func IsPermanentCode(code ErrorCode) bool {
+	return permanentCodes[code]
+}
@@ -0,0 +1,163 @@
+// Package errors contains the errors of the controller. They carry a code, whether the call that failed can be retried,
+// and the error that caused them, e.g. an error of the kubernetes API, of a grpc service or of the datastore, which is
+// mapped to a code automatically. The errors support errors.Is, which matches errors by code, and errors.As.
+//
+// The node and task handlers return their own errors, which convert to these errors through errors.As when they are known
+// not to go away when retried, e.g. node errors of a bad specification. Other errors are classified from their causes.
+package errors
+
+import (
+	stdErrors "errors"
+	"fmt"
+)
+
+type Error struct {
+	code      ErrorCode
+	message   string
+	retryable bool
+	// Whether the error was marked as not retryable by the caller, rather than classified from its code.
+	markedNonRetryable bool
+	cause              error
+}
+
+func (e *Error) Code() ErrorCode {
+	return e.code
+}
+
+func (e *Error) Message() string {
+	return e.message
+}
+
+// Retryable returns whether the call that failed is expected to succeed if it is retried.
+func (e *Error) Retryable() bool {
+	return e.retryable
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.cause == nil:
+		return fmt.Sprintf("[%v] %v", e.code, e.message)
+	case len(e.message) == 0:
+		return fmt.Sprintf("[%v] %v", e.code, e.cause)
+	}
+
+	return fmt.Sprintf("[%v] %v, caused by: %v", e.code, e.message, e.cause)
+}
+
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches errors with the same code, so that errors.Is(err, errors.Errorf(NotFound, "")) finds the errors of a code
+// anywhere in the chain of err.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.code == e.code
+}
+
+// Errorf creates an error with the code, that is retryable if the code is.
+func Errorf(code ErrorCode, msgFmt string, args ...interface{}) *Error {
+	return &Error{
+		code:      code,
+		message:   fmt.Sprintf(msgFmt, args...),
+		retryable: IsRetryableCode(code),
+	}
+}
+
+// Wrapf creates an error with the code, caused by the given error. It is retryable if the code is.
+func Wrapf(code ErrorCode, cause error, msgFmt string, args ...interface{}) *Error {
+	e := Errorf(code, msgFmt, args...)
+	e.cause = cause
+	return e
+}
+
+// Wrap wraps the error with the code it maps to, see FromError. It returns nil if the error is nil.
+func Wrap(cause error, msgFmt string, args ...interface{}) error {
+	if cause == nil {
+		return nil
+	}
+
+	return Wrapf(FromError(cause).code, cause, msgFmt, args...)
+}
+
+// NonRetryable marks the error as one that is not expected to go away when the call that failed is retried, whatever
+// its code.
+func (e *Error) NonRetryable() *Error {
+	e.retryable = false
+	e.markedNonRetryable = true
+	return e
+}
+
+// Returns the error as an error of the controller, if it is one or the error of another package of the controller, e.g.
+// a node error, converts to one through its As method, see errors.As.
+func asError(err error) (*Error, bool) {
+	if controllerErr, ok := err.(*Error); ok {
+		return controllerErr, true
+	}
+
+	if converter, ok := err.(interface{ As(interface{}) bool }); ok {
+		var controllerErr *Error
+		if converter.As(&controllerErr) && controllerErr != nil {
+			return controllerErr, true
+		}
+	}
+
+	return nil, false
+}
+
+// MarkPermanent wraps the error with its code, marked as not retryable, if the code is known not to go away when the
+// call that failed is retried, e.g. Forbidden or InvalidArgument. Other errors are returned as they are.
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if code := FromError(err).code; IsPermanentCode(code) {
+		return Wrapf(code, err, "").NonRetryable()
+	}
+
+	return err
+}
+
+// MarkedNonRetryable returns the error in the chain of err that was marked with NonRetryable, if any. Unlike the codes
+// classified automatically, which may come from transient conditions, e.g. an object read before it is visible, these
+// are known not to go away when retried.
+func MarkedNonRetryable(err error) (*Error, bool) {
+	type causer interface {
+		Cause() error
+	}
+
+	for e := err; e != nil; {
+		if controllerErr, ok := asError(e); ok && controllerErr.markedNonRetryable {
+			return controllerErr, true
+		}
+
+		if c, ok := e.(causer); ok {
+			e = c.Cause()
+		} else {
+			e = stdErrors.Unwrap(e)
+		}
+	}
+
+	return nil, false
+}
+
+// GetCode returns the code of the error, see FromError.
+func GetCode(err error) ErrorCode {
+	return FromError(err).code
+}
+
+// Matches returns whether the error has the code, see FromError.
+func Matches(err error, code ErrorCode) bool {
+	return err != nil && GetCode(err) == code
+}
+
+// IsRetryable returns whether the call that failed with the error is expected to succeed if it is retried, see
+// FromError. Errors that are not recognized are retryable.
+func IsRetryable(err error) bool {
+	return err != nil && FromError(err).retryable
+}
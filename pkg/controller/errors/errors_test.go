@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	stdErrors "github.com/flyteorg/flytestdlib/errors"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorf(t *testing.T) {
+	err := Errorf(NotFound, "workflow [%v]", "w1")
+	assert.Equal(t, NotFound, err.Code())
+	assert.Equal(t, "workflow [w1]", err.Message())
+	assert.False(t, err.Retryable())
+	assert.Nil(t, err.Cause())
+	assert.Equal(t, "[NotFound] workflow [w1]", err.Error())
+
+	assert.True(t, Errorf(Unavailable, "").Retryable())
+	assert.False(t, Errorf(Unavailable, "").NonRetryable().Retryable())
+}
+
+func TestWrapf(t *testing.T) {
+	cause := fmt.Errorf("some error")
+	err := Wrapf(Internal, cause, "failed [%v]", 1)
+	assert.Equal(t, Internal, err.Code())
+	assert.True(t, err.Retryable())
+	assert.Equal(t, cause, err.Cause())
+	assert.Equal(t, cause, errors.Unwrap(err))
+	assert.Equal(t, cause, pkgErrors.Cause(err))
+	assert.Equal(t, "[Internal] failed [1], caused by: some error", err.Error())
+	assert.Equal(t, "[Internal] some error", Wrapf(Internal, cause, "").Error())
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(nil, "msg"))
+
+	err := Wrap(Errorf(Throttled, "slow down"), "failed to launch")
+	assert.True(t, Matches(err, Throttled))
+	assert.True(t, IsRetryable(err))
+	assert.Equal(t, "[Throttled] failed to launch, caused by: [Throttled] slow down", err.Error())
+}
+
+func TestIsAs(t *testing.T) {
+	err := fmt.Errorf("outer: %w", pkgErrors.Wrap(Errorf(Conflict, "stale"), "middle"))
+	assert.True(t, errors.Is(err, Errorf(Conflict, "")))
+	assert.False(t, errors.Is(err, Errorf(NotFound, "")))
+
+	var controllerErr *Error
+	if assert.True(t, errors.As(err, &controllerErr)) {
+		assert.Equal(t, Conflict, controllerErr.Code())
+	}
+
+	// Works with the error codes of the std lib too.
+	assert.True(t, stdErrors.IsCausedBy(err, Conflict))
+}
+
+func TestMatchesAndIsRetryable(t *testing.T) {
+	assert.False(t, Matches(nil, Unknown))
+	assert.False(t, IsRetryable(nil))
+	assert.True(t, Matches(fmt.Errorf("unrecognized"), Unknown))
+	assert.True(t, IsRetryable(fmt.Errorf("unrecognized")))
+	assert.False(t, IsRetryable(Errorf(InvalidArgument, "bad")))
+	assert.Equal(t, InvalidArgument, GetCode(pkgErrors.Wrap(Errorf(InvalidArgument, "bad"), "wrapped")))
+}
+
+func TestMarkedNonRetryable(t *testing.T) {
+	marked := Errorf(InvalidArgument, "invalid spec").NonRetryable()
+	found, ok := MarkedNonRetryable(pkgErrors.Wrap(fmt.Errorf("failed: %w", marked), "failed to launch"))
+	assert.True(t, ok)
+	assert.Equal(t, marked, found)
+
+	_, ok = MarkedNonRetryable(Errorf(InvalidArgument, "invalid spec"))
+	assert.False(t, ok)
+
+	_, ok = MarkedNonRetryable(fmt.Errorf("some error"))
+	assert.False(t, ok)
+}
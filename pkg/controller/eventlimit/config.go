@@ -0,0 +1,43 @@
+package eventlimit
+
+import (
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "event-rate-limit"
+
+var (
+	defaultConfig = &Config{
+		Enabled:       false,
+		Rate:          10,
+		Burst:         50,
+		MaxExecutions: 10000,
+		ExecutionTTL:  config.Duration{Duration: time.Hour},
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls the per execution rate limiting of events sent to admin. Phase transitions are always sent, only
+// repeated updates of an unchanged phase are coalesced once an execution exceeds its rate.
+type Config struct {
+	Enabled       bool            `json:"enabled" pflag:",Enables rate limiting events per execution."`
+	Rate          int             `json:"rate" pflag:",Sustained number of events per second allowed for a single execution."`
+	Burst         int             `json:"burst" pflag:",Number of events a single execution can send in a burst above its rate."`
+	MaxExecutions int             `json:"max-executions" pflag:",Maximum number of executions whose rate limiting state is kept in memory."`
+	ExecutionTTL  config.Duration `json:"execution-ttl" pflag:",Duration after which the rate limiting state of an execution without events is dropped."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,178 @@
+// Package eventlimit protects admin from executions that produce a flood of events. Every execution gets its own
+// token bucket. Phase transitions are always sent, but once an execution runs out of tokens, repeated updates of an
+// entity whose phase did not change are coalesced: they are dropped and only their number is reported with the next
+// event sent for the same entity.
+package eventlimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type sinkMetrics struct {
+	EventsSent      labeled.Counter
+	EventsCoalesced labeled.Counter
+}
+
+// executionState tracks the rate limit and the last phase sent for every entity of a single execution.
+type executionState struct {
+	lock      sync.Mutex
+	limiter   *rate.Limiter
+	lastPhase map[string]string
+	coalesced map[string]uint32
+}
+
+// eventSink decorates an events.EventSink and coalesces repeated updates of executions that exceed their rate.
+type eventSink struct {
+	events.EventSink
+	cfg        *Config
+	lock       sync.Mutex
+	executions *cache.LRUExpireCache
+	metrics    *sinkMetrics
+}
+
+// describedEvent identifies the execution and the entity an event belongs to, and the phase the entity is in.
+type describedEvent struct {
+	execID   *core.WorkflowExecutionIdentifier
+	entity   string
+	phase    string
+	terminal bool
+}
+
+func describe(message proto.Message) (describedEvent, bool) {
+	switch e := message.(type) {
+	case *event.WorkflowExecutionEvent:
+		return describedEvent{
+			execID:   e.GetExecutionId(),
+			entity:   "workflow",
+			phase:    e.GetPhase().String(),
+			terminal: isTerminalWorkflowPhase(e.GetPhase()),
+		}, e.GetExecutionId() != nil
+	case *event.NodeExecutionEvent:
+		return describedEvent{
+			execID: e.GetId().GetExecutionId(),
+			entity: fmt.Sprintf("node/%v/%v", e.GetId().GetNodeId(), e.GetRetryGroup()),
+			phase:  e.GetPhase().String(),
+		}, e.GetId().GetExecutionId() != nil
+	case *event.TaskExecutionEvent:
+		execID := e.GetParentNodeExecutionId().GetExecutionId()
+		return describedEvent{
+			execID: execID,
+			entity: fmt.Sprintf("task/%v/%v/%v", e.GetParentNodeExecutionId().GetNodeId(), e.GetTaskId().GetName(),
+				e.GetRetryAttempt()),
+			phase: e.GetPhase().String(),
+		}, execID != nil
+	default:
+		return describedEvent{}, false
+	}
+}
+
+func isTerminalWorkflowPhase(p core.WorkflowExecution_Phase) bool {
+	switch p {
+	case core.WorkflowExecution_SUCCEEDED, core.WorkflowExecution_FAILED, core.WorkflowExecution_ABORTED,
+		core.WorkflowExecution_TIMED_OUT:
+		return true
+	}
+
+	return false
+}
+
+func executionKey(execID *core.WorkflowExecutionIdentifier) string {
+	return fmt.Sprintf("%v/%v/%v", execID.GetProject(), execID.GetDomain(), execID.GetName())
+}
+
+func (s *eventSink) getOrCreateState(key string) *executionState {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if v, ok := s.executions.Get(key); ok {
+		return v.(*executionState)
+	}
+
+	state := &executionState{
+		limiter:   rate.NewLimiter(rate.Limit(s.cfg.Rate), s.cfg.Burst),
+		lastPhase: map[string]string{},
+		coalesced: map[string]uint32{},
+	}
+
+	s.executions.Add(key, state, s.cfg.ExecutionTTL.Duration)
+	return state
+}
+
+// Task events carry a free form reason, which is used to report how many updates were coalesced before this one.
+func withCoalescedCount(message proto.Message, count uint32) proto.Message {
+	taskEvent, ok := message.(*event.TaskExecutionEvent)
+	if !ok {
+		return message
+	}
+
+	annotated := proto.Clone(taskEvent).(*event.TaskExecutionEvent)
+	annotated.Reason = strings.TrimSpace(fmt.Sprintf("%v [%d similar updates coalesced]", annotated.Reason, count))
+	return annotated
+}
+
+func (s *eventSink) Sink(ctx context.Context, message proto.Message) error {
+	described, ok := describe(message)
+	if !ok {
+		return s.EventSink.Sink(ctx, message)
+	}
+
+	key := executionKey(described.execID)
+	state := s.getOrCreateState(key)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	// Every event consumes a token, so that transitions count towards the rate as well, but only repeats are dropped.
+	allowed := state.limiter.Allow()
+	if !allowed && state.lastPhase[described.entity] == described.phase {
+		state.coalesced[described.entity]++
+		s.metrics.EventsCoalesced.Inc(ctx)
+		logger.Debugf(ctx, "Coalescing repeated event for [%v] of execution [%v] in phase [%v]", described.entity, key,
+			described.phase)
+		return nil
+	}
+
+	if count := state.coalesced[described.entity]; count > 0 {
+		message = withCoalescedCount(message, count)
+	}
+
+	if err := s.EventSink.Sink(ctx, message); err != nil {
+		return err
+	}
+
+	s.metrics.EventsSent.Inc(ctx)
+	state.lastPhase[described.entity] = described.phase
+	delete(state.coalesced, described.entity)
+	if described.terminal {
+		s.executions.Remove(key)
+	}
+
+	return nil
+}
+
+// NewEventSink wraps the given EventSink so that repeated updates of executions that exceed the configured rate are
+// coalesced.
+func NewEventSink(sink events.EventSink, cfg *Config, scope promutils.Scope) events.EventSink {
+	return &eventSink{
+		EventSink:  sink,
+		cfg:        cfg,
+		executions: cache.NewLRUExpireCache(cfg.MaxExecutions),
+		metrics: &sinkMetrics{
+			EventsSent:      labeled.NewCounter("events_sent", "Number of events passed on by the rate limiter", scope),
+			EventsCoalesced: labeled.NewCounter("events_coalesced", "Number of repeated events dropped by the rate limiter", scope),
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package eventlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/testkit"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NamespaceKey)
+}
+
+func newExecID(name string) *core.WorkflowExecutionIdentifier {
+	return &core.WorkflowExecutionIdentifier{Project: "project", Domain: "domain", Name: name}
+}
+
+func newTaskEvent(execID *core.WorkflowExecutionIdentifier, phase core.TaskExecution_Phase, version uint32) *event.TaskExecutionEvent {
+	return &event.TaskExecutionEvent{
+		TaskId:                &core.Identifier{Name: "task"},
+		ParentNodeExecutionId: &core.NodeExecutionIdentifier{NodeId: "n1", ExecutionId: execID},
+		Phase:                 phase,
+		PhaseVersion:          version,
+	}
+}
+
+func TestEventSink_Sink(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &Config{
+		Enabled:       true,
+		Rate:          1,
+		Burst:         2,
+		MaxExecutions: 10,
+		ExecutionTTL:  config.Duration{Duration: time.Hour},
+	}
+
+	t.Run("coalesce-repeats", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		sink := NewEventSink(recorder, cfg, promutils.NewTestScope())
+		execID := newExecID("coalesce")
+
+		for version := uint32(0); version < 5; version++ {
+			assert.NoError(t, sink.Sink(ctx, newTaskEvent(execID, core.TaskExecution_RUNNING, version)))
+		}
+
+		// The burst lets the first two through, the remaining repeats are coalesced.
+		assert.Len(t, recorder.TaskEvents(), 2)
+
+		// A phase transition is always sent and reports the coalesced updates.
+		assert.NoError(t, sink.Sink(ctx, newTaskEvent(execID, core.TaskExecution_SUCCEEDED, 0)))
+		taskEvents := recorder.TaskEvents()
+		if assert.Len(t, taskEvents, 3) {
+			assert.Equal(t, core.TaskExecution_SUCCEEDED, taskEvents[2].GetPhase())
+			assert.Equal(t, "[3 similar updates coalesced]", taskEvents[2].GetReason())
+		}
+	})
+
+	t.Run("per-execution", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		sink := NewEventSink(recorder, cfg, promutils.NewTestScope())
+
+		for _, name := range []string{"a", "b", "c"} {
+			for version := uint32(0); version < 2; version++ {
+				assert.NoError(t, sink.Sink(ctx, newTaskEvent(newExecID(name), core.TaskExecution_RUNNING, version)))
+			}
+		}
+
+		assert.Len(t, recorder.TaskEvents(), 6)
+	})
+
+	t.Run("terminal-workflow-event", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		sink := NewEventSink(recorder, cfg, promutils.NewTestScope())
+		execID := newExecID("terminal")
+
+		for version := uint32(0); version < 3; version++ {
+			assert.NoError(t, sink.Sink(ctx, newTaskEvent(execID, core.TaskExecution_RUNNING, version)))
+		}
+		assert.Len(t, recorder.TaskEvents(), 2)
+
+		assert.NoError(t, sink.Sink(ctx, &event.WorkflowExecutionEvent{ExecutionId: execID, Phase: core.WorkflowExecution_ABORTED}))
+		assert.Len(t, recorder.WorkflowEvents(), 1)
+
+		// The state of the execution was dropped, so it gets a fresh bucket.
+		assert.NoError(t, sink.Sink(ctx, newTaskEvent(execID, core.TaskExecution_RUNNING, 3)))
+		assert.Len(t, recorder.TaskEvents(), 3)
+	})
+
+	t.Run("sink-failure", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		recorder.SinkErr = assert.AnError
+		sink := NewEventSink(recorder, cfg, promutils.NewTestScope())
+
+		assert.Error(t, sink.Sink(ctx, newTaskEvent(newExecID("failure"), core.TaskExecution_RUNNING, 0)))
+	})
+}
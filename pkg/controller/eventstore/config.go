@@ -0,0 +1,40 @@
+package eventstore
+
+import (
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "event-store"
+
+var (
+	defaultConfig = &Config{
+		Enabled:       false,
+		ObjectName:    "events.jsonl",
+		ReplayBackoff: config.Duration{Duration: time.Second},
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls storing workflow, node and task events in the metadata store instead of sending them to admin, for
+// clusters that cannot reach admin. Stored events are pushed to admin later with the events replay command of
+// kubectl-flyte.
+type Config struct {
+	Enabled       bool            `json:"enabled" pflag:",Stores events in the metadata store instead of sending them to admin."`
+	ObjectName    string          `json:"object-name" pflag:",Name of the events object created under the execution's metadata prefix."`
+	ReplayBackoff config.Duration `json:"replay-backoff" pflag:",Time to wait before retrying events that admin throttled during a replay."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,214 @@
+package eventstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	eventsErr "github.com/flyteorg/flyteidl/clients/go/events/errors"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Suffix of the object that records how many events of an execution have been replayed.
+const checkpointSuffix = ".replayed"
+
+// ReplayResult summarizes the replay of the events of an execution.
+type ReplayResult struct {
+	// Number of events the sink accepted.
+	Sent int
+	// Number of events the sink rejected because it already had them, or because their execution already completed.
+	Skipped int
+	// Number of events that had been replayed before and were not sent again.
+	Previous int
+}
+
+// Replayer pushes the stored events of executions to an event sink, typically the admin event sink. The number of events
+// replayed is checkpointed next to the events object, so that replays can be resumed and repeated safely.
+type Replayer struct {
+	store      *storage.DataStore
+	basePrefix storage.DataReference
+	objectName string
+	sink       events.EventSink
+	backoff    time.Duration
+	sleep      func(time.Duration)
+}
+
+func toEvent(record Record) (proto.Message, error) {
+	var message proto.Message
+	switch record.Kind {
+	case EventKindWorkflow:
+		message = &event.WorkflowExecutionEvent{}
+	case EventKindNode:
+		message = &event.NodeExecutionEvent{}
+	case EventKindTask:
+		message = &event.TaskExecutionEvent{}
+	default:
+		return nil, fmt.Errorf("unknown event kind [%v]", record.Kind)
+	}
+
+	if err := jsonpb.UnmarshalString(string(record.Event), message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (r *Replayer) readCheckpoint(ctx context.Context, ref storage.DataReference) (int, error) {
+	reader, err := r.store.ReadRaw(ctx, ref)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Debugf(ctx, "Failed to close checkpoint reader. Error: %v", closeErr)
+		}
+	}()
+
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(raw)))
+}
+
+func (r *Replayer) writeCheckpoint(ctx context.Context, ref storage.DataReference, replayed int) error {
+	raw := strconv.Itoa(replayed)
+	return r.store.WriteRaw(ctx, ref, int64(len(raw)), storage.Options{}, strings.NewReader(raw))
+}
+
+// Sends the event, waiting for the configured backoff whenever the sink throttles. Returns true if the sink already had
+// the event.
+func (r *Replayer) send(ctx context.Context, message proto.Message) (bool, error) {
+	for {
+		err := r.sink.Sink(ctx, message)
+		switch {
+		case err == nil:
+			return false, nil
+		case eventsErr.IsAlreadyExists(err), eventsErr.IsEventAlreadyInTerminalStateError(err):
+			return true, nil
+		case eventsErr.IsResourceExhausted(err):
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+
+			r.sleep(r.backoff)
+		default:
+			return false, err
+		}
+	}
+}
+
+// Replay sends the stored events of the execution that have not been replayed yet, in the order they were recorded.
+// Replay stops at the first event the sink fails to accept. The events replayed until then are checkpointed, so the
+// next replay resumes from that event.
+func (r *Replayer) Replay(ctx context.Context, execID *core.WorkflowExecutionIdentifier) (ReplayResult, error) {
+	result := ReplayResult{}
+	ref, err := GetEventsReference(ctx, r.store, r.basePrefix, r.objectName, execID)
+	if err != nil {
+		return result, err
+	}
+
+	checkpointRef := storage.DataReference(string(ref) + checkpointSuffix)
+	if result.Previous, err = r.readCheckpoint(ctx, checkpointRef); err != nil {
+		return result, err
+	}
+
+	reader, err := r.store.ReadRaw(ctx, ref)
+	if err != nil {
+		return result, err
+	}
+
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Debugf(ctx, "Failed to close events object reader. Error: %v", closeErr)
+		}
+	}()
+
+	replayed := 0
+	var replayErr error
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 64*1024*1024)
+	for scanner.Scan() {
+		replayed++
+		if replayed <= result.Previous {
+			continue
+		}
+
+		record := Record{}
+		if replayErr = json.Unmarshal(scanner.Bytes(), &record); replayErr != nil {
+			break
+		}
+
+		message, err := toEvent(record)
+		if err != nil {
+			replayErr = err
+			break
+		}
+
+		skipped, err := r.send(ctx, message)
+		if err != nil {
+			replayErr = err
+			break
+		} else if skipped {
+			result.Skipped++
+		} else {
+			result.Sent++
+		}
+	}
+
+	if replayErr == nil {
+		replayErr = scanner.Err()
+	} else {
+		// The event that failed was not replayed.
+		replayed--
+	}
+
+	if replayed > result.Previous {
+		if err := r.writeCheckpoint(ctx, checkpointRef, replayed); err != nil {
+			logger.Warnf(ctx, "Failed to checkpoint replay of execution [%v]. Error: %v", execID, err)
+		}
+	}
+
+	if replayErr != nil {
+		return result, fmt.Errorf("failed to replay event [%v] of execution [%v]: %w", replayed+1, execID, replayErr)
+	}
+
+	return result, nil
+}
+
+// NewReplayer creates a Replayer that sends the events stored under the given metadata prefix to the sink.
+func NewReplayer(ctx context.Context, store *storage.DataStore, metadataPrefix string, cfg *Config,
+	sink events.EventSink) (*Replayer, error) {
+
+	basePrefix, err := getBasePrefix(ctx, store, metadataPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		store:      store,
+		basePrefix: basePrefix,
+		objectName: cfg.ObjectName,
+		sink:       sink,
+		backoff:    cfg.ReplayBackoff.Duration,
+		sleep:      time.Sleep,
+	}, nil
+}
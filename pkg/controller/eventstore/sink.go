@@ -0,0 +1,179 @@
+// Package eventstore stores workflow, node and task events in the metadata store instead of sending them to admin, so
+// that workflows can be executed in clusters that cannot reach admin. The events are written as JSON lines to a single
+// object per execution, stored next to the execution's metadata, and are pushed to admin later by a Replayer.
+package eventstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type EventKind = string
+
+const (
+	EventKindWorkflow EventKind = "workflow"
+	EventKindNode     EventKind = "node"
+	EventKindTask     EventKind = "task"
+)
+
+// Record is a single line in the events object and holds one event, marshaled with jsonpb.
+type Record struct {
+	Kind  EventKind       `json:"kind"`
+	Event json.RawMessage `json:"event"`
+}
+
+type sinkMetrics struct {
+	EventsStored  labeled.Counter
+	StoreFailures labeled.Counter
+}
+
+// eventSink is an events.EventSink that appends every event to the events object of the execution it belongs to.
+type eventSink struct {
+	store      *storage.DataStore
+	basePrefix storage.DataReference
+	objectName string
+	metrics    *sinkMetrics
+}
+
+var jsonMarshaler = jsonpb.Marshaler{}
+
+// GetEventsReference returns the location of the events object for the given execution.
+func GetEventsReference(ctx context.Context, store storage.ReferenceConstructor, basePrefix storage.DataReference,
+	objectName string, execID *core.WorkflowExecutionIdentifier) (storage.DataReference, error) {
+
+	return store.ConstructReference(ctx, basePrefix,
+		fmt.Sprintf("%v-%v-%v", execID.GetProject(), execID.GetDomain(), execID.GetName()), objectName)
+}
+
+// Returns the execution the event belongs to and the record that stores it.
+func toRecord(message proto.Message) (*core.WorkflowExecutionIdentifier, Record, error) {
+	var execID *core.WorkflowExecutionIdentifier
+	record := Record{}
+	switch e := message.(type) {
+	case *event.WorkflowExecutionEvent:
+		record.Kind = EventKindWorkflow
+		execID = e.GetExecutionId()
+	case *event.NodeExecutionEvent:
+		record.Kind = EventKindNode
+		execID = e.GetId().GetExecutionId()
+	case *event.TaskExecutionEvent:
+		record.Kind = EventKindTask
+		execID = e.GetParentNodeExecutionId().GetExecutionId()
+	default:
+		return nil, record, fmt.Errorf("unknown event type [%T]", message)
+	}
+
+	if execID == nil {
+		return nil, record, fmt.Errorf("event of kind [%v] has no execution id", record.Kind)
+	}
+
+	raw, err := jsonMarshaler.MarshalToString(message)
+	if err != nil {
+		return nil, record, err
+	}
+
+	record.Event = json.RawMessage(raw)
+	return execID, record, nil
+}
+
+// Appends the record as a JSON line to the object at the given reference.
+func appendRecord(ctx context.Context, store *storage.DataStore, ref storage.DataReference, record Record) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	// Object stores do not support appends, so the existing object is read and written back with the new record.
+	// Workflows are evaluated by a single worker at a time, hence there are no concurrent writers for one execution.
+	buf := &bytes.Buffer{}
+	reader, err := store.ReadRaw(ctx, ref)
+	if err != nil && !storage.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		existing, readErr := ioutil.ReadAll(reader)
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Debugf(ctx, "Failed to close events object reader. Error: %v", closeErr)
+		}
+
+		if readErr != nil {
+			return readErr
+		}
+
+		buf.Write(existing)
+	}
+
+	buf.Write(raw)
+	buf.WriteByte('\n')
+	return store.WriteRaw(ctx, ref, int64(buf.Len()), storage.Options{}, buf)
+}
+
+func (s *eventSink) Sink(ctx context.Context, message proto.Message) error {
+	execID, record, err := toRecord(message)
+	if err != nil {
+		return err
+	}
+
+	ref, err := GetEventsReference(ctx, s.store, s.basePrefix, s.objectName, execID)
+	if err != nil {
+		return err
+	}
+
+	// The stored events are the only record of the execution, failures are returned so that the event is retried.
+	if err := appendRecord(ctx, s.store, ref, record); err != nil {
+		s.metrics.StoreFailures.Inc(ctx)
+		logger.Warnf(ctx, "Failed to store %v event of execution [%v]. Error: %v", record.Kind, execID, err)
+		return err
+	}
+
+	s.metrics.EventsStored.Inc(ctx)
+	return nil
+}
+
+func (s *eventSink) Close() error {
+	return nil
+}
+
+// Returns the prefix the events objects are stored under, which is the same metadata prefix the workflow executor uses.
+func getBasePrefix(ctx context.Context, store *storage.DataStore, metadataPrefix string) (storage.DataReference, error) {
+	basePrefix := store.GetBaseContainerFQN(ctx)
+	if metadataPrefix == "" {
+		return basePrefix, nil
+	}
+
+	return store.ConstructReference(ctx, basePrefix, metadataPrefix)
+}
+
+// NewEventSink creates an EventSink that stores every event in the events object of its execution, in place of the
+// admin event sink.
+func NewEventSink(ctx context.Context, store *storage.DataStore, metadataPrefix string, cfg *Config,
+	scope promutils.Scope) (events.EventSink, error) {
+
+	basePrefix, err := getBasePrefix(ctx, store, metadataPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventSink{
+		store:      store,
+		basePrefix: basePrefix,
+		objectName: cfg.ObjectName,
+		metrics: &sinkMetrics{
+			EventsStored:  labeled.NewCounter("events_stored", "Number of events stored in the metadata store", scope),
+			StoreFailures: labeled.NewCounter("store_failures", "Number of events that failed to be stored", scope),
+		},
+	}, nil
+}
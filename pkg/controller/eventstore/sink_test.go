@@ -0,0 +1,130 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	eventsErr "github.com/flyteorg/flyteidl/clients/go/events/errors"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.ProjectKey, contextutils.DomainKey, contextutils.WorkflowIDKey, contextutils.TaskIDKey)
+}
+
+var execID = &core.WorkflowExecutionIdentifier{
+	Project: "project",
+	Domain:  "domain",
+	Name:    "name",
+}
+
+// fakeSink records the events it accepts and fails the calls with the queued errors first.
+type fakeSink struct {
+	errs     []error
+	received []proto.Message
+}
+
+func (f *fakeSink) Sink(_ context.Context, message proto.Message) error {
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		if err != nil {
+			return err
+		}
+	}
+
+	f.received = append(f.received, message)
+	return nil
+}
+
+func (f *fakeSink) Close() error {
+	return nil
+}
+
+func TestEventSinkAndReplayer(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	cfg := &Config{ObjectName: "events.jsonl"}
+	sink, err := NewEventSink(ctx, store, "metadata", cfg, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	recorded := []proto.Message{
+		&event.WorkflowExecutionEvent{ExecutionId: execID, Phase: core.WorkflowExecution_RUNNING},
+		&event.NodeExecutionEvent{
+			Id:    &core.NodeExecutionIdentifier{ExecutionId: execID, NodeId: "n1"},
+			Phase: core.NodeExecution_RUNNING,
+		},
+		&event.TaskExecutionEvent{
+			TaskId:                &core.Identifier{Name: "task"},
+			ParentNodeExecutionId: &core.NodeExecutionIdentifier{ExecutionId: execID, NodeId: "n1"},
+			Phase:                 core.TaskExecution_RUNNING,
+		},
+	}
+
+	for _, e := range recorded {
+		assert.NoError(t, sink.Sink(ctx, e))
+	}
+
+	assert.Error(t, sink.Sink(ctx, &event.WorkflowExecutionEvent{}))
+	assert.Error(t, sink.Sink(ctx, &core.Identifier{}))
+
+	ref, err := GetEventsReference(ctx, store, "metadata", cfg.ObjectName, execID)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.DataReference("/metadata/project-domain-name/events.jsonl"), ref)
+
+	newReplayer := func(target *fakeSink) *Replayer {
+		replayer, err := NewReplayer(ctx, store, "metadata", cfg, target)
+		assert.NoError(t, err)
+		replayer.sleep = func(time.Duration) {}
+		return replayer
+	}
+
+	t.Run("replay stops at failures and resumes", func(t *testing.T) {
+		target := &fakeSink{errs: []error{
+			nil,
+			eventsErr.WrapError(fmt.Errorf("unavailable")),
+		}}
+
+		result, err := newReplayer(target).Replay(ctx, execID)
+		assert.Error(t, err)
+		assert.Equal(t, 1, result.Sent)
+		assert.Len(t, target.received, 1)
+		assert.True(t, proto.Equal(recorded[0], target.received[0]))
+
+		target.errs = []error{
+			&eventsErr.EventError{Code: eventsErr.ResourceExhausted, Cause: fmt.Errorf("throttled")},
+			&eventsErr.EventError{Code: eventsErr.AlreadyExists, Cause: fmt.Errorf("exists")},
+		}
+
+		result, err = newReplayer(target).Replay(ctx, execID)
+		assert.NoError(t, err)
+		assert.Equal(t, ReplayResult{Sent: 1, Skipped: 1, Previous: 1}, result)
+		if assert.Len(t, target.received, 2) {
+			assert.True(t, proto.Equal(recorded[2], target.received[1]))
+		}
+	})
+
+	t.Run("replay is checkpointed", func(t *testing.T) {
+		target := &fakeSink{}
+		result, err := newReplayer(target).Replay(ctx, execID)
+		assert.NoError(t, err)
+		assert.Equal(t, ReplayResult{Previous: 3}, result)
+		assert.Empty(t, target.received)
+	})
+
+	t.Run("no events", func(t *testing.T) {
+		_, err := newReplayer(&fakeSink{}).Replay(ctx, &core.WorkflowExecutionIdentifier{Name: "other"})
+		assert.Error(t, err)
+	})
+}
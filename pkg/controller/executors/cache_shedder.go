@@ -0,0 +1,12 @@
+package executors
+
+import (
+	"context"
+)
+
+// CacheShedder is implemented by components that keep in-memory caches which can be dropped at any time, at the cost
+// of recomputing or refetching the cached values later. It is used to relieve memory pressure on propeller.
+type CacheShedder interface {
+	// ShedCache drops all the cached entries.
+	ShedCache(ctx context.Context)
+}
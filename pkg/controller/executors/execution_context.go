@@ -1,6 +1,8 @@
 package executors
 
 import (
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 )
 
@@ -28,6 +30,14 @@ type ImmutableParentInfo interface {
 	CurrentAttempt() uint32
 }
 
+// ImmutableParentTaskInfo is implemented by the parent info of nodes that were generated by a task execution, e.g. the
+// nodes of a dynamic workflow, so that their events can be nested under that task execution.
+type ImmutableParentTaskInfo interface {
+	ImmutableParentInfo
+	// Returns the task execution that generated the nodes, nil if they were not generated by a task.
+	GetParentTaskID() *core.TaskExecutionIdentifier
+}
+
 type ControlFlow interface {
 	CurrentParallelism() uint32
 	IncrementParallelism() uint32
@@ -56,6 +66,7 @@ func (e execContext) GetParentInfo() ImmutableParentInfo {
 type parentExecutionInfo struct {
 	uniqueID        v1alpha1.NodeID
 	currentAttempts uint32
+	parentTaskID    *core.TaskExecutionIdentifier
 }
 
 func (p *parentExecutionInfo) GetUniqueID() v1alpha1.NodeID {
@@ -66,6 +77,10 @@ func (p *parentExecutionInfo) CurrentAttempt() uint32 {
 	return p.currentAttempts
 }
 
+func (p *parentExecutionInfo) GetParentTaskID() *core.TaskExecutionIdentifier {
+	return p.parentTaskID
+}
+
 type controlFlow struct {
 	// We could use atomic.Uint32, but this is not required for current Propeller. As every round is run in a single
 	// thread and using atomic will introduce memory barriers
@@ -110,6 +125,15 @@ func NewParentInfo(uniqueID string, currentAttempts uint32) ImmutableParentInfo
 	}
 }
 
+// NewParentTaskInfo creates the parent info of nodes that were generated by the given task execution.
+func NewParentTaskInfo(uniqueID string, currentAttempts uint32, parentTaskID *core.TaskExecutionIdentifier) ImmutableParentTaskInfo {
+	return &parentExecutionInfo{
+		currentAttempts: currentAttempts,
+		uniqueID:        uniqueID,
+		parentTaskID:    parentTaskID,
+	}
+}
+
 func InitializeControlFlow() ControlFlow {
 	return &controlFlow{
 		v: 0,
@@ -2,7 +2,10 @@ package executors
 
 import (
 	"context"
+	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
@@ -44,8 +47,43 @@ func (c fallbackClientReader) List(ctx context.Context, list client.ObjectList,
 	return
 }
 
+// ownerReferenceFilteringClient drops owner references of the configured kinds from objects before writing them. It is
+// used for clusters that do not hold the owners, where the garbage collector would otherwise delete the objects. Kinds
+// are compared case-insensitively, since owner references to CRDs are not always built with the registered kind.
+type ownerReferenceFilteringClient struct {
+	client.Client
+	kinds sets.String
+}
+
+func (c ownerReferenceFilteringClient) filterOwnerReferences(obj client.Object) {
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 {
+		return
+	}
+
+	filtered := make([]metav1.OwnerReference, 0, len(refs))
+	for _, ref := range refs {
+		if !c.kinds.Has(strings.ToLower(ref.Kind)) {
+			filtered = append(filtered, ref)
+		}
+	}
+
+	obj.SetOwnerReferences(filtered)
+}
+
+func (c ownerReferenceFilteringClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.filterOwnerReferences(obj)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c ownerReferenceFilteringClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.filterOwnerReferences(obj)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
 type FallbackClientBuilder struct {
-	uncached []client.Object
+	uncached           []client.Object
+	filteredOwnerKinds []string
 }
 
 func (f *FallbackClientBuilder) WithUncached(objs ...client.Object) cluster.ClientBuilder {
@@ -53,13 +91,20 @@ func (f *FallbackClientBuilder) WithUncached(objs ...client.Object) cluster.Clie
 	return f
 }
 
+// WithoutOwnerReferences drops owner references of the given kinds from all objects written by the client. This is
+// needed when the client talks to a different cluster than the one that holds the owners.
+func (f *FallbackClientBuilder) WithoutOwnerReferences(kinds ...string) *FallbackClientBuilder {
+	f.filteredOwnerKinds = append(f.filteredOwnerKinds, kinds...)
+	return f
+}
+
 func (f FallbackClientBuilder) Build(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
 	c, err := client.New(config, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return client.NewDelegatingClient(client.NewDelegatingClientInput{
+	delegatingClient, err := client.NewDelegatingClient(client.NewDelegatingClientInput{
 		Client: c,
 		CacheReader: fallbackClientReader{
 			orderedClients: []client.Reader{cache, c},
@@ -68,6 +113,19 @@ func (f FallbackClientBuilder) Build(cache cache.Cache, config *rest.Config, opt
 		// TODO figure out if this should be true?
 		// CacheUnstructured: true,
 	})
+	if err != nil || len(f.filteredOwnerKinds) == 0 {
+		return delegatingClient, err
+	}
+
+	kinds := sets.NewString()
+	for _, kind := range f.filteredOwnerKinds {
+		kinds.Insert(strings.ToLower(kind))
+	}
+
+	return ownerReferenceFilteringClient{
+		Client: delegatingClient,
+		kinds:  kinds,
+	}, nil
 }
 
 // Creates a new k8s client that uses the cached client for reads and falls back to making API
@@ -0,0 +1,43 @@
+package executors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOwnerReferenceFilteringClient(t *testing.T) {
+	ctx := context.TODO()
+	c := ownerReferenceFilteringClient{
+		Client: fake.NewClientBuilder().Build(),
+		kinds:  sets.NewString("flyteworkflow"),
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "ns",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "FlyteWorkflow", Name: "wf"},
+				{Kind: "ConfigMap", Name: "cm"},
+			},
+		},
+	}
+
+	assert.NoError(t, c.Create(ctx, pod))
+
+	created := &v1.Pod{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "pod"}, created))
+	assert.Equal(t, []metav1.OwnerReference{{Kind: "ConfigMap", Name: "cm"}}, created.GetOwnerReferences())
+
+	created.OwnerReferences = append(created.OwnerReferences, metav1.OwnerReference{Kind: "flyteworkflow", Name: "wf"})
+	assert.NoError(t, c.Update(ctx, created))
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "pod"}, created))
+	assert.Equal(t, []metav1.OwnerReference{{Kind: "ConfigMap", Name: "cm"}}, created.GetOwnerReferences())
+}
@@ -4,15 +4,11 @@ package mocks
 
 import (
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	executors "github.com/flyteorg/flytepropeller/pkg/controller/executors"
-
 	mock "github.com/stretchr/testify/mock"
-
-	types "k8s.io/apimachinery/pkg/types"
-
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // ExecutionContext is an autogenerated mock type for the ExecutionContext type
@@ -540,6 +536,40 @@ func (_m *ExecutionContext) GetRawOutputDataConfig() v1alpha1.RawOutputDataConfi
 	return r0
 }
 
+type ExecutionContext_GetScheduledAt struct {
+	*mock.Call
+}
+
+func (_m ExecutionContext_GetScheduledAt) Return(_a0 *v1.Time) *ExecutionContext_GetScheduledAt {
+	return &ExecutionContext_GetScheduledAt{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ExecutionContext) OnGetScheduledAt() *ExecutionContext_GetScheduledAt {
+	c := _m.On("GetScheduledAt")
+	return &ExecutionContext_GetScheduledAt{Call: c}
+}
+
+func (_m *ExecutionContext) OnGetScheduledAtMatch(matchers ...interface{}) *ExecutionContext_GetScheduledAt {
+	c := _m.On("GetScheduledAt", matchers...)
+	return &ExecutionContext_GetScheduledAt{Call: c}
+}
+
+// GetScheduledAt provides a mock function with given fields:
+func (_m *ExecutionContext) GetScheduledAt() *v1.Time {
+	ret := _m.Called()
+
+	var r0 *v1.Time
+	if rf, ok := ret.Get(0).(func() *v1.Time); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Time)
+		}
+	}
+
+	return r0
+}
+
 type ExecutionContext_GetSecurityContext struct {
 	*mock.Call
 }
@@ -5,13 +5,10 @@ package mocks
 import (
 	core "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 
+	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	mock "github.com/stretchr/testify/mock"
-
-	types "k8s.io/apimachinery/pkg/types"
-
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	v1alpha1 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // ImmutableExecutionContext is an autogenerated mock type for the ImmutableExecutionContext type
@@ -439,6 +436,40 @@ func (_m *ImmutableExecutionContext) GetRawOutputDataConfig() v1alpha1.RawOutput
 	return r0
 }
 
+type ImmutableExecutionContext_GetScheduledAt struct {
+	*mock.Call
+}
+
+func (_m ImmutableExecutionContext_GetScheduledAt) Return(_a0 *v1.Time) *ImmutableExecutionContext_GetScheduledAt {
+	return &ImmutableExecutionContext_GetScheduledAt{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ImmutableExecutionContext) OnGetScheduledAt() *ImmutableExecutionContext_GetScheduledAt {
+	c := _m.On("GetScheduledAt")
+	return &ImmutableExecutionContext_GetScheduledAt{Call: c}
+}
+
+func (_m *ImmutableExecutionContext) OnGetScheduledAtMatch(matchers ...interface{}) *ImmutableExecutionContext_GetScheduledAt {
+	c := _m.On("GetScheduledAt", matchers...)
+	return &ImmutableExecutionContext_GetScheduledAt{Call: c}
+}
+
+// GetScheduledAt provides a mock function with given fields:
+func (_m *ImmutableExecutionContext) GetScheduledAt() *v1.Time {
+	ret := _m.Called()
+
+	var r0 *v1.Time
+	if rf, ok := ret.Get(0).(func() *v1.Time); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Time)
+		}
+	}
+
+	return r0
+}
+
 type ImmutableExecutionContext_GetSecurityContext struct {
 	*mock.Call
 }
@@ -9,20 +9,28 @@ import (
 
 	"strings"
 
+	flyteworkflow "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytestdlib/contextutils"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/clock"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 type gcMetrics struct {
-	gcRoundSuccess labeled.Counter
-	gcRoundFailure labeled.Counter
-	gcTime         labeled.StopWatch
+	gcRoundSuccess  labeled.Counter
+	gcRoundFailure  labeled.Counter
+	gcTime          labeled.StopWatch
+	archiveFailures labeled.Counter
+}
+
+// WorkflowArchiver stores a completed workflow before the garbage collector deletes it.
+type WorkflowArchiver interface {
+	Archive(ctx context.Context, wf *flyteworkflow.FlyteWorkflow) error
 }
 
 // Garbage collector is an active background cleanup service, that deletes all workflows that are completed and older
@@ -35,6 +43,8 @@ type GarbageCollector struct {
 	clk             clock.Clock
 	metrics         *gcMetrics
 	namespace       string
+	// Optional, when set workflows are only deleted once they have been archived.
+	archiver WorkflowArchiver
 }
 
 // Issues a background deletion command with label selector for all completed workflows outside of the retention period
@@ -76,6 +86,13 @@ func (g *GarbageCollector) deleteWorkflowsForNamespace(ctx context.Context, name
 	gracePeriodZero := int64(0)
 	propagation := v1.DeletePropagationBackground
 
+	if g.archiver != nil {
+		return g.archiveAndDeleteWorkflowsForNamespace(ctx, namespace, labelSelector, v1.DeleteOptions{
+			GracePeriodSeconds: &gracePeriodZero,
+			PropagationPolicy:  &propagation,
+		})
+	}
+
 	return g.wfClient.FlyteWorkflows(namespace).DeleteCollection(
 		ctx,
 		v1.DeleteOptions{
@@ -88,6 +105,35 @@ func (g *GarbageCollector) deleteWorkflowsForNamespace(ctx context.Context, name
 	)
 }
 
+// Archives every workflow that matches the label selector and deletes the ones that were archived. Workflows that fail
+// to be archived are kept, so that they are retried in the next round.
+func (g *GarbageCollector) archiveAndDeleteWorkflowsForNamespace(ctx context.Context, namespace string,
+	labelSelector *v1.LabelSelector, deleteOptions v1.DeleteOptions) error {
+
+	wfClient := g.wfClient.FlyteWorkflows(namespace)
+	workflows, err := wfClient.List(ctx, v1.ListOptions{
+		LabelSelector: v1.FormatLabelSelector(labelSelector),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range workflows.Items {
+		wf := &workflows.Items[i]
+		if err := g.archiver.Archive(ctx, wf); err != nil {
+			g.metrics.archiveFailures.Inc(ctx)
+			logger.Errorf(ctx, "Failed to archive workflow [%s/%s], skipping its deletion. Error: [%v]", namespace, wf.Name, err)
+			continue
+		}
+
+		if err := wfClient.Delete(ctx, wf.Name, deleteOptions); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // A periodic GC running
 func (g *GarbageCollector) runGC(ctx context.Context, ticker clock.Ticker) {
 	logger.Infof(ctx, "Background workflow garbage collection started, with duration [%s], TTL [%d] hours", g.interval.String(), g.ttlHours)
@@ -123,7 +169,8 @@ func (g *GarbageCollector) StartGC(ctx context.Context) error {
 	return nil
 }
 
-func NewGarbageCollector(cfg *config.Config, scope promutils.Scope, clk clock.Clock, namespaceClient corev1.NamespaceInterface, wfClient v1alpha1.FlyteworkflowV1alpha1Interface) (*GarbageCollector, error) {
+func NewGarbageCollector(cfg *config.Config, scope promutils.Scope, clk clock.Clock, namespaceClient corev1.NamespaceInterface,
+	wfClient v1alpha1.FlyteworkflowV1alpha1Interface, archiver WorkflowArchiver) (*GarbageCollector, error) {
 	ttl := 23
 	if cfg.MaxTTLInHours < 23 {
 		ttl = cfg.MaxTTLInHours
@@ -139,8 +186,11 @@ func NewGarbageCollector(cfg *config.Config, scope promutils.Scope, clk clock.Cl
 			gcTime:         labeled.NewStopWatch("gc_latency", "time taken to issue a delete for TTL'ed workflows", time.Millisecond, scope),
 			gcRoundSuccess: labeled.NewCounter("gc_success", "successful executions of delete request", scope),
 			gcRoundFailure: labeled.NewCounter("gc_failure", "failure to delete workflows", scope),
+			archiveFailures: labeled.NewCounter("gc_archive_failure", "failure to archive workflows before deleting them",
+				scope),
 		},
 		clk:       clk,
 		namespace: cfg.LimitNamespace,
+		archiver:  archiver,
 	}, nil
 }
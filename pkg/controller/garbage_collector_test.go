@@ -2,10 +2,12 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
 
+	flyteworkflow "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	config2 "github.com/flyteorg/flytepropeller/pkg/controller/config"
 
 	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
@@ -25,7 +27,7 @@ func TestNewGarbageCollector(t *testing.T) {
 			MaxTTLInHours:  2,
 			LimitNamespace: "flyte",
 		}
-		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), clock.NewFakeClock(time.Now()), nil, nil)
+		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), clock.NewFakeClock(time.Now()), nil, nil, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, 2, gc.ttlHours)
 	})
@@ -36,7 +38,7 @@ func TestNewGarbageCollector(t *testing.T) {
 			MaxTTLInHours:  24,
 			LimitNamespace: "flyte",
 		}
-		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), clock.NewFakeClock(time.Now()), nil, nil)
+		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), clock.NewFakeClock(time.Now()), nil, nil, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, 23, gc.ttlHours)
 	})
@@ -47,7 +49,7 @@ func TestNewGarbageCollector(t *testing.T) {
 			MaxTTLInHours:  0,
 			LimitNamespace: "flyte",
 		}
-		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), nil, nil, nil)
+		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), nil, nil, nil, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, 0, gc.ttlHours)
 		assert.NoError(t, gc.StartGC(context.TODO()))
@@ -60,7 +62,7 @@ func TestNewGarbageCollector(t *testing.T) {
 			MaxTTLInHours:  -1,
 			LimitNamespace: "flyte",
 		}
-		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), nil, nil, nil)
+		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), nil, nil, nil, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, -1, gc.ttlHours)
 		assert.NoError(t, gc.StartGC(context.TODO()))
@@ -70,6 +72,24 @@ func TestNewGarbageCollector(t *testing.T) {
 type mockWfClient struct {
 	v1alpha1.FlyteWorkflowInterface
 	DeleteCollectionCb func(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	ListCb             func(opts v1.ListOptions) (*flyteworkflow.FlyteWorkflowList, error)
+	DeleteCb           func(name string, options v1.DeleteOptions) error
+}
+
+func (m *mockWfClient) List(ctx context.Context, opts v1.ListOptions) (*flyteworkflow.FlyteWorkflowList, error) {
+	return m.ListCb(opts)
+}
+
+func (m *mockWfClient) Delete(ctx context.Context, name string, options v1.DeleteOptions) error {
+	return m.DeleteCb(name, options)
+}
+
+type mockArchiver struct {
+	ArchiveCb func(wf *flyteworkflow.FlyteWorkflow) error
+}
+
+func (m *mockArchiver) Archive(ctx context.Context, wf *flyteworkflow.FlyteWorkflow) error {
+	return m.ArchiveCb(wf)
 }
 
 func (m *mockWfClient) DeleteCollection(ctx context.Context, options v1.DeleteOptions, listOptions v1.ListOptions) error {
@@ -143,7 +163,7 @@ func TestGarbageCollector_StartGC(t *testing.T) {
 
 		fakeClock := clock.NewFakeClock(b)
 		mockNamespaceInvoked = false
-		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), fakeClock, mockNamespaceClient, mockClient)
+		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), fakeClock, mockNamespaceClient, mockClient, nil)
 		assert.NoError(t, err)
 		wg.Add(1)
 		ctx := context.TODO()
@@ -164,7 +184,7 @@ func TestGarbageCollector_StartGC(t *testing.T) {
 
 		fakeClock := clock.NewFakeClock(b)
 		mockNamespaceInvoked = false
-		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), fakeClock, mockNamespaceClient, mockClient)
+		gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), fakeClock, mockNamespaceClient, mockClient, nil)
 		assert.NoError(t, err)
 		wg.Add(2)
 		ctx := context.TODO()
@@ -176,3 +196,59 @@ func TestGarbageCollector_StartGC(t *testing.T) {
 		assert.True(t, mockNamespaceInvoked)
 	})
 }
+
+func TestGarbageCollector_Archival(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &config2.Config{
+		GCInterval:     config.Duration{Duration: time.Minute * 30},
+		MaxTTLInHours:  2,
+		LimitNamespace: "flyte",
+	}
+
+	var deleted []string
+	mockWfClient := &mockWfClient{
+		ListCb: func(opts v1.ListOptions) (*flyteworkflow.FlyteWorkflowList, error) {
+			assert.Equal(t, "termination-status=terminated", opts.LabelSelector)
+			return &flyteworkflow.FlyteWorkflowList{
+				Items: []flyteworkflow.FlyteWorkflow{
+					{ObjectMeta: v1.ObjectMeta{Name: "archived", Namespace: "flyte"}},
+					{ObjectMeta: v1.ObjectMeta{Name: "failed", Namespace: "flyte"}},
+				},
+			}, nil
+		},
+		DeleteCb: func(name string, options v1.DeleteOptions) error {
+			deleted = append(deleted, name)
+			return nil
+		},
+		DeleteCollectionCb: func(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+			assert.Fail(t, "workflows must be deleted individually once archived")
+			return nil
+		},
+	}
+
+	mockClient := &mockClient{
+		FlyteWorkflowsCb: func(namespace string) v1alpha1.FlyteWorkflowInterface {
+			return mockWfClient
+		},
+	}
+
+	var archived []string
+	archiver := &mockArchiver{
+		ArchiveCb: func(wf *flyteworkflow.FlyteWorkflow) error {
+			if wf.Name == "failed" {
+				return fmt.Errorf("failed to archive")
+			}
+
+			archived = append(archived, wf.Name)
+			return nil
+		},
+	}
+
+	gc, err := NewGarbageCollector(cfg, promutils.NewTestScope(), clock.NewFakeClock(time.Now()), nil, mockClient, archiver)
+	assert.NoError(t, err)
+
+	selector := &v1.LabelSelector{MatchLabels: map[string]string{"termination-status": "terminated"}}
+	assert.NoError(t, gc.deleteWorkflowsForNamespace(ctx, "flyte", selector))
+	assert.Equal(t, []string{"archived"}, archived)
+	assert.Equal(t, []string{"archived"}, deleted)
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/flyteorg/flytestdlib/storage"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	"github.com/flyteorg/flytepropeller/pkg/visualize"
+)
+
+const (
+	graphSnapshotJSONFile = "graph.json"
+	graphSnapshotDOTFile  = "graph.dot"
+)
+
+// GraphSnapshottingWorkflowExecutor writes a snapshot of the graph of each workflow it evaluates to the data dir of the
+// workflow whenever the phase of the workflow or of one of its nodes changes, and records where in the status of the
+// workflow.
+type GraphSnapshottingWorkflowExecutor struct {
+	executors.Workflow
+	store  *storage.DataStore
+	format config.GraphSnapshotFormat
+}
+
+func (e *GraphSnapshottingWorkflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+	err := e.Workflow.HandleFlyteWorkflow(ctx, w)
+	e.snapshot(ctx, w)
+	return err
+}
+
+func (e *GraphSnapshottingWorkflowExecutor) HandleAbortedWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow, maxRetries uint32) error {
+	err := e.Workflow.HandleAbortedWorkflow(ctx, w, maxRetries)
+	e.snapshot(ctx, w)
+	return err
+}
+
+func (e *GraphSnapshottingWorkflowExecutor) render(s *visualize.GraphSnapshot) (string, []byte, error) {
+	if e.format == config.GraphSnapshotFormatDOT {
+		return graphSnapshotDOTFile, []byte(s.ToGraphViz()), nil
+	}
+
+	raw, err := json.Marshal(s)
+	return graphSnapshotJSONFile, raw, err
+}
+
+// Writes the snapshot of the workflow unless the last one written describes the same phases. It is best effort, it does
+// not fail the round.
+func (e *GraphSnapshottingWorkflowExecutor) snapshot(ctx context.Context, w *v1alpha1.FlyteWorkflow) {
+	dataDir := w.GetExecutionStatus().GetDataDir()
+	if len(dataDir) == 0 {
+		return
+	}
+
+	s := visualize.NewGraphSnapshot(w)
+	hash := sha256.Sum256([]byte(s.Phases()))
+	encodedHash := hex.EncodeToString(hash[:])
+	if w.Status.GraphSnapshot != nil && w.Status.GraphSnapshot.Hash == encodedHash {
+		return
+	}
+
+	fileName, raw, err := e.render(s)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to render the graph snapshot of the workflow. Error: %v", err)
+		return
+	}
+
+	ref, err := e.store.ConstructReference(ctx, dataDir, fileName)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to construct the reference of the graph snapshot of the workflow. Error: %v", err)
+		return
+	}
+
+	if err := e.store.WriteRaw(ctx, ref, int64(len(raw)), storage.Options{}, bytes.NewReader(raw)); err != nil {
+		logger.Warnf(ctx, "Failed to write the graph snapshot of the workflow to [%v]. Error: %v", ref, err)
+		return
+	}
+
+	logger.Debugf(ctx, "Wrote the graph snapshot of the workflow to [%v]", ref)
+	w.Status.GraphSnapshot = &v1alpha1.GraphSnapshotReference{Location: ref, Hash: encodedHash}
+}
+
+// NewGraphSnapshottingWorkflowExecutor wraps the given executor to write graph snapshots of the workflows it evaluates.
+func NewGraphSnapshottingWorkflowExecutor(cfg config.GraphSnapshotConfig, executor executors.Workflow, store *storage.DataStore) (
+	*GraphSnapshottingWorkflowExecutor, error) {
+
+	if cfg.Format != config.GraphSnapshotFormatJSON && cfg.Format != config.GraphSnapshotFormatDOT {
+		return nil, fmt.Errorf("unsupported graph snapshot format [%v], expected one of [%v, %v]", cfg.Format,
+			config.GraphSnapshotFormatJSON, config.GraphSnapshotFormatDOT)
+	}
+
+	return &GraphSnapshottingWorkflowExecutor{
+		Workflow: executor,
+		store:    store,
+		format:   cfg.Format,
+	}, nil
+}
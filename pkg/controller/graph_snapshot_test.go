@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/visualize"
+)
+
+func TestGraphSnapshottingWorkflowExecutor(t *testing.T) {
+	ctx := context.TODO()
+	newWorkflow := func() *v1alpha1.FlyteWorkflow {
+		w := &v1alpha1.FlyteWorkflow{
+			ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "wf"},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{
+				ID: "wf",
+				Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+					v1alpha1.StartNodeID: {ID: v1alpha1.StartNodeID, Kind: v1alpha1.NodeKindStart},
+					"n1":                 {ID: "n1", Name: "task", Kind: v1alpha1.NodeKindTask},
+				},
+				Connections: v1alpha1.Connections{
+					Downstream: map[v1alpha1.NodeID][]v1alpha1.NodeID{v1alpha1.StartNodeID: {"n1"}},
+				},
+			},
+		}
+		w.Status.SetDataDir("s3://bucket/wf")
+		w.Status.UpdatePhase(v1alpha1.WorkflowPhaseRunning, "", nil)
+		return w
+	}
+
+	// Moves the node to the given phase.
+	handleTo := func(phase v1alpha1.NodePhase) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			w := args.Get(1).(*v1alpha1.FlyteWorkflow)
+			w.Status.NodeStatus = map[v1alpha1.NodeID]*v1alpha1.NodeStatus{"n1": {Phase: phase}}
+		}
+	}
+
+	read := func(t *testing.T, store *storage.DataStore, ref storage.DataReference) []byte {
+		reader, err := store.ReadRaw(ctx, ref)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+
+		raw, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		return raw
+	}
+
+	t.Run("json", func(t *testing.T) {
+		store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		wfExec := &mocks.Workflow{}
+		e, err := NewGraphSnapshottingWorkflowExecutor(config.GraphSnapshotConfig{Format: config.GraphSnapshotFormatJSON}, wfExec, store)
+		assert.NoError(t, err)
+		w := newWorkflow()
+
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.NodePhaseRunning)).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		if !assert.NotNil(t, w.Status.GraphSnapshot) {
+			return
+		}
+
+		assert.Equal(t, storage.DataReference("s3://bucket/wf/"+graphSnapshotJSONFile), w.Status.GraphSnapshot.Location)
+		s := &visualize.GraphSnapshot{}
+		assert.NoError(t, json.Unmarshal(read(t, store, w.Status.GraphSnapshot.Location), s))
+		assert.Equal(t, []visualize.GraphSnapshotNode{
+			{ID: "n1", Name: "task", Kind: string(v1alpha1.NodeKindTask), Phase: v1alpha1.NodePhaseRunning.String()},
+			{ID: v1alpha1.StartNodeID, Kind: string(v1alpha1.NodeKindStart), Phase: v1alpha1.NodePhaseNotYetStarted.String()},
+		}, s.Nodes)
+		assert.Equal(t, []visualize.GraphSnapshotEdge{{From: v1alpha1.StartNodeID, To: "n1"}}, s.Edges)
+
+		// The snapshot is not written again while the phases do not change.
+		hash := w.Status.GraphSnapshot.Hash
+		assert.NoError(t, store.WriteRaw(ctx, w.Status.GraphSnapshot.Location, 0, storage.Options{}, &emptyReader{}))
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.NodePhaseRunning)).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		assert.Empty(t, read(t, store, w.Status.GraphSnapshot.Location))
+
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.NodePhaseSucceeded)).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		assert.NotEqual(t, hash, w.Status.GraphSnapshot.Hash)
+		assert.Contains(t, string(read(t, store, w.Status.GraphSnapshot.Location)), v1alpha1.NodePhaseSucceeded.String())
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		wfExec := &mocks.Workflow{}
+		e, err := NewGraphSnapshottingWorkflowExecutor(config.GraphSnapshotConfig{Format: config.GraphSnapshotFormatDOT}, wfExec, store)
+		assert.NoError(t, err)
+		w := newWorkflow()
+
+		wfExec.OnHandleAbortedWorkflowMatch(mock.Anything, w, mock.Anything).Run(handleTo(v1alpha1.NodePhaseFailed)).Return(nil).Once()
+		assert.NoError(t, e.HandleAbortedWorkflow(ctx, w, 1))
+		if assert.NotNil(t, w.Status.GraphSnapshot) {
+			assert.Equal(t, storage.DataReference("s3://bucket/wf/"+graphSnapshotDOTFile), w.Status.GraphSnapshot.Location)
+			dot := string(read(t, store, w.Status.GraphSnapshot.Location))
+			assert.Contains(t, dot, "digraph G {")
+			assert.Contains(t, dot, "\"start-node\" -> \"n1\";")
+		}
+	})
+
+	t.Run("unsupported-format", func(t *testing.T) {
+		_, err := NewGraphSnapshottingWorkflowExecutor(config.GraphSnapshotConfig{Format: "svg"}, &mocks.Workflow{}, nil)
+		assert.Error(t, err)
+	})
+}
+
+type emptyReader struct{}
+
+func (emptyReader) Read(_ []byte) (int, error) {
+	return 0, io.EOF
+}
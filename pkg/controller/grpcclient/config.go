@@ -0,0 +1,88 @@
+package grpcclient
+
+import (
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "grpc-clients"
+
+const (
+	// AdminTarget is the name of the target used for the connection to FlyteAdmin.
+	AdminTarget = "admin"
+	// DataCatalogTarget is the name of the target used for the connection to DataCatalog.
+	DataCatalogTarget = "datacatalog"
+)
+
+var (
+	defaultConfig = &Config{
+		Default: TargetConfig{},
+		Targets: map[string]TargetConfig{},
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls how propeller dials the gRPC services it talks to. The default target config applies to all of
+// them, the fields set in the config of a specific target override it.
+type Config struct {
+	Default TargetConfig `json:"default" pflag:",Config applied to all gRPC clients."`
+	// Maps target names, e.g. admin or datacatalog, to the config that overrides the default config for them.
+	Targets map[string]TargetConfig `json:"targets" pflag:"-,Config overrides per target."`
+}
+
+// TargetConfig controls how a single gRPC service is dialed. Proxies are picked up from the HTTPS_PROXY and NO_PROXY
+// environment variables unless they are disabled.
+type TargetConfig struct {
+	Endpoint            string   `json:"endpoint" pflag:",Overrides the endpoint of the target, e.g. dns:///flyteadmin:81 to resolve all addresses of a headless service."`
+	Addresses           []string `json:"addresses" pflag:",Static list of host:port addresses to balance between instead of resolving the endpoint."`
+	Authority           string   `json:"authority" pflag:",Overrides the authority used in TLS verification and the :authority header."`
+	CABundle            string   `json:"ca-bundle" pflag:",Path to a PEM file of CA certificates trusted in addition to the system ones."`
+	LoadBalancingPolicy string   `json:"load-balancing-policy" pflag:",Load balancing policy, e.g. round_robin or pick_first."`
+	ServiceConfig       string   `json:"service-config" pflag:",Default gRPC service config in JSON, takes precedence over the load balancing policy."`
+	DisableProxy        bool     `json:"disable-proxy" pflag:",Ignores the proxy environment variables."`
+}
+
+// merge returns the config with all fields that are set in the override replaced.
+func (t TargetConfig) merge(override TargetConfig) TargetConfig {
+	if len(override.Endpoint) > 0 {
+		t.Endpoint = override.Endpoint
+	}
+
+	if len(override.Addresses) > 0 {
+		t.Addresses = override.Addresses
+	}
+
+	if len(override.Authority) > 0 {
+		t.Authority = override.Authority
+	}
+
+	if len(override.CABundle) > 0 {
+		t.CABundle = override.CABundle
+	}
+
+	if len(override.LoadBalancingPolicy) > 0 {
+		t.LoadBalancingPolicy = override.LoadBalancingPolicy
+	}
+
+	if len(override.ServiceConfig) > 0 {
+		t.ServiceConfig = override.ServiceConfig
+	}
+
+	t.DisableProxy = t.DisableProxy || override.DisableProxy
+	return t
+}
+
+// GetTargetConfig returns the effective config of the named target.
+func (cfg Config) GetTargetConfig(name string) TargetConfig {
+	return cfg.Default.merge(cfg.Targets[name])
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,127 @@
+package grpcclient
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Scheme of the resolver that serves the static addresses of a target.
+const staticResolverScheme = "static"
+
+// Factory dials gRPC services according to the config of their targets, so that proxies, custom CAs, resolution and
+// load balancing are handled the same way for every client.
+type Factory struct {
+	cfg Config
+}
+
+// Target returns the address to dial for the named target along with the options configured for it, except for the
+// transport security. The given endpoint is used unless the target config overrides it.
+func (f Factory) Target(name, endpoint string) (string, []grpc.DialOption, error) {
+	cfg := f.cfg.GetTargetConfig(name)
+	var opts []grpc.DialOption
+
+	if len(cfg.Endpoint) > 0 {
+		endpoint = cfg.Endpoint
+	}
+
+	if len(cfg.Addresses) > 0 {
+		addresses := make([]resolver.Address, 0, len(cfg.Addresses))
+		for _, address := range cfg.Addresses {
+			addresses = append(addresses, resolver.Address{Addr: address})
+		}
+
+		// The resolver is registered on the connection only, so the same scheme can be used for all targets.
+		r := manual.NewBuilderWithScheme(staticResolverScheme)
+		r.InitialState(resolver.State{Addresses: addresses})
+		opts = append(opts, grpc.WithResolvers(r))
+		endpoint = fmt.Sprintf("%v:///%v", staticResolverScheme, name)
+	}
+
+	if len(endpoint) == 0 {
+		return "", nil, fmt.Errorf("no endpoint configured for gRPC target [%v]", name)
+	}
+
+	if len(cfg.Authority) > 0 {
+		opts = append(opts, grpc.WithAuthority(cfg.Authority))
+	}
+
+	switch {
+	case len(cfg.ServiceConfig) > 0:
+		opts = append(opts, grpc.WithDefaultServiceConfig(cfg.ServiceConfig))
+	case len(cfg.LoadBalancingPolicy) > 0:
+		if balancer.Get(cfg.LoadBalancingPolicy) == nil {
+			return "", nil, fmt.Errorf("unknown load balancing policy [%v] for gRPC target [%v]", cfg.LoadBalancingPolicy, name)
+		}
+
+		opts = append(opts, grpc.WithDefaultServiceConfig(
+			fmt.Sprintf(`{"loadBalancingConfig": [{"%v": {}}]}`, cfg.LoadBalancingPolicy)))
+	}
+
+	if cfg.DisableProxy {
+		opts = append(opts, grpc.WithNoProxy())
+	}
+
+	return endpoint, opts, nil
+}
+
+// TransportCredentials returns TLS credentials for the named target that trust the system CAs as well as the CA bundle
+// configured for the target.
+func (f Factory) TransportCredentials(name string) (credentials.TransportCredentials, error) {
+	cfg := f.cfg.GetTargetConfig(name)
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.CABundle) > 0 {
+		raw, err := ioutil.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle of gRPC target [%v]: %w", name, err)
+		}
+
+		if !pool.AppendCertsFromPEM(raw) {
+			return nil, fmt.Errorf("no certificates found in CA bundle [%v] of gRPC target [%v]", cfg.CABundle, name)
+		}
+	}
+
+	return credentials.NewClientTLSFromCert(pool, ""), nil
+}
+
+// Dial connects to the named target. Additional options are applied after the ones of the target config.
+func (f Factory) Dial(ctx context.Context, name, endpoint string, insecure bool, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	target, targetOpts, err := f.Target(name, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if insecure {
+		logger.Debugf(ctx, "Establishing insecure connection to [%v] at [%v]", name, target)
+		targetOpts = append(targetOpts, grpc.WithInsecure())
+	} else {
+		logger.Debugf(ctx, "Establishing secure connection to [%v] at [%v]", name, target)
+		creds, err := f.TransportCredentials(name)
+		if err != nil {
+			return nil, err
+		}
+
+		targetOpts = append(targetOpts, grpc.WithTransportCredentials(creds))
+	}
+
+	return grpc.DialContext(ctx, target, append(targetOpts, opts...)...)
+}
+
+func NewFactory(cfg *Config) *Factory {
+	return &Factory{
+		cfg: *cfg,
+	}
+}
@@ -0,0 +1,112 @@
+package grpcclient
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestConfig_GetTargetConfig(t *testing.T) {
+	cfg := Config{
+		Default: TargetConfig{LoadBalancingPolicy: "round_robin", Authority: "flyte.example.com"},
+		Targets: map[string]TargetConfig{
+			AdminTarget: {Endpoint: "dns:///flyteadmin:81", Authority: "admin.example.com", DisableProxy: true},
+		},
+	}
+
+	assert.Equal(t, TargetConfig{
+		Endpoint:            "dns:///flyteadmin:81",
+		Authority:           "admin.example.com",
+		LoadBalancingPolicy: "round_robin",
+		DisableProxy:        true,
+	}, cfg.GetTargetConfig(AdminTarget))
+	assert.Equal(t, cfg.Default, cfg.GetTargetConfig(DataCatalogTarget))
+}
+
+func TestFactory_Target(t *testing.T) {
+	t.Run("default endpoint", func(t *testing.T) {
+		target, opts, err := NewFactory(&Config{}).Target(DataCatalogTarget, "datacatalog:89")
+		assert.NoError(t, err)
+		assert.Equal(t, "datacatalog:89", target)
+		assert.Empty(t, opts)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		f := NewFactory(&Config{Targets: map[string]TargetConfig{
+			DataCatalogTarget: {Endpoint: "dns:///datacatalog:89", LoadBalancingPolicy: "round_robin", DisableProxy: true},
+		}})
+		target, opts, err := f.Target(DataCatalogTarget, "datacatalog:89")
+		assert.NoError(t, err)
+		assert.Equal(t, "dns:///datacatalog:89", target)
+		assert.Len(t, opts, 2)
+	})
+
+	t.Run("static addresses", func(t *testing.T) {
+		f := NewFactory(&Config{Default: TargetConfig{Addresses: []string{"10.0.0.1:89", "10.0.0.2:89"}}})
+		target, _, err := f.Target(DataCatalogTarget, "datacatalog:89")
+		assert.NoError(t, err)
+		assert.Equal(t, "static:///datacatalog", target)
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, _, err := NewFactory(&Config{Default: TargetConfig{LoadBalancingPolicy: "random"}}).Target(AdminTarget, "admin:81")
+		assert.Error(t, err)
+	})
+
+	t.Run("no endpoint", func(t *testing.T) {
+		_, _, err := NewFactory(&Config{}).Target(AdminTarget, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestFactory_TransportCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grpcclient")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	bundle := filepath.Join(dir, "ca.pem")
+	assert.NoError(t, ioutil.WriteFile(bundle, []byte("not a certificate"), 0600))
+
+	_, err = NewFactory(&Config{}).TransportCredentials(AdminTarget)
+	assert.NoError(t, err)
+
+	_, err = NewFactory(&Config{Default: TargetConfig{CABundle: bundle}}).TransportCredentials(AdminTarget)
+	assert.Error(t, err)
+
+	_, err = NewFactory(&Config{Default: TargetConfig{CABundle: filepath.Join(dir, "missing.pem")}}).TransportCredentials(AdminTarget)
+	assert.Error(t, err)
+}
+
+func TestFactory_Dial(t *testing.T) {
+	ctx := context.TODO()
+	var addresses []string
+	for i := 0; i < 2; i++ {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		server := grpc.NewServer()
+		healthpb.RegisterHealthServer(server, health.NewServer())
+		go func() { _ = server.Serve(lis) }()
+		defer server.Stop()
+		addresses = append(addresses, lis.Addr().String())
+	}
+
+	f := NewFactory(&Config{Targets: map[string]TargetConfig{
+		DataCatalogTarget: {Addresses: addresses, LoadBalancingPolicy: "round_robin", DisableProxy: true},
+	}})
+	conn, err := f.Dial(ctx, DataCatalogTarget, "unresolvable:89", true)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	for i := 0; i < 4; i++ {
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		assert.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+}
@@ -14,13 +14,15 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	controllerErrors "github.com/flyteorg/flytepropeller/pkg/controller/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/tracing"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 // TODO Lets move everything to use controller runtime
@@ -36,6 +38,7 @@ type propellerMetrics struct {
 	WorkflowNotFound         prometheus.Counter
 	StreakLength             labeled.Counter
 	RoundTime                labeled.StopWatch
+	RoundLatency             prometheus.Histogram
 }
 
 func newPropellerMetrics(scope promutils.Scope) *propellerMetrics {
@@ -51,6 +54,7 @@ func newPropellerMetrics(scope promutils.Scope) *propellerMetrics {
 		WorkflowNotFound:         roundScope.MustNewCounter("not_found", "workflow not found in the cache"),
 		StreakLength:             labeled.NewCounter("streak_length", "Number of consecutive rounds used in fast follow mode", roundScope, labeled.EmitUnlabeledMetric),
 		RoundTime:                labeled.NewStopWatch("round_time", "Total time taken by one round traversing, copying and storing a workflow", time.Millisecond, roundScope, labeled.EmitUnlabeledMetric),
+		RoundLatency:             roundScope.MustNewHistogram("latency_seconds", "Histogram of the time taken by one round, with the trace of the workflow as exemplar if tracing is enabled"),
 	}
 }
 
@@ -64,12 +68,40 @@ func RecordSystemError(w *v1alpha1.FlyteWorkflow, err error) *v1alpha1.FlyteWork
 	return wfDeepCopy
 }
 
+// Helper method to fail the workflow on a system error that was marked as not retryable, instead of retrying it until the
+// failed attempts are exhausted.
+func FailOnSystemError(w *v1alpha1.FlyteWorkflow, err *controllerErrors.Error) *v1alpha1.FlyteWorkflow {
+	wfDeepCopy := w.DeepCopy()
+	wfDeepCopy.GetExecutionStatus().UpdatePhase(v1alpha1.WorkflowPhaseFailing, "Workflow failed on a system error that is not retryable", &core.ExecutionError{
+		Kind:    core.ExecutionError_SYSTEM,
+		Code:    err.Code(),
+		Message: err.Error(),
+	})
+	return wfDeepCopy
+}
+
 // Core Propeller structure that houses the Reconciliation loop for Flytepropeller
+// RestartInjector decides whether the result of an evaluation round is discarded, as if propeller restarted before
+// persisting it. It is only used for resilience testing.
+type RestartInjector interface {
+	ShouldRestart(ctx context.Context) bool
+}
+
+// DataKeyProvider adds the data key that encrypts the futures of a workflow to the context of its rounds. It may update
+// the data key reference in the status of the workflow.
+type DataKeyProvider interface {
+	WithWorkflowDataKey(ctx context.Context, w *v1alpha1.FlyteWorkflow) (context.Context, error)
+}
+
 type Propeller struct {
 	wfStore          workflowstore.FlyteWorkflow
 	workflowExecutor executors.Workflow
 	metrics          *propellerMetrics
 	cfg              *config.Config
+	restarts         RestartInjector
+	dataKeys         DataKeyProvider
+	canary           *CanaryRouter
+	admission        *AdmissionController
 }
 
 // Initializes all downstream executors
@@ -90,6 +122,33 @@ func (p *Propeller) TryMutateWorkflow(ctx context.Context, originalW *v1alpha1.F
 	}
 	ctx = contextutils.WithResourceVersion(ctx, mutableW.GetResourceVersion())
 
+	if p.canary == nil {
+		return p.tryMutateWorkflow(ctx, mutableW)
+	}
+
+	c := p.canary.Route(ctx, mutableW)
+	start := time.Now()
+	w, err := p.tryMutateWorkflow(ctx, mutableW)
+	p.canary.Observe(c, start, w, err)
+	return w, err
+}
+
+func (p *Propeller) tryMutateWorkflow(ctx context.Context, mutableW *v1alpha1.FlyteWorkflow) (*v1alpha1.FlyteWorkflow, error) {
+	if p.dataKeys != nil {
+		generated := mutableW.Status.DataKey == nil
+		var err error
+		if ctx, err = p.dataKeys.WithWorkflowDataKey(ctx, mutableW); err != nil {
+			return nil, err
+		}
+
+		// A new data key is persisted before anything is encrypted with it, the status of a round that fails is
+		// discarded and the documents written during it could not be decrypted anymore.
+		if generated && mutableW.Status.DataKey != nil {
+			logger.Info(ctx, "Generated the data key of the workflow, persisting it before evaluating the workflow.")
+			return mutableW, nil
+		}
+	}
+
 	maxRetries := uint32(p.cfg.MaxWorkflowRetries)
 	if IsDeleted(mutableW) || (mutableW.Status.FailedAttempts > maxRetries) {
 		var err error
@@ -115,6 +174,14 @@ func (p *Propeller) TryMutateWorkflow(ctx context.Context, originalW *v1alpha1.F
 		var err error
 		SetFinalizerIfEmpty(mutableW, FinalizerKey)
 
+		// Workflows that would grow too large are failed before they start, the next round records the failure.
+		if p.admission != nil && mutableW.GetExecutionStatus().GetPhase() == v1alpha1.WorkflowPhaseReady {
+			if execErr := p.admission.Admit(ctx, mutableW); execErr != nil {
+				mutableW.Status.UpdatePhase(v1alpha1.WorkflowPhaseFailing, execErr.GetMessage(), execErr)
+				return mutableW, nil
+			}
+		}
+
 		func() {
 			t := p.metrics.RawWorkflowTraversalTime.Start(ctx)
 			defer func() {
@@ -130,7 +197,7 @@ func (p *Propeller) TryMutateWorkflow(ctx context.Context, originalW *v1alpha1.F
 		}()
 
 		if err != nil {
-			logger.Errorf(ctx, "Error when trying to reconcile workflow. Error [%v]. Error Type[%v]. Is nill [%v]",
+			logger.Errorf(ctx, "Error when trying to reconcile workflow. Error [%v]. Error Type[%v].",
 				err, reflect.TypeOf(err))
 			p.metrics.SystemError.Inc(ctx)
 			return nil, err
@@ -181,7 +248,7 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 			logger.Warningf(ctx, "Workflow namespace[%v]/name[%v] Stale.", namespace, name)
 			return nil
 		}
-		logger.Warningf(ctx, "Failed to GetWorkflow, retrying with back-off", fetchErr)
+		logger.Warningf(ctx, "Failed to GetWorkflow, retrying with back-off. Error: %v", fetchErr)
 		return fetchErr
 	}
 
@@ -192,6 +259,7 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 			return nil
 		}
 	}
+	ctx = tracing.WithWorkflowTrace(ctx, w)
 	streak := 0
 	defer p.metrics.StreakLength.Add(ctx, float64(streak))
 
@@ -201,12 +269,22 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 	}
 
 	for streak = 0; streak < maxLength; streak++ {
-		t := p.metrics.RoundTime.Start(ctx)
+		t := p.startRoundTimer(ctx)
 		mutatedWf, err := p.TryMutateWorkflow(ctx, w)
 		if err != nil {
 			// NOTE We are overriding the deepcopy here, as we are essentially ingnoring all mutations
 			// We only want to increase failed attempts and discard any other partial changes to the CRD.
-			mutatedWf = RecordSystemError(w, err)
+			// Only errors marked as not retryable fail the workflow right away, the codes classified automatically may
+			// be transient, e.g. a blob that is not visible yet, and are retried until the failed attempts run out.
+			controllerErr, nonRetryable := controllerErrors.MarkedNonRetryable(err)
+			phase := w.GetExecutionStatus().GetPhase()
+			if nonRetryable && !w.GetExecutionStatus().IsTerminated() && phase != v1alpha1.WorkflowPhaseFailing {
+				logger.Errorf(ctx, "Failing the workflow on a system error that is not retryable [%v]. Error: %v",
+					controllerErr.Code(), err)
+				mutatedWf = FailOnSystemError(w, controllerErr)
+			} else {
+				mutatedWf = RecordSystemError(w, err)
+			}
 			p.metrics.SystemError.Inc(ctx)
 		} else if mutatedWf == nil {
 			logger.Errorf(ctx, "Should not happen! Mutation resulted in a nil workflow!")
@@ -227,11 +305,17 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 				ResetFinalizers(mutatedWf)
 			}
 		}
+		if p.restarts != nil && p.restarts.ShouldRestart(ctx) {
+			t.Stop()
+			return fmt.Errorf("simulated a restart, discarded the result of the evaluation round")
+		}
+
 		// TODO we will need to call updatestatus when it is supported. But to preserve metadata like (label/finalizer) we will need to use update
 
 		// update the GetExecutionStatus block of the FlyteWorkflow resource. UpdateStatus will not
 		// allow changes to the Spec of the resource, which is ideal for ensuring
 		// nothing other than resource status has been updated.
+		mutatedWf.Status.IncRound()
 		newWf, updateErr := p.wfStore.Update(ctx, mutatedWf, workflowstore.PriorityClassCritical)
 		if updateErr != nil {
 			t.Stop()
@@ -247,6 +331,7 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 					Code:    "WorkflowTooLarge",
 					Message: "Workflow execution state is too large for Flyte to handle.",
 				})
+				mutableW.Status.IncRound()
 				if _, e := p.wfStore.Update(ctx, mutableW, workflowstore.PriorityClassCritical); e != nil {
 					logger.Errorf(ctx, "Failed recording a large workflow as failed, reason: %s. Retrying...", e)
 					return e
@@ -275,14 +360,51 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 	return nil
 }
 
+// roundTimer times a round in both the round time summary and the round latency histogram.
+type roundTimer struct {
+	ctx     context.Context
+	start   time.Time
+	timer   labeled.Timer
+	latency prometheus.Histogram
+}
+
+func (r roundTimer) Stop() {
+	r.timer.Stop()
+	tracing.Observe(r.ctx, r.latency, time.Since(r.start).Seconds())
+}
+
+func (p *Propeller) startRoundTimer(ctx context.Context) roundTimer {
+	return roundTimer{
+		ctx:     ctx,
+		start:   time.Now(),
+		timer:   p.metrics.RoundTime.Start(ctx),
+		latency: p.metrics.RoundLatency,
+	}
+}
+
 // NewPropellerHandler creates a new Propeller and initializes metrics
-func NewPropellerHandler(_ context.Context, cfg *config.Config, wfStore workflowstore.FlyteWorkflow, executor executors.Workflow, scope promutils.Scope) *Propeller {
+func NewPropellerHandler(_ context.Context, cfg *config.Config, wfStore workflowstore.FlyteWorkflow, executor executors.Workflow,
+	restarts RestartInjector, dataKeys DataKeyProvider, scope promutils.Scope) *Propeller {
 
 	metrics := newPropellerMetrics(scope)
+	var canary *CanaryRouter
+	if cfg.Canary.Enabled {
+		canary = NewCanaryRouter(cfg.Canary, scope.NewSubScope("canary"))
+	}
+
+	var admission *AdmissionController
+	if cfg.Admission.Enabled {
+		admission = NewAdmissionController(cfg.Admission, scope.NewSubScope("admission"))
+	}
+
 	return &Propeller{
 		metrics:          metrics,
 		wfStore:          wfStore,
 		workflowExecutor: executor,
 		cfg:              cfg,
+		restarts:         restarts,
+		dataKeys:         dataKeys,
+		canary:           canary,
+		admission:        admission,
 	}
 }
@@ -11,9 +11,13 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	controllerErrors "github.com/flyteorg/flytepropeller/pkg/controller/errors"
+	nodeErrors "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
 
 	"github.com/flyteorg/flytestdlib/promutils"
@@ -48,7 +52,7 @@ func TestPropeller_Handle(t *testing.T) {
 		MaxWorkflowRetries: 0,
 	}
 
-	p := NewPropellerHandler(ctx, cfg, s, exec, scope)
+	p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, scope)
 
 	const namespace = "test"
 	const name = "123"
@@ -60,7 +64,7 @@ func TestPropeller_Handle(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, scope)
 		s.OnGetMatch(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.Wrap(workflowstore.ErrStaleWorkflowError, "stale")).Once()
 		assert.NoError(t, p.Handle(ctx, namespace, name))
 	})
@@ -157,6 +161,7 @@ func TestPropeller_Handle(t *testing.T) {
 		assert.Equal(t, v1alpha1.WorkflowPhaseReady, r.GetExecutionStatus().GetPhase())
 		assert.Equal(t, 0, len(r.Finalizers))
 		assert.Equal(t, uint32(1), r.Status.FailedAttempts)
+		assert.Equal(t, uint64(1), r.Status.Round)
 		assert.False(t, HasCompletedLabel(r))
 	})
 
@@ -462,6 +467,84 @@ func TestPropeller_Handle(t *testing.T) {
 	})
 }
 
+func TestPropeller_Handle_NonRetryableError(t *testing.T) {
+	ctx := context.TODO()
+	scope := promutils.NewTestScope()
+	s := workflowstore.NewInMemoryWorkflowStore()
+	exec := &mockExecutor{}
+	p := NewPropellerHandler(ctx, &config.Config{MaxWorkflowRetries: 5}, s, exec, nil, nil, scope)
+
+	const namespace = "test"
+	t.Run("not-retryable", func(t *testing.T) {
+		assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
+			ObjectMeta:   v1.ObjectMeta{Name: "w1", Namespace: namespace},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{ID: "w1"},
+		}))
+		exec.HandleCb = func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+			return errors.Wrapf(controllerErrors.Wrapf(controllerErrors.InvalidArgument,
+				k8serrors.NewBadRequest("invalid pod spec"), "").NonRetryable(), "failed to create pod")
+		}
+		assert.Error(t, p.Handle(ctx, namespace, "w1"))
+
+		r, err := s.Get(ctx, namespace, "w1")
+		assert.NoError(t, err)
+		assert.Equal(t, v1alpha1.WorkflowPhaseFailing, r.GetExecutionStatus().GetPhase())
+		assert.Equal(t, uint32(0), r.Status.FailedAttempts)
+		assert.Equal(t, core.ExecutionError_SYSTEM, r.GetExecutionStatus().GetExecutionError().GetKind())
+		assert.Equal(t, controllerErrors.InvalidArgument, r.GetExecutionStatus().GetExecutionError().GetCode())
+	})
+
+	t.Run("bad-specification", func(t *testing.T) {
+		assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
+			ObjectMeta:   v1.ObjectMeta{Name: "w4", Namespace: namespace},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{ID: "w4"},
+		}))
+		exec.HandleCb = func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+			return errors.Wrapf(nodeErrors.Errorf(nodeErrors.BadSpecificationError, "n1",
+				"Upstream node [n0] of node [n1] not defined"), "failed to handle the workflow")
+		}
+		assert.Error(t, p.Handle(ctx, namespace, "w4"))
+
+		r, err := s.Get(ctx, namespace, "w4")
+		assert.NoError(t, err)
+		assert.Equal(t, v1alpha1.WorkflowPhaseFailing, r.GetExecutionStatus().GetPhase())
+		assert.Equal(t, controllerErrors.InvalidArgument, r.GetExecutionStatus().GetExecutionError().GetCode())
+	})
+
+	t.Run("classified", func(t *testing.T) {
+		assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
+			ObjectMeta:   v1.ObjectMeta{Name: "w3", Namespace: namespace},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{ID: "w3"},
+		}))
+		// Errors that are not retryable by their code alone may be transient, e.g. a pod deleted between reads.
+		exec.HandleCb = func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+			return errors.Wrapf(k8serrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "p"), "failed to get pod")
+		}
+		assert.Error(t, p.Handle(ctx, namespace, "w3"))
+
+		r, err := s.Get(ctx, namespace, "w3")
+		assert.NoError(t, err)
+		assert.Equal(t, v1alpha1.WorkflowPhaseReady, r.GetExecutionStatus().GetPhase())
+		assert.Equal(t, uint32(1), r.Status.FailedAttempts)
+	})
+
+	t.Run("retryable", func(t *testing.T) {
+		assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
+			ObjectMeta:   v1.ObjectMeta{Name: "w2", Namespace: namespace},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{ID: "w2"},
+		}))
+		exec.HandleCb = func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+			return errors.Wrapf(k8serrors.NewServiceUnavailable("down"), "failed to create pod")
+		}
+		assert.Error(t, p.Handle(ctx, namespace, "w2"))
+
+		r, err := s.Get(ctx, namespace, "w2")
+		assert.NoError(t, err)
+		assert.Equal(t, v1alpha1.WorkflowPhaseReady, r.GetExecutionStatus().GetPhase())
+		assert.Equal(t, uint32(1), r.Status.FailedAttempts)
+	})
+}
+
 func TestPropeller_Handle_TurboMode(t *testing.T) {
 	scope := promutils.NewTestScope()
 	ctx := context.TODO()
@@ -475,7 +558,7 @@ func TestPropeller_Handle_TurboMode(t *testing.T) {
 	const namespace = "test"
 	const name = "123"
 
-	p := NewPropellerHandler(ctx, cfg, s, exec, scope)
+	p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, scope)
 
 	t.Run("error", func(t *testing.T) {
 		assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
@@ -677,7 +760,7 @@ func TestPropellerHandler_Initialize(t *testing.T) {
 		MaxWorkflowRetries: 0,
 	}
 
-	p := NewPropellerHandler(ctx, cfg, s, exec, scope)
+	p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, scope)
 
 	assert.NoError(t, p.Initialize(ctx))
 }
@@ -695,7 +778,7 @@ func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, scope)
 		wf := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      name,
@@ -716,7 +799,7 @@ func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, scope)
 		wf := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      name,
@@ -737,7 +820,7 @@ func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, s, exec, nil, nil, scope)
 		wf := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      name,
@@ -759,3 +842,87 @@ func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+type alwaysRestart struct{}
+
+func (alwaysRestart) ShouldRestart(_ context.Context) bool {
+	return true
+}
+
+func TestPropeller_Handle_InjectedRestart(t *testing.T) {
+	ctx := context.TODO()
+	s := workflowstore.NewInMemoryWorkflowStore()
+	exec := &mockExecutor{}
+	p := NewPropellerHandler(ctx, &config.Config{}, s, exec, alwaysRestart{}, nil, promutils.NewTestScope())
+
+	const namespace = "test"
+	const name = "123"
+	assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{
+			ID: "w1",
+		},
+	}))
+	exec.HandleCb = func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+		w.GetExecutionStatus().UpdatePhase(v1alpha1.WorkflowPhaseSucceeding, "done", nil)
+		return nil
+	}
+	assert.Error(t, p.Handle(ctx, namespace, name))
+
+	// The result of the round is discarded, as if propeller restarted before persisting it.
+	r, err := s.Get(ctx, namespace, name)
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.WorkflowPhaseReady, r.GetExecutionStatus().GetPhase())
+	assert.Equal(t, uint32(0), r.Status.FailedAttempts)
+}
+
+type fakeDataKeys struct{}
+
+func (fakeDataKeys) WithWorkflowDataKey(ctx context.Context, w *v1alpha1.FlyteWorkflow) (context.Context, error) {
+	if w.Status.DataKey == nil {
+		w.Status.DataKey = &v1alpha1.DataKeyReference{MasterKeyID: "key-1", Ciphertext: []byte("data-key")}
+	}
+
+	return ctx, nil
+}
+
+func TestPropeller_Handle_DataKey(t *testing.T) {
+	ctx := context.TODO()
+	s := workflowstore.NewInMemoryWorkflowStore()
+	exec := &mockExecutor{}
+	p := NewPropellerHandler(ctx, &config.Config{MaxWorkflowRetries: 5}, s, exec, nil, fakeDataKeys{}, promutils.NewTestScope())
+
+	const namespace = "test"
+	const name = "123"
+	assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{
+			ID: "w1",
+		},
+	}))
+	rounds := 0
+	exec.HandleCb = func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+		rounds++
+		return fmt.Errorf("failed")
+	}
+
+	// The data key is persisted before the workflow is evaluated, so that a failing round doesn't lose it.
+	assert.NoError(t, p.Handle(ctx, namespace, name))
+	assert.Equal(t, 0, rounds)
+	r, err := s.Get(ctx, namespace, name)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", r.Status.DataKey.MasterKeyID)
+
+	assert.Error(t, p.Handle(ctx, namespace, name))
+	assert.Equal(t, 1, rounds)
+	r, err = s.Get(ctx, namespace, name)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), r.Status.FailedAttempts)
+	assert.Equal(t, "key-1", r.Status.DataKey.MasterKeyID)
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// Code label of requests that got no response, e.g. because the connection failed or the request timed out.
+	kubeAPIErrorCode = "error"
+	// Resource label of requests to paths that are not resources, e.g. discovery or health checks.
+	kubeAPINonResource = "non_resource"
+)
+
+// KubeAPIMetrics counts and times the requests propeller sends to the API server, per verb and resource, so operators
+// can tell which interactions consume the client QPS budget and when the API server throttles or rejects them.
+type KubeAPIMetrics struct {
+	Requests  *prometheus.CounterVec
+	Latency   *prometheus.HistogramVec
+	Conflicts *prometheus.CounterVec
+	Throttled *prometheus.CounterVec
+}
+
+// InstrumentConfig records the requests of all clients built from the given config.
+func (m *KubeAPIMetrics) InstrumentConfig(cfg *rest.Config) {
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &kubeAPIRoundTripper{
+			delegate: rt,
+			metrics:  m,
+		}
+	})
+}
+
+type kubeAPIRoundTripper struct {
+	delegate http.RoundTripper
+	metrics  *KubeAPIMetrics
+}
+
+func (k *kubeAPIRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	verb, resource := parseKubeAPIRequest(req)
+	start := time.Now()
+	resp, err := k.delegate.RoundTrip(req)
+	k.metrics.Latency.WithLabelValues(verb, resource).Observe(time.Since(start).Seconds())
+
+	code := kubeAPIErrorCode
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+		switch resp.StatusCode {
+		case http.StatusConflict:
+			k.metrics.Conflicts.WithLabelValues(verb, resource).Inc()
+		case http.StatusTooManyRequests:
+			k.metrics.Throttled.WithLabelValues(verb, resource).Inc()
+		}
+	}
+
+	k.metrics.Requests.WithLabelValues(verb, resource, code).Inc()
+	return resp, err
+}
+
+// parseKubeAPIRequest returns the verb and resource of a request to the API server, following the paths of the
+// kubernetes API, i.e. /api/{version}/... for the core group and /apis/{group}/{version}/... for the others. The
+// resource of requests to subresources is reported as {resource}/{subresource}, e.g. pods/status.
+func parseKubeAPIRequest(req *http.Request) (verb, resource string) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var rest []string
+	switch {
+	case len(parts) > 2 && parts[0] == "api":
+		rest = parts[2:]
+	case len(parts) > 3 && parts[0] == "apis":
+		rest = parts[3:]
+	default:
+		return strings.ToLower(req.Method), kubeAPINonResource
+	}
+
+	watch := req.URL.Query().Get("watch") == "true" || req.URL.Query().Get("watch") == "1"
+	if rest[0] == "watch" {
+		watch = true
+		rest = rest[1:]
+	}
+
+	// Resources in a namespace, other than the namespace itself and its subresources, e.g. namespaces/{name}/status.
+	if rest[0] == "namespaces" && (len(rest) > 3 || len(rest) == 3 && rest[2] != "status" && rest[2] != "finalize") {
+		rest = rest[2:]
+	}
+
+	if len(rest) == 0 {
+		return strings.ToLower(req.Method), kubeAPINonResource
+	}
+
+	resource = rest[0]
+	hasName := len(rest) > 1
+	if len(rest) > 2 {
+		resource = rest[0] + "/" + rest[2]
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		if watch {
+			verb = "watch"
+		} else if hasName {
+			verb = "get"
+		} else {
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodPatch:
+		verb = "patch"
+	case http.MethodDelete:
+		if hasName {
+			verb = "delete"
+		} else {
+			verb = "deletecollection"
+		}
+	default:
+		verb = strings.ToLower(req.Method)
+	}
+
+	return verb, resource
+}
+
+func NewKubeAPIMetrics(scope promutils.Scope) *KubeAPIMetrics {
+	return &KubeAPIMetrics{
+		Requests: scope.MustNewCounterVec("requests", "Number of requests sent to the API server, per verb, resource and response code",
+			"verb", "resource", "code"),
+		Latency: scope.MustNewHistogramVec("request_latency_seconds", "Latency of requests sent to the API server, per verb and resource",
+			"verb", "resource"),
+		Conflicts: scope.MustNewCounterVec("conflicts", "Number of requests the API server rejected with a conflict (409), per verb and resource",
+			"verb", "resource"),
+		Throttled: scope.MustNewCounterVec("throttled", "Number of requests the API server throttled (429), per verb and resource",
+			"verb", "resource"),
+	}
+}
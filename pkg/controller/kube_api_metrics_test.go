@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseKubeAPIRequest(t *testing.T) {
+	tests := []struct {
+		method   string
+		url      string
+		verb     string
+		resource string
+	}{
+		{http.MethodGet, "/api/v1/namespaces/ns/pods/p", "get", "pods"},
+		{http.MethodGet, "/api/v1/namespaces/ns/pods", "list", "pods"},
+		{http.MethodGet, "/api/v1/pods?watch=true", "watch", "pods"},
+		{http.MethodGet, "/api/v1/watch/namespaces/ns/pods", "watch", "pods"},
+		{http.MethodGet, "/api/v1/namespaces/ns", "get", "namespaces"},
+		{http.MethodPut, "/api/v1/namespaces/ns/status", "update", "namespaces/status"},
+		{http.MethodPost, "/apis/flyte.lyft.com/v1alpha1/namespaces/ns/flyteworkflows", "create", "flyteworkflows"},
+		{http.MethodPut, "/apis/flyte.lyft.com/v1alpha1/namespaces/ns/flyteworkflows/wf", "update", "flyteworkflows"},
+		{http.MethodPatch, "/api/v1/namespaces/ns/pods/p/status", "patch", "pods/status"},
+		{http.MethodDelete, "/api/v1/namespaces/ns/pods/p", "delete", "pods"},
+		{http.MethodDelete, "/api/v1/namespaces/ns/pods", "deletecollection", "pods"},
+		{http.MethodGet, "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/crd", "get", "customresourcedefinitions"},
+		{http.MethodGet, "/healthz", "get", kubeAPINonResource},
+		{http.MethodGet, "/apis/flyte.lyft.com/v1alpha1", "get", kubeAPINonResource},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v %v", tt.method, tt.url), func(t *testing.T) {
+			verb, resource := parseKubeAPIRequest(httptest.NewRequest(tt.method, tt.url, nil))
+			assert.Equal(t, tt.verb, verb)
+			assert.Equal(t, tt.resource, resource)
+		})
+	}
+}
+
+func TestKubeAPIMetrics_InstrumentConfig(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	metrics := NewKubeAPIMetrics(promutils.NewTestScope())
+	cfg := &rest.Config{Host: server.URL}
+	metrics.InstrumentConfig(cfg)
+	rt, err := rest.TransportFor(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	client := &http.Client{Transport: rt}
+
+	update := func() {
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/api/v1/namespaces/ns/pods/p", nil)
+		assert.NoError(t, err)
+		resp, err := client.Do(req)
+		if assert.NoError(t, err) {
+			assert.NoError(t, resp.Body.Close())
+		}
+	}
+
+	update()
+	status = http.StatusConflict
+	update()
+	status = http.StatusTooManyRequests
+	update()
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Requests.WithLabelValues("update", "pods", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Requests.WithLabelValues("update", "pods", "409")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Conflicts.WithLabelValues("update", "pods")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Throttled.WithLabelValues("update", "pods")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.Latency))
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/profutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// AutoscalingPath is the path of the profiler port the load of propeller is served on.
+const AutoscalingPath = "/autoscaling"
+
+type loadMetrics struct {
+	QueueDepth    prometheus.Gauge
+	SubQueueDepth prometheus.Gauge
+	RoundLatency  prometheus.Gauge
+	FreeWorkers   prometheus.Gauge
+	Utilization   prometheus.Gauge
+}
+
+// Load is the load of a propeller replica, as last collected by the LoadReporter.
+type Load struct {
+	// Number of workflows waiting for a worker.
+	QueueDepth int `json:"queueDepth"`
+	// Number of workflows waiting to be moved to the queue, after their nodes were updated.
+	SubQueueDepth int `json:"subQueueDepth"`
+	// Average latency of the rounds since the previous collection, in milliseconds.
+	RoundLatencyMillis int64 `json:"roundLatencyMs"`
+	Workers            int   `json:"workers"`
+	FreeWorkers        int   `json:"freeWorkers"`
+	// Percentage of the workers that are busy.
+	UtilizationPercent int       `json:"utilizationPercent"`
+	CollectedAt        time.Time `json:"collectedAt"`
+}
+
+type subQueue interface {
+	SubQueueLen() int
+}
+
+// LoadReporter periodically collects the load of propeller, reports it in metrics and serves it over http, so that
+// propeller deployments can be autoscaled on it, e.g. with the KEDA metrics-api scaler using queueDepth as the value.
+type LoadReporter struct {
+	workQueue  CompositeWorkQueue
+	workerPool *WorkerPool
+	interval   time.Duration
+	metrics    loadMetrics
+	lock       sync.RWMutex
+	load       Load
+}
+
+// Collect collects the load of propeller once.
+func (r *LoadReporter) Collect() Load {
+	load := Load{
+		QueueDepth:  r.workQueue.Len(),
+		CollectedAt: time.Now(),
+	}
+
+	if q, ok := r.workQueue.(subQueue); ok {
+		load.SubQueueDepth = q.SubQueueLen()
+	}
+
+	_, latency := r.workerPool.collectRounds()
+	load.RoundLatencyMillis = latency.Milliseconds()
+	load.Workers, load.FreeWorkers = r.workerPool.workerCounts()
+	if load.Workers > 0 {
+		load.UtilizationPercent = 100 * (load.Workers - load.FreeWorkers) / load.Workers
+	}
+
+	r.metrics.QueueDepth.Set(float64(load.QueueDepth))
+	r.metrics.SubQueueDepth.Set(float64(load.SubQueueDepth))
+	r.metrics.RoundLatency.Set(float64(load.RoundLatencyMillis))
+	r.metrics.FreeWorkers.Set(float64(load.FreeWorkers))
+	r.metrics.Utilization.Set(float64(load.UtilizationPercent))
+
+	r.lock.Lock()
+	r.load = load
+	r.lock.Unlock()
+	return load
+}
+
+// ServeHTTP responds with the last collected load.
+func (r *LoadReporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.lock.RLock()
+	load := r.load
+	r.lock.RUnlock()
+
+	if err := profutils.WriteJSONResponse(w, http.StatusOK, load); err != nil {
+		logger.Errorf(context.TODO(), "Failed to write load response. Error: %v", err)
+	}
+}
+
+// Run collects the load periodically in the background until the context is cancelled.
+func (r *LoadReporter) Run(ctx context.Context) {
+	reporterCtx := contextutils.WithGoroutineLabel(ctx, "load-reporter")
+	ticker := time.NewTicker(r.interval)
+
+	go func() {
+		pprof.SetGoroutineLabels(reporterCtx)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reporterCtx.Done():
+				return
+			case <-ticker.C:
+				r.Collect()
+			}
+		}
+	}()
+}
+
+func NewLoadReporter(workQueue CompositeWorkQueue, workerPool *WorkerPool, interval time.Duration,
+	scope promutils.Scope) *LoadReporter {
+
+	return &LoadReporter{
+		workQueue:  workQueue,
+		workerPool: workerPool,
+		interval:   interval,
+		metrics: loadMetrics{
+			QueueDepth:    scope.MustNewGauge("queue_depth", "Number of workflows waiting for a worker"),
+			SubQueueDepth: scope.MustNewGauge("sub_queue_depth", "Number of workflows waiting to be moved to the queue"),
+			RoundLatency:  scope.MustNewGauge("round_latency_ms", "Average latency of the rounds since the previous collection"),
+			FreeWorkers:   scope.MustNewGauge("free_workers", "Number of workers that are not processing a workflow"),
+			Utilization:   scope.MustNewGauge("utilization_percent", "Percentage of the workers that are processing a workflow"),
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	propellerConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+func TestLoadReporter(t *testing.T) {
+	ctx := context.TODO()
+	scope := promutils.NewTestScope()
+	q, err := NewCompositeWorkQueue(ctx, propellerConfig.CompositeQueueConfig{
+		Type:             propellerConfig.CompositeQueueBatch,
+		BatchingInterval: config.Duration{Duration: time.Hour},
+	}, scope)
+	assert.NoError(t, err)
+
+	w := NewWorkerPool(ctx, scope, q, &testHandler{})
+	w.workers, w.freeWorkers = 4, 1
+	w.rounds, w.roundLatency = 2, int64(30*time.Millisecond)
+	q.Add("ns/wf-1")
+	q.Add("ns/wf-2")
+	q.AddToSubQueue("ns/wf-3")
+
+	r := NewLoadReporter(q, w, time.Minute, scope.NewSubScope("load"))
+	load := r.Collect()
+	assert.Equal(t, 2, load.QueueDepth)
+	assert.Equal(t, 1, load.SubQueueDepth)
+	assert.Equal(t, int64(15), load.RoundLatencyMillis)
+	assert.Equal(t, 4, load.Workers)
+	assert.Equal(t, 1, load.FreeWorkers)
+	assert.Equal(t, 75, load.UtilizationPercent)
+	assert.Equal(t, float64(2), testutil.ToFloat64(r.metrics.QueueDepth))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, AutoscalingPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	served := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Equal(t, float64(2), served["queueDepth"])
+
+	// Rounds are only averaged over the rounds since the previous collection.
+	assert.Equal(t, int64(0), r.Collect().RoundLatencyMillis)
+}
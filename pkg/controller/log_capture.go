@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/flyteorg/flytestdlib/storage"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const controllerLogsFile = "controller_logs.txt"
+
+// LogCapturingWorkflowExecutor keeps the last lines propeller logged while evaluating each workflow, at any level, and
+// writes them to the datastore next to the data of the workflow when it fails, so that the decisions propeller took can
+// be looked at without raising the level of the logger.
+type LogCapturingWorkflowExecutor struct {
+	executors.Workflow
+	store *storage.DataStore
+	lines int
+	// Buffers by workflow. The least recently evaluated workflows lose their buffer if there are too many.
+	buffers *lru.Cache
+}
+
+func (e *LogCapturingWorkflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+	return e.capture(ctx, w, func(ctx context.Context) error {
+		return e.Workflow.HandleFlyteWorkflow(ctx, w)
+	})
+}
+
+func (e *LogCapturingWorkflowExecutor) HandleAbortedWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow, maxRetries uint32) error {
+	return e.capture(ctx, w, func(ctx context.Context) error {
+		return e.Workflow.HandleAbortedWorkflow(ctx, w, maxRetries)
+	})
+}
+
+// ShedCache drops the captured lines of all the workflows.
+func (e *LogCapturingWorkflowExecutor) ShedCache(ctx context.Context) {
+	logger.Infof(ctx, "Dropping the captured logs of [%d] workflows", e.buffers.Len())
+	e.buffers.Purge()
+}
+
+// Evaluates the workflow with the lines it logs kept in its buffer. The buffer is dropped once the workflow terminates,
+// and written to the datastore first if the workflow failed.
+func (e *LogCapturingWorkflowExecutor) capture(ctx context.Context, w *v1alpha1.FlyteWorkflow, handle func(ctx context.Context) error) error {
+	key := w.GetK8sWorkflowID().String()
+	if w.GetExecutionStatus().IsTerminated() {
+		e.buffers.Remove(key)
+		return handle(ctx)
+	}
+
+	buf, ok := e.buffers.Get(key)
+	if !ok {
+		buf = logger.NewLogBuffer(e.lines)
+		e.buffers.Add(key, buf)
+	}
+
+	err := handle(logger.WithLogBuffer(ctx, buf.(*logger.LogBuffer)))
+	if status := w.GetExecutionStatus(); status.IsTerminated() {
+		e.buffers.Remove(key)
+		if status.GetPhase() == v1alpha1.WorkflowPhaseFailed {
+			e.write(ctx, status.GetDataDir(), buf.(*logger.LogBuffer))
+		}
+	}
+
+	return err
+}
+
+// Writes the buffer next to the data of the workflow. It is best effort, it does not fail the round.
+func (e *LogCapturingWorkflowExecutor) write(ctx context.Context, dataDir v1alpha1.DataReference, buf *logger.LogBuffer) {
+	if len(dataDir) == 0 {
+		logger.Warnf(ctx, "The failed workflow has no data dir, dropping its captured logs")
+		return
+	}
+
+	ref, err := e.store.ConstructReference(ctx, dataDir, controllerLogsFile)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to construct the reference of the captured logs of the workflow. Error: %v", err)
+		return
+	}
+
+	raw := []byte(buf.String())
+	if err := e.store.WriteRaw(ctx, ref, int64(len(raw)), storage.Options{}, bytes.NewReader(raw)); err != nil {
+		logger.Warnf(ctx, "Failed to write the captured logs of the workflow to [%v]. Error: %v", ref, err)
+		return
+	}
+
+	logger.Infof(ctx, "Wrote the captured logs of the failed workflow to [%v]", ref)
+}
+
+// NewLogCapturingWorkflowExecutor wraps the given executor to capture the logs of the workflows it evaluates.
+func NewLogCapturingWorkflowExecutor(cfg config.LogCaptureConfig, executor executors.Workflow, store *storage.DataStore) (*LogCapturingWorkflowExecutor, error) {
+	buffers, err := lru.New(cfg.MaxWorkflows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LogCapturingWorkflowExecutor{
+		Workflow: executor,
+		store:    store,
+		lines:    cfg.Lines,
+		buffers:  buffers,
+	}, nil
+}
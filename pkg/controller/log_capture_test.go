@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+func TestLogCapturingWorkflowExecutor(t *testing.T) {
+	ctx := context.TODO()
+	newWorkflow := func() *v1alpha1.FlyteWorkflow {
+		w := &v1alpha1.FlyteWorkflow{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "wf"}}
+		w.Status.SetDataDir("s3://bucket/wf")
+		w.Status.UpdatePhase(v1alpha1.WorkflowPhaseRunning, "", nil)
+		return w
+	}
+
+	// Logs a line with the context of every round and moves the workflow to the given phase.
+	handleTo := func(phase v1alpha1.WorkflowPhase, line string) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			logger.Info(args.Get(0).(context.Context), line)
+			args.Get(1).(*v1alpha1.FlyteWorkflow).Status.UpdatePhase(phase, "", nil)
+		}
+	}
+
+	readLogs := func(t *testing.T, store *storage.DataStore) (string, bool) {
+		ref := storage.DataReference("s3://bucket/wf/" + controllerLogsFile)
+		if md, err := store.Head(ctx, ref); !assert.NoError(t, err) || !md.Exists() {
+			return "", false
+		}
+
+		reader, err := store.ReadRaw(ctx, ref)
+		assert.NoError(t, err)
+		raw, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		return string(raw), true
+	}
+
+	t.Run("failed", func(t *testing.T) {
+		store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		wfExec := &mocks.Workflow{}
+		e, err := NewLogCapturingWorkflowExecutor(config.LogCaptureConfig{Lines: 2, MaxWorkflows: 10}, wfExec, store)
+		assert.NoError(t, err)
+		w := newWorkflow()
+
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.WorkflowPhaseRunning, "round 1")).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.WorkflowPhaseFailing, "round 2")).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		_, found := readLogs(t, store)
+		assert.False(t, found)
+
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.WorkflowPhaseFailed, "round 3")).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		logs, found := readLogs(t, store)
+		assert.True(t, found)
+		assert.NotContains(t, logs, "round 1")
+		assert.Contains(t, logs, "round 2")
+		assert.Contains(t, logs, "round 3")
+		assert.Equal(t, 0, e.buffers.Len())
+
+		// Rounds of terminated workflows are not captured.
+		wfExec.OnHandleAbortedWorkflowMatch(mock.Anything, w, uint32(1)).Run(handleTo(v1alpha1.WorkflowPhaseFailed, "round 4")).Return(nil).Once()
+		assert.NoError(t, e.HandleAbortedWorkflow(ctx, w, 1))
+		logs, _ = readLogs(t, store)
+		assert.NotContains(t, logs, "round 4")
+		assert.Equal(t, 0, e.buffers.Len())
+	})
+
+	t.Run("succeeded", func(t *testing.T) {
+		store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		wfExec := &mocks.Workflow{}
+		e, err := NewLogCapturingWorkflowExecutor(config.LogCaptureConfig{Lines: 2, MaxWorkflows: 10}, wfExec, store)
+		assert.NoError(t, err)
+		w := newWorkflow()
+
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.WorkflowPhaseSuccess, "round 1")).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		_, found := readLogs(t, store)
+		assert.False(t, found)
+		assert.Equal(t, 0, e.buffers.Len())
+	})
+
+	t.Run("aborted-system-failure", func(t *testing.T) {
+		store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		wfExec := &mocks.Workflow{}
+		e, err := NewLogCapturingWorkflowExecutor(config.LogCaptureConfig{Lines: 2, MaxWorkflows: 10}, wfExec, store)
+		assert.NoError(t, err)
+		w := newWorkflow()
+
+		wfExec.OnHandleAbortedWorkflowMatch(mock.Anything, w, uint32(1)).Run(handleTo(v1alpha1.WorkflowPhaseFailed, "aborted")).Return(nil).Once()
+		assert.NoError(t, e.HandleAbortedWorkflow(ctx, w, 1))
+		logs, found := readLogs(t, store)
+		assert.True(t, found)
+		assert.Contains(t, logs, "aborted")
+	})
+
+	t.Run("shed", func(t *testing.T) {
+		store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		wfExec := &mocks.Workflow{}
+		e, err := NewLogCapturingWorkflowExecutor(config.LogCaptureConfig{Lines: 2, MaxWorkflows: 10}, wfExec, store)
+		assert.NoError(t, err)
+		w := newWorkflow()
+
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(v1alpha1.WorkflowPhaseRunning, "round 1")).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+		assert.Equal(t, 1, e.buffers.Len())
+		e.ShedCache(ctx)
+		assert.Equal(t, 0, e.buffers.Len())
+	})
+}
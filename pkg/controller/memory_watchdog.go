@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const (
+	// Env var to lookup the pod namespace in, memory pressure events are recorded on propeller's own pod. It is
+	// specified like podNameEnvVar, using the metadata.namespace field.
+	podNamespaceEnvVar = "POD_NAMESPACE"
+
+	memoryPressureReason         = "MemoryPressure"
+	memoryPressureRelievedReason = "MemoryPressureRelieved"
+)
+
+// ActiveWorkerLimiter limits the number of workers that process workflows. A limit of 0 lifts the limit.
+type ActiveWorkerLimiter interface {
+	SetActiveWorkerLimit(limit int)
+}
+
+type memoryWatchdogMetrics struct {
+	MemoryUsage    prometheus.Gauge
+	Shedding       prometheus.Gauge
+	CachesShed     prometheus.Counter
+	PressureEvents prometheus.Counter
+}
+
+// MemoryWatchdog periodically checks the memory usage of propeller. When the usage crosses the high watermark, it
+// drops the caches of the registered shedders and reduces the number of active workers, so that propeller keeps
+// making progress, albeit slower, instead of getting OOMKilled. All workers become active again once the usage falls
+// below the low watermark. Caches are shed on every check while the usage stays above the high watermark.
+type MemoryWatchdog struct {
+	cfg            config.MemoryWatchdogConfig
+	highWatermark  uint64
+	lowWatermark   uint64
+	reducedWorkers int
+	readUsage      func() uint64
+	workers        ActiveWorkerLimiter
+	shedders       []executors.CacheShedder
+	recorder       record.EventRecorder
+	self           *corev1.ObjectReference
+	metrics        memoryWatchdogMetrics
+	lock           sync.Mutex
+	shedding       bool
+}
+
+// Check compares the current memory usage against the watermarks and sheds load or restores it accordingly.
+func (m *MemoryWatchdog) Check(ctx context.Context) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	usage := m.readUsage()
+	m.metrics.MemoryUsage.Set(float64(usage))
+
+	if usage >= m.highWatermark {
+		for _, s := range m.shedders {
+			s.ShedCache(ctx)
+		}
+		m.metrics.CachesShed.Inc()
+		// Return the memory that was just freed to the OS rather than waiting for the next GC cycle.
+		debug.FreeOSMemory()
+
+		if !m.shedding {
+			m.shedding = true
+			m.metrics.Shedding.Set(1)
+			m.metrics.PressureEvents.Inc()
+			m.workers.SetActiveWorkerLimit(m.reducedWorkers)
+			m.recordEvent(ctx, corev1.EventTypeWarning, memoryPressureReason, fmt.Sprintf(
+				"Memory usage [%d] bytes crossed the high watermark [%d] bytes, shed caches and reduced active workers to [%d]",
+				usage, m.highWatermark, m.reducedWorkers))
+		}
+		return
+	}
+
+	if m.shedding && usage <= m.lowWatermark {
+		m.shedding = false
+		m.metrics.Shedding.Set(0)
+		m.workers.SetActiveWorkerLimit(0)
+		m.recordEvent(ctx, corev1.EventTypeNormal, memoryPressureRelievedReason, fmt.Sprintf(
+			"Memory usage [%d] bytes fell below the low watermark [%d] bytes, all workers are active again",
+			usage, m.lowWatermark))
+	}
+}
+
+// IsShedding returns true while the memory usage has not yet recovered from crossing the high watermark.
+func (m *MemoryWatchdog) IsShedding() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.shedding
+}
+
+func (m *MemoryWatchdog) recordEvent(ctx context.Context, eventType, reason, message string) {
+	if eventType == corev1.EventTypeWarning {
+		logger.Warn(ctx, message)
+	} else {
+		logger.Info(ctx, message)
+	}
+
+	if m.self != nil {
+		m.recorder.Event(m.self, eventType, reason, message)
+	}
+}
+
+// Run starts checking the memory usage in the background until the context is cancelled.
+func (m *MemoryWatchdog) Run(ctx context.Context) {
+	watchdogCtx := contextutils.WithGoroutineLabel(ctx, "memory-watchdog")
+	ticker := time.NewTicker(m.cfg.CheckInterval.Duration)
+
+	go func() {
+		pprof.SetGoroutineLabels(watchdogCtx)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-watchdogCtx.Done():
+				return
+			case <-ticker.C:
+				m.Check(watchdogCtx)
+			}
+		}
+	}()
+}
+
+// Approximates the resident memory of the process by the memory obtained from the OS that has not been returned to it.
+func readMemoryUsage() uint64 {
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+	return stats.Sys - stats.HeapReleased
+}
+
+// Returns a reference to propeller's own pod if the downward API exposes it, so that events can be recorded on it.
+func getSelfReference() *corev1.ObjectReference {
+	name, found := os.LookupEnv(podNameEnvVar)
+	if !found {
+		return nil
+	}
+
+	namespace, found := os.LookupEnv(podNamespaceEnvVar)
+	if !found {
+		return nil
+	}
+
+	return &corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Namespace: namespace, Name: name}
+}
+
+func NewMemoryWatchdog(cfg config.MemoryWatchdogConfig, numWorkers int, workers ActiveWorkerLimiter,
+	recorder record.EventRecorder, scope promutils.Scope, shedders ...executors.CacheShedder) (*MemoryWatchdog, error) {
+
+	limit, err := resource.ParseQuantity(cfg.MemoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory limit [%v]: %w", cfg.MemoryLimit, err)
+	}
+
+	if limit.Value() <= 0 {
+		return nil, fmt.Errorf("memory limit must be positive, found [%v]", cfg.MemoryLimit)
+	}
+
+	if cfg.LowWatermarkPercent <= 0 || cfg.LowWatermarkPercent > cfg.HighWatermarkPercent || cfg.HighWatermarkPercent > 100 {
+		return nil, fmt.Errorf("watermarks must satisfy 0 < low [%d] <= high [%d] <= 100",
+			cfg.LowWatermarkPercent, cfg.HighWatermarkPercent)
+	}
+
+	if cfg.CheckInterval.Duration <= 0 {
+		return nil, fmt.Errorf("check interval must be positive, found [%v]", cfg.CheckInterval.Duration)
+	}
+
+	reducedWorkers := numWorkers * cfg.ReducedWorkersPercent / 100
+	if reducedWorkers < 1 {
+		reducedWorkers = 1
+	}
+
+	limitBytes := uint64(limit.Value())
+	return &MemoryWatchdog{
+		cfg:            cfg,
+		highWatermark:  limitBytes * uint64(cfg.HighWatermarkPercent) / 100,
+		lowWatermark:   limitBytes * uint64(cfg.LowWatermarkPercent) / 100,
+		reducedWorkers: reducedWorkers,
+		readUsage:      readMemoryUsage,
+		workers:        workers,
+		shedders:       shedders,
+		recorder:       recorder,
+		self:           getSelfReference(),
+		metrics: memoryWatchdogMetrics{
+			MemoryUsage:    scope.MustNewGauge("memory_usage_bytes", "Memory usage of propeller as observed by the memory watchdog"),
+			Shedding:       scope.MustNewGauge("memory_shedding", "1 while propeller sheds load due to high memory usage, 0 otherwise"),
+			CachesShed:     scope.MustNewCounter("memory_caches_shed", "Number of times caches were shed due to high memory usage"),
+			PressureEvents: scope.MustNewCounter("memory_pressure_count", "Number of times the memory usage crossed the high watermark"),
+		},
+	}, nil
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	stdConfig "github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+)
+
+type fakeWorkerLimiter struct {
+	limit int
+}
+
+func (f *fakeWorkerLimiter) SetActiveWorkerLimit(limit int) {
+	f.limit = limit
+}
+
+func TestMemoryWatchdog_Check(t *testing.T) {
+	ctx := context.TODO()
+	cfg := config.MemoryWatchdogConfig{
+		Enabled:               true,
+		MemoryLimit:           "1000",
+		HighWatermarkPercent:  90,
+		LowWatermarkPercent:   70,
+		CheckInterval:         stdConfig.Duration{Duration: time.Second},
+		ReducedWorkersPercent: 25,
+	}
+
+	assert.NoError(t, os.Setenv(podNameEnvVar, "propeller"))
+	assert.NoError(t, os.Setenv(podNamespaceEnvVar, "flyte"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv(podNameEnvVar))
+		assert.NoError(t, os.Unsetenv(podNamespaceEnvVar))
+	}()
+
+	shedder := &mocks.CacheShedder{}
+	shedder.On("ShedCache", ctx).Return()
+	workers := &fakeWorkerLimiter{}
+	recorder := record.NewFakeRecorder(10)
+
+	w, err := NewMemoryWatchdog(cfg, 8, workers, recorder, promutils.NewTestScope(), shedder)
+	assert.NoError(t, err)
+
+	usage := uint64(0)
+	w.readUsage = func() uint64 { return usage }
+
+	t.Run("below-high-watermark", func(t *testing.T) {
+		usage = 800
+		w.Check(ctx)
+		assert.False(t, w.IsShedding())
+		shedder.AssertNotCalled(t, "ShedCache", mock.Anything)
+		assert.Equal(t, 0, workers.limit)
+	})
+
+	t.Run("crossed-high-watermark", func(t *testing.T) {
+		usage = 950
+		w.Check(ctx)
+		assert.True(t, w.IsShedding())
+		shedder.AssertNumberOfCalls(t, "ShedCache", 1)
+		assert.Equal(t, 2, workers.limit)
+		assert.Contains(t, <-recorder.Events, memoryPressureReason)
+		assert.Equal(t, float64(1), testutil.ToFloat64(w.metrics.Shedding))
+	})
+
+	t.Run("still-high", func(t *testing.T) {
+		w.Check(ctx)
+		shedder.AssertNumberOfCalls(t, "ShedCache", 2)
+		assert.Equal(t, float64(1), testutil.ToFloat64(w.metrics.PressureEvents))
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("between-watermarks", func(t *testing.T) {
+		usage = 800
+		w.Check(ctx)
+		assert.True(t, w.IsShedding())
+		assert.Equal(t, 2, workers.limit)
+		shedder.AssertNumberOfCalls(t, "ShedCache", 2)
+	})
+
+	t.Run("recovered", func(t *testing.T) {
+		usage = 600
+		w.Check(ctx)
+		assert.False(t, w.IsShedding())
+		assert.Equal(t, 0, workers.limit)
+		assert.Contains(t, <-recorder.Events, memoryPressureRelievedReason)
+		assert.Equal(t, float64(0), testutil.ToFloat64(w.metrics.Shedding))
+	})
+}
+
+func TestNewMemoryWatchdog_InvalidConfig(t *testing.T) {
+	valid := config.MemoryWatchdogConfig{
+		MemoryLimit:           "4Gi",
+		HighWatermarkPercent:  90,
+		LowWatermarkPercent:   75,
+		CheckInterval:         stdConfig.Duration{Duration: time.Second},
+		ReducedWorkersPercent: 50,
+	}
+
+	_, err := NewMemoryWatchdog(valid, 1, &fakeWorkerLimiter{}, record.NewFakeRecorder(1), promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	invalidLimit := valid
+	invalidLimit.MemoryLimit = "lots"
+	_, err = NewMemoryWatchdog(invalidLimit, 1, &fakeWorkerLimiter{}, record.NewFakeRecorder(1), promutils.NewTestScope())
+	assert.Error(t, err)
+
+	invertedWatermarks := valid
+	invertedWatermarks.LowWatermarkPercent = 95
+	_, err = NewMemoryWatchdog(invertedWatermarks, 1, &fakeWorkerLimiter{}, record.NewFakeRecorder(1), promutils.NewTestScope())
+	assert.Error(t, err)
+}
+
+func TestWorkerPool_SetActiveWorkerLimit(t *testing.T) {
+	w := &WorkerPool{}
+	assert.False(t, w.isLimited(3))
+
+	w.SetActiveWorkerLimit(2)
+	assert.False(t, w.isLimited(0))
+	assert.False(t, w.isLimited(1))
+	assert.True(t, w.isLimited(2))
+
+	w.SetActiveWorkerLimit(0)
+	assert.False(t, w.isLimited(2))
+}
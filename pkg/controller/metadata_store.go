@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/tracing"
+)
+
+// Names of the middlewares the metadata store can be wrapped with, see config.MetadataStoreConfig.
+const (
+	MetadataStoreTenancy     = "tenancy"
+	MetadataStoreChaos       = "chaos"
+	MetadataStoreMetrics     = "metrics"
+	MetadataStoreCompression = "compression"
+	MetadataStoreEncryption  = "encryption"
+)
+
+// The middlewares that wrap the raw reads and writes of the store and re-encode protobufs on top of them, and the ones
+// that transform protobufs. The former bypass the latter when they wrap them, so they have to be listed first.
+var (
+	rawMetadataStoreMiddlewares = map[string]bool{
+		MetadataStoreTenancy: true,
+		MetadataStoreChaos:   true,
+		MetadataStoreMetrics: true,
+	}
+
+	protobufMetadataStoreMiddlewares = map[string]bool{
+		MetadataStoreCompression: true,
+		MetadataStoreEncryption:  true,
+	}
+)
+
+// DataStoreMiddleware wraps the metadata store to add a cross-cutting feature to all its reads and writes.
+type DataStoreMiddleware func(store *storage.DataStore) (*storage.DataStore, error)
+
+// wrapDataStore wraps the store with the middlewares of the given names, the first one being the innermost. Middlewares
+// that are known but disabled by their config are registered as nil and skipped. Middlewares that wrap raw reads and
+// writes have to come before the ones that transform protobufs, whether they are enabled or not.
+func wrapDataStore(store *storage.DataStore, names []string, middlewares map[string]DataStoreMiddleware) (
+	*storage.DataStore, error) {
+
+	applied := make(map[string]bool, len(names))
+	protobufMiddleware := ""
+	for _, name := range names {
+		middleware, found := middlewares[name]
+		if !found {
+			return nil, fmt.Errorf("unknown metadata store middleware [%v]", name)
+		}
+
+		if applied[name] {
+			return nil, fmt.Errorf("metadata store middleware [%v] is listed more than once", name)
+		}
+
+		if rawMetadataStoreMiddlewares[name] && len(protobufMiddleware) > 0 {
+			return nil, fmt.Errorf("metadata store middleware [%v] wraps raw reads and writes and would bypass [%v], "+
+				"it has to be listed before it", name, protobufMiddleware)
+		}
+
+		if protobufMetadataStoreMiddlewares[name] && len(protobufMiddleware) == 0 {
+			protobufMiddleware = name
+		}
+
+		applied[name] = true
+		if middleware == nil {
+			continue
+		}
+
+		var err error
+		if store, err = middleware(store); err != nil {
+			return nil, fmt.Errorf("failed to apply metadata store middleware [%v]: %w", name, err)
+		}
+	}
+
+	return store, nil
+}
+
+// Operations of the metadata store, as reported in the op label of its metrics.
+const (
+	metadataStoreHead  = "head"
+	metadataStoreRead  = "read"
+	metadataStoreWrite = "write"
+	metadataStoreCopy  = "copy"
+)
+
+type metadataStoreMetrics struct {
+	Latency  *prometheus.HistogramVec
+	Bytes    *prometheus.CounterVec
+	Failures *prometheus.CounterVec
+}
+
+// instrumentedRawStore times the operations of the metadata store and counts the bytes read and written, so operators
+// can tell how much metadata executions move. The latencies carry the trace of the execution as exemplar.
+type instrumentedRawStore struct {
+	storage.RawStore
+	metrics *metadataStoreMetrics
+}
+
+func (s instrumentedRawStore) observe(ctx context.Context, op string, start time.Time, err error) {
+	tracing.Observe(ctx, s.metrics.Latency.WithLabelValues(op), time.Since(start).Seconds())
+	if err != nil && !storage.IsFailedWriteToCache(err) {
+		s.metrics.Failures.WithLabelValues(op).Inc()
+	}
+}
+
+func (s instrumentedRawStore) Head(ctx context.Context, reference storage.DataReference) (storage.Metadata, error) {
+	start := time.Now()
+	metadata, err := s.RawStore.Head(ctx, reference)
+	s.observe(ctx, metadataStoreHead, start, err)
+	return metadata, err
+}
+
+func (s instrumentedRawStore) ReadRaw(ctx context.Context, reference storage.DataReference) (io.ReadCloser, error) {
+	start := time.Now()
+	rc, err := s.RawStore.ReadRaw(ctx, reference)
+	s.observe(ctx, metadataStoreRead, start, err)
+	if rc == nil {
+		return rc, err
+	}
+
+	return &countingReadCloser{ReadCloser: rc, bytes: s.metrics.Bytes.WithLabelValues(metadataStoreRead)}, err
+}
+
+func (s instrumentedRawStore) WriteRaw(ctx context.Context, reference storage.DataReference, size int64,
+	opts storage.Options, raw io.Reader) error {
+
+	start := time.Now()
+	err := s.RawStore.WriteRaw(ctx, reference, size, opts, raw)
+	s.observe(ctx, metadataStoreWrite, start, err)
+	// The reader is passed as is, stores may need it to be seekable, so the size of writes is the declared one.
+	if (err == nil || storage.IsFailedWriteToCache(err)) && size > 0 {
+		s.metrics.Bytes.WithLabelValues(metadataStoreWrite).Add(float64(size))
+	}
+
+	return err
+}
+
+func (s instrumentedRawStore) CopyRaw(ctx context.Context, source, destination storage.DataReference,
+	opts storage.Options) error {
+
+	start := time.Now()
+	err := s.RawStore.CopyRaw(ctx, source, destination, opts)
+	s.observe(ctx, metadataStoreCopy, start, err)
+	return err
+}
+
+// countingReadCloser adds the number of bytes read through it to a counter.
+type countingReadCloser struct {
+	io.ReadCloser
+	bytes prometheus.Counter
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.bytes.Add(float64(n))
+	return n, err
+}
+
+// newInstrumentedDataStore wraps the datastore so that the latency, the failures and the bytes of all its operations
+// are recorded. It wraps raw reads and writes, so it has to come before the middlewares that transform protobufs.
+func newInstrumentedDataStore(store *storage.DataStore, scope promutils.Scope) *storage.DataStore {
+	rawStore := instrumentedRawStore{
+		RawStore: store.ComposedProtobufStore,
+		metrics: &metadataStoreMetrics{
+			Latency:  scope.MustNewHistogramVec("latency", "Latency of metadata store operations in seconds", "op"),
+			Bytes:    scope.MustNewCounterVec("bytes", "Number of bytes read from and written to the metadata store", "op"),
+			Failures: scope.MustNewCounterVec("failures", "Number of metadata store operations that failed", "op"),
+		},
+	}
+
+	return storage.NewCompositeDataStore(store.ReferenceConstructor,
+		storage.NewDefaultProtobufStore(rawStore, scope.NewSubScope("protobuf")))
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMemoryDataStore(t *testing.T) *storage.DataStore {
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	return store
+}
+
+func TestWrapDataStore(t *testing.T) {
+	var applied []string
+	recording := func(name string) DataStoreMiddleware {
+		return func(store *storage.DataStore) (*storage.DataStore, error) {
+			applied = append(applied, name)
+			return store, nil
+		}
+	}
+
+	middlewares := map[string]DataStoreMiddleware{
+		"inner":    recording("inner"),
+		"outer":    recording("outer"),
+		"disabled": nil,
+		"failing": func(store *storage.DataStore) (*storage.DataStore, error) {
+			return nil, fmt.Errorf("failed")
+		},
+	}
+
+	t.Run("in order", func(t *testing.T) {
+		applied = nil
+		_, err := wrapDataStore(newMemoryDataStore(t), []string{"inner", "disabled", "outer"}, middlewares)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"inner", "outer"}, applied)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := wrapDataStore(newMemoryDataStore(t), []string{"inner", "unknown"}, middlewares)
+		assert.EqualError(t, err, "unknown metadata store middleware [unknown]")
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := wrapDataStore(newMemoryDataStore(t), []string{"inner", "inner"}, middlewares)
+		assert.EqualError(t, err, "metadata store middleware [inner] is listed more than once")
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		_, err := wrapDataStore(newMemoryDataStore(t), []string{"failing"}, middlewares)
+		assert.EqualError(t, err, "failed to apply metadata store middleware [failing]: failed")
+	})
+
+	t.Run("raw after protobuf", func(t *testing.T) {
+		middlewares := map[string]DataStoreMiddleware{
+			MetadataStoreTenancy:     recording(MetadataStoreTenancy),
+			MetadataStoreMetrics:     recording(MetadataStoreMetrics),
+			MetadataStoreCompression: recording(MetadataStoreCompression),
+			MetadataStoreEncryption:  nil,
+		}
+
+		applied = nil
+		_, err := wrapDataStore(newMemoryDataStore(t), []string{MetadataStoreTenancy, MetadataStoreMetrics,
+			MetadataStoreCompression, MetadataStoreEncryption}, middlewares)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{MetadataStoreTenancy, MetadataStoreMetrics, MetadataStoreCompression}, applied)
+
+		_, err = wrapDataStore(newMemoryDataStore(t), []string{MetadataStoreCompression, MetadataStoreMetrics}, middlewares)
+		assert.EqualError(t, err, "metadata store middleware [metrics] wraps raw reads and writes and would bypass "+
+			"[compression], it has to be listed before it")
+
+		// Disabled middlewares are ordered as well, enabling them must not change whether the config is valid.
+		_, err = wrapDataStore(newMemoryDataStore(t), []string{MetadataStoreEncryption, MetadataStoreTenancy}, middlewares)
+		assert.EqualError(t, err, "metadata store middleware [tenancy] wraps raw reads and writes and would bypass "+
+			"[encryption], it has to be listed before it")
+	})
+}
+
+func TestInstrumentedDataStore(t *testing.T) {
+	ctx := context.TODO()
+	store := newInstrumentedDataStore(newMemoryDataStore(t), promutils.NewTestScope())
+	metrics := store.ComposedProtobufStore.(storage.DefaultProtobufStore).RawStore.(instrumentedRawStore).metrics
+
+	literals := &core.LiteralMap{Literals: map[string]*core.Literal{"x": {}}}
+	assert.NoError(t, store.WriteProtobuf(ctx, "s3://bucket/outputs.pb", storage.Options{}, literals))
+	size := float64(proto.Size(literals))
+	assert.Equal(t, size, testutil.ToFloat64(metrics.Bytes.WithLabelValues(metadataStoreWrite)))
+
+	read := &core.LiteralMap{}
+	assert.NoError(t, store.ReadProtobuf(ctx, "s3://bucket/outputs.pb", read))
+	assert.True(t, proto.Equal(literals, read))
+	assert.Equal(t, size, testutil.ToFloat64(metrics.Bytes.WithLabelValues(metadataStoreRead)))
+
+	_, err := store.ReadRaw(ctx, "s3://bucket/missing.pb")
+	assert.Error(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Failures.WithLabelValues(metadataStoreRead)))
+
+	rc, err := store.ReadRaw(ctx, "s3://bucket/outputs.pb")
+	assert.NoError(t, err)
+	_, err = ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.NoError(t, rc.Close())
+	assert.Equal(t, 2*size, testutil.ToFloat64(metrics.Bytes.WithLabelValues(metadataStoreRead)))
+}
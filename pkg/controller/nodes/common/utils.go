@@ -3,6 +3,8 @@ package common
 import (
 	"strconv"
 
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/utils"
@@ -35,3 +37,25 @@ func CreateParentInfo(grandParentInfo executors.ImmutableParentInfo, nodeID stri
 	return executors.NewParentInfo(uniqueID, parentAttempt), nil
 
 }
+
+// CreateParentTaskInfo creates the parent info of the nodes generated by a task execution of the parent node, e.g. a
+// dynamic task. The task execution is identified by the unique id of the parent node, like its task events are, so that
+// events of the generated nodes are nested under it at any depth.
+func CreateParentTaskInfo(grandParentInfo executors.ImmutableParentInfo, nodeID string, parentAttempt uint32,
+	taskExecID *core.TaskExecutionIdentifier) (executors.ImmutableParentInfo, error) {
+	uniqueID, err := GenerateUniqueID(grandParentInfo, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	parentTaskID := &core.TaskExecutionIdentifier{
+		TaskId:       taskExecID.GetTaskId(),
+		RetryAttempt: taskExecID.GetRetryAttempt(),
+		NodeExecutionId: &core.NodeExecutionIdentifier{
+			ExecutionId: taskExecID.GetNodeExecutionId().GetExecutionId(),
+			NodeId:      uniqueID,
+		},
+	}
+
+	return executors.NewParentTaskInfo(uniqueID, parentAttempt, parentTaskID), nil
+}
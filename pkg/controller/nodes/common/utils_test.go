@@ -3,7 +3,9 @@ package common
 import (
 	"testing"
 
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -57,3 +59,31 @@ func TestCreateParentInfoNil(t *testing.T) {
 	assert.Equal(t, "n1", parent.GetUniqueID())
 	assert.Equal(t, uint32(1), parent.CurrentAttempt())
 }
+
+func TestCreateParentTaskInfo(t *testing.T) {
+	gp := ParentInfo{
+		uniqueID: "u1",
+		attempt:  uint32(2),
+	}
+	execID := &core.WorkflowExecutionIdentifier{Name: "e1"}
+	taskExecID := &core.TaskExecutionIdentifier{
+		TaskId:       &core.Identifier{Name: "t1"},
+		RetryAttempt: 1,
+		NodeExecutionId: &core.NodeExecutionIdentifier{
+			ExecutionId: execID,
+			NodeId:      "n1",
+		},
+	}
+	parent, err := CreateParentTaskInfo(gp, "n1", uint32(1), taskExecID)
+	assert.Nil(t, err)
+	assert.Equal(t, "u1-2-n1", parent.GetUniqueID())
+	assert.Equal(t, uint32(1), parent.CurrentAttempt())
+
+	parentTaskID := parent.(executors.ImmutableParentTaskInfo).GetParentTaskID()
+	assert.Equal(t, "t1", parentTaskID.GetTaskId().GetName())
+	assert.Equal(t, uint32(1), parentTaskID.GetRetryAttempt())
+	assert.Equal(t, execID, parentTaskID.GetNodeExecutionId().GetExecutionId())
+	assert.Equal(t, "u1-2-n1", parentTaskID.GetNodeExecutionId().GetNodeId())
+	// The task execution id of the parent node itself is left untouched.
+	assert.Equal(t, "n1", taskExecID.GetNodeExecutionId().GetNodeId())
+}
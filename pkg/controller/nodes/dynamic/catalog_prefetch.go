@@ -0,0 +1,100 @@
+package dynamic
+
+import (
+	"context"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// catalogPrefetcher is implemented by task node handlers that can look up the cached outputs of many task executions
+// before they run.
+type catalogPrefetcher interface {
+	PrefetchCatalog(ctx context.Context, requests []task.CatalogPrefetchRequest)
+}
+
+// Returns the literal of binding data that does not depend on the outputs of other nodes. Returns false if the binding
+// data refers to an output that is only known once the workflow runs.
+func resolveStaticBindingData(bindingData *core.BindingData) (*core.Literal, bool) {
+	switch bindingData.GetValue().(type) {
+	case *core.BindingData_Scalar:
+		return &core.Literal{Value: &core.Literal_Scalar{Scalar: bindingData.GetScalar()}}, true
+	case *core.BindingData_Collection:
+		literals := make([]*core.Literal, 0, len(bindingData.GetCollection().GetBindings()))
+		for _, b := range bindingData.GetCollection().GetBindings() {
+			l, ok := resolveStaticBindingData(b)
+			if !ok {
+				return nil, false
+			}
+
+			literals = append(literals, l)
+		}
+
+		return &core.Literal{Value: &core.Literal_Collection{Collection: &core.LiteralCollection{Literals: literals}}}, true
+	case *core.BindingData_Map:
+		literals := make(map[string]*core.Literal, len(bindingData.GetMap().GetBindings()))
+		for k, b := range bindingData.GetMap().GetBindings() {
+			l, ok := resolveStaticBindingData(b)
+			if !ok {
+				return nil, false
+			}
+
+			literals[k] = l
+		}
+
+		return &core.Literal{Value: &core.Literal_Map{Map: &core.LiteralMap{Literals: literals}}}, true
+	}
+
+	return nil, false
+}
+
+// Returns the prefetch requests of the task nodes of the workflow whose inputs are all static.
+func buildCatalogPrefetchRequests(ctx context.Context, w v1alpha1.ExecutableWorkflow) []task.CatalogPrefetchRequest {
+	requests := make([]task.CatalogPrefetchRequest, 0, len(w.GetNodes()))
+	for _, nodeID := range w.GetNodes() {
+		n, ok := w.GetNode(nodeID)
+		if !ok || n.GetKind() != v1alpha1.NodeKindTask || n.GetTaskID() == nil {
+			continue
+		}
+
+		tk, err := w.GetTask(*n.GetTaskID())
+		if err != nil || !tk.CoreTask().GetMetadata().GetDiscoverable() {
+			continue
+		}
+
+		inputs := &core.LiteralMap{Literals: make(map[string]*core.Literal, len(n.GetInputBindings()))}
+		static := true
+		for _, b := range n.GetInputBindings() {
+			l, ok := resolveStaticBindingData(b.GetBinding())
+			if !ok {
+				static = false
+				break
+			}
+
+			inputs.Literals[b.GetVar()] = l
+		}
+
+		if !static {
+			logger.Debugf(ctx, "Not prefetching node [%v], its inputs depend on other nodes", nodeID)
+			continue
+		}
+
+		requests = append(requests, task.CatalogPrefetchRequest{Task: tk.CoreTask(), Inputs: inputs})
+	}
+
+	return requests
+}
+
+// Looks up the cached outputs of the task nodes of the dynamic workflow in bulk, so that map-style dynamic workflows do
+// not look up their sub-nodes in the catalog one at a time as they start.
+func (d dynamicNodeTaskNodeHandler) prefetchCatalog(ctx context.Context, dCtx dynamicWorkflowContext) {
+	prefetcher, ok := d.TaskNodeHandler.(catalogPrefetcher)
+	if !ok || !dCtx.isDynamic || dCtx.subWorkflow == nil {
+		return
+	}
+
+	prefetcher.PrefetchCatalog(ctx, buildCatalogPrefetchRequests(ctx, dCtx.subWorkflow))
+}
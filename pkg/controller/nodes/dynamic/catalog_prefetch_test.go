@@ -0,0 +1,65 @@
+package dynamic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
+)
+
+func Test_buildCatalogPrefetchRequests(t *testing.T) {
+	ctx := context.TODO()
+
+	scalarBinding := func(v int64) *core.BindingData {
+		return &core.BindingData{Value: &core.BindingData_Scalar{Scalar: coreutils.MustMakeLiteral(v).GetScalar()}}
+	}
+
+	promiseBinding := &core.BindingData{Value: &core.BindingData_Promise{
+		Promise: &core.OutputReference{NodeId: "upstream", Var: "x"}}}
+
+	newNode := func(kind v1alpha1.NodeKind, taskID string, bindings ...*core.BindingData) *mocks.ExecutableNode {
+		n := &mocks.ExecutableNode{}
+		n.OnGetKind().Return(kind)
+		n.OnGetTaskID().Return(&taskID)
+		inputs := make([]*v1alpha1.Binding, 0, len(bindings))
+		for _, b := range bindings {
+			inputs = append(inputs, &v1alpha1.Binding{Binding: &core.Binding{Var: "x", Binding: b}})
+		}
+
+		n.OnGetInputBindings().Return(inputs)
+		return n
+	}
+
+	newTask := func(discoverable bool) *mocks.ExecutableTask {
+		tk := &mocks.ExecutableTask{}
+		tk.OnCoreTask().Return(&core.TaskTemplate{Metadata: &core.TaskMetadata{Discoverable: discoverable}})
+		return tk
+	}
+
+	w := &mocks.ExecutableWorkflow{}
+	w.OnGetNodes().Return([]v1alpha1.NodeID{"static", "collection", "promise", "not-cached", "branch"})
+	w.OnGetNode("static").Return(newNode(v1alpha1.NodeKindTask, "cached", scalarBinding(1)), true)
+	w.OnGetNode("collection").Return(newNode(v1alpha1.NodeKindTask, "cached", &core.BindingData{
+		Value: &core.BindingData_Collection{Collection: &core.BindingDataCollection{
+			Bindings: []*core.BindingData{scalarBinding(1), scalarBinding(2)},
+		}}}), true)
+	w.OnGetNode("promise").Return(newNode(v1alpha1.NodeKindTask, "cached", &core.BindingData{
+		Value: &core.BindingData_Collection{Collection: &core.BindingDataCollection{
+			Bindings: []*core.BindingData{scalarBinding(1), promiseBinding},
+		}}}), true)
+	w.OnGetNode("not-cached").Return(newNode(v1alpha1.NodeKindTask, "not-cached", scalarBinding(1)), true)
+	w.OnGetNode("branch").Return(newNode(v1alpha1.NodeKindBranch, ""), true)
+	w.OnGetTask("cached").Return(newTask(true), nil)
+	w.OnGetTask("not-cached").Return(newTask(false), nil)
+
+	requests := buildCatalogPrefetchRequests(ctx, w)
+	if assert.Len(t, requests, 2) {
+		assert.Equal(t, int64(1), requests[0].Inputs.Literals["x"].GetScalar().GetPrimitive().GetInteger())
+		assert.Len(t, requests[1].Inputs.Literals["x"].GetCollection().GetLiterals(), 2)
+	}
+}
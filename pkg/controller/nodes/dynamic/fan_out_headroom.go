@@ -0,0 +1,156 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// fanOutExecContext caps the number of task sub-nodes of a dynamic node that run at once, so that sub-nodes that are
+// ready are launched in batches the namespace can take. The parallelism of the sub-nodes is counted separately from the
+// workflow, but still added to it, so that the parallelism limit of the workflow keeps applying.
+type fanOutExecContext struct {
+	executors.ExecutionContext
+	budget      uint32
+	parallelism uint32
+}
+
+// A max parallelism of zero disables the parallelism check, so the budget and the parallelism are both offset by one.
+func (f *fanOutExecContext) GetExecutionConfig() v1alpha1.ExecutionConfig {
+	cfg := f.ExecutionContext.GetExecutionConfig()
+	cfg.MaxParallelism = f.budget + 1
+	return cfg
+}
+
+func (f *fanOutExecContext) CurrentParallelism() uint32 {
+	if maxParallelism := f.ExecutionContext.GetExecutionConfig().MaxParallelism; maxParallelism > 0 &&
+		f.ExecutionContext.CurrentParallelism() >= maxParallelism {
+		return f.budget + 1
+	}
+
+	return f.parallelism + 1
+}
+
+func (f *fanOutExecContext) IncrementParallelism() uint32 {
+	f.ExecutionContext.IncrementParallelism()
+	f.parallelism++
+	return f.parallelism + 1
+}
+
+// Counts the task sub-nodes of the dynamic workflow that have not been launched yet and the ones that are running.
+func countTaskSubNodes(ctx context.Context, w v1alpha1.ExecutableWorkflow, nl executors.NodeLookup) (waiting, running int) {
+	for _, nodeID := range w.GetNodes() {
+		n, ok := w.GetNode(nodeID)
+		if !ok || n.GetKind() != v1alpha1.NodeKindTask {
+			continue
+		}
+
+		switch nl.GetNodeExecutionStatus(ctx, nodeID).GetPhase() {
+		case v1alpha1.NodePhaseNotYetStarted, v1alpha1.NodePhaseQueued:
+			waiting++
+		case v1alpha1.NodePhaseRunning, v1alpha1.NodePhaseRetryableFailure:
+			running++
+		}
+	}
+
+	return waiting, running
+}
+
+// Returns the number of pods that can be launched in the namespace before its pending pods reach the configured maximum
+// or one of its resource quotas runs out of pods, along with the reason for the limit.
+func (d dynamicNodeTaskNodeHandler) namespaceHeadroom(ctx context.Context, namespace string) (int, string, error) {
+	pods := &v1.PodList{}
+	if err := d.kubeClient.GetClient().List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return 0, "", err
+	}
+
+	pending := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodPending {
+			pending++
+		}
+	}
+
+	headroom := d.fanOutHeadroom.MaxPendingPods - pending
+	reason := fmt.Sprintf("namespace [%v] has [%v/%v] pending pods", namespace, pending, d.fanOutHeadroom.MaxPendingPods)
+
+	quotas := &v1.ResourceQuotaList{}
+	if err := d.kubeClient.GetClient().List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+		return 0, "", err
+	}
+
+	for _, quota := range quotas.Items {
+		hard, found := quota.Status.Hard[v1.ResourcePods]
+		if !found {
+			continue
+		}
+
+		used := quota.Status.Used[v1.ResourcePods]
+		if remaining := int(hard.Value() - used.Value()); remaining < headroom {
+			headroom = remaining
+			reason = fmt.Sprintf("resource quota [%v/%v] has [%v/%v] pods in use", namespace, quota.Name, used.Value(),
+				hard.Value())
+		}
+	}
+
+	if headroom < 0 {
+		headroom = 0
+	}
+
+	return headroom, reason, nil
+}
+
+// Returns the execution context to progress the sub-nodes of the dynamic node with. If the dynamic node fans out to more
+// task sub-nodes that are waiting to be launched than the namespace has headroom for, the returned context only lets as
+// many of them run as the namespace can take, and the backpressure is recorded in the status of the dynamic node.
+func (d dynamicNodeTaskNodeHandler) applyFanOutHeadroom(ctx context.Context, nCtx handler.NodeExecutionContext,
+	dCtx dynamicWorkflowContext) (executors.ExecutionContext, error) {
+
+	if !d.fanOutHeadroom.Enabled || d.kubeClient == nil || !dCtx.isDynamic {
+		return dCtx.execContext, nil
+	}
+
+	waiting, running := countTaskSubNodes(ctx, dCtx.subWorkflow, dCtx.nodeLookup)
+	if waiting+running < d.fanOutHeadroom.MinFanOut {
+		return dCtx.execContext, nil
+	}
+
+	nodeStatus := nCtx.NodeStatus()
+	headroom := waiting
+	reason := ""
+	if waiting > 0 {
+		var err error
+		if headroom, reason, err = d.namespaceHeadroom(ctx, nCtx.NodeExecutionMetadata().GetNamespace()); err != nil {
+			return nil, err
+		}
+	}
+
+	if headroom >= waiting {
+		if nodeStatus.GetFanOutBackpressure() != nil {
+			logger.Infof(ctx, "Namespace has headroom for the [%d] waiting sub-nodes again", waiting)
+			nodeStatus.SetFanOutBackpressure(nil)
+		}
+
+		return dCtx.execContext, nil
+	}
+
+	logger.Infof(ctx, "Launching at most [%d] of [%d] waiting sub-nodes, %v", headroom, waiting, reason)
+	d.metrics.FanOutBackpressure.Inc(ctx)
+	if nodeStatus.GetFanOutBackpressure() == nil {
+		nodeStatus.SetFanOutBackpressure(&v1alpha1.FanOutBackpressure{
+			Reason: fmt.Sprintf("launching at most [%v] of [%v] waiting sub-nodes, %v", headroom, waiting, reason),
+		})
+	}
+
+	return &fanOutExecContext{
+		ExecutionContext: dCtx.execContext,
+		budget:           uint32(running + headroom),
+	}, nil
+}
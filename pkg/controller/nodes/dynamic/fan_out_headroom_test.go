@@ -0,0 +1,223 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	flyteMocks "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	executorMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	nodeMocks "github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func pod(name string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+}
+
+func podQuota(hard, used int64) *v1.ResourceQuota {
+	return &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "pods", Namespace: "ns"},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{v1.ResourcePods: *resource.NewQuantity(hard, resource.DecimalSI)},
+			Used: v1.ResourceList{v1.ResourcePods: *resource.NewQuantity(used, resource.DecimalSI)},
+		},
+	}
+}
+
+func newFanOutHandler(maxPendingPods int, objects ...*v1.Pod) (dynamicNodeTaskNodeHandler, *fake.ClientBuilder) {
+	builder := fake.NewClientBuilder()
+	for _, o := range objects {
+		builder = builder.WithObjects(o)
+	}
+
+	return dynamicNodeTaskNodeHandler{
+		metrics: newMetrics(promutils.NewTestScope()),
+		fanOutHeadroom: config.FanOutHeadroomConfig{
+			Enabled:        true,
+			MinFanOut:      3,
+			MaxPendingPods: maxPendingPods,
+		},
+	}, builder
+}
+
+func withClient(d dynamicNodeTaskNodeHandler, builder *fake.ClientBuilder) dynamicNodeTaskNodeHandler {
+	kubeClient := &executorMocks.Client{}
+	kubeClient.OnGetClient().Return(builder.Build())
+	d.kubeClient = kubeClient
+	return d
+}
+
+func TestFanOutExecContext(t *testing.T) {
+	t.Run("budget", func(t *testing.T) {
+		parent := &executorMocks.ExecutionContext{}
+		parent.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{})
+		parent.OnCurrentParallelism().Return(0)
+		parent.OnIncrementParallelism().Return(1)
+
+		eCtx := &fanOutExecContext{ExecutionContext: parent, budget: 2}
+		assert.Equal(t, uint32(3), eCtx.GetExecutionConfig().MaxParallelism)
+		assert.Less(t, eCtx.CurrentParallelism(), eCtx.GetExecutionConfig().MaxParallelism)
+
+		eCtx.IncrementParallelism()
+		eCtx.IncrementParallelism()
+		assert.Equal(t, eCtx.GetExecutionConfig().MaxParallelism, eCtx.CurrentParallelism())
+		parent.AssertNumberOfCalls(t, "IncrementParallelism", 2)
+	})
+
+	t.Run("no-budget", func(t *testing.T) {
+		parent := &executorMocks.ExecutionContext{}
+		parent.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{})
+		parent.OnCurrentParallelism().Return(0)
+
+		eCtx := &fanOutExecContext{ExecutionContext: parent}
+		assert.Equal(t, uint32(1), eCtx.GetExecutionConfig().MaxParallelism)
+		assert.Equal(t, uint32(1), eCtx.CurrentParallelism())
+	})
+
+	t.Run("workflow-parallelism-reached", func(t *testing.T) {
+		parent := &executorMocks.ExecutionContext{}
+		parent.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{MaxParallelism: 4})
+		parent.OnCurrentParallelism().Return(4)
+
+		eCtx := &fanOutExecContext{ExecutionContext: parent, budget: 10}
+		assert.Equal(t, eCtx.GetExecutionConfig().MaxParallelism, eCtx.CurrentParallelism())
+	})
+}
+
+func TestNamespaceHeadroom(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("pending-pods", func(t *testing.T) {
+		d, builder := newFanOutHandler(5, pod("p1", v1.PodPending), pod("p2", v1.PodPending), pod("p3", v1.PodRunning))
+		d = withClient(d, builder)
+
+		headroom, reason, err := d.namespaceHeadroom(ctx, "ns")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, headroom)
+		assert.Equal(t, "namespace [ns] has [2/5] pending pods", reason)
+	})
+
+	t.Run("resource-quota", func(t *testing.T) {
+		d, builder := newFanOutHandler(5, pod("p1", v1.PodPending))
+		d = withClient(d, builder.WithObjects(podQuota(10, 9)))
+
+		headroom, reason, err := d.namespaceHeadroom(ctx, "ns")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, headroom)
+		assert.Equal(t, "resource quota [ns/pods] has [9/10] pods in use", reason)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		d, builder := newFanOutHandler(1, pod("p1", v1.PodPending), pod("p2", v1.PodPending))
+		d = withClient(d, builder)
+
+		headroom, _, err := d.namespaceHeadroom(ctx, "ns")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, headroom)
+	})
+}
+
+func TestApplyFanOutHeadroom(t *testing.T) {
+	ctx := context.TODO()
+
+	newDynamicWorkflowContext := func(phases ...v1alpha1.NodePhase) dynamicWorkflowContext {
+		w := &flyteMocks.ExecutableWorkflow{}
+		nodeIDs := make([]v1alpha1.NodeID, 0, len(phases))
+		nodes := make(map[v1alpha1.NodeID]v1alpha1.ExecutableNode, len(phases))
+		statuses := make(map[v1alpha1.NodeID]v1alpha1.ExecutableNodeStatus, len(phases))
+		for i, phase := range phases {
+			nodeID := fmt.Sprintf("n%d", i)
+			n := &v1alpha1.NodeSpec{ID: nodeID, Kind: v1alpha1.NodeKindTask}
+			nodeIDs = append(nodeIDs, nodeID)
+			nodes[nodeID] = n
+			statuses[nodeID] = &v1alpha1.NodeStatus{Phase: phase}
+			w.OnGetNode(nodeID).Return(n, true)
+		}
+		w.OnGetNodes().Return(nodeIDs)
+
+		parent := &executorMocks.ExecutionContext{}
+		parent.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{})
+		parent.OnCurrentParallelism().Return(0)
+
+		return dynamicWorkflowContext{
+			execContext: parent,
+			subWorkflow: w,
+			nodeLookup:  executors.NewTestNodeLookup(nodes, statuses),
+			isDynamic:   true,
+		}
+	}
+
+	newNodeExecutionContext := func(status *v1alpha1.NodeStatus) *nodeMocks.NodeExecutionContext {
+		metadata := &nodeMocks.NodeExecutionMetadata{}
+		metadata.OnGetNamespace().Return("ns")
+		nCtx := &nodeMocks.NodeExecutionContext{}
+		nCtx.OnNodeStatus().Return(status)
+		nCtx.OnNodeExecutionMetadata().Return(metadata)
+		return nCtx
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		d, _ := newFanOutHandler(0)
+		d.fanOutHeadroom.Enabled = false
+		dCtx := newDynamicWorkflowContext(v1alpha1.NodePhaseNotYetStarted, v1alpha1.NodePhaseNotYetStarted, v1alpha1.NodePhaseNotYetStarted)
+
+		eCtx, err := d.applyFanOutHeadroom(ctx, newNodeExecutionContext(&v1alpha1.NodeStatus{}), dCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, dCtx.execContext, eCtx)
+	})
+
+	t.Run("small-fan-out", func(t *testing.T) {
+		d, builder := newFanOutHandler(0)
+		d = withClient(d, builder)
+		dCtx := newDynamicWorkflowContext(v1alpha1.NodePhaseNotYetStarted, v1alpha1.NodePhaseNotYetStarted)
+
+		eCtx, err := d.applyFanOutHeadroom(ctx, newNodeExecutionContext(&v1alpha1.NodeStatus{}), dCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, dCtx.execContext, eCtx)
+	})
+
+	t.Run("enough-headroom", func(t *testing.T) {
+		d, builder := newFanOutHandler(10, pod("p1", v1.PodPending))
+		d = withClient(d, builder)
+		dCtx := newDynamicWorkflowContext(v1alpha1.NodePhaseNotYetStarted, v1alpha1.NodePhaseQueued, v1alpha1.NodePhaseRunning)
+		status := &v1alpha1.NodeStatus{FanOutBackpressure: &v1alpha1.FanOutBackpressure{Reason: "earlier", Reported: true}}
+
+		eCtx, err := d.applyFanOutHeadroom(ctx, newNodeExecutionContext(status), dCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, dCtx.execContext, eCtx)
+		assert.Nil(t, status.GetFanOutBackpressure())
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		d, builder := newFanOutHandler(3, pod("p1", v1.PodPending), pod("p2", v1.PodPending))
+		d = withClient(d, builder)
+		dCtx := newDynamicWorkflowContext(v1alpha1.NodePhaseNotYetStarted, v1alpha1.NodePhaseQueued,
+			v1alpha1.NodePhaseNotYetStarted, v1alpha1.NodePhaseRunning, v1alpha1.NodePhaseSucceeded)
+		status := &v1alpha1.NodeStatus{}
+
+		eCtx, err := d.applyFanOutHeadroom(ctx, newNodeExecutionContext(status), dCtx)
+		assert.NoError(t, err)
+		if assert.IsType(t, &fanOutExecContext{}, eCtx) {
+			assert.Equal(t, uint32(2), eCtx.(*fanOutExecContext).budget)
+		}
+
+		if assert.NotNil(t, status.GetFanOutBackpressure()) {
+			assert.Equal(t, "launching at most [1] of [3] waiting sub-nodes, namespace [ns] has [2/3] pending pods",
+				status.GetFanOutBackpressure().Reason)
+			assert.False(t, status.GetFanOutBackpressure().Reported)
+		}
+	})
+}
@@ -8,12 +8,12 @@ import (
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
 	"github.com/flyteorg/flytepropeller/pkg/utils"
 
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task"
 
@@ -24,6 +24,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 //go:generate mockery -all -case=underscore
@@ -43,6 +44,7 @@ type metrics struct {
 	CacheHit               labeled.StopWatch
 	CacheError             labeled.Counter
 	CacheMiss              labeled.Counter
+	FanOutBackpressure     labeled.Counter
 }
 
 func newMetrics(scope promutils.Scope) metrics {
@@ -52,14 +54,17 @@ func newMetrics(scope promutils.Scope) metrics {
 		CacheHit:               labeled.NewStopWatch("dynamic_workflow_cache_hit", "A dynamic workflow was loaded from store.", time.Microsecond, scope),
 		CacheError:             labeled.NewCounter("cache_err", "A dynamic workflow failed to store or load from data store.", scope),
 		CacheMiss:              labeled.NewCounter("cache_miss", "A dynamic workflow did not already exist in the data store.", scope),
+		FanOutBackpressure:     labeled.NewCounter("fan_out_backpressure", "Rounds in which a dynamic node launched fewer sub-nodes than were ready, because the namespace lacked headroom.", scope),
 	}
 }
 
 type dynamicNodeTaskNodeHandler struct {
 	TaskNodeHandler
-	metrics      metrics
-	nodeExecutor executors.Node
-	lpReader     launchplan.Reader
+	metrics        metrics
+	nodeExecutor   executors.Node
+	lpReader       launchplan.Reader
+	fanOutHeadroom config.FanOutHeadroomConfig
+	kubeClient     executors.Client
 }
 
 func (d dynamicNodeTaskNodeHandler) handleParentNode(ctx context.Context, prevState handler.DynamicNodeState, nCtx handler.NodeExecutionContext) (handler.Transition, handler.DynamicNodeState, error) {
@@ -105,6 +110,9 @@ func (d dynamicNodeTaskNodeHandler) produceDynamicWorkflow(ctx context.Context,
 		}
 		return handler.Transition{}, handler.DynamicNodeState{}, err
 	}
+
+	d.prefetchCatalog(ctx, dCtx)
+
 	taskNodeInfoMetadata := &event.TaskNodeMetadata{}
 	if dCtx.subWorkflowClosure != nil && dCtx.subWorkflowClosure.Primary != nil && dCtx.subWorkflowClosure.Primary.Template != nil {
 		taskNodeInfoMetadata.DynamicWorkflow = &event.DynamicWorkflowNodeMetadata{
@@ -133,7 +141,12 @@ func (d dynamicNodeTaskNodeHandler) handleDynamicSubNodes(ctx context.Context, n
 		return handler.Transition{}, handler.DynamicNodeState{}, err
 	}
 
-	trns, newState, err := d.progressDynamicWorkflow(ctx, dCtx.execContext, dCtx.subWorkflow, dCtx.nodeLookup, nCtx, prevState)
+	execContext, err := d.applyFanOutHeadroom(ctx, nCtx, dCtx)
+	if err != nil {
+		return handler.UnknownTransition, prevState, err
+	}
+
+	trns, newState, err := d.progressDynamicWorkflow(ctx, execContext, dCtx.subWorkflow, dCtx.nodeLookup, nCtx, prevState)
 	if err != nil {
 		return handler.UnknownTransition, prevState, err
 	}
@@ -263,6 +276,13 @@ func (d dynamicNodeTaskNodeHandler) finalizeParentNode(ctx context.Context, nCtx
 	return nil
 }
 
+// ShedCache drops the caches of the underlying task node handler, if it has any.
+func (d dynamicNodeTaskNodeHandler) ShedCache(ctx context.Context) {
+	if shedder, ok := d.TaskNodeHandler.(executors.CacheShedder); ok {
+		shedder.ShedCache(ctx)
+	}
+}
+
 // This is a weird method. We should always finalize before we set the dynamic parent node phase as complete?
 func (d dynamicNodeTaskNodeHandler) Finalize(ctx context.Context, nCtx handler.NodeExecutionContext) error {
 	errs := make([]error, 0, 2)
@@ -297,12 +317,15 @@ func (d dynamicNodeTaskNodeHandler) Finalize(ctx context.Context, nCtx handler.N
 	return nil
 }
 
-func New(underlying TaskNodeHandler, nodeExecutor executors.Node, launchPlanReader launchplan.Reader, scope promutils.Scope) handler.Node {
+func New(underlying TaskNodeHandler, nodeExecutor executors.Node, launchPlanReader launchplan.Reader,
+	fanOutHeadroom config.FanOutHeadroomConfig, kubeClient executors.Client, scope promutils.Scope) handler.Node {
 
 	return &dynamicNodeTaskNodeHandler{
 		TaskNodeHandler: underlying,
 		metrics:         newMetrics(scope),
 		nodeExecutor:    nodeExecutor,
 		lpReader:        launchPlanReader,
+		fanOutHeadroom:  fanOutHeadroom,
+		kubeClient:      kubeClient,
 	}
 }
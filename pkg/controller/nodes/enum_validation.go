@@ -0,0 +1,69 @@
+package nodes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+)
+
+// Returns an error naming the offending value if the literal, or any literal nested in it, is not one of the values the
+// enum type allows.
+func validateEnumLiteral(varName string, literal *core.Literal, literalType *core.LiteralType) error {
+	if literal == nil || literalType == nil {
+		return nil
+	}
+
+	switch {
+	case literalType.GetEnumType() != nil:
+		v := literal.GetScalar().GetPrimitive().GetStringValue()
+		for _, allowed := range literalType.GetEnumType().GetValues() {
+			if allowed == v {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("input [%v] has value [%v], which is not one of the allowed values %v", varName, v,
+			literalType.GetEnumType().GetValues())
+	case literalType.GetCollectionType() != nil:
+		for _, item := range literal.GetCollection().GetLiterals() {
+			if err := validateEnumLiteral(varName, item, literalType.GetCollectionType()); err != nil {
+				return err
+			}
+		}
+	case literalType.GetMapValueType() != nil:
+		keys := make([]string, 0, len(literal.GetMap().GetLiterals()))
+		for k := range literal.GetMap().GetLiterals() {
+			keys = append(keys, k)
+		}
+
+		// Sorted so that the same input always reports the same offending value.
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := validateEnumLiteral(varName, literal.GetMap().GetLiterals()[k], literalType.GetMapValueType()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validates the resolved inputs of a node against the enum types declared by its interface. The compiler only validates
+// statically bound values, values produced by upstream nodes are only known once the inputs are resolved.
+func validateEnumInputs(expected *core.VariableMap, inputs *core.LiteralMap) error {
+	variables := expected.GetVariables()
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		if err := validateEnumLiteral(name, inputs.GetLiterals()[name], variables[name].GetType()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
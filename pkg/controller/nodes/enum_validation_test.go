@@ -0,0 +1,59 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEnumInputs(t *testing.T) {
+	enumType := &core.LiteralType{Type: &core.LiteralType_EnumType{EnumType: &core.EnumType{Values: []string{"red", "green"}}}}
+	expected := &core.VariableMap{Variables: map[string]*core.Variable{
+		"color":  {Type: enumType},
+		"colors": {Type: &core.LiteralType{Type: &core.LiteralType_CollectionType{CollectionType: enumType}}},
+		"byName": {Type: &core.LiteralType{Type: &core.LiteralType_MapValueType{MapValueType: enumType}}},
+		"x":      {Type: &core.LiteralType{Type: &core.LiteralType_Simple{Simple: core.SimpleType_INTEGER}}},
+	}}
+
+	t.Run("valid", func(t *testing.T) {
+		inputs := &core.LiteralMap{Literals: map[string]*core.Literal{
+			"color":  coreutils.MustMakeLiteral("red"),
+			"colors": coreutils.MustMakeLiteral([]interface{}{"red", "green"}),
+			"byName": coreutils.MustMakeLiteral(map[string]interface{}{"a": "green"}),
+			"x":      coreutils.MustMakeLiteral(1),
+		}}
+
+		assert.NoError(t, validateEnumInputs(expected, inputs))
+	})
+
+	t.Run("missing inputs", func(t *testing.T) {
+		assert.NoError(t, validateEnumInputs(expected, nil))
+		assert.NoError(t, validateEnumInputs(nil, &core.LiteralMap{}))
+	})
+
+	t.Run("invalid scalar", func(t *testing.T) {
+		err := validateEnumInputs(expected, &core.LiteralMap{Literals: map[string]*core.Literal{
+			"color": coreutils.MustMakeLiteral("blue"),
+		}})
+
+		assert.EqualError(t, err, "input [color] has value [blue], which is not one of the allowed values [red green]")
+	})
+
+	t.Run("invalid collection item", func(t *testing.T) {
+		err := validateEnumInputs(expected, &core.LiteralMap{Literals: map[string]*core.Literal{
+			"colors": coreutils.MustMakeLiteral([]interface{}{"red", "yellow"}),
+		}})
+
+		assert.EqualError(t, err, "input [colors] has value [yellow], which is not one of the allowed values [red green]")
+	})
+
+	t.Run("invalid map value", func(t *testing.T) {
+		err := validateEnumInputs(expected, &core.LiteralMap{Literals: map[string]*core.Literal{
+			"byName": coreutils.MustMakeLiteral(map[string]interface{}{"a": "red", "b": ""}),
+		}})
+
+		assert.EqualError(t, err, "input [byName] has value [], which is not one of the allowed values [red green]")
+	})
+}
@@ -25,4 +25,5 @@ const (
 	StorageError                       ErrorCode = "StorageError"
 	EventRecordingFailed               ErrorCode = "EventRecordingFailed"
 	CatalogCallFailed                  ErrorCode = "CatalogCallFailed"
+	AbortInProgress                    ErrorCode = "AbortInProgress"
 )
@@ -5,8 +5,15 @@ import (
 	"strings"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	controllerErrors "github.com/flyteorg/flytepropeller/pkg/controller/errors"
 )
 
+// Node errors with these codes are not expected to go away when the round is retried, they convert to controller errors
+// with the mapped codes, marked as not retryable.
+var nonRetryableCodes = map[ErrorCode]controllerErrors.ErrorCode{
+	BadSpecificationError: controllerErrors.InvalidArgument,
+}
+
 type ErrorMessage = string
 
 type NodeError struct {
@@ -23,6 +30,23 @@ func (n *NodeError) Error() string {
 	return fmt.Sprintf("failed at Node[%s]. %v: %v", n.Node, n.ErrCode, n.Message)
 }
 
+// As converts the error to a controller error if it is not expected to go away when the round is retried, see
+// errors.As.
+func (n *NodeError) As(target interface{}) bool {
+	controllerErr, ok := target.(**controllerErrors.Error)
+	if !ok {
+		return false
+	}
+
+	code, nonRetryable := nonRetryableCodes[n.ErrCode]
+	if !nonRetryable {
+		return false
+	}
+
+	*controllerErr = controllerErrors.Errorf(code, "%v", n.Error()).NonRetryable()
+	return true
+}
+
 type NodeErrorWithCause struct {
 	NodeError error
 	cause     error
@@ -54,6 +78,18 @@ func (n *NodeErrorWithCause) Cause() error {
 	return n.cause
 }
 
+// As converts the error to a controller error if its node error is not expected to go away when the round is retried,
+// see errors.As.
+func (n *NodeErrorWithCause) As(target interface{}) bool {
+	nodeErr, ok := n.NodeError.(*NodeError)
+	return ok && nodeErr.As(target)
+}
+
+// Unwrap returns the cause, so that errors.Is and errors.As look through node errors.
+func (n *NodeErrorWithCause) Unwrap() error {
+	return n.cause
+}
+
 func errorf(c ErrorCode, n v1alpha1.NodeID, msgFmt string, args ...interface{}) error {
 	return &NodeError{
 		ErrCode: c,
@@ -113,3 +149,36 @@ func (e ErrorCollection) Error() string {
 
 	return sb.String()
 }
+
+// IsAbortInProgress returns true if the error only signals aborts that have not completed yet, e.g. resources that are
+// still terminating, rather than aborts that failed. Collections qualify if all their errors do.
+func IsAbortInProgress(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case ErrorCollection:
+			for _, c := range e.Errors {
+				if !IsAbortInProgress(c) {
+					return false
+				}
+			}
+
+			return len(e.Errors) > 0
+		case *ErrorCollection:
+			return e != nil && IsAbortInProgress(*e)
+		}
+
+		if Matches(err, AbortInProgress) {
+			return true
+		}
+
+		if wrapper, ok := err.(interface{ Unwrap() error }); ok {
+			err = wrapper.Unwrap()
+		} else if causer, ok := err.(interface{ Cause() error }); ok {
+			err = causer.Cause()
+		} else {
+			return false
+		}
+	}
+
+	return false
+}
@@ -1,11 +1,14 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	extErrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+
+	controllerErrors "github.com/flyteorg/flytepropeller/pkg/controller/errors"
 )
 
 func TestErrorf(t *testing.T) {
@@ -31,6 +34,7 @@ func TestErrorfWithCause(t *testing.T) {
 	assert.Equal(t, "n1", nodeErr.Node)
 	assert.Equal(t, fmt.Sprintf("Message [%v]", msg), nodeErr.Message)
 	assert.Equal(t, cause, extErrors.Cause(e))
+	assert.Equal(t, cause, errors.Unwrap(e))
 	assert.Equal(t, "failed at Node[n1]. IllegalStateError: Message [msg], caused by: Some Error", err.Error())
 }
 
@@ -47,3 +51,32 @@ func TestMatches(t *testing.T) {
 	assert.False(t, Matches(cause, IllegalStateError))
 	assert.False(t, Matches(cause, BadSpecificationError))
 }
+
+func TestIsAbortInProgress(t *testing.T) {
+	inProgress := Errorf(AbortInProgress, "n1", "terminating")
+	failed := Errorf(IllegalStateError, "n2", "failed")
+
+	assert.True(t, IsAbortInProgress(inProgress))
+	assert.True(t, IsAbortInProgress(Wrapf(CausedByError, "n0", inProgress, "aborting subworkflow")))
+	assert.True(t, IsAbortInProgress(fmt.Errorf("wrapped: %w", ErrorCollection{Errors: []error{inProgress, inProgress}})))
+	assert.False(t, IsAbortInProgress(ErrorCollection{Errors: []error{inProgress, failed}}))
+	assert.False(t, IsAbortInProgress(ErrorCollection{}))
+	assert.False(t, IsAbortInProgress(failed))
+	assert.False(t, IsAbortInProgress(nil))
+}
+
+func TestAsControllerError(t *testing.T) {
+	badSpec := Errorf(BadSpecificationError, "n1", "Upstream node [n0] not defined")
+	controllerErr, ok := controllerErrors.MarkedNonRetryable(Wrapf(CausedByError, "n2", badSpec, "failed subworkflow"))
+	assert.True(t, ok)
+	assert.Equal(t, controllerErrors.InvalidArgument, controllerErr.Code())
+	assert.Equal(t, controllerErrors.InvalidArgument, controllerErrors.GetCode(badSpec))
+
+	var target *controllerErrors.Error
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", Wrapf(BadSpecificationError, "n1", fmt.Errorf("cause"), "bad")), &target))
+	assert.False(t, target.Retryable())
+
+	_, ok = controllerErrors.MarkedNonRetryable(Errorf(IllegalStateError, "n1", "failed"))
+	assert.False(t, ok)
+	assert.False(t, errors.As(Errorf(IllegalStateError, "n1", "failed"), &target))
+}
@@ -18,7 +18,9 @@ package nodes
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/recovery"
@@ -35,7 +37,7 @@ import (
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
 	"github.com/flyteorg/flytestdlib/contextutils"
-	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/pbhash"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
 	"github.com/flyteorg/flytestdlib/storage"
@@ -50,9 +52,12 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/branch"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
 )
 
 type nodeMetrics struct {
@@ -68,6 +73,9 @@ type nodeMetrics struct {
 	PermanentUnknownErrorDuration labeled.StopWatch
 	ResolutionFailure             labeled.Counter
 	InputsWriteFailure            labeled.Counter
+	InputsWriteSkipped            labeled.Counter
+	ForcedNodes                   labeled.Counter
+	OperatorSkippedNodes          labeled.Counter
 	TimedOutFailure               labeled.Counter
 
 	InterruptedThresholdHit      labeled.Counter
@@ -81,6 +89,17 @@ type nodeMetrics struct {
 	QueuingLatency         labeled.StopWatch
 	NodeExecutionTime      labeled.StopWatch
 	NodeInputGatherLatency labeled.StopWatch
+
+	// Resource usage (requested resources multiplied by runtime) of completed task node attempts
+	CPUUsage    labeled.Counter
+	MemoryUsage labeled.Counter
+	GPUUsage    labeled.Counter
+
+	MemoryEscalations labeled.Counter
+
+	// Counts the durations that would have been negative, because the clocks of propeller replicas are skewed, and were
+	// observed as zero instead.
+	NegativeDurations labeled.Counter
 }
 
 // Implements the executors.Node interface
@@ -100,6 +119,12 @@ type nodeExecutor struct {
 	defaultDataSandbox              storage.DataReference
 	shardSelector                   ioutils.ShardSelector
 	recoveryClient                  recovery.Client
+	resourceUsageAccounting         bool
+	oomRetry                        config.OOMRetryConfig
+	outputInlining                  config.OutputInliningConfig
+	taskTemplates                   *TaskTemplateStore
+	zeroCopyInputs                  bool
+	maxParallelTerminations         int
 }
 
 func (c *nodeExecutor) RecordTransitionLatency(ctx context.Context, dag executors.DAGStructure, nl executors.NodeLookup, node v1alpha1.ExecutableNode, nodeStatus v1alpha1.ExecutableNodeStatus) {
@@ -111,10 +136,10 @@ func (c *nodeExecutor) RecordTransitionLatency(ctx context.Context, dag executor
 			return
 		}
 		if !t.IsZero() {
-			c.metrics.TransitionLatency.Observe(ctx, t.Time, time.Now())
+			utils.ObserveDuration(ctx, c.metrics.TransitionLatency, c.metrics.NegativeDurations, t.Time, time.Now())
 		}
 	} else if nodeStatus.GetPhase() == v1alpha1.NodePhaseRetryableFailure && nodeStatus.GetLastUpdatedAt() != nil {
-		c.metrics.TransitionLatency.Observe(ctx, nodeStatus.GetLastUpdatedAt().Time, time.Now())
+		utils.ObserveDuration(ctx, c.metrics.TransitionLatency, c.metrics.NegativeDurations, nodeStatus.GetLastUpdatedAt().Time, time.Now())
 	}
 }
 
@@ -277,10 +302,45 @@ func (c *nodeExecutor) preExecute(ctx context.Context, dag executors.DAGStructur
 		return handler.PhaseInfoUndefined, err
 	}
 
+	// The compiler never sets the policy, it is validated here for the FlyteWorkflows it is set on directly.
+	policy := nCtx.Node().GetSkippedUpstreamPolicy()
+	if err := v1alpha1.ValidateSkippedUpstreamPolicy(policy); err != nil {
+		return handler.PhaseInfoFailure(core.ExecutionError_USER, "InvalidSkippedUpstreamPolicy", err.Error(), nil), nil
+	}
+
+	if predicatePhase == PredicatePhaseSkip {
+		if policy != v1alpha1.SkippedUpstreamPolicySkip {
+			skippedOnly, err := upstreamOnlySkipped(ctx, dag, nCtx.ContextualNodeLookup(), nCtx.Node())
+			if err != nil {
+				return handler.PhaseInfoUndefined, err
+			}
+
+			if skippedOnly && policy == v1alpha1.SkippedUpstreamPolicyFail {
+				return handler.PhaseInfoFailure(core.ExecutionError_USER, "UpstreamNodeSkipped",
+					"Node failed as an upstream node was skipped", nil), nil
+			} else if skippedOnly && policy == v1alpha1.SkippedUpstreamPolicyUseDefaults {
+				logger.Infof(ctx, "Upstream node was skipped, executing Node with absent upstream outputs")
+				predicatePhase = PredicatePhaseReady
+			}
+		}
+	}
+
 	if predicatePhase == PredicatePhaseReady {
 		// TODO: Performance problem, we maybe in a retry loop and do not need to resolve the inputs again.
 		// For now we will do this
 		node := nCtx.Node()
+		if delay := node.GetStartDelay(); delay != nil && !node.IsStartNode() {
+			remaining, err := startDelayRemaining(nCtx, delay, time.Now())
+			if err != nil {
+				return handler.PhaseInfoFailure(core.ExecutionError_USER, "InvalidStartDelay", err.Error(), nil), nil
+			}
+
+			if remaining > 0 {
+				logger.Debugf(ctx, "Node is ready, delaying its start by [%v]", remaining)
+				return handler.PhaseInfoNotReady(fmt.Sprintf("start delayed by [%v]", remaining)), nil
+			}
+		}
+
 		var nodeInputs *core.LiteralMap
 		if !node.IsStartNode() {
 			if nCtx.ExecutionContext().GetExecutionConfig().RecoveryExecution.WorkflowExecutionIdentifier != nil {
@@ -295,7 +355,8 @@ func (c *nodeExecutor) preExecute(ctx context.Context, dag executors.DAGStructur
 			defer t.Stop()
 			// Can execute
 			var err error
-			nodeInputs, err = Resolve(ctx, c.outputResolver, nCtx.ContextualNodeLookup(), node.GetID(), node.GetInputBindings())
+			nodeInputs, err = Resolve(ctx, c.outputResolver, nCtx.ContextualNodeLookup(), nCtx.ExecutionContext(), node.GetID(),
+				node.GetInputBindings())
 			// TODO we need to handle retryable, network errors here!!
 			if err != nil {
 				c.metrics.ResolutionFailure.Inc(ctx)
@@ -303,9 +364,39 @@ func (c *nodeExecutor) preExecute(ctx context.Context, dag executors.DAGStructur
 				return handler.PhaseInfoFailure(core.ExecutionError_SYSTEM, "BindingResolutionFailure", err.Error(), nil), nil
 			}
 
-			if nodeInputs != nil {
+			if tr := nCtx.TaskReader(); tr != nil {
+				tk, err := tr.Read(ctx)
+				if err != nil {
+					return handler.PhaseInfoUndefined, err
+				}
+
+				if err := validateEnumInputs(tk.GetInterface().GetInputs(), nodeInputs); err != nil {
+					logger.Warningf(ctx, "Resolved inputs of Node are not valid. Error [%v]", err)
+					return handler.PhaseInfoFailure(core.ExecutionError_USER, "InvalidEnumValue", err.Error(), nil), nil
+				}
+			}
+
+			if when := node.GetWhen(); when != nil {
+				proceed, err := branch.EvaluateBooleanExpression(when, nodeInputs)
+				if err != nil {
+					logger.Warningf(ctx, "Failed to evaluate the when predicate of Node. Error [%v]", err)
+					return handler.PhaseInfoFailure(core.ExecutionError_USER, "WhenPredicateFailure", err.Error(), nil), nil
+				}
+
+				if !proceed {
+					logger.Infof(ctx, "When predicate of Node evaluated to false, skipping it")
+					nodeStatus.SetPredicateSkipped()
+					return handler.PhaseInfoSkip(nil, "Node skipped as its when predicate evaluated to false"), nil
+				}
+			}
+
+			if ref, ok := c.passThroughInputs(ctx, nCtx); ok && nodeInputs != nil {
+				logger.Debugf(ctx, "Passing through inputs of Node from [%s] without copying them.", ref)
+				nodeStatus.SetInputsRef(ref)
+				nodeStatus.SetInputsHash("")
+			} else if nodeInputs != nil {
 				inputsFile := v1alpha1.GetInputsFile(dataDir)
-				if err := c.store.WriteProtobuf(ctx, inputsFile, storage.Options{}, nodeInputs); err != nil {
+				if err := c.writeInputs(ctx, nodeStatus, inputsFile, nodeInputs); err != nil {
 					c.metrics.InputsWriteFailure.Inc(ctx)
 					logger.Errorf(ctx, "Failed to store inputs for Node. Error [%v]. InputsFile [%s]", err, inputsFile)
 					return handler.PhaseInfoUndefined, errors.Wrapf(
@@ -330,6 +421,47 @@ func (c *nodeExecutor) preExecute(ctx context.Context, dag executors.DAGStructur
 	return handler.PhaseInfoNotReady("predecessor node not yet complete"), nil
 }
 
+// Writes the inputs of the node to its inputs file, unless the node was started before with the same inputs and they are
+// still stored, so that all the attempts of the node read identical inputs and they are not written again. The inputs
+// are compared by the hash recorded in the node status when they were last written. Sub-nodes of a retried node start
+// with the inputs ref and hash of their previous attempt, whose inputs file they keep reading from if it is reused.
+func (c *nodeExecutor) writeInputs(ctx context.Context, nodeStatus v1alpha1.ExecutableNodeStatus,
+	inputsFile storage.DataReference, inputs *core.LiteralMap) error {
+
+	inputsHash := ""
+	if hash, err := pbhash.ComputeHash(ctx, inputs); err == nil {
+		inputsHash = base64.RawURLEncoding.EncodeToString(hash)
+	} else {
+		logger.Warnf(ctx, "Failed to hash inputs of Node, writing them. Error [%v]", err)
+	}
+
+	if len(inputsHash) > 0 && inputsHash == nodeStatus.GetInputsHash() {
+		stored := nodeStatus.GetInputsRef()
+		if len(stored) == 0 {
+			stored = inputsFile
+		}
+
+		metadata, err := c.store.Head(ctx, stored)
+		if err == nil && metadata.Exists() {
+			c.metrics.InputsWriteSkipped.Inc(ctx)
+			logger.Debugf(ctx, "Reusing unchanged inputs of Node. InputsFile [%s]", stored)
+			return nil
+		}
+	}
+
+	if err := c.store.WriteProtobuf(ctx, inputsFile, storage.Options{}, inputs); err != nil {
+		return err
+	}
+
+	nodeStatus.SetInputsHash(inputsHash)
+	// The node may have passed through its inputs or reused those of a previous attempt before it was reset.
+	if len(nodeStatus.GetInputsRef()) > 0 {
+		nodeStatus.SetInputsRef("")
+	}
+
+	return nil
+}
+
 func isTimeoutExpired(queuedAt *metav1.Time, timeout time.Duration) bool {
 	if !queuedAt.IsZero() && timeout != 0 {
 		deadline := queuedAt.Add(timeout)
@@ -360,7 +492,11 @@ func (c *nodeExecutor) execute(ctx context.Context, h handler.Node, nCtx *nodeEx
 	logger.Debugf(ctx, "Executing node")
 	defer logger.Debugf(ctx, "Node execution round complete")
 
-	t, err := h.Handle(ctx, nCtx)
+	t, delta, err := handler.AdaptLegacyNode(h).Handle(ctx, nCtx)
+	if applyErr := delta.Apply(nCtx.NodeStateWriter()); applyErr != nil && err == nil {
+		err = applyErr
+	}
+
 	if err != nil {
 		return handler.PhaseInfoUndefined, err
 	}
@@ -427,10 +563,14 @@ func (c *nodeExecutor) finalize(ctx context.Context, h handler.Node, nCtx handle
 func (c *nodeExecutor) handleNotYetStartedNode(ctx context.Context, dag executors.DAGStructure, nCtx *nodeExecContext, _ handler.Node) (executors.NodeStatus, error) {
 	logger.Debugf(ctx, "Node not yet started, running pre-execute")
 	defer logger.Debugf(ctx, "Node pre-execute completed")
-	p, err := c.preExecute(ctx, dag, nCtx)
-	if err != nil {
-		logger.Errorf(ctx, "failed preExecute for node. Error: %s", err.Error())
-		return executors.NodeStatusUndefined, err
+	p, skipped := c.skipNode(ctx, nCtx)
+	if !skipped {
+		var err error
+		p, err = c.preExecute(ctx, dag, nCtx)
+		if err != nil {
+			logger.Errorf(ctx, "failed preExecute for node. Error: %s", err.Error())
+			return executors.NodeStatusUndefined, err
+		}
 	}
 
 	if p.GetPhase() == handler.EPhaseUndefined {
@@ -489,12 +629,20 @@ func (c *nodeExecutor) handleQueuedOrRunningNode(ctx context.Context, nCtx *node
 	// across execute which is used to emit metrics
 	lastAttemptStartTime := nodeStatus.GetLastAttemptStartedAt()
 
-	p, err := c.execute(ctx, h, nCtx, nodeStatus)
+	p, forced, err := c.forceNode(ctx, h, nCtx)
 	if err != nil {
-		logger.Errorf(ctx, "failed Execute for node. Error: %s", err.Error())
+		logger.Errorf(ctx, "failed to force node. Error: %s", err.Error())
 		return executors.NodeStatusUndefined, err
 	}
 
+	if !forced {
+		p, err = c.execute(ctx, h, nCtx, nodeStatus)
+		if err != nil {
+			logger.Errorf(ctx, "failed Execute for node. Error: %s", err.Error())
+			return executors.NodeStatusUndefined, err
+		}
+	}
+
 	if p.GetPhase() == handler.EPhaseUndefined {
 		return executors.NodeStatusUndefined, errors.Errorf(errors.IllegalStateError, nCtx.NodeID(), "received undefined phase.")
 	}
@@ -517,25 +665,38 @@ func (c *nodeExecutor) handleQueuedOrRunningNode(ctx context.Context, nCtx *node
 
 		if execErr.GetKind() == core.ExecutionError_SYSTEM {
 			nodeStatus.IncrementSystemFailures()
-			c.metrics.SystemErrorDuration.Observe(ctx, startTime, endTime)
+			utils.ObserveDuration(ctx, c.metrics.SystemErrorDuration, c.metrics.NegativeDurations, startTime, endTime)
 		} else if execErr.GetKind() == core.ExecutionError_USER {
-			c.metrics.UserErrorDuration.Observe(ctx, startTime, endTime)
+			utils.ObserveDuration(ctx, c.metrics.UserErrorDuration, c.metrics.NegativeDurations, startTime, endTime)
 		} else {
-			c.metrics.UnknownErrorDuration.Observe(ctx, startTime, endTime)
+			utils.ObserveDuration(ctx, c.metrics.UnknownErrorDuration, c.metrics.NegativeDurations, startTime, endTime)
 		}
 		// When a node fails, we fail the workflow. Independent of number of nodes succeeding/failing, whenever a first node fails,
 		// the entire workflow is failed.
 		if np == v1alpha1.NodePhaseFailing {
 			if execErr.GetKind() == core.ExecutionError_SYSTEM {
 				nodeStatus.IncrementSystemFailures()
-				c.metrics.PermanentSystemErrorDuration.Observe(ctx, startTime, endTime)
+				utils.ObserveDuration(ctx, c.metrics.PermanentSystemErrorDuration, c.metrics.NegativeDurations, startTime, endTime)
 			} else if execErr.GetKind() == core.ExecutionError_USER {
-				c.metrics.PermanentUserErrorDuration.Observe(ctx, startTime, endTime)
+				utils.ObserveDuration(ctx, c.metrics.PermanentUserErrorDuration, c.metrics.NegativeDurations, startTime, endTime)
 			} else {
-				c.metrics.PermanentUnknownErrorDuration.Observe(ctx, startTime, endTime)
+				utils.ObserveDuration(ctx, c.metrics.PermanentUnknownErrorDuration, c.metrics.NegativeDurations, startTime, endTime)
 			}
 		}
 	}
+	if lastAttemptStartTime != nil && (p.GetPhase() == handler.EPhaseSuccess || p.GetPhase() == handler.EPhaseFailed ||
+		p.GetPhase() == handler.EPhaseRetryableFailure || p.GetPhase() == handler.EPhaseTimedout) {
+		c.recordAttemptResourceUsage(ctx, nCtx, nodeStatus, lastAttemptStartTime.Time, time.Now())
+	}
+
+	if p.GetPhase() == handler.EPhaseRetryableFailure {
+		c.escalateResourcesOnOOM(ctx, nCtx, nodeStatus, execErr)
+	}
+
+	if p.GetPhase() == handler.EPhaseSuccess {
+		c.inlineOutputs(ctx, nCtx, nodeStatus, p.GetInfo())
+	}
+
 	finalStatus := executors.NodeStatusRunning
 	if np == v1alpha1.NodePhaseFailing && !h.FinalizeRequired() {
 		logger.Infof(ctx, "Finalize not required, moving node to Failed")
@@ -580,7 +741,7 @@ func (c *nodeExecutor) handleQueuedOrRunningNode(ctx context.Context, nCtx *node
 		// We reach here only when transitioning from Queued to Running. In this case, the startedAt is not set.
 		if np == v1alpha1.NodePhaseRunning {
 			if nodeStatus.GetQueuedAt() != nil {
-				c.metrics.QueuingLatency.Observe(ctx, nodeStatus.GetQueuedAt().Time, time.Now())
+				utils.ObserveDuration(ctx, c.metrics.QueuingLatency, c.metrics.NegativeDurations, nodeStatus.GetQueuedAt().Time, time.Now())
 			}
 		}
 	}
@@ -592,16 +753,24 @@ func (c *nodeExecutor) handleQueuedOrRunningNode(ctx context.Context, nCtx *node
 func (c *nodeExecutor) handleRetryableFailure(ctx context.Context, nCtx *nodeExecContext, h handler.Node) (executors.NodeStatus, error) {
 	nodeStatus := nCtx.NodeStatus()
 	logger.Debugf(ctx, "node failed with retryable failure, aborting and finalizing, message: %s", nodeStatus.GetMessage())
-	if err := c.abort(ctx, h, nCtx, nodeStatus.GetMessage()); err != nil {
+	abortCtx := handler.WithAbortCause(ctx, handler.AbortCause{Kind: handler.AbortCauseRetry, Reason: nodeStatus.GetMessage()})
+	if err := c.abort(abortCtx, h, nCtx, nodeStatus.GetMessage()); err != nil {
 		return executors.NodeStatusUndefined, err
 	}
 
 	// NOTE: It is important to increment attempts only after abort has been called. Increment attempt mutates the state
 	// Attempt is used throughout the system to determine the idempotent resource version.
-	nodeStatus.IncrementAttempts()
+	attempts := nodeStatus.IncrementAttempts()
 	nodeStatus.UpdatePhase(v1alpha1.NodePhaseRunning, v1.Now(), "retrying", nil)
+	// The output dir of sub-nodes is only derived once, move this node to the output dir of the new attempt so that its
+	// sub-nodes, e.g. the children of a dynamic node, never write to or reuse the outputs of a previous attempt.
+	outputDir, err := nCtx.DataStore().ConstructReference(ctx, nodeStatus.GetDataDir(), strconv.FormatUint(uint64(attempts), 10))
+	if err != nil {
+		return executors.NodeStatusUndefined, err
+	}
+	nodeStatus.SetOutputDir(outputDir)
 	// We are going to retry in the next round, so we should clear all current state
-	nodeStatus.ClearSubNodeStatus()
+	nodeStatus.ClearSubNodeStatusForRetry()
 	nodeStatus.ClearTaskStatus()
 	nodeStatus.ClearWorkflowStatus()
 	nodeStatus.ClearDynamicNodeStatus()
@@ -627,7 +796,7 @@ func (c *nodeExecutor) handleNode(ctx context.Context, dag executors.DAGStructur
 			return executors.NodeStatusUndefined, err
 		}
 		nodeStatus.UpdatePhase(v1alpha1.NodePhaseFailed, v1.Now(), nodeStatus.GetMessage(), nodeStatus.GetExecutionError())
-		c.metrics.FailureDuration.Observe(ctx, nodeStatus.GetStartedAt().Time, nodeStatus.GetStoppedAt().Time)
+		utils.ObserveDuration(ctx, c.metrics.FailureDuration, c.metrics.NegativeDurations, nodeStatus.GetStartedAt().Time, nodeStatus.GetStoppedAt().Time)
 		if nCtx.md.IsInterruptible() {
 			c.metrics.InterruptibleNodesTerminated.Inc(ctx)
 		}
@@ -636,7 +805,8 @@ func (c *nodeExecutor) handleNode(ctx context.Context, dag executors.DAGStructur
 
 	if currentPhase == v1alpha1.NodePhaseTimingOut {
 		logger.Debugf(ctx, "node timing out")
-		if err := c.abort(ctx, h, nCtx, "node timed out"); err != nil {
+		abortCtx := handler.WithAbortCause(ctx, handler.AbortCause{Kind: handler.AbortCauseTimeout, Reason: nodeStatus.GetMessage()})
+		if err := c.abort(abortCtx, h, nCtx, "node timed out"); err != nil {
 			return executors.NodeStatusUndefined, err
 		}
 
@@ -657,7 +827,7 @@ func (c *nodeExecutor) handleNode(ctx context.Context, dag executors.DAGStructur
 
 		nodeStatus.ClearSubNodeStatus()
 		nodeStatus.UpdatePhase(v1alpha1.NodePhaseSucceeded, v1.Now(), "completed successfully", nil)
-		c.metrics.SuccessDuration.Observe(ctx, nodeStatus.GetStartedAt().Time, nodeStatus.GetStoppedAt().Time)
+		utils.ObserveDuration(ctx, c.metrics.SuccessDuration, c.metrics.NegativeDurations, nodeStatus.GetStartedAt().Time, nodeStatus.GetStoppedAt().Time)
 		if nCtx.md.IsInterruptible() {
 			c.metrics.InterruptibleNodesTerminated.Inc(ctx)
 		}
@@ -799,6 +969,7 @@ func (c *nodeExecutor) RecursiveNodeHandler(ctx context.Context, execContext exe
 	nodePhase := nodeStatus.GetPhase()
 
 	if canHandleNode(nodePhase) {
+		currentNodeCtx = logger.WithAttempt(currentNodeCtx, nodeStatus.GetAttempts())
 		// TODO Follow up Pull Request,
 		// 1. Rename this method to DAGTraversalHandleNode (accepts a DAGStructure along-with) the remaining arguments
 		// 2. Create a new method called HandleNode (part of the interface) (remaining all args as the previous method, but no DAGStructure
@@ -897,52 +1068,40 @@ func (c *nodeExecutor) FinalizeHandler(ctx context.Context, execContext executor
 	}
 
 	if canHandleNode(nodePhase) {
-		ctx = contextutils.WithNodeID(ctx, currentNode.GetID())
+		return c.finalizeNode(ctx, execContext, nl, currentNode)
+	}
 
-		// Now depending on the node type decide
-		h, err := c.nodeHandlerFactory.GetHandler(currentNode.GetKind())
-		if err != nil {
-			return err
-		}
+	// Finalize downstream nodes
+	nodes, errs := collectNodesToTerminate(ctx, dag, nl, currentNode, func(v1alpha1.NodePhase) bool {
+		return true
+	})
 
-		nCtx, err := c.newNodeExecContextDefault(ctx, currentNode.GetID(), execContext, nl)
-		if err != nil {
-			return err
-		}
-		// Abort this node
-		err = c.finalize(ctx, h, nCtx)
-		if err != nil {
-			return err
-		}
-	} else {
-		// Abort downstream nodes
-		downstreamNodes, err := dag.FromNode(currentNode.GetID())
-		if err != nil {
-			logger.Debugf(ctx, "Error when retrieving downstream nodes. Error [%v]", err)
-			return nil
-		}
+	errs = append(errs, c.terminateNodes(ctx, nodes, func(ctx context.Context, n v1alpha1.ExecutableNode) error {
+		return c.finalizeNode(ctx, execContext, nl, n)
+	})...)
 
-		errs := make([]error, 0, len(downstreamNodes))
-		for _, d := range downstreamNodes {
-			downstreamNode, ok := nl.GetNode(d)
-			if !ok {
-				return errors.Errorf(errors.BadSpecificationError, currentNode.GetID(), "Unable to find Downstream Node [%v]", d)
-			}
+	if len(errs) > 0 {
+		return errors.ErrorCollection{Errors: errs}
+	}
 
-			if err := c.FinalizeHandler(ctx, execContext, dag, nl, downstreamNode); err != nil {
-				logger.Infof(ctx, "Failed to abort node [%v]. Error: %v", d, err)
-				errs = append(errs, err)
-			}
-		}
+	return nil
+}
 
-		if len(errs) > 0 {
-			return errors.ErrorCollection{Errors: errs}
-		}
+func (c *nodeExecutor) finalizeNode(ctx context.Context, execContext executors.ExecutionContext, nl executors.NodeLookup, currentNode v1alpha1.ExecutableNode) error {
+	ctx = contextutils.WithNodeID(ctx, currentNode.GetID())
 
-		return nil
+	// Now depending on the node type decide
+	h, err := c.nodeHandlerFactory.GetHandler(currentNode.GetKind())
+	if err != nil {
+		return err
 	}
 
-	return nil
+	nCtx, err := c.newNodeExecContextDefault(ctx, currentNode.GetID(), execContext, nl)
+	if err != nil {
+		return err
+	}
+
+	return c.finalize(ctx, h, nCtx)
 }
 
 func (c *nodeExecutor) AbortHandler(ctx context.Context, execContext executors.ExecutionContext, dag executors.DAGStructure, nl executors.NodeLookup, currentNode v1alpha1.ExecutableNode, reason string) error {
@@ -956,88 +1115,93 @@ func (c *nodeExecutor) AbortHandler(ctx context.Context, execContext executors.E
 	}
 
 	if canHandleNode(nodePhase) {
+		return c.abortNode(ctx, execContext, nl, currentNode, reason)
+	}
+
+	if !isAbortWalkedThrough(nodePhase) {
 		ctx = contextutils.WithNodeID(ctx, currentNode.GetID())
+		logger.Warnf(ctx, "Trying to abort a node in state [%s]", nodeStatus.GetPhase().String())
+		return nil
+	}
 
-		// Now depending on the node type decide
-		h, err := c.nodeHandlerFactory.GetHandler(currentNode.GetKind())
-		if err != nil {
-			return err
-		}
+	// Abort downstream nodes
+	nodes, errs := collectNodesToTerminate(ctx, dag, nl, currentNode, isAbortWalkedThrough)
+	errs = append(errs, c.terminateNodes(ctx, nodes, func(ctx context.Context, n v1alpha1.ExecutableNode) error {
+		return c.abortNode(ctx, execContext, nl, n, reason)
+	})...)
 
-		nCtx, err := c.newNodeExecContextDefault(ctx, currentNode.GetID(), execContext, nl)
-		if err != nil {
-			return err
-		}
-		// Abort this node
-		err = c.abort(ctx, h, nCtx, reason)
-		if err != nil {
-			return err
-		}
-		nodeExecutionID := &core.NodeExecutionIdentifier{
-			ExecutionId: nCtx.NodeExecutionMetadata().GetNodeExecutionID().ExecutionId,
-			NodeId:      nCtx.NodeExecutionMetadata().GetNodeExecutionID().NodeId,
-		}
-		if nCtx.ExecutionContext().GetEventVersion() != v1alpha1.EventVersion0 {
-			currentNodeUniqueID, err := common.GenerateUniqueID(nCtx.ExecutionContext().GetParentInfo(), nodeExecutionID.NodeId)
-			if err != nil {
-				return err
-			}
-			nodeExecutionID.NodeId = currentNodeUniqueID
-		}
+	if len(errs) > 0 {
+		return errors.ErrorCollection{Errors: errs}
+	}
 
-		err = c.IdempotentRecordEvent(ctx, &event.NodeExecutionEvent{
-			Id:         nodeExecutionID,
-			Phase:      core.NodeExecution_ABORTED,
-			OccurredAt: ptypes.TimestampNow(),
-			OutputResult: &event.NodeExecutionEvent_Error{
-				Error: &core.ExecutionError{
-					Code:    "NodeAborted",
-					Message: reason,
-				},
-			},
-		})
-		if err != nil {
-			if errors2.IsCausedBy(err, errors.IllegalStateError) {
-				logger.Debugf(ctx, "Failed to record abort event due to illegal state transition. Ignoring the error. Error: %v", err)
-			} else {
-				logger.Warningf(ctx, "Failed to record nodeEvent, error [%s]", err.Error())
-				return errors.Wrapf(errors.EventRecordingFailed, nCtx.NodeID(), err, "failed to record node event")
-			}
-		}
-	} else if nodePhase == v1alpha1.NodePhaseSucceeded || nodePhase == v1alpha1.NodePhaseSkipped || nodePhase == v1alpha1.NodePhaseRecovered {
-		// Abort downstream nodes
-		downstreamNodes, err := dag.FromNode(currentNode.GetID())
-		if err != nil {
-			logger.Debugf(ctx, "Error when retrieving downstream nodes. Error [%v]", err)
-			return nil
-		}
+	return nil
+}
 
-		errs := make([]error, 0, len(downstreamNodes))
-		for _, d := range downstreamNodes {
-			downstreamNode, ok := nl.GetNode(d)
-			if !ok {
-				return errors.Errorf(errors.BadSpecificationError, currentNode.GetID(), "Unable to find Downstream Node [%v]", d)
-			}
+// Aborting walks through the nodes that completed to abort those downstream of them.
+func isAbortWalkedThrough(phase v1alpha1.NodePhase) bool {
+	return phase == v1alpha1.NodePhaseSucceeded || phase == v1alpha1.NodePhaseSkipped || phase == v1alpha1.NodePhaseRecovered
+}
 
-			if err := c.AbortHandler(ctx, execContext, dag, nl, downstreamNode, reason); err != nil {
-				logger.Infof(ctx, "Failed to abort node [%v]. Error: %v", d, err)
-				errs = append(errs, err)
-			}
-		}
+func (c *nodeExecutor) abortNode(ctx context.Context, execContext executors.ExecutionContext, nl executors.NodeLookup, currentNode v1alpha1.ExecutableNode, reason string) error {
+	ctx = contextutils.WithNodeID(ctx, currentNode.GetID())
 
-		if len(errs) > 0 {
-			return errors.ErrorCollection{Errors: errs}
+	// Now depending on the node type decide
+	h, err := c.nodeHandlerFactory.GetHandler(currentNode.GetKind())
+	if err != nil {
+		return err
+	}
+
+	nCtx, err := c.newNodeExecContextDefault(ctx, currentNode.GetID(), execContext, nl)
+	if err != nil {
+		return err
+	}
+	// Abort this node
+	err = c.abort(ctx, h, nCtx, reason)
+	if err != nil {
+		return err
+	}
+	nodeExecutionID := &core.NodeExecutionIdentifier{
+		ExecutionId: nCtx.NodeExecutionMetadata().GetNodeExecutionID().ExecutionId,
+		NodeId:      nCtx.NodeExecutionMetadata().GetNodeExecutionID().NodeId,
+	}
+	if nCtx.ExecutionContext().GetEventVersion() != v1alpha1.EventVersion0 {
+		currentNodeUniqueID, err := common.GenerateUniqueID(nCtx.ExecutionContext().GetParentInfo(), nodeExecutionID.NodeId)
+		if err != nil {
+			return err
 		}
+		nodeExecutionID.NodeId = currentNodeUniqueID
+	}
 
-		return nil
-	} else {
-		ctx = contextutils.WithNodeID(ctx, currentNode.GetID())
-		logger.Warnf(ctx, "Trying to abort a node in state [%s]", nodeStatus.GetPhase().String())
+	err = c.IdempotentRecordEvent(ctx, &event.NodeExecutionEvent{
+		Id:         nodeExecutionID,
+		Phase:      core.NodeExecution_ABORTED,
+		OccurredAt: ptypes.TimestampNow(),
+		OutputResult: &event.NodeExecutionEvent_Error{
+			Error: &core.ExecutionError{
+				Code:    "NodeAborted",
+				Message: reason,
+			},
+		},
+	})
+	if err != nil {
+		if errors2.IsCausedBy(err, errors.IllegalStateError) {
+			logger.Debugf(ctx, "Failed to record abort event due to illegal state transition. Ignoring the error. Error: %v", err)
+		} else {
+			logger.Warningf(ctx, "Failed to record nodeEvent, error [%s]", err.Error())
+			return errors.Wrapf(errors.EventRecordingFailed, nCtx.NodeID(), err, "failed to record node event")
+		}
 	}
 
 	return nil
 }
 
+// ShedCache drops the caches kept by the node handlers.
+func (c *nodeExecutor) ShedCache(ctx context.Context) {
+	if shedder, ok := c.nodeHandlerFactory.(executors.CacheShedder); ok {
+		shedder.ShedCache(ctx)
+	}
+}
+
 func (c *nodeExecutor) Initialize(ctx context.Context) error {
 	logger.Infof(ctx, "Initializing Core Node Executor")
 	s := c.newSetupContext(ctx)
@@ -1056,6 +1220,11 @@ func NewExecutor(ctx context.Context, nodeConfig config.NodeConfig, store *stora
 	}
 
 	nodeScope := scope.NewSubScope("node")
+	taskTemplates, err := NewTaskTemplateStore(store, nodeConfig.TaskTemplateCacheSize, nodeScope.NewSubScope("task_templates"))
+	if err != nil {
+		return nil, err
+	}
+
 	exec := &nodeExecutor{
 		store:               store,
 		enqueueWorkflow:     enQWorkflow,
@@ -1074,6 +1243,9 @@ func NewExecutor(ctx context.Context, nodeConfig config.NodeConfig, store *stora
 			PermanentSystemErrorDuration:  labeled.NewStopWatch("perma_system_error_duration", "Indicates the total execution time before non recoverable system error", time.Millisecond, nodeScope, labeled.EmitUnlabeledMetric),
 			PermanentUnknownErrorDuration: labeled.NewStopWatch("perma_unknown_error_duration", "Indicates the total execution time before non recoverable unknown error", time.Millisecond, nodeScope, labeled.EmitUnlabeledMetric),
 			InputsWriteFailure:            labeled.NewCounter("inputs_write_fail", "Indicates failure in writing node inputs to metastore", nodeScope),
+			InputsWriteSkipped:            labeled.NewCounter("inputs_write_skipped", "Node inputs not written again because the inputs file already holds them", nodeScope),
+			ForcedNodes:                   labeled.NewCounter("forced_nodes", "Nodes forced to fail or succeed by operators", nodeScope),
+			OperatorSkippedNodes:          labeled.NewCounter("operator_skipped_nodes", "Nodes skipped by operators before they started", nodeScope),
 			TimedOutFailure:               labeled.NewCounter("timeout_fail", "Indicates failure due to timeout", nodeScope),
 			InterruptedThresholdHit:       labeled.NewCounter("interrupted_threshold", "Indicates the node interruptible disabled because it hit max failure count", nodeScope),
 			InterruptibleNodesRunning:     labeled.NewCounter("interruptible_nodes_running", "number of interruptible nodes running", nodeScope),
@@ -1083,6 +1255,11 @@ func NewExecutor(ctx context.Context, nodeConfig config.NodeConfig, store *stora
 			QueuingLatency:                labeled.NewStopWatch("queueing_latency", "Measures the latency between the time a node's been queued to the time the handler reported the executable moved to running state", time.Millisecond, nodeScope, labeled.EmitUnlabeledMetric),
 			NodeExecutionTime:             labeled.NewStopWatch("node_exec_latency", "Measures the time taken to execute one node, a node can be complex so it may encompass sub-node latency.", time.Microsecond, nodeScope, labeled.EmitUnlabeledMetric),
 			NodeInputGatherLatency:        labeled.NewStopWatch("node_input_latency", "Measures the latency to aggregate inputs and check readiness of a node", time.Millisecond, nodeScope, labeled.EmitUnlabeledMetric),
+			CPUUsage:                      labeled.NewCounter("cpu_usage_millicore_seconds", "Requested CPU multiplied by runtime of completed task node attempts", nodeScope),
+			MemoryUsage:                   labeled.NewCounter("memory_usage_mib_seconds", "Requested memory multiplied by runtime of completed task node attempts", nodeScope),
+			GPUUsage:                      labeled.NewCounter("gpu_usage_seconds", "Requested GPUs multiplied by runtime of completed task node attempts", nodeScope),
+			MemoryEscalations:             labeled.NewCounter("oom_memory_escalations", "Number of times the memory of a task node was escalated after an OOMKilled attempt", nodeScope),
+			NegativeDurations:             labeled.NewCounter("negative_durations", "Number of durations observed as zero because their end was before their start", nodeScope),
 		},
 		outputResolver:                  NewRemoteFileOutputResolver(store),
 		defaultExecutionDeadline:        nodeConfig.DefaultDeadlines.DefaultNodeExecutionDeadline.Duration,
@@ -1092,8 +1269,15 @@ func NewExecutor(ctx context.Context, nodeConfig config.NodeConfig, store *stora
 		defaultDataSandbox:              defaultRawOutputPrefix,
 		shardSelector:                   shardSelector,
 		recoveryClient:                  recoveryClient,
+		resourceUsageAccounting:         nodeConfig.ResourceUsageAccounting,
+		oomRetry:                        nodeConfig.OOMRetry,
+		outputInlining:                  nodeConfig.OutputInlining,
+		taskTemplates:                   taskTemplates,
+		zeroCopyInputs:                  nodeConfig.ZeroCopyInputs,
+		maxParallelTerminations:         nodeConfig.MaxParallelTerminations,
 	}
-	nodeHandlerFactory, err := NewHandlerFactory(ctx, exec, workflowLauncher, launchPlanReader, kubeClient, catalogClient, recoveryClient, nodeScope)
+	nodeHandlerFactory, err := NewHandlerFactory(ctx, exec, workflowLauncher, launchPlanReader, kubeClient, catalogClient, recoveryClient,
+		nodeConfig.FanOutHeadroom, nodeScope)
 	exec.nodeHandlerFactory = nodeHandlerFactory
 	return exec, err
 }
@@ -569,6 +569,9 @@ func TestNodeExecutor_RecursiveNodeHandler_Recurse(t *testing.T) {
 			mockN2Status.OnGetDataDir().Return(storage.DataReference("blah"))
 			mockN2Status.On("SetOutputDir", mock.AnythingOfType(reflect.TypeOf(storage.DataReference("x")).String()))
 			mockN2Status.OnGetOutputDir().Return(storage.DataReference("blah"))
+			mockN2Status.OnGetInputsRef().Return(storage.DataReference(""))
+			mockN2Status.OnGetInputsHash().Return("")
+			mockN2Status.On("SetInputsHash", mock.Anything)
 			mockN2Status.OnGetWorkflowNodeStatus().Return(nil)
 
 			mockN2Status.OnGetStoppedAt().Return(nil)
@@ -592,7 +595,12 @@ func TestNodeExecutor_RecursiveNodeHandler_Recurse(t *testing.T) {
 			mockNode.OnIsEndNode().Return(false)
 			mockNode.OnGetTaskID().Return(&taskID)
 			mockNode.OnGetInputBindings().Return([]*v1alpha1.Binding{})
+			mockNode.OnGetWhen().Return(nil)
+			mockNode.OnGetSkippedUpstreamPolicy().Return(v1alpha1.SkippedUpstreamPolicySkip)
 			mockNode.OnIsInterruptible().Return(nil)
+			mockNode.OnGetLabels().Return(nil)
+			mockNode.OnGetAnnotations().Return(nil)
+			mockNode.OnGetStartDelay().Return(nil)
 			mockNode.OnGetName().Return("name")
 
 			mockNodeN0 := &mocks.ExecutableNode{}
@@ -603,10 +611,14 @@ func TestNodeExecutor_RecursiveNodeHandler_Recurse(t *testing.T) {
 			mockNodeN0.OnIsEndNode().Return(false)
 			mockNodeN0.OnGetTaskID().Return(&taskID0)
 			mockNodeN0.OnIsInterruptible().Return(nil)
+			mockNodeN0.OnGetLabels().Return(nil)
+			mockNodeN0.OnGetAnnotations().Return(nil)
+			mockNodeN0.OnGetStartDelay().Return(nil)
 			mockNodeN0.OnGetName().Return("name")
 
 			mockN0Status := &mocks.ExecutableNodeStatus{}
 			mockN0Status.OnGetPhase().Return(n0Phase)
+			mockN0Status.OnIsPredicateSkipped().Return(false)
 			mockN0Status.OnGetAttempts().Return(uint32(0))
 			mockN0Status.OnGetExecutionError().Return(nil)
 
@@ -630,6 +642,7 @@ func TestNodeExecutor_RecursiveNodeHandler_Recurse(t *testing.T) {
 			mockWf.OnFromNode(nodeN0).Return([]string{nodeN2}, nil)
 			mockWf.OnFromNode(nodeN2).Return([]string{}, fmt.Errorf("did not expect"))
 			mockWf.OnGetExecutionID().Return(v1alpha1.WorkflowExecutionIdentifier{})
+			mockWf.OnGetAnnotations().Return(nil)
 			mockWf.OnGetExecutionStatus().Return(mockWfStatus)
 			mockWf.OnGetTask(taskID0).Return(tk, nil)
 			mockWf.OnGetTask(taskID).Return(tk, nil)
@@ -915,6 +928,9 @@ func TestNodeExecutor_RecursiveNodeHandler_Recurse(t *testing.T) {
 					assert.Nil(t, s.Err)
 				}
 				assert.Equal(t, uint32(test.attempts), mockNodeStatus.GetAttempts())
+				if test.attempts > 0 {
+					assert.Equal(t, fmt.Sprintf("%v/%v", mockNodeStatus.GetDataDir(), test.attempts), mockNodeStatus.GetOutputDir().String())
+				}
 				assert.Equal(t, test.eventRecorded, called, "event recording expected: %v, but got %v", test.eventRecorded, called)
 			})
 		}
@@ -1273,6 +1289,7 @@ func TestNodeExecutor_RecursiveNodeHandler_BranchNode(t *testing.T) {
 				eCtx.OnIsInterruptible().Return(true)
 				eCtx.OnGetExecutionID().Return(v1alpha1.WorkflowExecutionIdentifier{WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{}})
 				eCtx.OnGetLabels().Return(nil)
+				eCtx.OnGetAnnotations().Return(nil)
 				eCtx.OnGetEventVersion().Return(v1alpha1.EventVersion0)
 				eCtx.OnGetParentInfo().Return(nil)
 				eCtx.OnGetRawOutputDataConfig().Return(v1alpha1.RawOutputDataConfig{
@@ -1287,14 +1304,23 @@ func TestNodeExecutor_RecursiveNodeHandler_BranchNode(t *testing.T) {
 				branchTakenNode.OnGetKind().Return(v1alpha1.NodeKindTask)
 				branchTakenNode.OnGetTaskID().Return(&tid)
 				branchTakenNode.OnIsInterruptible().Return(nil)
+				branchTakenNode.OnGetLabels().Return(nil)
+				branchTakenNode.OnGetAnnotations().Return(nil)
+				branchTakenNode.OnGetStartDelay().Return(nil)
+				branchTakenNode.OnGetSkippedUpstreamPolicy().Return(v1alpha1.SkippedUpstreamPolicySkip)
 				branchTakenNode.OnIsStartNode().Return(false)
 				branchTakenNode.OnIsEndNode().Return(false)
 				branchTakenNode.OnGetInputBindings().Return(nil)
+				branchTakenNode.OnGetWhen().Return(nil)
 				branchTakeNodeStatus := &mocks.ExecutableNodeStatus{}
 				branchTakeNodeStatus.OnGetPhase().Return(test.currentNodePhase)
 				branchTakeNodeStatus.OnIsDirty().Return(false)
 				branchTakeNodeStatus.OnGetSystemFailures().Return(1)
+				branchTakeNodeStatus.OnGetAttempts().Return(0)
 				branchTakeNodeStatus.OnGetDataDir().Return("data")
+				branchTakeNodeStatus.OnGetInputsRef().Return("")
+				branchTakeNodeStatus.OnGetInputsHash().Return("")
+				branchTakeNodeStatus.On("SetInputsHash", mock.Anything)
 				branchTakeNodeStatus.OnGetParentNodeID().Return(&parentBranchNodeID)
 				branchTakeNodeStatus.OnGetParentTaskID().Return(nil)
 				branchTakeNodeStatus.OnGetStartedAt().Return(&now)
@@ -1715,6 +1741,40 @@ func TestNodeExecutionEventV1(t *testing.T) {
 	assert.Equal(t, "2", eventOpt.RetryGroup)
 }
 
+func TestNodeExecutionEventV1_ParentTask(t *testing.T) {
+	execID := &core.WorkflowExecutionIdentifier{
+		Name:    "e1",
+		Domain:  "d1",
+		Project: "p1",
+	}
+	nID := &core.NodeExecutionIdentifier{
+		NodeId:      "n1",
+		ExecutionId: execID,
+	}
+	parentTaskID := &core.TaskExecutionIdentifier{
+		NodeExecutionId: &core.NodeExecutionIdentifier{
+			NodeId:      "np1",
+			ExecutionId: execID,
+		},
+		RetryAttempt: 2,
+	}
+	p := handler.PhaseInfoQueued("r")
+	parentInfo := executors.NewParentTaskInfo("np1", 2, parentTaskID)
+
+	n := &mocks.ExecutableNode{}
+	n.OnGetID().Return("id")
+	n.OnGetName().Return("name")
+	ns := &mocks.ExecutableNodeStatus{}
+	ns.OnGetPhase().Return(v1alpha1.NodePhaseNotYetStarted)
+	ns.OnGetParentTaskID().Return(nil)
+	ev, err := ToNodeExecutionEvent(nID, p, "reference", ns, v1alpha1.EventVersion1, parentInfo, n)
+	assert.NoError(t, err)
+	assert.Equal(t, "np1-2-n1", ev.Id.NodeId)
+	assert.Equal(t, "np1", ev.ParentNodeMetadata.NodeId)
+	assert.Equal(t, parentTaskID, ev.ParentTaskMetadata.Id)
+	assert.Equal(t, "2", ev.RetryGroup)
+}
+
 func TestNodeExecutor_RecursiveNodeHandler_ParallelismLimit(t *testing.T) {
 	ctx := context.Background()
 	enQWf := func(workflowID v1alpha1.WorkflowID) {
@@ -2239,3 +2299,17 @@ func TestRecover(t *testing.T) {
 		mockPBStore.AssertNumberOfCalls(t, "ReadProtobuf", 1)
 	})
 }
+
+func TestNodeExecutor_preExecute_InvalidSkippedUpstreamPolicy(t *testing.T) {
+	ctx := context.TODO()
+	dag := &mocks4.DAGStructure{}
+	dag.OnToNode("n1").Return(nil, nil)
+	c := &nodeExecutor{}
+
+	node := &v1alpha1.NodeSpec{ID: "n1", Kind: v1alpha1.NodeKindTask, SkippedUpstreamPolicy: "retry"}
+	p, err := c.preExecute(ctx, dag, &nodeExecContext{node: node, nodeStatus: &v1alpha1.NodeStatus{}})
+	assert.NoError(t, err)
+	assert.Equal(t, handler.EPhaseFailed, p.GetPhase())
+	assert.Equal(t, "InvalidSkippedUpstreamPolicy", p.GetErr().GetCode())
+	assert.Equal(t, core.ExecutionError_USER, p.GetErr().GetKind())
+}
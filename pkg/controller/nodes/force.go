@@ -0,0 +1,76 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/storage"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Error code of nodes forced to fail by an operator.
+const forcedFailureErrorCode = "ForcedFailure"
+
+// Aborts the node and returns the phase an operator forced it to, through the force nodes annotation of the workflow,
+// and whether it was forced. The outputs of nodes forced to succeed are copied to their output dir, so that downstream
+// nodes read them like any other outputs. Invalid requests are logged and ignored.
+func (c *nodeExecutor) forceNode(ctx context.Context, h handler.Node, nCtx *nodeExecContext) (handler.PhaseInfo, bool, error) {
+	requests, err := v1alpha1.GetNodeForceRequests(nCtx.ExecutionContext().GetAnnotations())
+	if err != nil {
+		logger.Warnf(ctx, "Ignoring requests to force nodes. Error: %v", err)
+		return handler.PhaseInfoUndefined, false, nil
+	}
+
+	request, found := requests[nCtx.NodeExecutionMetadata().GetNodeExecutionID().GetNodeId()]
+	if !found {
+		return handler.PhaseInfoUndefined, false, nil
+	}
+
+	if err := request.Validate(); err != nil {
+		logger.Warnf(ctx, "Ignoring invalid request to force node. Error: %v", err)
+		return handler.PhaseInfoUndefined, false, nil
+	}
+
+	reason := fmt.Sprintf("forced to %v by [%v]: %v", request.Phase, request.RequestedBy, request.Reason)
+	logger.Infof(ctx, "Node %v", reason)
+	abortCtx := handler.WithAbortCause(ctx, handler.AbortCause{Kind: handler.AbortCauseForced, Reason: reason})
+	if err := c.abort(abortCtx, h, nCtx, reason); err != nil {
+		return handler.PhaseInfoUndefined, false, err
+	}
+
+	var phaseInfo handler.PhaseInfo
+	if request.Phase == v1alpha1.ForcedNodePhaseFailed {
+		phaseInfo = handler.PhaseInfoFailure(core.ExecutionError_USER, forcedFailureErrorCode, reason, nil)
+	} else {
+		info := &handler.ExecutionInfo{}
+		if len(request.OutputsURI) > 0 {
+			outputsFile := v1alpha1.GetOutputsFile(nCtx.NodeStatus().GetOutputDir())
+			if err := copyOutputs(ctx, nCtx.DataStore(), request.OutputsURI, outputsFile); err != nil {
+				return handler.PhaseInfoUndefined, false, errors.Wrapf(errors.StorageError, nCtx.NodeID(), err,
+					"failed to copy outputs of forced node from [%v]", request.OutputsURI)
+			}
+
+			info.OutputInfo = &handler.OutputInfo{OutputURI: outputsFile}
+		}
+
+		phaseInfo = handler.PhaseInfoSuccess(info)
+	}
+
+	nCtx.NodeStatus().SetForced(&v1alpha1.NodeForce{NodeForceRequest: request})
+	c.metrics.ForcedNodes.Inc(ctx)
+	return phaseInfo, true, nil
+}
+
+func copyOutputs(ctx context.Context, store *storage.DataStore, from, to storage.DataReference) error {
+	outputs := &core.LiteralMap{}
+	if err := store.ReadProtobuf(ctx, from, outputs); err != nil {
+		return err
+	}
+
+	return store.WriteProtobuf(ctx, to, storage.Options{}, outputs)
+}
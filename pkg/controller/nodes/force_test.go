@@ -0,0 +1,105 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	executorMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestNodeExecutor_forceNode(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	c := &nodeExecutor{
+		store: store,
+		metrics: &nodeMetrics{
+			ForcedNodes: labeled.NewCounter("forced_nodes", "", promutils.NewTestScope()),
+		},
+	}
+
+	newContext := func(requests map[string]v1alpha1.NodeForceRequest) (*nodeExecContext, *mocks.Node) {
+		annotations := map[string]string{}
+		if requests != nil {
+			raw, err := json.Marshal(requests)
+			assert.NoError(t, err)
+			annotations[v1alpha1.ForceNodesAnnotation] = string(raw)
+		}
+
+		ec := &executorMocks.ExecutionContext{}
+		ec.OnGetAnnotations().Return(annotations)
+		md := &mocks.NodeExecutionMetadata{}
+		md.OnGetNodeExecutionID().Return(&core.NodeExecutionIdentifier{NodeId: "n1"})
+		h := &mocks.Node{}
+		h.OnAbortMatch(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		h.OnFinalizeMatch(mock.Anything, mock.Anything).Return(nil)
+		return &nodeExecContext{
+			store:      store,
+			ic:         ec,
+			md:         md,
+			nodeStatus: &v1alpha1.NodeStatus{Phase: v1alpha1.NodePhaseRunning, OutputDir: "s3://bucket/n1/0"},
+		}, h
+	}
+
+	t.Run("not-forced", func(t *testing.T) {
+		for _, requests := range []map[string]v1alpha1.NodeForceRequest{
+			nil,
+			{"n2": {Phase: v1alpha1.ForcedNodePhaseFailed, Reason: "stuck", RequestedBy: "op"}},
+			{"n1": {Phase: v1alpha1.ForcedNodePhaseFailed}},
+		} {
+			nCtx, h := newContext(requests)
+			_, forced, err := c.forceNode(ctx, h, nCtx)
+			assert.NoError(t, err)
+			assert.False(t, forced)
+			h.AssertNotCalled(t, "Abort", mock.Anything, mock.Anything, mock.Anything)
+			assert.Nil(t, nCtx.NodeStatus().GetForced())
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		request := v1alpha1.NodeForceRequest{Phase: v1alpha1.ForcedNodePhaseFailed, Reason: "stuck", RequestedBy: "op"}
+		nCtx, h := newContext(map[string]v1alpha1.NodeForceRequest{"n1": request})
+		p, forced, err := c.forceNode(ctx, h, nCtx)
+		assert.NoError(t, err)
+		assert.True(t, forced)
+		assert.Equal(t, handler.EPhaseFailed, p.GetPhase())
+		assert.Equal(t, forcedFailureErrorCode, p.GetErr().GetCode())
+		assert.Contains(t, p.GetErr().GetMessage(), "stuck")
+		h.AssertCalled(t, "Abort", mock.Anything, mock.Anything, mock.Anything)
+		if assert.NotNil(t, nCtx.NodeStatus().GetForced()) {
+			assert.Equal(t, request, nCtx.NodeStatus().GetForced().NodeForceRequest)
+			assert.False(t, nCtx.NodeStatus().GetForced().Reported)
+		}
+	})
+
+	t.Run("succeeded", func(t *testing.T) {
+		outputs := coreutils.MustMakeLiteral(map[string]interface{}{"x": 1}).GetMap()
+		outputsURI := storage.DataReference("s3://bucket/manual/outputs.pb")
+		assert.NoError(t, store.WriteProtobuf(ctx, outputsURI, storage.Options{}, outputs))
+
+		nCtx, h := newContext(map[string]v1alpha1.NodeForceRequest{"n1": {
+			Phase: v1alpha1.ForcedNodePhaseSucceeded, OutputsURI: outputsURI, Reason: "done manually", RequestedBy: "op"}})
+		p, forced, err := c.forceNode(ctx, h, nCtx)
+		assert.NoError(t, err)
+		assert.True(t, forced)
+		assert.Equal(t, handler.EPhaseSuccess, p.GetPhase())
+		outputsFile := v1alpha1.GetOutputsFile(nCtx.NodeStatus().GetOutputDir())
+		assert.Equal(t, outputsFile, p.GetInfo().OutputInfo.OutputURI)
+
+		copied := &core.LiteralMap{}
+		assert.NoError(t, store.ReadProtobuf(ctx, outputsFile, copied))
+		assert.Equal(t, int64(1), copied.GetLiterals()["x"].GetScalar().GetPrimitive().GetInteger())
+	})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import "context"
+
+// AbortCauseKind is why a node is aborted.
+type AbortCauseKind string
+
+const (
+	AbortCauseUnknown AbortCauseKind = "Unknown"
+	// The execution was aborted by a user, or its workflow was deleted.
+	AbortCauseUser AbortCauseKind = "UserAbort"
+	// The node exceeded its deadline.
+	AbortCauseTimeout AbortCauseKind = "Timeout"
+	// Another node failed the workflow, so the nodes that are still running are aborted.
+	AbortCauseWorkflowFailure AbortCauseKind = "WorkflowFailure"
+	// The current attempt of the node failed and the node will be retried.
+	AbortCauseRetry AbortCauseKind = "Retry"
+	// The workflow exhausted its retries for system failures.
+	AbortCauseSystemFailure AbortCauseKind = "SystemFailure"
+	// An operator forced the node to fail or succeed.
+	AbortCauseForced AbortCauseKind = "Forced"
+)
+
+// AbortCause describes why a node is aborted, so that handlers and plugins can clean up differently, e.g. keep the logs
+// of a task that timed out.
+type AbortCause struct {
+	Kind   AbortCauseKind
+	Reason string
+}
+
+type abortCauseContextKey struct{}
+
+// WithAbortCause returns a context that aborts nodes, and the nodes nested in them, with the given cause.
+func WithAbortCause(ctx context.Context, cause AbortCause) context.Context {
+	return context.WithValue(ctx, abortCauseContextKey{}, cause)
+}
+
+// AbortCauseFromContext returns the cause set by WithAbortCause, or an unknown cause with the given reason if none was
+// set.
+func AbortCauseFromContext(ctx context.Context, reason string) AbortCause {
+	if cause, ok := ctx.Value(abortCauseContextKey{}).(AbortCause); ok {
+		return cause
+	}
+
+	return AbortCause{Kind: AbortCauseUnknown, Reason: reason}
+}
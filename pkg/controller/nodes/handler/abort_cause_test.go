@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortCauseFromContext(t *testing.T) {
+	ctx := context.TODO()
+	assert.Equal(t, AbortCause{Kind: AbortCauseUnknown, Reason: "reason"}, AbortCauseFromContext(ctx, "reason"))
+
+	cause := AbortCause{Kind: AbortCauseTimeout, Reason: "node timed out"}
+	ctx = WithAbortCause(ctx, cause)
+	assert.Equal(t, cause, AbortCauseFromContext(ctx, "reason"))
+	type otherKey struct{}
+	assert.Equal(t, cause, AbortCauseFromContext(context.WithValue(ctx, otherKey{}, "value"), "reason"))
+}
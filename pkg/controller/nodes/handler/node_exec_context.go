@@ -41,7 +41,9 @@ type NodeExecutionMetadata interface {
 	IsInterruptible() bool
 }
 
-type NodeExecutionContext interface {
+// ImmutableNodeExecutionContext is the read-only view of a node execution that NodeV2 handlers receive. It exposes the
+// spec of the node and its current handler state, but no way to change the status of the node.
+type ImmutableNodeExecutionContext interface {
 	// This path is never read by propeller, but allows using some container or prefix in a specific container for all output from tasks
 	// Sandboxes provide exactly once execution semantics and only the successful sandbox wins. Ideally a sandbox should be a path that is
 	// available to the task at High Bandwidth (for example the base path of a sharded s3 bucket.
@@ -60,7 +62,6 @@ type NodeExecutionContext interface {
 	TaskReader() TaskReader
 
 	NodeStateReader() NodeStateReader
-	NodeStateWriter() NodeStateWriter
 
 	NodeExecutionMetadata() NodeExecutionMetadata
 	MaxDatasetSizeBytes() int64
@@ -69,6 +70,13 @@ type NodeExecutionContext interface {
 
 	ContextualNodeLookup() executors.NodeLookup
 	ExecutionContext() executors.ExecutionContext
+}
+
+type NodeExecutionContext interface {
+	ImmutableNodeExecutionContext
+
+	NodeStateWriter() NodeStateWriter
+
 	// TODO We should not need to pass NodeStatus, we probably only need it for DataDir, which should actually be sent using an OutputWriter interface
 	// Deprecated
 	NodeStatus() v1alpha1.ExecutableNodeStatus
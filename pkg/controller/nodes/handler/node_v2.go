@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+)
+
+// StateDelta holds the handler states a handler wants to store for its node. States that are nil are left unchanged.
+type StateDelta struct {
+	TaskNodeState     *TaskNodeState
+	BranchNodeState   *BranchNodeState
+	DynamicNodeState  *DynamicNodeState
+	WorkflowNodeState *WorkflowNodeState
+}
+
+// IsEmpty returns true if the delta does not change any state.
+func (d StateDelta) IsEmpty() bool {
+	return d.TaskNodeState == nil && d.BranchNodeState == nil && d.DynamicNodeState == nil && d.WorkflowNodeState == nil
+}
+
+// Apply writes every state that is set in the delta to the given writer.
+func (d StateDelta) Apply(w NodeStateWriter) error {
+	if d.TaskNodeState != nil {
+		if err := w.PutTaskNodeState(*d.TaskNodeState); err != nil {
+			return err
+		}
+	}
+
+	if d.BranchNodeState != nil {
+		if err := w.PutBranchNode(*d.BranchNodeState); err != nil {
+			return err
+		}
+	}
+
+	if d.DynamicNodeState != nil {
+		if err := w.PutDynamicNodeState(*d.DynamicNodeState); err != nil {
+			return err
+		}
+	}
+
+	if d.WorkflowNodeState != nil {
+		if err := w.PutWorkflowNodeState(*d.WorkflowNodeState); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stateDeltaWriter is a NodeStateWriter that records the states written to it in a delta.
+type stateDeltaWriter struct {
+	delta StateDelta
+}
+
+func (s *stateDeltaWriter) PutTaskNodeState(state TaskNodeState) error {
+	s.delta.TaskNodeState = &state
+	return nil
+}
+
+func (s *stateDeltaWriter) PutBranchNode(state BranchNodeState) error {
+	s.delta.BranchNodeState = &state
+	return nil
+}
+
+func (s *stateDeltaWriter) PutDynamicNodeState(state DynamicNodeState) error {
+	s.delta.DynamicNodeState = &state
+	return nil
+}
+
+func (s *stateDeltaWriter) PutWorkflowNodeState(state WorkflowNodeState) error {
+	s.delta.WorkflowNodeState = &state
+	return nil
+}
+
+// NodeV2 is the interface of node handlers that do not mutate the status of their node. Handlers receive a read-only
+// view of the node execution and return the changes to their state along with the transition, which the node executor
+// applies. This allows evaluating nodes without touching shared status objects and testing handlers by asserting on
+// their return values alone.
+type NodeV2 interface {
+	// Method to indicate that finalize is required for this handler
+	FinalizeRequired() bool
+
+	// Setup should be called, before invoking any other methods of this handler in a single thread context
+	Setup(ctx context.Context, setupContext SetupContext) error
+
+	// Core method that should handle this node. The returned delta is applied even if an error is returned.
+	Handle(ctx context.Context, executionContext ImmutableNodeExecutionContext) (Transition, StateDelta, error)
+
+	// This method should be invoked to indicate the node needs to be aborted.
+	Abort(ctx context.Context, executionContext ImmutableNodeExecutionContext, reason string) error
+
+	// This method is always called before completing the node, if FinalizeRequired returns true.
+	Finalize(ctx context.Context, executionContext ImmutableNodeExecutionContext) error
+}
+
+// deltaRecordingContext is a NodeExecutionContext whose state writes are recorded instead of applied.
+type deltaRecordingContext struct {
+	NodeExecutionContext
+	writer *stateDeltaWriter
+}
+
+func (d deltaRecordingContext) NodeStateWriter() NodeStateWriter {
+	return d.writer
+}
+
+// legacyNode adapts a Node to the NodeV2 interface.
+type legacyNode struct {
+	h Node
+}
+
+func (l legacyNode) FinalizeRequired() bool {
+	return l.h.FinalizeRequired()
+}
+
+func (l legacyNode) Setup(ctx context.Context, setupContext SetupContext) error {
+	return l.h.Setup(ctx, setupContext)
+}
+
+// toLegacyContext returns the full NodeExecutionContext backing a read-only view. Existing handlers still read the
+// deprecated NodeStatus, so they can only run with views that are backed by one.
+func toLegacyContext(executionContext ImmutableNodeExecutionContext) (NodeExecutionContext, error) {
+	nCtx, ok := executionContext.(NodeExecutionContext)
+	if !ok {
+		return nil, fmt.Errorf("node [%v] is handled by a legacy handler that requires a NodeExecutionContext, found [%T]",
+			executionContext.NodeID(), executionContext)
+	}
+
+	return nCtx, nil
+}
+
+func (l legacyNode) Handle(ctx context.Context, executionContext ImmutableNodeExecutionContext) (Transition, StateDelta, error) {
+	nCtx, err := toLegacyContext(executionContext)
+	if err != nil {
+		return UnknownTransition, StateDelta{}, err
+	}
+
+	writer := &stateDeltaWriter{}
+	t, err := l.h.Handle(ctx, deltaRecordingContext{NodeExecutionContext: nCtx, writer: writer})
+	return t, writer.delta, err
+}
+
+func (l legacyNode) Abort(ctx context.Context, executionContext ImmutableNodeExecutionContext, reason string) error {
+	nCtx, err := toLegacyContext(executionContext)
+	if err != nil {
+		return err
+	}
+
+	return l.h.Abort(ctx, nCtx, reason)
+}
+
+func (l legacyNode) Finalize(ctx context.Context, executionContext ImmutableNodeExecutionContext) error {
+	nCtx, err := toLegacyContext(executionContext)
+	if err != nil {
+		return err
+	}
+
+	return l.h.Finalize(ctx, nCtx)
+}
+
+// nodeV2 adapts a NodeV2 to the Node interface, so that it can be registered alongside existing handlers.
+type nodeV2 struct {
+	h NodeV2
+}
+
+func (n nodeV2) FinalizeRequired() bool {
+	return n.h.FinalizeRequired()
+}
+
+func (n nodeV2) Setup(ctx context.Context, setupContext SetupContext) error {
+	return n.h.Setup(ctx, setupContext)
+}
+
+func (n nodeV2) Handle(ctx context.Context, executionContext NodeExecutionContext) (Transition, error) {
+	t, delta, err := n.h.Handle(ctx, executionContext)
+	if applyErr := delta.Apply(executionContext.NodeStateWriter()); applyErr != nil && err == nil {
+		err = applyErr
+	}
+
+	return t, err
+}
+
+func (n nodeV2) Abort(ctx context.Context, executionContext NodeExecutionContext, reason string) error {
+	return n.h.Abort(ctx, executionContext, reason)
+}
+
+func (n nodeV2) Finalize(ctx context.Context, executionContext NodeExecutionContext) error {
+	return n.h.Finalize(ctx, executionContext)
+}
+
+// AdaptLegacyNode returns a NodeV2 that runs the given handler. The states the handler writes are returned as a delta
+// instead of being applied. Handlers that were adapted from a NodeV2 are unwrapped.
+func AdaptLegacyNode(h Node) NodeV2 {
+	if adapted, ok := h.(nodeV2); ok {
+		return adapted.h
+	}
+
+	return legacyNode{h: h}
+}
+
+// AdaptNodeV2 returns a Node that runs the given handler and applies the delta it returns. Handlers that were adapted
+// from a Node are unwrapped.
+func AdaptNodeV2(h NodeV2) Node {
+	if adapted, ok := h.(legacyNode); ok {
+		return adapted.h
+	}
+
+	return nodeV2{h: h}
+}
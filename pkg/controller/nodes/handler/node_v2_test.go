@@ -0,0 +1,127 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/testkit"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NodeIDKey)
+}
+
+// countingHandler is a NodeV2 handler that counts its rounds in the plugin phase version of its task state.
+type countingHandler struct{}
+
+func (countingHandler) FinalizeRequired() bool {
+	return false
+}
+
+func (countingHandler) Setup(context.Context, handler.SetupContext) error {
+	return nil
+}
+
+func (countingHandler) Handle(_ context.Context, nCtx handler.ImmutableNodeExecutionContext) (handler.Transition, handler.StateDelta, error) {
+	state := nCtx.NodeStateReader().GetTaskNodeState()
+	state.PluginPhase = pluginCore.PhaseRunning
+	state.PluginPhaseVersion++
+	return handler.DoTransition(handler.TransitionTypeEphemeral, handler.PhaseInfoRunning(nil)),
+		handler.StateDelta{TaskNodeState: &state}, nil
+}
+
+func (countingHandler) Abort(context.Context, handler.ImmutableNodeExecutionContext, string) error {
+	return nil
+}
+
+func (countingHandler) Finalize(context.Context, handler.ImmutableNodeExecutionContext) error {
+	return nil
+}
+
+func newNodeExecutionContext(t *testing.T) *testkit.NodeExecutionContext {
+	nCtx, err := testkit.NewNodeExecutionContextBuilder(&v1alpha1.NodeSpec{ID: "n1", Kind: v1alpha1.NodeKindTask}).
+		Build(context.TODO())
+	assert.NoError(t, err)
+	return nCtx
+}
+
+func TestStateDelta_Apply(t *testing.T) {
+	assert.True(t, handler.StateDelta{}.IsEmpty())
+
+	state := &testkit.NodeState{}
+	delta := handler.StateDelta{
+		BranchNodeState:   &handler.BranchNodeState{Phase: v1alpha1.BranchNodeSuccess},
+		WorkflowNodeState: &handler.WorkflowNodeState{Phase: v1alpha1.WorkflowNodePhaseExecuting},
+	}
+
+	assert.False(t, delta.IsEmpty())
+	assert.NoError(t, delta.Apply(state))
+	assert.Nil(t, state.Task)
+	assert.Nil(t, state.Dynamic)
+	assert.Equal(t, v1alpha1.BranchNodeSuccess, state.Branch.Phase)
+	assert.Equal(t, v1alpha1.WorkflowNodePhaseExecuting, state.Workflow.Phase)
+
+	writer := &mocks.NodeStateWriter{}
+	writer.OnPutBranchNodeMatch(mock.Anything).Return(fmt.Errorf("failed"))
+	assert.Error(t, delta.Apply(writer))
+}
+
+func TestAdaptLegacyNode(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("records state writes", func(t *testing.T) {
+		nCtx := newNodeExecutionContext(t)
+		legacy := &mocks.Node{}
+		legacy.OnHandleMatch(ctx, mock.Anything).Run(func(args mock.Arguments) {
+			legacyCtx := args.Get(1).(handler.NodeExecutionContext)
+			assert.NoError(t, legacyCtx.NodeStateWriter().PutDynamicNodeState(handler.DynamicNodeState{Reason: "r"}))
+			assert.Equal(t, nCtx.NodeStatus(), legacyCtx.NodeStatus())
+		}).Return(handler.DoTransition(handler.TransitionTypeEphemeral, handler.PhaseInfoRunning(nil)), nil)
+
+		trns, delta, err := handler.AdaptLegacyNode(legacy).Handle(ctx, nCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseRunning, trns.Info().GetPhase())
+		if assert.NotNil(t, delta.DynamicNodeState) {
+			assert.Equal(t, "r", delta.DynamicNodeState.Reason)
+		}
+
+		// Nothing is written to the node state until the delta is applied.
+		assert.Nil(t, nCtx.State.Dynamic)
+	})
+
+	t.Run("requires node execution context", func(t *testing.T) {
+		view := &mocks.ImmutableNodeExecutionContext{}
+		view.OnNodeID().Return("n1")
+		_, _, err := handler.AdaptLegacyNode(&mocks.Node{}).Handle(ctx, view)
+		assert.Error(t, err)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		legacy := &mocks.Node{}
+		assert.Equal(t, legacy, handler.AdaptNodeV2(handler.AdaptLegacyNode(legacy)))
+		assert.Equal(t, countingHandler{}, handler.AdaptLegacyNode(handler.AdaptNodeV2(countingHandler{})))
+	})
+}
+
+func TestAdaptNodeV2(t *testing.T) {
+	ctx := context.TODO()
+	nCtx := newNodeExecutionContext(t)
+	h := handler.AdaptNodeV2(countingHandler{})
+
+	for i := 1; i <= 2; i++ {
+		trns, err := h.Handle(ctx, nCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseRunning, trns.Info().GetPhase())
+		assert.Equal(t, uint32(i), nCtx.State.Task.PluginPhaseVersion)
+	}
+}
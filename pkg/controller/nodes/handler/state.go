@@ -19,6 +19,8 @@ type TaskNodeState struct {
 	PluginStateVersion uint32
 	BarrierClockTick   uint32
 	LastPhaseUpdatedAt time.Time
+	StalledReason      string
+	ReservationOwner   string
 }
 
 type BranchNodeState struct {
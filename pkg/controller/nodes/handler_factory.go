@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/branch"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/end"
@@ -52,8 +53,18 @@ func (f handlerFactory) Setup(ctx context.Context, setup handler.SetupContext) e
 	return nil
 }
 
+// ShedCache drops the caches of all the registered handlers that keep any.
+func (f handlerFactory) ShedCache(ctx context.Context) {
+	for _, v := range f.handlers {
+		if shedder, ok := v.(executors.CacheShedder); ok {
+			shedder.ShedCache(ctx)
+		}
+	}
+}
+
 func NewHandlerFactory(ctx context.Context, executor executors.Node, workflowLauncher launchplan.Executor,
-	launchPlanReader launchplan.Reader, kubeClient executors.Client, client catalog.Client, recoveryClient recovery.Client, scope promutils.Scope) (HandlerFactory, error) {
+	launchPlanReader launchplan.Reader, kubeClient executors.Client, client catalog.Client, recoveryClient recovery.Client,
+	fanOutHeadroom config.FanOutHeadroomConfig, scope promutils.Scope) (HandlerFactory, error) {
 
 	t, err := task.New(ctx, kubeClient, client, recoveryClient, scope)
 	if err != nil {
@@ -63,7 +74,7 @@ func NewHandlerFactory(ctx context.Context, executor executors.Node, workflowLau
 	f := &handlerFactory{
 		handlers: map[v1alpha1.NodeKind]handler.Node{
 			v1alpha1.NodeKindBranch:   branch.New(executor, scope),
-			v1alpha1.NodeKindTask:     dynamic.New(t, executor, launchPlanReader, scope),
+			v1alpha1.NodeKindTask:     dynamic.New(t, executor, launchPlanReader, fanOutHeadroom, kubeClient, scope),
 			v1alpha1.NodeKindWorkflow: subworkflow.New(executor, workflowLauncher, recoveryClient, scope),
 			v1alpha1.NodeKindStart:    start.New(),
 			v1alpha1.NodeKindEnd:      end.New(),
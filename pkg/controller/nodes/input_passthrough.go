@@ -0,0 +1,86 @@
+package nodes
+
+import (
+	"context"
+
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// nodeInputFilePaths points at the outputs file of the upstream node the inputs of a node were passed through from, or
+// the inputs file of a previous attempt the node reuses, instead of the inputs file in its data dir. It checks the node status on every call, as the inputs of a node are only
+// constructed in the round it is queued.
+type nodeInputFilePaths struct {
+	io.InputFilePaths
+	nodeStatus v1alpha1.ExecutableNodeStatus
+}
+
+func (p nodeInputFilePaths) GetInputPath() storage.DataReference {
+	if ref := p.nodeStatus.GetInputsRef(); len(ref) > 0 {
+		return ref
+	}
+
+	return p.InputFilePaths.GetInputPath()
+}
+
+// Returns the outputs file of the upstream task node whose outputs are the inputs of the task node, unchanged. That is
+// when every input is bound to the output of the same name of that node, and the outputs of the upstream task and the
+// inputs of the task have the same names and identical types. The node can then read its inputs from that file instead
+// of a copy of them.
+func (c *nodeExecutor) passThroughInputs(ctx context.Context, nCtx handler.NodeExecutionContext) (storage.DataReference, bool) {
+	bindings := nCtx.Node().GetInputBindings()
+	if !c.zeroCopyInputs || len(bindings) == 0 || nCtx.TaskReader() == nil {
+		return "", false
+	}
+
+	var upstreamID v1alpha1.NodeID
+	for _, b := range bindings {
+		promise := b.GetBinding().GetPromise()
+		if promise == nil || promise.GetVar() != b.GetVar() || (len(upstreamID) > 0 && promise.GetNodeId() != upstreamID) {
+			return "", false
+		}
+
+		upstreamID = promise.GetNodeId()
+	}
+
+	upstream, ok := nCtx.ContextualNodeLookup().GetNode(upstreamID)
+	if !ok || upstream.GetKind() != v1alpha1.NodeKindTask || upstream.GetTaskID() == nil || len(upstream.GetOutputAlias()) > 0 {
+		return "", false
+	}
+
+	upstreamTask, err := nCtx.ExecutionContext().GetTask(*upstream.GetTaskID())
+	if err != nil {
+		return "", false
+	}
+
+	tk, err := nCtx.TaskReader().Read(ctx)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to read task, copying inputs. Error: %v", err)
+		return "", false
+	}
+
+	inputs := tk.GetInterface().GetInputs().GetVariables()
+	outputs := upstreamTask.CoreTask().GetInterface().GetOutputs().GetVariables()
+	if len(inputs) != len(bindings) || len(inputs) != len(outputs) {
+		return "", false
+	}
+
+	for name, input := range inputs {
+		output, found := outputs[name]
+		if !found || !proto.Equal(input.GetType(), output.GetType()) {
+			return "", false
+		}
+	}
+
+	upstreamStatus := nCtx.ContextualNodeLookup().GetNodeExecutionStatus(ctx, upstreamID)
+	if upstreamStatus.GetPhase() != v1alpha1.NodePhaseSucceeded {
+		return "", false
+	}
+
+	return v1alpha1.GetOutputsFile(upstreamStatus.GetOutputDir()), true
+}
@@ -0,0 +1,109 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	execMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestPassThroughInputs(t *testing.T) {
+	ctx := context.TODO()
+	intType := &core.LiteralType{Type: &core.LiteralType_Simple{Simple: core.SimpleType_INTEGER}}
+	strType := &core.LiteralType{Type: &core.LiteralType_Simple{Simple: core.SimpleType_STRING}}
+	newInterface := func(types map[string]*core.LiteralType) *core.VariableMap {
+		vars := map[string]*core.Variable{}
+		for name, typ := range types {
+			vars[name] = &core.Variable{Type: typ}
+		}
+
+		return &core.VariableMap{Variables: vars}
+	}
+
+	upstreamTaskID := "upstream-task"
+	upstreamTask := &v1alpha1.TaskSpec{TaskTemplate: &core.TaskTemplate{
+		Interface: &core.TypedInterface{Outputs: newInterface(map[string]*core.LiteralType{"x": intType, "y": strType})},
+	}}
+	upstream := &v1alpha1.NodeSpec{ID: "n0", Kind: v1alpha1.NodeKindTask, TaskRef: &upstreamTaskID}
+	upstreamStatus := &v1alpha1.NodeStatus{Phase: v1alpha1.NodePhaseSucceeded, OutputDir: "s3://bucket/n0/0"}
+
+	promise := func(nodeID, bindToVar, v string) *v1alpha1.Binding {
+		return &v1alpha1.Binding{Binding: &core.Binding{
+			Var: v,
+			Binding: &core.BindingData{Value: &core.BindingData_Promise{
+				Promise: &core.OutputReference{NodeId: nodeID, Var: bindToVar},
+			}},
+		}}
+	}
+
+	newContext := func(bindings []*v1alpha1.Binding, inputs map[string]*core.LiteralType) *mocks.NodeExecutionContext {
+		n := &v1alpha1.NodeSpec{ID: "n1", Kind: v1alpha1.NodeKindTask, InputBindings: bindings}
+		tr := &mocks.TaskReader{}
+		tr.OnReadMatch(ctx).Return(&core.TaskTemplate{Interface: &core.TypedInterface{Inputs: newInterface(inputs)}}, nil)
+		eCtx := &execMocks.ExecutionContext{}
+		eCtx.OnGetTask(upstreamTaskID).Return(upstreamTask, nil)
+
+		nCtx := &mocks.NodeExecutionContext{}
+		nCtx.OnNode().Return(n)
+		nCtx.OnTaskReader().Return(tr)
+		nCtx.OnExecutionContext().Return(eCtx)
+		nCtx.OnContextualNodeLookup().Return(executors.NewTestNodeLookup(
+			map[v1alpha1.NodeID]v1alpha1.ExecutableNode{"n0": upstream, "n1": n},
+			map[v1alpha1.NodeID]v1alpha1.ExecutableNodeStatus{"n0": upstreamStatus},
+		))
+		return nCtx
+	}
+
+	exec := &nodeExecutor{zeroCopyInputs: true}
+	sameInputs := map[string]*core.LiteralType{"x": intType, "y": strType}
+
+	t.Run("passed-through", func(t *testing.T) {
+		ref, ok := exec.passThroughInputs(ctx, newContext([]*v1alpha1.Binding{promise("n0", "x", "x"), promise("n0", "y", "y")}, sameInputs))
+		assert.True(t, ok)
+		assert.Equal(t, storage.DataReference("s3://bucket/n0/0/outputs.pb"), ref)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, ok := (&nodeExecutor{}).passThroughInputs(ctx, newContext([]*v1alpha1.Binding{promise("n0", "x", "x"), promise("n0", "y", "y")}, sameInputs))
+		assert.False(t, ok)
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		_, ok := exec.passThroughInputs(ctx, newContext([]*v1alpha1.Binding{promise("n0", "x", "y"), promise("n0", "y", "x")}, sameInputs))
+		assert.False(t, ok)
+	})
+
+	t.Run("subset", func(t *testing.T) {
+		_, ok := exec.passThroughInputs(ctx, newContext([]*v1alpha1.Binding{promise("n0", "x", "x")}, map[string]*core.LiteralType{"x": intType}))
+		assert.False(t, ok)
+	})
+
+	t.Run("different-type", func(t *testing.T) {
+		_, ok := exec.passThroughInputs(ctx, newContext([]*v1alpha1.Binding{promise("n0", "x", "x"), promise("n0", "y", "y")},
+			map[string]*core.LiteralType{"x": intType, "y": intType}))
+		assert.False(t, ok)
+	})
+}
+
+func TestNodeInputFilePaths(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	s := &v1alpha1.NodeStatus{DataDir: "s3://bucket/n1"}
+	paths := nodeInputFilePaths{InputFilePaths: ioutils.NewInputFilePaths(ctx, store, s.GetDataDir()), nodeStatus: s}
+	assert.Equal(t, storage.DataReference("s3://bucket/n1/inputs.pb"), paths.GetInputPath())
+
+	s.SetInputsRef("s3://bucket/n0/0/outputs.pb")
+	assert.Equal(t, storage.DataReference("s3://bucket/n0/0/outputs.pb"), paths.GetInputPath())
+	assert.Equal(t, storage.DataReference("s3://bucket/n1"), paths.GetInputPrefixPath())
+}
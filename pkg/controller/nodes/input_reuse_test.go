@@ -0,0 +1,149 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	executorsMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	nodeHandlerMocks "github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestNodeExecutor_writeInputs(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	c := &nodeExecutor{
+		store: store,
+		metrics: &nodeMetrics{
+			InputsWriteSkipped: labeled.NewCounter("inputs_write_skipped", "", promutils.NewTestScope()),
+		},
+	}
+
+	inputsFile := storage.DataReference("s3://bucket/n1/inputs.pb")
+	inputs := coreutils.MustMakeLiteral(map[string]interface{}{"x": 1}).GetMap()
+	nodeStatus := &v1alpha1.NodeStatus{}
+	assert.NoError(t, c.writeInputs(ctx, nodeStatus, inputsFile, inputs))
+	assert.NotEmpty(t, nodeStatus.GetInputsHash())
+
+	read := func() *core.LiteralMap {
+		written := &core.LiteralMap{}
+		assert.NoError(t, store.ReadProtobuf(ctx, inputsFile, written))
+		return written
+	}
+
+	// The same inputs are not written again.
+	assert.NoError(t, store.WriteProtobuf(ctx, inputsFile, storage.Options{}, &core.LiteralMap{}))
+	assert.NoError(t, c.writeInputs(ctx, nodeStatus, inputsFile, inputs))
+	assert.Empty(t, read().GetLiterals())
+
+	// Changed inputs are written.
+	changed := coreutils.MustMakeLiteral(map[string]interface{}{"x": 2}).GetMap()
+	hash := nodeStatus.GetInputsHash()
+	assert.NoError(t, c.writeInputs(ctx, nodeStatus, inputsFile, changed))
+	assert.NotEqual(t, hash, nodeStatus.GetInputsHash())
+	assert.Equal(t, int64(2), read().GetLiterals()["x"].GetScalar().GetPrimitive().GetInteger())
+
+	// The inputs are written again if the inputs file no longer exists.
+	reset := &v1alpha1.NodeStatus{InputsHash: nodeStatus.GetInputsHash()}
+	missingFile := storage.DataReference("s3://bucket/n2/inputs.pb")
+	assert.NoError(t, c.writeInputs(ctx, reset, missingFile, changed))
+	metadata, err := store.Head(ctx, missingFile)
+	assert.NoError(t, err)
+	assert.True(t, metadata.Exists())
+}
+
+func TestNodeExecutor_InputsReusedAcrossRetry(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	scope := promutils.NewTestScope()
+	c := &nodeExecutor{
+		store: store,
+		metrics: &nodeMetrics{
+			InputsWriteSkipped:     labeled.NewCounter("inputs_write_skipped", "", scope),
+			NodeInputGatherLatency: labeled.NewStopWatch("node_input_latency", "", time.Millisecond, scope),
+		},
+	}
+
+	parent := &v1alpha1.NodeStatus{
+		DataDir:                  "s3://bucket/parent",
+		OutputDir:                "s3://bucket/parent/0",
+		DataReferenceConstructor: store,
+	}
+
+	ec := &executorsMocks.ExecutionContext{}
+	ec.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{})
+	dag := &executorsMocks.DAGStructure{}
+	dag.OnToNode("sub").Return(nil, nil)
+
+	// Queues the sub-node of the current attempt of the parent with the given input.
+	queueSubNode := func(x int) v1alpha1.ExecutableNodeStatus {
+		node := &v1alpha1.NodeSpec{
+			ID:   "sub",
+			Kind: v1alpha1.NodeKindTask,
+			InputBindings: []*v1alpha1.Binding{{Binding: &core.Binding{
+				Var: "x",
+				Binding: &core.BindingData{
+					Value: &core.BindingData_Scalar{Scalar: coreutils.MustMakeLiteral(x).GetScalar()},
+				},
+			}}},
+		}
+
+		nodeStatus := parent.GetNodeExecutionStatus(ctx, "sub")
+		p, err := c.preExecute(ctx, dag, &nodeExecContext{node: node, nodeStatus: nodeStatus, ic: ec, store: store})
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseQueued, p.GetPhase())
+		return nodeStatus
+	}
+
+	readInputs := func(nodeStatus v1alpha1.ExecutableNodeStatus) int64 {
+		paths := nodeInputFilePaths{InputFilePaths: ioutils.NewInputFilePaths(ctx, store, nodeStatus.GetDataDir()), nodeStatus: nodeStatus}
+		inputs := &core.LiteralMap{}
+		assert.NoError(t, store.ReadProtobuf(ctx, paths.GetInputPath(), inputs))
+		return inputs.GetLiterals()["x"].GetScalar().GetPrimitive().GetInteger()
+	}
+
+	retry := func() {
+		h := &nodeHandlerMocks.Node{}
+		h.OnAbortMatch(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		h.OnFinalizeMatch(mock.Anything, mock.Anything).Return(nil)
+		s, err := c.handleRetryableFailure(ctx, &nodeExecContext{nodeStatus: parent, store: store}, h)
+		assert.NoError(t, err)
+		assert.Equal(t, executors.NodeStatusPending, s)
+	}
+
+	first := queueSubNode(1)
+	firstInputsFile := v1alpha1.GetInputsFile(first.GetDataDir())
+	assert.Empty(t, first.GetInputsRef())
+	assert.Equal(t, int64(1), readInputs(first))
+
+	// The sub-node of the retry reads the unchanged inputs of the previous attempt instead of writing them again.
+	retry()
+	second := queueSubNode(1)
+	assert.NotEqual(t, first.GetDataDir(), second.GetDataDir())
+	assert.Equal(t, firstInputsFile, second.GetInputsRef())
+	metadata, err := store.Head(ctx, v1alpha1.GetInputsFile(second.GetDataDir()))
+	assert.NoError(t, err)
+	assert.False(t, metadata.Exists())
+	assert.Equal(t, int64(1), readInputs(second))
+
+	// Changed inputs are written to the data dir of the sub-node of the retry.
+	retry()
+	third := queueSubNode(2)
+	assert.Empty(t, third.GetInputsRef())
+	assert.Equal(t, int64(2), readInputs(third))
+	assert.Len(t, parent.SubNodeStatus, 1)
+}
@@ -22,12 +22,23 @@ import (
 const NodeIDLabel = "node-id"
 const TaskNameLabel = "task-name"
 const NodeInterruptibleLabel = "interruptible"
+const ProjectLabel = "project"
+const DomainLabel = "domain"
+
+// ParentNodeIDLabel is set on nodes generated by a task, e.g. the nodes of a dynamic workflow, to the unique ID of the
+// node that generated them, so that the pods of a fan-out can be told apart from the other pods of the execution.
+const ParentNodeIDLabel = "parent-node-id"
+
+// IAMRoleAnnotation carries the IAM role, or cloud identity, requested in the security context of the execution to the
+// pods of its tasks, where the pod webhook applies it.
+const IAMRoleAnnotation = "flyte.org/iam-role"
 
 type nodeExecMetadata struct {
 	v1alpha1.Meta
-	nodeExecID     *core.NodeExecutionIdentifier
-	interrutptible bool
-	nodeLabels     map[string]string
+	nodeExecID      *core.NodeExecutionIdentifier
+	interrutptible  bool
+	nodeLabels      map[string]string
+	nodeAnnotations map[string]string
 }
 
 func (e nodeExecMetadata) GetNodeExecutionID() *core.NodeExecutionIdentifier {
@@ -50,6 +61,28 @@ func (e nodeExecMetadata) GetLabels() map[string]string {
 	return e.nodeLabels
 }
 
+// GetAnnotations returns the workflow annotations, overridden by those of the node, along with the IAM role requested
+// in the security context, if any.
+func (e nodeExecMetadata) GetAnnotations() map[string]string {
+	securityContext := e.Meta.GetSecurityContext()
+	iamRole := securityContext.GetRunAs().GetIamRole()
+	if len(iamRole) == 0 && len(e.nodeAnnotations) == 0 {
+		return e.Meta.GetAnnotations()
+	}
+
+	annotations := make(map[string]string, len(e.Meta.GetAnnotations())+len(e.nodeAnnotations)+1)
+	for k, v := range e.Meta.GetAnnotations() {
+		annotations[k] = v
+	}
+	for k, v := range e.nodeAnnotations {
+		annotations[k] = v
+	}
+	if len(iamRole) > 0 {
+		annotations[IAMRoleAnnotation] = iamRole
+	}
+	return annotations
+}
+
 type nodeExecContext struct {
 	store               *storage.DataStore
 	tr                  handler.TaskReader
@@ -146,19 +179,34 @@ func newNodeExecContext(_ context.Context, store *storage.DataStore, execContext
 			NodeId:      node.GetID(),
 			ExecutionId: execContext.GetExecutionID().WorkflowExecutionIdentifier,
 		},
-		interrutptible: interruptible,
+		interrutptible:  interruptible,
+		nodeAnnotations: node.GetAnnotations(),
 	}
 
-	// Copy the wf labels before adding node specific labels.
+	// Copy the wf labels, overridden by those set on the node, before adding node specific labels.
 	nodeLabels := make(map[string]string)
 	for k, v := range execContext.GetLabels() {
 		nodeLabels[k] = v
 	}
+	for k, v := range node.GetLabels() {
+		nodeLabels[k] = v
+	}
 	nodeLabels[NodeIDLabel] = utils.SanitizeLabelValue(node.GetID())
 	if tr != nil && tr.GetTaskID() != nil {
 		nodeLabels[TaskNameLabel] = utils.SanitizeLabelValue(tr.GetTaskID().Name)
 	}
 	nodeLabels[NodeInterruptibleLabel] = strconv.FormatBool(interruptible)
+	if parentInfo, ok := execContext.GetParentInfo().(executors.ImmutableParentTaskInfo); ok && parentInfo.GetParentTaskID() != nil {
+		nodeLabels[ParentNodeIDLabel] = utils.SanitizeLabelValue(parentInfo.GetUniqueID())
+	}
+	// Workflow labels take precedence, so that executions can keep using labels of their own with the same keys.
+	execID := md.nodeExecID.GetExecutionId()
+	if _, found := nodeLabels[ProjectLabel]; !found && len(execID.GetProject()) > 0 {
+		nodeLabels[ProjectLabel] = utils.SanitizeLabelValue(execID.GetProject())
+	}
+	if _, found := nodeLabels[DomainLabel]; !found && len(execID.GetDomain()) > 0 {
+		nodeLabels[DomainLabel] = utils.SanitizeLabelValue(execID.GetDomain())
+	}
 	md.nodeLabels = nodeLabels
 
 	return &nodeExecContext{
@@ -195,7 +243,11 @@ func (c *nodeExecutor) newNodeExecContextDefault(ctx context.Context, currentNod
 		if err != nil {
 			return nil, err
 		}
-		tr = taskReader{TaskTemplate: tk.CoreTask()}
+		if ref, ok := tk.(*v1alpha1.TaskReference); ok {
+			tr = remoteTaskReader{nodeID: n.GetID(), ref: ref, store: c.taskTemplates}
+		} else {
+			tr = taskReader{TaskTemplate: tk.CoreTask()}
+		}
 	}
 
 	workflowEnqueuer := func() error {
@@ -221,17 +273,20 @@ func (c *nodeExecutor) newNodeExecContextDefault(ctx context.Context, currentNod
 		rawOutputPrefix = storage.DataReference(executionContext.GetRawOutputDataConfig().OutputLocationPrefix)
 	}
 
-	return newNodeExecContext(ctx, c.store, executionContext, nl, n, s,
+	return newNodeExecContext(ctx, c.store, executionContext, nl, c.applyResourceEscalation(n, s), s,
 		ioutils.NewCachedInputReader(
 			ctx,
 			ioutils.NewRemoteFileInputReader(
 				ctx,
 				c.store,
-				ioutils.NewInputFilePaths(
-					ctx,
-					c.store,
-					s.GetDataDir(),
-				),
+				nodeInputFilePaths{
+					InputFilePaths: ioutils.NewInputFilePaths(
+						ctx,
+						c.store,
+						s.GetDataDir(),
+					),
+					nodeStatus: s,
+				},
 			),
 		),
 		interruptible,
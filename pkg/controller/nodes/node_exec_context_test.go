@@ -13,6 +13,7 @@ import (
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/storage"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
@@ -62,6 +63,62 @@ func Test_NodeContext(t *testing.T) {
 	assert.Equal(t, "false", nCtx.NodeExecutionMetadata().GetLabels()["interruptible"])
 	assert.Equal(t, "task-name", nCtx.NodeExecutionMetadata().GetLabels()["task-name"])
 	assert.Equal(t, p, nCtx.ExecutionContext().GetParentInfo())
+	assert.NotContains(t, nCtx.NodeExecutionMetadata().GetLabels(), ParentNodeIDLabel)
+}
+
+func Test_NodeContext_ParentNodeIDLabel(t *testing.T) {
+	dataStore, _ := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	w1 := &v1alpha1.FlyteWorkflow{DataReferenceConstructor: dataStore}
+	taskID := "taskID"
+	n := &v1alpha1.NodeSpec{ID: "dn0", TaskRef: &taskID, Kind: v1alpha1.NodeKindTask}
+
+	t.Run("generated-by-task", func(t *testing.T) {
+		p := executors.NewParentTaskInfo("n1", 0, &core.TaskExecutionIdentifier{})
+		execContext := executors.NewExecutionContext(w1, nil, nil, p, nil)
+		nCtx := newNodeExecContext(context.TODO(), dataStore, execContext, w1, n, nil, nil, false, 0, nil, TaskReader{}, nil, nil, "s3://bucket", ioutils.NewConstantShardSelector([]string{"x"}))
+		assert.Equal(t, "n1", nCtx.NodeExecutionMetadata().GetLabels()[ParentNodeIDLabel])
+	})
+
+	t.Run("sub-workflow", func(t *testing.T) {
+		p := executors.NewParentInfo("n1", 0)
+		execContext := executors.NewExecutionContext(w1, nil, nil, p, nil)
+		nCtx := newNodeExecContext(context.TODO(), dataStore, execContext, w1, n, nil, nil, false, 0, nil, TaskReader{}, nil, nil, "s3://bucket", ioutils.NewConstantShardSelector([]string{"x"}))
+		assert.NotContains(t, nCtx.NodeExecutionMetadata().GetLabels(), ParentNodeIDLabel)
+	})
+}
+
+func Test_NodeContext_NodeLabelsAndAnnotations(t *testing.T) {
+	dataStore, _ := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	w1 := &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:      map[string]string{"team": "wf-team", "env": "dev"},
+			Annotations: map[string]string{"owner": "wf-owner", "note": "wf"},
+		},
+		DataReferenceConstructor: dataStore,
+	}
+
+	taskID := "taskID"
+	n := &v1alpha1.NodeSpec{
+		ID:          "id",
+		TaskRef:     &taskID,
+		Kind:        v1alpha1.NodeKindTask,
+		Labels:      map[string]string{"team": "node-team", "cost-center": "ml", "node-id": "spoofed"},
+		Annotations: map[string]string{"owner": "node-owner"},
+	}
+
+	execContext := executors.NewExecutionContext(w1, nil, nil, parentInfo{}, nil)
+	nCtx := newNodeExecContext(context.TODO(), dataStore, execContext, w1, n, nil, nil, false, 0, nil, TaskReader{}, nil, nil, "s3://bucket", ioutils.NewConstantShardSelector([]string{"x"}))
+	labels := nCtx.NodeExecutionMetadata().GetLabels()
+	assert.Equal(t, "node-team", labels["team"])
+	assert.Equal(t, "dev", labels["env"])
+	assert.Equal(t, "ml", labels["cost-center"])
+	// Labels set by the system cannot be overridden by the node.
+	assert.Equal(t, "id", labels["node-id"])
+
+	assert.Equal(t, map[string]string{"owner": "node-owner", "note": "wf"}, nCtx.NodeExecutionMetadata().GetAnnotations())
+	// The workflow is left untouched.
+	assert.Equal(t, "wf-team", w1.GetLabels()["team"])
+	assert.Equal(t, "wf-owner", w1.GetAnnotations()["owner"])
 }
 
 func Test_NodeContextDefault(t *testing.T) {
@@ -47,6 +47,8 @@ func (n nodeStateManager) GetTaskNodeState() handler.TaskNodeState {
 			PluginState:        tn.GetPluginState(),
 			BarrierClockTick:   tn.GetBarrierClockTick(),
 			LastPhaseUpdatedAt: tn.GetLastPhaseUpdatedAt(),
+			StalledReason:      tn.GetStalledReason(),
+			ReservationOwner:   tn.GetReservationOwner(),
 		}
 	}
 	return handler.TaskNodeState{}
@@ -0,0 +1,105 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// escalatedNode replaces the resources of a node with the resources that were escalated for its subsequent attempts.
+type escalatedNode struct {
+	v1alpha1.ExecutableNode
+	resources *v1.ResourceRequirements
+}
+
+func (n escalatedNode) GetResources() *v1.ResourceRequirements {
+	return n.resources
+}
+
+// Returns the node with the escalated resources recorded in the node status applied, if any.
+func (c *nodeExecutor) applyResourceEscalation(n v1alpha1.ExecutableNode, s v1alpha1.ExecutableNodeStatus) v1alpha1.ExecutableNode {
+	if !c.oomRetry.Enabled || n.GetKind() != v1alpha1.NodeKindTask {
+		return n
+	}
+
+	if e := s.GetResourceEscalation(); e != nil {
+		return escalatedNode{ExecutableNode: n, resources: &e.Resources}
+	}
+
+	return n
+}
+
+// Scales the memory requests and limits by the given percentage, bounded by maxMemory. Returns false if the memory
+// could not be raised any further.
+func escalateMemory(resources v1.ResourceRequirements, percent int64, maxMemory resource.Quantity) (v1.ResourceRequirements, bool) {
+	escalated := *resources.DeepCopy()
+	changed := false
+	for _, list := range []v1.ResourceList{escalated.Requests, escalated.Limits} {
+		current, ok := list[v1.ResourceMemory]
+		if !ok {
+			continue
+		}
+
+		scaled := *resource.NewQuantity(current.Value()*percent/100, current.Format)
+		if scaled.Cmp(maxMemory) > 0 {
+			scaled = maxMemory.DeepCopy()
+		}
+
+		if scaled.Cmp(current) > 0 {
+			list[v1.ResourceMemory] = scaled
+			changed = true
+		}
+	}
+
+	return escalated, changed
+}
+
+// Records escalated memory requirements in the node status after an attempt of a task node was OOMKilled, so that all
+// subsequent attempts run with more memory.
+func (c *nodeExecutor) escalateResourcesOnOOM(ctx context.Context, nCtx handler.NodeExecutionContext,
+	nodeStatus v1alpha1.ExecutableNodeStatus, execErr *core.ExecutionError) {
+
+	if !c.oomRetry.Enabled || nCtx.Node().GetKind() != v1alpha1.NodeKindTask || execErr.GetCode() != k8s.PodFailureOOMKilled.Code {
+		return
+	}
+
+	maxMemory, err := resource.ParseQuantity(c.oomRetry.MaxMemory)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to parse max memory [%v] for OOM retries. Error: %v", c.oomRetry.MaxMemory, err)
+		return
+	}
+
+	// nCtx.Node() already reflects any previous escalation.
+	current := nCtx.Node().GetResources()
+	if current == nil {
+		logger.Infof(ctx, "Node has no resources set, skipping memory escalation.")
+		return
+	}
+
+	escalated, ok := escalateMemory(*current, c.oomRetry.MemoryMultiplierPercent, maxMemory)
+	if !ok {
+		logger.Infof(ctx, "Memory of node is already at the maximum [%v], skipping memory escalation.", maxMemory.String())
+		return
+	}
+
+	from := current.Requests[v1.ResourceMemory]
+	to := escalated.Requests[v1.ResourceMemory]
+	reason := fmt.Sprintf("attempt [%d] was OOMKilled, escalated memory requests from [%s] to [%s]",
+		nodeStatus.GetAttempts(), from.String(), to.String())
+	logger.Infof(ctx, "Node [%v] %s", nCtx.NodeID(), reason)
+
+	nodeStatus.SetResourceEscalation(&v1alpha1.ResourceEscalation{
+		Resources: escalated,
+		Attempt:   nodeStatus.GetAttempts(),
+		Reason:    reason,
+	})
+	c.metrics.MemoryEscalations.Inc(ctx)
+}
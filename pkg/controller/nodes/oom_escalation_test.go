@@ -0,0 +1,95 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	mocks2 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestEscalateMemory(t *testing.T) {
+	resources := v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Limits: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse("3Gi"),
+		},
+	}
+
+	escalated, ok := escalateMemory(resources, 200, resource.MustParse("4Gi"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(2*1024*1024*1024), escalated.Requests.Memory().Value())
+	assert.Equal(t, int64(4*1024*1024*1024), escalated.Limits.Memory().Value())
+	assert.Equal(t, int64(1000), escalated.Requests.Cpu().MilliValue())
+	// The original resources are left untouched
+	assert.Equal(t, int64(1024*1024*1024), resources.Requests.Memory().Value())
+
+	_, ok = escalateMemory(v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+	}, 200, resource.MustParse("4Gi"))
+	assert.False(t, ok)
+}
+
+func TestEscalateResourcesOnOOM(t *testing.T) {
+	ctx := context.TODO()
+	oomErr := &core.ExecutionError{Code: "OOMKilled", Kind: core.ExecutionError_USER}
+
+	newExecutor := func(enabled bool) *nodeExecutor {
+		return &nodeExecutor{
+			oomRetry: config.OOMRetryConfig{Enabled: enabled, MemoryMultiplierPercent: 150, MaxMemory: "8Gi"},
+			metrics: &nodeMetrics{
+				MemoryEscalations: labeled.NewCounter("escalations", "", promutils.NewTestScope()),
+			},
+		}
+	}
+
+	newContext := func(kind v1alpha1.NodeKind) *mocks.NodeExecutionContext {
+		n := &mocks2.ExecutableNode{}
+		n.OnGetKind().Return(kind)
+		n.OnGetResources().Return(&v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+		})
+		nCtx := &mocks.NodeExecutionContext{}
+		nCtx.OnNode().Return(n)
+		nCtx.OnNodeID().Return("n1")
+		return nCtx
+	}
+
+	t.Run("escalated", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{Attempts: 1}
+		newExecutor(true).escalateResourcesOnOOM(ctx, newContext(v1alpha1.NodeKindTask), s, oomErr)
+		if assert.NotNil(t, s.GetResourceEscalation()) {
+			assert.Equal(t, int64(3*1024*1024*1024), s.GetResourceEscalation().Resources.Requests.Memory().Value())
+			assert.Equal(t, uint32(1), s.GetResourceEscalation().Attempt)
+			assert.Contains(t, s.GetResourceEscalation().Reason, "OOMKilled")
+		}
+
+		escalatedNode := newExecutor(true).applyResourceEscalation(newContext(v1alpha1.NodeKindTask).Node(), s)
+		assert.Equal(t, int64(3*1024*1024*1024), escalatedNode.GetResources().Requests.Memory().Value())
+	})
+
+	t.Run("not-oom", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{}
+		newExecutor(true).escalateResourcesOnOOM(ctx, newContext(v1alpha1.NodeKindTask), s,
+			&core.ExecutionError{Code: "Evicted"})
+		assert.Nil(t, s.GetResourceEscalation())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{}
+		newExecutor(false).escalateResourcesOnOOM(ctx, newContext(v1alpha1.NodeKindTask), s, oomErr)
+		assert.Nil(t, s.GetResourceEscalation())
+	})
+}
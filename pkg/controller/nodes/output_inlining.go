@@ -0,0 +1,98 @@
+package nodes
+
+import (
+	"context"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Stores the outputs of a task node that just succeeded in its status, if they are small enough, so that downstream
+// nodes and branches can resolve them without a datastore round trip. Larger outputs are only stored in the datastore.
+// Inlining is best effort, the outputs file remains the source of truth, and may only cover some of the outputs.
+func (c *nodeExecutor) inlineOutputs(ctx context.Context, nCtx handler.NodeExecutionContext,
+	nodeStatus v1alpha1.ExecutableNodeStatus, info *handler.ExecutionInfo) {
+
+	if !c.outputInlining.Enabled || nCtx.Node().GetKind() != v1alpha1.NodeKindTask || info == nil || info.OutputInfo == nil {
+		return
+	}
+
+	outputsFileRef := info.OutputInfo.OutputURI
+	metadata, err := c.store.Head(ctx, outputsFileRef)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to check the size of outputs [%v], skipping inlining. Error: %v", outputsFileRef, err)
+		return
+	}
+
+	perType := len(c.outputInlining.MaxSizeBytesPerType) > 0
+	if !metadata.Exists() || (!perType && metadata.Size() > c.outputInlining.MaxSizeBytes) {
+		return
+	}
+
+	outputs := &core.LiteralMap{}
+	if err := c.store.ReadProtobuf(ctx, outputsFileRef, outputs); err != nil {
+		logger.Warnf(ctx, "Failed to read outputs [%v], skipping inlining. Error: %v", outputsFileRef, err)
+		return
+	}
+
+	if perType {
+		outputs = c.selectInlinedOutputs(outputs)
+		if len(outputs.GetLiterals()) == 0 {
+			return
+		}
+	}
+
+	logger.Debugf(ctx, "Inlining [%v] of [%v] bytes of outputs into the node status", proto.Size(outputs), metadata.Size())
+	nodeStatus.SetInlinedOutputs(outputs)
+}
+
+// Returns the outputs that are no larger than the threshold of their literal type. Downstream nodes read the others
+// from the outputs file.
+func (c *nodeExecutor) selectInlinedOutputs(outputs *core.LiteralMap) *core.LiteralMap {
+	selected := &core.LiteralMap{Literals: map[string]*core.Literal{}}
+	for name, l := range outputs.GetLiterals() {
+		maxSizeBytes, ok := c.outputInlining.MaxSizeBytesPerType[literalKind(l)]
+		if !ok {
+			maxSizeBytes = c.outputInlining.MaxSizeBytes
+		}
+
+		if maxSizeBytes >= 0 && int64(proto.Size(l)) <= maxSizeBytes {
+			selected.Literals[name] = l
+		}
+	}
+
+	return selected
+}
+
+// Returns the literal type that inlining thresholds are configured for.
+func literalKind(l *core.Literal) string {
+	switch v := l.GetValue().(type) {
+	case *core.Literal_Collection:
+		return "collection"
+	case *core.Literal_Map:
+		return "map"
+	case *core.Literal_Scalar:
+		switch v.Scalar.GetValue().(type) {
+		case *core.Scalar_Primitive:
+			return "primitive"
+		case *core.Scalar_Blob:
+			return "blob"
+		case *core.Scalar_Schema:
+			return "schema"
+		case *core.Scalar_Binary:
+			return "binary"
+		case *core.Scalar_Generic:
+			return "generic"
+		case *core.Scalar_NoneType:
+			return "none"
+		case *core.Scalar_Error:
+			return "error"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,141 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	mocks2 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestInlineOutputs(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	outputs := &core.LiteralMap{Literals: map[string]*core.Literal{"is_even": coreutils.MustMakePrimitiveLiteral(true)}}
+	outputsFileRef := storage.DataReference("s3://bucket/n1/outputs.pb")
+	assert.NoError(t, store.WriteProtobuf(ctx, outputsFileRef, storage.Options{}, outputs))
+
+	newContext := func(kind v1alpha1.NodeKind) *mocks.NodeExecutionContext {
+		n := &mocks2.ExecutableNode{}
+		n.OnGetKind().Return(kind)
+		nCtx := &mocks.NodeExecutionContext{}
+		nCtx.OnNode().Return(n)
+		return nCtx
+	}
+
+	newExecutor := func(enabled bool, maxSizeBytes int64) *nodeExecutor {
+		return &nodeExecutor{
+			store:          store,
+			outputInlining: config.OutputInliningConfig{Enabled: enabled, MaxSizeBytes: maxSizeBytes},
+		}
+	}
+
+	info := &handler.ExecutionInfo{OutputInfo: &handler.OutputInfo{OutputURI: outputsFileRef}}
+
+	t.Run("inlined", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{}
+		newExecutor(true, 1024).inlineOutputs(ctx, newContext(v1alpha1.NodeKindTask), s, info)
+		if assert.NotNil(t, s.GetInlinedOutputs()) {
+			assert.True(t, s.GetInlinedOutputs().Literals["is_even"].GetScalar().GetPrimitive().GetBoolean())
+		}
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{}
+		newExecutor(true, 1).inlineOutputs(ctx, newContext(v1alpha1.NodeKindTask), s, info)
+		assert.Nil(t, s.GetInlinedOutputs())
+	})
+
+	t.Run("no-outputs", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{}
+		newExecutor(true, 1024).inlineOutputs(ctx, newContext(v1alpha1.NodeKindTask), s,
+			&handler.ExecutionInfo{OutputInfo: &handler.OutputInfo{OutputURI: "s3://bucket/missing/outputs.pb"}})
+		assert.Nil(t, s.GetInlinedOutputs())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{}
+		newExecutor(false, 1024).inlineOutputs(ctx, newContext(v1alpha1.NodeKindTask), s, info)
+		assert.Nil(t, s.GetInlinedOutputs())
+	})
+
+	t.Run("not-a-task", func(t *testing.T) {
+		s := &v1alpha1.NodeStatus{}
+		newExecutor(true, 1024).inlineOutputs(ctx, newContext(v1alpha1.NodeKindBranch), s, info)
+		assert.Nil(t, s.GetInlinedOutputs())
+	})
+}
+
+func TestInlineOutputs_PerType(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	outputs := &core.LiteralMap{Literals: map[string]*core.Literal{
+		"count": coreutils.MustMakePrimitiveLiteral(int64(3)),
+		"model": coreutils.MakeLiteralForBlob("s3://bucket/model", false, ""),
+		"words": coreutils.MustMakeLiteral([]interface{}{"a", "b", "c"}),
+	}}
+	outputsFileRef := storage.DataReference("s3://bucket/n1/outputs.pb")
+	assert.NoError(t, store.WriteProtobuf(ctx, outputsFileRef, storage.Options{}, outputs))
+
+	n := &mocks2.ExecutableNode{}
+	n.OnGetKind().Return(v1alpha1.NodeKindTask)
+	nCtx := &mocks.NodeExecutionContext{}
+	nCtx.OnNode().Return(n)
+	info := &handler.ExecutionInfo{OutputInfo: &handler.OutputInfo{OutputURI: outputsFileRef}}
+
+	t.Run("some", func(t *testing.T) {
+		exec := &nodeExecutor{
+			store: store,
+			outputInlining: config.OutputInliningConfig{
+				Enabled:             true,
+				MaxSizeBytes:        1,
+				MaxSizeBytesPerType: map[string]int64{"primitive": 1024, "blob": -1},
+			},
+		}
+
+		s := &v1alpha1.NodeStatus{}
+		exec.inlineOutputs(ctx, nCtx, s, info)
+		if assert.NotNil(t, s.GetInlinedOutputs()) {
+			// Collections fall back to the max size of all outputs.
+			assert.Len(t, s.GetInlinedOutputs().Literals, 1)
+			assert.Equal(t, int64(3), s.GetInlinedOutputs().Literals["count"].GetScalar().GetPrimitive().GetInteger())
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		exec := &nodeExecutor{
+			store: store,
+			outputInlining: config.OutputInliningConfig{
+				Enabled:             true,
+				MaxSizeBytes:        1024,
+				MaxSizeBytesPerType: map[string]int64{"primitive": -1, "blob": -1, "collection": 1},
+			},
+		}
+
+		s := &v1alpha1.NodeStatus{}
+		exec.inlineOutputs(ctx, nCtx, s, info)
+		assert.Nil(t, s.GetInlinedOutputs())
+	})
+}
+
+func TestLiteralKind(t *testing.T) {
+	assert.Equal(t, "primitive", literalKind(coreutils.MustMakePrimitiveLiteral(1)))
+	assert.Equal(t, "blob", literalKind(coreutils.MakeLiteralForBlob("s3://bucket/blob", false, "")))
+	assert.Equal(t, "collection", literalKind(coreutils.MustMakeLiteral([]interface{}{1})))
+	assert.Equal(t, "map", literalKind(coreutils.MustMakeLiteral(map[string]interface{}{"a": 1})))
+	assert.Equal(t, "none", literalKind(coreutils.MustMakeLiteral(nil)))
+	assert.Equal(t, "", literalKind(&core.Literal{}))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/storage"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
@@ -13,6 +12,7 @@ import (
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 type VarName = string
@@ -41,6 +41,13 @@ type remoteFileOutputResolver struct {
 func (r remoteFileOutputResolver) ExtractOutput(ctx context.Context, nl executors.NodeLookup, n v1alpha1.ExecutableNode,
 	bindToVar VarName) (values *core.Literal, err error) {
 	nodeStatus := nl.GetNodeExecutionStatus(ctx, n.GetID())
+	// Outputs of skipped nodes are only resolved by nodes that execute regardless, e.g. because the upstream node was
+	// skipped by its when predicate or the skipped upstream policy of the downstream node.
+	if nodeStatus.GetPhase() == v1alpha1.NodePhaseSkipped {
+		logger.Debugf(ctx, "Node [%v] was skipped, resolving [%v] as absent", n.GetID(), bindToVar)
+		return absentLiteral(), nil
+	}
+
 	outputsFileRef := v1alpha1.GetOutputsFile(nodeStatus.GetOutputDir())
 
 	index, actualVar, err := ParseVarName(bindToVar)
@@ -54,15 +61,40 @@ func (r remoteFileOutputResolver) ExtractOutput(ctx context.Context, nl executor
 		actualVar = variable
 	}
 
+	d, err := readOutputs(ctx, r.store, n.GetID(), nodeStatus, outputsFileRef, actualVar)
+	if err != nil {
+		return nil, err
+	}
+
 	if index == nil {
-		return resolveSingleOutput(ctx, r.store, n.GetID(), outputsFileRef, actualVar)
+		return resolveSingleOutput(d, n.GetID(), outputsFileRef, actualVar)
 	}
 
-	return resolveSubtaskOutput(ctx, r.store, n.GetID(), outputsFileRef, *index, actualVar)
+	return resolveSubtaskOutput(d, n.GetID(), outputsFileRef, *index, actualVar)
 }
 
-func resolveSubtaskOutput(ctx context.Context, store storage.ProtobufStore, nodeID string, outputsFileRef storage.DataReference,
-	idx int, varName string) (*core.Literal, error) {
+// The value of an output of a node that did not run.
+func absentLiteral() *core.Literal {
+	return &core.Literal{
+		Value: &core.Literal_Scalar{
+			Scalar: &core.Scalar{
+				Value: &core.Scalar_NoneType{NoneType: &core.Void{}},
+			},
+		},
+	}
+}
+
+// Returns the outputs inlined into the node status, if they include the given variable, and reads them from the outputs
+// file otherwise.
+func readOutputs(ctx context.Context, store storage.ProtobufStore, nodeID string, nodeStatus v1alpha1.ExecutableNodeStatus,
+	outputsFileRef storage.DataReference, varName string) (*core.LiteralMap, error) {
+
+	if inlined := nodeStatus.GetInlinedOutputs(); inlined != nil {
+		if _, ok := inlined.GetLiterals()[varName]; ok {
+			return inlined, nil
+		}
+	}
+
 	d := &core.LiteralMap{}
 	// TODO we should do a head before read and if head results in not found then fail
 	if err := store.ReadProtobuf(ctx, outputsFileRef, d); err != nil {
@@ -70,6 +102,12 @@ func resolveSubtaskOutput(ctx context.Context, store storage.ProtobufStore, node
 			outputsFileRef)
 	}
 
+	return d, nil
+}
+
+func resolveSubtaskOutput(d *core.LiteralMap, nodeID string, outputsFileRef storage.DataReference, idx int,
+	varName string) (*core.Literal, error) {
+
 	if d.Literals == nil {
 		return nil, errors.Errorf(errors.OutputsNotFoundError, nodeID,
 			"Outputs not found at [%v]", outputsFileRef)
@@ -95,15 +133,9 @@ func resolveSubtaskOutput(ctx context.Context, store storage.ProtobufStore, node
 	return literals[idx], nil
 }
 
-func resolveSingleOutput(ctx context.Context, store storage.ProtobufStore, nodeID string, outputsFileRef storage.DataReference,
+func resolveSingleOutput(d *core.LiteralMap, nodeID string, outputsFileRef storage.DataReference,
 	varName string) (*core.Literal, error) {
 
-	d := &core.LiteralMap{}
-	if err := store.ReadProtobuf(ctx, outputsFileRef, d); err != nil {
-		return nil, errors.Wrapf(errors.CausedByError, nodeID, err, "Failed to GetPrevious data from outputDir [%v]",
-			outputsFileRef)
-	}
-
 	if d.Literals == nil {
 		return nil, errors.Errorf(errors.OutputsNotFoundError, nodeID,
 			"Outputs not found at [%v]", outputsFileRef)
@@ -1,11 +1,19 @@
 package nodes
 
 import (
+	"context"
 	"testing"
 
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	mocks2 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
+	execMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCreateAliasMap(t *testing.T) {
@@ -28,3 +36,56 @@ func TestCreateAliasMap(t *testing.T) {
 		assert.Equal(t, map[string]string{}, m)
 	}
 }
+
+func TestRemoteFileOutputResolver_InlinedOutputs(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	// Nothing is written to the datastore, the outputs can only be resolved from the status.
+	s := &v1alpha1.NodeStatus{OutputDir: "s3://bucket/n1"}
+	s.SetInlinedOutputs(&core.LiteralMap{Literals: map[string]*core.Literal{"x": coreutils.MustMakePrimitiveLiteral(int64(5))}})
+
+	n := &mocks2.ExecutableNode{}
+	n.OnGetID().Return("n1")
+	n.OnGetOutputAlias().Return(nil)
+	nl := &execMocks.NodeLookup{}
+	nl.OnGetNodeExecutionStatusMatch(mock.Anything, "n1").Return(s)
+
+	l, err := NewRemoteFileOutputResolver(store).ExtractOutput(ctx, nl, n, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), l.GetScalar().GetPrimitive().GetInteger())
+
+	_, err = NewRemoteFileOutputResolver(store).ExtractOutput(ctx, nl, n, "y")
+	assert.Error(t, err)
+
+	// Outputs that were not inlined are read from the outputs file.
+	assert.NoError(t, store.WriteProtobuf(ctx, v1alpha1.GetOutputsFile(s.GetOutputDir()), storage.Options{}, &core.LiteralMap{
+		Literals: map[string]*core.Literal{
+			"x": coreutils.MustMakePrimitiveLiteral(int64(5)),
+			"y": coreutils.MustMakePrimitiveLiteral(int64(6)),
+		},
+	}))
+	l, err = NewRemoteFileOutputResolver(store).ExtractOutput(ctx, nl, n, "y")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), l.GetScalar().GetPrimitive().GetInteger())
+}
+
+func TestRemoteFileOutputResolver_PredicateSkipped(t *testing.T) {
+	ctx := context.TODO()
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	s := &v1alpha1.NodeStatus{OutputDir: "s3://bucket/n1"}
+	s.UpdatePhase(v1alpha1.NodePhaseSkipped, v1.Now(), "when predicate evaluated to false", nil)
+	s.SetPredicateSkipped()
+
+	n := &mocks2.ExecutableNode{}
+	n.OnGetID().Return("n1")
+	nl := &execMocks.NodeLookup{}
+	nl.OnGetNodeExecutionStatusMatch(mock.Anything, "n1").Return(s)
+
+	l, err := NewRemoteFileOutputResolver(store).ExtractOutput(ctx, nl, n, "x")
+	assert.NoError(t, err)
+	assert.NotNil(t, l.GetScalar().GetNoneType())
+}
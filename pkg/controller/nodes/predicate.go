@@ -4,12 +4,12 @@ import (
 	"context"
 	"time"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 // Special enum to indicate if the node under consideration is ready to be executed or should be skipped
@@ -20,7 +20,8 @@ const (
 	PredicatePhaseNotReady PredicatePhase = iota
 	// Indicates node is ready to be executed - execution should proceed
 	PredicatePhaseReady
-	// Indicates that the node execution should be skipped as one of its parents was skipped or the branch was not taken
+	// Indicates that the node execution should be skipped as one of its parents was skipped or the branch was not taken.
+	// Parents that were skipped by their when predicate do not cause their children to be skipped.
 	PredicatePhaseSkip
 	// Indicates failure during Predicate check
 	PredicatePhaseUndefined
@@ -92,6 +93,11 @@ func CanExecute(ctx context.Context, dag executors.DAGStructure, nl executors.No
 			}
 		}
 
+		// Nodes skipped by their own when predicate are complete, their consumers see their outputs as absent.
+		if upstreamNodeStatus.GetPhase() == v1alpha1.NodePhaseSkipped && upstreamNodeStatus.IsPredicateSkipped() {
+			continue
+		}
+
 		if upstreamNodeStatus.GetPhase() == v1alpha1.NodePhaseSkipped ||
 			upstreamNodeStatus.GetPhase() == v1alpha1.NodePhaseFailed ||
 			upstreamNodeStatus.GetPhase() == v1alpha1.NodePhaseTimedOut {
@@ -109,6 +115,26 @@ func CanExecute(ctx context.Context, dag executors.DAGStructure, nl executors.No
 	return PredicatePhaseReady, nil
 }
 
+// Returns true if all upstream nodes of the given node that did not complete successfully were skipped, as opposed to
+// failed or timed out. It is used to decide whether the skipped upstream policy of a node applies.
+func upstreamOnlySkipped(ctx context.Context, dag executors.DAGStructure, nl executors.NodeLookup, node v1alpha1.BaseNode) (
+	bool, error) {
+
+	upstreamNodes, err := dag.ToNode(node.GetID())
+	if err != nil {
+		return false, errors.Errorf(errors.BadSpecificationError, node.GetID(), "Unable to find upstream nodes for Node")
+	}
+
+	for _, upstreamNodeID := range upstreamNodes {
+		phase := nl.GetNodeExecutionStatus(ctx, upstreamNodeID).GetPhase()
+		if phase == v1alpha1.NodePhaseFailed || phase == v1alpha1.NodePhaseTimedOut {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func GetParentNodeMaxEndTime(ctx context.Context, dag executors.DAGStructure, nl executors.NodeLookup, node v1alpha1.BaseNode) (t v1.Time, err error) {
 	zeroTime := v1.NewTime(time.Time{})
 	nodeID := node.GetID()
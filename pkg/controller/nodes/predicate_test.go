@@ -220,6 +220,7 @@ func TestCanExecute(t *testing.T) {
 		mockN1.OnGetBranchNode().Return(nil)
 		mockN1Status := &mocks.ExecutableNodeStatus{}
 		mockN1Status.OnGetPhase().Return(v1alpha1.NodePhaseSkipped)
+		mockN1Status.OnIsPredicateSkipped().Return(false)
 		mockN1Status.OnIsDirty().Return(false)
 
 		mockWf := &mocks.ExecutableWorkflow{}
@@ -236,6 +237,42 @@ func TestCanExecute(t *testing.T) {
 		assert.Equal(t, PredicatePhaseNotReady, p)
 	})
 
+	t.Run("upstreamConnectionsOneSkippedByPredicate", func(t *testing.T) {
+		// Setup
+		mockN2Status := &mocks.ExecutableNodeStatus{}
+		// No parent node
+		mockN2Status.OnIsDirty().Return(false)
+
+		mockNode := &mocks.BaseNode{}
+		mockNode.OnGetID().Return(nodeN2)
+
+		mockN0 := &mocks.ExecutableNode{}
+		mockN0.OnGetBranchNode().Return(nil)
+		mockN0Status := &mocks.ExecutableNodeStatus{}
+		mockN0Status.OnGetPhase().Return(v1alpha1.NodePhaseSucceeded)
+		mockN0Status.OnIsDirty().Return(false)
+
+		mockN1 := &mocks.ExecutableNode{}
+		mockN1.OnGetBranchNode().Return(nil)
+		mockN1Status := &mocks.ExecutableNodeStatus{}
+		mockN1Status.OnGetPhase().Return(v1alpha1.NodePhaseSkipped)
+		mockN1Status.OnIsPredicateSkipped().Return(true)
+		mockN1Status.OnIsDirty().Return(false)
+
+		mockWf := &mocks.ExecutableWorkflow{}
+		mockWf.OnGetNodeExecutionStatus(ctx, nodeN0).Return(mockN0Status)
+		mockWf.OnGetNodeExecutionStatus(ctx, nodeN1).Return(mockN1Status)
+		mockWf.OnGetNodeExecutionStatus(ctx, nodeN2).Return(mockN2Status)
+		mockWf.OnToNode(nodeN2).Return(upstreamN2, nil)
+		mockWf.OnGetNode(nodeN0).Return(mockN0, true)
+		mockWf.OnGetNode(nodeN1).Return(mockN1, true)
+		mockWf.OnGetID().Return("w1")
+
+		p, err := CanExecute(ctx, mockWf, mockWf, mockNode)
+		assert.NoError(t, err)
+		assert.Equal(t, PredicatePhaseReady, p)
+	})
+
 	t.Run("upstreamConnectionsOneSkipped", func(t *testing.T) {
 		// Setup
 		mockN2Status := &mocks.ExecutableNodeStatus{}
@@ -255,6 +292,7 @@ func TestCanExecute(t *testing.T) {
 		mockN1.OnGetBranchNode().Return(nil)
 		mockN1Status := &mocks.ExecutableNodeStatus{}
 		mockN1Status.OnGetPhase().Return(v1alpha1.NodePhaseSkipped)
+		mockN1Status.OnIsPredicateSkipped().Return(false)
 		mockN1Status.OnIsDirty().Return(false)
 
 		mockWf := &mocks.ExecutableWorkflow{}
@@ -284,12 +322,14 @@ func TestCanExecute(t *testing.T) {
 		mockN0.OnGetBranchNode().Return(nil)
 		mockN0Status := &mocks.ExecutableNodeStatus{}
 		mockN0Status.OnGetPhase().Return(v1alpha1.NodePhaseSkipped)
+		mockN0Status.OnIsPredicateSkipped().Return(false)
 		mockN0Status.OnIsDirty().Return(false)
 
 		mockN1 := &mocks.ExecutableNode{}
 		mockN1.OnGetBranchNode().Return(nil)
 		mockN1Status := &mocks.ExecutableNodeStatus{}
 		mockN1Status.OnGetPhase().Return(v1alpha1.NodePhaseSkipped)
+		mockN1Status.OnIsPredicateSkipped().Return(false)
 		mockN1Status.OnIsDirty().Return(false)
 
 		mockWf := &mocks.ExecutableWorkflow{}
@@ -410,6 +450,7 @@ func TestCanExecute(t *testing.T) {
 		mockN1.OnGetBranchNode().Return(nil)
 		mockN1Status := &mocks.ExecutableNodeStatus{}
 		mockN1Status.OnGetPhase().Return(v1alpha1.NodePhaseSkipped)
+		mockN1Status.OnIsPredicateSkipped().Return(false)
 		mockN1Status.OnIsDirty().Return(false)
 
 		mockWf := &mocks.ExecutableWorkflow{}
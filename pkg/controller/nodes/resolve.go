@@ -7,10 +7,11 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
-	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
-func ResolveBindingData(ctx context.Context, outputResolver OutputResolver, nl executors.NodeLookup, bindingData *core.BindingData) (*core.Literal, error) {
+func ResolveBindingData(ctx context.Context, outputResolver OutputResolver, nl executors.NodeLookup,
+	execContext executors.ImmutableExecutionContext, bindingData *core.BindingData) (*core.Literal, error) {
 	logger.Debugf(ctx, "Resolving binding data")
 
 	literal := &core.Literal{}
@@ -24,7 +25,7 @@ func ResolveBindingData(ctx context.Context, outputResolver OutputResolver, nl e
 		logger.Debugf(ctx, "bindingData.GetValue() [%v] is of type Collection", bindingData.GetValue())
 		literalCollection := make([]*core.Literal, 0, len(bindingData.GetCollection().GetBindings()))
 		for _, b := range bindingData.GetCollection().GetBindings() {
-			l, err := ResolveBindingData(ctx, outputResolver, nl, b)
+			l, err := ResolveBindingData(ctx, outputResolver, nl, execContext, b)
 			if err != nil {
 				logger.Debugf(ctx, "Failed to resolve binding data. Error: [%v]", err)
 				return nil, err
@@ -42,7 +43,7 @@ func ResolveBindingData(ctx context.Context, outputResolver OutputResolver, nl e
 		logger.Debugf(ctx, "bindingData.GetValue() [%v] is of type Map", bindingData.GetValue())
 		literalMap := make(map[string]*core.Literal, len(bindingData.GetMap().GetBindings()))
 		for k, v := range bindingData.GetMap().GetBindings() {
-			l, err := ResolveBindingData(ctx, outputResolver, nl, v)
+			l, err := ResolveBindingData(ctx, outputResolver, nl, execContext, v)
 			if err != nil {
 				logger.Debugf(ctx, "Failed to resolve binding data. Error: [%v]", err)
 				return nil, err
@@ -62,6 +63,10 @@ func ResolveBindingData(ctx context.Context, outputResolver OutputResolver, nl e
 		upstreamNodeID := bindingData.GetPromise().GetNodeId()
 		bindToVar := bindingData.GetPromise().GetVar()
 
+		if upstreamNodeID == v1alpha1.SystemNodeID {
+			return resolveSystemBinding(ctx, execContext, bindToVar)
+		}
+
 		if nl == nil {
 			return nil, errors.Errorf(errors.IllegalStateError, upstreamNodeID,
 				"Trying to resolve output from previous node, without providing the workflow for variable [%s]",
@@ -87,13 +92,14 @@ func ResolveBindingData(ctx context.Context, outputResolver OutputResolver, nl e
 	return literal, nil
 }
 
-func Resolve(ctx context.Context, outputResolver OutputResolver, nl executors.NodeLookup, nodeID v1alpha1.NodeID, bindings []*v1alpha1.Binding) (*core.LiteralMap, error) {
+func Resolve(ctx context.Context, outputResolver OutputResolver, nl executors.NodeLookup,
+	execContext executors.ImmutableExecutionContext, nodeID v1alpha1.NodeID, bindings []*v1alpha1.Binding) (*core.LiteralMap, error) {
 	logger.Debugf(ctx, "bindings: [%v]", bindings)
 	literalMap := make(map[string]*core.Literal, len(bindings))
 	for _, binding := range bindings {
 		logger.Debugf(ctx, "Resolving binding: [%v]", binding)
 		varName := binding.GetVar()
-		l, err := ResolveBindingData(ctx, outputResolver, nl, binding.GetBinding())
+		l, err := ResolveBindingData(ctx, outputResolver, nl, execContext, binding.GetBinding())
 		if err != nil {
 			return nil, errors.Wrapf(errors.BindingResolutionError, nodeID, err, "Error binding Var [%v].[%v]", "wf", binding.GetVar())
 		}
@@ -3,9 +3,11 @@ package nodes
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/flyteorg/flyteidl/clients/go/coreutils"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	execMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -13,6 +15,7 @@ import (
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
@@ -96,6 +99,10 @@ func (d *dummyBaseWorkflow) GetCreationTimestamp() v1.Time {
 	return v1.Now()
 }
 
+func (d *dummyBaseWorkflow) GetScheduledAt() *v1.Time {
+	return nil
+}
+
 func (d *dummyBaseWorkflow) GetAnnotations() map[string]string {
 	return map[string]string{}
 }
@@ -239,7 +246,7 @@ func TestResolveBindingData(t *testing.T) {
 	t.Run("StaticBinding", func(t *testing.T) {
 		w := &dummyBaseWorkflow{}
 		b := utils.MustMakePrimitiveBindingData(1)
-		l, err := ResolveBindingData(ctx, nil, w, b)
+		l, err := ResolveBindingData(ctx, nil, w, nil, b)
 		assert.NoError(t, err)
 		flyteassert.EqualLiterals(t, coreutils.MustMakeLiteral(1), l)
 	})
@@ -251,7 +258,7 @@ func TestResolveBindingData(t *testing.T) {
 			},
 		}
 		b := utils.MakeBindingDataPromise("n1", "x")
-		_, err := ResolveBindingData(ctx, nil, w, b)
+		_, err := ResolveBindingData(ctx, nil, w, nil, b)
 		assert.Error(t, err)
 	})
 
@@ -259,7 +266,7 @@ func TestResolveBindingData(t *testing.T) {
 		store := createInmemoryDataStore(t, testScope.NewSubScope("1"))
 		r := remoteFileOutputResolver{store: store}
 		b := utils.MakeBindingDataPromise("n1", "x")
-		_, err := ResolveBindingData(ctx, r, w, b)
+		_, err := ResolveBindingData(ctx, r, w, nil, b)
 		assert.Error(t, err)
 	})
 
@@ -270,7 +277,7 @@ func TestResolveBindingData(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NoError(t, store.WriteProtobuf(ctx, outputPath, storage.Options{}, m))
 		b := utils.MakeBindingDataPromise("n1", "x")
-		_, err = ResolveBindingData(ctx, r, w, b)
+		_, err = ResolveBindingData(ctx, r, w, nil, b)
 		assert.Error(t, err)
 	})
 
@@ -282,14 +289,14 @@ func TestResolveBindingData(t *testing.T) {
 		assert.NoError(t, store.WriteProtobuf(ctx, outputPath, storage.Options{}, m))
 
 		b := utils.MakeBindingDataPromise("n2", "x")
-		l, err := ResolveBindingData(ctx, r, w, b)
+		l, err := ResolveBindingData(ctx, r, w, nil, b)
 		if assert.NoError(t, err) {
 			flyteassert.EqualLiterals(t, coreutils.MustMakeLiteral(1), l)
 		}
 	})
 
 	t.Run("NullBinding", func(t *testing.T) {
-		l, err := ResolveBindingData(ctx, nil, w, nil)
+		l, err := ResolveBindingData(ctx, nil, w, nil, nil)
 		assert.NoError(t, err)
 		assert.Nil(t, l)
 	})
@@ -301,7 +308,7 @@ func TestResolveBindingData(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NoError(t, store.WriteProtobuf(ctx, outputPath, storage.Options{}, m))
 		b := utils.MakeBindingDataPromise("n1", "x")
-		_, err = ResolveBindingData(ctx, r, nil, b)
+		_, err = ResolveBindingData(ctx, r, nil, nil, b)
 		assert.Error(t, err)
 	})
 
@@ -312,7 +319,7 @@ func TestResolveBindingData(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NoError(t, store.WriteProtobuf(ctx, outputPath, storage.Options{}, m))
 		b := utils.MakeBindingDataPromise("n2", "m")
-		l, err := ResolveBindingData(ctx, r, w, b)
+		l, err := ResolveBindingData(ctx, r, w, nil, b)
 		if assert.NoError(t, err) {
 			flyteassert.EqualLiterals(t, coreutils.MustMakeLiteral(1), l)
 		}
@@ -332,7 +339,7 @@ func TestResolveBindingData(t *testing.T) {
 			utils.NewPair("x", utils.MakeBindingDataPromise("n2", "x")),
 			utils.NewPair("z", utils.MustMakePrimitiveBindingData(5)),
 		)
-		l, err := ResolveBindingData(ctx, r, w, b)
+		l, err := ResolveBindingData(ctx, r, w, nil, b)
 		if assert.NoError(t, err) {
 			expected, err := coreutils.MakeLiteralMap(map[string]interface{}{"x": 1, "z": 5})
 			assert.NoError(t, err)
@@ -351,7 +358,7 @@ func TestResolveBindingData(t *testing.T) {
 			utils.NewPair("x", utils.MakeBindingDataPromise("n1", "x")),
 			utils.NewPair("z", utils.MustMakePrimitiveBindingData(5)),
 		)
-		_, err := ResolveBindingData(ctx, r, w, b)
+		_, err := ResolveBindingData(ctx, r, w, nil, b)
 		assert.Error(t, err)
 	})
 
@@ -368,10 +375,59 @@ func TestResolveBindingData(t *testing.T) {
 			utils.MakeBindingDataPromise("n1", "x"),
 			utils.MustMakePrimitiveBindingData(5),
 		)
-		_, err = ResolveBindingData(ctx, r, w, b)
+		_, err = ResolveBindingData(ctx, r, w, nil, b)
 		assert.Error(t, err)
 
 	})
+
+	t.Run("SystemBinding", func(t *testing.T) {
+		kickoffTime := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+		scheduledTime := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+		newExecContext := func(scheduledAt *v1.Time) *execMocks.ImmutableExecutionContext {
+			execContext := &execMocks.ImmutableExecutionContext{}
+			execContext.OnGetCreationTimestamp().Return(v1.NewTime(kickoffTime))
+			execContext.OnGetScheduledAt().Return(scheduledAt)
+			execContext.OnGetExecutionID().Return(v1alpha1.WorkflowExecutionIdentifier{
+				WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{Name: "exec-name"},
+			})
+			return execContext
+		}
+
+		scheduled := v1.NewTime(scheduledTime)
+		tests := []struct {
+			name        string
+			varName     string
+			scheduledAt *v1.Time
+			expected    *core.Literal
+		}{
+			{"kickoff-time", v1alpha1.SystemVarKickoffTime, &scheduled, coreutils.MustMakeLiteral(kickoffTime)},
+			{"scheduled-time", v1alpha1.SystemVarScheduledTime, &scheduled, coreutils.MustMakeLiteral(scheduledTime)},
+			{"not-scheduled", v1alpha1.SystemVarScheduledTime, nil, coreutils.MustMakeLiteral(kickoffTime)},
+			{"execution-id", v1alpha1.SystemVarExecutionID, nil, coreutils.MustMakeLiteral("exec-name")},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				b := utils.MakeBindingDataPromise(v1alpha1.SystemNodeID, tt.varName)
+				l, err := ResolveBindingData(ctx, nil, w, newExecContext(tt.scheduledAt), b)
+				if assert.NoError(t, err) {
+					assert.True(t, proto.Equal(tt.expected, l), "expected [%v], found [%v]", tt.expected, l)
+				}
+			})
+		}
+
+		t.Run("unknown", func(t *testing.T) {
+			b := utils.MakeBindingDataPromise(v1alpha1.SystemNodeID, "y")
+			_, err := ResolveBindingData(ctx, nil, w, newExecContext(nil), b)
+			assert.Error(t, err)
+		})
+
+		t.Run("no-execution-context", func(t *testing.T) {
+			b := utils.MakeBindingDataPromise(v1alpha1.SystemNodeID, v1alpha1.SystemVarKickoffTime)
+			_, err := ResolveBindingData(ctx, nil, w, nil, b)
+			assert.Error(t, err)
+		})
+	})
 }
 
 func TestResolve(t *testing.T) {
@@ -431,7 +487,7 @@ func TestResolve(t *testing.T) {
 		})
 		assert.NoError(t, err)
 
-		l, err := Resolve(ctx, r, w, "n2", b)
+		l, err := Resolve(ctx, r, w, nil, "n2", b)
 		if assert.NoError(t, err) {
 			assert.NotNil(t, l)
 			if assert.NoError(t, err) {
@@ -458,7 +514,7 @@ func TestResolve(t *testing.T) {
 			},
 		}
 
-		_, err := Resolve(ctx, r, w, "n2", b)
+		_, err := Resolve(ctx, r, w, nil, "n2", b)
 		assert.Error(t, err)
 	})
 
@@ -0,0 +1,108 @@
+package nodes
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/flytek8s"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
+)
+
+const bytesPerMiB = 1024 * 1024
+
+// Returns the resources requested by a task node. Node level resource overrides take precedence over the resources
+// specified on the task template container.
+func getRequestedResources(ctx context.Context, nCtx handler.NodeExecutionContext) (v1.ResourceList, error) {
+	if overrides := nCtx.Node().GetResources(); overrides != nil && len(overrides.Requests) > 0 {
+		return overrides.Requests, nil
+	}
+
+	tk, err := nCtx.TaskReader().Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if tk.GetContainer().GetResources() == nil {
+		return nil, nil
+	}
+
+	requests := v1.ResourceList{}
+	for _, r := range tk.GetContainer().GetResources().GetRequests() {
+		q, err := resource.ParseQuantity(r.GetValue())
+		if err != nil {
+			logger.Warnf(ctx, "Failed to parse requested resource [%v] quantity [%v]. Error: %v", r.GetName(), r.GetValue(), err)
+			continue
+		}
+
+		switch r.GetName() {
+		case core.Resources_CPU:
+			requests[v1.ResourceCPU] = q
+		case core.Resources_MEMORY:
+			requests[v1.ResourceMemory] = q
+		case core.Resources_GPU:
+			requests[flytek8s.ResourceNvidiaGPU] = q
+		}
+	}
+
+	return requests, nil
+}
+
+// Computes the resource usage of holding the given resources for the given duration.
+func computeResourceUsage(requests v1.ResourceList, duration time.Duration) v1alpha1.ResourceUsage {
+	seconds := int64(duration.Seconds())
+	usage := v1alpha1.ResourceUsage{}
+	if cpu, ok := requests[v1.ResourceCPU]; ok {
+		usage.CPUMilliCoreSeconds = cpu.MilliValue() * seconds
+	}
+
+	if mem, ok := requests[v1.ResourceMemory]; ok {
+		usage.MemoryMiBSeconds = (mem.Value() / bytesPerMiB) * seconds
+	}
+
+	if gpu, ok := requests[flytek8s.ResourceNvidiaGPU]; ok {
+		usage.GPUSeconds = gpu.Value() * seconds
+	}
+
+	return usage
+}
+
+// Records the resource usage of the attempt that just finished in the node status and emits the corresponding usage
+// metrics. Only task nodes hold resources, all other node kinds are ignored.
+func (c *nodeExecutor) recordAttemptResourceUsage(ctx context.Context, nCtx handler.NodeExecutionContext,
+	nodeStatus v1alpha1.ExecutableNodeStatus, attemptStartedAt time.Time, attemptStoppedAt time.Time) {
+
+	if !c.resourceUsageAccounting || nCtx.Node().GetKind() != v1alpha1.NodeKindTask {
+		return
+	}
+
+	requests, err := getRequestedResources(ctx, nCtx)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to read requested resources, skipping resource usage accounting. Error: %v", err)
+		return
+	}
+
+	duration, clamped := utils.ClampedDuration(attemptStartedAt, attemptStoppedAt)
+	if clamped {
+		c.metrics.NegativeDurations.Inc(ctx)
+		logger.Warnf(ctx, "Attempt stopped at [%v] before it started at [%v], accounting it as taking no time",
+			attemptStoppedAt, attemptStartedAt)
+	}
+
+	usage := computeResourceUsage(requests, duration)
+	nodeStatus.AddAttemptResourceUsage(v1alpha1.AttemptResourceUsage{
+		ResourceUsage:   usage,
+		Attempt:         nodeStatus.GetAttempts(),
+		DurationSeconds: int64(duration.Seconds()),
+	})
+
+	c.metrics.CPUUsage.Add(ctx, float64(usage.CPUMilliCoreSeconds))
+	c.metrics.MemoryUsage.Add(ctx, float64(usage.MemoryMiBSeconds))
+	c.metrics.GPUUsage.Add(ctx, float64(usage.GPUSeconds))
+}
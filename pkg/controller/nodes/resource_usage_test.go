@@ -0,0 +1,80 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/flytek8s"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	mocks2 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestComputeResourceUsage(t *testing.T) {
+	requests := v1.ResourceList{
+		v1.ResourceCPU:             resource.MustParse("500m"),
+		v1.ResourceMemory:          resource.MustParse("2Gi"),
+		flytek8s.ResourceNvidiaGPU: resource.MustParse("1"),
+	}
+
+	usage := computeResourceUsage(requests, time.Minute)
+	assert.Equal(t, v1alpha1.ResourceUsage{
+		CPUMilliCoreSeconds: 500 * 60,
+		MemoryMiBSeconds:    2048 * 60,
+		GPUSeconds:          60,
+	}, usage)
+
+	assert.True(t, computeResourceUsage(nil, time.Minute).IsZero())
+}
+
+func TestGetRequestedResources(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("task-template", func(t *testing.T) {
+		tr := &mocks.TaskReader{}
+		tr.OnRead(ctx).Return(&core.TaskTemplate{
+			Target: &core.TaskTemplate_Container{
+				Container: &core.Container{
+					Resources: &core.Resources{
+						Requests: []*core.Resources_ResourceEntry{
+							{Name: core.Resources_CPU, Value: "1"},
+							{Name: core.Resources_MEMORY, Value: "100Mi"},
+							{Name: core.Resources_GPU, Value: "invalid"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+		n := &mocks2.ExecutableNode{}
+		n.OnGetResources().Return(nil)
+		nCtx := &mocks.NodeExecutionContext{}
+		nCtx.OnNode().Return(n)
+		nCtx.OnTaskReader().Return(tr)
+
+		requests, err := getRequestedResources(ctx, nCtx)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 2)
+		assert.Equal(t, int64(1000), requests.Cpu().MilliValue())
+		assert.Equal(t, int64(100*1024*1024), requests.Memory().Value())
+	})
+
+	t.Run("node-overrides", func(t *testing.T) {
+		n := &mocks2.ExecutableNode{}
+		n.OnGetResources().Return(&v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+		})
+		nCtx := &mocks.NodeExecutionContext{}
+		nCtx.OnNode().Return(n)
+
+		requests, err := getRequestedResources(ctx, nCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2000), requests.Cpu().MilliValue())
+	})
+}
@@ -0,0 +1,39 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Returns the skip phase of a node that has not started yet and that an operator skipped, through the skip nodes
+// annotation of the workflow, and whether it was skipped. The node is marked as skipped by a predicate, so that its
+// consumers run with its outputs absent instead of being skipped as well. Invalid requests are logged and ignored.
+func (c *nodeExecutor) skipNode(ctx context.Context, nCtx handler.NodeExecutionContext) (handler.PhaseInfo, bool) {
+	requests, err := v1alpha1.GetNodeSkipRequests(nCtx.ExecutionContext().GetAnnotations())
+	if err != nil {
+		logger.Warnf(ctx, "Ignoring requests to skip nodes. Error: %v", err)
+		return handler.PhaseInfoUndefined, false
+	}
+
+	request, found := requests[nCtx.NodeExecutionMetadata().GetNodeExecutionID().GetNodeId()]
+	if !found {
+		return handler.PhaseInfoUndefined, false
+	}
+
+	if err := request.Validate(); err != nil {
+		logger.Warnf(ctx, "Ignoring invalid request to skip node. Error: %v", err)
+		return handler.PhaseInfoUndefined, false
+	}
+
+	reason := fmt.Sprintf("skipped by [%v]: %v", request.RequestedBy, request.Reason)
+	logger.Infof(ctx, "Node %v", reason)
+	nodeStatus := nCtx.NodeStatus()
+	nodeStatus.SetPredicateSkipped()
+	nodeStatus.SetOperatorSkipped(&v1alpha1.NodeSkip{NodeSkipRequest: request})
+	c.metrics.OperatorSkippedNodes.Inc(ctx)
+	return handler.PhaseInfoSkip(nil, "Node "+reason), true
+}
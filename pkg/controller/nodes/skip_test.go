@@ -0,0 +1,74 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	executorMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestNodeExecutor_skipNode(t *testing.T) {
+	ctx := context.TODO()
+	c := &nodeExecutor{
+		metrics: &nodeMetrics{
+			OperatorSkippedNodes: labeled.NewCounter("operator_skipped_nodes", "", promutils.NewTestScope()),
+		},
+	}
+
+	newContext := func(annotations map[string]string) (*mocks.NodeExecutionContext, *v1alpha1.NodeStatus) {
+		ec := &executorMocks.ExecutionContext{}
+		ec.OnGetAnnotations().Return(annotations)
+		md := &mocks.NodeExecutionMetadata{}
+		md.OnGetNodeExecutionID().Return(&core.NodeExecutionIdentifier{NodeId: "n1"})
+		s := &v1alpha1.NodeStatus{}
+		nCtx := &mocks.NodeExecutionContext{}
+		nCtx.OnExecutionContext().Return(ec)
+		nCtx.OnNodeExecutionMetadata().Return(md)
+		nCtx.OnNodeStatus().Return(s)
+		return nCtx, s
+	}
+
+	skipAnnotation := func(requests map[string]v1alpha1.NodeSkipRequest) map[string]string {
+		raw, err := json.Marshal(requests)
+		assert.NoError(t, err)
+		return map[string]string{v1alpha1.SkipNodesAnnotation: string(raw)}
+	}
+
+	t.Run("skipped", func(t *testing.T) {
+		request := v1alpha1.NodeSkipRequest{Reason: "broken", RequestedBy: "op"}
+		nCtx, s := newContext(skipAnnotation(map[string]v1alpha1.NodeSkipRequest{"n1": request}))
+		p, skipped := c.skipNode(ctx, nCtx)
+		assert.True(t, skipped)
+		assert.Equal(t, handler.EPhaseSkip, p.GetPhase())
+		assert.Contains(t, p.GetReason(), "broken")
+		assert.True(t, s.IsPredicateSkipped())
+		if assert.NotNil(t, s.GetOperatorSkipped()) {
+			assert.Equal(t, request, s.GetOperatorSkipped().NodeSkipRequest)
+			assert.False(t, s.GetOperatorSkipped().Reported)
+		}
+	})
+
+	t.Run("not-skipped", func(t *testing.T) {
+		for _, annotations := range []map[string]string{
+			nil,
+			{v1alpha1.SkipNodesAnnotation: "n1"},
+			skipAnnotation(map[string]v1alpha1.NodeSkipRequest{"n2": {Reason: "broken", RequestedBy: "op"}}),
+			skipAnnotation(map[string]v1alpha1.NodeSkipRequest{"n1": {Reason: "broken"}}),
+		} {
+			nCtx, s := newContext(annotations)
+			_, skipped := c.skipNode(ctx, nCtx)
+			assert.False(t, skipped)
+			assert.False(t, s.IsPredicateSkipped())
+			assert.Nil(t, s.GetOperatorSkipped())
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package nodes
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+)
+
+// startDelayRemaining returns how long a node that is ready to run still has to wait before it starts. The time at
+// which the node became ready is recorded in its status the first time, so that the delay is not restarted by the
+// following rounds, nor by restarts of propeller. Delayed nodes are evaluated again with their workflow, the delay is
+// honored at the granularity of the re-evaluation of workflows.
+func startDelayRemaining(nCtx handler.NodeExecutionContext, delay *v1alpha1.StartDelay, now time.Time) (time.Duration, error) {
+	var from time.Time
+	switch delay.GetFrom() {
+	case v1alpha1.StartDelayFromWorkflow:
+		from = nCtx.ExecutionContext().GetCreationTimestamp().Time
+	case v1alpha1.StartDelayFromUpstream:
+		nodeStatus := nCtx.NodeStatus()
+		if readyAt := nodeStatus.GetReadyAt(); readyAt != nil {
+			from = readyAt.Time
+		} else {
+			nodeStatus.SetReadyAt(metav1.NewTime(now))
+			from = now
+		}
+	default:
+		return 0, fmt.Errorf("unknown start delay reference [%v]", delay.From)
+	}
+
+	return from.Add(delay.Duration.Duration).Sub(now), nil
+}
@@ -0,0 +1,58 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	execMocks "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+	nodeHandlerMocks "github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+)
+
+func TestStartDelayRemaining(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 10, 0, 0, time.UTC)
+	createdAt := now.Add(-time.Minute)
+
+	newNodeExecContext := func(nodeStatus *v1alpha1.NodeStatus) *nodeHandlerMocks.NodeExecutionContext {
+		execContext := &execMocks.ExecutionContext{}
+		execContext.OnGetCreationTimestamp().Return(metav1.NewTime(createdAt))
+		nCtx := &nodeHandlerMocks.NodeExecutionContext{}
+		nCtx.OnExecutionContext().Return(execContext)
+		nCtx.OnNodeStatus().Return(nodeStatus)
+		return nCtx
+	}
+
+	t.Run("upstream", func(t *testing.T) {
+		nodeStatus := &v1alpha1.NodeStatus{}
+		delay := &v1alpha1.StartDelay{Duration: metav1.Duration{Duration: 5 * time.Minute}}
+		remaining, err := startDelayRemaining(newNodeExecContext(nodeStatus), delay, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, remaining)
+		assert.Equal(t, now, nodeStatus.GetReadyAt().Time)
+		assert.True(t, nodeStatus.IsDirty())
+
+		// The delay keeps counting from the time the node became ready.
+		remaining, err = startDelayRemaining(newNodeExecContext(nodeStatus), delay, now.Add(6*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, -time.Minute, remaining)
+		assert.Equal(t, now, nodeStatus.GetReadyAt().Time)
+	})
+
+	t.Run("workflow", func(t *testing.T) {
+		nodeStatus := &v1alpha1.NodeStatus{}
+		delay := &v1alpha1.StartDelay{Duration: metav1.Duration{Duration: 5 * time.Minute}, From: v1alpha1.StartDelayFromWorkflow}
+		remaining, err := startDelayRemaining(newNodeExecContext(nodeStatus), delay, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 4*time.Minute, remaining)
+		assert.Nil(t, nodeStatus.GetReadyAt())
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		delay := &v1alpha1.StartDelay{Duration: metav1.Duration{Duration: 5 * time.Minute}, From: "task"}
+		_, err := startDelayRemaining(newNodeExecContext(&v1alpha1.NodeStatus{}), delay, now)
+		assert.Error(t, err)
+	})
+}
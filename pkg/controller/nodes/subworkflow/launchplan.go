@@ -10,14 +10,15 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/common"
 
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/admin"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/storage"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 type launchPlanHandler struct {
@@ -74,6 +75,11 @@ func (l *launchPlanHandler) StartLaunchPlan(ctx context.Context, nCtx handler.No
 		}
 		if recovered != nil && recovered.Closure != nil && recovered.Closure.Phase == core.NodeExecution_SUCCEEDED {
 			if recovered.Closure.GetWorkflowNodeMetadata() != nil {
+				if info, ok := l.linkRecoveredChildExecution(ctx, nCtx, recovered); ok {
+					logger.Infof(ctx, "Linked recovered child execution [%s] instead of launching a new one", info.WorkflowNodeInfo.LaunchedWorkflowID.Name)
+					return handler.DoTransition(handler.TransitionTypeEphemeral, handler.PhaseInfoRecovered(info)), nil
+				}
+
 				launchCtx.RecoveryExecution = recovered.Closure.GetWorkflowNodeMetadata().ExecutionId
 			} else {
 				logger.Debugf(ctx, "Attempted to recovered workflow node execution [%+v] but was missing workflow node metadata", recovered.Id)
@@ -100,6 +106,39 @@ func (l *launchPlanHandler) StartLaunchPlan(ctx context.Context, nCtx handler.No
 	})), nil
 }
 
+// Reuses the child execution the node launched in the execution being recovered, which succeeded, by copying its
+// outputs to the node. Returns false if its outputs cannot be recovered, in which case the child is launched again in
+// recovery mode.
+func (l *launchPlanHandler) linkRecoveredChildExecution(ctx context.Context, nCtx handler.NodeExecutionContext, recovered *admin.NodeExecution) (*handler.ExecutionInfo, bool) {
+	info := &handler.ExecutionInfo{
+		WorkflowNodeInfo: &handler.WorkflowNodeInfo{LaunchedWorkflowID: recovered.Closure.GetWorkflowNodeMetadata().ExecutionId},
+	}
+
+	recoveredData, err := l.recoveryClient.RecoverNodeExecutionData(ctx, nCtx.ExecutionContext().GetExecutionConfig().RecoveryExecution.WorkflowExecutionIdentifier, nCtx.NodeExecutionMetadata().GetNodeExecutionID())
+	if err != nil {
+		logger.Warnf(ctx, "Failed to recover data of workflow node [%+v] with err [%+v]", nCtx.NodeExecutionMetadata().GetNodeExecutionID(), err)
+		return nil, false
+	}
+
+	outputFile := v1alpha1.GetOutputsFile(nCtx.NodeStatus().GetOutputDir())
+	if recoveredData.GetFullOutputs() != nil {
+		if err := nCtx.DataStore().WriteProtobuf(ctx, outputFile, storage.Options{}, recoveredData.GetFullOutputs()); err != nil {
+			logger.Warnf(ctx, "Failed to write recovered outputs of child execution to [%s], err %s", outputFile, err.Error())
+			return nil, false
+		}
+	} else if uri := recovered.Closure.GetOutputUri(); len(uri) > 0 {
+		if err := nCtx.DataStore().CopyRaw(ctx, storage.DataReference(uri), outputFile, storage.Options{}); err != nil {
+			logger.Warnf(ctx, "Failed to copy recovered outputs of child execution from [%s], err %s", uri, err.Error())
+			return nil, false
+		}
+	} else {
+		return info, true
+	}
+
+	info.OutputInfo = &handler.OutputInfo{OutputURI: outputFile}
+	return info, true
+}
+
 func (l *launchPlanHandler) CheckLaunchPlanStatus(ctx context.Context, nCtx handler.NodeExecutionContext) (handler.Transition, error) {
 	parentNodeExecutionID, err := getParentNodeExecutionID(nCtx)
 	if err != nil {
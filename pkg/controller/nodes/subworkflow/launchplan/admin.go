@@ -6,13 +6,12 @@ import (
 	"time"
 
 	"github.com/flyteorg/flytestdlib/cache"
+	lru "github.com/hashicorp/golang-lru"
 	"golang.org/x/time/rate"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/flyteorg/flytestdlib/errors"
 
-	"github.com/flyteorg/flytestdlib/logger"
-
 	"github.com/flyteorg/flytestdlib/promutils"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/admin"
@@ -20,6 +19,9 @@ import (
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/service"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	controllerErrors "github.com/flyteorg/flytepropeller/pkg/controller/errors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 var isRecovery = true
@@ -34,6 +36,8 @@ func IsWorkflowTerminated(p core.WorkflowExecution_Phase) bool {
 type adminLaunchPlanExecutor struct {
 	adminClient service.AdminServiceClient
 	cache       cache.AutoRefresh
+	// Closures of terminated executions that were fetched when their events were received, ahead of the cache sync.
+	terminated *lru.Cache
 }
 
 type executionCacheItem struct {
@@ -54,19 +58,22 @@ func (a *adminLaunchPlanExecutor) handleLaunchError(ctx context.Context, isRecov
 		logger.Warnf(ctx, "failed to recover workflow [%s] with err %+v. will attempt to launch instead", launchPlanRef.Name, err)
 		return nil
 	}
-	switch statusCode {
-	case codes.AlreadyExists:
+	if statusCode == codes.AlreadyExists {
 		_, err := a.cache.GetOrCreate(executionID.String(), executionCacheItem{WorkflowExecutionIdentifier: *executionID})
 		if err != nil {
 			logger.Errorf(ctx, "Failed to add ExecID [%v] to auto refresh cache", executionID)
 		}
 
 		return errors.Wrapf(RemoteErrorAlreadyExists, err, "ExecID %s already exists", executionID.Name)
-	case codes.DataLoss, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.Canceled:
+	}
+
+	// Launches that fail with retryable errors, e.g. admin being unavailable or throttling, are retried, while the others
+	// fail the node.
+	if controllerErrors.IsRetryable(err) {
 		return errors.Wrapf(RemoteErrorSystem, err, "failed to launch workflow [%s], system error", launchPlanRef.Name)
-	default:
-		return errors.Wrapf(RemoteErrorUser, err, "failed to launch workflow")
 	}
+
+	return errors.Wrapf(RemoteErrorUser, err, "failed to launch workflow")
 }
 
 func (a *adminLaunchPlanExecutor) Launch(ctx context.Context, launchCtx LaunchContext,
@@ -114,7 +121,7 @@ func (a *adminLaunchPlanExecutor) Launch(ctx context.Context, launchCtx LaunchCo
 
 	_, err = a.cache.GetOrCreate(executionID.String(), executionCacheItem{WorkflowExecutionIdentifier: *executionID})
 	if err != nil {
-		logger.Info(ctx, "Failed to add ExecID [%v] to auto refresh cache", executionID)
+		logger.Infof(ctx, "Failed to add ExecID [%v] to auto refresh cache", executionID)
 	}
 
 	return nil
@@ -125,6 +132,10 @@ func (a *adminLaunchPlanExecutor) GetStatus(ctx context.Context, executionID *co
 		return nil, fmt.Errorf("nil executionID")
 	}
 
+	if closure, ok := a.terminated.Get(executionID.String()); ok {
+		return closure.(*admin.ExecutionClosure), nil
+	}
+
 	obj, err := a.cache.GetOrCreate(executionID.String(), executionCacheItem{WorkflowExecutionIdentifier: *executionID})
 	if err != nil {
 		return nil, err
@@ -139,7 +150,7 @@ func (a *adminLaunchPlanExecutor) GetLaunchPlan(ctx context.Context, launchPlanR
 	if launchPlanRef == nil {
 		return nil, fmt.Errorf("launch plan reference is nil")
 	}
-	logger.Debugf(ctx, "Retrieving launch plan %s", *launchPlanRef)
+	logger.Debugf(ctx, "Retrieving launch plan %v", launchPlanRef)
 	getObjectRequest := admin.ObjectGetRequest{
 		Id: launchPlanRef,
 	}
@@ -184,6 +195,7 @@ func (a *adminLaunchPlanExecutor) syncItem(ctx context.Context, batch cache.Batc
 		if exec.ExecutionClosure != nil {
 			if IsWorkflowTerminated(exec.ExecutionClosure.Phase) {
 				logger.Debugf(ctx, "Workflow [%s] is already completed, will not fetch execution information", exec.ExecutionClosure.WorkflowId)
+				a.terminated.Remove(obj.GetID())
 				resp = append(resp, cache.ItemSyncResponse{
 					ID:     obj.GetID(),
 					Item:   exec,
@@ -193,6 +205,21 @@ func (a *adminLaunchPlanExecutor) syncItem(ctx context.Context, batch cache.Batc
 			}
 		}
 
+		// The execution terminated since the last sync and its closure was fetched when its event was received. The cache
+		// holds the closure from now on, so it is evicted from the terminated executions.
+		if closure, ok := a.terminated.Get(obj.GetID()); ok {
+			a.terminated.Remove(obj.GetID())
+			resp = append(resp, cache.ItemSyncResponse{
+				ID: obj.GetID(),
+				Item: executionCacheItem{
+					WorkflowExecutionIdentifier: exec.WorkflowExecutionIdentifier,
+					ExecutionClosure:            closure.(*admin.ExecutionClosure),
+				},
+				Action: cache.Update,
+			})
+			continue
+		}
+
 		// Workflow is not already terminated, lets check the status
 		req := &admin.WorkflowExecutionGetRequest{
 			Id: &exec.WorkflowExecutionIdentifier,
@@ -236,8 +263,14 @@ func (a *adminLaunchPlanExecutor) syncItem(ctx context.Context, batch cache.Batc
 
 func NewAdminLaunchPlanExecutor(_ context.Context, client service.AdminServiceClient,
 	syncPeriod time.Duration, cfg *AdminConfig, scope promutils.Scope) (FlyteAdmin, error) {
+	terminated, err := lru.New(cfg.MaxCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
 	exec := &adminLaunchPlanExecutor{
 		adminClient: client,
+		terminated:  terminated,
 	}
 
 	rateLimiter := &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(cfg.TPS), cfg.Burst)}
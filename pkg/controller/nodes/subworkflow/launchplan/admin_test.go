@@ -336,6 +336,25 @@ func TestAdminLaunchPlanExecutor_Launch(t *testing.T) {
 		assert.Error(t, err)
 		assert.False(t, IsAlreadyExists(err))
 	})
+
+	t.Run("system and user errors", func(t *testing.T) {
+		for code, isUserError := range map[codes.Code]bool{
+			codes.Unavailable:       false,
+			codes.ResourceExhausted: false,
+			codes.Internal:          false,
+			codes.InvalidArgument:   true,
+			codes.PermissionDenied:  true,
+		} {
+			mockClient := &mocks.AdminServiceClient{}
+			exec, err := NewAdminLaunchPlanExecutor(ctx, mockClient, time.Second, defaultAdminConfig, promutils.NewTestScope())
+			assert.NoError(t, err)
+			mockClient.On("CreateExecution", ctx, mock.Anything).Return(nil, status.Error(code, ""))
+
+			err = exec.Launch(ctx, LaunchContext{}, id, &core.Identifier{}, nil)
+			assert.Error(t, err)
+			assert.Equal(t, isUserError, IsUserError(err), code.String())
+		}
+	})
 }
 
 func TestAdminLaunchPlanExecutor_Kill(t *testing.T) {
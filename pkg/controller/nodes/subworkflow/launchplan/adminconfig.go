@@ -12,6 +12,9 @@ var (
 		Burst:        10,
 		MaxCacheSize: 10000,
 		Workers:      10,
+		Watch: WatchConfig{
+			Path: "/execution-events",
+		},
 	}
 
 	adminConfigSection = ctrlConfig.MustRegisterSubSection("admin-launcher", defaultAdminConfig)
@@ -31,6 +34,18 @@ type AdminConfig struct {
 	MaxCacheSize int `json:"cacheSize" pflag:",Maximum cache in terms of number of items stored."`
 
 	Workers int `json:"workers" pflag:",Number of parallel workers to work on the queue."`
+
+	Watch WatchConfig `json:"watch" pflag:",Config for receiving the events of child executions instead of waiting for their status to be polled."`
+}
+
+// WatchConfig controls receiving the workflow execution events admin publishes as CloudEvents, e.g. pushed over HTTP
+// by the subscription of its cloudevents topic. When a child execution terminates, its status is fetched right away and
+// its parent workflow enqueued, instead of waiting for the next sync of the launcher cache. The profiler port is not
+// authenticated, so senders have to pass a shared secret as a bearer token.
+type WatchConfig struct {
+	Enabled   bool   `json:"enabled" pflag:",Enables receiving the events of executions as CloudEvents on the profiler port."`
+	Path      string `json:"path" pflag:",Path of the profiler port that receives the CloudEvents of executions."`
+	TokenPath string `json:"token-path" pflag:",Path of the file with the shared secret senders of CloudEvents pass as a bearer token."`
 }
 
 func GetAdminConfig() *AdminConfig {
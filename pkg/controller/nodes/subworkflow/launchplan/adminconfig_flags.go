@@ -54,5 +54,8 @@ func (cfg AdminConfig) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "burst"), defaultAdminConfig.Burst, "Maximum burst for throttle")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "cacheSize"), defaultAdminConfig.MaxCacheSize, "Maximum cache in terms of number of items stored.")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "workers"), defaultAdminConfig.Workers, "Number of parallel workers to work on the queue.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "watch.enabled"), defaultAdminConfig.Watch.Enabled, "Enables receiving the events of executions as CloudEvents on the profiler port.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "watch.path"), defaultAdminConfig.Watch.Path, "Path of the profiler port that receives the CloudEvents of executions.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "watch.token-path"), defaultAdminConfig.Watch.TokenPath, "Path of the file with the shared secret senders of CloudEvents pass as a bearer token.")
 	return cmdFlags
 }
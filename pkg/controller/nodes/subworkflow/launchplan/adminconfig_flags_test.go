@@ -155,4 +155,46 @@ func TestAdminConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_watch.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("watch.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("watch.enabled"); err == nil {
+				testDecodeJson_AdminConfig(t, fmt.Sprintf("%v", vBool), &actual.Watch.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_watch.path", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("watch.path", testValue)
+			if vString, err := cmdFlags.GetString("watch.path"); err == nil {
+				testDecodeJson_AdminConfig(t, fmt.Sprintf("%v", vString), &actual.Watch.Path)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_watch.token-path", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("watch.token-path", testValue)
+			if vString, err := cmdFlags.GetString("watch.token-path"); err == nil {
+				testDecodeJson_AdminConfig(t, fmt.Sprintf("%v", vString), &actual.Watch.TokenPath)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
@@ -8,7 +8,8 @@ import (
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/admin"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
-	"github.com/flyteorg/flytestdlib/logger"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 type failFastWorkflowLauncher struct {
@@ -0,0 +1,243 @@
+package launchplan
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/admin"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Content type of CloudEvents in the structured mode of the HTTP binding, in which the attributes and the data of the
+// event are the body of the request. Any other content type is the content type of the data, in the binary mode.
+const structuredCloudEventContentType = "application/cloudevents+json"
+
+// Maximum size of the body of a CloudEvent.
+const maxCloudEventBytes = 1 << 20
+
+// EnqueueWorkflow enqueues the FlyteWorkflow of the execution for evaluation.
+type EnqueueWorkflow func(ctx context.Context, executionID *core.WorkflowExecutionIdentifier)
+
+type structuredCloudEvent struct {
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+	DataBase64      string          `json:"data_base64"`
+}
+
+type watchMetrics struct {
+	Events             prometheus.Counter
+	InvalidEvents      prometheus.Counter
+	UnauthorizedEvents prometheus.Counter
+	Refreshes          prometheus.Counter
+}
+
+// executionEventHandler receives the workflow execution events admin publishes as CloudEvents. When a child execution
+// launched by propeller terminates, its closure is fetched right away and the workflow of its parent enqueued, so the
+// parent node completes without waiting for the launcher cache to poll admin.
+type executionEventHandler struct {
+	launcher *adminLaunchPlanExecutor
+	enqueue  EnqueueWorkflow
+	token    []byte
+	metrics  watchMetrics
+}
+
+// Decodes the data of an event, an admin.WorkflowExecutionEventRequest in JSON or protobuf. A bare
+// event.WorkflowExecutionEvent is accepted in JSON as well.
+func decodeWorkflowExecutionEvent(contentType string, data []byte) (*event.WorkflowExecutionEvent, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && len(contentType) > 0 {
+		return nil, fmt.Errorf("invalid content type [%v]: %w", contentType, err)
+	}
+
+	request := &admin.WorkflowExecutionEventRequest{}
+	if strings.Contains(mediaType, "protobuf") {
+		if err := proto.Unmarshal(data, request); err != nil {
+			return nil, err
+		}
+
+		return request.GetEvent(), nil
+	}
+
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(data), request); err != nil {
+		return nil, err
+	}
+
+	if request.GetEvent() != nil {
+		return request.GetEvent(), nil
+	}
+
+	e := &event.WorkflowExecutionEvent{}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(data), e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Reads the workflow execution event of a CloudEvent, in the structured or the binary mode of the HTTP binding.
+func readWorkflowExecutionEvent(r *http.Request) (*event.WorkflowExecutionEvent, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxCloudEventBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, structuredCloudEventContentType) {
+		return decodeWorkflowExecutionEvent(contentType, body)
+	}
+
+	structured := structuredCloudEvent{}
+	if err := json.Unmarshal(body, &structured); err != nil {
+		return nil, err
+	}
+
+	if len(structured.DataBase64) > 0 {
+		data, err := base64.StdEncoding.DecodeString(structured.DataBase64)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeWorkflowExecutionEvent(structured.DataContentType, data)
+	}
+
+	return decodeWorkflowExecutionEvent(structured.DataContentType, structured.Data)
+}
+
+// Returns true if the request carries the shared secret of the handler as its bearer token.
+func (h executionEventHandler) isAuthorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), h.token) == 1
+}
+
+func (h executionEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.isAuthorized(r) {
+		h.metrics.UnauthorizedEvents.Inc()
+		http.Error(w, "invalid or missing bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	h.metrics.Events.Inc()
+	e, err := readWorkflowExecutionEvent(r)
+	if err != nil {
+		h.metrics.InvalidEvents.Inc()
+		logger.Warnf(ctx, "Received an invalid workflow execution event. Error: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if e.GetExecutionId() == nil || !IsWorkflowTerminated(e.GetPhase()) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Failures are returned to the sender, so that it delivers the event again.
+	if err := h.refresh(ctx, e.GetExecutionId()); err != nil {
+		logger.Warnf(ctx, "Failed to refresh terminated execution [%v]. Error: %v", e.GetExecutionId(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Fetches the closure of a terminated execution and enqueues the workflow of its parent. Executions that were not
+// launched by this propeller, which are not in the launcher cache, and executions the cache already knows terminated
+// are ignored.
+func (h executionEventHandler) refresh(ctx context.Context, executionID *core.WorkflowExecutionIdentifier) error {
+	item, err := h.launcher.cache.Get(executionID.String())
+	if err != nil {
+		return nil
+	}
+
+	if closure := item.(executionCacheItem).ExecutionClosure; closure != nil && IsWorkflowTerminated(closure.GetPhase()) {
+		return nil
+	}
+
+	if _, found := h.launcher.terminated.Get(executionID.String()); found {
+		return nil
+	}
+
+	execution, err := h.launcher.adminClient.GetExecution(ctx, &admin.WorkflowExecutionGetRequest{Id: executionID})
+	if err != nil {
+		return err
+	}
+
+	if !IsWorkflowTerminated(execution.GetClosure().GetPhase()) {
+		logger.Debugf(ctx, "Execution [%v] is not terminated yet according to admin", executionID)
+		return nil
+	}
+
+	h.launcher.terminated.Add(executionID.String(), execution.GetClosure())
+	h.metrics.Refreshes.Inc()
+
+	if parent := execution.GetSpec().GetMetadata().GetParentNodeExecution().GetExecutionId(); parent != nil {
+		logger.Infof(ctx, "Child execution [%v] terminated, enqueueing parent [%v]", executionID.GetName(), parent.GetName())
+		h.enqueue(ctx, parent)
+	}
+
+	return nil
+}
+
+// NewExecutionEventHandler returns the handler of the CloudEvents of workflow executions, which enqueues the parents of
+// the child executions the launcher launched when they terminate. The launcher has to be the admin launcher. Only
+// requests that pass the given shared secret as their bearer token are accepted.
+func NewExecutionEventHandler(launcher FlyteAdmin, enqueue EnqueueWorkflow, token []byte, scope promutils.Scope) (
+	http.Handler, error) {
+
+	adminLauncher, ok := launcher.(*adminLaunchPlanExecutor)
+	if !ok {
+		return nil, fmt.Errorf("execution events require the admin launcher, found [%T]", launcher)
+	}
+
+	if len(token) == 0 {
+		return nil, fmt.Errorf("execution events require a shared secret")
+	}
+
+	return executionEventHandler{
+		launcher: adminLauncher,
+		enqueue:  enqueue,
+		token:    token,
+		metrics: watchMetrics{
+			Events:             scope.MustNewCounter("execution_events", "Number of workflow execution events received"),
+			InvalidEvents:      scope.MustNewCounter("invalid_execution_events", "Number of workflow execution events that could not be read"),
+			UnauthorizedEvents: scope.MustNewCounter("unauthorized_execution_events", "Number of workflow execution events rejected for a missing or wrong token"),
+			Refreshes:          scope.MustNewCounter("terminated_refreshes", "Number of child executions refreshed when they terminated"),
+		},
+	}, nil
+}
+
+// ReadWatchToken reads the shared secret senders of execution events have to pass, see WatchConfig.
+func ReadWatchToken(cfg WatchConfig) ([]byte, error) {
+	if len(cfg.TokenPath) == 0 {
+		return nil, fmt.Errorf("a token path is required to receive execution events")
+	}
+
+	raw, err := ioutil.ReadFile(cfg.TokenPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(raw), nil
+}
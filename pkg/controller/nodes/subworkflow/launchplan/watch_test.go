@@ -0,0 +1,200 @@
+package launchplan
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/admin/mocks"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/admin"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/cache"
+	cacheMocks "github.com/flyteorg/flytestdlib/cache/mocks"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newExecutionEventRequest(t *testing.T, id *core.WorkflowExecutionIdentifier, phase core.WorkflowExecution_Phase) string {
+	raw, err := (&jsonpb.Marshaler{}).MarshalToString(&admin.WorkflowExecutionEventRequest{
+		Event: &event.WorkflowExecutionEvent{ExecutionId: id, Phase: phase},
+	})
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestExecutionEventHandler(t *testing.T) {
+	ctx := context.TODO()
+	child := &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "child"}
+	parent := &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "parent"}
+	closure := &admin.ExecutionClosure{Phase: core.WorkflowExecution_SUCCEEDED}
+
+	setup := func(t *testing.T) (*mocks.AdminServiceClient, FlyteAdmin, http.Handler, *[]*core.WorkflowExecutionIdentifier) {
+		mockClient := &mocks.AdminServiceClient{}
+		exec, err := NewAdminLaunchPlanExecutor(ctx, mockClient, time.Hour, defaultAdminConfig, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		enqueued := &[]*core.WorkflowExecutionIdentifier{}
+		handler, err := NewExecutionEventHandler(exec, func(ctx context.Context, id *core.WorkflowExecutionIdentifier) {
+			*enqueued = append(*enqueued, id)
+		}, []byte("secret"), promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		_, err = exec.(*adminLaunchPlanExecutor).cache.GetOrCreate(child.String(),
+			executionCacheItem{WorkflowExecutionIdentifier: *child})
+		assert.NoError(t, err)
+		return mockClient, exec, handler, enqueued
+	}
+
+	postWithToken := func(handler http.Handler, token string, contentType string, body []byte) int {
+		r := httptest.NewRequest(http.MethodPost, "/execution-events", bytes.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		if len(token) > 0 {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	post := func(handler http.Handler, contentType string, body []byte) int {
+		return postWithToken(handler, "secret", contentType, body)
+	}
+
+	t.Run("terminated", func(t *testing.T) {
+		mockClient, exec, handler, enqueued := setup(t)
+		mockClient.OnGetExecutionMatch(mock.Anything, mock.MatchedBy(func(o *admin.WorkflowExecutionGetRequest) bool {
+			return proto.Equal(o.Id, child)
+		})).Return(&admin.Execution{
+			Id:      child,
+			Closure: closure,
+			Spec: &admin.ExecutionSpec{Metadata: &admin.ExecutionMetadata{
+				ParentNodeExecution: &core.NodeExecutionIdentifier{NodeId: "n", ExecutionId: parent},
+			}},
+		}, nil).Once()
+
+		body := newExecutionEventRequest(t, child, core.WorkflowExecution_SUCCEEDED)
+		assert.Equal(t, http.StatusNoContent, post(handler, "application/json", []byte(body)))
+		assert.Equal(t, []*core.WorkflowExecutionIdentifier{parent}, *enqueued)
+
+		s, err := exec.GetStatus(ctx, child)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(closure, s))
+
+		// Redelivered events don't reach admin again.
+		assert.Equal(t, http.StatusNoContent, post(handler, "application/json", []byte(body)))
+		mockClient.AssertExpectations(t)
+
+		// Once the launcher cache holds the closure, it is evicted from the terminated executions.
+		launcher := exec.(*adminLaunchPlanExecutor)
+		item, err := launcher.cache.Get(child.String())
+		assert.NoError(t, err)
+		iwMock := &cacheMocks.ItemWrapper{}
+		iwMock.OnGetItem().Return(item)
+		iwMock.OnGetID().Return(child.String())
+		resp, err := launcher.syncItem(ctx, cache.Batch{iwMock})
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(closure, resp[0].Item.(executionCacheItem).ExecutionClosure))
+		_, found := launcher.terminated.Get(child.String())
+		assert.False(t, found)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockClient, _, handler, enqueued := setup(t)
+		body := newExecutionEventRequest(t, child, core.WorkflowExecution_SUCCEEDED)
+		assert.Equal(t, http.StatusUnauthorized, postWithToken(handler, "", "application/json", []byte(body)))
+		assert.Equal(t, http.StatusUnauthorized, postWithToken(handler, "other", "application/json", []byte(body)))
+		assert.Empty(t, *enqueued)
+		mockClient.AssertNotCalled(t, "GetExecution", mock.Anything, mock.Anything)
+	})
+
+	t.Run("structured", func(t *testing.T) {
+		mockClient, _, handler, enqueued := setup(t)
+		mockClient.OnGetExecutionMatch(mock.Anything, mock.Anything).Return(&admin.Execution{
+			Id:      child,
+			Closure: closure,
+			Spec: &admin.ExecutionSpec{Metadata: &admin.ExecutionMetadata{
+				ParentNodeExecution: &core.NodeExecutionIdentifier{NodeId: "n", ExecutionId: parent},
+			}},
+		}, nil)
+
+		raw, err := proto.Marshal(&admin.WorkflowExecutionEventRequest{
+			Event: &event.WorkflowExecutionEvent{ExecutionId: child, Phase: core.WorkflowExecution_FAILED},
+		})
+		assert.NoError(t, err)
+		body := fmt.Sprintf(`{"specversion":"1.0","type":"com.flyte.resource.workflow","source":"admin","id":"1",`+
+			`"datacontenttype":"application/x-protobuf","data_base64":"%v"}`, base64.StdEncoding.EncodeToString(raw))
+		assert.Equal(t, http.StatusNoContent, post(handler, structuredCloudEventContentType, []byte(body)))
+		assert.Equal(t, []*core.WorkflowExecutionIdentifier{parent}, *enqueued)
+	})
+
+	t.Run("not terminated", func(t *testing.T) {
+		mockClient, _, handler, enqueued := setup(t)
+		body := newExecutionEventRequest(t, child, core.WorkflowExecution_RUNNING)
+		assert.Equal(t, http.StatusNoContent, post(handler, "application/json", []byte(body)))
+		assert.Empty(t, *enqueued)
+		mockClient.AssertNotCalled(t, "GetExecution", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown execution", func(t *testing.T) {
+		mockClient, _, handler, enqueued := setup(t)
+		other := &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "other"}
+		body := newExecutionEventRequest(t, other, core.WorkflowExecution_SUCCEEDED)
+		assert.Equal(t, http.StatusNoContent, post(handler, "application/json", []byte(body)))
+		assert.Empty(t, *enqueued)
+		mockClient.AssertNotCalled(t, "GetExecution", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, handler, _ := setup(t)
+		assert.Equal(t, http.StatusBadRequest, post(handler, "application/json", []byte("{")))
+	})
+
+	t.Run("admin failure", func(t *testing.T) {
+		mockClient, _, handler, enqueued := setup(t)
+		mockClient.OnGetExecutionMatch(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("unavailable"))
+		body := newExecutionEventRequest(t, child, core.WorkflowExecution_SUCCEEDED)
+		assert.Equal(t, http.StatusInternalServerError, post(handler, "application/json", []byte(body)))
+		assert.Empty(t, *enqueued)
+	})
+}
+
+func TestNewExecutionEventHandler_NotAdmin(t *testing.T) {
+	_, err := NewExecutionEventHandler(NewFailFastLaunchPlanExecutor(), nil, []byte("secret"), promutils.NewTestScope())
+	assert.Error(t, err)
+}
+
+func TestNewExecutionEventHandler_NoToken(t *testing.T) {
+	exec, err := NewAdminLaunchPlanExecutor(context.TODO(), &mocks.AdminServiceClient{}, time.Hour, defaultAdminConfig,
+		promutils.NewTestScope())
+	assert.NoError(t, err)
+	_, err = NewExecutionEventHandler(exec, nil, nil, promutils.NewTestScope())
+	assert.Error(t, err)
+}
+
+func TestReadWatchToken(t *testing.T) {
+	_, err := ReadWatchToken(WatchConfig{})
+	assert.Error(t, err)
+
+	f, err := ioutil.TempFile("", "token")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("secret\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	token, err := ReadWatchToken(WatchConfig{TokenPath: f.Name()})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), token)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
@@ -203,6 +204,7 @@ func TestSubWorkflowHandler_StartLaunchPlan(t *testing.T) {
 				},
 			},
 		}, nil)
+		recoveryClient.On("RecoverNodeExecutionData", mock.Anything, recoveredExecID, mock.Anything).Return(nil, fmt.Errorf("not found"))
 
 		h := launchPlanHandler{
 			launchPlan:     mockLPExec,
@@ -262,7 +264,81 @@ func TestSubWorkflowHandler_StartLaunchPlan(t *testing.T) {
 		s, err := h.StartLaunchPlan(ctx, nCtx)
 		assert.NoError(t, err)
 		assert.Equal(t, s.Info().GetPhase(), handler.EPhaseRunning)
-		assert.Equal(t, len(recoveryClient.Calls), 1)
+		assert.Equal(t, len(recoveryClient.Calls), 2)
+	})
+
+	t.Run("link recovered child execution", func(t *testing.T) {
+		recoveredExecID := &core.WorkflowExecutionIdentifier{
+			Project: "p",
+			Domain:  "d",
+			Name:    "n",
+		}
+		childExecID := &core.WorkflowExecutionIdentifier{
+			Project: "p",
+			Domain:  "d",
+			Name:    "child",
+		}
+
+		recoveryClient := recoveryMocks.RecoveryClient{}
+		recoveryClient.On("RecoverNodeExecution", mock.Anything, recoveredExecID, mock.Anything).Return(&admin.NodeExecution{
+			Closure: &admin.NodeExecutionClosure{
+				Phase: core.NodeExecution_SUCCEEDED,
+				TargetMetadata: &admin.NodeExecutionClosure_WorkflowNodeMetadata{
+					WorkflowNodeMetadata: &admin.WorkflowNodeMetadata{
+						ExecutionId: childExecID,
+					},
+				},
+			},
+		}, nil)
+		outputs := coreutils.MustMakeLiteral(map[string]interface{}{"x": 1}).GetMap()
+		recoveryClient.On("RecoverNodeExecutionData", mock.Anything, recoveredExecID, mock.Anything).Return(&admin.NodeExecutionGetDataResponse{
+			FullOutputs: outputs,
+		}, nil)
+
+		mockLPExec := &mocks.Executor{}
+		h := launchPlanHandler{
+			launchPlan:     mockLPExec,
+			recoveryClient: &recoveryClient,
+		}
+
+		ds := createInmemoryStore(t)
+		ns := &mocks2.ExecutableNodeStatus{}
+		ns.OnGetOutputDir().Return("s3://bucket/n/0")
+
+		nCtx := &mocks3.NodeExecutionContext{}
+		ir := &mocks4.InputReader{}
+		ir.OnGetMatch(mock.Anything).Return(&core.LiteralMap{}, nil)
+		nCtx.OnInputReader().Return(ir)
+		nm := &mocks3.NodeExecutionMetadata{}
+		nm.OnGetNodeExecutionID().Return(&core.NodeExecutionIdentifier{
+			ExecutionId: wfExecID,
+			NodeId:      "n",
+		})
+		nCtx.OnNodeExecutionMetadata().Return(nm)
+		ectx := &execMocks.ExecutionContext{}
+		ectx.OnGetEventVersion().Return(1)
+		ectx.OnGetParentInfo().Return(nil)
+		ectx.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{
+			RecoveryExecution: v1alpha1.WorkflowExecutionIdentifier{
+				WorkflowExecutionIdentifier: recoveredExecID,
+			},
+		})
+		nCtx.OnExecutionContext().Return(ectx)
+		nCtx.OnCurrentAttempt().Return(uint32(1))
+		nCtx.OnNode().Return(mockNode)
+		nCtx.OnNodeStatus().Return(ns)
+		nCtx.OnDataStore().Return(ds)
+
+		s, err := h.StartLaunchPlan(ctx, nCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseRecovered, s.Info().GetPhase())
+		assert.Equal(t, childExecID, s.Info().GetInfo().WorkflowNodeInfo.LaunchedWorkflowID)
+		assert.Equal(t, storage.DataReference("s3://bucket/n/0/outputs.pb"), s.Info().GetInfo().OutputInfo.OutputURI)
+		mockLPExec.AssertNotCalled(t, "Launch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		actual := &core.LiteralMap{}
+		assert.NoError(t, ds.ReadProtobuf(ctx, "s3://bucket/n/0/outputs.pb", actual))
+		assert.True(t, proto.Equal(outputs, actual))
 	})
 }
 
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/common"
-	"github.com/flyteorg/flytestdlib/logger"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flytestdlib/storage"
@@ -14,6 +13,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 // Subworkflow handler handles inline subWorkflows
@@ -74,6 +74,13 @@ func (s *subworkflowHandler) handleSubWorkflow(ctx context.Context, nCtx handler
 	}
 
 	if state.HasFailed() {
+		if isEligibleForRetry(nCtx) {
+			// The node executor aborts the subworkflow and clears the status of its nodes. The next attempt then runs all of
+			// them again, with the data dirs derived from the output dir of that attempt.
+			logger.Infof(ctx, "Subworkflow [%s] failed, retrying it. Error: %v", subworkflow.GetID(), state.Err)
+			return handler.DoTransition(handler.TransitionTypeEphemeral, handler.PhaseInfoRetryableFailureErr(state.Err, nil)), nil
+		}
+
 		workflowNodeState := handler.WorkflowNodeState{
 			Phase: v1alpha1.WorkflowNodePhaseFailing,
 			Error: state.Err,
@@ -130,6 +137,19 @@ func (s *subworkflowHandler) handleSubWorkflow(ctx context.Context, nCtx handler
 	return handler.DoTransition(handler.TransitionTypeEphemeral, handler.PhaseInfoRunning(nil)), nil
 }
 
+// Returns true if the subworkflow node has attempts left in its retry budget, in which case the whole subworkflow is
+// retried instead of handling its failure.
+func isEligibleForRetry(nCtx handler.NodeExecutionContext) bool {
+	retryStrategy := nCtx.Node().GetRetryStrategy()
+	if retryStrategy == nil || retryStrategy.MinAttempts == nil {
+		return false
+	}
+
+	status := nCtx.NodeStatus()
+	currentAttempt := (status.GetAttempts() + 1) - status.GetSystemFailures()
+	return currentAttempt < uint32(*retryStrategy.MinAttempts)
+}
+
 func (s *subworkflowHandler) getExecutionContextForDownstream(nCtx handler.NodeExecutionContext) (executors.ExecutionContext, error) {
 	newParentInfo, err := common.CreateParentInfo(nCtx.ExecutionContext().GetParentInfo(), nCtx.NodeID(), nCtx.CurrentAttempt())
 	if err != nil {
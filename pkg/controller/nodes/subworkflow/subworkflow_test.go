@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/common"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -164,3 +167,71 @@ func Test_subworkflowHandler_HandleAbort(t *testing.T) {
 		assert.NoError(t, s.HandleAbort(ctx, nCtx, "reason"))
 	})
 }
+
+func Test_subworkflowHandler_handleSubWorkflow_Retry(t *testing.T) {
+	ctx := context.TODO()
+	execErr := &core.ExecutionError{Code: "code", Message: "failed", Kind: core.ExecutionError_USER}
+
+	newNodeContext := func(minAttempts *int, attempts uint32) (*mocks.NodeExecutionContext, *mocks.NodeStateWriter) {
+		node := &coreMocks.ExecutableNode{}
+		node.OnGetRetryStrategy().Return(&v1alpha1.RetryStrategy{MinAttempts: minAttempts})
+
+		ns := &coreMocks.ExecutableNodeStatus{}
+		ns.OnGetAttempts().Return(attempts)
+		ns.OnGetSystemFailures().Return(0)
+
+		ectx := &execMocks.ExecutionContext{}
+		ectx.OnGetParentInfo().Return(nil)
+
+		nsw := &mocks.NodeStateWriter{}
+		nsw.OnPutWorkflowNodeStateMatch(mock.Anything).Return(nil)
+
+		nCtx := &mocks.NodeExecutionContext{}
+		nCtx.OnNode().Return(node)
+		nCtx.OnNodeStatus().Return(ns)
+		nCtx.OnNodeID().Return("n1")
+		nCtx.OnCurrentAttempt().Return(attempts)
+		nCtx.OnExecutionContext().Return(ectx)
+		nCtx.OnNodeStateWriter().Return(nsw)
+		return nCtx, nsw
+	}
+
+	newHandler := func() (subworkflowHandler, *coreMocks.ExecutableSubWorkflow) {
+		swf := &coreMocks.ExecutableSubWorkflow{}
+		swf.OnGetID().Return("swf")
+		swf.OnStartNode().Return(&coreMocks.ExecutableNode{})
+		swf.OnGetOnFailureNode().Return(nil)
+
+		nodeExec := &execMocks.Node{}
+		nodeExec.OnRecursiveNodeHandlerMatch(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(executors.NodeStatusFailed(execErr), nil)
+		return newSubworkflowHandler(nodeExec), swf
+	}
+
+	t.Run("retry", func(t *testing.T) {
+		attempts := 3
+		nCtx, nsw := newNodeContext(&attempts, 1)
+		s, swf := newHandler()
+		trns, err := s.handleSubWorkflow(ctx, nCtx, swf, &execMocks.NodeLookup{})
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseRetryableFailure, trns.Info().GetPhase())
+		assert.Equal(t, execErr, trns.Info().GetErr())
+		nsw.AssertNotCalled(t, "PutWorkflowNodeState", mock.Anything)
+	})
+
+	t.Run("retries-exhausted", func(t *testing.T) {
+		attempts := 3
+		nCtx, _ := newNodeContext(&attempts, 2)
+		s, swf := newHandler()
+		trns, err := s.handleSubWorkflow(ctx, nCtx, swf, &execMocks.NodeLookup{})
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseFailed, trns.Info().GetPhase())
+	})
+
+	t.Run("no-retries", func(t *testing.T) {
+		nCtx, _ := newNodeContext(nil, 0)
+		s, swf := newHandler()
+		trns, err := s.handleSubWorkflow(ctx, nCtx, swf, &execMocks.NodeLookup{})
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseFailed, trns.Info().GetPhase())
+	})
+}
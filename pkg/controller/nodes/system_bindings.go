@@ -0,0 +1,41 @@
+package nodes
+
+import (
+	"context"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Resolves a binding to an output of the reserved system node, i.e. a value that describes the execution itself rather
+// than the output of an upstream node.
+func resolveSystemBinding(ctx context.Context, execContext executors.ImmutableExecutionContext, varName string) (
+	*core.Literal, error) {
+
+	if execContext == nil {
+		return nil, errors.Errorf(errors.IllegalStateError, v1alpha1.SystemNodeID,
+			"Trying to resolve system variable [%s], without providing the execution context", varName)
+	}
+
+	logger.Debugf(ctx, "Resolving system variable [%v]", varName)
+	switch varName {
+	case v1alpha1.SystemVarKickoffTime:
+		return coreutils.MakePrimitiveLiteral(execContext.GetCreationTimestamp().UTC())
+	case v1alpha1.SystemVarScheduledTime:
+		if scheduledAt := execContext.GetScheduledAt(); scheduledAt != nil && !scheduledAt.IsZero() {
+			return coreutils.MakePrimitiveLiteral(scheduledAt.UTC())
+		}
+
+		return coreutils.MakePrimitiveLiteral(execContext.GetCreationTimestamp().UTC())
+	case v1alpha1.SystemVarExecutionID:
+		return coreutils.MakePrimitiveLiteral(execContext.GetExecutionID().GetName())
+	}
+
+	return nil, errors.Errorf(errors.BadSpecificationError, v1alpha1.SystemNodeID,
+		"Unknown system variable [%s]", varName)
+}
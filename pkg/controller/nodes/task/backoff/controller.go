@@ -9,10 +9,10 @@ import (
 
 	stdAtomic "github.com/flyteorg/flytestdlib/atomic"
 
-	"github.com/flyteorg/flytestdlib/logger"
-
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 // Controller is a name-spaced collection of back-off handlers
@@ -4,10 +4,10 @@ import (
 	"context"
 	"time"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"k8s.io/apimachinery/pkg/util/cache"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 type BarrierKey = string
@@ -49,6 +49,16 @@ func (b *barrier) GetPreviousBarrierTransition(ctx context.Context, k BarrierKey
 	return NoBarrierTransition
 }
 
+// Clear drops all the recorded transitions. Plugins are called again for nodes whose transitions were dropped, just
+// like they are after a restart.
+func (b *barrier) Clear() {
+	if b.barrierEnabled {
+		for _, k := range b.barrierTransitions.Keys() {
+			b.barrierTransitions.Remove(k)
+		}
+	}
+}
+
 func newLRUBarrier(_ context.Context, cfg config.BarrierConfig) *barrier {
 	b := &barrier{
 		barrierEnabled: cfg.Enabled,
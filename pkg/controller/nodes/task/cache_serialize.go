@@ -0,0 +1,188 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	catalogLineage "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog/datacatalog"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Key of the task config that makes concurrent executions of a cached task with the same cache key run one at a time.
+// The other executions wait for the outputs of the one that runs, instead of computing the same outputs concurrently.
+const taskConfigCacheSerializeKey = "cache_serialize"
+
+// Maximum number of reservations whose last heartbeat is remembered.
+const maxReservationHeartbeats = 10000
+
+func isCacheSerializable(tk *core.TaskTemplate) bool {
+	serialize, err := strconv.ParseBool(tk.GetConfig()[taskConfigCacheSerializeKey])
+	return err == nil && serialize && tk.GetMetadata().GetDiscoverable()
+}
+
+// Returns the owner of the catalog reservations of the node. It is the same for all attempts of the node, so that a
+// retry does not wait for the reservation held by the attempt before it to expire.
+func getReservationOwnerID(nCtx handler.NodeExecutionContext) string {
+	id := nCtx.NodeExecutionMetadata().GetNodeExecutionID()
+	return fmt.Sprintf("%s-%s-%s-%s", id.GetExecutionId().GetProject(), id.GetExecutionId().GetDomain(),
+		id.GetExecutionId().GetName(), id.GetNodeId())
+}
+
+// Returns the reserver of the catalog client, if the task is cache serializable and the client supports reservations.
+func (t Handler) getReserver(tk *core.TaskTemplate) (catalogLineage.Reserver, bool) {
+	reserver, ok := t.catalog.(catalogLineage.Reserver)
+	if !ok || !isCacheSerializable(tk) {
+		return nil, false
+	}
+
+	return reserver, true
+}
+
+// Reads the task, if the catalog client supports reservations. Returns false if the task is not cache serializable.
+func (t Handler) readCacheSerializableTask(ctx context.Context, tr pluginCore.TaskReader) (*core.TaskTemplate, bool) {
+	if _, ok := t.catalog.(catalogLineage.Reserver); !ok {
+		return nil, false
+	}
+
+	tk, err := tr.Read(ctx)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to read TaskTemplate. Error: %v", err)
+		return nil, false
+	}
+
+	return tk, isCacheSerializable(tk)
+}
+
+// Reserves the execution of the cache key of the task for the owner. Executions of tasks that are not cache serializable
+// always acquire the reservation.
+func (t Handler) reserveCatalogCache(ctx context.Context, tk *core.TaskTemplate, inputReader io.InputReader,
+	ownerID string) (datacatalog.Reservation, error) {
+
+	reserver, ok := t.getReserver(tk)
+	if !ok {
+		return datacatalog.Reservation{State: datacatalog.ReservationAcquired, OwnerID: ownerID}, nil
+	}
+
+	key, err := NewCatalogKey(ctx, tk, inputReader)
+	if err != nil {
+		return datacatalog.Reservation{}, err
+	}
+
+	reservation, err := reserver.GetOrReserve(ctx, key, ownerID)
+	if err != nil {
+		return datacatalog.Reservation{}, err
+	}
+
+	if reservation.State == datacatalog.ReservationAcquired {
+		t.reservationHeartbeats.Add(ownerID, struct{}{}, catalogLineage.GetConfig().Reservation.HeartbeatInterval.Duration)
+	}
+
+	return reservation, nil
+}
+
+// Reserves the cache key of an execution that missed the cache. Returns true, along with the transition to return, if
+// the execution has to wait, because another execution holds the reservation or because the outputs were just cached.
+// The owner of the reservation the node waits for is kept in its state, so that changes of owner are logged once.
+func (t Handler) waitForCatalogReservation(ctx context.Context, tCtx *taskExecutionContext,
+	nCtx handler.NodeExecutionContext) (handler.Transition, bool, error) {
+
+	tk, err := tCtx.tr.Read(ctx)
+	if err != nil {
+		return handler.UnknownTransition, false, err
+	}
+
+	reservation, err := t.reserveCatalogCache(ctx, tk, nCtx.InputReader(), getReservationOwnerID(nCtx))
+	if err != nil {
+		return handler.UnknownTransition, false, errors.Wrapf(errors.CatalogCallFailed, nCtx.NodeID(), err,
+			"failed to reserve the cache key of the task")
+	}
+
+	ts := nCtx.NodeStateReader().GetTaskNodeState()
+	previousOwner := ts.ReservationOwner
+	var reason string
+	switch reservation.State {
+	case datacatalog.ReservationAcquired:
+		if len(previousOwner) > 0 {
+			ts.ReservationOwner = ""
+			if err := nCtx.NodeStateWriter().PutTaskNodeState(ts); err != nil {
+				return handler.UnknownTransition, false, err
+			}
+		}
+
+		return handler.UnknownTransition, false, nil
+	case datacatalog.ReservationCached:
+		ts.ReservationOwner = ""
+		reason = "Outputs were cached by a concurrent execution, looking them up again"
+	default:
+		ts.ReservationOwner = reservation.OwnerID
+		reason = fmt.Sprintf("Waiting for the outputs of [%v], which holds the cache reservation", reservation.OwnerID)
+	}
+
+	if err := nCtx.NodeStateWriter().PutTaskNodeState(ts); err != nil {
+		return handler.UnknownTransition, false, err
+	}
+
+	if ts.ReservationOwner != previousOwner || len(ts.ReservationOwner) == 0 {
+		logger.Infof(ctx, "Catalog CacheSerialize: %v", reason)
+	} else {
+		logger.Debugf(ctx, "Catalog CacheSerialize: %v", reason)
+	}
+
+	return handler.DoTransition(handler.TransitionTypeEphemeral, handler.PhaseInfoQueued(reason)), true, nil
+}
+
+// Extends the reservation held by the owner, if it was not extended within the heartbeat interval. Failures are logged,
+// the reservation is extended again in the next round.
+func (t Handler) heartbeatCatalogReservation(ctx context.Context, tr pluginCore.TaskReader, inputReader io.InputReader,
+	ownerID string) {
+
+	tk, ok := t.readCacheSerializableTask(ctx, tr)
+	if !ok {
+		return
+	}
+
+	if _, recent := t.reservationHeartbeats.Get(ownerID); recent {
+		return
+	}
+
+	key, err := NewCatalogKey(ctx, tk, inputReader)
+	if err == nil {
+		err = t.catalog.(catalogLineage.Reserver).ExtendReservation(ctx, key, ownerID)
+	}
+
+	if err != nil {
+		logger.Warnf(ctx, "Failed to extend the catalog reservation of [%v]. Error: %v", ownerID, err)
+		return
+	}
+
+	t.reservationHeartbeats.Add(ownerID, struct{}{}, catalogLineage.GetConfig().Reservation.HeartbeatInterval.Duration)
+}
+
+// Releases the reservation held by the owner, so that executions waiting for it do not wait for it to expire. Failures
+// are logged, the reservation expires eventually.
+func (t Handler) releaseCatalogReservation(ctx context.Context, tr pluginCore.TaskReader, inputReader io.InputReader,
+	ownerID string) {
+
+	tk, ok := t.readCacheSerializableTask(ctx, tr)
+	if !ok {
+		return
+	}
+
+	t.reservationHeartbeats.Remove(ownerID)
+	key, err := NewCatalogKey(ctx, tk, inputReader)
+	if err == nil {
+		err = t.catalog.(catalogLineage.Reserver).ReleaseReservation(ctx, key, ownerID)
+	}
+
+	if err != nil {
+		logger.Warnf(ctx, "Failed to release the catalog reservation of [%v]. Error: %v", ownerID, err)
+	}
+}
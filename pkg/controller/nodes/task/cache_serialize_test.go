@@ -0,0 +1,186 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	pluginCoreMocks "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core/mocks"
+	ioMocks "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	nodeMocks "github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
+	catalogLineage "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog/datacatalog"
+)
+
+type fakeReserverCatalog struct {
+	catalogLineage.NOOPCatalog
+	reservation datacatalog.Reservation
+	reserved    []string
+}
+
+func (f *fakeReserverCatalog) GetOrReserve(_ context.Context, _ catalog.Key, ownerID string) (datacatalog.Reservation, error) {
+	f.reserved = append(f.reserved, ownerID)
+	return f.reservation, nil
+}
+
+func (f *fakeReserverCatalog) ExtendReservation(context.Context, catalog.Key, string) error {
+	return nil
+}
+
+func (f *fakeReserverCatalog) ReleaseReservation(context.Context, catalog.Key, string) error {
+	return nil
+}
+
+func newCacheSerializableTask(serialize string) *core.TaskTemplate {
+	return &core.TaskTemplate{
+		Id:        &core.Identifier{Name: "task"},
+		Metadata:  &core.TaskMetadata{Discoverable: true, DiscoveryVersion: "1"},
+		Interface: &core.TypedInterface{},
+		Config:    map[string]string{taskConfigCacheSerializeKey: serialize},
+	}
+}
+
+func Test_isCacheSerializable(t *testing.T) {
+	assert.True(t, isCacheSerializable(newCacheSerializableTask("true")))
+	assert.False(t, isCacheSerializable(newCacheSerializableTask("false")))
+	assert.False(t, isCacheSerializable(newCacheSerializableTask("maybe")))
+
+	notCached := newCacheSerializableTask("true")
+	notCached.Metadata.Discoverable = false
+	assert.False(t, isCacheSerializable(notCached))
+}
+
+func TestHandler_reserveCatalogCache(t *testing.T) {
+	ctx := context.TODO()
+	ir := &ioMocks.InputReader{}
+	ir.OnGetMatch(ctx).Return(&core.LiteralMap{}, nil)
+
+	t.Run("catalog without reservations", func(t *testing.T) {
+		h := Handler{catalog: catalogLineage.NOOPCatalog{}, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		reservation, err := h.reserveCatalogCache(ctx, newCacheSerializableTask("true"), ir, "owner")
+		assert.NoError(t, err)
+		assert.Equal(t, datacatalog.ReservationAcquired, reservation.State)
+	})
+
+	t.Run("task not serializable", func(t *testing.T) {
+		c := &fakeReserverCatalog{reservation: datacatalog.Reservation{State: datacatalog.ReservationInProgress}}
+		h := Handler{catalog: c, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		reservation, err := h.reserveCatalogCache(ctx, newCacheSerializableTask("false"), ir, "owner")
+		assert.NoError(t, err)
+		assert.Equal(t, datacatalog.ReservationAcquired, reservation.State)
+		assert.Empty(t, c.reserved)
+	})
+
+	t.Run("reservation held by another owner", func(t *testing.T) {
+		c := &fakeReserverCatalog{reservation: datacatalog.Reservation{State: datacatalog.ReservationInProgress, OwnerID: "other"}}
+		h := Handler{catalog: c, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		reservation, err := h.reserveCatalogCache(ctx, newCacheSerializableTask("true"), ir, "owner")
+		assert.NoError(t, err)
+		assert.Equal(t, datacatalog.ReservationInProgress, reservation.State)
+		assert.Equal(t, []string{"owner"}, c.reserved)
+		_, heartbeat := h.reservationHeartbeats.Get("owner")
+		assert.False(t, heartbeat)
+	})
+
+	t.Run("reservation acquired", func(t *testing.T) {
+		c := &fakeReserverCatalog{reservation: datacatalog.Reservation{State: datacatalog.ReservationAcquired, OwnerID: "owner"}}
+		h := Handler{catalog: c, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		reservation, err := h.reserveCatalogCache(ctx, newCacheSerializableTask("true"), ir, "owner")
+		assert.NoError(t, err)
+		assert.Equal(t, datacatalog.ReservationAcquired, reservation.State)
+		_, heartbeat := h.reservationHeartbeats.Get("owner")
+		assert.True(t, heartbeat)
+	})
+}
+
+func TestHandler_waitForCatalogReservation(t *testing.T) {
+	ctx := context.TODO()
+	ir := &ioMocks.InputReader{}
+	ir.OnGetMatch(ctx).Return(&core.LiteralMap{}, nil)
+
+	newContexts := func(state handler.TaskNodeState) (*taskExecutionContext, *nodeMocks.NodeExecutionContext, *[]handler.TaskNodeState) {
+		tr := &pluginCoreMocks.TaskReader{}
+		tr.OnReadMatch(mock.Anything).Return(newCacheSerializableTask("true"), nil)
+
+		metadata := &nodeMocks.NodeExecutionMetadata{}
+		metadata.OnGetNodeExecutionID().Return(&core.NodeExecutionIdentifier{
+			NodeId:      "n1",
+			ExecutionId: &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "e"},
+		})
+
+		reader := &nodeMocks.NodeStateReader{}
+		reader.OnGetTaskNodeState().Return(state)
+
+		var written []handler.TaskNodeState
+		writer := &nodeMocks.NodeStateWriter{}
+		writer.OnPutTaskNodeStateMatch(mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			written = append(written, args.Get(0).(handler.TaskNodeState))
+		})
+
+		nCtx := &nodeMocks.NodeExecutionContext{}
+		nCtx.OnInputReader().Return(ir)
+		nCtx.OnNodeExecutionMetadata().Return(metadata)
+		nCtx.OnNodeStateReader().Return(reader)
+		nCtx.OnNodeStateWriter().Return(writer)
+		nCtx.OnNodeID().Return("n1")
+		return &taskExecutionContext{tr: tr}, nCtx, &written
+	}
+
+	t.Run("waiting", func(t *testing.T) {
+		c := &fakeReserverCatalog{reservation: datacatalog.Reservation{State: datacatalog.ReservationInProgress, OwnerID: "other"}}
+		h := Handler{catalog: c, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		tCtx, nCtx, written := newContexts(handler.TaskNodeState{})
+
+		trns, waiting, err := h.waitForCatalogReservation(ctx, tCtx, nCtx)
+		assert.NoError(t, err)
+		assert.True(t, waiting)
+		assert.Equal(t, handler.EPhaseQueued, trns.Info().GetPhase())
+		assert.Equal(t, []string{"p-d-e-n1"}, c.reserved)
+		if assert.Len(t, *written, 1) {
+			assert.Equal(t, "other", (*written)[0].ReservationOwner)
+		}
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		c := &fakeReserverCatalog{reservation: datacatalog.Reservation{State: datacatalog.ReservationCached}}
+		h := Handler{catalog: c, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		tCtx, nCtx, written := newContexts(handler.TaskNodeState{ReservationOwner: "other"})
+
+		_, waiting, err := h.waitForCatalogReservation(ctx, tCtx, nCtx)
+		assert.NoError(t, err)
+		assert.True(t, waiting)
+		if assert.Len(t, *written, 1) {
+			assert.Empty(t, (*written)[0].ReservationOwner)
+		}
+	})
+
+	t.Run("acquired after waiting", func(t *testing.T) {
+		c := &fakeReserverCatalog{reservation: datacatalog.Reservation{State: datacatalog.ReservationAcquired, OwnerID: "p-d-e-n1"}}
+		h := Handler{catalog: c, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		tCtx, nCtx, written := newContexts(handler.TaskNodeState{ReservationOwner: "other"})
+
+		_, waiting, err := h.waitForCatalogReservation(ctx, tCtx, nCtx)
+		assert.NoError(t, err)
+		assert.False(t, waiting)
+		if assert.Len(t, *written, 1) {
+			assert.Empty(t, (*written)[0].ReservationOwner)
+		}
+	})
+
+	t.Run("acquired", func(t *testing.T) {
+		c := &fakeReserverCatalog{reservation: datacatalog.Reservation{State: datacatalog.ReservationAcquired, OwnerID: "p-d-e-n1"}}
+		h := Handler{catalog: c, reservationHeartbeats: cache.NewLRUExpireCache(10)}
+		tCtx, nCtx, written := newContexts(handler.TaskNodeState{})
+
+		_, waiting, err := h.waitForCatalogReservation(ctx, tCtx, nCtx)
+		assert.NoError(t, err)
+		assert.False(t, waiting)
+		assert.Empty(t, *written)
+	})
+}
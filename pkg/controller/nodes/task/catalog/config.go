@@ -3,10 +3,12 @@ package catalog
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
 	"github.com/flyteorg/flytestdlib/config"
 
+	"github.com/flyteorg/flytepropeller/pkg/controller/grpcclient"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog/datacatalog"
 )
 
@@ -16,7 +18,23 @@ const ConfigSectionKey = "catalog-cache"
 
 var (
 	defaultConfig = &Config{
-		Type: NoOpDiscoveryType,
+		Type:           NoOpDiscoveryType,
+		CacheKeyMode:   CacheKeyModeVersion,
+		TagHashVersion: datacatalog.TagHashVersionV1,
+		Lineage: LineageConfig{
+			Enabled:                false,
+			DefaultSamplingPercent: 0,
+			DomainSamplingPercent:  map[string]int{},
+		},
+		Prefetch: PrefetchConfig{
+			Enabled:     false,
+			Concurrency: 10,
+			TTL:         config.Duration{Duration: 5 * time.Minute},
+			MaxEntries:  10000,
+		},
+		Reservation: ReservationConfig{
+			HeartbeatInterval: config.Duration{Duration: 10 * time.Second},
+		},
 	}
 
 	configSection = config.MustRegisterSection(ConfigSectionKey, defaultConfig)
@@ -29,11 +47,59 @@ const (
 	DataCatalogType   DiscoveryType = "datacatalog"
 )
 
+type CacheKeyMode = string
+
+const (
+	// CacheKeyModeVersion keys cached outputs by the cache version and the interface of the task.
+	CacheKeyModeVersion CacheKeyMode = "version"
+	// CacheKeyModeSignature additionally keys cached outputs by the container image, command and args of the task, so
+	// that changing a task without bumping its cache version does not serve stale outputs. Switching to it misses every
+	// output that was cached before.
+	CacheKeyModeSignature CacheKeyMode = "signature"
+)
+
 type Config struct {
-	Type        DiscoveryType   `json:"type" pflag:"\"noop\", Catalog Implementation to use"`
-	Endpoint    string          `json:"endpoint" pflag:"\"\", Endpoint for catalog service"`
-	Insecure    bool            `json:"insecure" pflag:"false, Use insecure grpc connection"`
-	MaxCacheAge config.Duration `json:"max-cache-age" pflag:", Cache entries past this age will incur cache miss. 0 means cache never expires"`
+	Type         DiscoveryType   `json:"type" pflag:"\"noop\", Catalog Implementation to use"`
+	Endpoint     string          `json:"endpoint" pflag:"\"\", Endpoint for catalog service"`
+	Insecure     bool            `json:"insecure" pflag:"false, Use insecure grpc connection"`
+	MaxCacheAge  config.Duration `json:"max-cache-age" pflag:", Cache entries past this age will incur cache miss. 0 means cache never expires"`
+	Lineage      LineageConfig   `json:"lineage" pflag:",Config for recording the lineage of outputs of tasks that are not cached"`
+	CacheKeyMode CacheKeyMode    `json:"cache-key-mode" pflag:",Whether cached outputs are keyed by the cache version and interface of tasks only, or also by their container spec"`
+	// Maps task names to the max cache age of their cache entries, overriding MaxCacheAge. Tasks can also set their own
+	// max cache age in the cache_max_age key of their task config, which takes precedence over both.
+	TaskMaxCacheAge map[string]config.Duration `json:"task-max-cache-age" pflag:"-,Max cache age per task name, overrides max-cache-age"`
+	Prefetch        PrefetchConfig             `json:"prefetch" pflag:",Config for looking up the cached outputs of dynamic sub-nodes before they run"`
+	Reservation     ReservationConfig          `json:"reservation" pflag:",Config for serializing the executions of cache serializable tasks"`
+	// Cached outputs are looked up with the tag of this version first and then with the tags of the older versions, so
+	// that the version can be upgraded without missing the outputs cached before.
+	TagHashVersion datacatalog.TagHashVersion `json:"tag-hash-version" pflag:",Version of the hash function the tags of cached outputs are written with"`
+}
+
+// ReservationConfig controls how executions of cache serializable tasks hold their catalog reservations. Tasks opt in to
+// serialization with the cache_serialize key of their task config. The execution that holds the reservation of a cache
+// key extends it every heartbeat interval, while the other executions with the same cache key wait for its outputs.
+type ReservationConfig struct {
+	HeartbeatInterval config.Duration `json:"heartbeat-interval" pflag:",How often the execution holding a reservation extends it"`
+}
+
+// PrefetchConfig controls whether the cached outputs of the task nodes of dynamic workflows are looked up in bulk when
+// the dynamic workflow is built, instead of one at a time as each sub-node starts. Only sub-nodes whose inputs are known
+// when the workflow is built are prefetched.
+type PrefetchConfig struct {
+	Enabled     bool            `json:"enabled" pflag:",Enables prefetching the cached outputs of dynamic sub-nodes"`
+	Concurrency int             `json:"concurrency" pflag:",Maximum number of concurrent artifact lookups of a prefetch"`
+	TTL         config.Duration `json:"ttl" pflag:",How long prefetched cache hits are kept in memory"`
+	MaxEntries  int             `json:"max-entries" pflag:",Maximum number of prefetched cache hits kept in memory"`
+}
+
+// LineageConfig controls whether the outputs of tasks that are not cached are registered in the catalog as untagged
+// artifacts. Executions are sampled per domain, so that lineage can be recorded for all executions in production while
+// keeping the load on the catalog low for development domains.
+type LineageConfig struct {
+	Enabled                bool `json:"enabled" pflag:",Enables recording the outputs of tasks that are not cached in the catalog"`
+	DefaultSamplingPercent int  `json:"default-sampling-percent" pflag:",Percentage of executions to record lineage for, in domains without a specific sampling percentage"`
+	// Maps domains to the percentage of executions to record lineage for.
+	DomainSamplingPercent map[string]int `json:"domain-sampling-percent" pflag:"-,Percentage of executions to record lineage for, per domain"`
 }
 
 // Gets loaded config for Discovery
@@ -46,7 +112,26 @@ func NewCatalogClient(ctx context.Context) (catalog.Client, error) {
 
 	switch catalogConfig.Type {
 	case DataCatalogType:
-		return datacatalog.NewDataCatalog(ctx, catalogConfig.Endpoint, catalogConfig.Insecure, catalogConfig.MaxCacheAge.Duration)
+		client, err := datacatalog.NewDataCatalog(ctx, catalogConfig.Endpoint, catalogConfig.Insecure, catalogConfig.MaxCacheAge.Duration,
+			grpcclient.NewFactory(grpcclient.GetConfig()))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := client.SetTagHashVersion(catalogConfig.TagHashVersion); err != nil {
+			return nil, err
+		}
+
+		if catalogConfig.Prefetch.Enabled {
+			client.EnablePrefetch(catalogConfig.Prefetch.Concurrency, catalogConfig.Prefetch.TTL.Duration,
+				catalogConfig.Prefetch.MaxEntries)
+		}
+
+		if !catalogConfig.Lineage.Enabled {
+			return client, nil
+		}
+
+		return NewLineageClient(client, catalogConfig.Lineage), nil
 	case NoOpDiscoveryType, "":
 		return NOOPCatalog{}, nil
 	}
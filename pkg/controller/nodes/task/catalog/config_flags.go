@@ -54,5 +54,14 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "endpoint"), defaultConfig.Endpoint, " Endpoint for catalog service")
 	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "insecure"), defaultConfig.Insecure, " Use insecure grpc connection")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "max-cache-age"), defaultConfig.MaxCacheAge.String(), " Cache entries past this age will incur cache miss. 0 means cache never expires")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "lineage.enabled"), defaultConfig.Lineage.Enabled, "Enables recording the outputs of tasks that are not cached in the catalog")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "lineage.default-sampling-percent"), defaultConfig.Lineage.DefaultSamplingPercent, "Percentage of executions to record lineage for,  in domains without a specific sampling percentage")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "cache-key-mode"), defaultConfig.CacheKeyMode, "Whether cached outputs are keyed by the cache version and interface of tasks only,  or also by their container spec")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "prefetch.enabled"), defaultConfig.Prefetch.Enabled, "Enables prefetching the cached outputs of dynamic sub-nodes")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "prefetch.concurrency"), defaultConfig.Prefetch.Concurrency, "Maximum number of concurrent artifact lookups of a prefetch")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "prefetch.ttl"), defaultConfig.Prefetch.TTL.String(), "How long prefetched cache hits are kept in memory")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "prefetch.max-entries"), defaultConfig.Prefetch.MaxEntries, "Maximum number of prefetched cache hits kept in memory")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "reservation.heartbeat-interval"), defaultConfig.Reservation.HeartbeatInterval.String(), "How often the execution holding a reservation extends it")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "tag-hash-version"), defaultConfig.TagHashVersion, "Version of the hash function the tags of cached outputs are written with")
 	return cmdFlags
 }
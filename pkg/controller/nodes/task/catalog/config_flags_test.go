@@ -155,4 +155,130 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_lineage.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("lineage.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("lineage.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Lineage.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_lineage.default-sampling-percent", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("lineage.default-sampling-percent", testValue)
+			if vInt, err := cmdFlags.GetInt("lineage.default-sampling-percent"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Lineage.DefaultSamplingPercent)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_cache-key-mode", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("cache-key-mode", testValue)
+			if vString, err := cmdFlags.GetString("cache-key-mode"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.CacheKeyMode)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_prefetch.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("prefetch.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("prefetch.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Prefetch.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_prefetch.concurrency", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("prefetch.concurrency", testValue)
+			if vInt, err := cmdFlags.GetInt("prefetch.concurrency"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Prefetch.Concurrency)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_prefetch.ttl", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Prefetch.TTL.String()
+
+			cmdFlags.Set("prefetch.ttl", testValue)
+			if vString, err := cmdFlags.GetString("prefetch.ttl"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Prefetch.TTL)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_prefetch.max-entries", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("prefetch.max-entries", testValue)
+			if vInt, err := cmdFlags.GetInt("prefetch.max-entries"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Prefetch.MaxEntries)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_reservation.heartbeat-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Reservation.HeartbeatInterval.String()
+
+			cmdFlags.Set("reservation.heartbeat-interval", testValue)
+			if vString, err := cmdFlags.GetString("reservation.heartbeat-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Reservation.HeartbeatInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_tag-hash-version", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("tag-hash-version", testValue)
+			if vString, err := cmdFlags.GetString("tag-hash-version"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.TagHashVersion)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
@@ -2,7 +2,7 @@ package datacatalog
 
 import (
 	"context"
-	"crypto/x509"
+	"fmt"
 	"time"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
@@ -13,23 +13,82 @@ import (
 	grpcRetry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	"github.com/pkg/errors"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/grpcclient"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 var (
 	_ catalog.Client = &CatalogClient{}
 )
 
+type maxCacheAgeKey struct{}
+
+// WithMaxCacheAge overrides the max cache age of the client for the artifacts retrieved and put with the returned
+// context. A max cache age of 0 means the artifacts never expire.
+func WithMaxCacheAge(ctx context.Context, maxCacheAge time.Duration) context.Context {
+	return context.WithValue(ctx, maxCacheAgeKey{}, maxCacheAge)
+}
+
+type overwriteKey struct{}
+
+// WithOverwrite makes the artifacts put with the returned context replace the artifacts that are tagged with the same
+// inputs, instead of leaving them in place.
+func WithOverwrite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, overwriteKey{}, true)
+}
+
+func isOverwrite(ctx context.Context) bool {
+	overwrite, ok := ctx.Value(overwriteKey{}).(bool)
+	return ok && overwrite
+}
+
+// Returns the max cache age of the task set with WithMaxCacheAge, or else the one of the client. It returns true if the
+// max cache age was set for the task.
+func (m *CatalogClient) getMaxCacheAge(ctx context.Context) (time.Duration, bool) {
+	if maxCacheAge, ok := ctx.Value(maxCacheAgeKey{}).(time.Duration); ok {
+		return maxCacheAge, true
+	}
+
+	return m.maxCacheAge, false
+}
+
 // This is the client that caches task executions to DataCatalog service.
 type CatalogClient struct {
 	client      datacatalog.DataCatalogClient
 	maxCacheAge time.Duration
+	// Cache hits of prefetched keys, nil unless prefetching is enabled.
+	prefetched          *cache.LRUExpireCache
+	prefetchTTL         time.Duration
+	prefetchConcurrency int
+	// Version of the hash function tags are written with, v1 if empty.
+	tagHashVersion TagHashVersion
+}
+
+// SetTagHashVersion sets the version of the hash function the tags of cached artifacts are written with. Artifacts are
+// looked up with the tag of the version first, then with the tags of the older versions, and the artifacts found with
+// an older tag are tagged with the new one. Changing the version thus keeps serving the outputs cached before.
+func (m *CatalogClient) SetTagHashVersion(version TagHashVersion) error {
+	if _, ok := tagHashFuncs[version]; !ok {
+		return fmt.Errorf("unknown tag hash version [%v]", version)
+	}
+
+	m.tagHashVersion = version
+	return nil
+}
+
+func (m *CatalogClient) getTagHashVersion() TagHashVersion {
+	if len(m.tagHashVersion) == 0 {
+		return TagHashVersionV1
+	}
+
+	return m.tagHashVersion
 }
 
 // Helper method to retrieve a dataset that is associated with the task
@@ -66,18 +125,27 @@ func (m *CatalogClient) GetArtifactByTag(ctx context.Context, tagName string, da
 		return nil, err
 	}
 
-	// check artifact's age if the configuration specifies a max age
-	if m.maxCacheAge > time.Duration(0) {
-		artifact := response.Artifact
+	// check artifact's age against the shorter of the configured max age and the one the artifact was created with. A
+	// max cache age of 0 set for the task disables the expiry altogether.
+	artifact := response.Artifact
+	maxCacheAge, taskMaxCacheAge := m.getMaxCacheAge(ctx)
+	if !taskMaxCacheAge || maxCacheAge > time.Duration(0) {
+		artifactMaxAge := getArtifactMaxAge(ctx, artifact)
+		if artifactMaxAge > time.Duration(0) && (maxCacheAge <= time.Duration(0) || artifactMaxAge < maxCacheAge) {
+			maxCacheAge = artifactMaxAge
+		}
+	}
+
+	if maxCacheAge > time.Duration(0) {
 		createdAt, err := ptypes.Timestamp(artifact.CreatedAt)
 		if err != nil {
 			logger.Errorf(ctx, "DataCatalog Artifact has invalid createdAt %+v, err: %+v", artifact.CreatedAt, err)
 			return nil, err
 		}
 
-		if time.Since(createdAt) > m.maxCacheAge {
+		if time.Since(createdAt) > maxCacheAge {
 			logger.Warningf(ctx, "Expired Cached Artifact %v created on %v, older than max age %v",
-				artifact.Id, createdAt.String(), m.maxCacheAge)
+				artifact.Id, createdAt.String(), maxCacheAge)
 			return nil, status.Error(codes.NotFound, "Artifact over age limit")
 		}
 	}
@@ -91,34 +159,73 @@ func (m *CatalogClient) GetArtifactByTag(ctx context.Context, tagName string, da
 // - Lookup the Artifact that is tagged with the hash of the input values
 // - The artifactData contains the literal values that serve as the task outputs
 func (m *CatalogClient) Get(ctx context.Context, key catalog.Key) (catalog.Entry, error) {
+	if m.prefetched != nil {
+		key.InputReader = ioutils.NewCachedInputReader(ctx, key.InputReader)
+		if entry, found := m.getPrefetched(ctx, key); found {
+			return entry, nil
+		}
+	}
+
 	dataset, err := m.GetDataset(ctx, key)
 	if err != nil {
 		logger.Debugf(ctx, "DataCatalog failed to get dataset for ID %s, err: %+v", key.Identifier.String(), err)
 		return catalog.Entry{}, errors.Wrapf(err, "DataCatalog failed to get dataset for ID %s", key.Identifier.String())
 	}
 
+	tags, err := m.generateTagsForKey(ctx, key)
+	if err != nil {
+		return catalog.Entry{}, err
+	}
+
+	return m.getArtifactEntry(ctx, key, dataset, tags)
+}
+
+// Generates the tags of the artifact that caches the outputs of the task execution, from the hash of its input values.
+// The tag of the configured tag hash version comes first, followed by the tags of the older versions.
+func (m *CatalogClient) generateTagsForKey(ctx context.Context, key catalog.Key) ([]string, error) {
 	inputs := &core.LiteralMap{}
 	if key.TypedInterface.Inputs != nil {
 		retInputs, err := key.InputReader.Get(ctx)
 		if err != nil {
-			return catalog.Entry{}, errors.Wrap(err, "failed to read inputs when trying to query catalog")
+			return nil, errors.Wrap(err, "failed to read inputs when trying to query catalog")
 		}
 		inputs = retInputs
 	}
 
-	tag, err := GenerateArtifactTagName(ctx, inputs)
+	tags, err := GenerateArtifactTagNames(ctx, m.getTagHashVersion(), inputs)
 	if err != nil {
 		logger.Errorf(ctx, "DataCatalog failed to generate tag for inputs %+v, err: %+v", inputs, err)
-		return catalog.Entry{}, err
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Looks up the artifact of the dataset that is tagged with the first of the given tags that tags one, and returns its
+// data as the outputs of the task. An artifact found with an older tag is also tagged with the first tag, so that it is
+// found with it from then on.
+func (m *CatalogClient) getArtifactEntry(ctx context.Context, key catalog.Key, dataset *datacatalog.Dataset, tags []string) (catalog.Entry, error) {
+	var artifact *datacatalog.Artifact
+	var err error
+	tag := ""
+	for i := range tags {
+		tag = tags[i]
+		artifact, err = m.GetArtifactByTag(ctx, tag, dataset)
+		if err == nil || status.Code(err) != codes.NotFound {
+			break
+		}
 	}
 
-	artifact, err := m.GetArtifactByTag(ctx, tag, dataset)
 	if err != nil {
 		logger.Debugf(ctx, "DataCatalog failed to get artifact by tag %+v, err: %+v", tag, err)
 		return catalog.Entry{}, err
 	}
 	logger.Debugf(ctx, "Artifact found %v from tag %v", artifact, tag)
 
+	if tag != tags[0] {
+		m.migrateTag(ctx, dataset, artifact, tags[0])
+	}
+
 	var relevantTag *datacatalog.Tag
 	if len(artifact.GetTags()) > 0 {
 		// TODO should we look through all the tags to find the relevant one?
@@ -136,10 +243,26 @@ func (m *CatalogClient) Get(ctx context.Context, key catalog.Key) (catalog.Entry
 	return catalog.NewCatalogEntry(ioutils.NewInMemoryOutputReader(outputs, nil), catalog.NewStatus(core.CatalogCacheStatus_CACHE_HIT, md)), nil
 }
 
+// Tags the artifact found with an older tag with the tag of the current tag hash version. Failing to do so only means the
+// artifact keeps being looked up with the older tag.
+func (m *CatalogClient) migrateTag(ctx context.Context, dataset *datacatalog.Dataset, artifact *datacatalog.Artifact, tagName string) {
+	_, err := m.client.AddTag(ctx, &datacatalog.AddTagRequest{Tag: &datacatalog.Tag{
+		Name:       tagName,
+		Dataset:    dataset.GetId(),
+		ArtifactId: artifact.GetId(),
+	}})
+	if err != nil && status.Code(err) != codes.AlreadyExists {
+		logger.Warnf(ctx, "Failed to add tag %v to artifact %v found with an older tag, err: %v", tagName, artifact.GetId(), err)
+		return
+	}
+
+	logger.Debugf(ctx, "Added tag %v to artifact %v found with an older tag", tagName, artifact.GetId())
+}
+
 func (m *CatalogClient) CreateDataset(ctx context.Context, key catalog.Key, metadata *datacatalog.Metadata) (*datacatalog.DatasetID, error) {
 	datasetID, err := GenerateDatasetIDForTask(ctx, key)
 	if err != nil {
-		logger.Errorf(ctx, "DataCatalog failed to generate dataset for ID: %s, err: %s", key.Identifier, err)
+		logger.Errorf(ctx, "DataCatalog failed to generate dataset for ID: %v, err: %s", key.Identifier, err)
 		return nil, err
 	}
 
@@ -152,7 +275,7 @@ func (m *CatalogClient) CreateDataset(ctx context.Context, key catalog.Key, meta
 	if err != nil {
 		logger.Debugf(ctx, "Create dataset %v return err %v", datasetID, err)
 		if status.Code(err) == codes.AlreadyExists {
-			logger.Debugf(ctx, "Create Dataset for ID %s already exists", key.Identifier)
+			logger.Debugf(ctx, "Create Dataset for ID %v already exists", key.Identifier)
 		} else {
 			logger.Errorf(ctx, "Unable to create dataset %s, err: %s", datasetID, err)
 			return nil, err
@@ -186,10 +309,29 @@ func (m *CatalogClient) CreateArtifact(ctx context.Context, datasetID *datacatal
 		logger.Errorf(ctx, "Failed to create Artifact %+v, err: %v", cachedArtifact, err)
 		return cachedArtifact, err
 	}
-	logger.Debugf(ctx, "Created artifact: %v, with %v outputs from execution %v", cachedArtifact.Id, len(artifactDataList))
+	logger.Debugf(ctx, "Created artifact: %v, with %v outputs", cachedArtifact.Id, len(artifactDataList))
 	return cachedArtifact, nil
 }
 
+// Reads the outputs of the task execution, if the task declares any.
+func (m *CatalogClient) readOutputs(ctx context.Context, key catalog.Key, reader io.OutputReader) (*core.LiteralMap, error) {
+	if key.TypedInterface.Outputs == nil || len(key.TypedInterface.Outputs.Variables) == 0 {
+		return &core.LiteralMap{}, nil
+	}
+
+	retOutputs, retErr, err := reader.Read(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "DataCatalog failed to read outputs err: %s", err)
+		return nil, err
+	}
+	if retErr != nil {
+		logger.Errorf(ctx, "DataCatalog failed to read outputs, err :%s", retErr.Message)
+		return nil, errors.Errorf("Failed to read outputs. EC: %s, Msg: %s", retErr.Code, retErr.Message)
+	}
+	logger.Debugf(ctx, "DataCatalog read outputs")
+	return retOutputs, nil
+}
+
 // Catalog the task execution as a cached Artifact. We associate an Artifact as the cached data by tagging the Artifact
 // with the hash of the input values.
 //
@@ -206,7 +348,6 @@ func (m *CatalogClient) Put(ctx context.Context, key catalog.Key, reader io.Outp
 	}
 
 	inputs := &core.LiteralMap{}
-	outputs := &core.LiteralMap{}
 	if key.TypedInterface.Inputs != nil && len(key.TypedInterface.Inputs.Variables) != 0 {
 		retInputs, err := key.InputReader.Get(ctx)
 		if err != nil {
@@ -217,28 +358,22 @@ func (m *CatalogClient) Put(ctx context.Context, key catalog.Key, reader io.Outp
 		inputs = retInputs
 	}
 
-	if key.TypedInterface.Outputs != nil && len(key.TypedInterface.Outputs.Variables) != 0 {
-		retOutputs, retErr, err := reader.Read(ctx)
-		if err != nil {
-			logger.Errorf(ctx, "DataCatalog failed to read outputs err: %s", err)
-			return catalog.Status{}, err
-		}
-		if retErr != nil {
-			logger.Errorf(ctx, "DataCatalog failed to read outputs, err :%s", retErr.Message)
-			return catalog.Status{}, errors.Errorf("Failed to read outputs. EC: %s, Msg: %s", retErr.Code, retErr.Message)
-		}
-		logger.Debugf(ctx, "DataCatalog read outputs")
-		outputs = retOutputs
+	outputs, err := m.readOutputs(ctx, key, reader)
+	if err != nil {
+		return catalog.Status{}, err
 	}
 
-	// Create the artifact for the execution that belongs in the task
-	cachedArtifact, err := m.CreateArtifact(ctx, datasetID, outputs, GetArtifactMetadataForSource(metadata.TaskExecutionIdentifier))
+	// Create the artifact for the execution that belongs in the task, it expires after the max cache age of the task
+	artifactMetadata := GetArtifactMetadataForSource(metadata.TaskExecutionIdentifier)
+	maxCacheAge, _ := m.getMaxCacheAge(ctx)
+	setArtifactMaxAge(artifactMetadata, maxCacheAge)
+	cachedArtifact, err := m.CreateArtifact(ctx, datasetID, outputs, artifactMetadata)
 	if err != nil {
 		return catalog.Status{}, errors.Wrapf(err, "failed to create dataset for ID %s", key.Identifier.String())
 	}
 
 	// Tag the artifact since it is the cached artifact
-	tagName, err := GenerateArtifactTagName(ctx, inputs)
+	tagName, err := GenerateVersionedArtifactTagName(ctx, m.getTagHashVersion(), inputs)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to generate tag for artifact %+v, err: %+v", cachedArtifact.Id, err)
 		return catalog.Status{}, err
@@ -253,7 +388,11 @@ func (m *CatalogClient) Put(ctx context.Context, key catalog.Key, reader io.Outp
 	}
 	_, err = m.client.AddTag(ctx, &datacatalog.AddTagRequest{Tag: tag})
 	if err != nil {
-		if status.Code(err) == codes.AlreadyExists {
+		if status.Code(err) == codes.AlreadyExists && isOverwrite(ctx) {
+			// The datacatalog API can neither move nor delete tags, the artifact tagged before keeps being served.
+			logger.Errorf(ctx, "Failed to overwrite the artifact tagged %v with artifact %v, err: %+v", tagName, cachedArtifact.Id, err)
+			return catalog.Status{}, errors.Wrapf(err, "tag %v already tags another artifact and cannot be moved", tagName)
+		} else if status.Code(err) == codes.AlreadyExists {
 			logger.Warnf(ctx, "Tag %v already exists for Artifact %v (idempotent)", tagName, cachedArtifact.Id)
 		} else {
 			logger.Errorf(ctx, "Failed to add tag %+v for artifact %+v, err: %+v", tagName, cachedArtifact.Id, err)
@@ -264,9 +403,31 @@ func (m *CatalogClient) Put(ctx context.Context, key catalog.Key, reader io.Outp
 	return catalog.NewStatus(core.CatalogCacheStatus_CACHE_POPULATED, EventCatalogMetadata(datasetID, tag, nil)), nil
 }
 
+// Records the outputs of a task execution as an Artifact of the task's Dataset, without tagging it. Untagged artifacts
+// are never returned as cache hits, but they give the catalog the lineage of data produced by tasks that are not cached.
+func (m *CatalogClient) PutUntagged(ctx context.Context, key catalog.Key, reader io.OutputReader, metadata catalog.Metadata) (catalog.Status, error) {
+	datasetID, err := m.CreateDataset(ctx, key, GetDatasetMetadataForSource(metadata.TaskExecutionIdentifier))
+	if err != nil {
+		return catalog.Status{}, err
+	}
+
+	outputs, err := m.readOutputs(ctx, key, reader)
+	if err != nil {
+		return catalog.Status{}, err
+	}
+
+	artifact, err := m.CreateArtifact(ctx, datasetID, outputs, GetArtifactMetadataForSource(metadata.TaskExecutionIdentifier))
+	if err != nil {
+		return catalog.Status{}, errors.Wrapf(err, "failed to create artifact for ID %s", key.Identifier.String())
+	}
+
+	logger.Infof(ctx, "Recorded untagged artifact %v, task: %v", artifact.Id, key.Identifier)
+	return catalog.NewStatus(core.CatalogCacheStatus_CACHE_DISABLED, EventCatalogMetadata(datasetID, nil, nil)), nil
+}
+
 // Create a new Datacatalog client for task execution caching
-func NewDataCatalog(ctx context.Context, endpoint string, insecureConnection bool, maxCacheAge time.Duration) (*CatalogClient, error) {
-	var opts []grpc.DialOption
+func NewDataCatalog(ctx context.Context, endpoint string, insecureConnection bool, maxCacheAge time.Duration,
+	dialer *grpcclient.Factory) (*CatalogClient, error) {
 
 	grpcOptions := []grpcRetry.CallOption{
 		grpcRetry.WithBackoff(grpcRetry.BackoffLinear(100 * time.Millisecond)),
@@ -274,24 +435,8 @@ func NewDataCatalog(ctx context.Context, endpoint string, insecureConnection boo
 		grpcRetry.WithMax(5),
 	}
 
-	if insecureConnection {
-		logger.Debug(ctx, "Establishing insecure connection to DataCatalog")
-		opts = append(opts, grpc.WithInsecure())
-	} else {
-		logger.Debug(ctx, "Establishing secure connection to DataCatalog")
-		pool, err := x509.SystemCertPool()
-		if err != nil {
-			return nil, err
-		}
-
-		creds := credentials.NewClientTLSFromCert(pool, "")
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	}
-
 	retryInterceptor := grpc.WithUnaryInterceptor(grpcRetry.UnaryClientInterceptor(grpcOptions...))
-
-	opts = append(opts, retryInterceptor)
-	clientConn, err := grpc.Dial(endpoint, opts...)
+	clientConn, err := dialer.Dial(ctx, grpcclient.DataCatalogTarget, endpoint, insecureConnection, retryInterceptor)
 	if err != nil {
 		return nil, err
 	}
@@ -389,6 +389,165 @@ func TestCatalog_Get(t *testing.T) {
 		assert.Nil(t, e)
 		assert.Len(t, v.Literals, 0)
 	})
+
+	t.Run("Per task max cache age", func(t *testing.T) {
+		ir := &mocks2.InputReader{}
+		ir.On("Get", mock.Anything).Return(sampleParameters, nil, nil)
+
+		mockClient := &mocks.DataCatalogClient{}
+		sampleDataSet := &datacatalog.Dataset{
+			Id: datasetID,
+		}
+
+		mockClient.On("GetDataset", mock.Anything, mock.Anything).Return(
+			&datacatalog.GetDatasetResponse{Dataset: sampleDataSet}, nil)
+		createdAt, err := ptypes.TimestampProto(time.Now().Add(-2 * time.Hour))
+		assert.NoError(t, err)
+
+		mockClient.On("GetArtifact", mock.Anything, mock.Anything).Return(&datacatalog.GetArtifactResponse{
+			Artifact: &datacatalog.Artifact{
+				Id:        "test-artifact",
+				Dataset:   sampleDataSet.Id,
+				Data:      []*datacatalog.ArtifactData{sampleArtifactData},
+				CreatedAt: createdAt,
+			},
+		}, nil)
+
+		newKey := sampleKey
+		newKey.InputReader = ir
+
+		// The max cache age of the task overrides the one of the client, in both directions.
+		catalogClient := &CatalogClient{client: mockClient}
+		_, err = catalogClient.Get(WithMaxCacheAge(ctx, time.Hour), newKey)
+		getStatus, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, getStatus.Code())
+
+		catalogClient.maxCacheAge = time.Hour
+		resp, err := catalogClient.Get(WithMaxCacheAge(ctx, 0), newKey)
+		assert.NoError(t, err)
+		assert.Equal(t, core.CatalogCacheStatus_CACHE_HIT, resp.GetStatus().GetCacheStatus())
+	})
+
+	t.Run("Artifact max age", func(t *testing.T) {
+		ir := &mocks2.InputReader{}
+		ir.On("Get", mock.Anything).Return(sampleParameters, nil, nil)
+
+		mockClient := &mocks.DataCatalogClient{}
+		sampleDataSet := &datacatalog.Dataset{
+			Id: datasetID,
+		}
+
+		mockClient.On("GetDataset", mock.Anything, mock.Anything).Return(
+			&datacatalog.GetDatasetResponse{Dataset: sampleDataSet}, nil)
+		createdAt, err := ptypes.TimestampProto(time.Now().Add(-2 * time.Hour))
+		assert.NoError(t, err)
+
+		mockClient.On("GetArtifact", mock.Anything, mock.Anything).Return(&datacatalog.GetArtifactResponse{
+			Artifact: &datacatalog.Artifact{
+				Id:        "test-artifact",
+				Dataset:   sampleDataSet.Id,
+				Data:      []*datacatalog.ArtifactData{sampleArtifactData},
+				Metadata:  &datacatalog.Metadata{KeyMap: map[string]string{maxAgeKey: "1h0m0s"}},
+				CreatedAt: createdAt,
+			},
+		}, nil)
+
+		newKey := sampleKey
+		newKey.InputReader = ir
+
+		// The max age the artifact was created with applies when the task has none.
+		catalogClient := &CatalogClient{client: mockClient}
+		_, err = catalogClient.Get(ctx, newKey)
+		getStatus, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, getStatus.Code())
+
+		// The shorter of the max age of the reader and the one of the artifact applies.
+		catalogClient.maxCacheAge = 3 * time.Hour
+		_, err = catalogClient.Get(ctx, newKey)
+		getStatus, ok = status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, getStatus.Code())
+
+		_, err = catalogClient.Get(WithMaxCacheAge(ctx, 3*time.Hour), newKey)
+		getStatus, ok = status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, getStatus.Code())
+
+		// An explicit max cache age of 0 for the task disables the expiry.
+		resp, err := catalogClient.Get(WithMaxCacheAge(ctx, 0), newKey)
+		assert.NoError(t, err)
+		assert.Equal(t, core.CatalogCacheStatus_CACHE_HIT, resp.GetStatus().GetCacheStatus())
+	})
+
+	t.Run("Found w/ older tag hash version", func(t *testing.T) {
+		ir := &mocks2.InputReader{}
+		ir.On("Get", mock.Anything).Return(sampleParameters, nil, nil)
+
+		v1Tag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV1, sampleParameters)
+		assert.NoError(t, err)
+		v2Tag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV2, sampleParameters)
+		assert.NoError(t, err)
+
+		mockClient := &mocks.DataCatalogClient{}
+		sampleDataSet := &datacatalog.Dataset{Id: datasetID}
+		mockClient.On("GetDataset", mock.Anything, mock.Anything).Return(
+			&datacatalog.GetDatasetResponse{Dataset: sampleDataSet}, nil)
+		mockClient.On("GetArtifact", mock.Anything, mock.MatchedBy(func(o *datacatalog.GetArtifactRequest) bool {
+			return o.GetTagName() == v2Tag
+		})).Return(nil, status.Error(codes.NotFound, "test not found"))
+		mockClient.On("GetArtifact", mock.Anything, mock.MatchedBy(func(o *datacatalog.GetArtifactRequest) bool {
+			return o.GetTagName() == v1Tag
+		})).Return(&datacatalog.GetArtifactResponse{Artifact: &datacatalog.Artifact{
+			Id:      "test-artifact",
+			Dataset: sampleDataSet.Id,
+			Data:    []*datacatalog.ArtifactData{sampleArtifactData},
+		}}, nil)
+		mockClient.On("AddTag", mock.Anything, mock.MatchedBy(func(o *datacatalog.AddTagRequest) bool {
+			return o.GetTag().GetName() == v2Tag && o.GetTag().GetArtifactId() == "test-artifact"
+		})).Return(&datacatalog.AddTagResponse{}, nil)
+
+		catalogClient := &CatalogClient{client: mockClient}
+		assert.NoError(t, catalogClient.SetTagHashVersion(TagHashVersionV2))
+
+		newKey := sampleKey
+		newKey.InputReader = ir
+		resp, err := catalogClient.Get(ctx, newKey)
+		assert.NoError(t, err)
+		assert.Equal(t, core.CatalogCacheStatus_CACHE_HIT, resp.GetStatus().GetCacheStatus())
+		mockClient.AssertNumberOfCalls(t, "GetArtifact", 2)
+		mockClient.AssertNumberOfCalls(t, "AddTag", 1)
+	})
+
+	t.Run("Not found w/ any tag hash version", func(t *testing.T) {
+		ir := &mocks2.InputReader{}
+		ir.On("Get", mock.Anything).Return(sampleParameters, nil, nil)
+
+		mockClient := &mocks.DataCatalogClient{}
+		mockClient.On("GetDataset", mock.Anything, mock.Anything).Return(
+			&datacatalog.GetDatasetResponse{Dataset: &datacatalog.Dataset{Id: datasetID}}, nil)
+		mockClient.On("GetArtifact", mock.Anything, mock.Anything).Return(nil, status.Error(codes.NotFound, "test not found"))
+
+		catalogClient := &CatalogClient{client: mockClient}
+		assert.NoError(t, catalogClient.SetTagHashVersion(TagHashVersionV2))
+
+		newKey := sampleKey
+		newKey.InputReader = ir
+		_, err := catalogClient.Get(ctx, newKey)
+		assertGrpcErr(t, err, codes.NotFound)
+		mockClient.AssertNumberOfCalls(t, "GetArtifact", 2)
+		mockClient.AssertNotCalled(t, "AddTag", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCatalogClient_SetTagHashVersion(t *testing.T) {
+	catalogClient := &CatalogClient{}
+	assert.Equal(t, TagHashVersionV1, catalogClient.getTagHashVersion())
+	assert.NoError(t, catalogClient.SetTagHashVersion(TagHashVersionV2))
+	assert.Equal(t, TagHashVersionV2, catalogClient.getTagHashVersion())
+	assert.Error(t, catalogClient.SetTagHashVersion("v0"))
+	assert.Equal(t, TagHashVersionV2, catalogClient.getTagHashVersion())
 }
 
 func TestCatalog_Put(t *testing.T) {
@@ -538,4 +697,95 @@ func TestCatalog_Put(t *testing.T) {
 		assert.NotNil(t, s.GetMetadata())
 	})
 
+	t.Run("Overwrite cached execution", func(t *testing.T) {
+		ir := &mocks2.InputReader{}
+		ir.On("Get", mock.Anything).Return(sampleParameters, nil, nil)
+		newKey := sampleKey
+		newKey.InputReader = ir
+		or := ioutils.NewInMemoryOutputReader(sampleParameters, nil)
+		overwriteCtx := WithOverwrite(ctx)
+
+		newClient := func(addTagErr error) *CatalogClient {
+			mockClient := &mocks.DataCatalogClient{}
+			mockClient.On("CreateDataset", overwriteCtx, mock.Anything).Return(&datacatalog.CreateDatasetResponse{}, nil)
+			mockClient.On("CreateArtifact", overwriteCtx, mock.Anything).Return(&datacatalog.CreateArtifactResponse{}, nil)
+			mockClient.On("AddTag", overwriteCtx, mock.Anything).Return(&datacatalog.AddTagResponse{}, addTagErr)
+			return &CatalogClient{
+				client: mockClient,
+			}
+		}
+
+		s, err := newClient(nil).Put(overwriteCtx, newKey, or, catalog.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, core.CatalogCacheStatus_CACHE_POPULATED, s.GetCacheStatus())
+
+		_, err = newClient(status.Error(codes.AlreadyExists, "tag already exists")).Put(overwriteCtx, newKey, or,
+			catalog.Metadata{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Max cache age", func(t *testing.T) {
+		ir := &mocks2.InputReader{}
+		ir.On("Get", mock.Anything).Return(sampleParameters, nil, nil)
+		newKey := sampleKey
+		newKey.InputReader = ir
+		or := ioutils.NewInMemoryOutputReader(sampleParameters, nil)
+
+		put := func(ctx context.Context, maxCacheAge time.Duration) *datacatalog.Metadata {
+			var md *datacatalog.Metadata
+			mockClient := &mocks.DataCatalogClient{}
+			mockClient.On("CreateDataset", mock.Anything, mock.Anything).Return(&datacatalog.CreateDatasetResponse{}, nil)
+			mockClient.On("CreateArtifact", mock.Anything, mock.MatchedBy(func(o *datacatalog.CreateArtifactRequest) bool {
+				md = o.Artifact.Metadata
+				return true
+			})).Return(&datacatalog.CreateArtifactResponse{}, nil)
+			mockClient.On("AddTag", mock.Anything, mock.Anything).Return(&datacatalog.AddTagResponse{}, nil)
+
+			catalogClient := &CatalogClient{client: mockClient, maxCacheAge: maxCacheAge}
+			_, err := catalogClient.Put(ctx, newKey, or, catalog.Metadata{})
+			assert.NoError(t, err)
+			return md
+		}
+
+		assert.Equal(t, "1h0m0s", put(ctx, time.Hour).GetKeyMap()[maxAgeKey])
+		assert.Equal(t, "24h0m0s", put(WithMaxCacheAge(ctx, 24*time.Hour), time.Hour).GetKeyMap()[maxAgeKey])
+
+		_, ok := put(WithMaxCacheAge(ctx, 0), time.Hour).GetKeyMap()[maxAgeKey]
+		assert.False(t, ok)
+	})
+
+}
+
+func TestCatalog_PutUntagged(t *testing.T) {
+	ctx := context.Background()
+
+	mockClient := &mocks.DataCatalogClient{}
+	catalogClient := &CatalogClient{
+		client: mockClient,
+	}
+
+	mockClient.On("CreateDataset",
+		ctx,
+		mock.MatchedBy(func(o *datacatalog.CreateDatasetRequest) bool {
+			assert.True(t, proto.Equal(o.Dataset.Id, datasetID))
+			return true
+		}),
+	).Return(&datacatalog.CreateDatasetResponse{}, nil)
+
+	mockClient.On("CreateArtifact",
+		ctx,
+		mock.MatchedBy(func(o *datacatalog.CreateArtifactRequest) bool {
+			assert.EqualValues(t, 1, len(o.Artifact.Data))
+			assert.EqualValues(t, "out1", o.Artifact.Data[0].Name)
+			return true
+		}),
+	).Return(&datacatalog.CreateArtifactResponse{}, nil)
+
+	or := ioutils.NewInMemoryOutputReader(sampleParameters, nil)
+	s, err := catalogClient.PutUntagged(ctx, sampleKey, or, catalog.Metadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, core.CatalogCacheStatus_CACHE_DISABLED, s.GetCacheStatus())
+	assert.NotNil(t, s.GetMetadata().GetDatasetId())
+	assert.Nil(t, s.GetMetadata().GetArtifactTag())
+	mockClient.AssertNotCalled(t, "AddTag", mock.Anything, mock.Anything)
 }
@@ -0,0 +1,165 @@
+package datacatalog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const defaultBatchConcurrency = 10
+
+// Returns the key a prefetched entry is cached under.
+func prefetchCacheKey(datasetID *datacatalog.DatasetID, tag string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", datasetID.GetProject(), datasetID.GetDomain(), datasetID.GetName(),
+		datasetID.GetVersion(), datasetID.GetUUID(), tag)
+}
+
+// EnablePrefetch makes the client keep the cache hits of prefetched keys in memory for the given ttl, so that the Gets
+// that follow a prefetch are served without calling the catalog service. At most maxEntries hits are kept and at most
+// concurrency artifacts are looked up at the same time.
+func (m *CatalogClient) EnablePrefetch(concurrency int, ttl time.Duration, maxEntries int) {
+	m.prefetchConcurrency = concurrency
+	m.prefetchTTL = ttl
+	m.prefetched = cache.NewLRUExpireCache(maxEntries)
+}
+
+// Returns the prefetched entry of the key, if any.
+func (m *CatalogClient) getPrefetched(ctx context.Context, key catalog.Key) (catalog.Entry, bool) {
+	datasetID, err := GenerateDatasetIDForTask(ctx, key)
+	if err != nil {
+		return catalog.Entry{}, false
+	}
+
+	tags, err := m.generateTagsForKey(ctx, key)
+	if err != nil {
+		return catalog.Entry{}, false
+	}
+
+	if entry, found := m.prefetched.Get(prefetchCacheKey(datasetID, tags[0])); found {
+		logger.Debugf(ctx, "Serving prefetched artifact of dataset %v from tag %v", datasetID, tags[0])
+		return entry.(catalog.Entry), true
+	}
+
+	return catalog.Entry{}, false
+}
+
+// Looks up the cached task executions of the keys and returns the entries and errors in the order of the keys, along
+// with the prefetch cache key of every key that could be resolved.
+func (m *CatalogClient) getMany(ctx context.Context, keys []catalog.Key) ([]catalog.Entry, []string, []error) {
+	entries := make([]catalog.Entry, len(keys))
+	cacheKeys := make([]string, len(keys))
+	errs := make([]error, len(keys))
+	tags := make([][]string, len(keys))
+
+	// Keys of the same task share their dataset, which is looked up once.
+	datasets := make([]*datacatalog.Dataset, len(keys))
+	datasetsByKey := make(map[string]*datacatalog.Dataset)
+	datasetErrs := make(map[string]error)
+	for i, key := range keys {
+		datasetID, err := GenerateDatasetIDForTask(ctx, key)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		if tags[i], errs[i] = m.generateTagsForKey(ctx, key); errs[i] != nil {
+			continue
+		}
+
+		cacheKeys[i] = prefetchCacheKey(datasetID, tags[i][0])
+		datasetKey := prefetchCacheKey(datasetID, "")
+		if _, found := datasetsByKey[datasetKey]; !found {
+			if _, failed := datasetErrs[datasetKey]; !failed {
+				dataset, err := m.GetDataset(ctx, key)
+				if err != nil {
+					logger.Debugf(ctx, "DataCatalog failed to get dataset for ID %s, err: %+v", key.Identifier.String(), err)
+					datasetErrs[datasetKey] = errors.Wrapf(err, "DataCatalog failed to get dataset for ID %s", key.Identifier.String())
+				} else {
+					datasetsByKey[datasetKey] = dataset
+				}
+			}
+		}
+
+		if err, failed := datasetErrs[datasetKey]; failed {
+			errs[i] = err
+			continue
+		}
+
+		datasets[i] = datasetsByKey[datasetKey]
+	}
+
+	concurrency := m.prefetchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	// The catalog service has no bulk lookup, artifacts are looked up concurrently instead.
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for i := range keys {
+		if errs[i] != nil {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			entries[i], errs[i] = m.getArtifactEntry(ctx, keys[i], datasets[i], tags[i])
+		}(i)
+	}
+
+	wg.Wait()
+	return entries, cacheKeys, errs
+}
+
+// GetMany looks up the cached task executions of many keys and returns the entries and errors in the order of the keys.
+// Keys of the same task share a single dataset lookup and their artifacts are looked up concurrently.
+func (m *CatalogClient) GetMany(ctx context.Context, keys []catalog.Key) ([]catalog.Entry, []error) {
+	entries, _, errs := m.getMany(ctx, keys)
+	return entries, errs
+}
+
+// Prefetch looks up the keys with GetMany and keeps the cache hits in memory, so that the Gets that follow within the
+// prefetch ttl are served without calling the catalog service. Prefetching is best effort, lookup failures are left for
+// the Gets to surface. Returns the number of cache hits, nothing is looked up unless prefetching is enabled.
+func (m *CatalogClient) Prefetch(ctx context.Context, keys []catalog.Key) int {
+	if m.prefetched == nil || len(keys) == 0 {
+		return 0
+	}
+
+	entries, cacheKeys, errs := m.getMany(ctx, keys)
+	hits := 0
+	for i, entry := range entries {
+		if errs[i] != nil {
+			if status.Code(errors.Cause(errs[i])) != codes.NotFound {
+				logger.Warnf(ctx, "Failed to prefetch cached outputs of task [%v]. Error: %v", keys[i].Identifier, errs[i])
+			}
+
+			continue
+		}
+
+		if entry.GetStatus().GetCacheStatus() == core.CatalogCacheStatus_CACHE_HIT {
+			m.prefetched.Add(cacheKeys[i], entry, m.prefetchTTL)
+			hits++
+		}
+	}
+
+	logger.Infof(ctx, "Prefetched %v cache hits for %v keys", hits, len(keys))
+	return hits
+}
@@ -0,0 +1,80 @@
+package datacatalog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/datacatalog/mocks"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	mocks2 "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCatalogClient_Prefetch(t *testing.T) {
+	ctx := context.Background()
+	hitTag := "flyte_cached-BE6CZsMk6N3ExR_4X9EuwBgj2Jh2UwasXK3a_pM9xlY"
+
+	newKey := func(inputs *core.LiteralMap) catalog.Key {
+		ir := &mocks2.InputReader{}
+		ir.On("Get", mock.Anything).Return(inputs, nil)
+		key := sampleKey
+		key.InputReader = ir
+		return key
+	}
+
+	hitKey := newKey(sampleParameters)
+	missKey := newKey(&core.LiteralMap{Literals: map[string]*core.Literal{"out1": newStringLiteral("other")}})
+
+	newClient := func() (*CatalogClient, *mocks.DataCatalogClient) {
+		mockClient := &mocks.DataCatalogClient{}
+		mockClient.On("GetDataset", ctx, mock.Anything).
+			Return(&datacatalog.GetDatasetResponse{Dataset: &datacatalog.Dataset{Id: datasetID}}, nil)
+		mockClient.On("GetArtifact", ctx, mock.MatchedBy(func(o *datacatalog.GetArtifactRequest) bool {
+			return o.GetTagName() == hitTag
+		})).Return(&datacatalog.GetArtifactResponse{Artifact: &datacatalog.Artifact{
+			Id:      "test-artifact",
+			Dataset: datasetID,
+			Data:    []*datacatalog.ArtifactData{{Name: "test", Value: newStringLiteral("output1-stringval")}},
+		}}, nil)
+		mockClient.On("GetArtifact", ctx, mock.Anything).Return(nil, status.Error(codes.NotFound, "not found"))
+		return &CatalogClient{client: mockClient}, mockClient
+	}
+
+	t.Run("GetMany", func(t *testing.T) {
+		catalogClient, mockClient := newClient()
+		entries, errs := catalogClient.GetMany(ctx, []catalog.Key{hitKey, missKey, hitKey})
+		assert.NoError(t, errs[0])
+		assert.Equal(t, core.CatalogCacheStatus_CACHE_HIT, entries[0].GetStatus().GetCacheStatus())
+		assertGrpcErr(t, errs[1], codes.NotFound)
+		assert.NoError(t, errs[2])
+		mockClient.AssertNumberOfCalls(t, "GetDataset", 1)
+		mockClient.AssertNumberOfCalls(t, "GetArtifact", 3)
+	})
+
+	t.Run("Prefetch disabled", func(t *testing.T) {
+		catalogClient, mockClient := newClient()
+		assert.Equal(t, 0, catalogClient.Prefetch(ctx, []catalog.Key{hitKey}))
+		mockClient.AssertNotCalled(t, "GetDataset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Prefetched hits are served from memory", func(t *testing.T) {
+		catalogClient, mockClient := newClient()
+		catalogClient.EnablePrefetch(2, time.Minute, 10)
+		assert.Equal(t, 1, catalogClient.Prefetch(ctx, []catalog.Key{hitKey, missKey}))
+
+		entry, err := catalogClient.Get(ctx, hitKey)
+		assert.NoError(t, err)
+		assert.Equal(t, core.CatalogCacheStatus_CACHE_HIT, entry.GetStatus().GetCacheStatus())
+		mockClient.AssertNumberOfCalls(t, "GetArtifact", 2)
+
+		_, err = catalogClient.Get(ctx, missKey)
+		assertGrpcErr(t, err, codes.NotFound)
+		mockClient.AssertNumberOfCalls(t, "GetArtifact", 3)
+	})
+}
@@ -0,0 +1,108 @@
+package datacatalog
+
+import (
+	"context"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/pkg/errors"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type ReservationState int
+
+const (
+	// The caller holds the reservation and should execute the task.
+	ReservationAcquired ReservationState = iota
+	// Another owner holds the reservation and is executing the task.
+	ReservationInProgress
+	// The outputs of the task were cached since they were last looked up, no reservation is needed.
+	ReservationCached
+)
+
+// Reservation is the outcome of an attempt to reserve the execution of a task with a cache key.
+type Reservation struct {
+	State ReservationState
+	// Owner of the reservation, set unless the outputs are cached.
+	OwnerID string
+}
+
+// Returns the dataset and tag that identify the reservation of the cache key.
+func (m *CatalogClient) getReservationID(ctx context.Context, key catalog.Key) (*datacatalog.DatasetID, string, error) {
+	datasetID, err := GenerateDatasetIDForTask(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tags, err := m.generateTagsForKey(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Reservations are only held on the tag of the current tag hash version.
+	return datasetID, tags[0], nil
+}
+
+// GetOrReserve reserves the execution of the task with the cache key for the owner, so that concurrent executions with
+// the same cache key run one at a time. Reserving a cache key the owner already holds succeeds.
+func (m *CatalogClient) GetOrReserve(ctx context.Context, key catalog.Key, ownerID string) (Reservation, error) {
+	datasetID, tag, err := m.getReservationID(ctx, key)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	resp, err := m.client.GetOrReserveArtifact(ctx, &datacatalog.GetOrReserveArtifactRequest{
+		DatasetId: datasetID,
+		TagName:   tag,
+		OwnerId:   ownerID,
+	})
+	if err != nil {
+		return Reservation{}, errors.Wrapf(err, "DataCatalog failed to reserve tag [%v] of dataset [%v]", tag, datasetID)
+	}
+
+	if resp.GetArtifact() != nil {
+		return Reservation{State: ReservationCached}, nil
+	}
+
+	reservationStatus := resp.GetReservationStatus()
+	if reservationStatus.GetState() == datacatalog.ReservationStatus_ACQUIRED {
+		return Reservation{State: ReservationAcquired, OwnerID: ownerID}, nil
+	}
+
+	logger.Debugf(ctx, "Tag [%v] of dataset [%v] is reserved by [%v]", tag, datasetID, reservationStatus.GetOwnerId())
+	return Reservation{State: ReservationInProgress, OwnerID: reservationStatus.GetOwnerId()}, nil
+}
+
+// ExtendReservation extends the reservation the owner holds, so that it does not expire while the task is running.
+func (m *CatalogClient) ExtendReservation(ctx context.Context, key catalog.Key, ownerID string) error {
+	datasetID, tag, err := m.getReservationID(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.client.ExtendReservation(ctx, &datacatalog.ExtendReservationRequest{
+		DatasetId: datasetID,
+		TagName:   tag,
+		OwnerId:   ownerID,
+	})
+
+	return err
+}
+
+// ReleaseReservation releases the reservation the owner holds, so that the next execution with the cache key can run
+// without waiting for the reservation to expire.
+func (m *CatalogClient) ReleaseReservation(ctx context.Context, key catalog.Key, ownerID string) error {
+	datasetID, tag, err := m.getReservationID(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.client.ReleaseReservation(ctx, &datacatalog.ReleaseReservationRequest{
+		DatasetId: datasetID,
+		TagName:   tag,
+		OwnerId:   ownerID,
+	})
+
+	return err
+}
@@ -0,0 +1,82 @@
+package datacatalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/clients/go/datacatalog/mocks"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
+	mocks2 "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCatalogClient_GetOrReserve(t *testing.T) {
+	ctx := context.Background()
+	ir := &mocks2.InputReader{}
+	ir.On("Get", mock.Anything).Return(sampleParameters, nil)
+	key := sampleKey
+	key.InputReader = ir
+
+	reserve := func(resp *datacatalog.GetOrReserveArtifactResponse, err error) (Reservation, error) {
+		mockClient := &mocks.DataCatalogClient{}
+		mockClient.On("GetOrReserveArtifact", ctx, mock.MatchedBy(func(o *datacatalog.GetOrReserveArtifactRequest) bool {
+			return o.GetOwnerId() == "owner" && o.GetTagName() != "" && o.GetDatasetId().GetName() != ""
+		})).Return(resp, err)
+		return (&CatalogClient{client: mockClient}).GetOrReserve(ctx, key, "owner")
+	}
+
+	t.Run("Acquired", func(t *testing.T) {
+		reservation, err := reserve(&datacatalog.GetOrReserveArtifactResponse{
+			Value: &datacatalog.GetOrReserveArtifactResponse_ReservationStatus{ReservationStatus: &datacatalog.ReservationStatus{
+				State: datacatalog.ReservationStatus_ACQUIRED, OwnerId: "owner",
+			}},
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, Reservation{State: ReservationAcquired, OwnerID: "owner"}, reservation)
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		reservation, err := reserve(&datacatalog.GetOrReserveArtifactResponse{
+			Value: &datacatalog.GetOrReserveArtifactResponse_ReservationStatus{ReservationStatus: &datacatalog.ReservationStatus{
+				State: datacatalog.ReservationStatus_ALREADY_IN_PROGRESS, OwnerId: "other",
+			}},
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, Reservation{State: ReservationInProgress, OwnerID: "other"}, reservation)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		reservation, err := reserve(&datacatalog.GetOrReserveArtifactResponse{
+			Value: &datacatalog.GetOrReserveArtifactResponse_Artifact{Artifact: &datacatalog.Artifact{Id: "artifact"}},
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, ReservationCached, reservation.State)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		_, err := reserve(nil, errors.New("unavailable"))
+		assert.Error(t, err)
+	})
+}
+
+func TestCatalogClient_ExtendAndReleaseReservation(t *testing.T) {
+	ctx := context.Background()
+	ir := &mocks2.InputReader{}
+	ir.On("Get", mock.Anything).Return(sampleParameters, nil)
+	key := sampleKey
+	key.InputReader = ir
+
+	mockClient := &mocks.DataCatalogClient{}
+	mockClient.On("ExtendReservation", ctx, mock.MatchedBy(func(o *datacatalog.ExtendReservationRequest) bool {
+		return o.GetOwnerId() == "owner"
+	})).Return(&datacatalog.ExtendReservationResponse{}, nil)
+	mockClient.On("ReleaseReservation", ctx, mock.MatchedBy(func(o *datacatalog.ReleaseReservationRequest) bool {
+		return o.GetOwnerId() == "owner"
+	})).Return(nil, errors.New("unavailable"))
+
+	catalogClient := &CatalogClient{client: mockClient}
+	assert.NoError(t, catalogClient.ExtendReservation(ctx, key, "owner"))
+	assert.Error(t, catalogClient.ReleaseReservation(ctx, key, "owner"))
+}
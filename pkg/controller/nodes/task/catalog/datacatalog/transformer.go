@@ -2,17 +2,23 @@ package datacatalog
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
 
 	"github.com/flyteorg/flytepropeller/pkg/compiler/validators"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 
 	"github.com/flyteorg/flytestdlib/pbhash"
 )
@@ -116,6 +122,146 @@ func generateTaskSignatureHash(ctx context.Context, taskInterface core.TypedInte
 	return fmt.Sprintf("%v-%v", inputHashString, outputHashString), nil
 }
 
+// GenerateTaskSpecHash hashes the interface of the task and the image, command and args of its container, or the pod
+// spec of pod tasks. It changes whenever a task is changed in a way that may change its outputs, even if the version of
+// the task was not bumped.
+func GenerateTaskSpecHash(ctx context.Context, tk *core.TaskTemplate) (string, error) {
+	spec := &core.TaskTemplate{
+		Interface: &core.TypedInterface{Inputs: &emptyVariableMap, Outputs: &emptyVariableMap},
+	}
+
+	if inputs := tk.GetInterface().GetInputs(); len(inputs.GetVariables()) != 0 {
+		spec.Interface.Inputs = inputs
+	}
+
+	if outputs := tk.GetInterface().GetOutputs(); len(outputs.GetVariables()) != 0 {
+		spec.Interface.Outputs = outputs
+	}
+
+	if c := tk.GetContainer(); c != nil {
+		spec.Target = &core.TaskTemplate_Container{Container: &core.Container{
+			Image:   c.Image,
+			Command: c.Command,
+			Args:    c.Args,
+		}}
+	} else if pod := tk.GetK8SPod(); pod != nil {
+		spec.Target = &core.TaskTemplate_K8SPod{K8SPod: &core.K8SPod{PodSpec: pod.PodSpec}}
+	}
+
+	specHash, err := pbhash.ComputeHash(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+
+	specHashString := base64.RawURLEncoding.EncodeToString(specHash)
+	if len(specHashString) > maxParamHashLength {
+		specHashString = specHashString[0:maxParamHashLength]
+	}
+
+	return specHashString, nil
+}
+
+// Field number of the hash of a Literal, which newer versions of the IDL define as `string hash = 4`. The IDL this is
+// built with predates it, the hash is kept in the unknown fields of the literals unmarshalled from newer SDKs instead.
+const literalHashFieldNumber = 4
+
+const literalHashKey = "hash"
+
+// GetLiteralHash returns the hash users set on the literal to stand for its value in cache keys, e.g. the hash of the
+// content of a dataframe, or an empty string if it has none.
+func GetLiteralHash(literal *core.Literal) string {
+	if literal == nil {
+		return ""
+	}
+
+	b := literal.XXX_unrecognized
+	for len(b) > 0 {
+		key, n := proto.DecodeVarint(b)
+		if n == 0 {
+			return ""
+		}
+
+		b = b[n:]
+		var size uint64
+		switch key & 0x7 {
+		case proto.WireVarint:
+			_, n = proto.DecodeVarint(b)
+			if n == 0 {
+				return ""
+			}
+		case proto.WireFixed64:
+			n = 8
+		case proto.WireFixed32:
+			n = 4
+		case proto.WireBytes:
+			size, n = proto.DecodeVarint(b)
+			if n == 0 || uint64(len(b)-n) < size {
+				return ""
+			}
+
+			if key>>3 == literalHashFieldNumber {
+				return string(b[n : n+int(size)])
+			}
+
+			n += int(size)
+		default:
+			return ""
+		}
+
+		if n > len(b) {
+			return ""
+		}
+
+		b = b[n:]
+	}
+
+	return ""
+}
+
+// Encodes the hash of a literal the way newer versions of the IDL do.
+func encodeLiteralHash(hash string) []byte {
+	b := proto.EncodeVarint(literalHashFieldNumber<<3 | proto.WireBytes)
+	b = append(b, proto.EncodeVarint(uint64(len(hash)))...)
+	return append(b, hash...)
+}
+
+// Replaces the literals that have a hash with literals that only hold the hash, so that the cache keys of inputs depend
+// on the hashes instead of e.g. the URIs of the data the literals point to. Unknown fields are left out of the hashes of
+// literals, the hash is held in a generic literal instead.
+func hashify(literal *core.Literal) *core.Literal {
+	if hash := GetLiteralHash(literal); len(hash) > 0 {
+		return &core.Literal{Value: &core.Literal_Scalar{Scalar: &core.Scalar{Value: &core.Scalar_Generic{
+			Generic: &structpb.Struct{Fields: map[string]*structpb.Value{
+				literalHashKey: {Kind: &structpb.Value_StringValue{StringValue: hash}},
+			}},
+		}}}}
+	}
+
+	if collection := literal.GetCollection(); collection != nil {
+		literals := make([]*core.Literal, 0, len(collection.Literals))
+		for _, l := range collection.Literals {
+			literals = append(literals, hashify(l))
+		}
+
+		return &core.Literal{Value: &core.Literal_Collection{Collection: &core.LiteralCollection{Literals: literals}}}
+	}
+
+	if m := literal.GetMap(); m != nil {
+		return &core.Literal{Value: &core.Literal_Map{Map: hashifyLiteralMap(m)}}
+	}
+
+	return literal
+}
+
+func hashifyLiteralMap(literalMap *core.LiteralMap) *core.LiteralMap {
+	literals := make(map[string]*core.Literal, len(literalMap.GetLiterals()))
+	for name, literal := range literalMap.GetLiterals() {
+		literals[name] = hashify(literal)
+	}
+
+	return &core.LiteralMap{Literals: literals}
+}
+
 // Generate a tag by hashing the input values
 func GenerateArtifactTagName(ctx context.Context, inputs *core.LiteralMap) (string, error) {
 	if inputs == nil || len(inputs.Literals) == 0 {
@@ -132,6 +278,104 @@ func GenerateArtifactTagName(ctx context.Context, inputs *core.LiteralMap) (stri
 	return tag, nil
 }
 
+// Generates a tag by hashing the hashes of the input values in the order of their names, so that the tag of inputs does
+// not depend on how the literal map as a whole is serialized.
+func generateArtifactTagNameV2(ctx context.Context, inputs *core.LiteralMap) (string, error) {
+	return generateLiteralsTagName(ctx, TagHashVersionV2, inputs.GetLiterals())
+}
+
+// Generates a tag like generateArtifactTagNameV2, except that inputs with a hash are hashed by their hash instead of
+// their value.
+func generateArtifactTagNameV3(ctx context.Context, inputs *core.LiteralMap) (string, error) {
+	return generateLiteralsTagName(ctx, TagHashVersionV3, hashifyLiteralMap(inputs).GetLiterals())
+}
+
+func generateLiteralsTagName(ctx context.Context, version TagHashVersion, literals map[string]*core.Literal) (string, error) {
+	names := make([]string, 0, len(literals))
+	for name := range literals {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		literalHash, err := pbhash.ComputeHash(ctx, literals[name])
+		if err != nil {
+			return "", err
+		}
+
+		// Names are length prefixed so that no two sets of inputs write the same bytes.
+		_, _ = fmt.Fprintf(h, "%d:%s", len(name), name)
+		_, _ = h.Write(literalHash)
+	}
+
+	hashString := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	return fmt.Sprintf("%s_%s-%s", cachedTaskTag, version, hashString), nil
+}
+
+// TagHashVersion is the version of the hash function that generates the tags of cached artifacts from their inputs.
+// Artifacts tagged with one version are not found with the tags of another, so the version tags are written with can
+// only be changed along with the fallback to the previous versions on reads.
+type TagHashVersion = string
+
+const (
+	// TagHashVersionV1 hashes the serialized literal map of the inputs.
+	TagHashVersionV1 TagHashVersion = "v1"
+	// TagHashVersionV2 hashes the hashes of the inputs in the order of their names.
+	TagHashVersionV2 TagHashVersion = "v2"
+	// TagHashVersionV3 hashes the hashes of the inputs in the order of their names, hashing inputs that carry a hash,
+	// e.g. of the content of a dataframe, by that hash.
+	TagHashVersionV3 TagHashVersion = "v3"
+)
+
+// Tag hash versions, from the oldest to the newest.
+var tagHashVersions = []TagHashVersion{TagHashVersionV1, TagHashVersionV2, TagHashVersionV3}
+
+var tagHashFuncs = map[TagHashVersion]func(ctx context.Context, inputs *core.LiteralMap) (string, error){
+	TagHashVersionV1: GenerateArtifactTagName,
+	TagHashVersionV2: generateArtifactTagNameV2,
+	TagHashVersionV3: generateArtifactTagNameV3,
+}
+
+// GenerateVersionedArtifactTagName generates the tag of the inputs with the hash function of the given version.
+func GenerateVersionedArtifactTagName(ctx context.Context, version TagHashVersion, inputs *core.LiteralMap) (string, error) {
+	hashFunc, ok := tagHashFuncs[version]
+	if !ok {
+		return "", fmt.Errorf("unknown tag hash version [%v]", version)
+	}
+
+	return hashFunc(ctx, inputs)
+}
+
+// GenerateArtifactTagNames generates the tags of the inputs to look cached artifacts up with: the tag of the given
+// version first, followed by the tags of the older versions, from the newest to the oldest.
+func GenerateArtifactTagNames(ctx context.Context, version TagHashVersion, inputs *core.LiteralMap) ([]string, error) {
+	var tags []string
+	found := false
+	for i := len(tagHashVersions) - 1; i >= 0; i-- {
+		if tagHashVersions[i] == version {
+			found = true
+		}
+
+		if !found {
+			continue
+		}
+
+		tag, err := GenerateVersionedArtifactTagName(ctx, tagHashVersions[i], inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("unknown tag hash version [%v]", version)
+	}
+
+	return tags, nil
+}
+
 // Get the DataSetID for a task.
 // NOTE: the version of the task is a combination of both the discoverable_version and the task signature.
 // This is because the interface may of changed even if the discoverable_version hadn't.
@@ -165,6 +409,8 @@ const (
 	execProjectKey     = "exec-project"
 	execNodeIDKey      = "exec-node"
 	execTaskAttemptKey = "exec-attempt"
+	// Max age of the artifact when it was created, formatted as a Go duration.
+	maxAgeKey = "max-age"
 )
 
 // Understanding Catalog Identifiers
@@ -199,6 +445,36 @@ func GetArtifactMetadataForSource(taskExecutionID *core.TaskExecutionIdentifier)
 	}
 }
 
+// Records the max age the artifact is created with in its metadata. A max age of 0 records nothing, the artifact never
+// expires.
+func setArtifactMaxAge(md *datacatalog.Metadata, maxAge time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+
+	if md.KeyMap == nil {
+		md.KeyMap = map[string]string{}
+	}
+
+	md.KeyMap[maxAgeKey] = maxAge.String()
+}
+
+// Returns the max age the artifact was created with, 0 if it was created without one or before max ages were recorded.
+func getArtifactMaxAge(ctx context.Context, artifact *datacatalog.Artifact) time.Duration {
+	value, ok := artifact.GetMetadata().GetKeyMap()[maxAgeKey]
+	if !ok {
+		return 0
+	}
+
+	maxAge, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Warnf(ctx, "Ignoring invalid max age [%v] of artifact %v. Error: %v", value, artifact.GetId(), err)
+		return 0
+	}
+
+	return maxAge
+}
+
 // Returns the Source TaskExecutionIdentifier from the catalog metadata
 // For all the information not available it returns Unknown. This is because as of July-2020 Catalog does not have all
 // the information. After the first deployment of this code, it will have this and the "unknown's" can be phased out
@@ -4,12 +4,14 @@ import (
 	"context"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/flyteorg/flyteidl/clients/go/coreutils"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/datacatalog"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -128,6 +130,138 @@ func TestNoInputValues(t *testing.T) {
 	assert.Equal(t, tagDupe, tag)
 }
 
+func TestGenerateVersionedArtifactTagName(t *testing.T) {
+	ctx := context.TODO()
+	literalMap, err := coreutils.MakeLiteralMap(map[string]interface{}{"1": 1, "2": 2})
+	assert.NoError(t, err)
+
+	v1Tag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV1, literalMap)
+	assert.NoError(t, err)
+	assert.Equal(t, "flyte_cached-GQid5LjHbakcW68DS3P2jp80QLbiF0olFHF2hTh5bg8", v1Tag)
+
+	v2Tag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV2, literalMap)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(v2Tag, "flyte_cached_v2-"))
+	assert.NotEqual(t, v1Tag, v2Tag)
+
+	literalMap, err = coreutils.MakeLiteralMap(map[string]interface{}{"2": 2, "1": 1})
+	assert.NoError(t, err)
+	v2TagDupe, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV2, literalMap)
+	assert.NoError(t, err)
+	assert.Equal(t, v2Tag, v2TagDupe)
+
+	literalMap, err = coreutils.MakeLiteralMap(map[string]interface{}{"1": 2, "2": 1})
+	assert.NoError(t, err)
+	v2TagOther, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV2, literalMap)
+	assert.NoError(t, err)
+	assert.NotEqual(t, v2Tag, v2TagOther)
+
+	emptyTag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV2, nil)
+	assert.NoError(t, err)
+	emptyTagDupe, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV2, &core.LiteralMap{})
+	assert.NoError(t, err)
+	assert.Equal(t, emptyTag, emptyTagDupe)
+
+	_, err = GenerateVersionedArtifactTagName(ctx, "v0", literalMap)
+	assert.Error(t, err)
+}
+
+func TestGenerateArtifactTagNames(t *testing.T) {
+	ctx := context.TODO()
+	literalMap, err := coreutils.MakeLiteralMap(map[string]interface{}{"1": 1})
+	assert.NoError(t, err)
+	v1Tag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV1, literalMap)
+	assert.NoError(t, err)
+	v2Tag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV2, literalMap)
+	assert.NoError(t, err)
+	v3Tag, err := GenerateVersionedArtifactTagName(ctx, TagHashVersionV3, literalMap)
+	assert.NoError(t, err)
+
+	tags, err := GenerateArtifactTagNames(ctx, TagHashVersionV1, literalMap)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{v1Tag}, tags)
+
+	tags, err = GenerateArtifactTagNames(ctx, TagHashVersionV2, literalMap)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{v2Tag, v1Tag}, tags)
+
+	tags, err = GenerateArtifactTagNames(ctx, TagHashVersionV3, literalMap)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{v3Tag, v2Tag, v1Tag}, tags)
+
+	_, err = GenerateArtifactTagNames(ctx, "v0", literalMap)
+	assert.Error(t, err)
+}
+
+func newBlobLiteral(uri, hash string) *core.Literal {
+	literal := &core.Literal{Value: &core.Literal_Scalar{Scalar: &core.Scalar{
+		Value: &core.Scalar_Blob{Blob: &core.Blob{Uri: uri, Metadata: &core.BlobMetadata{}}},
+	}}}
+	if len(hash) > 0 {
+		literal.XXX_unrecognized = encodeLiteralHash(hash)
+	}
+
+	return literal
+}
+
+func TestGetLiteralHash(t *testing.T) {
+	assert.Equal(t, "", GetLiteralHash(nil))
+	assert.Equal(t, "", GetLiteralHash(newBlobLiteral("s3://a/b", "")))
+	assert.Equal(t, "abc", GetLiteralHash(newBlobLiteral("s3://a/b", "abc")))
+
+	// Survives the round trip through the IDL that predates the hash.
+	raw, err := proto.Marshal(newBlobLiteral("s3://a/b", "abc"))
+	assert.NoError(t, err)
+	literal := &core.Literal{}
+	assert.NoError(t, proto.Unmarshal(raw, literal))
+	assert.Equal(t, "abc", GetLiteralHash(literal))
+
+	// Other unknown fields are skipped.
+	literal.XXX_unrecognized = append([]byte{5<<3 | proto.WireVarint, 1}, literal.XXX_unrecognized...)
+	assert.Equal(t, "abc", GetLiteralHash(literal))
+
+	// Truncated fields are ignored.
+	literal.XXX_unrecognized = literal.XXX_unrecognized[:len(literal.XXX_unrecognized)-1]
+	assert.Equal(t, "", GetLiteralHash(literal))
+}
+
+func TestGenerateArtifactTagName_LiteralHash(t *testing.T) {
+	ctx := context.TODO()
+	tagOf := func(version TagHashVersion, literal *core.Literal) string {
+		tag, err := GenerateVersionedArtifactTagName(ctx, version, &core.LiteralMap{
+			Literals: map[string]*core.Literal{"df": literal},
+		})
+		assert.NoError(t, err)
+		return tag
+	}
+
+	// The same hash at different URIs hits the cache, different hashes do not.
+	assert.Equal(t, tagOf(TagHashVersionV3, newBlobLiteral("s3://a/1", "abc")), tagOf(TagHashVersionV3, newBlobLiteral("s3://a/2", "abc")))
+	assert.NotEqual(t, tagOf(TagHashVersionV3, newBlobLiteral("s3://a/1", "abc")), tagOf(TagHashVersionV3, newBlobLiteral("s3://a/1", "def")))
+	assert.NotEqual(t, tagOf(TagHashVersionV3, newBlobLiteral("s3://a/1", "")), tagOf(TagHashVersionV3, newBlobLiteral("s3://a/2", "")))
+
+	// Hashes of literals within collections and maps are honored too.
+	collectionOf := func(uri string) *core.Literal {
+		return &core.Literal{Value: &core.Literal_Collection{Collection: &core.LiteralCollection{
+			Literals: []*core.Literal{newBlobLiteral(uri, "abc")},
+		}}}
+	}
+	assert.Equal(t, tagOf(TagHashVersionV3, collectionOf("s3://a/1")), tagOf(TagHashVersionV3, collectionOf("s3://a/2")))
+
+	mapOf := func(uri string) *core.Literal {
+		return &core.Literal{Value: &core.Literal_Map{Map: &core.LiteralMap{
+			Literals: map[string]*core.Literal{"x": newBlobLiteral(uri, "abc")},
+		}}}
+	}
+	assert.Equal(t, tagOf(TagHashVersionV3, mapOf("s3://a/1")), tagOf(TagHashVersionV3, mapOf("s3://a/2")))
+
+	// The tags of the older versions are unchanged, they still hash inputs by their value.
+	for _, version := range []TagHashVersion{TagHashVersionV1, TagHashVersionV2} {
+		assert.NotEqual(t, tagOf(version, newBlobLiteral("s3://a/1", "abc")), tagOf(version, newBlobLiteral("s3://a/2", "abc")))
+		assert.Equal(t, tagOf(version, newBlobLiteral("s3://a/1", "")), tagOf(version, newBlobLiteral("s3://a/1", "abc")))
+	}
+}
+
 func TestGetOrDefault(t *testing.T) {
 	type args struct {
 		m            map[string]string
@@ -339,3 +473,57 @@ func TestDatasetIDToIdentifier(t *testing.T) {
 	assert.Equal(t, "d", id.Domain)
 	assert.Equal(t, "v", id.Version)
 }
+
+func TestGenerateTaskSpecHash(t *testing.T) {
+	ctx := context.TODO()
+	newTask := func(image string, args ...string) *core.TaskTemplate {
+		return &core.TaskTemplate{
+			Id: &core.Identifier{Name: "name", Version: "1"},
+			Interface: &core.TypedInterface{
+				Inputs: &core.VariableMap{Variables: map[string]*core.Variable{
+					"x": {Type: &core.LiteralType{Type: &core.LiteralType_Simple{Simple: core.SimpleType_INTEGER}}},
+				}},
+			},
+			Metadata: &core.TaskMetadata{DiscoveryVersion: "1", Discoverable: true},
+			Target: &core.TaskTemplate_Container{Container: &core.Container{
+				Image: image,
+				Args:  args,
+				Env:   []*core.KeyValuePair{{Key: "K", Value: "V"}},
+			}},
+		}
+	}
+
+	base, err := GenerateTaskSpecHash(ctx, newTask("image:1", "a"))
+	assert.NoError(t, err)
+	assert.Len(t, base, maxParamHashLength)
+
+	t.Run("stable", func(t *testing.T) {
+		tk := newTask("image:1", "a")
+		tk.Id.Version = "2"
+		tk.Metadata.DiscoveryVersion = "2"
+		tk.GetContainer().Env = nil
+		h, err := GenerateTaskSpecHash(ctx, tk)
+		assert.NoError(t, err)
+		assert.Equal(t, base, h)
+	})
+
+	t.Run("image", func(t *testing.T) {
+		h, err := GenerateTaskSpecHash(ctx, newTask("image:2", "a"))
+		assert.NoError(t, err)
+		assert.NotEqual(t, base, h)
+	})
+
+	t.Run("args", func(t *testing.T) {
+		h, err := GenerateTaskSpecHash(ctx, newTask("image:1", "b"))
+		assert.NoError(t, err)
+		assert.NotEqual(t, base, h)
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		tk := newTask("image:1", "a")
+		tk.Interface = nil
+		h, err := GenerateTaskSpecHash(ctx, tk)
+		assert.NoError(t, err)
+		assert.NotEqual(t, base, h)
+	})
+}
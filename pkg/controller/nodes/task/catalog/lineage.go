@@ -0,0 +1,135 @@
+package catalog
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog/datacatalog"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const percent = 100
+
+// LineageRecorder is implemented by catalog clients that can record the lineage of the outputs of tasks that are not
+// cached.
+type LineageRecorder interface {
+	// RecordLineage registers the outputs of the task execution in the catalog, without making them available as cache
+	// hits. Executions that are not sampled are skipped and return a cache disabled status.
+	RecordLineage(ctx context.Context, key catalog.Key, reader io.OutputReader, metadata catalog.Metadata) (catalog.Status, error)
+}
+
+// Prefetcher is implemented by catalog clients that can look up the cached outputs of many task executions before they
+// run.
+type Prefetcher interface {
+	// Prefetch looks up the keys and keeps the cache hits in memory for the Gets that follow. Prefetching is best effort,
+	// lookup failures are left for the Gets to surface. Returns the number of cache hits.
+	Prefetch(ctx context.Context, keys []catalog.Key) int
+}
+
+// Reserver is implemented by catalog clients that can reserve the execution of a task with a cache key for one owner,
+// so that concurrent executions with the same cache key run one at a time.
+type Reserver interface {
+	// GetOrReserve reserves the execution for the owner, unless the outputs are cached or another owner holds the
+	// reservation.
+	GetOrReserve(ctx context.Context, key catalog.Key, ownerID string) (datacatalog.Reservation, error)
+	// ExtendReservation keeps the reservation of the owner from expiring.
+	ExtendReservation(ctx context.Context, key catalog.Key, ownerID string) error
+	// ReleaseReservation releases the reservation of the owner.
+	ReleaseReservation(ctx context.Context, key catalog.Key, ownerID string) error
+}
+
+// UntaggedClient is a catalog client that can store artifacts without tagging them.
+type UntaggedClient interface {
+	catalog.Client
+	PutUntagged(ctx context.Context, key catalog.Key, reader io.OutputReader, metadata catalog.Metadata) (catalog.Status, error)
+}
+
+// LineageClient decorates a catalog client and records the lineage of sampled executions of tasks that are not cached.
+type LineageClient struct {
+	UntaggedClient
+	cfg LineageConfig
+}
+
+var _ LineageRecorder = &LineageClient{}
+var _ Prefetcher = &LineageClient{}
+var _ Reserver = &LineageClient{}
+
+// Returns whether lineage should be recorded for the execution. The decision is derived from a hash of the execution
+// name, so that either all or none of the tasks of an execution are recorded.
+func (l *LineageClient) isSampled(metadata catalog.Metadata) bool {
+	execID := metadata.TaskExecutionIdentifier.GetNodeExecutionId().GetExecutionId()
+	if execID == nil {
+		execID = metadata.WorkflowExecutionIdentifier
+	}
+
+	samplingPercent, found := l.cfg.DomainSamplingPercent[execID.GetDomain()]
+	if !found {
+		samplingPercent = l.cfg.DefaultSamplingPercent
+	}
+
+	if samplingPercent <= 0 {
+		return false
+	} else if samplingPercent >= percent {
+		return true
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(execID.GetProject() + "/" + execID.GetDomain() + "/" + execID.GetName()))
+	return int(h.Sum32()%percent) < samplingPercent
+}
+
+func (l *LineageClient) RecordLineage(ctx context.Context, key catalog.Key, reader io.OutputReader, metadata catalog.Metadata) (catalog.Status, error) {
+	if !l.isSampled(metadata) {
+		logger.Debugf(ctx, "Execution not sampled for lineage, skipping catalog write for task [%v]", key.Identifier)
+		return disabledStatus, nil
+	}
+
+	return l.PutUntagged(ctx, key, reader, metadata)
+}
+
+// Prefetch forwards to the decorated client, if it supports prefetching.
+func (l *LineageClient) Prefetch(ctx context.Context, keys []catalog.Key) int {
+	if prefetcher, ok := l.UntaggedClient.(Prefetcher); ok {
+		return prefetcher.Prefetch(ctx, keys)
+	}
+
+	return 0
+}
+
+// GetOrReserve forwards to the decorated client. If it does not support reservations, the owner always acquires them.
+func (l *LineageClient) GetOrReserve(ctx context.Context, key catalog.Key, ownerID string) (datacatalog.Reservation, error) {
+	if reserver, ok := l.UntaggedClient.(Reserver); ok {
+		return reserver.GetOrReserve(ctx, key, ownerID)
+	}
+
+	return datacatalog.Reservation{State: datacatalog.ReservationAcquired, OwnerID: ownerID}, nil
+}
+
+// ExtendReservation forwards to the decorated client, if it supports reservations.
+func (l *LineageClient) ExtendReservation(ctx context.Context, key catalog.Key, ownerID string) error {
+	if reserver, ok := l.UntaggedClient.(Reserver); ok {
+		return reserver.ExtendReservation(ctx, key, ownerID)
+	}
+
+	return nil
+}
+
+// ReleaseReservation forwards to the decorated client, if it supports reservations.
+func (l *LineageClient) ReleaseReservation(ctx context.Context, key catalog.Key, ownerID string) error {
+	if reserver, ok := l.UntaggedClient.(Reserver); ok {
+		return reserver.ReleaseReservation(ctx, key, ownerID)
+	}
+
+	return nil
+}
+
+// NewLineageClient wraps the given client so that it also records the lineage of tasks that are not cached.
+func NewLineageClient(client UntaggedClient, cfg LineageConfig) *LineageClient {
+	return &LineageClient{
+		UntaggedClient: client,
+		cfg:            cfg,
+	}
+}
@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUntaggedClient struct {
+	NOOPCatalog
+	untaggedPuts int
+}
+
+func (f *fakeUntaggedClient) PutUntagged(_ context.Context, _ catalog.Key, _ io.OutputReader, _ catalog.Metadata) (catalog.Status, error) {
+	f.untaggedPuts++
+	return catalog.NewStatus(core.CatalogCacheStatus_CACHE_DISABLED, &core.CatalogMetadata{}), nil
+}
+
+func newLineageMetadata(domain, name string) catalog.Metadata {
+	return catalog.Metadata{
+		TaskExecutionIdentifier: &core.TaskExecutionIdentifier{
+			NodeExecutionId: &core.NodeExecutionIdentifier{
+				ExecutionId: &core.WorkflowExecutionIdentifier{Project: "project", Domain: domain, Name: name},
+			},
+		},
+	}
+}
+
+func TestLineageClient_RecordLineage(t *testing.T) {
+	ctx := context.TODO()
+	client := &fakeUntaggedClient{}
+	lineage := NewLineageClient(client, LineageConfig{
+		Enabled:                true,
+		DefaultSamplingPercent: 0,
+		DomainSamplingPercent:  map[string]int{"production": 100, "staging": 50},
+	})
+
+	t.Run("sampled-domain", func(t *testing.T) {
+		s, err := lineage.RecordLineage(ctx, catalog.Key{}, nil, newLineageMetadata("production", "exec"))
+		assert.NoError(t, err)
+		assert.NotNil(t, s.GetMetadata())
+		assert.Equal(t, 1, client.untaggedPuts)
+	})
+
+	t.Run("unsampled-domain", func(t *testing.T) {
+		s, err := lineage.RecordLineage(ctx, catalog.Key{}, nil, newLineageMetadata("development", "exec"))
+		assert.NoError(t, err)
+		assert.Equal(t, core.CatalogCacheStatus_CACHE_DISABLED, s.GetCacheStatus())
+		assert.Nil(t, s.GetMetadata())
+		assert.Equal(t, 1, client.untaggedPuts)
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		sampled := 0
+		for i := 0; i < 1000; i++ {
+			md := newLineageMetadata("staging", fmt.Sprintf("exec-%d", i))
+			if lineage.isSampled(md) {
+				sampled++
+			}
+
+			// The decision is stable for the same execution.
+			assert.Equal(t, lineage.isSampled(md), lineage.isSampled(md))
+		}
+
+		assert.InDelta(t, 500, sampled, 100)
+	})
+}
@@ -6,10 +6,11 @@ import (
 	"strings"
 	"time"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/flyteorg/flytestdlib/config"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 //go:generate pflags Config --default-var defaultConfig
@@ -30,17 +31,59 @@ var (
 			MaxDuration: config.Duration{Duration: time.Minute * 10},
 		},
 		MaxErrorMessageLength: 2048,
+		NodeLostConfig: NodeLostConfig{
+			Enabled:             false,
+			NotReadyGracePeriod: config.Duration{Duration: time.Minute * 5},
+			DeletedGracePeriod:  config.Duration{Duration: time.Second * 30},
+		},
+		EventWatcherConfig: EventWatcherConfig{
+			Enabled: false,
+			Reasons: []string{"FailedScheduling", "Preempted", "Evicted"},
+		},
+		CoPilotTimeoutConfig: CoPilotTimeoutConfig{
+			Enabled:       false,
+			UploadTimeout: config.Duration{Duration: time.Minute * 30},
+		},
+		ScratchVolumeConfig: ScratchVolumeConfig{
+			Enabled:   false,
+			MountPath: "/scratch",
+			MaxSize:   "100Gi",
+		},
+		CredentialsConfig: CredentialsConfig{
+			Enabled:   false,
+			MountPath: "/var/run/flyte/credentials",
+		},
+		OutputErrorConflictConfig: OutputErrorConflictConfig{
+			Policy: OutputErrorConflictPreferLatest,
+		},
+		FanOutSpreadConfig: FanOutSpreadConfig{
+			Enabled:           false,
+			TopologyKeys:      []string{"topology.kubernetes.io/zone"},
+			MaxSkew:           1,
+			WhenUnsatisfiable: "ScheduleAnyway",
+		},
 	}
 
 	section = config.MustRegisterSection(SectionKey, defaultConfig)
 )
 
 type Config struct {
-	TaskPlugins            TaskPluginConfig `json:"task-plugins" pflag:",Task plugin configuration"`
-	MaxPluginPhaseVersions int32            `json:"max-plugin-phase-versions" pflag:",Maximum number of plugin phase versions allowed for one phase."`
-	BarrierConfig          BarrierConfig    `json:"barrier" pflag:",Config for Barrier implementation"`
-	BackOffConfig          BackOffConfig    `json:"backoff" pflag:",Config for Exponential BackOff implementation"`
-	MaxErrorMessageLength  int              `json:"maxLogMessageLength" pflag:",Max length of error message."`
+	TaskPlugins               TaskPluginConfig          `json:"task-plugins" pflag:",Task plugin configuration"`
+	MaxPluginPhaseVersions    int32                     `json:"max-plugin-phase-versions" pflag:",Maximum number of plugin phase versions allowed for one phase."`
+	BarrierConfig             BarrierConfig             `json:"barrier" pflag:",Config for Barrier implementation"`
+	BackOffConfig             BackOffConfig             `json:"backoff" pflag:",Config for Exponential BackOff implementation"`
+	MaxErrorMessageLength     int                       `json:"maxLogMessageLength" pflag:",Max length of error message."`
+	NodeLostConfig            NodeLostConfig            `json:"node-lost" pflag:",Config for detecting pods whose node was lost"`
+	EventWatcherConfig        EventWatcherConfig        `json:"event-watcher" pflag:",Config for surfacing K8s warning events of task resources"`
+	CoPilotTimeoutConfig      CoPilotTimeoutConfig      `json:"co-pilot-timeout" pflag:",Config for detecting co-pilot sidecars that hang while uploading outputs"`
+	ScratchVolumeConfig       ScratchVolumeConfig       `json:"scratch-volume" pflag:",Config for scratch volumes requested by executions"`
+	InFlightQuotaConfig       InFlightQuotaConfig       `json:"in-flight-quota" pflag:",Config for capping the number of in-flight task resources per kind"`
+	AbortConfig               AbortConfig               `json:"abort" pflag:",Config for aborting task resources"`
+	ServiceRateLimitConfig    ServiceRateLimitConfig    `json:"service-rate-limit" pflag:",Config for rate limiting the launches of tasks that call the same external service"`
+	DenylistConfig            DenylistConfig            `json:"denylist" pflag:",Config for rejecting the task types and plugins that may not run in some projects and domains"`
+	CredentialsConfig         CredentialsConfig         `json:"credentials" pflag:",Config for the short-lived credentials minted for the pods of executions"`
+	OutputErrorConflictConfig OutputErrorConflictConfig `json:"output-error-conflict" pflag:",Config for attempts that wrote both outputs and an error"`
+	FanOutSpreadConfig        FanOutSpreadConfig        `json:"fan-out-spread" pflag:",Config for spreading the pods of fan-outs over zones and nodes"`
 }
 
 type BarrierConfig struct {
@@ -60,6 +103,137 @@ type BackOffConfig struct {
 	MaxDuration config.Duration `json:"max-duration" pflag:",The cap of the backoff duration"`
 }
 
+// NodeLostConfig controls how pods that are running on deleted or NotReady nodes are detected. Such pods are failed as
+// retryable system errors once the grace period has passed, instead of waiting for Kubernetes to time them out.
+type NodeLostConfig struct {
+	Enabled             bool            `json:"enabled" pflag:",Enables failing attempts whose pod runs on a deleted or NotReady node. Requires permissions to watch nodes."`
+	NotReadyGracePeriod config.Duration `json:"not-ready-grace-period" pflag:",Duration a node has to be NotReady before its pods are considered lost"`
+	DeletedGracePeriod  config.Duration `json:"deleted-grace-period" pflag:",Duration a node has to be observed as deleted before its pods are considered lost"`
+}
+
+// EventWatcherConfig controls whether K8s Warning events of task resources are watched. Events with one of the
+// configured reasons are surfaced as the reason of the task phase, so that scheduling problems are visible without
+// kubectl access.
+type EventWatcherConfig struct {
+	Enabled bool     `json:"enabled" pflag:",Enables watching K8s Warning events of task resources. Requires permissions to watch events."`
+	Reasons []string `json:"reasons" pflag:",Reasons of the Warning events that are surfaced as the task phase reason"`
+}
+
+// CoPilotTimeoutConfig controls how long the co-pilot sidecar of a raw-container task may keep uploading outputs after
+// all other containers of the pod have terminated. Without it, a hanging upload keeps the task in Running forever.
+type CoPilotTimeoutConfig struct {
+	Enabled       bool            `json:"enabled" pflag:",Enables failing attempts whose co-pilot sidecar does not finish uploading outputs in time"`
+	UploadTimeout config.Duration `json:"upload-timeout" pflag:",Duration the co-pilot sidecar may run after all other containers of the pod terminated"`
+}
+
+// ScratchVolumeConfig controls how the scratch volumes that executions request in their execution config are attached to
+// the pods of their tasks.
+type ScratchVolumeConfig struct {
+	Enabled          bool                          `json:"enabled" pflag:",Enables attaching the scratch volume requested by an execution to the pods of its tasks"`
+	MountPath        string                        `json:"mount-path" pflag:",Path at which the scratch volume is mounted in all containers of the pod"`
+	StorageClassName string                        `json:"storage-class-name" pflag:",Storage class of ephemeral scratch volumes. Uses the cluster default if empty"`
+	MaxSize          string                        `json:"max-size" pflag:",Maximum size of a scratch volume that an execution may request"`
+	Persistent       PersistentScratchVolumeConfig `json:"persistent" pflag:",Config for persistent scratch volumes that outlive the pods of a node attempt"`
+}
+
+// PersistentScratchVolumeConfig controls the PVCs that propeller creates for node attempts that request a persistent
+// scratch volume.
+type PersistentScratchVolumeConfig struct {
+	StorageClassName      string `json:"storage-class-name" pflag:",Storage class of persistent scratch volumes. Uses the cluster default if empty"`
+	MaxClaimsPerNamespace int    `json:"max-claims-per-namespace" pflag:",Maximum number of persistent scratch volumes that may exist at once in a namespace. Unlimited if 0"`
+}
+
+// InFlightQuotaConfig caps the number of task resources of a kind that may exist at once, in total and per namespace.
+// Kinds are the lowercase K8s kinds the plugins create, e.g. pod or sparkapplication. Launches over a cap are kept
+// waiting for resources until enough resources of the kind complete or are deleted, so that a runaway fan-out does not
+// exhaust the API server and etcd.
+// Resources are counted from the informer cache of the kind, so that the caps may be overshot by the resources launched
+// within one informer sync. Pods that have succeeded or failed are not counted.
+type InFlightQuotaConfig struct {
+	Enabled         bool           `json:"enabled" pflag:",Enables capping the number of in-flight task resources"`
+	MaxPerKind      map[string]int `json:"max-per-kind" pflag:"-,Maximum number of in-flight task resources of a kind across all namespaces. Unlimited if unset"`
+	MaxPerNamespace map[string]int `json:"max-per-namespace" pflag:"-,Maximum number of in-flight task resources of a kind in one namespace. Unlimited if unset"`
+}
+
+// AbortConfig controls how task resources are aborted. They are first deleted politely, so that they can terminate
+// gracefully. Resources that are still terminating the grace period after they were due to be deleted, e.g. pods on a
+// lost node or held back by a finalizer, are then force deleted, so that aborting their node cannot hang. Their nodes
+// are only marked aborted once they are gone.
+type AbortConfig struct {
+	ForceDeleteGracePeriod config.Duration `json:"force-delete-grace-period" pflag:",Duration a resource may keep terminating after it was due to be deleted before it is force deleted. Disabled if 0"`
+}
+
+// ServiceRateLimitConfig bounds the rate at which tasks that call the same external service are launched, so that a
+// fan-out does not overload a shared database or API. Tasks declare the service they call as the service_tag key of
+// their config. Each tag has a token bucket of its own, tasks whose tag has no limit are launched without limit.
+// Launches over the rate stay queued until a token is available. Buckets are kept in memory by the propeller that
+// evaluates the workflow, so that they bound the launches cluster-wide unless workflows are sharded across propellers.
+type ServiceRateLimitConfig struct {
+	Enabled bool                 `json:"enabled" pflag:",Enables rate limiting the launches of tasks per service tag"`
+	Limits  map[string]RateLimit `json:"limits" pflag:"-,Rate limit of the launches of tasks per service tag"`
+}
+
+type RateLimit struct {
+	QPS   float64 `json:"qps" pflag:",Sustained number of launches per second"`
+	Burst int     `json:"burst" pflag:",Number of launches allowed at once"`
+}
+
+// DenylistConfig rejects the nodes of the task types and plugins that may not run in a project and domain, e.g. raw
+// containers in production, with a user error when they start. Nodes that already started are left running. It is read
+// in every round, so that changes of the config file apply without restarting propeller.
+type DenylistConfig struct {
+	Enabled bool           `json:"enabled" pflag:",Enables rejecting denylisted task types and plugins"`
+	Rules   []DenylistRule `json:"rules" pflag:"-,Task types and plugins denied per project and domain"`
+}
+
+// DenylistRule denies task types and plugins in the executions of a project and domain. Rules with no project or domain
+// apply to all of them.
+type DenylistRule struct {
+	Project   string   `json:"project" pflag:",Project of the executions the rule applies to. Applies to all projects if empty"`
+	Domain    string   `json:"domain" pflag:",Domain of the executions the rule applies to. Applies to all domains if empty"`
+	TaskTypes []string `json:"task-types" pflag:",Task types the executions may not run"`
+	Plugins   []string `json:"plugins" pflag:",IDs of the plugins the executions may not run tasks with"`
+}
+
+// CredentialsConfig controls the short-lived credentials, e.g. STS tokens or database passwords, that the registered
+// credentials minter mints for the pods of executions. The credentials of a node attempt are stored in a secret of its
+// own, mounted in all containers of its pod, and revoked once the attempt is finalized.
+type CredentialsConfig struct {
+	Enabled   bool   `json:"enabled" pflag:",Enables minting credentials for pods with the registered credentials minter"`
+	MountPath string `json:"mount-path" pflag:",Path at which the credentials secret is mounted in all containers of the pod"`
+}
+
+type OutputErrorConflictPolicy = string
+
+const (
+	// OutputErrorConflictPreferLatest uses whichever of the outputs and the error was written last, if the output
+	// reader can tell when they were written. The error is used otherwise.
+	OutputErrorConflictPreferLatest OutputErrorConflictPolicy = "PreferLatest"
+	// OutputErrorConflictPreferError always uses the error.
+	OutputErrorConflictPreferError OutputErrorConflictPolicy = "PreferError"
+	// OutputErrorConflictFail fails the attempt with a retryable system error, so that it runs again.
+	OutputErrorConflictFail OutputErrorConflictPolicy = "Fail"
+)
+
+// OutputErrorConflictConfig controls which of the outputs and the error document of an attempt are used when the
+// attempt wrote both, e.g. because its container was restarted in place after writing one of them. Either way, the
+// conflict is noted in the custom info of the task event and counted.
+type OutputErrorConflictConfig struct {
+	Policy OutputErrorConflictPolicy `json:"policy" pflag:",Which of the outputs and the error of an attempt that wrote both to use. One of PreferLatest; PreferError or Fail"`
+}
+
+// FanOutSpreadConfig spreads the pods of the sub-nodes generated by the same node, e.g. the nodes of a dynamic workflow,
+// over topology domains, so that a fan-out survives the loss of a zone or a node. The topology spread constraints and the
+// pod anti-affinity that are added select the pods with the same parent node in the same execution. Pods that already
+// have topology spread constraints or a pod anti-affinity keep them.
+type FanOutSpreadConfig struct {
+	Enabled                 bool     `json:"enabled" pflag:",Enables spreading the pods of the sub-nodes generated by the same node"`
+	TopologyKeys            []string `json:"topology-keys" pflag:",Node labels to add a topology spread constraint for"`
+	MaxSkew                 int32    `json:"max-skew" pflag:",Maximum difference between the number of pods of a fan-out in any two topology domains"`
+	WhenUnsatisfiable       string   `json:"when-unsatisfiable" pflag:",What to do with pods that would violate a spread constraint. One of ScheduleAnyway or DoNotSchedule"`
+	AntiAffinityTopologyKey string   `json:"anti-affinity-topology-key" pflag:",Node label whose domains the pods of a fan-out prefer to be spread over with a pod anti-affinity. Disabled if empty"`
+}
+
 type PluginID = string
 type TaskType = string
 
@@ -120,3 +294,7 @@ func (p TaskPluginConfig) GetEnabledPlugins() (PluginsConfigMeta, error) {
 func GetConfig() *Config {
 	return section.GetConfig().(*Config)
 }
+
+func SetConfig(cfg *Config) error {
+	return section.SetConfig(cfg)
+}
@@ -58,5 +58,30 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "backoff.base-second"), defaultConfig.BackOffConfig.BaseSecond, "The number of seconds representing the base duration of the exponential backoff")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "backoff.max-duration"), defaultConfig.BackOffConfig.MaxDuration.String(), "The cap of the backoff duration")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "maxLogMessageLength"), defaultConfig.MaxErrorMessageLength, "Max length of error message.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "node-lost.enabled"), defaultConfig.NodeLostConfig.Enabled, "Enables failing attempts whose pod runs on a deleted or NotReady node. Requires permissions to watch nodes.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "node-lost.not-ready-grace-period"), defaultConfig.NodeLostConfig.NotReadyGracePeriod.String(), "Duration a node has to be NotReady before its pods are considered lost")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "node-lost.deleted-grace-period"), defaultConfig.NodeLostConfig.DeletedGracePeriod.String(), "Duration a node has to be observed as deleted before its pods are considered lost")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "event-watcher.enabled"), defaultConfig.EventWatcherConfig.Enabled, "Enables watching K8s Warning events of task resources. Requires permissions to watch events.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "event-watcher.reasons"), []string{}, "Reasons of the Warning events that are surfaced as the task phase reason")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "co-pilot-timeout.enabled"), defaultConfig.CoPilotTimeoutConfig.Enabled, "Enables failing attempts whose co-pilot sidecar does not finish uploading outputs in time")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "co-pilot-timeout.upload-timeout"), defaultConfig.CoPilotTimeoutConfig.UploadTimeout.String(), "Duration the co-pilot sidecar may run after all other containers of the pod terminated")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "scratch-volume.enabled"), defaultConfig.ScratchVolumeConfig.Enabled, "Enables attaching the scratch volume requested by an execution to the pods of its tasks")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "scratch-volume.mount-path"), defaultConfig.ScratchVolumeConfig.MountPath, "Path at which the scratch volume is mounted in all containers of the pod")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "scratch-volume.storage-class-name"), defaultConfig.ScratchVolumeConfig.StorageClassName, "Storage class of ephemeral scratch volumes. Uses the cluster default if empty")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "scratch-volume.max-size"), defaultConfig.ScratchVolumeConfig.MaxSize, "Maximum size of a scratch volume that an execution may request")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "scratch-volume.persistent.storage-class-name"), defaultConfig.ScratchVolumeConfig.Persistent.StorageClassName, "Storage class of persistent scratch volumes. Uses the cluster default if empty")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "scratch-volume.persistent.max-claims-per-namespace"), defaultConfig.ScratchVolumeConfig.Persistent.MaxClaimsPerNamespace, "Maximum number of persistent scratch volumes that may exist at once in a namespace. Unlimited if 0")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "in-flight-quota.enabled"), defaultConfig.InFlightQuotaConfig.Enabled, "Enables capping the number of in-flight task resources")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "abort.force-delete-grace-period"), defaultConfig.AbortConfig.ForceDeleteGracePeriod.String(), "Duration a resource may keep terminating after it was due to be deleted before it is force deleted. Disabled if 0")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "service-rate-limit.enabled"), defaultConfig.ServiceRateLimitConfig.Enabled, "Enables rate limiting the launches of tasks per service tag")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "denylist.enabled"), defaultConfig.DenylistConfig.Enabled, "Enables rejecting denylisted task types and plugins")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "credentials.enabled"), defaultConfig.CredentialsConfig.Enabled, "Enables minting credentials for pods with the registered credentials minter")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "credentials.mount-path"), defaultConfig.CredentialsConfig.MountPath, "Path at which the credentials secret is mounted in all containers of the pod")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "output-error-conflict.policy"), defaultConfig.OutputErrorConflictConfig.Policy, "Which of the outputs and the error of an attempt that wrote both to use. One of PreferLatest; PreferError or Fail")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "fan-out-spread.enabled"), defaultConfig.FanOutSpreadConfig.Enabled, "Enables spreading the pods of the sub-nodes generated by the same node")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "fan-out-spread.topology-keys"), []string{}, "Node labels to add a topology spread constraint for")
+	cmdFlags.Int32(fmt.Sprintf("%v%v", prefix, "fan-out-spread.max-skew"), defaultConfig.FanOutSpreadConfig.MaxSkew, "Maximum difference between the number of pods of a fan-out in any two topology domains")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "fan-out-spread.when-unsatisfiable"), defaultConfig.FanOutSpreadConfig.WhenUnsatisfiable, "What to do with pods that would violate a spread constraint. One of ScheduleAnyway or DoNotSchedule")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "fan-out-spread.anti-affinity-topology-key"), defaultConfig.FanOutSpreadConfig.AntiAffinityTopologyKey, "Node label whose domains the pods of a fan-out prefer to be spread over with a pod anti-affinity. Disabled if empty")
 	return cmdFlags
 }
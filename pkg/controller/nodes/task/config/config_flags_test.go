@@ -211,4 +211,354 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_node-lost.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("node-lost.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("node-lost.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.NodeLostConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-lost.not-ready-grace-period", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.NodeLostConfig.NotReadyGracePeriod.String()
+
+			cmdFlags.Set("node-lost.not-ready-grace-period", testValue)
+			if vString, err := cmdFlags.GetString("node-lost.not-ready-grace-period"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.NodeLostConfig.NotReadyGracePeriod)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_node-lost.deleted-grace-period", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.NodeLostConfig.DeletedGracePeriod.String()
+
+			cmdFlags.Set("node-lost.deleted-grace-period", testValue)
+			if vString, err := cmdFlags.GetString("node-lost.deleted-grace-period"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.NodeLostConfig.DeletedGracePeriod)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_event-watcher.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("event-watcher.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("event-watcher.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.EventWatcherConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_event-watcher.reasons", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config("1,1", ",")
+
+			cmdFlags.Set("event-watcher.reasons", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("event-watcher.reasons"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.EventWatcherConfig.Reasons)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_co-pilot-timeout.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("co-pilot-timeout.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("co-pilot-timeout.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.CoPilotTimeoutConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_co-pilot-timeout.upload-timeout", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.CoPilotTimeoutConfig.UploadTimeout.String()
+
+			cmdFlags.Set("co-pilot-timeout.upload-timeout", testValue)
+			if vString, err := cmdFlags.GetString("co-pilot-timeout.upload-timeout"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.CoPilotTimeoutConfig.UploadTimeout)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_scratch-volume.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("scratch-volume.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("scratch-volume.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.ScratchVolumeConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_scratch-volume.mount-path", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("scratch-volume.mount-path", testValue)
+			if vString, err := cmdFlags.GetString("scratch-volume.mount-path"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ScratchVolumeConfig.MountPath)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_scratch-volume.storage-class-name", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("scratch-volume.storage-class-name", testValue)
+			if vString, err := cmdFlags.GetString("scratch-volume.storage-class-name"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ScratchVolumeConfig.StorageClassName)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_scratch-volume.max-size", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("scratch-volume.max-size", testValue)
+			if vString, err := cmdFlags.GetString("scratch-volume.max-size"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ScratchVolumeConfig.MaxSize)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_scratch-volume.persistent.storage-class-name", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("scratch-volume.persistent.storage-class-name", testValue)
+			if vString, err := cmdFlags.GetString("scratch-volume.persistent.storage-class-name"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ScratchVolumeConfig.Persistent.StorageClassName)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_scratch-volume.persistent.max-claims-per-namespace", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("scratch-volume.persistent.max-claims-per-namespace", testValue)
+			if vInt, err := cmdFlags.GetInt("scratch-volume.persistent.max-claims-per-namespace"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.ScratchVolumeConfig.Persistent.MaxClaimsPerNamespace)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_in-flight-quota.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("in-flight-quota.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("in-flight-quota.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.InFlightQuotaConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_abort.force-delete-grace-period", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.AbortConfig.ForceDeleteGracePeriod.String()
+
+			cmdFlags.Set("abort.force-delete-grace-period", testValue)
+			if vString, err := cmdFlags.GetString("abort.force-delete-grace-period"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.AbortConfig.ForceDeleteGracePeriod)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_service-rate-limit.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("service-rate-limit.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("service-rate-limit.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.ServiceRateLimitConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_denylist.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("denylist.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("denylist.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.DenylistConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_credentials.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("credentials.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("credentials.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.CredentialsConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_credentials.mount-path", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("credentials.mount-path", testValue)
+			if vString, err := cmdFlags.GetString("credentials.mount-path"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.CredentialsConfig.MountPath)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_output-error-conflict.policy", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("output-error-conflict.policy", testValue)
+			if vString, err := cmdFlags.GetString("output-error-conflict.policy"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.OutputErrorConflictConfig.Policy)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_fan-out-spread.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("fan-out-spread.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("fan-out-spread.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.FanOutSpreadConfig.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_fan-out-spread.topology-keys", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config("1,1", ",")
+
+			cmdFlags.Set("fan-out-spread.topology-keys", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("fan-out-spread.topology-keys"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.FanOutSpreadConfig.TopologyKeys)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_fan-out-spread.max-skew", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("fan-out-spread.max-skew", testValue)
+			if vInt32, err := cmdFlags.GetInt32("fan-out-spread.max-skew"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt32), &actual.FanOutSpreadConfig.MaxSkew)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_fan-out-spread.when-unsatisfiable", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("fan-out-spread.when-unsatisfiable", testValue)
+			if vString, err := cmdFlags.GetString("fan-out-spread.when-unsatisfiable"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.FanOutSpreadConfig.WhenUnsatisfiable)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_fan-out-spread.anti-affinity-topology-key", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("fan-out-spread.anti-affinity-topology-key", testValue)
+			if vString, err := cmdFlags.GetString("fan-out-spread.anti-affinity-topology-key"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.FanOutSpreadConfig.AntiAffinityTopologyKey)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Error code of the nodes rejected because their task type or plugin is denylisted in the project and domain of their
+// execution.
+const taskDeniedErrorCode = "TaskDenied"
+
+// getDenyReason returns why the task may not run in the project and domain of the execution, if a rule of the denylist
+// denies its task type or plugin.
+func getDenyReason(cfg config.DenylistConfig, execID *core.WorkflowExecutionIdentifier, taskType, pluginID string) (string, bool) {
+	if !cfg.Enabled {
+		return "", false
+	}
+
+	for _, rule := range cfg.Rules {
+		if len(rule.Project) > 0 && rule.Project != execID.GetProject() ||
+			len(rule.Domain) > 0 && rule.Domain != execID.GetDomain() {
+			continue
+		}
+
+		for _, deniedTaskType := range rule.TaskTypes {
+			if deniedTaskType == taskType {
+				return fmt.Sprintf("Task type [%v] is not allowed in project [%v] and domain [%v]",
+					taskType, execID.GetProject(), execID.GetDomain()), true
+			}
+		}
+
+		for _, deniedPluginID := range rule.Plugins {
+			if deniedPluginID == pluginID {
+				return fmt.Sprintf("Tasks of plugin [%v] are not allowed in project [%v] and domain [%v]",
+					pluginID, execID.GetProject(), execID.GetDomain()), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Fails the node with a user error if its task type or plugin is denylisted in the project and domain of its execution.
+// Returns true, along with the transition to return, if it is.
+func (t Handler) rejectDeniedTask(ctx context.Context, cfg config.DenylistConfig, execID *core.WorkflowExecutionIdentifier,
+	taskType, pluginID string) (handler.Transition, bool) {
+	reason, denied := getDenyReason(cfg, execID, taskType, pluginID)
+	if !denied {
+		return handler.UnknownTransition, false
+	}
+
+	t.metrics.deniedTasks.Inc(ctx)
+	logger.Warnf(ctx, "Rejecting the node. %v", reason)
+	return handler.DoTransition(handler.TransitionTypeEphemeral,
+		handler.PhaseInfoFailure(core.ExecutionError_USER, taskDeniedErrorCode, reason, nil)), true
+}
@@ -0,0 +1,80 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func TestGetDenyReason(t *testing.T) {
+	cfg := config.DenylistConfig{
+		Enabled: true,
+		Rules: []config.DenylistRule{
+			{Domain: "production", TaskTypes: []string{"raw-container"}},
+			{Project: "flytesnacks", Domain: "staging", Plugins: []string{"spark"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		project  string
+		domain   string
+		taskType string
+		pluginID string
+		denied   bool
+	}{
+		{"denied task type", "flytesnacks", "production", "raw-container", "container", true},
+		{"allowed task type", "flytesnacks", "production", "python-task", "container", false},
+		{"other domain", "flytesnacks", "development", "raw-container", "container", false},
+		{"denied plugin", "flytesnacks", "staging", "spark", "spark", true},
+		{"other project", "other", "staging", "spark", "spark", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execID := &core.WorkflowExecutionIdentifier{Project: tt.project, Domain: tt.domain, Name: "exec"}
+			reason, denied := getDenyReason(cfg, execID, tt.taskType, tt.pluginID)
+			assert.Equal(t, tt.denied, denied)
+			assert.Equal(t, tt.denied, len(reason) > 0)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		execID := &core.WorkflowExecutionIdentifier{Project: "flytesnacks", Domain: "production", Name: "exec"}
+		_, denied := getDenyReason(disabled, execID, "raw-container", "container")
+		assert.False(t, denied)
+	})
+}
+
+func TestHandler_rejectDeniedTask(t *testing.T) {
+	ctx := context.TODO()
+	h := Handler{
+		metrics: &metrics{
+			deniedTasks: labeled.NewCounter("denied_tasks", "", promutils.NewTestScope()),
+		},
+	}
+
+	cfg := config.DenylistConfig{
+		Enabled: true,
+		Rules:   []config.DenylistRule{{Domain: "production", TaskTypes: []string{"raw-container"}}},
+	}
+
+	execID := &core.WorkflowExecutionIdentifier{Project: "flytesnacks", Domain: "production", Name: "exec"}
+	trns, denied := h.rejectDeniedTask(ctx, cfg, execID, "raw-container", "container")
+	assert.True(t, denied)
+	assert.Equal(t, handler.EPhaseFailed, trns.Info().GetPhase())
+	assert.Equal(t, core.ExecutionError_USER, trns.Info().GetErr().GetKind())
+	assert.Equal(t, taskDeniedErrorCode, trns.Info().GetErr().GetCode())
+
+	_, denied = h.rejectDeniedTask(ctx, cfg, execID, "python-task", "container")
+	assert.False(t, denied)
+}
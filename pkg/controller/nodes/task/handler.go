@@ -23,21 +23,25 @@ import (
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
 	pluginK8s "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s"
 	"github.com/flyteorg/flytestdlib/contextutils"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
 	"github.com/flyteorg/flytestdlib/storage"
 	"github.com/golang/protobuf/ptypes"
 	regErrors "github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/cache"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/resourcemanager"
 	rmConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/resourcemanager/config"
 
+	controllerErrors "github.com/flyteorg/flytepropeller/pkg/controller/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/secretmanager"
+	"github.com/flyteorg/flytepropeller/pkg/controller/tracing"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 const pluginContextKey = contextutils.Key("plugin")
@@ -50,8 +54,15 @@ type metrics struct {
 	catalogPutSuccessCount labeled.Counter
 	catalogMissCount       labeled.Counter
 	catalogHitCount        labeled.Counter
+	catalogOverwriteCount  labeled.Counter
 	pluginExecutionLatency labeled.StopWatch
 	pluginQueueLatency     labeled.StopWatch
+	serviceRateLimited     labeled.Counter
+	deniedTasks            labeled.Counter
+	outputErrorConflicts   labeled.Counter
+	// Histogram of the time taken by plugins to handle a round, per plugin, with the trace of the execution as exemplar
+	// if tracing is enabled.
+	pluginExecuteLatency *prometheus.HistogramVec
 
 	// TODO We should have a metric to capture custom state size
 	scope promutils.Scope
@@ -172,6 +183,14 @@ type Handler struct {
 	barrierCache    *barrier
 	cfg             *config.Config
 	pluginScope     promutils.Scope
+	// Owners of catalog reservations that were extended within the heartbeat interval.
+	reservationHeartbeats *cache.LRUExpireCache
+	serviceRateLimiters   serviceRateLimiters
+}
+
+// ShedCache drops the recorded plugin transitions.
+func (t *Handler) ShedCache(ctx context.Context) {
+	t.barrierCache.Clear()
 }
 
 func (t *Handler) FinalizeRequired() bool {
@@ -354,7 +373,9 @@ func (t Handler) invokePlugin(ctx context.Context, p pluginCore.Plugin, tCtx *ta
 			}
 		}()
 		childCtx := context.WithValue(ctx, pluginContextKey, p.GetID())
+		start := time.Now()
 		trns, err = p.Handle(childCtx, tCtx)
+		tracing.Observe(ctx, t.metrics.pluginExecuteLatency.WithLabelValues(p.GetID()), time.Since(start).Seconds())
 		return
 	}()
 	if err != nil {
@@ -448,7 +469,18 @@ func (t Handler) invokePlugin(ctx context.Context, p pluginCore.Plugin, tCtx *ta
 		logger.Debugf(ctx, "Task success detected, calling on Task success")
 		outputCommitter := ioutils.NewRemoteFileOutputWriter(ctx, tCtx.DataStore(), tCtx.OutputWriter())
 		execID := tCtx.TaskExecutionMetadata().GetTaskExecutionID().GetID()
-		cacheStatus, ee, err := t.ValidateOutputAndCacheAdd(ctx, tCtx.NodeID(), tCtx.InputReader(), tCtx.ow.GetReader(),
+		reader, conflict, err := t.resolveOutputErrorConflict(ctx, tCtx.ow.GetReader(), config.GetConfig().OutputErrorConflictConfig)
+		if err != nil {
+			return nil, err
+		}
+		if conflict != nil {
+			pluginTrns.ObserveOutputErrorConflict(conflict)
+			if ee := conflict.executionError(); ee != nil {
+				pluginTrns.ObservedExecutionError(ee)
+				return pluginTrns, nil
+			}
+		}
+		cacheStatus, ee, err := t.ValidateOutputAndCacheAdd(ctx, tCtx.NodeID(), tCtx.InputReader(), reader,
 			outputCommitter, tCtx.ExecutionContext().GetExecutionConfig(), tCtx.tr, catalog.Metadata{
 				TaskExecutionIdentifier: &execID,
 			})
@@ -473,6 +505,14 @@ func (t Handler) Handle(ctx context.Context, nCtx handler.NodeExecutionContext)
 		return handler.UnknownTransition, errors.Wrapf(errors.UnsupportedTaskTypeError, nCtx.NodeID(), err, "unable to resolve plugin")
 	}
 
+	// The denylist is only enforced when nodes start, so that changes of it do not fail the nodes that already run.
+	if nCtx.NodeStateReader().GetTaskNodeState().PluginPhase == pluginCore.PhaseUndefined {
+		execID := nCtx.NodeExecutionMetadata().GetNodeExecutionID().GetExecutionId()
+		if trns, denied := t.rejectDeniedTask(ctx, config.GetConfig().DenylistConfig, execID, ttype, p.GetID()); denied {
+			return trns, nil
+		}
+	}
+
 	checkCatalog := !p.GetProperties().DisableNodeLevelCaching
 	if !checkCatalog {
 		logger.Infof(ctx, "Node level caching is disabled. Skipping catalog read.")
@@ -496,7 +536,8 @@ func (t Handler) Handle(ctx context.Context, nCtx handler.NodeExecutionContext)
 	// STEP 1: Check Cache
 	if ts.PluginPhase == pluginCore.PhaseUndefined && checkCatalog {
 		// This is assumed to be first time. we will check catalog and call handle
-		entry, err := t.CheckCatalogCache(ctx, tCtx.tr, nCtx.InputReader(), tCtx.ow)
+		entry, err := t.CheckCatalogCache(ctx, tCtx.tr, nCtx.InputReader(), tCtx.ow,
+			nCtx.ExecutionContext().GetExecutionConfig())
 		if err != nil {
 			logger.Errorf(ctx, "failed to check catalog cache with error")
 			return handler.UnknownTransition, err
@@ -525,6 +566,16 @@ func (t Handler) Handle(ctx context.Context, nCtx handler.NodeExecutionContext)
 			logger.Infof(ctx, "No CacheHIT. Status [%s]", entry.GetStatus().GetCacheStatus().String())
 			pluginTrns.PopulateCacheInfo(entry)
 		}
+
+		// Executions of cache serializable tasks only run once they hold the reservation of their cache key, the others
+		// stay queued and look up the cache again in the next round.
+		if entry.GetStatus().GetCacheStatus() == core.CatalogCacheStatus_CACHE_MISS {
+			if trns, waiting, err := t.waitForCatalogReservation(ctx, tCtx, nCtx); err != nil || waiting {
+				return trns, err
+			}
+		}
+	} else if ts.PluginPhase != pluginCore.PhaseUndefined && checkCatalog {
+		t.heartbeatCatalogReservation(ctx, tCtx.tr, nCtx.InputReader(), getReservationOwnerID(nCtx))
 	}
 
 	barrierTick := uint32(0)
@@ -535,10 +586,19 @@ func (t Handler) Handle(ctx context.Context, nCtx handler.NodeExecutionContext)
 		barrierTick = prevBarrier.BarrierClockTick
 		// Lets check if this value in cache is less than or equal to one in the store
 		if barrierTick <= ts.BarrierClockTick {
+			// Tasks that are not launched yet wait for the rate limit of the service they call.
+			if ts.PluginPhase == pluginCore.PhaseUndefined {
+				if trns, waiting, err := t.waitForServiceRateLimit(ctx, tCtx); err != nil || waiting {
+					return trns, err
+				}
+			}
+
 			var err error
 			pluginTrns, err = t.invokePlugin(ctx, p, tCtx, ts)
 			if err != nil {
-				return handler.UnknownTransition, errors.Wrapf(errors.RuntimeExecutionError, nCtx.NodeID(), err, "failed during plugin execution")
+				// Plugins that are refused by the systems they call, e.g. for a bad resource spec, are not retried.
+				return handler.UnknownTransition, errors.Wrapf(errors.RuntimeExecutionError, nCtx.NodeID(),
+					controllerErrors.MarkPermanent(err), "failed during plugin execution")
 			}
 			if pluginTrns.IsPreviouslyObserved() {
 				logger.Debugf(ctx, "No state change for Task, previously observed same transition. Short circuiting.")
@@ -636,6 +696,7 @@ func (t Handler) Handle(ctx context.Context, nCtx handler.NodeExecutionContext)
 		PluginPhaseVersion: pluginTrns.pInfo.Version(),
 		BarrierClockTick:   barrierTick,
 		LastPhaseUpdatedAt: time.Now(),
+		StalledReason:      getStalledReason(pluginTrns.pInfo),
 	})
 	if err != nil {
 		logger.Errorf(ctx, "Failed to store TaskNode state, err :%s", err.Error())
@@ -654,7 +715,7 @@ func (t Handler) Abort(ctx context.Context, nCtx handler.NodeExecutionContext, r
 	logger.Debugf(ctx, "Abort invoked with phase [%v]", currentPhase)
 
 	if currentPhase.IsTerminal() {
-		logger.Debugf(ctx, "Returning immediately from Abort since task is already in terminal phase.", currentPhase)
+		logger.Debugf(ctx, "Returning immediately from Abort since task is already in terminal phase [%v].", currentPhase)
 		return nil
 	}
 
@@ -679,12 +740,21 @@ func (t Handler) Abort(ctx context.Context, nCtx handler.NodeExecutionContext, r
 			}
 		}()
 
-		childCtx := context.WithValue(ctx, pluginContextKey, p.GetID())
+		// Plugins always see why they are aborted, even when it was not set by whoever started the abort.
+		cause := handler.AbortCauseFromContext(ctx, reason)
+		logger.Infof(ctx, "Aborting task of type [%s], cause [%v: %v]", ttype, cause.Kind, cause.Reason)
+		abortCtx := handler.WithAbortCause(ctx, cause)
+		childCtx := context.WithValue(abortCtx, pluginContextKey, p.GetID())
 		err = p.Abort(childCtx, tCtx)
 		return
 	}()
 
 	if err != nil {
+		if errors.IsAbortInProgress(err) {
+			logger.Infof(ctx, "Abort of the task is still in progress. %v", err)
+			return err
+		}
+
 		logger.Errorf(ctx, "Abort failed when calling plugin abort.")
 		return err
 	}
@@ -725,6 +795,8 @@ func (t Handler) Finalize(ctx context.Context, nCtx handler.NodeExecutionContext
 		return errors.Wrapf(errors.IllegalStateError, nCtx.NodeID(), err, "unable to create Handler execution context")
 	}
 
+	t.releaseCatalogReservation(ctx, tCtx.tr, nCtx.InputReader(), getReservationOwnerID(nCtx))
+
 	return func() (err error) {
 		defer func() {
 			if r := recover(); r != nil {
@@ -765,8 +837,13 @@ func New(ctx context.Context, kubeClient executors.Client, client catalog.Client
 			catalogPutSuccessCount: labeled.NewCounter("discovery_put_success_count", "Discovery Put success count", scope),
 			catalogPutFailureCount: labeled.NewCounter("discovery_put_failure_count", "Discovery Put failure count", scope),
 			catalogGetFailureCount: labeled.NewCounter("discovery_get_failure_count", "Discovery Get faillure count", scope),
+			catalogOverwriteCount:  labeled.NewCounter("discovery_overwrite_count", "Discovery Put count of outputs overwriting the cached ones", scope),
 			pluginExecutionLatency: labeled.NewStopWatch("plugin_exec_latency", "Time taken to invoke plugin for one round", time.Microsecond, scope),
 			pluginQueueLatency:     labeled.NewStopWatch("plugin_queue_latency", "Time spent by plugin in queued phase", time.Microsecond, scope),
+			serviceRateLimited:     labeled.NewCounter("service_rate_limited", "Task launches held back by the rate limit of their service", scope),
+			deniedTasks:            labeled.NewCounter("denied_tasks", "Nodes rejected because their task type or plugin is denylisted in their project and domain", scope),
+			outputErrorConflicts:   labeled.NewCounter("output_error_conflicts", "Attempts that wrote both outputs and an error", scope),
+			pluginExecuteLatency:   scope.MustNewHistogramVec("plugin_execute_latency_seconds", "Time taken by plugins to handle one round, per plugin", "plugin"),
 			scope:                  scope,
 		},
 		pluginScope:     scope.NewSubScope("plugin"),
@@ -777,5 +854,8 @@ func New(ctx context.Context, kubeClient executors.Client, client catalog.Client
 		secretManager:   secretmanager.NewFileEnvSecretManager(secretmanager.GetConfig()),
 		barrierCache:    newLRUBarrier(ctx, cfg.BarrierConfig),
 		cfg:             cfg,
+
+		reservationHeartbeats: cache.NewLRUExpireCache(maxReservationHeartbeats),
+		serviceRateLimiters:   newServiceRateLimiters(cfg.ServiceRateLimitConfig),
 	}, nil
 }
@@ -77,6 +77,9 @@ func Test_task_setDefault(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tk := &Handler{
+				metrics: &metrics{
+					pluginExecuteLatency: promutils.NewTestScope().MustNewHistogramVec("plugin_execute_latency_seconds", "", "plugin"),
+				},
 				defaultPlugin: tt.fields.defaultPlugin,
 			}
 			if err := tk.setDefault(context.TODO(), tt.args.p); (err != nil) != tt.wantErr {
@@ -324,6 +327,9 @@ func Test_task_ResolvePlugin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tk := Handler{
+				metrics: &metrics{
+					pluginExecuteLatency: promutils.NewTestScope().MustNewHistogramVec("plugin_execute_latency_seconds", "", "plugin"),
+				},
 				defaultPlugins: tt.fields.plugins,
 				defaultPlugin:  tt.fields.defaultPlugin,
 				pluginsForType: tt.fields.pluginsForType,
@@ -656,6 +662,9 @@ func Test_task_Handle_NoCatalog(t *testing.T) {
 			nCtx := createNodeContext(tt.args.startingPluginPhase, uint32(tt.args.startingPluginPhaseVersion), tt.args.expectedState, ev, "test", state, tt.want.incrParallel)
 			c := &pluginCatalogMocks.Client{}
 			tk := Handler{
+				metrics: &metrics{
+					pluginExecuteLatency: promutils.NewTestScope().MustNewHistogramVec("plugin_execute_latency_seconds", "", "plugin"),
+				},
 				cfg: &config.Config{MaxErrorMessageLength: 100},
 				defaultPlugins: map[pluginCore.TaskType]pluginCore.Plugin{
 					"test": fakeplugins.NewPhaseBasedPlugin(),
@@ -711,7 +720,7 @@ func Test_task_Handle_NoCatalog(t *testing.T) {
 
 func Test_task_Handle_Catalog(t *testing.T) {
 
-	createNodeContext := func(recorder events.TaskEventRecorder, ttype string, s *taskNodeStateHolder) *nodeMocks.NodeExecutionContext {
+	createNodeContext := func(recorder events.TaskEventRecorder, ttype string, s *taskNodeStateHolder, overwriteCache bool) *nodeMocks.NodeExecutionContext {
 		wfExecID := &core.WorkflowExecutionIdentifier{
 			Project: "project",
 			Domain:  "domain",
@@ -796,7 +805,7 @@ func Test_task_Handle_Catalog(t *testing.T) {
 		nCtx.OnEnqueueOwnerFunc().Return(nil)
 
 		executionContext := &mocks.ExecutionContext{}
-		executionContext.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{})
+		executionContext.OnGetExecutionConfig().Return(v1alpha1.ExecutionConfig{OverwriteCache: overwriteCache})
 		executionContext.OnGetEventVersion().Return(v1alpha1.EventVersion0)
 		executionContext.OnGetParentInfo().Return(nil)
 		nCtx.OnExecutionContext().Return(executionContext)
@@ -825,6 +834,7 @@ func Test_task_Handle_Catalog(t *testing.T) {
 		catalogFetch      bool
 		catalogFetchError bool
 		catalogWriteError bool
+		overwriteCache    bool
 	}
 	type want struct {
 		handlerPhase handler.EPhase
@@ -866,6 +876,16 @@ func Test_task_Handle_Catalog(t *testing.T) {
 				eventPhase:   core.TaskExecution_SUCCEEDED,
 			},
 		},
+		{
+			"cache-overwrite",
+			args{
+				overwriteCache: true,
+			},
+			want{
+				handlerPhase: handler.EPhaseSuccess,
+				eventPhase:   core.TaskExecution_SUCCEEDED,
+			},
+		},
 		{
 			"cache-write-err",
 			args{
@@ -881,7 +901,7 @@ func Test_task_Handle_Catalog(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			state := &taskNodeStateHolder{}
 			ev := &fakeBufferedTaskEventRecorder{}
-			nCtx := createNodeContext(ev, "test", state)
+			nCtx := createNodeContext(ev, "test", state, tt.args.overwriteCache)
 			c := &pluginCatalogMocks.Client{}
 			if tt.args.catalogFetch {
 				or := &ioMocks.OutputReader{}
@@ -930,6 +950,10 @@ func Test_task_Handle_Catalog(t *testing.T) {
 					assert.NoError(t, err)
 					assert.True(t, r.Exists())
 				}
+				if tt.args.overwriteCache {
+					c.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+					c.AssertCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				}
 			}
 		})
 	}
@@ -1267,6 +1291,7 @@ func Test_task_Abort(t *testing.T) {
 		tr := &nodeMocks.TaskReader{}
 		tr.OnGetTaskID().Return(taskID)
 		tr.OnGetTaskType().Return("x")
+		tr.OnReadMatch(mock.Anything).Return(&core.TaskTemplate{}, nil)
 
 		ns := &flyteMocks.ExecutableNodeStatus{}
 		ns.OnGetDataDir().Return(storage.DataReference("data-dir"))
@@ -1329,8 +1354,21 @@ func Test_task_Abort(t *testing.T) {
 		defaultPluginCallback func() pluginCore.Plugin
 	}
 	type args struct {
-		ev *fakeBufferedTaskEventRecorder
+		ev  *fakeBufferedTaskEventRecorder
+		ctx context.Context
 	}
+	withCause := func(cause handler.AbortCause) func() pluginCore.Plugin {
+		return func() pluginCore.Plugin {
+			p := &pluginCoreMocks.Plugin{}
+			p.On("GetID").Return("id")
+			p.OnGetProperties().Return(pluginCore.PluginProperties{})
+			p.On("Abort", mock.MatchedBy(func(ctx context.Context) bool {
+				return handler.AbortCauseFromContext(ctx, "") == cause
+			}), mock.Anything).Return(nil)
+			return p
+		}
+	}
+	timeout := handler.AbortCause{Kind: handler.AbortCauseTimeout, Reason: "node timed out"}
 	tests := []struct {
 		name        string
 		fields      fields
@@ -1340,7 +1378,7 @@ func Test_task_Abort(t *testing.T) {
 	}{
 		{"no-plugin", fields{defaultPluginCallback: func() pluginCore.Plugin {
 			return nil
-		}}, args{nil}, true, false},
+		}}, args{}, true, false},
 
 		{"abort-fails", fields{defaultPluginCallback: func() pluginCore.Plugin {
 			p := &pluginCoreMocks.Plugin{}
@@ -1348,7 +1386,7 @@ func Test_task_Abort(t *testing.T) {
 			p.OnGetProperties().Return(pluginCore.PluginProperties{})
 			p.On("Abort", mock.Anything, mock.Anything).Return(fmt.Errorf("error"))
 			return p
-		}}, args{nil}, true, true},
+		}}, args{}, true, true},
 		{"abort-success", fields{defaultPluginCallback: func() pluginCore.Plugin {
 			p := &pluginCoreMocks.Plugin{}
 			p.On("GetID").Return("id")
@@ -1356,16 +1394,27 @@ func Test_task_Abort(t *testing.T) {
 			p.On("Abort", mock.Anything, mock.Anything).Return(nil)
 			return p
 		}}, args{ev: &fakeBufferedTaskEventRecorder{}}, false, true},
+		{"abort-cause-default", fields{defaultPluginCallback: withCause(handler.AbortCause{Kind: handler.AbortCauseUnknown, Reason: "reason"})},
+			args{ev: &fakeBufferedTaskEventRecorder{}}, false, true},
+		{"abort-cause-timeout", fields{defaultPluginCallback: withCause(timeout)},
+			args{ev: &fakeBufferedTaskEventRecorder{}, ctx: handler.WithAbortCause(context.TODO(), timeout)}, false, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := tt.fields.defaultPluginCallback()
 			tk := Handler{
+				metrics: &metrics{
+					pluginExecuteLatency: promutils.NewTestScope().MustNewHistogramVec("plugin_execute_latency_seconds", "", "plugin"),
+				},
 				defaultPlugin:   m,
 				resourceManager: noopRm,
 			}
 			nCtx := createNodeCtx(tt.args.ev)
-			if err := tk.Abort(context.TODO(), nCtx, "reason"); (err != nil) != tt.wantErr {
+			ctx := tt.args.ctx
+			if ctx == nil {
+				ctx = context.TODO()
+			}
+			if err := tk.Abort(ctx, nCtx, "reason"); (err != nil) != tt.wantErr {
 				t.Errorf("Handler.Abort() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			c := 0
@@ -1411,6 +1460,7 @@ func Test_task_Abort_v1(t *testing.T) {
 		tr := &nodeMocks.TaskReader{}
 		tr.OnGetTaskID().Return(taskID)
 		tr.OnGetTaskType().Return("x")
+		tr.OnReadMatch(mock.Anything).Return(&core.TaskTemplate{}, nil)
 
 		ns := &flyteMocks.ExecutableNodeStatus{}
 		ns.OnGetDataDir().Return(storage.DataReference("data-dir"))
@@ -1505,6 +1555,9 @@ func Test_task_Abort_v1(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			m := tt.fields.defaultPluginCallback()
 			tk := Handler{
+				metrics: &metrics{
+					pluginExecuteLatency: promutils.NewTestScope().MustNewHistogramVec("plugin_execute_latency_seconds", "", "plugin"),
+				},
 				defaultPlugin:   m,
 				resourceManager: noopRm,
 			}
@@ -1555,6 +1608,7 @@ func Test_task_Finalize(t *testing.T) {
 	tr := &nodeMocks.TaskReader{}
 	tr.OnGetTaskID().Return(taskID)
 	tr.OnGetTaskType().Return("x")
+	tr.OnReadMatch(mock.Anything).Return(&core.TaskTemplate{}, nil)
 
 	ns := &flyteMocks.ExecutableNodeStatus{}
 	ns.OnGetDataDir().Return(storage.DataReference("data-dir"))
@@ -1646,6 +1700,9 @@ func Test_task_Finalize(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			m := tt.fields.defaultPluginCallback()
 			tk := Handler{
+				metrics: &metrics{
+					pluginExecuteLatency: promutils.NewTestScope().MustNewHistogramVec("plugin_execute_latency_seconds", "", "plugin"),
+				},
 				defaultPlugin:   m,
 				resourceManager: noopRm,
 			}
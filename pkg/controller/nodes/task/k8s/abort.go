@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodeErrors "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// abortInStages deletes the resource politely, and force deletes it once it has kept terminating for the grace period
+// after it was due to be deleted. It returns an AbortInProgress error until the resource is gone, so that its node is
+// only marked aborted then.
+func (e *PluginManager) abortInStages(ctx context.Context, tCtx pluginsCore.TaskExecutionContext, o client.Object,
+	gracePeriod time.Duration, now time.Time) error {
+
+	nsName := k8stypes.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+	if err := e.kubeClient.GetClient().Get(ctx, nsName, o); err != nil {
+		if IsK8sObjectNotExists(err) {
+			return nil
+		}
+
+		logger.Warningf(ctx, "Failed to get the Resource with name: %v before aborting it. Error: %v", nsName, err)
+		return err
+	}
+
+	nodeID := tCtx.TaskExecutionMetadata().GetTaskExecutionID().GetID().NodeExecutionId.GetNodeId()
+	deletionTimestamp := o.GetDeletionTimestamp()
+	if deletionTimestamp == nil {
+		if err := e.kubeClient.GetClient().Delete(ctx, o); err != nil {
+			if IsK8sObjectNotExists(err) {
+				return nil
+			}
+
+			logger.Warningf(ctx, "Failed to delete the Resource with name: %v. Error: %v", nsName, err)
+			return err
+		}
+
+		return nodeErrors.Errorf(nodeErrors.AbortInProgress, nodeID, "deleted resource [%v], waiting for it to terminate", nsName)
+	}
+
+	if deadline := deletionTimestamp.Add(gracePeriod); now.Before(deadline) {
+		return nodeErrors.Errorf(nodeErrors.AbortInProgress, nodeID,
+			"resource [%v] is terminating, it will be force deleted if it still exists at [%v]", nsName, deadline)
+	}
+
+	logger.Warnf(ctx, "Force deleting the Resource with name: %v, it is still terminating [%v] after it was due to be deleted",
+		nsName, now.Sub(deletionTimestamp.Time))
+	if err := e.ClearFinalizers(ctx, o); err != nil {
+		return err
+	}
+
+	err := e.kubeClient.GetClient().Delete(ctx, o, client.GracePeriodSeconds(0),
+		client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !IsK8sObjectNotExists(err) {
+		logger.Warningf(ctx, "Failed to force delete the Resource with name: %v. Error: %v", nsName, err)
+		return err
+	}
+
+	e.metrics.ResourcesForceDeleted.Inc(ctx)
+	return nil
+}
@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pluginsCoreMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core/mocks"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s"
+	pluginsk8sMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s/mocks"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nodeErrors "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+// terminatingFakeClient keeps objects terminating after they are deleted, unless they are force deleted.
+type terminatingFakeClient struct {
+	client.Client
+	deletionTimestamp *metav1.Time
+	forceDeleted      bool
+}
+
+func (c *terminatingFakeClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if err := c.Client.Get(ctx, key, obj); err != nil {
+		return err
+	}
+
+	obj.SetDeletionTimestamp(c.deletionTimestamp)
+	return nil
+}
+
+func (c *terminatingFakeClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	deleteOpts := &client.DeleteOptions{}
+	deleteOpts.ApplyOptions(opts)
+	if deleteOpts.GracePeriodSeconds == nil || *deleteOpts.GracePeriodSeconds != 0 {
+		if c.deletionTimestamp == nil {
+			c.deletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+
+		return nil
+	}
+
+	c.forceDeleted = deleteOpts.PropagationPolicy != nil && *deleteOpts.PropagationPolicy == metav1.DeletePropagationBackground
+	return c.Client.Delete(ctx, obj)
+}
+
+func (c *terminatingFakeClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	obj.SetDeletionTimestamp(nil)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func TestPluginManager_AbortInStages(t *testing.T) {
+	ctx := context.TODO()
+	tctx := getMockTaskContext(PluginPhaseStarted, PluginPhaseStarted)
+	newPod := func() *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns", Finalizers: []string{finalizer}}}
+	}
+
+	newPluginManager := func(objects ...client.Object) (*PluginManager, *terminatingFakeClient) {
+		fc := &terminatingFakeClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()}
+		kubeClient := &pluginsCoreMock.KubeClient{}
+		kubeClient.OnGetClient().Return(fc)
+		return &PluginManager{kubeClient: kubeClient, metrics: newPluginMetrics(promutils.NewTestScope())}, fc
+	}
+
+	exists := func(c client.Client) bool {
+		return c.Get(ctx, k8stypes.NamespacedName{Namespace: "ns", Name: "test"}, &v1.Pod{}) == nil
+	}
+
+	t.Run("gone", func(t *testing.T) {
+		pluginManager, _ := newPluginManager()
+		assert.NoError(t, pluginManager.abortInStages(ctx, tctx, newPod(), time.Minute, time.Now()))
+	})
+
+	t.Run("polite then forced", func(t *testing.T) {
+		pluginManager, fc := newPluginManager(newPod())
+		now := time.Now()
+
+		err := pluginManager.abortInStages(ctx, tctx, newPod(), time.Minute, now)
+		assert.True(t, nodeErrors.Matches(err, nodeErrors.AbortInProgress))
+		assert.NotNil(t, fc.deletionTimestamp)
+
+		// Still terminating within the grace period.
+		err = pluginManager.abortInStages(ctx, tctx, newPod(), time.Minute, now.Add(30*time.Second))
+		assert.True(t, nodeErrors.Matches(err, nodeErrors.AbortInProgress))
+		assert.False(t, fc.forceDeleted)
+		assert.True(t, exists(fc.Client))
+
+		assert.NoError(t, pluginManager.abortInStages(ctx, tctx, newPod(), time.Minute, now.Add(2*time.Minute)))
+		assert.True(t, fc.forceDeleted)
+		assert.False(t, exists(fc.Client))
+	})
+}
+
+func TestPluginManager_Abort_ForceDeleteGracePeriod(t *testing.T) {
+	ctx := context.TODO()
+	cfg := nodeTaskConfig.GetConfig()
+	assert.NoError(t, nodeTaskConfig.SetConfig(&nodeTaskConfig.Config{
+		AbortConfig: nodeTaskConfig.AbortConfig{ForceDeleteGracePeriod: config.Duration{Duration: time.Minute}},
+	}))
+	defer func() { assert.NoError(t, nodeTaskConfig.SetConfig(cfg)) }()
+
+	tctx := getMockTaskContext(PluginPhaseStarted, PluginPhaseStarted)
+	fc := &terminatingFakeClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}).Build()}
+	kubeClient := &pluginsCoreMock.KubeClient{}
+	kubeClient.OnGetClient().Return(fc)
+	mockResourceHandler := &pluginsk8sMock.Plugin{}
+	mockResourceHandler.OnGetProperties().Return(k8s.PluginProperties{})
+	mockResourceHandler.OnBuildIdentityResourceMatch(mock.Anything, mock.Anything).Return(&v1.Pod{}, nil)
+	pluginManager := &PluginManager{plugin: mockResourceHandler, kubeClient: kubeClient,
+		metrics: newPluginMetrics(promutils.NewTestScope())}
+
+	// The node is not aborted while its pod terminates.
+	err := pluginManager.Abort(ctx, tctx)
+	assert.True(t, nodeErrors.IsAbortInProgress(err))
+	assert.NotNil(t, fc.deletionTimestamp)
+
+	fc.deletionTimestamp = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	assert.NoError(t, pluginManager.Abort(ctx, tctx))
+	assert.True(t, fc.forceDeleted)
+}
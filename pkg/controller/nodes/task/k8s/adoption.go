@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"context"
+	"strconv"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compilerK8s "github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const (
+	// AttemptLabel is the retry attempt of the task execution that owns a resource.
+	AttemptLabel = "attempt"
+	// Set by the node executor on every task execution, it cannot be imported from there without a cycle.
+	nodeIDLabel = "node-id"
+)
+
+// addAttemptLabel labels resources of task executions, i.e. those labeled with an execution and a node, with the retry
+// attempt that owns them, so that together the labels identify the attempt.
+func addAttemptLabel(taskCtx pluginsCore.TaskExecutionMetadata, o client.Object) {
+	labels := o.GetLabels()
+	if len(labels[compilerK8s.ExecutionIDLabel]) == 0 || len(labels[nodeIDLabel]) == 0 {
+		return
+	}
+
+	labels[AttemptLabel] = strconv.FormatUint(uint64(taskCtx.GetTaskExecutionID().GetID().RetryAttempt), 10)
+	o.SetLabels(labels)
+}
+
+// findExistingResource returns the name of a resource that already exists for the execution, node and attempt of the
+// task, if any. The resource may have been created by a previous propeller that crashed before it recorded the launch
+// in the plugin state, in which case it must be adopted rather than launched a second time. Resources are named after
+// the attempt, so the resource is looked up in the informer cache by that name.
+func (e *PluginManager) findExistingResource(ctx context.Context, taskCtx pluginsCore.TaskExecutionMetadata) (string, bool, error) {
+	if e.resourceLevelMonitor == nil || e.resourceLevelMonitor.sharedInformer == nil {
+		return "", false, nil
+	}
+
+	execID := taskCtx.GetLabels()[compilerK8s.ExecutionIDLabel]
+	nodeID := taskCtx.GetLabels()[nodeIDLabel]
+	if len(execID) == 0 || len(nodeID) == 0 {
+		return "", false, nil
+	}
+
+	key := taskCtx.GetNamespace() + "/" + taskCtx.GetTaskExecutionID().GetGeneratedName()
+	obj, exists, err := e.resourceLevelMonitor.sharedInformer.GetIndexer().GetByKey(key)
+	if err != nil || !exists {
+		return "", false, err
+	}
+
+	metadata, err := meta.Accessor(obj)
+	if err != nil || metadata.GetDeletionTimestamp() != nil {
+		return "", false, nil
+	}
+
+	attempt := strconv.FormatUint(uint64(taskCtx.GetTaskExecutionID().GetID().RetryAttempt), 10)
+	labels := metadata.GetLabels()
+	if labels[compilerK8s.ExecutionIDLabel] != execID || labels[nodeIDLabel] != nodeID || labels[AttemptLabel] != attempt {
+		return "", false, nil
+	}
+
+	logger.Infof(ctx, "Found existing resource [%v] of attempt [%v]", key, attempt)
+	e.metrics.ResourcesAdopted.Inc(contextutils.WithNamespace(ctx, taskCtx.GetNamespace()))
+	return metadata.GetName(), true, nil
+}
+
+// setAdoptedName names the identity resource of the task after the resource that was adopted for it, if any.
+func setAdoptedName(tCtx pluginsCore.TaskExecutionContext, o client.Object) {
+	ps := PluginState{}
+	if _, err := tCtx.PluginStateReader().Get(&ps); err == nil && len(ps.ResourceName) > 0 {
+		o.SetName(ps.ResourceName)
+	}
+}
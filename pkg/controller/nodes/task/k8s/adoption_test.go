@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	pluginsCoreMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core/mocks"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/flytek8s/config"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s"
+	pluginsk8sMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s/mocks"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestPluginManager_Handle_Adoption(t *testing.T) {
+	ctx := context.TODO()
+	taskLabels := map[string]string{"execution-id": "wf", "node-id": "n1"}
+	tm := getMockTaskExecutionMetadataCustom("wf-n1-0", "ns", map[string]string{}, taskLabels, metav1.OwnerReference{})
+
+	newPod := func(name string, attempt string, deleted bool) *v1.Pod {
+		p := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{"execution-id": "wf", "node-id": "n1", AttemptLabel: attempt},
+		}}
+
+		if deleted {
+			now := metav1.Now()
+			p.DeletionTimestamp = &now
+		}
+
+		return p
+	}
+
+	newPluginManager := func(pods ...*v1.Pod) *PluginManager {
+		informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &v1.Pod{}, 0,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		for _, p := range pods {
+			assert.NoError(t, informer.GetIndexer().Add(p))
+		}
+
+		return &PluginManager{
+			metrics: newPluginMetrics(promutils.NewTestScope()),
+			resourceLevelMonitor: &ResourceLevelMonitor{
+				sharedInformer: informer,
+				gvk:            schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			},
+		}
+	}
+
+	t.Run("attempt label", func(t *testing.T) {
+		p := pluginsk8sMock.Plugin{}
+		p.OnGetProperties().Return(k8s.PluginProperties{})
+		pluginManager := PluginManager{plugin: &p}
+		o := &v1.Pod{}
+		pluginManager.AddObjectMetadata(tm, o, config.GetK8sPluginConfig())
+		assert.Equal(t, "0", o.GetLabels()[AttemptLabel])
+	})
+
+	t.Run("found", func(t *testing.T) {
+		pluginManager := newPluginManager(newPod("other-attempt", "1", false), newPod("wf-n1-0", "0", false))
+		name, found, err := pluginManager.findExistingResource(ctx, tm)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "wf-n1-0", name)
+	})
+
+	t.Run("other attempt", func(t *testing.T) {
+		pluginManager := newPluginManager(newPod("wf-n1-0", "1", false))
+		_, found, err := pluginManager.findExistingResource(ctx, tm)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		pluginManager := newPluginManager(newPod("wf-n1-0", "0", true))
+		_, found, err := pluginManager.findExistingResource(ctx, tm)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("not a task execution", func(t *testing.T) {
+		pluginManager := newPluginManager(newPod("wf-n1-0", "0", false))
+		_, found, err := pluginManager.findExistingResource(ctx, getMockTaskExecutionMetadata())
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("adopted", func(t *testing.T) {
+		tCtx := &pluginsCoreMock.TaskExecutionContext{}
+		tCtx.OnTaskExecutionMetadata().Return(tm)
+		stateReader := &pluginsCoreMock.PluginStateReader{}
+		stateReader.OnGetMatch(mock.Anything).Return(uint8(0), nil)
+		tCtx.OnPluginStateReader().Return(stateReader)
+		stateWriter := &pluginsCoreMock.PluginStateWriter{}
+		stateWriter.OnPutMatch(mock.Anything, mock.MatchedBy(func(i interface{}) bool {
+			ps, ok := i.(*PluginState)
+			return ok && ps.Phase == PluginPhaseStarted && ps.ResourceName == "wf-n1-0"
+		})).Return(nil)
+		tCtx.OnPluginStateWriter().Return(stateWriter)
+
+		// The plugin is not asked to build a resource, nothing is launched.
+		pluginManager := newPluginManager(newPod("wf-n1-0", "0", false))
+		pluginManager.plugin = &pluginsk8sMock.Plugin{}
+		transition, err := pluginManager.Handle(ctx, tCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, pluginsCore.PhaseQueued, transition.Info().Phase())
+		stateWriter.AssertNumberOfCalls(t, "Put", 1)
+	})
+
+	t.Run("adopted name", func(t *testing.T) {
+		tCtx := &pluginsCoreMock.TaskExecutionContext{}
+		stateReader := &pluginsCoreMock.PluginStateReader{}
+		stateReader.OnGetMatch(mock.MatchedBy(func(i interface{}) bool {
+			ps, ok := i.(*PluginState)
+			if ok {
+				ps.ResourceName = "duplicate"
+			}
+			return ok
+		})).Return(uint8(pluginStateVersion), nil)
+		tCtx.OnPluginStateReader().Return(stateReader)
+
+		o := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "wf-n1-0"}}
+		setAdoptedName(tCtx, o)
+		assert.Equal(t, "duplicate", o.GetName())
+	})
+}
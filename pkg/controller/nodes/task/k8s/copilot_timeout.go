@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+// Checks whether the co-pilot sidecar of a raw-container task is still uploading outputs long after all other containers
+// of the pod have terminated. The pod stays Running for as long as the sidecar runs, so a hanging upload would otherwise
+// keep the task in Running until it hits its active deadline, if it has one at all.
+func checkCoPilotUploadTimeout(o client.Object, namePrefix string, cfg nodeTaskConfig.CoPilotTimeoutConfig,
+	now time.Time) (pluginsCore.PhaseInfo, bool) {
+
+	pod, ok := o.(*v1.Pod)
+	if !cfg.Enabled || !ok || len(namePrefix) == 0 {
+		return pluginsCore.PhaseInfoUndefined, false
+	}
+
+	var uploader string
+	var finishedAt time.Time
+	for _, c := range pod.Status.ContainerStatuses {
+		switch {
+		case strings.HasPrefix(c.Name, namePrefix):
+			if c.State.Running != nil {
+				uploader = c.Name
+			}
+		case c.State.Terminated == nil:
+			// A task container is still running, the sidecar is not expected to have finished yet.
+			return pluginsCore.PhaseInfoUndefined, false
+		case c.State.Terminated.FinishedAt.Time.After(finishedAt):
+			finishedAt = c.State.Terminated.FinishedAt.Time
+		}
+	}
+
+	if len(uploader) == 0 || finishedAt.IsZero() || now.Sub(finishedAt) < cfg.UploadTimeout.Duration {
+		return pluginsCore.PhaseInfoUndefined, false
+	}
+
+	message := fmt.Sprintf("co-pilot sidecar [%s] of pod [%s] did not finish uploading outputs within [%v] after the"+
+		" task container terminated", uploader, pod.Name, cfg.UploadTimeout.Duration)
+	return PodFailureCoPilotUploadTimeout.ToPhaseInfo(message, nil), true
+}
@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func TestCheckCoPilotUploadTimeout(t *testing.T) {
+	now := time.Now()
+	cfg := nodeTaskConfig.CoPilotTimeoutConfig{
+		Enabled:       true,
+		UploadTimeout: config.Duration{Duration: time.Minute},
+	}
+
+	newPod := func(main v1.ContainerState) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "main", State: main},
+					{Name: "flyte-copilot-sidecar", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				},
+			},
+		}
+	}
+
+	terminated := func(finishedAt time.Time) v1.ContainerState {
+		return v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(finishedAt)}}
+	}
+
+	t.Run("timed-out", func(t *testing.T) {
+		p, timedOut := checkCoPilotUploadTimeout(newPod(terminated(now.Add(-2*time.Minute))), "flyte-copilot-", cfg, now)
+		assert.True(t, timedOut)
+		assert.Equal(t, pluginsCore.PhaseRetryableFailure, p.Phase())
+		assert.Equal(t, PodFailureCoPilotUploadTimeout.Code, p.Err().GetCode())
+	})
+
+	t.Run("uploading", func(t *testing.T) {
+		_, timedOut := checkCoPilotUploadTimeout(newPod(terminated(now.Add(-time.Second))), "flyte-copilot-", cfg, now)
+		assert.False(t, timedOut)
+	})
+
+	t.Run("task-running", func(t *testing.T) {
+		_, timedOut := checkCoPilotUploadTimeout(newPod(v1.ContainerState{Running: &v1.ContainerStateRunning{}}),
+			"flyte-copilot-", cfg, now)
+		assert.False(t, timedOut)
+	})
+
+	t.Run("no-copilot", func(t *testing.T) {
+		_, timedOut := checkCoPilotUploadTimeout(newPod(terminated(now.Add(-2*time.Minute))), "other-", cfg, now)
+		assert.False(t, timedOut)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		_, timedOut := checkCoPilotUploadTimeout(newPod(terminated(now.Add(-2*time.Minute))), "flyte-copilot-", disabled, now)
+		assert.False(t, timedOut)
+	})
+}
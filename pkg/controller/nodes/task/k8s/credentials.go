@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"context"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/utils"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const (
+	// Label of the secrets that hold the credentials minted for pods.
+	credentialsLabel = "flyte.org/credentials"
+
+	credentialsSecretSuffix = "-credentials"
+	credentialsVolumeName   = "flyte-credentials"
+)
+
+// CredentialsMinter mints short-lived credentials, e.g. STS tokens or database passwords, scoped to a node attempt.
+type CredentialsMinter interface {
+	// Mint returns the credentials of the node attempt, by the name of the file they are mounted as. It is called every
+	// time the pod of the attempt is about to be created. No secret is created if it returns no credentials.
+	Mint(ctx context.Context, taskCtx pluginsCore.TaskExecutionMetadata) (map[string][]byte, error)
+	// Revoke revokes the credentials minted for the node attempt. It is called when the attempt is finalized, whether
+	// or not credentials were minted for it, and may be called more than once.
+	Revoke(ctx context.Context, taskCtx pluginsCore.TaskExecutionMetadata) error
+}
+
+var credentialsMinter CredentialsMinter
+
+// RegisterCredentialsMinter sets the minter of the credentials of pods. Builds of propeller that mint credentials are
+// expected to register theirs before the plugins are initialized, e.g. in an init func.
+func RegisterCredentialsMinter(minter CredentialsMinter) {
+	credentialsMinter = minter
+}
+
+// The credentials of a node attempt are held by one secret, named after the pod of the attempt.
+func getCredentialsSecretName(podName string) string {
+	return podName + credentialsSecretSuffix
+}
+
+// Mints the credentials of the pod, stores them in its credentials secret and mounts the secret in all of its
+// containers. Objects other than pods are left unchanged.
+func (e *PluginManager) mintCredentials(ctx context.Context, taskCtx pluginsCore.TaskExecutionMetadata, o client.Object,
+	cfg nodeTaskConfig.CredentialsConfig) error {
+
+	pod, ok := o.(*v1.Pod)
+	if !cfg.Enabled || !ok || e.credentialsMinter == nil {
+		return nil
+	}
+
+	credentials, err := e.credentialsMinter.Mint(ctx, taskCtx)
+	if err != nil || len(credentials) == 0 {
+		return err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getCredentialsSecretName(pod.GetName()),
+			Namespace: pod.GetNamespace(),
+			Labels:    utils.UnionMaps(pod.GetLabels(), map[string]string{credentialsLabel: "true"}),
+			// Owned by the workflow, like the pod itself, so that the secret is garbage collected with the workflow even
+			// if the attempt is never finalized.
+			OwnerReferences: pod.GetOwnerReferences(),
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: credentials,
+	}
+
+	logger.Infof(ctx, "Storing [%d] minted credentials in secret [%v/%v]", len(credentials), secret.Namespace, secret.Name)
+	err = e.kubeClient.GetClient().Create(ctx, secret)
+	if k8serrors.IsAlreadyExists(err) {
+		// Left behind by a previous launch of the attempt, whose credentials may have expired since.
+		err = e.kubeClient.GetClient().Update(ctx, secret)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: credentialsVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: secret.Name},
+		},
+	})
+
+	mount := v1.VolumeMount{Name: credentialsVolumeName, MountPath: cfg.MountPath, ReadOnly: true}
+	for i := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].VolumeMounts = append(pod.Spec.InitContainers[i].VolumeMounts, mount)
+	}
+
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, mount)
+	}
+
+	return nil
+}
+
+// Revokes the credentials minted for the node attempt and deletes its credentials secret.
+func (e *PluginManager) revokeCredentials(ctx context.Context, taskCtx pluginsCore.TaskExecutionMetadata,
+	cfg nodeTaskConfig.CredentialsConfig) error {
+
+	if !cfg.Enabled || e.credentialsMinter == nil {
+		return nil
+	}
+
+	if err := e.credentialsMinter.Revoke(ctx, taskCtx); err != nil {
+		logger.Warningf(ctx, "Failed to revoke the credentials of [%v]. Error: %v",
+			taskCtx.GetTaskExecutionID().GetGeneratedName(), err)
+		return err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getCredentialsSecretName(taskCtx.GetTaskExecutionID().GetGeneratedName()),
+			Namespace: taskCtx.GetNamespace(),
+		},
+	}
+
+	if err := e.kubeClient.GetClient().Delete(ctx, secret); err != nil && !IsK8sObjectNotExists(err) {
+		logger.Warningf(ctx, "Failed to delete credentials secret [%v/%v]. Error: %v", secret.Namespace, secret.Name, err)
+		return err
+	}
+
+	return nil
+}
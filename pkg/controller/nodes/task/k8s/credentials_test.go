@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	pluginsCoreMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core/mocks"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+type fakeCredentialsMinter struct {
+	minted  int
+	revoked []string
+	err     error
+}
+
+func (f *fakeCredentialsMinter) Mint(_ context.Context, taskCtx pluginsCore.TaskExecutionMetadata) (map[string][]byte, error) {
+	f.minted++
+	return map[string][]byte{"token": []byte(fmt.Sprintf("%v-%d", taskCtx.GetTaskExecutionID().GetGeneratedName(), f.minted))}, f.err
+}
+
+func (f *fakeCredentialsMinter) Revoke(_ context.Context, taskCtx pluginsCore.TaskExecutionMetadata) error {
+	f.revoked = append(f.revoked, taskCtx.GetTaskExecutionID().GetGeneratedName())
+	return f.err
+}
+
+func TestCredentials(t *testing.T) {
+	ctx := context.TODO()
+	cfg := nodeTaskConfig.CredentialsConfig{
+		Enabled:   true,
+		MountPath: "/var/run/flyte/credentials",
+	}
+
+	newPod := func() *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "a",
+				Namespace:       "ns",
+				OwnerReferences: []metav1.OwnerReference{{Name: "wf"}},
+			},
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "primary"}, {Name: "sidecar"}}},
+		}
+	}
+
+	tm := getMockTaskExecutionMetadataCustom("a", "ns", nil, nil, metav1.OwnerReference{})
+
+	t.Run("mint-and-revoke", func(t *testing.T) {
+		c := fake.NewClientBuilder().Build()
+		kubeClient := &pluginsCoreMock.KubeClient{}
+		kubeClient.OnGetClient().Return(c)
+		minter := &fakeCredentialsMinter{}
+		e := &PluginManager{kubeClient: kubeClient, credentialsMinter: minter}
+
+		pod := newPod()
+		assert.NoError(t, e.mintCredentials(ctx, tm, pod, cfg))
+		if assert.Len(t, pod.Spec.Volumes, 1) {
+			assert.Equal(t, "a-credentials", pod.Spec.Volumes[0].Secret.SecretName)
+		}
+
+		for _, container := range pod.Spec.Containers {
+			assert.Equal(t, []v1.VolumeMount{{Name: credentialsVolumeName, MountPath: cfg.MountPath, ReadOnly: true}},
+				container.VolumeMounts)
+		}
+
+		secret := &v1.Secret{}
+		assert.NoError(t, c.Get(ctx, k8stypes.NamespacedName{Namespace: "ns", Name: "a-credentials"}, secret))
+		assert.Equal(t, "a-1", string(secret.Data["token"]))
+		assert.Equal(t, "true", secret.Labels[credentialsLabel])
+		assert.Equal(t, []metav1.OwnerReference{{Name: "wf"}}, secret.OwnerReferences)
+
+		// Relaunching the same attempt mints new credentials.
+		assert.NoError(t, e.mintCredentials(ctx, tm, newPod(), cfg))
+		assert.NoError(t, c.Get(ctx, k8stypes.NamespacedName{Namespace: "ns", Name: "a-credentials"}, secret))
+		assert.Equal(t, "a-2", string(secret.Data["token"]))
+
+		assert.NoError(t, e.revokeCredentials(ctx, tm, cfg))
+		assert.Equal(t, []string{"a"}, minter.revoked)
+		assert.True(t, IsK8sObjectNotExists(c.Get(ctx, k8stypes.NamespacedName{Namespace: "ns", Name: "a-credentials"}, secret)))
+
+		// Revoking credentials whose secret no longer exists is not an error.
+		assert.NoError(t, e.revokeCredentials(ctx, tm, cfg))
+	})
+
+	t.Run("mint-failure", func(t *testing.T) {
+		e := &PluginManager{credentialsMinter: &fakeCredentialsMinter{err: fmt.Errorf("sts unavailable")}}
+		pod := newPod()
+		assert.Error(t, e.mintCredentials(ctx, tm, pod, cfg))
+		assert.Empty(t, pod.Spec.Volumes)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		minter := &fakeCredentialsMinter{}
+		e := &PluginManager{credentialsMinter: minter}
+		disabled := cfg
+		disabled.Enabled = false
+
+		pod := newPod()
+		assert.NoError(t, e.mintCredentials(ctx, tm, pod, disabled))
+		assert.NoError(t, e.revokeCredentials(ctx, tm, disabled))
+		assert.Empty(t, pod.Spec.Volumes)
+		assert.Equal(t, 0, minter.minted)
+		assert.Empty(t, minter.revoked)
+	})
+
+	t.Run("no-minter", func(t *testing.T) {
+		e := &PluginManager{}
+		pod := newPod()
+		assert.NoError(t, e.mintCredentials(ctx, tm, pod, cfg))
+		assert.NoError(t, e.revokeCredentials(ctx, tm, cfg))
+		assert.Empty(t, pod.Spec.Volumes)
+	})
+}
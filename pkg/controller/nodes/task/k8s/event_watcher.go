@@ -0,0 +1,171 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	v1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	ctrlCache "sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// WarningEvent is the latest K8s Warning event observed for an object, e.g. the FailedScheduling event of a pod.
+type WarningEvent struct {
+	UID       k8stypes.UID
+	Reason    string
+	Message   string
+	Timestamp time.Time
+}
+
+// String formats the event the way it is surfaced as the reason of a task phase.
+func (w WarningEvent) String() string {
+	return fmt.Sprintf("%s: %s", w.Reason, w.Message)
+}
+
+// EventWatcher correlates K8s Warning events back to the objects they were reported for. Only the latest event with one
+// of the configured reasons is kept per object and it is dropped once K8s garbage collects the event, so the memory
+// used is bounded by the events that currently exist in the cluster.
+// A single EventWatcher is shared by all K8s plugins, since all of them would otherwise watch the same events.
+type EventWatcher struct {
+	reasons sets.String
+	lock    sync.RWMutex
+	latest  map[k8stypes.NamespacedName]WarningEvent
+	// Guards started apart from the events, so that looking them up does not wait for the informer
+	startLock sync.Mutex
+	started   bool
+}
+
+func getEventTimestamp(e *v1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+func (w *EventWatcher) observe(obj interface{}) {
+	e, ok := obj.(*v1.Event)
+	if !ok || e.Type != v1.EventTypeWarning || !w.reasons.Has(e.Reason) {
+		return
+	}
+
+	key := k8stypes.NamespacedName{Namespace: e.InvolvedObject.Namespace, Name: e.InvolvedObject.Name}
+	observed := WarningEvent{
+		UID:       e.UID,
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Timestamp: getEventTimestamp(e),
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if existing, found := w.latest[key]; !found || existing.UID == observed.UID || !observed.Timestamp.Before(existing.Timestamp) {
+		w.latest[key] = observed
+	}
+}
+
+func (w *EventWatcher) forget(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	e, ok := obj.(*v1.Event)
+	if !ok {
+		return
+	}
+
+	key := k8stypes.NamespacedName{Namespace: e.InvolvedObject.Namespace, Name: e.InvolvedObject.Name}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if existing, found := w.latest[key]; found && existing.UID == e.UID {
+		delete(w.latest, key)
+	}
+}
+
+func (w *EventWatcher) OnAdd(obj interface{}) {
+	w.observe(obj)
+}
+
+func (w *EventWatcher) OnUpdate(_, newObj interface{}) {
+	w.observe(newObj)
+}
+
+func (w *EventWatcher) OnDelete(obj interface{}) {
+	w.forget(obj)
+}
+
+// GetLatestWarningEvent returns the latest Warning event observed for the object with the given name.
+func (w *EventWatcher) GetLatestWarningEvent(name k8stypes.NamespacedName) (WarningEvent, bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	e, found := w.latest[name]
+	return e, found
+}
+
+// Start registers the watcher with the events informer of the given cache. It is safe to call Start more than once, the
+// watcher is only registered by the first call that succeeds.
+func (w *EventWatcher) Start(ctx context.Context, c ctrlCache.Cache) error {
+	w.startLock.Lock()
+	defer w.startLock.Unlock()
+	if w.started {
+		return nil
+	}
+
+	informer, err := c.GetInformer(ctx, &v1.Event{})
+	if err != nil {
+		return err
+	}
+
+	logger.Infof(ctx, "Watching K8s Warning events with reasons %v", w.reasons.List())
+	informer.AddEventHandler(w)
+	w.started = true
+	return nil
+}
+
+// NewEventWatcher creates an EventWatcher that keeps track of Warning events with one of the given reasons.
+func NewEventWatcher(reasons []string) *EventWatcher {
+	return &EventWatcher{
+		reasons: sets.NewString(reasons...),
+		latest:  map[k8stypes.NamespacedName]WarningEvent{},
+	}
+}
+
+// Surfaces the latest Warning event of the object as the reason of the phase, if the object is still waiting to be
+// scheduled. The phase version is bumped whenever a new event is surfaced, so that it is also recorded as a Flyte event
+// and shows up in the UI. The events surfaced so far are kept in the given plugin state, which the caller persists.
+func (e *PluginManager) addWarningEventReason(ctx context.Context, ps *PluginState, nsName k8stypes.NamespacedName,
+	p pluginsCore.PhaseInfo) pluginsCore.PhaseInfo {
+
+	if e.eventWatcher == nil || (p.Phase() != pluginsCore.PhaseQueued && p.Phase() != pluginsCore.PhaseWaitingForResources) {
+		return p
+	}
+
+	warning, found := e.eventWatcher.GetLatestWarningEvent(nsName)
+	if !found {
+		return p
+	}
+
+	reason := warning.String()
+	if ps.LastWarningEvent != reason {
+		logger.Infof(ctx, "Observed new Warning event for [%v]: %v", nsName, reason)
+		ps.LastWarningEvent = reason
+		ps.WarningEventVersions++
+	}
+
+	version := p.Version() + ps.WarningEventVersions
+	if p.Phase() == pluginsCore.PhaseWaitingForResources {
+		return pluginsCore.PhaseInfoWaitingForResourcesInfo(warning.Timestamp, version, reason, p.Info())
+	}
+
+	return pluginsCore.PhaseInfoQueuedWithTaskInfo(version, reason, p.Info())
+}
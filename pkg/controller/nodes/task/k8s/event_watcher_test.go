@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+func newWarningEvent(uid, reason, message string, ts time.Time) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: uid, Namespace: "ns", UID: k8stypes.UID(uid)},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: "pod"},
+		Type:           v1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(ts),
+	}
+}
+
+func TestEventWatcher(t *testing.T) {
+	now := time.Now()
+	podName := k8stypes.NamespacedName{Namespace: "ns", Name: "pod"}
+
+	t.Run("ignored-events", func(t *testing.T) {
+		w := NewEventWatcher([]string{"FailedScheduling"})
+		w.OnAdd(newWarningEvent("1", "BackOff", "restarting", now))
+		normal := newWarningEvent("2", "FailedScheduling", "", now)
+		normal.Type = v1.EventTypeNormal
+		w.OnAdd(normal)
+		w.OnAdd(&v1.Pod{})
+
+		_, found := w.GetLatestWarningEvent(podName)
+		assert.False(t, found)
+	})
+
+	t.Run("latest-event", func(t *testing.T) {
+		w := NewEventWatcher([]string{"FailedScheduling", "Preempted"})
+		w.OnAdd(newWarningEvent("1", "FailedScheduling", "0/3 nodes are available", now))
+		w.OnAdd(newWarningEvent("2", "Preempted", "preempted by other-pod", now.Add(-time.Minute)))
+
+		e, found := w.GetLatestWarningEvent(podName)
+		assert.True(t, found)
+		assert.Equal(t, "FailedScheduling: 0/3 nodes are available", e.String())
+
+		updated := newWarningEvent("1", "FailedScheduling", "0/4 nodes are available", now.Add(time.Minute))
+		w.OnUpdate(nil, updated)
+		e, _ = w.GetLatestWarningEvent(podName)
+		assert.Equal(t, "0/4 nodes are available", e.Message)
+
+		w.OnDelete(newWarningEvent("2", "Preempted", "", now))
+		_, found = w.GetLatestWarningEvent(podName)
+		assert.True(t, found)
+
+		w.OnDelete(cache.DeletedFinalStateUnknown{Obj: updated})
+		_, found = w.GetLatestWarningEvent(podName)
+		assert.False(t, found)
+	})
+}
+
+func TestEventWatcher_Start(t *testing.T) {
+	ctx := context.TODO()
+	w := NewEventWatcher([]string{"FailedScheduling"})
+	informers := &informertest.FakeInformers{Error: fmt.Errorf("not synced")}
+	assert.Error(t, w.Start(ctx, informers))
+	assert.False(t, w.started)
+
+	// A failed start is retried by the next call.
+	informers.Error = nil
+	assert.NoError(t, w.Start(ctx, informers))
+	assert.True(t, w.started)
+	assert.NoError(t, w.Start(ctx, informers))
+}
+
+func TestPluginManager_AddWarningEventReason(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	podName := k8stypes.NamespacedName{Namespace: "ns", Name: "pod"}
+	w := NewEventWatcher([]string{"FailedScheduling"})
+	pluginManager := &PluginManager{eventWatcher: w}
+	state := &PluginState{Phase: PluginPhaseStarted}
+	queued := pluginsCore.PhaseInfoQueued(now, pluginsCore.DefaultPhaseVersion, "Scheduling")
+
+	t.Run("no-event", func(t *testing.T) {
+		p := pluginManager.addWarningEventReason(ctx, state, podName, queued)
+		assert.Equal(t, queued, p)
+	})
+
+	t.Run("new-event", func(t *testing.T) {
+		w.OnAdd(newWarningEvent("1", "FailedScheduling", "0/3 nodes are available", now))
+		p := pluginManager.addWarningEventReason(ctx, state, podName, queued)
+		assert.Equal(t, pluginsCore.PhaseQueued, p.Phase())
+		assert.Equal(t, uint32(1), p.Version())
+		assert.Equal(t, "FailedScheduling: 0/3 nodes are available", p.Reason())
+		assert.Equal(t, PluginPhaseStarted, state.Phase)
+
+		// Observing the same event again should not bump the version, so that no duplicate event is recorded.
+		p = pluginManager.addWarningEventReason(ctx, state, podName, queued)
+		assert.Equal(t, uint32(1), p.Version())
+	})
+
+	t.Run("updated-event", func(t *testing.T) {
+		w.OnUpdate(nil, newWarningEvent("1", "FailedScheduling", "0/4 nodes are available", now))
+		p := pluginManager.addWarningEventReason(ctx, state, podName,
+			pluginsCore.PhaseInfoWaitingForResourcesInfo(now, pluginsCore.DefaultPhaseVersion, "quota", nil))
+		assert.Equal(t, pluginsCore.PhaseWaitingForResources, p.Phase())
+		assert.Equal(t, uint32(2), p.Version())
+		assert.Equal(t, "FailedScheduling: 0/4 nodes are available", p.Reason())
+	})
+
+	t.Run("running", func(t *testing.T) {
+		running := pluginsCore.PhaseInfoRunning(pluginsCore.DefaultPhaseVersion, nil)
+		p := pluginManager.addWarningEventReason(ctx, state, podName, running)
+		assert.Equal(t, running, p)
+	})
+}
@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compilerK8s "github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+// Set by the node executor on nodes generated by a task, it cannot be imported from there without a cycle.
+const parentNodeIDLabel = "parent-node-id"
+
+// fanOutSelector returns the selector of the pods of the fan-out the pod belongs to, i.e. the pods of the sub-nodes
+// generated by the same node in the same execution. Returns false if the pod does not belong to a fan-out.
+func fanOutSelector(pod *v1.Pod) (*metav1.LabelSelector, bool) {
+	executionID, found := pod.GetLabels()[compilerK8s.ExecutionIDLabel]
+	if !found {
+		return nil, false
+	}
+
+	parentNodeID, found := pod.GetLabels()[parentNodeIDLabel]
+	if !found {
+		return nil, false
+	}
+
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			compilerK8s.ExecutionIDLabel: executionID,
+			parentNodeIDLabel:            parentNodeID,
+		},
+	}, true
+}
+
+// addFanOutSpread adds topology spread constraints, and optionally a preferred pod anti-affinity, to pods of fan-outs, so
+// that the pods of a fan-out are spread over the configured topology domains.
+func addFanOutSpread(o client.Object, cfg nodeTaskConfig.FanOutSpreadConfig) {
+	pod, ok := o.(*v1.Pod)
+	if !cfg.Enabled || !ok {
+		return
+	}
+
+	selector, ok := fanOutSelector(pod)
+	if !ok {
+		return
+	}
+
+	if len(pod.Spec.TopologySpreadConstraints) == 0 {
+		for _, key := range cfg.TopologyKeys {
+			pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, v1.TopologySpreadConstraint{
+				MaxSkew:           cfg.MaxSkew,
+				TopologyKey:       key,
+				WhenUnsatisfiable: v1.UnsatisfiableConstraintAction(cfg.WhenUnsatisfiable),
+				LabelSelector:     selector.DeepCopy(),
+			})
+		}
+	}
+
+	if len(cfg.AntiAffinityTopologyKey) == 0 || (pod.Spec.Affinity != nil && pod.Spec.Affinity.PodAntiAffinity != nil) {
+		return
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &v1.Affinity{}
+	}
+
+	pod.Spec.Affinity.PodAntiAffinity = &v1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+			{
+				Weight: 100,
+				PodAffinityTerm: v1.PodAffinityTerm{
+					LabelSelector: selector.DeepCopy(),
+					TopologyKey:   cfg.AntiAffinityTopologyKey,
+				},
+			},
+		},
+	}
+}
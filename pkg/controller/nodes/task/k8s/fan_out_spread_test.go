@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func TestAddFanOutSpread(t *testing.T) {
+	cfg := nodeTaskConfig.FanOutSpreadConfig{
+		Enabled:                 true,
+		TopologyKeys:            []string{"topology.kubernetes.io/zone"},
+		MaxSkew:                 1,
+		WhenUnsatisfiable:       "ScheduleAnyway",
+		AntiAffinityTopologyKey: "kubernetes.io/hostname",
+	}
+
+	newPod := func(labels map[string]string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "primary"}}},
+		}
+	}
+
+	fanOutLabels := map[string]string{"execution-id": "exec", "parent-node-id": "n1", "node-id": "n1-0-dn0"}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"execution-id": "exec", "parent-node-id": "n1"}}
+
+	t.Run("fan-out", func(t *testing.T) {
+		pod := newPod(fanOutLabels)
+		addFanOutSpread(pod, cfg)
+
+		assert.Equal(t, []v1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: v1.ScheduleAnyway,
+				LabelSelector:     selector,
+			},
+		}, pod.Spec.TopologySpreadConstraints)
+
+		if assert.NotNil(t, pod.Spec.Affinity) && assert.NotNil(t, pod.Spec.Affinity.PodAntiAffinity) {
+			terms := pod.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+			if assert.Len(t, terms, 1) {
+				assert.Equal(t, "kubernetes.io/hostname", terms[0].PodAffinityTerm.TopologyKey)
+				assert.Equal(t, selector, terms[0].PodAffinityTerm.LabelSelector)
+			}
+		}
+	})
+
+	t.Run("not-a-fan-out", func(t *testing.T) {
+		pod := newPod(map[string]string{"execution-id": "exec", "node-id": "n1"})
+		addFanOutSpread(pod, cfg)
+		assert.Empty(t, pod.Spec.TopologySpreadConstraints)
+		assert.Nil(t, pod.Spec.Affinity)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		pod := newPod(fanOutLabels)
+		disabled := cfg
+		disabled.Enabled = false
+		addFanOutSpread(pod, disabled)
+		assert.Empty(t, pod.Spec.TopologySpreadConstraints)
+		assert.Nil(t, pod.Spec.Affinity)
+	})
+
+	t.Run("existing-constraints", func(t *testing.T) {
+		pod := newPod(fanOutLabels)
+		existing := []v1.TopologySpreadConstraint{{MaxSkew: 3, TopologyKey: "rack", WhenUnsatisfiable: v1.DoNotSchedule}}
+		pod.Spec.TopologySpreadConstraints = existing
+		pod.Spec.Affinity = &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{}, NodeAffinity: &v1.NodeAffinity{}}
+		addFanOutSpread(pod, cfg)
+
+		assert.Equal(t, existing, pod.Spec.TopologySpreadConstraints)
+		assert.Equal(t, &v1.PodAntiAffinity{}, pod.Spec.Affinity.PodAntiAffinity)
+	})
+
+	t.Run("keeps-node-affinity", func(t *testing.T) {
+		pod := newPod(fanOutLabels)
+		pod.Spec.Affinity = &v1.Affinity{NodeAffinity: &v1.NodeAffinity{}}
+		addFanOutSpread(pod, cfg)
+
+		assert.NotNil(t, pod.Spec.Affinity.NodeAffinity)
+		assert.NotNil(t, pod.Spec.Affinity.PodAntiAffinity)
+	})
+}
@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compilerK8s "github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// isInFlight returns true if the object is a task resource that still counts against the in-flight quota. Only objects
+// labeled with an execution id are created by propeller, the informers of shared kinds, e.g. pods, see all objects.
+func isInFlight(obj interface{}) bool {
+	metadata, err := meta.Accessor(obj)
+	if err != nil || metadata.GetDeletionTimestamp() != nil {
+		return false
+	}
+
+	if _, ok := metadata.GetLabels()[compilerK8s.ExecutionIDLabel]; !ok {
+		return false
+	}
+
+	if pod, ok := obj.(*v1.Pod); ok {
+		return pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed
+	}
+
+	return true
+}
+
+func countInFlight(objects []interface{}) int {
+	count := 0
+	for _, obj := range objects {
+		if isInFlight(obj) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// checkInFlightQuota returns a non-empty reason if launching the object would exceed the in-flight quota of its kind.
+func (e *PluginManager) checkInFlightQuota(ctx context.Context, o client.Object, cfg nodeTaskConfig.InFlightQuotaConfig) (string, error) {
+	if !cfg.Enabled || e.resourceLevelMonitor == nil {
+		return "", nil
+	}
+
+	kind := strings.ToLower(e.resourceLevelMonitor.gvk.Kind)
+	indexer := e.resourceLevelMonitor.sharedInformer.GetIndexer()
+	if maxInNamespace, found := cfg.MaxPerNamespace[kind]; found {
+		objects, err := indexer.ByIndex(cache.NamespaceIndex, o.GetNamespace())
+		if err != nil {
+			return "", err
+		}
+
+		if count := countInFlight(objects); count >= maxInNamespace {
+			e.metrics.InFlightQuotaExceeded.Inc(contextutils.WithNamespace(ctx, o.GetNamespace()))
+			return fmt.Sprintf("maximum number of in-flight %v resources in namespace [%v] reached (%v/%v)", kind,
+				o.GetNamespace(), count, maxInNamespace), nil
+		}
+	}
+
+	if maxTotal, found := cfg.MaxPerKind[kind]; found {
+		if count := countInFlight(indexer.List()); count >= maxTotal {
+			e.metrics.InFlightQuotaExceeded.Inc(contextutils.WithNamespace(ctx, o.GetNamespace()))
+			return fmt.Sprintf("maximum number of in-flight %v resources reached (%v/%v)", kind, count, maxTotal), nil
+		}
+	}
+
+	logger.Debugf(ctx, "In-flight quota of kind [%v] allows launching [%v/%v]", kind, o.GetNamespace(), o.GetName())
+	return "", nil
+}
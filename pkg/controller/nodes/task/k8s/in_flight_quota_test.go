@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func TestPluginManager_CheckInFlightQuota(t *testing.T) {
+	ctx := context.TODO()
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &v1.Pod{}, 0,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+	newPod := func(namespace, name string, labeled bool, phase v1.PodPhase) *v1.Pod {
+		p := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+
+		if labeled {
+			p.Labels = map[string]string{"execution-id": "wf"}
+		}
+
+		return p
+	}
+
+	for _, p := range []*v1.Pod{
+		newPod("a", "running", true, v1.PodRunning),
+		newPod("a", "pending", true, v1.PodPending),
+		newPod("a", "succeeded", true, v1.PodSucceeded),
+		newPod("a", "not-flyte", false, v1.PodRunning),
+		newPod("b", "running", true, v1.PodRunning),
+	} {
+		assert.NoError(t, informer.GetIndexer().Add(p))
+	}
+
+	pluginManager := &PluginManager{
+		metrics: newPluginMetrics(promutils.NewTestScope()),
+		resourceLevelMonitor: &ResourceLevelMonitor{
+			sharedInformer: informer,
+			gvk:            schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		},
+	}
+
+	cfg := nodeTaskConfig.InFlightQuotaConfig{
+		Enabled:         true,
+		MaxPerKind:      map[string]int{"pod": 4},
+		MaxPerNamespace: map[string]int{"pod": 2},
+	}
+
+	t.Run("namespace quota reached", func(t *testing.T) {
+		reason, err := pluginManager.checkInFlightQuota(ctx, newPod("a", "new", true, ""), cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "maximum number of in-flight pod resources in namespace [a] reached (2/2)", reason)
+	})
+
+	t.Run("within quota", func(t *testing.T) {
+		reason, err := pluginManager.checkInFlightQuota(ctx, newPod("b", "new", true, ""), cfg)
+		assert.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("kind quota reached", func(t *testing.T) {
+		kindCfg := cfg
+		kindCfg.MaxPerKind = map[string]int{"pod": 3}
+		reason, err := pluginManager.checkInFlightQuota(ctx, newPod("b", "new", true, ""), kindCfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "maximum number of in-flight pod resources reached (3/3)", reason)
+	})
+
+	t.Run("other kind", func(t *testing.T) {
+		otherCfg := cfg
+		otherCfg.MaxPerNamespace = map[string]int{"sparkapplication": 0}
+		reason, err := pluginManager.checkInFlightQuota(ctx, newPod("a", "new", true, ""), otherCfg)
+		assert.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabledCfg := cfg
+		disabledCfg.Enabled = false
+		reason, err := pluginManager.checkInFlightQuota(ctx, newPod("a", "new", true, ""), disabledCfg)
+		assert.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+}
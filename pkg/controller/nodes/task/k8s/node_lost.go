@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	v1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+func getNodeReadyCondition(node *v1.Node) *v1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == v1.NodeReady {
+			return &node.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// Checks whether the node that the pod is scheduled on was deleted or is NotReady. Nodes are read through the cached
+// kube client, hence this relies on the node informer rather than querying the API server. Once the node has been gone
+// for longer than the configured grace period, the attempt is failed as a retryable system error instead of waiting
+// for Kubernetes to eventually time out the pod. The time the deletion of the node was first observed is kept in the
+// given plugin state, which the caller persists.
+func (e *PluginManager) checkNodeLost(ctx context.Context, ps *PluginState, o client.Object,
+	cfg nodeTaskConfig.NodeLostConfig, now time.Time) (pluginsCore.PhaseInfo, bool) {
+
+	pod, ok := o.(*v1.Pod)
+	if !cfg.Enabled || !ok || len(pod.Spec.NodeName) == 0 {
+		return pluginsCore.PhaseInfoUndefined, false
+	}
+
+	node := &v1.Node{}
+	err := e.kubeClient.GetClient().Get(ctx, k8stypes.NamespacedName{Name: pod.Spec.NodeName}, node)
+	if err != nil && !IsK8sObjectNotExists(err) {
+		logger.Warnf(ctx, "Failed to retrieve node [%v] of pod [%v]. Error: %v", pod.Spec.NodeName, pod.Name, err)
+		return pluginsCore.PhaseInfoUndefined, false
+	}
+
+	var lostSince time.Time
+	var gracePeriod time.Duration
+	var state string
+	if err != nil {
+		// The time the node was deleted is not known, so the grace period starts when the deletion was first observed.
+		if ps.NodeDeletedObservedAt.IsZero() {
+			ps.NodeDeletedObservedAt = now
+		}
+
+		lostSince, gracePeriod, state = ps.NodeDeletedObservedAt, cfg.DeletedGracePeriod.Duration, "was deleted"
+	} else {
+		ps.NodeDeletedObservedAt = time.Time{}
+		cond := getNodeReadyCondition(node)
+		if cond == nil || cond.Status == v1.ConditionTrue {
+			return pluginsCore.PhaseInfoUndefined, false
+		}
+
+		lostSince, gracePeriod, state = cond.LastTransitionTime.Time, cfg.NotReadyGracePeriod.Duration, "is NotReady"
+	}
+
+	if now.Sub(lostSince) < gracePeriod {
+		logger.Infof(ctx, "Node [%v] of pod [%v] %s since [%v], waiting for grace period [%v]", pod.Spec.NodeName,
+			pod.Name, state, lostSince, gracePeriod)
+		return pluginsCore.PhaseInfoUndefined, false
+	}
+
+	e.metrics.NodeLost.Inc(ctx)
+	message := fmt.Sprintf("node [%s] of pod [%s] %s since [%v]", pod.Spec.NodeName, pod.Name, state, lostSince.Format(time.RFC3339))
+	return PodFailureNodeShutdown.ToPhaseInfo(message, nil), true
+}
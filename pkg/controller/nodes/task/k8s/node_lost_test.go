@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	pluginsCoreMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core/mocks"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s"
+	pluginsk8sMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s/mocks"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func newPluginStateTestContext(state *PluginState) pluginsCore.TaskExecutionContext {
+	tCtx := &pluginsCoreMock.TaskExecutionContext{}
+	stateReader := &pluginsCoreMock.PluginStateReader{}
+	stateReader.OnGetMatch(mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*PluginState) = *state
+	}).Return(uint8(pluginStateVersion), nil)
+	tCtx.OnPluginStateReader().Return(stateReader)
+
+	stateWriter := &pluginsCoreMock.PluginStateWriter{}
+	stateWriter.OnPutMatch(mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*state = *args.Get(1).(*PluginState)
+	}).Return(nil)
+	tCtx.OnPluginStateWriter().Return(stateWriter)
+	return tCtx
+}
+
+func TestPluginManager_CheckNodeLost(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	cfg := nodeTaskConfig.NodeLostConfig{
+		Enabled:             true,
+		NotReadyGracePeriod: config.Duration{Duration: time.Minute},
+		DeletedGracePeriod:  config.Duration{Duration: time.Minute},
+	}
+
+	newNode := func(name string, status v1.ConditionStatus, since time.Time) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{
+					{Type: v1.NodeReady, Status: status, LastTransitionTime: metav1.NewTime(since)},
+				},
+			},
+		}
+	}
+
+	kubeClient := &pluginsCoreMock.KubeClient{}
+	kubeClient.OnGetClient().Return(fake.NewClientBuilder().WithObjects(
+		newNode("ready", v1.ConditionTrue, now.Add(-time.Hour)),
+		newNode("not-ready-recently", v1.ConditionFalse, now.Add(-time.Second)),
+		newNode("not-ready", v1.ConditionUnknown, now.Add(-time.Hour)),
+	).Build())
+
+	pluginManager := &PluginManager{
+		kubeClient: kubeClient,
+		metrics:    newPluginMetrics(promutils.NewTestScope()),
+	}
+
+	newPod := func(nodeName string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod"}, Spec: v1.PodSpec{NodeName: nodeName}}
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		_, lost := pluginManager.checkNodeLost(ctx, &PluginState{}, newPod("ready"), cfg, now)
+		assert.False(t, lost)
+	})
+
+	t.Run("not-scheduled", func(t *testing.T) {
+		_, lost := pluginManager.checkNodeLost(ctx, &PluginState{}, newPod(""), cfg, now)
+		assert.False(t, lost)
+	})
+
+	t.Run("not-ready-within-grace-period", func(t *testing.T) {
+		_, lost := pluginManager.checkNodeLost(ctx, &PluginState{}, newPod("not-ready-recently"), cfg, now)
+		assert.False(t, lost)
+	})
+
+	t.Run("not-ready", func(t *testing.T) {
+		p, lost := pluginManager.checkNodeLost(ctx, &PluginState{}, newPod("not-ready"), cfg, now)
+		assert.True(t, lost)
+		assert.Equal(t, pluginsCore.PhaseRetryableFailure, p.Phase())
+		assert.Equal(t, PodFailureNodeShutdown.Code, p.Err().GetCode())
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		state := &PluginState{Phase: PluginPhaseStarted}
+		_, lost := pluginManager.checkNodeLost(ctx, state, newPod("deleted"), cfg, now)
+		assert.False(t, lost)
+		assert.Equal(t, now, state.NodeDeletedObservedAt)
+		assert.Equal(t, PluginPhaseStarted, state.Phase)
+
+		p, lost := pluginManager.checkNodeLost(ctx, state, newPod("deleted"), cfg, now.Add(2*time.Minute))
+		assert.True(t, lost)
+		assert.Equal(t, pluginsCore.PhaseRetryableFailure, p.Phase())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		_, lost := pluginManager.checkNodeLost(ctx, &PluginState{}, newPod("not-ready"), disabled, now)
+		assert.False(t, lost)
+	})
+}
+
+func TestPluginManager_CheckResourcePhase_PluginState(t *testing.T) {
+	ctx := context.TODO()
+	prevCfg := nodeTaskConfig.GetConfig()
+	cfg := *prevCfg
+	cfg.NodeLostConfig = nodeTaskConfig.NodeLostConfig{
+		Enabled:            true,
+		DeletedGracePeriod: config.Duration{Duration: time.Hour},
+	}
+	assert.NoError(t, nodeTaskConfig.SetConfig(&cfg))
+	defer func() { assert.NoError(t, nodeTaskConfig.SetConfig(prevCfg)) }()
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec:       v1.PodSpec{NodeName: "deleted"},
+	}
+
+	kubeClient := &pluginsCoreMock.KubeClient{}
+	kubeClient.OnGetClient().Return(fake.NewClientBuilder().WithObjects(pod).Build())
+
+	plugin := &pluginsk8sMock.Plugin{}
+	plugin.OnGetProperties().Return(k8s.PluginProperties{})
+	plugin.OnBuildIdentityResourceMatch(mock.Anything, mock.Anything).Return(&v1.Pod{}, nil)
+	plugin.OnGetTaskPhaseMatch(mock.Anything, mock.Anything, mock.Anything).Return(
+		pluginsCore.PhaseInfoQueued(time.Now(), pluginsCore.DefaultPhaseVersion, "Scheduling"), nil)
+
+	w := NewEventWatcher([]string{"FailedScheduling"})
+	pluginManager := &PluginManager{
+		plugin:       plugin,
+		kubeClient:   kubeClient,
+		metrics:      newPluginMetrics(promutils.NewTestScope()),
+		eventWatcher: w,
+	}
+
+	state := &PluginState{Phase: PluginPhaseStarted}
+	tCtx := newPluginStateTestContext(state).(*pluginsCoreMock.TaskExecutionContext)
+	tCtx.OnTaskExecutionMetadata().Return(getMockTaskExecutionMetadata())
+
+	w.OnAdd(&v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "1", Namespace: "ns", UID: "1"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: "test"},
+		Type:           v1.EventTypeWarning,
+		Reason:         "FailedScheduling",
+		Message:        "0/3 nodes are available",
+		LastTimestamp:  metav1.Now(),
+	})
+
+	_, err := pluginManager.CheckResourcePhase(ctx, tCtx)
+	assert.NoError(t, err)
+	observedAt := state.NodeDeletedObservedAt
+	assert.False(t, observedAt.IsZero())
+	assert.Equal(t, "FailedScheduling: 0/3 nodes are available", state.LastWarningEvent)
+
+	// A new Warning event must not reset the grace period of the deleted node.
+	w.OnAdd(&v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "2", Namespace: "ns", UID: "2"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: "test"},
+		Type:           v1.EventTypeWarning,
+		Reason:         "FailedScheduling",
+		Message:        "0/4 nodes are available",
+		LastTimestamp:  metav1.NewTime(time.Now().Add(time.Minute)),
+	})
+
+	_, err = pluginManager.CheckResourcePhase(ctx, tCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, observedAt, state.NodeDeletedObservedAt)
+	assert.Equal(t, "FailedScheduling: 0/4 nodes are available", state.LastWarningEvent)
+	assert.Equal(t, uint32(2), state.WarningEventVersions)
+}
@@ -7,7 +7,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -15,6 +14,8 @@ import (
 
 	"github.com/flyteorg/flytestdlib/contextutils"
 	"github.com/flyteorg/flytestdlib/promutils"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 const resourceLevelMonitorCycleDuration = 10 * time.Second
@@ -7,7 +7,8 @@ import (
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/k8s"
-	"github.com/flyteorg/flytestdlib/logger"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 var _ k8s.PluginContext = &pluginContext{}
@@ -35,7 +35,6 @@ import (
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/utils"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -43,6 +42,7 @@ import (
 	"github.com/flyteorg/flyteplugins/go/tasks/errors"
 
 	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
@@ -60,6 +60,13 @@ const (
 
 type PluginState struct {
 	Phase PluginPhase
+	// The first time the node of the pod was observed to be deleted, zero if the node exists
+	NodeDeletedObservedAt time.Time
+	// The last Warning event that was surfaced as the phase reason and the number of distinct events surfaced so far
+	LastWarningEvent     string
+	WarningEventVersions uint32
+	// The name of the resource that was adopted for the attempt instead of being launched, empty if it was launched
+	ResourceName string
 }
 
 type PluginMetrics struct {
@@ -68,6 +75,14 @@ type PluginMetrics struct {
 	GetCacheHit     labeled.StopWatch
 	GetAPILatency   labeled.StopWatch
 	ResourceDeleted labeled.Counter
+	NodeLost        labeled.Counter
+	CoPilotTimeouts labeled.Counter
+	// Counts launches that were kept waiting because the in-flight quota of their kind was reached
+	InFlightQuotaExceeded labeled.Counter
+	// Counts resources that already existed for their attempt and were adopted instead of launched again
+	ResourcesAdopted labeled.Counter
+	// Counts aborted resources that were force deleted because they kept terminating past the grace period
+	ResourcesForceDeleted labeled.Counter
 }
 
 func newPluginMetrics(s promutils.Scope) PluginMetrics {
@@ -81,6 +96,16 @@ func newPluginMetrics(s promutils.Scope) PluginMetrics {
 			time.Millisecond, s),
 		ResourceDeleted: labeled.NewCounter("pods_deleted", "Counts how many times CheckTaskStatus is"+
 			" called with a deleted resource.", s),
+		NodeLost: labeled.NewCounter("pods_node_lost", "Counts how many attempts were failed because the node of"+
+			" their pod was deleted or NotReady.", s),
+		CoPilotTimeouts: labeled.NewCounter("copilot_upload_timeouts", "Counts how many attempts were failed because"+
+			" their co-pilot sidecar did not finish uploading outputs in time.", s),
+		InFlightQuotaExceeded: labeled.NewCounter("in_flight_quota_exceeded", "Counts how many launches were kept"+
+			" waiting because the in-flight quota of their resource kind was reached.", s),
+		ResourcesAdopted: labeled.NewCounter("resources_adopted", "Counts how many resources already existed for"+
+			" their attempt and were adopted instead of launched again.", s),
+		ResourcesForceDeleted: labeled.NewCounter("resources_force_deleted", "Counts how many aborted resources were"+
+			" force deleted because they kept terminating past the grace period.", s),
 	}
 }
 
@@ -99,6 +124,8 @@ type PluginManager struct {
 	// Per namespace-resource
 	backOffController    *backoff.Controller
 	resourceLevelMonitor *ResourceLevelMonitor
+	eventWatcher         *EventWatcher
+	credentialsMinter    CredentialsMinter
 }
 
 func (e *PluginManager) AddObjectMetadata(taskCtx pluginsCore.TaskExecutionMetadata, o client.Object, cfg *config.K8sPluginConfig) {
@@ -106,6 +133,7 @@ func (e *PluginManager) AddObjectMetadata(taskCtx pluginsCore.TaskExecutionMetad
 	o.SetAnnotations(utils.UnionMaps(cfg.DefaultAnnotations, o.GetAnnotations(), utils.CopyMap(taskCtx.GetAnnotations())))
 	o.SetLabels(utils.UnionMaps(o.GetLabels(), utils.CopyMap(taskCtx.GetLabels()), cfg.DefaultLabels))
 	o.SetName(taskCtx.GetTaskExecutionID().GetGeneratedName())
+	addAttemptLabel(taskCtx, o)
 
 	if !e.plugin.GetProperties().DisableInjectOwnerReferences {
 		o.SetOwnerReferences([]metav1.OwnerReference{taskCtx.GetOwnerReference()})
@@ -195,6 +223,33 @@ func (e *PluginManager) LaunchResource(ctx context.Context, tCtx pluginsCore.Tas
 	}
 
 	e.AddObjectMetadata(k8sTaskCtxMetadata, o, config.GetK8sPluginConfig())
+	scratchCfg := nodeTaskConfig.GetConfig().ScratchVolumeConfig
+	if err := addScratchVolume(o, scratchCfg); err != nil {
+		return pluginsCore.DoTransition(pluginsCore.PhaseInfoFailure("BadScratchVolume", err.Error(), nil)), nil
+	}
+
+	addPreviousAttemptEnv(o)
+	addFanOutSpread(o, nodeTaskConfig.GetConfig().FanOutSpreadConfig)
+
+	if reason, err := e.checkInFlightQuota(ctx, o, nodeTaskConfig.GetConfig().InFlightQuotaConfig); err != nil {
+		return pluginsCore.UnknownTransition, errors.Wrapf(errors.RuntimeFailure, err, "failed to check in-flight quota")
+	} else if len(reason) > 0 {
+		logger.Infof(ctx, "Not launching [%v/%v], %v", o.GetNamespace(), o.GetName(), reason)
+		return pluginsCore.DoTransition(pluginsCore.PhaseInfoWaitingForResources(time.Now(), pluginsCore.DefaultPhaseVersion,
+			reason)), nil
+	}
+
+	if created, err := e.ensureScratchClaim(ctx, o, scratchCfg); err != nil {
+		return pluginsCore.UnknownTransition, errors.Wrapf(errors.RuntimeFailure, err, "failed to create scratch claim")
+	} else if !created {
+		return pluginsCore.DoTransition(pluginsCore.PhaseInfoWaitingForResources(time.Now(), pluginsCore.DefaultPhaseVersion,
+			"waiting for scratch claim, maximum number of scratch claims in namespace reached")), nil
+	}
+
+	if err := e.mintCredentials(ctx, k8sTaskCtxMetadata, o, nodeTaskConfig.GetConfig().CredentialsConfig); err != nil {
+		return pluginsCore.UnknownTransition, errors.Wrapf(errors.RuntimeFailure, err, "failed to mint credentials")
+	}
+
 	logger.Infof(ctx, "Creating Object: Type:[%v], Object:[%v/%v]", o.GetObjectKind().GroupVersionKind(), o.GetNamespace(), o.GetName())
 
 	key := backoff.ComposeResourceKey(o)
@@ -247,6 +302,7 @@ func (e *PluginManager) CheckResourcePhase(ctx context.Context, tCtx pluginsCore
 	}
 
 	e.AddObjectMetadata(tCtx.TaskExecutionMetadata(), o, config.GetK8sPluginConfig())
+	setAdoptedName(tCtx, o)
 	nsName := k8stypes.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
 	// Attempt to get resource from informer cache, if not found, retrieve it from API server.
 	if err := e.kubeClient.GetClient().Get(ctx, nsName, o); err != nil {
@@ -255,6 +311,12 @@ func (e *PluginManager) CheckResourcePhase(ctx context.Context, tCtx pluginsCore
 			// Pod does not exist error. This should be retried using the retry policy
 			logger.Warningf(ctx, "Failed to find the Resource with name: %v. Error: %v", nsName, err)
 			failureReason := fmt.Sprintf("resource not found, name [%s]. reason: %s", nsName.String(), err.Error())
+			if e.eventWatcher != nil {
+				if warning, found := e.eventWatcher.GetLatestWarningEvent(nsName); found {
+					failureReason = fmt.Sprintf("%s. last warning: %s", failureReason, warning.String())
+				}
+			}
+
 			return pluginsCore.DoTransition(pluginsCore.PhaseInfoSystemRetryableFailure("ResourceDeletedExternally", failureReason, nil)), nil
 		}
 
@@ -289,13 +351,46 @@ func (e *PluginManager) CheckResourcePhase(ctx context.Context, tCtx pluginsCore
 		return pluginsCore.DoTransition(p), nil
 	}
 
+	if p.Phase().IsFailure() {
+		return pluginsCore.DoTransition(classifyPodFailurePhase(o, p, "")), nil
+	}
+
+	if !p.Phase().IsTerminal() {
+		// The plugin state is read once and written once per round, so that the checks below do not overwrite each
+		// other's changes.
+		ps := PluginState{}
+		if _, err := tCtx.PluginStateReader().Get(&ps); err != nil {
+			return pluginsCore.UnknownTransition, err
+		}
+
+		prevState := ps
+		lostPhase, lost := e.checkNodeLost(ctx, &ps, o, nodeTaskConfig.GetConfig().NodeLostConfig, time.Now())
+		if lost {
+			return pluginsCore.DoTransition(lostPhase), nil
+		}
+
+		if timeoutPhase, timedOut := checkCoPilotUploadTimeout(o, config.GetK8sPluginConfig().CoPilot.NamePrefix,
+			nodeTaskConfig.GetConfig().CoPilotTimeoutConfig, time.Now()); timedOut {
+			e.metrics.CoPilotTimeouts.Inc(ctx)
+			return pluginsCore.DoTransition(timeoutPhase), nil
+		}
+
+		p = e.addWarningEventReason(ctx, &ps, nsName, p)
+		if ps != prevState {
+			if err := tCtx.PluginStateWriter().Put(pluginStateVersion, &ps); err != nil {
+				return pluginsCore.UnknownTransition, err
+			}
+		}
+	}
+
 	if !p.Phase().IsTerminal() && o.GetDeletionTimestamp() != nil {
 		// If the object has been deleted, that is, it has a deletion timestamp, but is not in a terminal state, we should
 		// mark the task as a retryable failure.  We've seen this happen when a kubelet disappears - all pods running on
 		// the node are marked with a deletionTimestamp, but our finalizers prevent the pod from being deleted.
 		// This can also happen when a user deletes a Pod directly.
 		failureReason := fmt.Sprintf("object [%s] terminated in the background, manually", nsName.String())
-		return pluginsCore.DoTransition(pluginsCore.PhaseInfoSystemRetryableFailure("UnexpectedObjectDeletion", failureReason, nil)), nil
+		return pluginsCore.DoTransition(classifyPodFailurePhase(o,
+			pluginsCore.PhaseInfoSystemRetryableFailure("UnexpectedObjectDeletion", failureReason, nil), failureReason)), nil
 	}
 
 	return pluginsCore.DoTransition(p), nil
@@ -310,6 +405,20 @@ func (e PluginManager) Handle(ctx context.Context, tCtx pluginsCore.TaskExecutio
 		return pluginsCore.UnknownTransition, errors.Wrapf(errors.CorruptedPluginState, err, "Failed to read unmarshal custom state")
 	}
 	if ps.Phase == PluginPhaseNotStarted {
+		name, found, err := e.findExistingResource(ctx, tCtx.TaskExecutionMetadata())
+		if err != nil {
+			return pluginsCore.UnknownTransition, errors.Wrapf(errors.RuntimeFailure, err, "failed to look up existing resources")
+		}
+
+		if found {
+			if err := tCtx.PluginStateWriter().Put(pluginStateVersion, &PluginState{Phase: PluginPhaseStarted, ResourceName: name}); err != nil {
+				return pluginsCore.UnknownTransition, err
+			}
+
+			return pluginsCore.DoTransition(pluginsCore.PhaseInfoQueued(time.Now(), pluginsCore.DefaultPhaseVersion,
+				fmt.Sprintf("adopted existing resource [%v]", name))), nil
+		}
+
 		t, err := e.LaunchResource(ctx, tCtx)
 		if err == nil && t.Info().Phase() == pluginsCore.PhaseQueued {
 			if err := tCtx.PluginStateWriter().Put(pluginStateVersion, &PluginState{Phase: PluginPhaseStarted}); err != nil {
@@ -334,6 +443,15 @@ func (e PluginManager) Abort(ctx context.Context, tCtx pluginsCore.TaskExecution
 	}
 
 	e.AddObjectMetadata(tCtx.TaskExecutionMetadata(), o, config.GetK8sPluginConfig())
+	setAdoptedName(tCtx, o)
+
+	if gracePeriod := nodeTaskConfig.GetConfig().AbortConfig.ForceDeleteGracePeriod.Duration; gracePeriod > 0 {
+		if err := e.abortInStages(ctx, tCtx, o, gracePeriod, time.Now()); err != nil {
+			return err
+		}
+
+		return e.deleteScratchClaim(ctx, tCtx.TaskExecutionMetadata(), nodeTaskConfig.GetConfig().ScratchVolumeConfig)
+	}
 
 	err = e.kubeClient.GetClient().Delete(ctx, o)
 	if err != nil && !IsK8sObjectNotExists(err) {
@@ -342,7 +460,7 @@ func (e PluginManager) Abort(ctx context.Context, tCtx pluginsCore.TaskExecution
 		return err
 	}
 
-	return nil
+	return e.deleteScratchClaim(ctx, tCtx.TaskExecutionMetadata(), nodeTaskConfig.GetConfig().ScratchVolumeConfig)
 }
 
 func (e *PluginManager) ClearFinalizers(ctx context.Context, o client.Object) error {
@@ -366,15 +484,26 @@ func (e *PluginManager) Finalize(ctx context.Context, tCtx pluginsCore.TaskExecu
 	var o client.Object
 	var nsName k8stypes.NamespacedName
 	cfg := config.GetK8sPluginConfig()
+	// The scratch claim of the attempt is no longer needed once it is finalized, regardless of what happens to the
+	// resource itself.
+	if err := e.deleteScratchClaim(ctx, tCtx.TaskExecutionMetadata(), nodeTaskConfig.GetConfig().ScratchVolumeConfig); err != nil {
+		errs.Append(err)
+	}
+
+	if err := e.revokeCredentials(ctx, tCtx.TaskExecutionMetadata(), nodeTaskConfig.GetConfig().CredentialsConfig); err != nil {
+		errs.Append(err)
+	}
+
 	if cfg.InjectFinalizer || cfg.DeleteResourceOnFinalize {
 		o, err = e.plugin.BuildIdentityResource(ctx, tCtx.TaskExecutionMetadata())
 		if err != nil {
 			// This will recurrent, so we will skip further finalize
 			logger.Errorf(ctx, "Failed to build the Resource with name: %v. Error: %v, when finalizing.", tCtx.TaskExecutionMetadata().GetTaskExecutionID().GetGeneratedName(), err)
-			return nil
+			return errs.ErrorOrDefault()
 		}
 
 		e.AddObjectMetadata(tCtx.TaskExecutionMetadata(), o, config.GetK8sPluginConfig())
+		setAdoptedName(tCtx, o)
 		nsName = k8stypes.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
 	}
 
@@ -386,7 +515,7 @@ func (e *PluginManager) Finalize(ctx context.Context, tCtx pluginsCore.TaskExecu
 		// Attempt to get resource from informer cache, if not found, retrieve it from API server.
 		if err := e.kubeClient.GetClient().Get(ctx, nsName, o); err != nil {
 			if IsK8sObjectNotExists(err) {
-				return nil
+				return errs.ErrorOrDefault()
 			}
 			// This happens sometimes because a node gets removed and K8s deletes the pod. This will result in a
 			// Pod does not exist error. This should be retried using the retry policy
@@ -422,13 +551,23 @@ func (e *PluginManager) Finalize(ctx context.Context, tCtx pluginsCore.TaskExecu
 }
 
 func NewPluginManagerWithBackOff(ctx context.Context, iCtx pluginsCore.SetupContext, entry k8s.PluginEntry, backOffController *backoff.Controller,
-	monitorIndex *ResourceMonitorIndex) (*PluginManager, error) {
+	monitorIndex *ResourceMonitorIndex, eventWatcher *EventWatcher) (*PluginManager, error) {
 
 	mgr, err := NewPluginManager(ctx, iCtx, entry, monitorIndex)
-	if err == nil {
-		mgr.backOffController = backOffController
+	if err != nil {
+		return mgr, err
+	}
+
+	mgr.backOffController = backOffController
+	if eventWatcher != nil {
+		if err := eventWatcher.Start(ctx, iCtx.KubeClient().GetCache()); err != nil {
+			return nil, errors.Wrapf(errors.PluginInitializationFailed, err, "failed to watch K8s events")
+		}
+
+		mgr.eventWatcher = eventWatcher
 	}
-	return mgr, err
+
+	return mgr, nil
 }
 
 // Creates a K8s generic task executor. This provides an easier way to build task executors that create K8s resources.
@@ -561,6 +700,7 @@ func NewPluginManager(ctx context.Context, iCtx pluginsCore.SetupContext, entry
 		metrics:              newPluginMetrics(metricsScope),
 		kubeClient:           kubeClient,
 		resourceLevelMonitor: rm,
+		credentialsMinter:    credentialsMinter,
 	}, nil
 }
 
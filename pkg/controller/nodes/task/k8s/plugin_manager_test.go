@@ -370,7 +370,7 @@ func TestK8sTaskExecutor_Handle_LaunchResource(t *testing.T) {
 			ID:              "x",
 			ResourceToWatch: &v1.Pod{},
 			Plugin:          mockResourceHandler,
-		}, backOffController, NewResourceMonitorIndex())
+		}, backOffController, NewResourceMonitorIndex(), nil)
 
 		assert.NoError(t, err)
 		transition, err := pluginManager.Handle(ctx, tctx)
@@ -452,6 +452,8 @@ func TestPluginManager_Handle_CheckResourceStatus(t *testing.T) {
 			Namespace: tm.GetNamespace(),
 		},
 	}
+	evictedRes := res.DeepCopy()
+	evictedRes.Status.Reason = "Evicted"
 	type args struct {
 		getTaskPhaseCB func() (pluginsCore.PhaseInfo, error)
 		fakeClient     func() extendedFakeClient
@@ -526,6 +528,20 @@ func TestPluginManager_Handle_CheckResourceStatus(t *testing.T) {
 				wantPhase: pluginsCore.PhaseRunning,
 			},
 		},
+		{
+			"lookup-success-evicted",
+			args{
+				fakeClient: func() extendedFakeClient {
+					return extendedFakeClient{Client: fake.NewFakeClient(evictedRes)}
+				},
+				getTaskPhaseCB: func() (pluginsCore.PhaseInfo, error) {
+					return pluginsCore.PhaseInfoFailure("Evicted", "The node was low on resource", nil), nil
+				},
+			},
+			want{
+				wantPhase: pluginsCore.PhaseRetryableFailure,
+			},
+		},
 		{
 			"lookup-success-error",
 			args{
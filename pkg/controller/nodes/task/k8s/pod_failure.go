@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	v1 "k8s.io/api/core/v1"
+)
+
+// PodFailureClass is a structured description of a well known way in which a pod can fail. It determines the error
+// code and kind that are reported for the attempt and whether the attempt may be retried.
+type PodFailureClass struct {
+	Code      string
+	Kind      core.ExecutionError_ErrorKind
+	Retryable bool
+}
+
+var (
+	// The container exceeded its memory limit. Retrying may succeed if the memory usage is not deterministic.
+	PodFailureOOMKilled = PodFailureClass{Code: "OOMKilled", Kind: core.ExecutionError_USER, Retryable: true}
+	// The kubelet evicted the pod, usually because the node ran out of a resource.
+	PodFailureEvicted = PodFailureClass{Code: "Evicted", Kind: core.ExecutionError_SYSTEM, Retryable: true}
+	// The pod exceeded its activeDeadlineSeconds. A retry would run into the same deadline.
+	PodFailureDeadlineExceeded = PodFailureClass{Code: "DeadlineExceeded", Kind: core.ExecutionError_USER, Retryable: false}
+	// The image of one of the containers could not be pulled.
+	PodFailureImagePull = PodFailureClass{Code: "ImagePullBackOff", Kind: core.ExecutionError_USER, Retryable: true}
+	// The node the pod was running on was shut down or lost.
+	PodFailureNodeShutdown = PodFailureClass{Code: "NodeShutdown", Kind: core.ExecutionError_SYSTEM, Retryable: true}
+	// The co-pilot sidecar did not finish uploading the outputs in time after the task container terminated.
+	PodFailureCoPilotUploadTimeout = PodFailureClass{Code: "CoPilotUploadTimeout", Kind: core.ExecutionError_SYSTEM, Retryable: true}
+)
+
+// Annotation set by the sidecar plugin on multi-container pods to designate the container whose termination determines
+// the outcome of the task.
+const primaryContainerKey = "primary_container_name"
+
+var (
+	podEvictedReasons        = []string{"Evicted"}
+	podNodeShutdownReasons   = []string{"Shutdown", "NodeShutdown", "NodeLost", "Terminated"}
+	containerImagePullErrors = []string{"ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull"}
+)
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the statuses of the containers that determine the outcome of the pod. For multi-container pods with a primary
+// container, the statuses of the other (sidecar) containers are ignored, since sidecars are expected to keep running
+// or to be killed once the primary container terminates. Init containers always have to succeed for the primary
+// container to start, so they are always considered.
+func getOutcomeContainerStatuses(pod *v1.Pod) []v1.ContainerStatus {
+	statuses := append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	primaryContainerName := pod.GetAnnotations()[primaryContainerKey]
+	for _, c := range pod.Status.ContainerStatuses {
+		if len(primaryContainerName) == 0 || c.Name == primaryContainerName {
+			statuses = append(statuses, c)
+		}
+	}
+
+	return statuses
+}
+
+// ClassifyPodFailure inspects the pod and container states and returns the failure class of the pod, if the pod is in
+// one of the known failure states.
+func ClassifyPodFailure(pod *v1.Pod) (PodFailureClass, bool) {
+	status := pod.Status
+	switch {
+	case containsString(podEvictedReasons, status.Reason):
+		return PodFailureEvicted, true
+	case containsString(podNodeShutdownReasons, status.Reason):
+		return PodFailureNodeShutdown, true
+	case status.Reason == "DeadlineExceeded":
+		return PodFailureDeadlineExceeded, true
+	}
+
+	containerStatuses := getOutcomeContainerStatuses(pod)
+	// Only the current state tells why a container failed, a container that was OOM killed before it was restarted may
+	// have failed for another reason since.
+	for _, c := range containerStatuses {
+		if c.State.Terminated != nil && c.State.Terminated.Reason == PodFailureOOMKilled.Code {
+			return PodFailureOOMKilled, true
+		}
+	}
+
+	for _, c := range containerStatuses {
+		if c.State.Waiting != nil && containsString(containerImagePullErrors, c.State.Waiting.Reason) {
+			return PodFailureImagePull, true
+		}
+	}
+
+	return PodFailureClass{}, false
+}
+
+// ToPhaseInfo builds the terminal phase info of an attempt that failed with this failure class.
+func (c PodFailureClass) ToPhaseInfo(message string, info *pluginsCore.TaskInfo) pluginsCore.PhaseInfo {
+	phase := pluginsCore.PhasePermanentFailure
+	if c.Retryable {
+		phase = pluginsCore.PhaseRetryableFailure
+	}
+
+	return pluginsCore.PhaseInfoFailed(phase, &core.ExecutionError{
+		Code:    c.Code,
+		Message: message,
+		Kind:    c.Kind,
+	}, info)
+}
+
+// Overrides the phase info reported for a failed pod with the structured failure class of the pod, so that all pod
+// based plugins report the same error codes and retryability for the same failure.
+func classifyPodFailurePhase(o interface{}, p pluginsCore.PhaseInfo, message string) pluginsCore.PhaseInfo {
+	pod, ok := o.(*v1.Pod)
+	if !ok {
+		return p
+	}
+
+	class, ok := ClassifyPodFailure(pod)
+	if !ok {
+		return p
+	}
+
+	if p.Err() != nil && len(p.Err().GetMessage()) > 0 {
+		message = p.Err().GetMessage()
+	}
+
+	if len(message) == 0 {
+		message = pod.Status.Message
+	}
+
+	if len(message) == 0 {
+		message = fmt.Sprintf("pod failed with [%s]", class.Code)
+	}
+
+	return class.ToPhaseInfo(message, p.Info())
+}
@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClassifyPodFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		status v1.PodStatus
+		want   PodFailureClass
+		found  bool
+	}{
+		{"evicted", v1.PodStatus{Reason: "Evicted"}, PodFailureEvicted, true},
+		{"node-shutdown", v1.PodStatus{Reason: "Shutdown"}, PodFailureNodeShutdown, true},
+		{"deadline-exceeded", v1.PodStatus{Reason: "DeadlineExceeded"}, PodFailureDeadlineExceeded, true},
+		{"oom-killed", v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+			},
+		}, PodFailureOOMKilled, true},
+		{"oom-killed-init-container", v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+			},
+		}, PodFailureOOMKilled, true},
+		{"oom-killed-previous-run", v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State:                v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled"}},
+				},
+			},
+		}, PodFailureClass{}, false},
+		{"image-pull", v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+			},
+		}, PodFailureImagePull, true},
+		{"unknown", v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+			},
+		}, PodFailureClass{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, found := ClassifyPodFailure(&v1.Pod{Status: tt.status})
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, class)
+		})
+	}
+}
+
+func TestClassifyPodFailure_PrimaryContainer(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{primaryContainerKey: "primary"}},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "primary", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+				{Name: "sidecar", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+			},
+		},
+	}
+
+	_, found := ClassifyPodFailure(pod)
+	assert.False(t, found)
+
+	pod.Status.ContainerStatuses[0].State.Terminated.Reason = "OOMKilled"
+	class, found := ClassifyPodFailure(pod)
+	assert.True(t, found)
+	assert.Equal(t, PodFailureOOMKilled, class)
+}
+
+func TestClassifyPodFailurePhase(t *testing.T) {
+	t.Run("non-pod", func(t *testing.T) {
+		p := pluginsCore.PhaseInfoFailure("code", "message", nil)
+		assert.Equal(t, p, classifyPodFailurePhase(&v1.Service{}, p, ""))
+	})
+
+	t.Run("retryable", func(t *testing.T) {
+		p := pluginsCore.PhaseInfoFailure("Evicted", "node low on memory", nil)
+		classified := classifyPodFailurePhase(&v1.Pod{Status: v1.PodStatus{Reason: "Evicted"}}, p, "")
+		assert.Equal(t, pluginsCore.PhaseRetryableFailure, classified.Phase())
+		assert.Equal(t, "Evicted", classified.Err().GetCode())
+		assert.Equal(t, core.ExecutionError_SYSTEM, classified.Err().GetKind())
+		assert.Equal(t, "node low on memory", classified.Err().GetMessage())
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		p := pluginsCore.PhaseInfoRetryableFailure("DeadlineExceeded", "", nil)
+		pod := &v1.Pod{Status: v1.PodStatus{Reason: "DeadlineExceeded", Message: "Pod was active too long"}}
+		classified := classifyPodFailurePhase(pod, p, "")
+		assert.Equal(t, pluginsCore.PhasePermanentFailure, classified.Phase())
+		assert.Equal(t, core.ExecutionError_USER, classified.Err().GetKind())
+		assert.Equal(t, "Pod was active too long", classified.Err().GetMessage())
+	})
+}
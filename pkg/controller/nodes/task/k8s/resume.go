@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Annotation that carries the raw output prefix of the previous attempt of resumable tasks from the task execution
+	// metadata to the objects built by the plugins.
+	PreviousAttemptRawOutputPrefixAnnotation = "flyte.org/previous-attempt-raw-output-prefix"
+
+	// Environment variable that holds the raw output prefix of the previous attempt in the containers of the pods of
+	// resumable tasks, so that they can continue from what the previous attempt wrote there.
+	PreviousAttemptRawOutputPrefixEnvVar = "FLYTE_PREVIOUS_ATTEMPT_RAW_OUTPUT_PREFIX"
+)
+
+// WithPreviousAttemptAnnotations returns a copy of the annotations that also holds the raw output prefix of the previous
+// attempt, or the annotations unchanged if there is no previous attempt.
+func WithPreviousAttemptAnnotations(annotations map[string]string, rawOutputPrefix string) map[string]string {
+	if len(rawOutputPrefix) == 0 {
+		return annotations
+	}
+
+	withPrevious := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		withPrevious[k] = v
+	}
+
+	withPrevious[PreviousAttemptRawOutputPrefixAnnotation] = rawOutputPrefix
+	return withPrevious
+}
+
+// Sets the raw output prefix of the previous attempt held by the annotations of the pod in the environment of all of its
+// containers. Objects other than pods are left unchanged, operators that create pods from them are expected to
+// propagate the annotations.
+func addPreviousAttemptEnv(o client.Object) {
+	pod, ok := o.(*v1.Pod)
+	if !ok {
+		return
+	}
+
+	rawOutputPrefix, found := pod.GetAnnotations()[PreviousAttemptRawOutputPrefixAnnotation]
+	if !found {
+		return
+	}
+
+	env := v1.EnvVar{Name: PreviousAttemptRawOutputPrefixEnvVar, Value: rawOutputPrefix}
+	for i := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].Env = append(pod.Spec.InitContainers[i].Env, env)
+	}
+
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, env)
+	}
+}
@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAddPreviousAttemptEnv(t *testing.T) {
+	annotations := map[string]string{"a": "b"}
+	withPrevious := WithPreviousAttemptAnnotations(annotations, "s3://sandbox/x/name-n1-0")
+	assert.Equal(t, map[string]string{"a": "b"}, annotations)
+	assert.Equal(t, "s3://sandbox/x/name-n1-0", withPrevious[PreviousAttemptRawOutputPrefixAnnotation])
+	assert.Equal(t, annotations, WithPreviousAttemptAnnotations(annotations, ""))
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: withPrevious},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init"}},
+			Containers:     []v1.Container{{Name: "primary"}, {Name: "sidecar"}},
+		},
+	}
+
+	addPreviousAttemptEnv(pod)
+	expected := []v1.EnvVar{{Name: PreviousAttemptRawOutputPrefixEnvVar, Value: "s3://sandbox/x/name-n1-0"}}
+	assert.Equal(t, expected, pod.Spec.InitContainers[0].Env)
+	for _, container := range pod.Spec.Containers {
+		assert.Equal(t, expected, container.Env)
+	}
+
+	notResumed := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "primary"}}}}
+	addPreviousAttemptEnv(notResumed)
+	assert.Empty(t, notResumed.Spec.Containers[0].Env)
+}
@@ -0,0 +1,122 @@
+package k8s
+
+import (
+	"context"
+
+	pluginsCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/utils"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const (
+	// Label of the PVCs that back persistent scratch volumes. It is used to count the claims of a namespace.
+	scratchClaimLabel = "flyte.org/scratch-claim"
+
+	scratchClaimSuffix = "-scratch"
+)
+
+// Persistent scratch volumes are backed by one PVC per node attempt, named after the pod of the attempt.
+func getScratchClaimName(podName string) string {
+	return podName + scratchClaimSuffix
+}
+
+func requestsScratchClaim(annotations map[string]string, cfg nodeTaskConfig.ScratchVolumeConfig) bool {
+	return cfg.Enabled && annotations[ScratchVolumeTypeAnnotation] == v1alpha1.ScratchVolumeTypePersistent
+}
+
+func newScratchClaim(pod *v1.Pod, size resource.Quantity, cfg nodeTaskConfig.ScratchVolumeConfig) *v1.PersistentVolumeClaim {
+	claim := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getScratchClaimName(pod.GetName()),
+			Namespace: pod.GetNamespace(),
+			Labels:    utils.UnionMaps(pod.GetLabels(), map[string]string{scratchClaimLabel: "true"}),
+			// Owned by the workflow, like the pod itself, so that the claim is garbage collected with the workflow even
+			// if the attempt is never finalized.
+			OwnerReferences: pod.GetOwnerReferences(),
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: size},
+			},
+		},
+	}
+
+	if len(cfg.Persistent.StorageClassName) > 0 {
+		storageClassName := cfg.Persistent.StorageClassName
+		claim.Spec.StorageClassName = &storageClassName
+	}
+
+	return claim
+}
+
+// Creates the PVC that backs the persistent scratch volume of the pod, if it requests one. Returns false if the claim
+// cannot be created yet because its namespace already has the maximum number of scratch claims.
+func (e *PluginManager) ensureScratchClaim(ctx context.Context, o client.Object, cfg nodeTaskConfig.ScratchVolumeConfig) (bool, error) {
+	pod, ok := o.(*v1.Pod)
+	if !ok || !requestsScratchClaim(pod.GetAnnotations(), cfg) {
+		return true, nil
+	}
+
+	_, size, _, err := getScratchVolumeRequest(pod.GetAnnotations(), cfg)
+	if err != nil {
+		return false, err
+	}
+
+	claims := &v1.PersistentVolumeClaimList{}
+	if err := e.kubeClient.GetClient().List(ctx, claims, client.InNamespace(pod.GetNamespace()),
+		client.MatchingLabels{scratchClaimLabel: "true"}); err != nil {
+		return false, err
+	}
+
+	claimName := getScratchClaimName(pod.GetName())
+	for _, existing := range claims.Items {
+		if existing.GetName() == claimName {
+			return true, nil
+		}
+	}
+
+	if maxClaims := cfg.Persistent.MaxClaimsPerNamespace; maxClaims > 0 && len(claims.Items) >= maxClaims {
+		logger.Infof(ctx, "Namespace [%v] has reached the maximum of [%v] scratch claims, delaying the creation of [%v]",
+			pod.GetNamespace(), maxClaims, claimName)
+		return false, nil
+	}
+
+	logger.Infof(ctx, "Creating scratch claim [%v/%v] of size [%v]", pod.GetNamespace(), claimName, size.String())
+	if err := e.kubeClient.GetClient().Create(ctx, newScratchClaim(pod, size, cfg)); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Deletes the PVC that backs the persistent scratch volume of the node attempt, if it requested one.
+func (e *PluginManager) deleteScratchClaim(ctx context.Context, taskCtx pluginsCore.TaskExecutionMetadata,
+	cfg nodeTaskConfig.ScratchVolumeConfig) error {
+
+	if !requestsScratchClaim(taskCtx.GetAnnotations(), cfg) {
+		return nil
+	}
+
+	claim := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getScratchClaimName(taskCtx.GetTaskExecutionID().GetGeneratedName()),
+			Namespace: taskCtx.GetNamespace(),
+		},
+	}
+
+	if err := e.kubeClient.GetClient().Delete(ctx, claim); err != nil && !IsK8sObjectNotExists(err) {
+		logger.Warningf(ctx, "Failed to delete scratch claim [%v/%v]. Error: %v", claim.Namespace, claim.Name, err)
+		return err
+	}
+
+	return nil
+}
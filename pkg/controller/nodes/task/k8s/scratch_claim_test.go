@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	pluginsCoreMock "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core/mocks"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func TestScratchClaims(t *testing.T) {
+	ctx := context.TODO()
+	cfg := nodeTaskConfig.ScratchVolumeConfig{
+		Enabled:   true,
+		MountPath: "/scratch",
+		MaxSize:   "100Gi",
+		Persistent: nodeTaskConfig.PersistentScratchVolumeConfig{
+			StorageClassName:      "durable",
+			MaxClaimsPerNamespace: 2,
+		},
+	}
+
+	annotations := WithScratchVolumeAnnotations(nil, &v1alpha1.ScratchVolume{
+		Type: v1alpha1.ScratchVolumeTypePersistent,
+		Size: "10Gi",
+	})
+
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "ns",
+				Annotations:     annotations,
+				OwnerReferences: []metav1.OwnerReference{{Name: "wf"}},
+			},
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "primary"}}},
+		}
+	}
+
+	newPluginManager := func(c client.Client) *PluginManager {
+		kubeClient := &pluginsCoreMock.KubeClient{}
+		kubeClient.OnGetClient().Return(c)
+		return &PluginManager{kubeClient: kubeClient}
+	}
+
+	t.Run("mounted", func(t *testing.T) {
+		pod := newPod("a")
+		assert.NoError(t, addScratchVolume(pod, cfg))
+		if assert.Len(t, pod.Spec.Volumes, 1) {
+			assert.Equal(t, "a-scratch", pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		}
+	})
+
+	t.Run("create-and-delete", func(t *testing.T) {
+		c := fake.NewClientBuilder().Build()
+		e := newPluginManager(c)
+
+		created, err := e.ensureScratchClaim(ctx, newPod("a"), cfg)
+		assert.NoError(t, err)
+		assert.True(t, created)
+
+		claim := &v1.PersistentVolumeClaim{}
+		assert.NoError(t, c.Get(ctx, k8stypes.NamespacedName{Namespace: "ns", Name: "a-scratch"}, claim))
+		assert.Equal(t, "durable", *claim.Spec.StorageClassName)
+		assert.Equal(t, resource.MustParse("10Gi"), claim.Spec.Resources.Requests[v1.ResourceStorage])
+		assert.Equal(t, "true", claim.Labels[scratchClaimLabel])
+		assert.Equal(t, []metav1.OwnerReference{{Name: "wf"}}, claim.OwnerReferences)
+
+		// Relaunching the same attempt reuses its claim.
+		created, err = e.ensureScratchClaim(ctx, newPod("a"), cfg)
+		assert.NoError(t, err)
+		assert.True(t, created)
+
+		tm := getMockTaskExecutionMetadataCustom("a", "ns", annotations, nil, metav1.OwnerReference{})
+		assert.NoError(t, e.deleteScratchClaim(ctx, tm, cfg))
+		assert.True(t, IsK8sObjectNotExists(c.Get(ctx, k8stypes.NamespacedName{Namespace: "ns", Name: "a-scratch"}, claim)))
+
+		// Deleting a claim that no longer exists is not an error.
+		assert.NoError(t, e.deleteScratchClaim(ctx, tm, cfg))
+	})
+
+	t.Run("quota", func(t *testing.T) {
+		c := fake.NewClientBuilder().Build()
+		e := newPluginManager(c)
+
+		for _, name := range []string{"a", "b"} {
+			created, err := e.ensureScratchClaim(ctx, newPod(name), cfg)
+			assert.NoError(t, err)
+			assert.True(t, created)
+		}
+
+		created, err := e.ensureScratchClaim(ctx, newPod("c"), cfg)
+		assert.NoError(t, err)
+		assert.False(t, created)
+
+		// Existing claims still count as created.
+		created, err = e.ensureScratchClaim(ctx, newPod("b"), cfg)
+		assert.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("not-requested", func(t *testing.T) {
+		e := newPluginManager(fake.NewClientBuilder().Build())
+		pod := newPod("a")
+		pod.Annotations = WithScratchVolumeAnnotations(nil, &v1alpha1.ScratchVolume{
+			Type: v1alpha1.ScratchVolumeTypeEmptyDir,
+			Size: "10Gi",
+		})
+
+		created, err := e.ensureScratchClaim(ctx, pod, cfg)
+		assert.NoError(t, err)
+		assert.True(t, created)
+
+		tm := getMockTaskExecutionMetadataCustom("a", "ns", pod.Annotations, nil, metav1.OwnerReference{})
+		assert.NoError(t, e.deleteScratchClaim(ctx, tm, cfg))
+	})
+}
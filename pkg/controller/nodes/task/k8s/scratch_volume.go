@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+const (
+	// Annotations that carry the scratch volume requested by the execution from the task execution metadata to the pods
+	// built by the plugins.
+	ScratchVolumeTypeAnnotation = "flyte.org/scratch-volume-type"
+	ScratchVolumeSizeAnnotation = "flyte.org/scratch-volume-size"
+
+	scratchVolumeName = "flyte-scratch"
+)
+
+// WithScratchVolumeAnnotations returns a copy of the annotations that also describes the given scratch volume, or the
+// annotations unchanged if no scratch volume is requested.
+func WithScratchVolumeAnnotations(annotations map[string]string, scratchVolume *v1alpha1.ScratchVolume) map[string]string {
+	if scratchVolume == nil {
+		return annotations
+	}
+
+	withScratch := make(map[string]string, len(annotations)+2)
+	for k, v := range annotations {
+		withScratch[k] = v
+	}
+
+	withScratch[ScratchVolumeTypeAnnotation] = scratchVolume.Type
+	withScratch[ScratchVolumeSizeAnnotation] = scratchVolume.Size
+	return withScratch
+}
+
+// Returns the type and size of the scratch volume described by the annotations, if any.
+func getScratchVolumeRequest(annotations map[string]string, cfg nodeTaskConfig.ScratchVolumeConfig) (
+	volumeType string, size resource.Quantity, found bool, err error) {
+
+	volumeType, found = annotations[ScratchVolumeTypeAnnotation]
+	if !found {
+		return "", size, false, nil
+	}
+
+	size, err = resource.ParseQuantity(annotations[ScratchVolumeSizeAnnotation])
+	if err != nil {
+		return "", size, true, fmt.Errorf("invalid scratch volume size: %v", err)
+	}
+
+	if maxSize, err := resource.ParseQuantity(cfg.MaxSize); err == nil && size.Cmp(maxSize) > 0 {
+		return "", size, true, fmt.Errorf("requested scratch volume size [%s] exceeds the maximum of [%s]", size.String(), cfg.MaxSize)
+	}
+
+	return volumeType, size, true, nil
+}
+
+func newScratchVolume(podName, volumeType string, size resource.Quantity, cfg nodeTaskConfig.ScratchVolumeConfig) (v1.Volume, error) {
+	switch volumeType {
+	case v1alpha1.ScratchVolumeTypeEmptyDir:
+		return v1.Volume{
+			Name: scratchVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{SizeLimit: &size},
+			},
+		}, nil
+	case v1alpha1.ScratchVolumeTypeEphemeral:
+		spec := v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: size},
+			},
+		}
+
+		if len(cfg.StorageClassName) > 0 {
+			storageClassName := cfg.StorageClassName
+			spec.StorageClassName = &storageClassName
+		}
+
+		return v1.Volume{
+			Name: scratchVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Ephemeral: &v1.EphemeralVolumeSource{
+					VolumeClaimTemplate: &v1.PersistentVolumeClaimTemplate{Spec: spec},
+				},
+			},
+		}, nil
+	case v1alpha1.ScratchVolumeTypePersistent:
+		return v1.Volume{
+			Name: scratchVolumeName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: getScratchClaimName(podName)},
+			},
+		}, nil
+	}
+
+	return v1.Volume{}, fmt.Errorf("unknown scratch volume type [%s]", volumeType)
+}
+
+// Attaches the scratch volume described by the annotations of the pod and mounts it in all of its containers. Objects
+// other than pods are left unchanged, operators that create pods from them are expected to propagate the annotations.
+func addScratchVolume(o client.Object, cfg nodeTaskConfig.ScratchVolumeConfig) error {
+	pod, ok := o.(*v1.Pod)
+	if !cfg.Enabled || !ok {
+		return nil
+	}
+
+	volumeType, size, found, err := getScratchVolumeRequest(pod.GetAnnotations(), cfg)
+	if err != nil || !found {
+		return err
+	}
+
+	volume, err := newScratchVolume(pod.GetName(), volumeType, size, cfg)
+	if err != nil {
+		return err
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+	mount := v1.VolumeMount{Name: scratchVolumeName, MountPath: cfg.MountPath}
+	for i := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].VolumeMounts = append(pod.Spec.InitContainers[i].VolumeMounts, mount)
+	}
+
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, mount)
+	}
+
+	return nil
+}
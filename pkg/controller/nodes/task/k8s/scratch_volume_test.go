@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	nodeTaskConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func TestWithScratchVolumeAnnotations(t *testing.T) {
+	annotations := map[string]string{"a": "b"}
+	assert.Equal(t, annotations, WithScratchVolumeAnnotations(annotations, nil))
+
+	withScratch := WithScratchVolumeAnnotations(annotations, &v1alpha1.ScratchVolume{
+		Type: v1alpha1.ScratchVolumeTypeEmptyDir,
+		Size: "10Gi",
+	})
+	assert.Equal(t, map[string]string{
+		"a":                         "b",
+		ScratchVolumeTypeAnnotation: v1alpha1.ScratchVolumeTypeEmptyDir,
+		ScratchVolumeSizeAnnotation: "10Gi",
+	}, withScratch)
+	assert.Len(t, annotations, 1)
+}
+
+func TestAddScratchVolume(t *testing.T) {
+	cfg := nodeTaskConfig.ScratchVolumeConfig{
+		Enabled:          true,
+		MountPath:        "/scratch",
+		StorageClassName: "fast",
+		MaxSize:          "100Gi",
+	}
+
+	newPod := func(volumeType, size string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: WithScratchVolumeAnnotations(nil, &v1alpha1.ScratchVolume{Type: volumeType, Size: size}),
+			},
+			Spec: v1.PodSpec{
+				InitContainers: []v1.Container{{Name: "init"}},
+				Containers:     []v1.Container{{Name: "primary"}, {Name: "sidecar"}},
+			},
+		}
+	}
+
+	t.Run("empty-dir", func(t *testing.T) {
+		pod := newPod(v1alpha1.ScratchVolumeTypeEmptyDir, "10Gi")
+		assert.NoError(t, addScratchVolume(pod, cfg))
+		if assert.Len(t, pod.Spec.Volumes, 1) {
+			assert.Equal(t, resource.MustParse("10Gi"), *pod.Spec.Volumes[0].EmptyDir.SizeLimit)
+		}
+
+		for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			assert.Equal(t, []v1.VolumeMount{{Name: scratchVolumeName, MountPath: "/scratch"}}, c.VolumeMounts)
+		}
+	})
+
+	t.Run("ephemeral", func(t *testing.T) {
+		pod := newPod(v1alpha1.ScratchVolumeTypeEphemeral, "10Gi")
+		assert.NoError(t, addScratchVolume(pod, cfg))
+		if assert.Len(t, pod.Spec.Volumes, 1) {
+			spec := pod.Spec.Volumes[0].Ephemeral.VolumeClaimTemplate.Spec
+			assert.Equal(t, "fast", *spec.StorageClassName)
+			assert.Equal(t, resource.MustParse("10Gi"), spec.Resources.Requests[v1.ResourceStorage])
+		}
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		assert.Error(t, addScratchVolume(newPod(v1alpha1.ScratchVolumeTypeEmptyDir, "1Ti"), cfg))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Error(t, addScratchVolume(newPod(v1alpha1.ScratchVolumeTypeEmptyDir, "lots"), cfg))
+		assert.Error(t, addScratchVolume(newPod("hostPath", "10Gi"), cfg))
+	})
+
+	t.Run("not-requested", func(t *testing.T) {
+		pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "primary"}}}}
+		assert.NoError(t, addScratchVolume(pod, cfg))
+		assert.Empty(t, pod.Spec.Volumes)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		pod := newPod(v1alpha1.ScratchVolumeTypeEmptyDir, "10Gi")
+		assert.NoError(t, addScratchVolume(pod, disabled))
+		assert.Empty(t, pod.Spec.Volumes)
+	})
+}
@@ -0,0 +1,179 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Code of the error attempts that wrote both outputs and an error fail with, under the Fail policy.
+const outputErrorConflictErrorCode = "OutputErrorConflict"
+
+// Key of the custom info of task events that describes the conflict of an attempt that wrote both outputs and an error.
+const outputErrorConflictInfoKey = "outputErrorConflict"
+
+// OutputWriteTimesReader is implemented by output readers that can tell when the outputs and the error of an attempt
+// were written. The PreferLatest policy can only tell which of them is the latest with such readers.
+type OutputWriteTimesReader interface {
+	OutputsWrittenAt(ctx context.Context) (time.Time, error)
+	ErrorWrittenAt(ctx context.Context) (time.Time, error)
+}
+
+type outputErrorConflictResolution = string
+
+const (
+	outputErrorConflictUseOutputs outputErrorConflictResolution = "UseOutputs"
+	outputErrorConflictUseError   outputErrorConflictResolution = "UseError"
+	outputErrorConflictFailed     outputErrorConflictResolution = "Failed"
+)
+
+// Describes how the conflict of an attempt that wrote both outputs and an error was resolved.
+type outputErrorConflict struct {
+	policy     config.OutputErrorConflictPolicy
+	resolution outputErrorConflictResolution
+	// When the outputs and the error were written, zero if the output reader cannot tell.
+	outputsWrittenAt time.Time
+	errorWrittenAt   time.Time
+}
+
+// Returns the error the attempt fails with, nil unless the conflict was resolved by failing the attempt.
+func (c *outputErrorConflict) executionError() *io.ExecutionError {
+	if c.resolution != outputErrorConflictFailed {
+		return nil
+	}
+
+	return &io.ExecutionError{
+		ExecutionError: &core.ExecutionError{
+			Code:    outputErrorConflictErrorCode,
+			Message: "Task attempt wrote both outputs and an error",
+			Kind:    core.ExecutionError_SYSTEM,
+		},
+		IsRecoverable: true,
+	}
+}
+
+func (c *outputErrorConflict) toStruct() *structpb.Struct {
+	fields := map[string]*structpb.Value{
+		"policy":     {Kind: &structpb.Value_StringValue{StringValue: c.policy}},
+		"resolution": {Kind: &structpb.Value_StringValue{StringValue: c.resolution}},
+	}
+
+	if !c.outputsWrittenAt.IsZero() && !c.errorWrittenAt.IsZero() {
+		fields["outputsWrittenAt"] = &structpb.Value{Kind: &structpb.Value_StringValue{
+			StringValue: c.outputsWrittenAt.UTC().Format(time.RFC3339Nano)}}
+		fields["errorWrittenAt"] = &structpb.Value{Kind: &structpb.Value_StringValue{
+			StringValue: c.errorWrittenAt.UTC().Format(time.RFC3339Nano)}}
+	}
+
+	return &structpb.Struct{Fields: fields}
+}
+
+// An output reader of an attempt that wrote both outputs and an error, which only reports the one that was picked.
+type resolvedOutputReader struct {
+	io.OutputReader
+	isError bool
+}
+
+func (r resolvedOutputReader) IsError(_ context.Context) (bool, error) {
+	return r.isError, nil
+}
+
+func (r resolvedOutputReader) Exists(_ context.Context) (bool, error) {
+	return !r.isError, nil
+}
+
+// Returns whether the outputs of the attempt were written after its error. False if the reader cannot tell, or if they
+// were written at the same time, so that the error is used unless the outputs are known to be the latest.
+func outputsWrittenLast(ctx context.Context, r io.OutputReader) (outputsWrittenAt, errorWrittenAt time.Time, last bool) {
+	timesReader, ok := r.(OutputWriteTimesReader)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	outputsWrittenAt, err := timesReader.OutputsWrittenAt(ctx)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to read when the outputs were written. Error: %v", err)
+		return time.Time{}, time.Time{}, false
+	}
+
+	errorWrittenAt, err = timesReader.ErrorWrittenAt(ctx)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to read when the error was written. Error: %v", err)
+		return time.Time{}, time.Time{}, false
+	}
+
+	return outputsWrittenAt, errorWrittenAt, outputsWrittenAt.After(errorWrittenAt)
+}
+
+// Detects attempts that wrote both outputs and an error, e.g. because their container was restarted in place after
+// writing one of them, and picks one of them according to the policy. Returns the reader to validate the outputs with,
+// which only reports the one that was picked, and the conflict, nil if the attempt did not write both.
+func (t *Handler) resolveOutputErrorConflict(ctx context.Context, r io.OutputReader, cfg config.OutputErrorConflictConfig) (
+	io.OutputReader, *outputErrorConflict, error) {
+
+	if r == nil {
+		return r, nil, nil
+	}
+
+	isError, err := r.IsError(ctx)
+	if err != nil || !isError {
+		return r, nil, err
+	}
+
+	exists, err := r.Exists(ctx)
+	if err != nil || !exists {
+		return r, nil, err
+	}
+
+	conflict := &outputErrorConflict{policy: cfg.Policy}
+	switch cfg.Policy {
+	case config.OutputErrorConflictFail:
+		conflict.resolution = outputErrorConflictFailed
+	case config.OutputErrorConflictPreferError:
+		conflict.resolution = outputErrorConflictUseError
+	default:
+		var outputsLast bool
+		conflict.outputsWrittenAt, conflict.errorWrittenAt, outputsLast = outputsWrittenLast(ctx, r)
+		conflict.resolution = outputErrorConflictUseError
+		if outputsLast {
+			conflict.resolution = outputErrorConflictUseOutputs
+		}
+	}
+
+	t.metrics.outputErrorConflicts.Inc(ctx)
+	logger.Warnf(ctx, "Task attempt wrote both outputs and an error, resolved to [%v] under policy [%v]",
+		conflict.resolution, conflict.policy)
+	return resolvedOutputReader{OutputReader: r, isError: conflict.resolution != outputErrorConflictUseOutputs}, conflict, nil
+}
+
+// ObserveOutputErrorConflict records the conflict in the custom info of the final task event. It is only observed for
+// plugins that succeeded.
+func (p *pluginRequestedTransition) ObserveOutputErrorConflict(conflict *outputErrorConflict) {
+	info := p.pInfo.Info()
+	if info == nil {
+		info = &pluginCore.TaskInfo{}
+		p.pInfo = pluginCore.PhaseInfoSuccess(info)
+	}
+
+	customInfo := &structpb.Struct{}
+	if info.CustomInfo != nil {
+		customInfo = proto.Clone(info.CustomInfo).(*structpb.Struct)
+	}
+
+	if customInfo.Fields == nil {
+		customInfo.Fields = make(map[string]*structpb.Value)
+	}
+
+	customInfo.Fields[outputErrorConflictInfoKey] = &structpb.Value{
+		Kind: &structpb.Value_StructValue{StructValue: conflict.toStruct()},
+	}
+	info.CustomInfo = customInfo
+}
@@ -0,0 +1,124 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
+	ioMocks "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io/mocks"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+type timedOutputReader struct {
+	io.OutputReader
+	outputsWrittenAt time.Time
+	errorWrittenAt   time.Time
+}
+
+func (r timedOutputReader) OutputsWrittenAt(_ context.Context) (time.Time, error) {
+	return r.outputsWrittenAt, nil
+}
+
+func (r timedOutputReader) ErrorWrittenAt(_ context.Context) (time.Time, error) {
+	return r.errorWrittenAt, nil
+}
+
+func TestHandler_resolveOutputErrorConflict(t *testing.T) {
+	ctx := context.TODO()
+	h := Handler{
+		metrics: &metrics{
+			outputErrorConflicts: labeled.NewCounter("output_error_conflicts", "", promutils.NewTestScope()),
+		},
+	}
+
+	newReader := func(isError, exists bool) *ioMocks.OutputReader {
+		r := &ioMocks.OutputReader{}
+		r.OnIsErrorMatch(mock.Anything).Return(isError, nil)
+		r.OnExistsMatch(mock.Anything).Return(exists, nil)
+		return r
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name       string
+		policy     config.OutputErrorConflictPolicy
+		reader     io.OutputReader
+		resolution outputErrorConflictResolution
+	}{
+		{"prefer-latest-unknown-times", config.OutputErrorConflictPreferLatest, newReader(true, true), outputErrorConflictUseError},
+		{"prefer-latest-outputs", config.OutputErrorConflictPreferLatest,
+			timedOutputReader{OutputReader: newReader(true, true), outputsWrittenAt: now, errorWrittenAt: now.Add(-time.Second)},
+			outputErrorConflictUseOutputs},
+		{"prefer-latest-error", config.OutputErrorConflictPreferLatest,
+			timedOutputReader{OutputReader: newReader(true, true), outputsWrittenAt: now.Add(-time.Second), errorWrittenAt: now},
+			outputErrorConflictUseError},
+		{"prefer-latest-tie", config.OutputErrorConflictPreferLatest,
+			timedOutputReader{OutputReader: newReader(true, true), outputsWrittenAt: now, errorWrittenAt: now},
+			outputErrorConflictUseError},
+		{"prefer-error", config.OutputErrorConflictPreferError,
+			timedOutputReader{OutputReader: newReader(true, true), outputsWrittenAt: now, errorWrittenAt: now.Add(-time.Second)},
+			outputErrorConflictUseError},
+		{"fail", config.OutputErrorConflictFail, newReader(true, true), outputErrorConflictFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, conflict, err := h.resolveOutputErrorConflict(ctx, tt.reader, config.OutputErrorConflictConfig{Policy: tt.policy})
+			assert.NoError(t, err)
+			if !assert.NotNil(t, conflict) {
+				return
+			}
+
+			assert.Equal(t, tt.resolution, conflict.resolution)
+			isError, err := r.IsError(ctx)
+			assert.NoError(t, err)
+			exists, err := r.Exists(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.resolution != outputErrorConflictUseOutputs, isError)
+			assert.Equal(t, tt.resolution == outputErrorConflictUseOutputs, exists)
+
+			if ee := conflict.executionError(); tt.resolution == outputErrorConflictFailed {
+				if assert.NotNil(t, ee) {
+					assert.True(t, ee.IsRecoverable)
+					assert.Equal(t, outputErrorConflictErrorCode, ee.Code)
+				}
+			} else {
+				assert.Nil(t, ee)
+			}
+		})
+	}
+
+	t.Run("no-conflict", func(t *testing.T) {
+		for _, reader := range []io.OutputReader{newReader(true, false), newReader(false, true), nil} {
+			r, conflict, err := h.resolveOutputErrorConflict(ctx, reader, config.OutputErrorConflictConfig{
+				Policy: config.OutputErrorConflictFail})
+			assert.NoError(t, err)
+			assert.Nil(t, conflict)
+			assert.Equal(t, reader, r)
+		}
+	})
+}
+
+func TestPluginRequestedTransition_ObserveOutputErrorConflict(t *testing.T) {
+	p := &pluginRequestedTransition{pInfo: pluginCore.PhaseInfoSuccess(nil)}
+	p.ObserveOutputErrorConflict(&outputErrorConflict{
+		policy:     config.OutputErrorConflictPreferLatest,
+		resolution: outputErrorConflictUseError,
+	})
+
+	p.ObservedExecutionError(&io.ExecutionError{ExecutionError: &core.ExecutionError{Code: "x"}})
+	assert.Equal(t, pluginCore.PhasePermanentFailure, p.pInfo.Phase())
+	conflict := p.pInfo.Info().CustomInfo.GetFields()[outputErrorConflictInfoKey].GetStructValue()
+	if assert.NotNil(t, conflict) {
+		assert.Equal(t, "UseError", conflict.GetFields()["resolution"].GetStringValue())
+		assert.Equal(t, "PreferLatest", conflict.GetFields()["policy"].GetStringValue())
+	}
+}
@@ -7,10 +7,10 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/backoff"
 
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
-	"github.com/flyteorg/flytestdlib/logger"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 func WranglePluginsAndGenerateFinalList(ctx context.Context, cfg *config.TaskPluginConfig, pr PluginRegistryIface) ([]core.PluginEntry, error) {
@@ -51,6 +51,12 @@ func WranglePluginsAndGenerateFinalList(ctx context.Context, cfg *config.TaskPlu
 	// Create a single resource monitor object for all plugins to use
 	monitorIndex := k8s.NewResourceMonitorIndex()
 
+	// Create a single K8s event watcher for all plugins to share, if surfacing Warning events is enabled
+	var eventWatcher *k8s.EventWatcher
+	if eventWatcherCfg := config.GetConfig().EventWatcherConfig; eventWatcherCfg.Enabled {
+		eventWatcher = k8s.NewEventWatcher(eventWatcherCfg.Reasons)
+	}
+
 	k8sPlugins := pr.GetK8sPlugins()
 	for i := range k8sPlugins {
 		kpe := k8sPlugins[i]
@@ -63,7 +69,7 @@ func WranglePluginsAndGenerateFinalList(ctx context.Context, cfg *config.TaskPlu
 				ID:                  id,
 				RegisteredTaskTypes: kpe.RegisteredTaskTypes,
 				LoadPlugin: func(ctx context.Context, iCtx core.SetupContext) (plugin core.Plugin, e error) {
-					return k8s.NewPluginManagerWithBackOff(ctx, iCtx, kpe, backOffController, monitorIndex)
+					return k8s.NewPluginManagerWithBackOff(ctx, iCtx, kpe, backOffController, monitorIndex, eventWatcher)
 				},
 				IsDefault:           kpe.IsDefault,
 				DefaultForTaskTypes: pluginsConfigMeta.AllDefaultForTaskTypes[id],
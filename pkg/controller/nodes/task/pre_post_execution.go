@@ -2,6 +2,8 @@ package task
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
 
@@ -9,31 +11,68 @@ import (
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
 	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
-	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/storage"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	errors2 "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	catalogLineage "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog/datacatalog"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 var cacheDisabled = catalog.NewStatus(core.CatalogCacheStatus_CACHE_DISABLED, nil)
 
-func (t *Handler) CheckCatalogCache(ctx context.Context, tr pluginCore.TaskReader, inputReader io.InputReader, outputWriter io.OutputWriter) (catalog.Entry, error) {
+// Key of the task config that holds the max cache age of the task, formatted as a Go duration, e.g. 24h.
+const taskConfigMaxCacheAgeKey = "cache_max_age"
+
+// GetTaskMaxCacheAge returns the max cache age of the task from its task config, or from the per task catalog config.
+// Returns false if neither sets one, in which case the max cache age of the catalog client applies. Invalid max cache
+// ages in the task config are ignored.
+func GetTaskMaxCacheAge(ctx context.Context, tk *core.TaskTemplate, cfg *catalogLineage.Config) (time.Duration, bool) {
+	if value, ok := tk.GetConfig()[taskConfigMaxCacheAgeKey]; ok {
+		maxCacheAge, err := time.ParseDuration(value)
+		if err == nil {
+			return maxCacheAge, true
+		}
+
+		logger.Warnf(ctx, "Ignoring invalid %v [%v] in task config. Error: %v", taskConfigMaxCacheAgeKey, value, err)
+	}
+
+	if maxCacheAge, ok := cfg.TaskMaxCacheAge[tk.GetId().GetName()]; ok {
+		return maxCacheAge.Duration, true
+	}
+
+	return 0, false
+}
+
+func (t *Handler) CheckCatalogCache(ctx context.Context, tr pluginCore.TaskReader, inputReader io.InputReader,
+	outputWriter io.OutputWriter, executionConfig v1alpha1.ExecutionConfig) (catalog.Entry, error) {
 	tk, err := tr.Read(ctx)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to read TaskTemplate, error :%s", err.Error())
 		return catalog.Entry{}, err
 	}
 
+	if tk.Metadata.Discoverable && executionConfig.OverwriteCache {
+		// The cached outputs are replaced by the outputs of this execution once it succeeds.
+		logger.Infof(ctx, "Catalog CacheOverwrite: Skipping catalog lookup for Task [%s/%s/%s/%s]", tk.Id.Project,
+			tk.Id.Domain, tk.Id.Name, tk.Id.Version)
+		return catalog.NewCatalogEntry(nil, catalog.NewStatus(core.CatalogCacheStatus_CACHE_MISS, nil)), nil
+	}
+
 	if tk.Metadata.Discoverable {
 		logger.Infof(ctx, "Catalog CacheEnabled: Looking up catalog Cache.")
-		key := catalog.Key{
-			Identifier:     *tk.Id,
-			CacheVersion:   tk.Metadata.DiscoveryVersion,
-			TypedInterface: *tk.Interface,
-			InputReader:    inputReader,
+		key, err := NewCatalogKey(ctx, tk, inputReader)
+		if err != nil {
+			logger.Errorf(ctx, "Failed to generate catalog key, error :%s", err.Error())
+			return catalog.Entry{}, err
+		}
+
+		if maxCacheAge, ok := GetTaskMaxCacheAge(ctx, tk, catalogLineage.GetConfig()); ok {
+			ctx = datacatalog.WithMaxCacheAge(ctx, maxCacheAge)
 		}
 
 		resp, err := t.catalog.Get(ctx, key)
@@ -71,6 +110,73 @@ func (t *Handler) CheckCatalogCache(ctx context.Context, tr pluginCore.TaskReade
 	return catalog.NewCatalogEntry(nil, cacheDisabled), nil
 }
 
+// CatalogPrefetchRequest identifies a task execution whose inputs are known before it runs.
+type CatalogPrefetchRequest struct {
+	Task   *core.TaskTemplate
+	Inputs *core.LiteralMap
+}
+
+// staticInputReader is an InputReader of inputs that are known before the task runs and are not stored yet.
+type staticInputReader struct {
+	inputs *core.LiteralMap
+}
+
+func (s staticInputReader) GetInputPrefixPath() storage.DataReference {
+	return ""
+}
+
+func (s staticInputReader) GetInputPath() storage.DataReference {
+	return ""
+}
+
+func (s staticInputReader) Get(_ context.Context) (*core.LiteralMap, error) {
+	return s.inputs, nil
+}
+
+// NewStaticInputReader returns an InputReader of inputs that are known before the task runs.
+func NewStaticInputReader(inputs *core.LiteralMap) io.InputReader {
+	return staticInputReader{inputs: inputs}
+}
+
+// PrefetchCatalog looks up the cached outputs of the given task executions in bulk, if the catalog client supports it,
+// so that the cache lookups of the executions are served from memory when they start. Executions of tasks that are not
+// cached are skipped. Prefetching is best effort and never fails.
+func (t *Handler) PrefetchCatalog(ctx context.Context, requests []CatalogPrefetchRequest) {
+	prefetcher, ok := t.catalog.(catalogLineage.Prefetcher)
+	if !ok || len(requests) == 0 {
+		return
+	}
+
+	// Keys are grouped by task, since each task may have its own max cache age.
+	tasks := make(map[string]*core.TaskTemplate)
+	keys := make(map[string][]catalog.Key)
+	for _, r := range requests {
+		if !r.Task.GetMetadata().GetDiscoverable() || r.Task.GetId() == nil || r.Task.GetInterface() == nil {
+			continue
+		}
+
+		key, err := NewCatalogKey(ctx, r.Task, staticInputReader{inputs: r.Inputs})
+		if err != nil {
+			logger.Warnf(ctx, "Failed to generate catalog key for Task [%v]. Error: %v", r.Task.GetId(), err)
+			continue
+		}
+
+		taskID := r.Task.GetId().String()
+		tasks[taskID] = r.Task
+		keys[taskID] = append(keys[taskID], key)
+	}
+
+	for taskID, tk := range tasks {
+		taskCtx := ctx
+		if maxCacheAge, ok := GetTaskMaxCacheAge(ctx, tk, catalogLineage.GetConfig()); ok {
+			taskCtx = datacatalog.WithMaxCacheAge(ctx, maxCacheAge)
+		}
+
+		hits := prefetcher.Prefetch(taskCtx, keys[taskID])
+		logger.Debugf(ctx, "Prefetched [%v/%v] cached executions of Task [%v]", hits, len(keys[taskID]), tk.GetId())
+	}
+}
+
 func (t *Handler) ValidateOutputAndCacheAdd(ctx context.Context, nodeID v1alpha1.NodeID, i io.InputReader,
 	r io.OutputReader, outputCommitter io.OutputWriter, executionConfig v1alpha1.ExecutionConfig,
 	tr ioutils.SimpleTaskReader, m catalog.Metadata) (catalog.Status, *io.ExecutionError, error) {
@@ -157,34 +263,91 @@ func (t *Handler) ValidateOutputAndCacheAdd(ctx context.Context, nodeID v1alpha1
 	}
 	writeToCatalog := !p.GetProperties().DisableNodeLevelCaching
 
-	if !tk.Metadata.Discoverable || !writeToCatalog {
-		if !writeToCatalog {
-			logger.Infof(ctx, "Node level caching is disabled. Skipping catalog write.")
-		}
+	if !writeToCatalog {
+		logger.Infof(ctx, "Node level caching is disabled. Skipping catalog write.")
 		return cacheDisabled, nil, nil
 	}
 
-	cacheVersion := "0"
-	if tk.Metadata != nil {
-		cacheVersion = tk.Metadata.DiscoveryVersion
+	if !tk.Metadata.Discoverable {
+		return t.recordLineage(ctx, tk, i, r, m), nil, nil
 	}
 
-	key := catalog.Key{
-		Identifier:     *tk.Id,
-		CacheVersion:   cacheVersion,
-		TypedInterface: *tk.Interface,
-		InputReader:    i,
+	key, err := NewCatalogKey(ctx, tk, i)
+	if err != nil {
+		return cacheDisabled, nil, err
 	}
 
 	logger.Infof(ctx, "Catalog CacheEnabled. recording execution [%s/%s/%s/%s]", tk.Id.Project, tk.Id.Domain, tk.Id.Name, tk.Id.Version)
+	putCtx := ctx
+	if executionConfig.OverwriteCache {
+		putCtx = datacatalog.WithOverwrite(putCtx)
+	}
+
+	if maxCacheAge, ok := GetTaskMaxCacheAge(ctx, tk, catalogLineage.GetConfig()); ok {
+		putCtx = datacatalog.WithMaxCacheAge(putCtx, maxCacheAge)
+	}
+
 	// ignores discovery write failures
-	s, err2 := t.catalog.Put(ctx, key, r, m)
+	s, err2 := t.catalog.Put(putCtx, key, r, m)
 	if err2 != nil {
 		t.metrics.catalogPutFailureCount.Inc(ctx)
 		logger.Errorf(ctx, "Failed to write results to catalog for Task [%v]. Error: %v", tk.GetId(), err2)
 		return catalog.NewStatus(core.CatalogCacheStatus_CACHE_PUT_FAILURE, s.GetMetadata()), nil, nil
 	}
 	t.metrics.catalogPutSuccessCount.Inc(ctx)
+	if executionConfig.OverwriteCache {
+		t.metrics.catalogOverwriteCount.Inc(ctx)
+	}
 	logger.Infof(ctx, "Successfully cached results to catalog - Task [%v]", tk.GetId())
 	return s, nil, nil
 }
+
+// NewCatalogKey builds the catalog key of a task. In the signature cache key mode, the hash of the container spec of the
+// task is appended to its cache version, which makes it part of the dataset the outputs are cached in.
+func NewCatalogKey(ctx context.Context, tk *core.TaskTemplate, i io.InputReader) (catalog.Key, error) {
+	cacheVersion := "0"
+	if tk.Metadata != nil {
+		cacheVersion = tk.Metadata.DiscoveryVersion
+	}
+
+	if catalogLineage.GetConfig().CacheKeyMode == catalogLineage.CacheKeyModeSignature {
+		specHash, err := datacatalog.GenerateTaskSpecHash(ctx, tk)
+		if err != nil {
+			return catalog.Key{}, err
+		}
+
+		cacheVersion = fmt.Sprintf("%s-%s", cacheVersion, specHash)
+	}
+
+	return catalog.Key{
+		Identifier:     *tk.Id,
+		CacheVersion:   cacheVersion,
+		TypedInterface: *tk.Interface,
+		InputReader:    i,
+	}, nil
+}
+
+// Records the outputs of a task that is not cached in the catalog, if the catalog is configured to record lineage. Lineage
+// is best effort, failures are logged and never fail the task.
+func (t *Handler) recordLineage(ctx context.Context, tk *core.TaskTemplate, i io.InputReader, r io.OutputReader,
+	m catalog.Metadata) catalog.Status {
+
+	recorder, ok := t.catalog.(catalogLineage.LineageRecorder)
+	if !ok {
+		return cacheDisabled
+	}
+
+	key, err := NewCatalogKey(ctx, tk, i)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to generate catalog key for Task [%v]. Error: %v", tk.GetId(), err)
+		return cacheDisabled
+	}
+
+	s, err := recorder.RecordLineage(ctx, key, r, m)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to record lineage in catalog for Task [%v]. Error: %v", key.Identifier, err)
+		return cacheDisabled
+	}
+
+	return s
+}
@@ -0,0 +1,40 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/stretchr/testify/assert"
+
+	catalogLineage "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+)
+
+func Test_getTaskMaxCacheAge(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &catalogLineage.Config{
+		TaskMaxCacheAge: map[string]config.Duration{"configured": {Duration: time.Hour}},
+	}
+
+	newTask := func(name string, taskConfig map[string]string) *core.TaskTemplate {
+		return &core.TaskTemplate{Id: &core.Identifier{Name: name}, Config: taskConfig}
+	}
+
+	maxCacheAge, ok := GetTaskMaxCacheAge(ctx, newTask("other", nil), cfg)
+	assert.False(t, ok)
+	assert.Zero(t, maxCacheAge)
+
+	maxCacheAge, ok = GetTaskMaxCacheAge(ctx, newTask("configured", nil), cfg)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, maxCacheAge)
+
+	maxCacheAge, ok = GetTaskMaxCacheAge(ctx, newTask("configured", map[string]string{"cache_max_age": "10m"}), cfg)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Minute, maxCacheAge)
+
+	maxCacheAge, ok = GetTaskMaxCacheAge(ctx, newTask("configured", map[string]string{"cache_max_age": "soon"}), cfg)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, maxCacheAge)
+}
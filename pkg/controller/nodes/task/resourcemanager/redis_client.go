@@ -4,8 +4,8 @@ import (
 	"context"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/resourcemanager/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/go-redis/redis"
 )
 
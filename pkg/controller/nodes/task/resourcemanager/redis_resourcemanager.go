@@ -8,7 +8,7 @@ import (
 
 	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
 	rmConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/resourcemanager/config"
-	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
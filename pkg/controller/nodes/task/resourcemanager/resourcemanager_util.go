@@ -4,7 +4,7 @@ import (
 	"context"
 
 	rmConfig "github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/resourcemanager/config"
-	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 )
 
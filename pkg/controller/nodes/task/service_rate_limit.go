@@ -0,0 +1,61 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"golang.org/x/time/rate"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Key of the task config that declares the external service the task calls. Launches of tasks that call the same
+// service are rate limited together.
+const taskConfigServiceTagKey = "service_tag"
+
+// serviceRateLimiters holds the token bucket of every service tag that has a rate limit.
+type serviceRateLimiters map[string]*rate.Limiter
+
+func newServiceRateLimiters(cfg config.ServiceRateLimitConfig) serviceRateLimiters {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	limiters := make(serviceRateLimiters, len(cfg.Limits))
+	for tag, limit := range cfg.Limits {
+		limiters[tag] = rate.NewLimiter(rate.Limit(limit.QPS), limit.Burst)
+	}
+
+	return limiters
+}
+
+func getServiceTag(tk *core.TaskTemplate) string {
+	return tk.GetConfig()[taskConfigServiceTagKey]
+}
+
+// Takes a token from the bucket of the service the task calls, if it has one. Returns true, along with the transition
+// to return, if there is none left and the launch has to wait. The launch is attempted again in the next round.
+func (t Handler) waitForServiceRateLimit(ctx context.Context, tCtx *taskExecutionContext) (handler.Transition, bool, error) {
+	if len(t.serviceRateLimiters) == 0 {
+		return handler.UnknownTransition, false, nil
+	}
+
+	tk, err := tCtx.tr.Read(ctx)
+	if err != nil {
+		return handler.UnknownTransition, false, err
+	}
+
+	tag := getServiceTag(tk)
+	limiter, found := t.serviceRateLimiters[tag]
+	if !found || limiter.Allow() {
+		return handler.UnknownTransition, false, nil
+	}
+
+	t.metrics.serviceRateLimited.Inc(ctx)
+	reason := fmt.Sprintf("Waiting for the launch rate limit of service [%v]", tag)
+	logger.Infof(ctx, "%v", reason)
+	return handler.DoTransition(handler.TransitionTypeEphemeral, handler.PhaseInfoQueued(reason)), true, nil
+}
@@ -0,0 +1,74 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	pluginCoreMocks "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core/mocks"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+)
+
+func TestHandler_waitForServiceRateLimit(t *testing.T) {
+	ctx := context.TODO()
+	newTaskExecutionContext := func(serviceTag string) *taskExecutionContext {
+		tr := &pluginCoreMocks.TaskReader{}
+		tr.OnReadMatch(mock.Anything).Return(&core.TaskTemplate{Config: map[string]string{taskConfigServiceTagKey: serviceTag}}, nil)
+		return &taskExecutionContext{tr: tr}
+	}
+
+	newHandler := func(cfg config.ServiceRateLimitConfig) Handler {
+		return Handler{
+			serviceRateLimiters: newServiceRateLimiters(cfg),
+			metrics: &metrics{
+				serviceRateLimited: labeled.NewCounter("service_rate_limited", "", promutils.NewTestScope()),
+			},
+		}
+	}
+
+	cfg := config.ServiceRateLimitConfig{
+		Enabled: true,
+		Limits:  map[string]config.RateLimit{"db": {QPS: 0.001, Burst: 2}},
+	}
+
+	t.Run("limited", func(t *testing.T) {
+		h := newHandler(cfg)
+		for i := 0; i < 2; i++ {
+			_, waiting, err := h.waitForServiceRateLimit(ctx, newTaskExecutionContext("db"))
+			assert.NoError(t, err)
+			assert.False(t, waiting)
+		}
+
+		trns, waiting, err := h.waitForServiceRateLimit(ctx, newTaskExecutionContext("db"))
+		assert.NoError(t, err)
+		assert.True(t, waiting)
+		assert.Equal(t, handler.EPhaseQueued, trns.Info().GetPhase())
+		assert.Equal(t, handler.TransitionTypeEphemeral, trns.Type())
+	})
+
+	t.Run("other service", func(t *testing.T) {
+		h := newHandler(cfg)
+		for i := 0; i < 5; i++ {
+			_, waiting, err := h.waitForServiceRateLimit(ctx, newTaskExecutionContext("api"))
+			assert.NoError(t, err)
+			assert.False(t, waiting)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		h := newHandler(disabled)
+		for i := 0; i < 5; i++ {
+			_, waiting, err := h.waitForServiceRateLimit(ctx, newTaskExecutionContext("db"))
+			assert.NoError(t, err)
+			assert.False(t, waiting)
+		}
+	})
+}
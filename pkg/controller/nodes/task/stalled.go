@@ -0,0 +1,40 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+)
+
+const (
+	stalledReasonResourceQuota = "ResourceQuotaWait"
+	stalledReasonImagePull     = "ImagePullBackOff"
+	// Prefix of the reason surfaced by the K8s event watcher when the scheduler cannot place the pod of a task.
+	stalledReasonUnschedulable = "FailedScheduling"
+)
+
+// Container waiting reasons reported by the kubelet while it is unable to pull the image of a task.
+var imagePullWaitingReasons = []string{"ImagePullBackOff", "ErrImagePull"}
+
+// Returns a non-empty reason if the plugin reports that the task is waiting on something that propeller cannot act on,
+// e.g. an exhausted resource quota or an image that cannot be pulled. Such tasks do not make progress by being
+// re-evaluated more often.
+func getStalledReason(pInfo pluginCore.PhaseInfo) string {
+	switch pInfo.Phase() {
+	case pluginCore.PhaseWaitingForResources:
+		return fmt.Sprintf("%s: %s", stalledReasonResourceQuota, pInfo.Reason())
+	case pluginCore.PhaseQueued, pluginCore.PhaseInitializing:
+		if strings.HasPrefix(pInfo.Reason(), stalledReasonUnschedulable) {
+			return pInfo.Reason()
+		}
+
+		for _, r := range imagePullWaitingReasons {
+			if strings.Contains(pInfo.Reason(), r) {
+				return fmt.Sprintf("%s: %s", stalledReasonImagePull, pInfo.Reason())
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,39 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStalledReason(t *testing.T) {
+	now := time.Now()
+
+	t.Run("quota", func(t *testing.T) {
+		reason := getStalledReason(pluginCore.PhaseInfoWaitingForResources(now, pluginCore.DefaultPhaseVersion, "quota exceeded"))
+		assert.Equal(t, "ResourceQuotaWait: quota exceeded", reason)
+	})
+
+	t.Run("image-pull", func(t *testing.T) {
+		reason := getStalledReason(pluginCore.PhaseInfoInitializing(now, pluginCore.DefaultPhaseVersion,
+			"[ContainersNotReady|ErrImagePull]: Back-off pulling image", nil))
+		assert.Equal(t, "ImagePullBackOff: [ContainersNotReady|ErrImagePull]: Back-off pulling image", reason)
+
+		reason = getStalledReason(pluginCore.PhaseInfoQueued(now, pluginCore.DefaultPhaseVersion, "ImagePullBackOff"))
+		assert.Equal(t, "ImagePullBackOff: ImagePullBackOff", reason)
+	})
+
+	t.Run("unschedulable", func(t *testing.T) {
+		reason := getStalledReason(pluginCore.PhaseInfoQueued(now, 1, "FailedScheduling: 0/3 nodes are available"))
+		assert.Equal(t, "FailedScheduling: 0/3 nodes are available", reason)
+	})
+
+	t.Run("progressing", func(t *testing.T) {
+		assert.Empty(t, getStalledReason(pluginCore.PhaseInfoQueued(now, pluginCore.DefaultPhaseVersion, "Scheduling")))
+		assert.Empty(t, getStalledReason(pluginCore.PhaseInfoInitializing(now, pluginCore.DefaultPhaseVersion,
+			"[ContainersNotReady|ContainerCreating]: pulling image", nil)))
+		assert.Empty(t, getStalledReason(pluginCore.PhaseInfoRunning(pluginCore.DefaultPhaseVersion, nil)))
+	})
+}
@@ -11,8 +11,6 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/resourcemanager"
 
-	"github.com/flyteorg/flytestdlib/logger"
-
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 
 	pluginCatalog "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/catalog"
@@ -22,6 +20,9 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytepropeller/pkg/utils"
 )
 
@@ -50,6 +51,7 @@ type taskExecutionMetadata struct {
 	taskExecID  taskExecutionID
 	o           pluginCore.TaskOverrides
 	maxAttempts uint32
+	annotations map[string]string
 }
 
 func (t taskExecutionMetadata) GetTaskExecutionID() pluginCore.TaskExecutionID {
@@ -64,6 +66,14 @@ func (t taskExecutionMetadata) GetMaxAttempts() uint32 {
 	return t.maxAttempts
 }
 
+func (t taskExecutionMetadata) GetAnnotations() map[string]string {
+	if t.annotations != nil {
+		return t.annotations
+	}
+
+	return t.NodeExecutionMetadata.GetAnnotations()
+}
+
 type taskExecutionContext struct {
 	handler.NodeExecutionContext
 	tm  taskExecutionMetadata
@@ -78,7 +88,7 @@ type taskExecutionContext struct {
 
 func (t *taskExecutionContext) TaskRefreshIndicator() pluginCore.SignalAsync {
 	return func(ctx context.Context) {
-		err := t.NodeExecutionContext.EnqueueOwnerFunc()
+		err := t.NodeExecutionContext.EnqueueOwnerFunc()()
 		if err != nil {
 			logger.Errorf(ctx, "Failed to enqueue owner for Task [%v] and Owner [%v]. Error: %v",
 				t.TaskExecutionMetadata().GetTaskExecutionID(),
@@ -124,6 +134,43 @@ func (t taskExecutionContext) SecretManager() pluginCore.SecretManager {
 	return t.sm
 }
 
+// Key of the task config that opts the task in to resuming from the raw outputs of its previous attempt, e.g. the files
+// a multi-file upload or a training job already wrote, rather than starting from scratch when it is retried.
+const taskConfigResumableKey = "resumable"
+
+// Returns the raw output prefix of the previous attempt of resumable tasks, empty for the first attempt and for tasks
+// that are not resumable.
+func previousAttemptRawOutputPrefix(ctx context.Context, nCtx handler.NodeExecutionContext, length int,
+	nodeUniqueID string, attempt uint32) (string, error) {
+
+	if attempt == 0 {
+		return "", nil
+	}
+
+	tk, err := nCtx.TaskReader().Read(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if resumable, err := strconv.ParseBool(tk.GetConfig()[taskConfigResumableKey]); err != nil || !resumable {
+		return "", nil
+	}
+
+	uniqueID, err := utils.FixedLengthUniqueIDForParts(length, nCtx.NodeExecutionMetadata().GetOwnerID().Name, nodeUniqueID,
+		strconv.Itoa(int(attempt-1)))
+	if err != nil {
+		return "", err
+	}
+
+	sandbox, err := ioutils.NewShardedRawOutputPath(ctx, nCtx.OutputShardSelector(), nCtx.RawOutputPrefix(), uniqueID,
+		nCtx.DataStore())
+	if err != nil {
+		return "", err
+	}
+
+	return string(sandbox.GetRawOutputPrefix()), nil
+}
+
 func (t *Handler) newTaskExecutionContext(ctx context.Context, nCtx handler.NodeExecutionContext, plugin pluginCore.Plugin) (*taskExecutionContext, error) {
 	id := GetTaskExecutionIdentifier(nCtx)
 
@@ -173,6 +220,23 @@ func (t *Handler) newTaskExecutionContext(ctx context.Context, nCtx handler.Node
 		return nil, err
 	}
 
+	var annotations map[string]string
+	if config.GetConfig().ScratchVolumeConfig.Enabled {
+		annotations = k8s.WithScratchVolumeAnnotations(nCtx.NodeExecutionMetadata().GetAnnotations(),
+			nCtx.ExecutionContext().GetExecutionConfig().ScratchVolume)
+	}
+
+	previousRawOutputPrefix, err := previousAttemptRawOutputPrefix(ctx, nCtx, length, currentNodeUniqueID, id.RetryAttempt)
+	if err != nil {
+		return nil, errors.Wrapf(errors.StorageError, nCtx.NodeID(), err, "failed to find raw outputs of previous attempt")
+	} else if len(previousRawOutputPrefix) > 0 {
+		if annotations == nil {
+			annotations = nCtx.NodeExecutionMetadata().GetAnnotations()
+		}
+
+		annotations = k8s.WithPreviousAttemptAnnotations(annotations, previousRawOutputPrefix)
+	}
+
 	return &taskExecutionContext{
 		NodeExecutionContext: nCtx,
 		tm: taskExecutionMetadata{
@@ -180,6 +244,7 @@ func (t *Handler) newTaskExecutionContext(ctx context.Context, nCtx handler.Node
 			taskExecID:            taskExecutionID{execName: uniqueID, id: id},
 			o:                     nCtx.Node(),
 			maxAttempts:           maxAttempts,
+			annotations:           annotations,
 		},
 		rm: resourcemanager.GetTaskResourceManager(
 			t.resourceManager, resourceNamespacePrefix, id),
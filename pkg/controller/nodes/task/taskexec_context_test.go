@@ -20,6 +20,7 @@ import (
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/storage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	v12 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -29,6 +30,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	nodeMocks "github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler/mocks"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/codex"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/k8s"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/secretmanager"
 )
 
@@ -59,6 +61,8 @@ func TestHandler_newTaskExecutionContext(t *testing.T) {
 	taskID := &core.Identifier{}
 	tr := &nodeMocks.TaskReader{}
 	tr.OnGetTaskID().Return(taskID)
+	taskTemplate := &core.TaskTemplate{}
+	tr.On("Read", mock.Anything).Return(func(context.Context) *core.TaskTemplate { return taskTemplate }, nil)
 
 	ns := &flyteMocks.ExecutableNodeStatus{}
 	ns.OnGetDataDir().Return(storage.DataReference("data-dir"))
@@ -180,4 +184,16 @@ func TestHandler_newTaskExecutionContext(t *testing.T) {
 	})
 	anotherTaskExecCtx, _ := tk.newTaskExecutionContext(context.TODO(), nCtx, anotherPlugin)
 	assert.Equal(t, anotherTaskExecCtx.TaskExecutionMetadata().GetTaskExecutionID().GetGeneratedName(), "fpmmhh6q")
+
+	assert.NotContains(t, got.TaskExecutionMetadata().GetAnnotations(), k8s.PreviousAttemptRawOutputPrefixAnnotation)
+	t.Run("resumable", func(t *testing.T) {
+		taskTemplate.Config = map[string]string{taskConfigResumableKey: "true"}
+		defer func() { taskTemplate.Config = nil }()
+
+		got, err := tk.newTaskExecutionContext(context.TODO(), nCtx, p)
+		assert.NoError(t, err)
+		assert.Equal(t, "s3://sandbox/x/name-n1-0",
+			got.TaskExecutionMetadata().GetAnnotations()[k8s.PreviousAttemptRawOutputPrefixAnnotation])
+		assert.Equal(t, "s3://sandbox/x/name-n1-1", string(got.OutputWriter().GetRawOutputPrefix()))
+	})
 }
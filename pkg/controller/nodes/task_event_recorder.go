@@ -5,10 +5,11 @@ import (
 
 	"github.com/flyteorg/flyteidl/clients/go/events"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/pkg/errors"
 
 	eventsErr "github.com/flyteorg/flyteidl/clients/go/events/errors"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 type taskEventRecorder struct {
@@ -0,0 +1,118 @@
+package nodes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type taskTemplateStoreMetrics struct {
+	CacheHit         prometheus.Counter
+	CacheMiss        prometheus.Counter
+	ChecksumMismatch prometheus.Counter
+}
+
+// TaskTemplateStore reads the task templates that were offloaded from workflows. Templates are verified against the
+// checksum of their reference and cached by URI and checksum, so that each is only read once rather than in every round
+// of every node that runs it.
+type TaskTemplateStore struct {
+	store   *storage.DataStore
+	cache   *lru.Cache
+	metrics taskTemplateStoreMetrics
+}
+
+func (s *TaskTemplateStore) Get(ctx context.Context, ref *v1alpha1.TaskReference) (*core.TaskTemplate, error) {
+	key := fmt.Sprintf("%v@%v", ref.URI, ref.Checksum)
+	if tmpl, ok := s.cache.Get(key); ok {
+		s.metrics.CacheHit.Inc()
+		return tmpl.(*core.TaskTemplate), nil
+	}
+
+	s.metrics.CacheMiss.Inc()
+	reader, err := s.store.ReadRaw(ctx, ref.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Debugf(ctx, "Failed to close task template reader. Error: %v", closeErr)
+		}
+	}()
+
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(raw)
+	if checksum := hex.EncodeToString(sum[:]); checksum != ref.Checksum {
+		s.metrics.ChecksumMismatch.Inc()
+		return nil, fmt.Errorf("checksum [%v] of task template [%v] does not match the expected [%v]", checksum, ref.URI, ref.Checksum)
+	}
+
+	tmpl := &core.TaskTemplate{}
+	if err := proto.Unmarshal(raw, tmpl); err != nil {
+		return nil, err
+	}
+
+	s.cache.Add(key, tmpl)
+	return tmpl, nil
+}
+
+func NewTaskTemplateStore(store *storage.DataStore, cacheSize int, scope promutils.Scope) (*TaskTemplateStore, error) {
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaskTemplateStore{
+		store: store,
+		cache: cache,
+		metrics: taskTemplateStoreMetrics{
+			CacheHit:         scope.MustNewCounter("cache_hit", "Number of task templates read from the cache"),
+			CacheMiss:        scope.MustNewCounter("cache_miss", "Number of task templates read from the datastore"),
+			ChecksumMismatch: scope.MustNewCounter("checksum_mismatch", "Number of task templates that did not match the checksum of their reference"),
+		},
+	}, nil
+}
+
+// remoteTaskReader reads a task template offloaded from the workflow, its id and type are known without reading it.
+type remoteTaskReader struct {
+	nodeID v1alpha1.NodeID
+	ref    *v1alpha1.TaskReference
+	store  *TaskTemplateStore
+}
+
+func (t remoteTaskReader) GetTaskType() v1alpha1.TaskType {
+	return t.ref.TaskType()
+}
+
+func (t remoteTaskReader) GetTaskID() *core.Identifier {
+	return t.ref.CoreTask().Id
+}
+
+func (t remoteTaskReader) Read(ctx context.Context) (*core.TaskTemplate, error) {
+	tmpl, err := t.store.Get(ctx, t.ref)
+	if err != nil {
+		return nil, errors.Wrapf(errors.StorageError, t.nodeID, err, "failed to read task template [%v]", t.ref.URI)
+	}
+
+	return tmpl, nil
+}
+
+var _ handler.TaskReader = remoteTaskReader{}
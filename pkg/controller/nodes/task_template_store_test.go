@@ -0,0 +1,84 @@
+package nodes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+)
+
+func TestTaskTemplateStore_Get(t *testing.T) {
+	ctx := context.TODO()
+	dataStore, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	tmpl := &core.TaskTemplate{
+		Id:   &core.Identifier{ResourceType: core.ResourceType_TASK, Name: "task"},
+		Type: "container",
+	}
+	raw, err := proto.Marshal(tmpl)
+	assert.NoError(t, err)
+	uri := storage.DataReference("s3://bucket/tasks/task.pb")
+	assert.NoError(t, dataStore.WriteRaw(ctx, uri, int64(len(raw)), storage.Options{}, bytes.NewReader(raw)))
+	sum := sha256.Sum256(raw)
+
+	ref := &v1alpha1.TaskReference{
+		ID:       &v1alpha1.Identifier{Identifier: tmpl.Id},
+		Type:     tmpl.Type,
+		URI:      uri,
+		Checksum: hex.EncodeToString(sum[:]),
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		s, err := NewTaskTemplateStore(dataStore, 10, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			actual, err := s.Get(ctx, ref)
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tmpl, actual))
+		}
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.CacheMiss))
+		assert.Equal(t, float64(2), testutil.ToFloat64(s.metrics.CacheHit))
+	})
+
+	t.Run("checksum-mismatch", func(t *testing.T) {
+		s, err := NewTaskTemplateStore(dataStore, 10, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		tampered := ref.DeepCopy()
+		tampered.Checksum = "abc"
+		_, err = s.Get(ctx, tampered)
+		assert.Error(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.ChecksumMismatch))
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		s, err := NewTaskTemplateStore(dataStore, 10, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		tr := remoteTaskReader{nodeID: "n1", ref: ref, store: s}
+		assert.Equal(t, "container", tr.GetTaskType())
+		assert.Equal(t, "task", tr.GetTaskID().Name)
+		actual, err := tr.Read(ctx)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(tmpl, actual))
+
+		missing := ref.DeepCopy()
+		missing.URI = "s3://bucket/tasks/missing.pb"
+		_, err = remoteTaskReader{nodeID: "n1", ref: missing, store: s}.Read(ctx)
+		assert.True(t, errors.Matches(err, errors.StorageError))
+	})
+}
@@ -0,0 +1,103 @@
+package nodes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// collectNodesToTerminate walks the DAG downstream of the node, through the nodes whose phase walkThrough accepts, and
+// returns the nodes that have to be aborted or finalized by their handlers, along with the errors met on the way. Every
+// node is returned once, even if it is downstream of several nodes. The statuses of the nodes are all resolved here,
+// serially, so that the nodes can then be terminated concurrently.
+func collectNodesToTerminate(ctx context.Context, dag executors.DAGStructure, nl executors.NodeLookup,
+	from v1alpha1.ExecutableNode, walkThrough func(v1alpha1.NodePhase) bool) ([]v1alpha1.ExecutableNode, []error) {
+
+	var nodes []v1alpha1.ExecutableNode
+	var errs []error
+	visited := map[v1alpha1.NodeID]bool{from.GetID(): true}
+	queue := []v1alpha1.ExecutableNode{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		phase := nl.GetNodeExecutionStatus(ctx, node.GetID()).GetPhase()
+		if phase == v1alpha1.NodePhaseNotYetStarted {
+			continue
+		}
+
+		if canHandleNode(phase) {
+			nodes = append(nodes, node)
+			continue
+		}
+
+		if !walkThrough(phase) {
+			logger.Debugf(ctx, "Not terminating node [%v] in state [%v], nor the nodes downstream of it", node.GetID(), phase)
+			continue
+		}
+
+		downstreamNodes, err := dag.FromNode(node.GetID())
+		if err != nil {
+			logger.Debugf(ctx, "Error when retrieving downstream nodes. Error [%v]", err)
+			continue
+		}
+
+		for _, d := range downstreamNodes {
+			if visited[d] {
+				continue
+			}
+
+			visited[d] = true
+			downstreamNode, ok := nl.GetNode(d)
+			if !ok {
+				errs = append(errs, errors.Errorf(errors.BadSpecificationError, node.GetID(), "Unable to find Downstream Node [%v]", d))
+				continue
+			}
+
+			queue = append(queue, downstreamNode)
+		}
+	}
+
+	return nodes, errs
+}
+
+// terminateNodes aborts or finalizes the nodes with terminate, up to maxParallelTerminations of them at a time, and
+// returns the errors of all the nodes that failed to terminate.
+func (c *nodeExecutor) terminateNodes(ctx context.Context, nodes []v1alpha1.ExecutableNode,
+	terminate func(ctx context.Context, n v1alpha1.ExecutableNode) error) []error {
+
+	nodeErrs := make([]error, len(nodes))
+	if c.maxParallelTerminations <= 1 || len(nodes) <= 1 {
+		for i, n := range nodes {
+			nodeErrs[i] = terminate(ctx, n)
+		}
+	} else {
+		sem := make(chan struct{}, c.maxParallelTerminations)
+		wg := sync.WaitGroup{}
+		for i, n := range nodes {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(i int, n v1alpha1.ExecutableNode) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				nodeErrs[i] = terminate(ctx, n)
+			}(i, n)
+		}
+
+		wg.Wait()
+	}
+
+	var errs []error
+	for i, err := range nodeErrs {
+		if err != nil {
+			logger.Infof(ctx, "Failed to terminate node [%v]. Error: %v", nodes[i].GetID(), err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
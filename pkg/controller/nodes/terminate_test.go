@@ -0,0 +1,128 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1/mocks"
+	mocks4 "github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+)
+
+func newTerminateTestDAG(phases map[v1alpha1.NodeID]v1alpha1.NodePhase,
+	edges map[v1alpha1.NodeID][]v1alpha1.NodeID) (*mocks4.DAGStructure, *mocks4.NodeLookup) {
+
+	dag := &mocks4.DAGStructure{}
+	nl := &mocks4.NodeLookup{}
+	for id, phase := range phases {
+		n := &mocks.ExecutableNode{}
+		n.OnGetID().Return(id)
+		ns := &mocks.ExecutableNodeStatus{}
+		ns.OnGetPhase().Return(phase)
+		nl.OnGetNode(id).Return(n, true)
+		nl.OnGetNodeExecutionStatusMatch(mock.Anything, id).Return(ns)
+		dag.OnFromNode(id).Return(edges[id], nil)
+	}
+
+	nl.OnGetNode("missing").Return(nil, false)
+	return dag, nl
+}
+
+func nodeIDs(nodes []v1alpha1.ExecutableNode) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.GetID())
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+func TestCollectNodesToTerminate(t *testing.T) {
+	ctx := context.TODO()
+	dag, nl := newTerminateTestDAG(map[v1alpha1.NodeID]v1alpha1.NodePhase{
+		"start":       v1alpha1.NodePhaseSucceeded,
+		"running":     v1alpha1.NodePhaseRunning,
+		"succeeded":   v1alpha1.NodePhaseSucceeded,
+		"skipped":     v1alpha1.NodePhaseSkipped,
+		"queued":      v1alpha1.NodePhaseQueued,
+		"failed":      v1alpha1.NodePhaseFailed,
+		"after-fail":  v1alpha1.NodePhaseRunning,
+		"not-started": v1alpha1.NodePhaseNotYetStarted,
+		"after-start": v1alpha1.NodePhaseRunning,
+	}, map[v1alpha1.NodeID][]v1alpha1.NodeID{
+		"start":       {"running", "succeeded", "skipped", "failed", "not-started"},
+		"succeeded":   {"queued", "running"},
+		"skipped":     {"queued"},
+		"failed":      {"after-fail", "missing"},
+		"not-started": {"after-start"},
+	})
+	start, _ := nl.GetNode("start")
+
+	t.Run("abort", func(t *testing.T) {
+		nodes, errs := collectNodesToTerminate(ctx, dag, nl, start, isAbortWalkedThrough)
+		assert.Empty(t, errs)
+		// Nodes reachable through several nodes are only terminated once.
+		assert.Equal(t, []string{"queued", "running"}, nodeIDs(nodes))
+	})
+
+	t.Run("finalize", func(t *testing.T) {
+		nodes, errs := collectNodesToTerminate(ctx, dag, nl, start, func(v1alpha1.NodePhase) bool { return true })
+		assert.Len(t, errs, 1)
+		assert.Equal(t, []string{"after-fail", "queued", "running"}, nodeIDs(nodes))
+	})
+}
+
+func TestNodeExecutor_TerminateNodes(t *testing.T) {
+	ctx := context.TODO()
+	nodes := make([]v1alpha1.ExecutableNode, 0, 20)
+	for i := 0; i < 20; i++ {
+		n := &mocks.ExecutableNode{}
+		n.OnGetID().Return(fmt.Sprintf("n%d", i))
+		nodes = append(nodes, n)
+	}
+
+	for _, parallelism := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("parallelism-%d", parallelism), func(t *testing.T) {
+			exec := nodeExecutor{maxParallelTerminations: parallelism}
+			lock := sync.Mutex{}
+			terminated := map[string]bool{}
+			running, maxRunning := 0, 0
+			errs := exec.terminateNodes(ctx, nodes, func(ctx context.Context, n v1alpha1.ExecutableNode) error {
+				lock.Lock()
+				terminated[n.GetID()] = true
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				lock.Unlock()
+
+				defer func() {
+					lock.Lock()
+					running--
+					lock.Unlock()
+				}()
+
+				if n.GetID() == "n3" || n.GetID() == "n17" {
+					return fmt.Errorf("failed to terminate [%v]", n.GetID())
+				}
+
+				return nil
+			})
+
+			assert.Len(t, terminated, 20)
+			assert.Len(t, errs, 2)
+			if parallelism <= 1 {
+				assert.Equal(t, 1, maxRunning)
+			} else {
+				assert.LessOrEqual(t, maxRunning, parallelism)
+			}
+		})
+	}
+}
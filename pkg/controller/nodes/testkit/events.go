@@ -0,0 +1,87 @@
+package testkit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/golang/protobuf/proto"
+)
+
+// RecordingEventSink is an in-memory events.EventSink that keeps every event it is sent so that tests can assert on
+// the sequence of workflow, node and task events a handler emitted. It is safe for concurrent use.
+type RecordingEventSink struct {
+	lock   sync.Mutex
+	events []proto.Message
+	// If set, Sink returns this error instead of recording the event.
+	SinkErr error
+}
+
+func (r *RecordingEventSink) Sink(_ context.Context, message proto.Message) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.SinkErr != nil {
+		return r.SinkErr
+	}
+
+	r.events = append(r.events, proto.Clone(message))
+	return nil
+}
+
+func (r *RecordingEventSink) Close() error {
+	return nil
+}
+
+// Events returns all recorded events in the order they were sunk.
+func (r *RecordingEventSink) Events() []proto.Message {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]proto.Message(nil), r.events...)
+}
+
+// TaskEvents returns the recorded task execution events in the order they were sunk.
+func (r *RecordingEventSink) TaskEvents() []*event.TaskExecutionEvent {
+	var res []*event.TaskExecutionEvent
+	for _, e := range r.Events() {
+		if ev, ok := e.(*event.TaskExecutionEvent); ok {
+			res = append(res, ev)
+		}
+	}
+
+	return res
+}
+
+// NodeEvents returns the recorded node execution events in the order they were sunk.
+func (r *RecordingEventSink) NodeEvents() []*event.NodeExecutionEvent {
+	var res []*event.NodeExecutionEvent
+	for _, e := range r.Events() {
+		if ev, ok := e.(*event.NodeExecutionEvent); ok {
+			res = append(res, ev)
+		}
+	}
+
+	return res
+}
+
+// WorkflowEvents returns the recorded workflow execution events in the order they were sunk.
+func (r *RecordingEventSink) WorkflowEvents() []*event.WorkflowExecutionEvent {
+	var res []*event.WorkflowExecutionEvent
+	for _, e := range r.Events() {
+		if ev, ok := e.(*event.WorkflowExecutionEvent); ok {
+			res = append(res, ev)
+		}
+	}
+
+	return res
+}
+
+// Reset drops all recorded events.
+func (r *RecordingEventSink) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = nil
+}
+
+func NewRecordingEventSink() *RecordingEventSink {
+	return &RecordingEventSink{}
+}
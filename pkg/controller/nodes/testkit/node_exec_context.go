@@ -0,0 +1,388 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/io"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+)
+
+const (
+	DefaultProject   = "project"
+	DefaultDomain    = "domain"
+	DefaultNamespace = "namespace"
+	DefaultName      = "name"
+	DefaultWorkflow  = "wf"
+
+	DefaultDataDir         storage.DataReference = "s3://bucket/metadata"
+	DefaultRawOutputPrefix storage.DataReference = "s3://bucket/raw"
+
+	defaultMaxDatasetSizeBytes = 1024 * 1024
+)
+
+// NodeExecutionContextBuilder builds a handler.NodeExecutionContext from real v1alpha1 types. Anything that is not
+// explicitly configured gets a sensible default: an in-memory datastore, a recording event sink, a workflow that only
+// contains the node under test and an empty node state.
+type NodeExecutionContextBuilder struct {
+	node                *v1alpha1.NodeSpec
+	wf                  *v1alpha1.FlyteWorkflow
+	store               *storage.DataStore
+	sink                *RecordingEventSink
+	inputs              *core.LiteralMap
+	task                *core.TaskTemplate
+	state               *NodeState
+	rawOutputPrefix     storage.DataReference
+	maxDatasetSizeBytes int64
+	interruptible       bool
+	parentInfo          executors.ImmutableParentInfo
+}
+
+var _ handler.NodeExecutionContext = &NodeExecutionContext{}
+
+// NodeExecutionContext is the handler.NodeExecutionContext produced by the builder. Besides implementing the interface
+// it exposes the fakes backing it so that tests can assert on side effects.
+type NodeExecutionContext struct {
+	node                v1alpha1.ExecutableNode
+	nodeStatus          v1alpha1.ExecutableNodeStatus
+	store               *storage.DataStore
+	inputs              io.InputReader
+	recorder            events.TaskEventRecorder
+	tr                  handler.TaskReader
+	md                  nodeExecMetadata
+	rawOutputPrefix     storage.DataReference
+	maxDatasetSizeBytes int64
+	nl                  executors.NodeLookup
+	ic                  executors.ExecutionContext
+	enqueued            int32
+
+	// The workflow the node belongs to.
+	Workflow *v1alpha1.FlyteWorkflow
+	// The node state handlers read and write through NodeStateReader and NodeStateWriter.
+	State *NodeState
+	// Every event recorded through EventsRecorder.
+	Sink *RecordingEventSink
+}
+
+func (n *NodeExecutionContext) RawOutputPrefix() storage.DataReference {
+	return n.rawOutputPrefix
+}
+
+func (n *NodeExecutionContext) OutputShardSelector() ioutils.ShardSelector {
+	return ioutils.NewConstantShardSelector([]string{"x"})
+}
+
+func (n *NodeExecutionContext) DataStore() *storage.DataStore {
+	return n.store
+}
+
+func (n *NodeExecutionContext) InputReader() io.InputReader {
+	return n.inputs
+}
+
+func (n *NodeExecutionContext) EventsRecorder() events.TaskEventRecorder {
+	return n.recorder
+}
+
+func (n *NodeExecutionContext) NodeID() v1alpha1.NodeID {
+	return n.node.GetID()
+}
+
+func (n *NodeExecutionContext) Node() v1alpha1.ExecutableNode {
+	return n.node
+}
+
+func (n *NodeExecutionContext) CurrentAttempt() uint32 {
+	return n.nodeStatus.GetAttempts()
+}
+
+func (n *NodeExecutionContext) TaskReader() handler.TaskReader {
+	return n.tr
+}
+
+func (n *NodeExecutionContext) NodeStateReader() handler.NodeStateReader {
+	return n.State
+}
+
+func (n *NodeExecutionContext) NodeStateWriter() handler.NodeStateWriter {
+	return n.State
+}
+
+func (n *NodeExecutionContext) NodeExecutionMetadata() handler.NodeExecutionMetadata {
+	return n.md
+}
+
+func (n *NodeExecutionContext) MaxDatasetSizeBytes() int64 {
+	return n.maxDatasetSizeBytes
+}
+
+func (n *NodeExecutionContext) EnqueueOwnerFunc() func() error {
+	return func() error {
+		atomic.AddInt32(&n.enqueued, 1)
+		return nil
+	}
+}
+
+func (n *NodeExecutionContext) ContextualNodeLookup() executors.NodeLookup {
+	return n.nl
+}
+
+func (n *NodeExecutionContext) ExecutionContext() executors.ExecutionContext {
+	return n.ic
+}
+
+func (n *NodeExecutionContext) NodeStatus() v1alpha1.ExecutableNodeStatus {
+	return n.nodeStatus
+}
+
+// EnqueueCount returns how many times the handler asked for the owning workflow to be enqueued.
+func (n *NodeExecutionContext) EnqueueCount() int {
+	return int(atomic.LoadInt32(&n.enqueued))
+}
+
+// OutputsFile returns where the node is expected to write its outputs for the current attempt.
+func (n *NodeExecutionContext) OutputsFile() storage.DataReference {
+	return v1alpha1.GetOutputsFile(n.nodeStatus.GetOutputDir())
+}
+
+// WithWorkflow sets the workflow the node belongs to. The node is added to the workflow if it is not already part of
+// it.
+func (b *NodeExecutionContextBuilder) WithWorkflow(wf *v1alpha1.FlyteWorkflow) *NodeExecutionContextBuilder {
+	b.wf = wf
+	return b
+}
+
+func (b *NodeExecutionContextBuilder) WithDataStore(store *storage.DataStore) *NodeExecutionContextBuilder {
+	b.store = store
+	return b
+}
+
+func (b *NodeExecutionContextBuilder) WithEventSink(sink *RecordingEventSink) *NodeExecutionContextBuilder {
+	b.sink = sink
+	return b
+}
+
+// WithInputs sets the inputs written to the node's data dir when the context is built. Defaults to no inputs.
+func (b *NodeExecutionContextBuilder) WithInputs(inputs *core.LiteralMap) *NodeExecutionContextBuilder {
+	b.inputs = inputs
+	return b
+}
+
+// WithTask sets the task template returned by the TaskReader. Task nodes that reference a task in the workflow do not
+// need it.
+func (b *NodeExecutionContextBuilder) WithTask(task *core.TaskTemplate) *NodeExecutionContextBuilder {
+	b.task = task
+	return b
+}
+
+// WithNodeState seeds the state that the handler observes through NodeStateReader.
+func (b *NodeExecutionContextBuilder) WithNodeState(state *NodeState) *NodeExecutionContextBuilder {
+	b.state = state
+	return b
+}
+
+func (b *NodeExecutionContextBuilder) WithRawOutputPrefix(prefix storage.DataReference) *NodeExecutionContextBuilder {
+	b.rawOutputPrefix = prefix
+	return b
+}
+
+func (b *NodeExecutionContextBuilder) WithMaxDatasetSizeBytes(size int64) *NodeExecutionContextBuilder {
+	b.maxDatasetSizeBytes = size
+	return b
+}
+
+func (b *NodeExecutionContextBuilder) WithInterruptible(interruptible bool) *NodeExecutionContextBuilder {
+	b.interruptible = interruptible
+	return b
+}
+
+// WithParentInfo makes the node behave as if it ran inside a parent node, e.g. a dynamic or sub-workflow node.
+func (b *NodeExecutionContextBuilder) WithParentInfo(parentInfo executors.ImmutableParentInfo) *NodeExecutionContextBuilder {
+	b.parentInfo = parentInfo
+	return b
+}
+
+func (b *NodeExecutionContextBuilder) defaultWorkflow() *v1alpha1.FlyteWorkflow {
+	return &v1alpha1.FlyteWorkflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              DefaultName,
+			Namespace:         DefaultNamespace,
+			CreationTimestamp: metav1.NewTime(DefaultStartTime),
+		},
+		ExecutionID: v1alpha1.WorkflowExecutionIdentifier{
+			WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{
+				Project: DefaultProject,
+				Domain:  DefaultDomain,
+				Name:    DefaultName,
+			},
+		},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{
+			ID:    DefaultWorkflow,
+			Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{},
+		},
+		Status: v1alpha1.WorkflowStatus{
+			DataDir: DefaultDataDir,
+		},
+	}
+}
+
+// Build wires everything together. The node status is taken from the workflow, so any status set on it beforehand
+// (phase, attempts, ...) is visible to the handler.
+func (b *NodeExecutionContextBuilder) Build(ctx context.Context) (*NodeExecutionContext, error) {
+	store := b.store
+	if store == nil {
+		var err error
+		if store, err = NewInMemoryDataStore(); err != nil {
+			return nil, err
+		}
+	}
+
+	sink := b.sink
+	if sink == nil {
+		sink = NewRecordingEventSink()
+	}
+
+	state := b.state
+	if state == nil {
+		state = &NodeState{}
+	}
+
+	wf := b.wf
+	if wf == nil {
+		wf = b.defaultWorkflow()
+	}
+
+	if wf.WorkflowSpec == nil {
+		wf.WorkflowSpec = &v1alpha1.WorkflowSpec{ID: DefaultWorkflow}
+	}
+
+	if wf.Nodes == nil {
+		wf.Nodes = map[v1alpha1.NodeID]*v1alpha1.NodeSpec{}
+	}
+
+	if _, ok := wf.Nodes[b.node.GetID()]; !ok {
+		wf.Nodes[b.node.GetID()] = b.node
+	}
+
+	if len(wf.Status.DataDir) == 0 {
+		wf.Status.DataDir = DefaultDataDir
+	}
+
+	wf.DataReferenceConstructor = store
+	wf.Status.DataReferenceConstructor = store
+
+	nodeStatus := wf.GetNodeExecutionStatus(ctx, b.node.GetID())
+	inputs := b.inputs
+	if inputs == nil {
+		inputs = &core.LiteralMap{}
+	}
+
+	if err := store.WriteProtobuf(ctx, v1alpha1.GetInputsFile(nodeStatus.GetDataDir()), storage.Options{}, inputs); err != nil {
+		return nil, err
+	}
+
+	var tr handler.TaskReader
+	if b.task != nil {
+		tr = taskReader{TaskTemplate: b.task}
+	} else if b.node.GetKind() == v1alpha1.NodeKindTask && b.node.GetTaskID() != nil {
+		tk, err := wf.GetTask(*b.node.GetTaskID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to find task [%s] for node [%s]: %w", *b.node.GetTaskID(), b.node.GetID(), err)
+		}
+		tr = taskReader{TaskTemplate: tk.CoreTask()}
+	}
+
+	parentInfo := b.parentInfo
+	if parentInfo == nil {
+		parentInfo = executors.NewParentInfo("", 0)
+	}
+
+	rawOutputPrefix := b.rawOutputPrefix
+	if len(rawOutputPrefix) == 0 {
+		rawOutputPrefix = DefaultRawOutputPrefix
+	}
+
+	maxDatasetSizeBytes := b.maxDatasetSizeBytes
+	if maxDatasetSizeBytes == 0 {
+		maxDatasetSizeBytes = defaultMaxDatasetSizeBytes
+	}
+
+	return &NodeExecutionContext{
+		node:       b.node,
+		nodeStatus: nodeStatus,
+		store:      store,
+		inputs: ioutils.NewRemoteFileInputReader(ctx, store,
+			ioutils.NewInputFilePaths(ctx, store, nodeStatus.GetDataDir())),
+		recorder: events.NewTaskEventRecorder(sink, promutils.NewTestScope()),
+		tr:       tr,
+		md: nodeExecMetadata{
+			Meta: wf,
+			nodeExecID: &core.NodeExecutionIdentifier{
+				NodeId:      b.node.GetID(),
+				ExecutionId: wf.GetExecutionID().WorkflowExecutionIdentifier,
+			},
+			interruptible: b.interruptible,
+		},
+		rawOutputPrefix:     rawOutputPrefix,
+		maxDatasetSizeBytes: maxDatasetSizeBytes,
+		nl:                  executors.NewNodeLookup(wf, wf.GetExecutionStatus()),
+		ic:                  executors.NewExecutionContext(wf, wf, wf, parentInfo, executors.InitializeControlFlow()),
+		Workflow:            wf,
+		State:               state,
+		Sink:                sink,
+	}, nil
+}
+
+// NewNodeExecutionContextBuilder starts building a context for the given node.
+func NewNodeExecutionContextBuilder(node *v1alpha1.NodeSpec) *NodeExecutionContextBuilder {
+	return &NodeExecutionContextBuilder{node: node}
+}
+
+type nodeExecMetadata struct {
+	v1alpha1.Meta
+	nodeExecID    *core.NodeExecutionIdentifier
+	interruptible bool
+}
+
+func (m nodeExecMetadata) GetNodeExecutionID() *core.NodeExecutionIdentifier {
+	return m.nodeExecID
+}
+
+func (m nodeExecMetadata) GetK8sServiceAccount() string {
+	return m.Meta.GetServiceAccountName()
+}
+
+func (m nodeExecMetadata) GetOwnerID() types.NamespacedName {
+	return types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}
+}
+
+func (m nodeExecMetadata) IsInterruptible() bool {
+	return m.interruptible
+}
+
+type taskReader struct {
+	*core.TaskTemplate
+}
+
+func (t taskReader) GetTaskType() v1alpha1.TaskType {
+	return t.TaskTemplate.Type
+}
+
+func (t taskReader) GetTaskID() *core.Identifier {
+	return t.Id
+}
+
+func (t taskReader) Read(_ context.Context) (*core.TaskTemplate, error) {
+	return t.TaskTemplate, nil
+}
@@ -0,0 +1,63 @@
+package testkit
+
+import (
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+)
+
+// NodeState is an in-memory handler.NodeStateReader and handler.NodeStateWriter. Unlike the node executor, it does not
+// persist anything into the node status, so tests can inspect exactly what a handler wrote during a single round and
+// seed the state the next round should observe.
+type NodeState struct {
+	Task     *handler.TaskNodeState
+	Branch   *handler.BranchNodeState
+	Dynamic  *handler.DynamicNodeState
+	Workflow *handler.WorkflowNodeState
+}
+
+func (n *NodeState) PutTaskNodeState(s handler.TaskNodeState) error {
+	n.Task = &s
+	return nil
+}
+
+func (n *NodeState) PutBranchNode(s handler.BranchNodeState) error {
+	n.Branch = &s
+	return nil
+}
+
+func (n *NodeState) PutDynamicNodeState(s handler.DynamicNodeState) error {
+	n.Dynamic = &s
+	return nil
+}
+
+func (n *NodeState) PutWorkflowNodeState(s handler.WorkflowNodeState) error {
+	n.Workflow = &s
+	return nil
+}
+
+func (n NodeState) GetTaskNodeState() handler.TaskNodeState {
+	if n.Task != nil {
+		return *n.Task
+	}
+	return handler.TaskNodeState{}
+}
+
+func (n NodeState) GetBranchNode() handler.BranchNodeState {
+	if n.Branch != nil {
+		return *n.Branch
+	}
+	return handler.BranchNodeState{}
+}
+
+func (n NodeState) GetDynamicNodeState() handler.DynamicNodeState {
+	if n.Dynamic != nil {
+		return *n.Dynamic
+	}
+	return handler.DynamicNodeState{}
+}
+
+func (n NodeState) GetWorkflowNodeState() handler.WorkflowNodeState {
+	if n.Workflow != nil {
+		return *n.Workflow
+	}
+	return handler.WorkflowNodeState{}
+}
@@ -0,0 +1,31 @@
+// Package testkit provides deterministic building blocks for integration style tests of node handlers and task
+// plugins. Instead of wiring up mocks for every method of handler.NodeExecutionContext, tests describe the node, its
+// status and inputs with the real v1alpha1 types and get back a fully functional context backed by an in-memory
+// datastore, a recording event sink and a controllable clock.
+//
+// Like the rest of propeller, the datastore and event recorders use labeled metrics, so tests need to call
+// labeled.SetMetricKeys before building a context.
+package testkit
+
+import (
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// DefaultStartTime is the time every FakeClock handed out by this package starts at, so that timestamps in tests are
+// reproducible.
+var DefaultStartTime = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// NewInMemoryDataStore creates a datastore that keeps all blobs in memory. Each call returns an independent store.
+func NewInMemoryDataStore() (*storage.DataStore, error) {
+	return storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+}
+
+// NewFakeClock returns a clock that only moves when the test steps it, starting at DefaultStartTime. Pass it to the
+// components that accept a clock.Clock (e.g. the backoff handler) to control time deterministically.
+func NewFakeClock() *clock.FakeClock {
+	return clock.NewFakeClock(DefaultStartTime)
+}
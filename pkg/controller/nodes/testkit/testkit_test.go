@@ -0,0 +1,98 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/end"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NodeIDKey)
+}
+
+func TestNodeExecutionContextBuilder(t *testing.T) {
+	ctx := context.TODO()
+	inputs := &core.LiteralMap{Literals: map[string]*core.Literal{"x": coreutils.MustMakePrimitiveLiteral(int64(5))}}
+
+	t.Run("end-node", func(t *testing.T) {
+		nCtx, err := NewNodeExecutionContextBuilder(&v1alpha1.NodeSpec{ID: v1alpha1.EndNodeID, Kind: v1alpha1.NodeKindEnd}).
+			WithInputs(inputs).
+			Build(ctx)
+		assert.NoError(t, err)
+
+		trns, err := end.New().Handle(ctx, nCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, handler.EPhaseSuccess, trns.Info().GetPhase())
+
+		outputs := &core.LiteralMap{}
+		assert.NoError(t, nCtx.DataStore().ReadProtobuf(ctx, nCtx.OutputsFile(), outputs))
+		assert.Equal(t, int64(5), outputs.Literals["x"].GetScalar().GetPrimitive().GetInteger())
+	})
+
+	t.Run("task-node", func(t *testing.T) {
+		taskID := "t1"
+		wf := (&NodeExecutionContextBuilder{}).defaultWorkflow()
+		wf.Tasks = map[v1alpha1.TaskID]*v1alpha1.TaskSpec{
+			taskID: {TaskTemplate: &core.TaskTemplate{Id: &core.Identifier{Name: "task"}, Type: "python"}},
+		}
+
+		sink := NewRecordingEventSink()
+		nCtx, err := NewNodeExecutionContextBuilder(&v1alpha1.NodeSpec{ID: "n1", Kind: v1alpha1.NodeKindTask, TaskRef: &taskID}).
+			WithWorkflow(wf).
+			WithEventSink(sink).
+			WithInterruptible(true).
+			Build(ctx)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "python", nCtx.TaskReader().GetTaskType())
+		assert.True(t, nCtx.NodeExecutionMetadata().IsInterruptible())
+		assert.Equal(t, "n1", nCtx.NodeExecutionMetadata().GetNodeExecutionID().NodeId)
+		assert.Equal(t, DefaultName, nCtx.ExecutionContext().GetExecutionID().Name)
+
+		_, ok := nCtx.ContextualNodeLookup().GetNode("n1")
+		assert.True(t, ok)
+
+		// Inputs were not provided, so there are none to read.
+		in, err := nCtx.InputReader().Get(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, in.GetLiterals())
+
+		assert.NoError(t, nCtx.NodeStateWriter().PutTaskNodeState(handler.TaskNodeState{PluginPhaseVersion: 2}))
+		assert.Equal(t, uint32(2), nCtx.NodeStateReader().GetTaskNodeState().PluginPhaseVersion)
+		assert.Nil(t, nCtx.State.Branch)
+
+		assert.NoError(t, nCtx.EventsRecorder().RecordTaskEvent(ctx, &event.TaskExecutionEvent{Phase: core.TaskExecution_RUNNING}))
+		if assert.Len(t, sink.TaskEvents(), 1) {
+			assert.Equal(t, core.TaskExecution_RUNNING, sink.TaskEvents()[0].Phase)
+		}
+		assert.Empty(t, sink.NodeEvents())
+
+		assert.NoError(t, nCtx.EnqueueOwnerFunc()())
+		assert.Equal(t, 1, nCtx.EnqueueCount())
+	})
+
+	t.Run("missing-task", func(t *testing.T) {
+		taskID := "missing"
+		_, err := NewNodeExecutionContextBuilder(&v1alpha1.NodeSpec{ID: "n1", Kind: v1alpha1.NodeKindTask, TaskRef: &taskID}).
+			Build(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestFakeClock(t *testing.T) {
+	c := NewFakeClock()
+	assert.Equal(t, DefaultStartTime, c.Now())
+	c.Step(time.Minute)
+	assert.Equal(t, DefaultStartTime.Add(time.Minute), c.Now())
+}
@@ -11,12 +11,12 @@ import (
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/golang/protobuf/ptypes"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 func ToNodeExecOutput(info *handler.OutputInfo) *event.NodeExecutionEvent_OutputUri {
@@ -117,6 +117,12 @@ func ToNodeExecutionEvent(nodeExecID *core.NodeExecutionIdentifier,
 				NodeId: parentInfo.GetUniqueID(),
 			}
 			nev.RetryGroup = strconv.Itoa(int(parentInfo.CurrentAttempt()))
+			// Nodes generated by a task, e.g. dynamic nodes, are also nested under the task execution of their parent.
+			if p, ok := parentInfo.(executors.ImmutableParentTaskInfo); ok && p.GetParentTaskID() != nil {
+				nev.ParentTaskMetadata = &event.ParentTaskExecutionMetadata{
+					Id: p.GetParentTaskID(),
+				}
+			}
 		}
 		nev.SpecNodeId = node.GetID()
 		nev.Id.NodeId = currentNodeUniqueID
@@ -191,6 +197,8 @@ func UpdateNodeStatus(np v1alpha1.NodePhase, p handler.PhaseInfo, n *nodeStateMa
 		t.SetPluginState(n.t.PluginState)
 		t.SetPluginStateVersion(n.t.PluginStateVersion)
 		t.SetBarrierClockTick(n.t.BarrierClockTick)
+		t.SetStalledReason(n.t.StalledReason)
+		t.SetReservationOwner(n.t.ReservationOwner)
 	}
 
 	// Update dynamic node status
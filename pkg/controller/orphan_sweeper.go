@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flyteworkflow "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// flyteFinalizerPrefix prefixes the finalizers propeller adds to task resources. They are only ever removed by the
+// workflow that owns the resource, so they have to be dropped before an orphan can be deleted.
+const flyteFinalizerPrefix = "flyte/"
+
+type orphanSweeperMetrics struct {
+	orphansFound   prometheus.Counter
+	orphansDeleted prometheus.Counter
+	sweepFailures  prometheus.Counter
+	sweepTime      promutils.StopWatch
+}
+
+// OrphanSweeper is a background cleanup service that deletes task resources, e.g. pods, whose FlyteWorkflow no longer
+// exists. Task resources are normally cleaned up when their workflow is finalized or garbage collected through their
+// owner references, but they leak when a workflow is force deleted or its finalizer fails: the garbage collector then
+// marks them deleted, yet the flyte finalizers keep them around forever.
+// Resources are matched to their workflow through their owner reference, by name and UID. The execution-id label does
+// not identify the workflow, it only holds the prefix of the name of workflows created without an execution ID.
+// Resources without an owner reference to a FlyteWorkflow, e.g. those of plugins that disable owner references, are
+// never swept, and only the resources of the cluster propeller runs in are.
+type OrphanSweeper struct {
+	kubeClient client.Client
+	wfClient   v1alpha1.FlyteworkflowV1alpha1Interface
+	resources  []schema.GroupVersionKind
+	namespace  string
+	minAge     time.Duration
+	interval   time.Duration
+	dryRun     bool
+	clk        clock.Clock
+	metrics    orphanSweeperMetrics
+}
+
+// ParseResourceKind parses a resource kind formatted as <apiVersion>/<kind>, e.g. v1/Pod or kubeflow.org/v1/PyTorchJob.
+func ParseResourceKind(kind string) (schema.GroupVersionKind, error) {
+	idx := strings.LastIndex(kind, "/")
+	if idx <= 0 || idx == len(kind)-1 {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid resource kind [%v], expected <apiVersion>/<kind>", kind)
+	}
+
+	gv, err := schema.ParseGroupVersion(kind[:idx])
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid resource kind [%v]: %w", kind, err)
+	}
+
+	return gv.WithKind(kind[idx+1:]), nil
+}
+
+// Returns the owner reference of the resource to its FlyteWorkflow, if any.
+func workflowOwnerOf(obj *unstructured.Unstructured) (v1.OwnerReference, bool) {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == flyteworkflow.FlyteWorkflowKind {
+			return ref, true
+		}
+	}
+
+	return v1.OwnerReference{}, false
+}
+
+// Returns true if the resource still carries finalizers only its workflow removes.
+func hasFlyteFinalizers(obj *unstructured.Unstructured) bool {
+	for _, f := range obj.GetFinalizers() {
+		if strings.HasPrefix(f, flyteFinalizerPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the UID of the workflow with the given name, or false if it does not exist.
+func (s *OrphanSweeper) getWorkflowUID(ctx context.Context, namespace, name string) (types.UID, bool, error) {
+	wf, err := s.wfClient.FlyteWorkflows(namespace).Get(ctx, name, v1.GetOptions{})
+	if err == nil {
+		return wf.GetUID(), true, nil
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return "", false, nil
+	}
+
+	return "", false, err
+}
+
+func (s *OrphanSweeper) deleteOrphan(ctx context.Context, obj *unstructured.Unstructured) error {
+	finalizers := make([]string, 0, len(obj.GetFinalizers()))
+	for _, f := range obj.GetFinalizers() {
+		if !strings.HasPrefix(f, flyteFinalizerPrefix) {
+			finalizers = append(finalizers, f)
+		}
+	}
+
+	if len(finalizers) != len(obj.GetFinalizers()) {
+		obj.SetFinalizers(finalizers)
+		if err := s.kubeClient.Update(ctx, obj); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	err := s.kubeClient.Delete(ctx, obj, client.PropagationPolicy(v1.DeletePropagationBackground))
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *OrphanSweeper) sweepKind(ctx context.Context, gvk schema.GroupVersionKind) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	opts := []client.ListOption{client.HasLabels{k8s.ExecutionIDLabel}}
+	if len(s.namespace) > 0 {
+		opts = append(opts, client.InNamespace(s.namespace))
+	}
+
+	if err := s.kubeClient.List(ctx, list, opts...); err != nil {
+		return err
+	}
+
+	// Several resources usually belong to the same workflow, only look each workflow up once per sweep.
+	type workflowState struct {
+		uid   types.UID
+		found bool
+	}
+
+	workflows := map[string]workflowState{}
+	now := s.clk.Now()
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if obj.GetDeletionTimestamp() != nil && !hasFlyteFinalizers(obj) {
+			continue
+		}
+
+		if now.Sub(obj.GetCreationTimestamp().Time) < s.minAge {
+			continue
+		}
+
+		owner, ok := workflowOwnerOf(obj)
+		if !ok {
+			continue
+		}
+
+		wfName := owner.Name
+		key := obj.GetNamespace() + "/" + wfName
+		wf, ok := workflows[key]
+		if !ok {
+			var err error
+			if wf.uid, wf.found, err = s.getWorkflowUID(ctx, obj.GetNamespace(), wfName); err != nil {
+				return err
+			}
+
+			workflows[key] = wf
+		}
+
+		// A workflow recreated with the same name does not own the resources of its predecessor.
+		if wf.found && wf.uid == owner.UID {
+			continue
+		}
+
+		s.metrics.orphansFound.Inc()
+		if s.dryRun {
+			logger.Infof(ctx, "Found orphaned %v [%v/%v] of workflow [%v], not deleting it in dry run mode.",
+				gvk.Kind, obj.GetNamespace(), obj.GetName(), wfName)
+			continue
+		}
+
+		logger.Infof(ctx, "Deleting orphaned %v [%v/%v] of workflow [%v].", gvk.Kind, obj.GetNamespace(),
+			obj.GetName(), wfName)
+		if err := s.deleteOrphan(ctx, obj); err != nil {
+			return err
+		}
+
+		s.metrics.orphansDeleted.Inc()
+	}
+
+	return nil
+}
+
+// Sweep deletes the orphaned resources of every configured kind once.
+func (s *OrphanSweeper) Sweep(ctx context.Context) {
+	timer := s.metrics.sweepTime.Start()
+	defer timer.Stop()
+
+	for _, gvk := range s.resources {
+		if err := s.sweepKind(ctx, gvk); err != nil {
+			s.metrics.sweepFailures.Inc()
+			logger.Errorf(ctx, "Failed to sweep orphaned resources of kind [%v]. Error: %v", gvk, err)
+		}
+	}
+}
+
+// Run sweeps periodically in the background until the context is cancelled.
+func (s *OrphanSweeper) Run(ctx context.Context) {
+	sweeperCtx := contextutils.WithGoroutineLabel(ctx, "orphan-sweeper")
+	ticker := s.clk.NewTicker(s.interval)
+
+	go func() {
+		pprof.SetGoroutineLabels(sweeperCtx)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sweeperCtx.Done():
+				return
+			case <-ticker.C():
+				s.Sweep(sweeperCtx)
+			}
+		}
+	}()
+}
+
+func NewOrphanSweeper(cfg *config.Config, kubeClient client.Client, wfClient v1alpha1.FlyteworkflowV1alpha1Interface,
+	clk clock.Clock, scope promutils.Scope) (*OrphanSweeper, error) {
+
+	resources := make([]schema.GroupVersionKind, 0, len(cfg.OrphanSweeper.Resources))
+	for _, r := range cfg.OrphanSweeper.Resources {
+		gvk, err := ParseResourceKind(r)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, gvk)
+	}
+
+	if cfg.OrphanSweeper.Interval.Duration <= 0 {
+		return nil, fmt.Errorf("orphan sweeper interval must be positive, found [%v]", cfg.OrphanSweeper.Interval)
+	}
+
+	namespace := cfg.LimitNamespace
+	if strings.ToLower(namespace) == "all" || strings.ToLower(namespace) == "all-namespaces" {
+		namespace = ""
+	}
+
+	return &OrphanSweeper{
+		kubeClient: kubeClient,
+		wfClient:   wfClient,
+		resources:  resources,
+		namespace:  namespace,
+		minAge:     cfg.OrphanSweeper.MinAge.Duration,
+		interval:   cfg.OrphanSweeper.Interval.Duration,
+		dryRun:     cfg.OrphanSweeper.DryRun,
+		clk:        clk,
+		metrics: orphanSweeperMetrics{
+			orphansFound:   scope.MustNewCounter("orphans_found", "Number of task resources found whose workflow no longer exists"),
+			orphansDeleted: scope.MustNewCounter("orphans_deleted", "Number of orphaned task resources deleted"),
+			sweepFailures:  scope.MustNewCounter("sweep_failures", "Number of failed sweeps of a resource kind"),
+			sweepTime:      scope.MustNewStopWatch("sweep_time", "Time taken by one sweep of all resource kinds", time.Millisecond),
+		},
+	}, nil
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stdConfig "github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	flyteworkflow "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	wfFake "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/fake"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+func TestParseResourceKind(t *testing.T) {
+	gvk, err := ParseResourceKind("v1/Pod")
+	assert.NoError(t, err)
+	assert.Equal(t, schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, gvk)
+
+	gvk, err = ParseResourceKind("kubeflow.org/v1/PyTorchJob")
+	assert.NoError(t, err)
+	assert.Equal(t, schema.GroupVersionKind{Group: "kubeflow.org", Version: "v1", Kind: "PyTorchJob"}, gvk)
+
+	for _, invalid := range []string{"Pod", "v1/", "/Pod", "a/b/c/Pod"} {
+		_, err = ParseResourceKind(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestOrphanSweeper_Sweep(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	clk := clock.NewFakeClock(now)
+
+	newPod := func(name, execID, owner string, ownerUID types.UID, age time.Duration, finalizers ...string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ns",
+				CreationTimestamp: v1.NewTime(now.Add(-age)),
+				Finalizers:        finalizers,
+				Labels:            map[string]string{},
+			},
+		}
+
+		if len(execID) > 0 {
+			p.Labels[k8s.ExecutionIDLabel] = execID
+		}
+
+		if len(owner) > 0 {
+			p.OwnerReferences = []v1.OwnerReference{{
+				APIVersion: flyteworkflow.SchemeGroupVersion.String(),
+				Kind:       flyteworkflow.FlyteWorkflowKind,
+				Name:       owner,
+				UID:        ownerUID,
+			}}
+		}
+
+		return p
+	}
+
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		newPod("running", "exists", "exists", "exists-uid", 2*time.Hour),
+		// Workflows created without an execution ID are labeled with the prefix of their generated name.
+		newPod("generated", "project-domain-wf", "project-domain-wf-x7k2p", "generated-uid", 2*time.Hour,
+			"flyte/flytek8s"),
+		newPod("orphan", "gone", "gone", "gone-uid", 2*time.Hour, "flyte/flytek8s", "other/finalizer"),
+		newPod("orphan-sibling", "gone", "gone", "gone-uid", 2*time.Hour),
+		newPod("recreated-orphan", "exists", "exists", "previous-uid", 2*time.Hour),
+		newPod("young-orphan", "gone", "gone", "gone-uid", time.Minute),
+		newPod("not-owned", "gone", "", "", 2*time.Hour),
+		newPod("unlabeled", "", "gone", "gone-uid", 2*time.Hour),
+	).Build()
+
+	wfClient := wfFake.NewSimpleClientset()
+	for name, uid := range map[string]types.UID{"exists": "exists-uid", "project-domain-wf-x7k2p": "generated-uid"} {
+		_, err := wfClient.FlyteworkflowV1alpha1().FlyteWorkflows("ns").Create(ctx, &flyteworkflow.FlyteWorkflow{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "ns", UID: uid},
+		}, v1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	cfg := &config.Config{
+		LimitNamespace: "all",
+		OrphanSweeper: config.OrphanSweeperConfig{
+			Enabled:   true,
+			Interval:  stdConfig.Duration{Duration: time.Minute},
+			MinAge:    stdConfig.Duration{Duration: time.Hour},
+			Resources: []string{"v1/Pod"},
+		},
+	}
+
+	getPod := func(name string) (*corev1.Pod, error) {
+		p := &corev1.Pod{}
+		err := kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: name}, p)
+		return p, err
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		dryRunCfg := *cfg
+		dryRunCfg.OrphanSweeper.DryRun = true
+		s, err := NewOrphanSweeper(&dryRunCfg, kubeClient, wfClient.FlyteworkflowV1alpha1(), clk, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		s.Sweep(ctx)
+		assert.Equal(t, float64(3), testutil.ToFloat64(s.metrics.orphansFound))
+		assert.Equal(t, float64(0), testutil.ToFloat64(s.metrics.orphansDeleted))
+		_, err = getPod("orphan")
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		s, err := NewOrphanSweeper(cfg, kubeClient, wfClient.FlyteworkflowV1alpha1(), clk, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		s.Sweep(ctx)
+		assert.Equal(t, float64(3), testutil.ToFloat64(s.metrics.orphansDeleted))
+		assert.Equal(t, float64(0), testutil.ToFloat64(s.metrics.sweepFailures))
+
+		for _, name := range []string{"running", "generated", "young-orphan", "not-owned", "unlabeled"} {
+			_, err = getPod(name)
+			assert.NoError(t, err, name)
+		}
+
+		// The fake client does not honor finalizers, the orphan is gone once flyte finalizers have been removed.
+		_, err = getPod("orphan-sibling")
+		assert.True(t, k8serrors.IsNotFound(err))
+		_, err = getPod("orphan")
+		assert.True(t, k8serrors.IsNotFound(err))
+		_, err = getPod("recreated-orphan")
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		generated, err := getPod("generated")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"flyte/flytek8s"}, generated.Finalizers)
+	})
+}
+
+func TestNewOrphanSweeper(t *testing.T) {
+	cfg := &config.Config{
+		OrphanSweeper: config.OrphanSweeperConfig{
+			Interval:  stdConfig.Duration{Duration: time.Minute},
+			Resources: []string{"Pod"},
+		},
+	}
+
+	_, err := NewOrphanSweeper(cfg, nil, nil, clock.RealClock{}, promutils.NewTestScope())
+	assert.Error(t, err)
+
+	cfg.OrphanSweeper.Resources = []string{"v1/Pod"}
+	cfg.OrphanSweeper.Interval = stdConfig.Duration{}
+	_, err = NewOrphanSweeper(cfg, nil, nil, clock.RealClock{}, promutils.NewTestScope())
+	assert.Error(t, err)
+}
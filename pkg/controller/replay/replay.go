@@ -0,0 +1,218 @@
+// Package replay re-executes workflow evaluations from a FlyteWorkflow snapshot, e.g. an archived or a live workflow,
+// entirely in memory. The workflow executor runs against a fake K8s client, a no-op catalog and a datastore that reads
+// through to the real datastore but keeps all writes in memory. Every phase transition of the workflow and its nodes is
+// logged, which helps reproducing scheduling bugs without touching the cluster or the execution's data.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/admin"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflow"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// The id that decisions about the workflow itself, rather than one of its nodes, are recorded under.
+const workflowDecisionID = ""
+
+// Decision is a phase transition of the workflow or one of its nodes that was observed during a replay.
+type Decision struct {
+	// The round of evaluation, starting at 1, in which the transition was observed.
+	Round int
+	// The id of the node, nested nodes are separated by a slash. Empty for the workflow itself.
+	NodeID  string
+	From    string
+	To      string
+	Message string
+}
+
+func (d Decision) String() string {
+	if d.NodeID == workflowDecisionID {
+		return fmt.Sprintf("round [%d] workflow [%s] -> [%s]: %s", d.Round, d.From, d.To, d.Message)
+	}
+
+	return fmt.Sprintf("round [%d] node [%s] [%s] -> [%s]: %s", d.Round, d.NodeID, d.From, d.To, d.Message)
+}
+
+type fakeKubeClient struct {
+	client client.Client
+	cache  cache.Cache
+}
+
+func (f fakeKubeClient) GetClient() client.Client {
+	return f.client
+}
+
+func (f fakeKubeClient) GetCache() cache.Cache {
+	return f.cache
+}
+
+// Executions are never recovered during a replay, nodes always execute as they would without a recovery execution.
+type noRecoveryClient struct{}
+
+func (noRecoveryClient) RecoverNodeExecution(_ context.Context, _ *core.WorkflowExecutionIdentifier,
+	_ *core.NodeExecutionIdentifier) (*admin.NodeExecution, error) {
+
+	return nil, fmt.Errorf("recovering node executions is not supported during a replay")
+}
+
+func (noRecoveryClient) RecoverNodeExecutionData(_ context.Context, _ *core.WorkflowExecutionIdentifier,
+	_ *core.NodeExecutionIdentifier) (*admin.NodeExecutionGetDataResponse, error) {
+
+	return nil, fmt.Errorf("recovering node executions is not supported during a replay")
+}
+
+// Harness replays workflow evaluations in memory.
+type Harness struct {
+	executor executors.Workflow
+}
+
+type phaseInfo struct {
+	phase   string
+	message string
+}
+
+// Collects the phases of the workflow and all its nodes, keyed by the decision id.
+func collectPhases(wf *v1alpha1.FlyteWorkflow) map[string]phaseInfo {
+	phases := map[string]phaseInfo{
+		workflowDecisionID: {phase: wf.Status.Phase.String(), message: wf.Status.Message},
+	}
+
+	var collect func(prefix string, statuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus)
+	collect = func(prefix string, statuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) {
+		for key, s := range statuses {
+			if s == nil {
+				continue
+			}
+
+			id := v1alpha1.StripAttemptSuffix(key)
+			phases[prefix+id] = phaseInfo{phase: s.Phase.String(), message: s.Message}
+			collect(prefix+id+"/", s.SubNodeStatus)
+		}
+	}
+
+	collect("", wf.Status.NodeStatus)
+	return phases
+}
+
+func diffPhases(round int, before, after map[string]phaseInfo) []Decision {
+	ids := make([]string, 0, len(after))
+	for id := range after {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	decisions := make([]Decision, 0, len(ids))
+	for _, id := range ids {
+		if prev, ok := before[id]; !ok || prev.phase != after[id].phase {
+			decisions = append(decisions, Decision{
+				Round:   round,
+				NodeID:  id,
+				From:    before[id].phase,
+				To:      after[id].phase,
+				Message: after[id].message,
+			})
+		}
+	}
+
+	return decisions
+}
+
+// Evaluates the workflow once and turns panics into errors, as the controller does.
+func (h *Harness) evaluate(ctx context.Context, wf *v1alpha1.FlyteWorkflow) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic when evaluating workflow, Stack: [%s]", string(debug.Stack()))
+		}
+	}()
+
+	return h.executor.HandleFlyteWorkflow(ctx, wf)
+}
+
+// Replay evaluates a copy of the snapshot until it terminates or for at most maxRounds rounds. It returns the phase
+// transitions observed in every round and the final state of the workflow. The snapshot is not modified.
+func (h *Harness) Replay(ctx context.Context, snapshot *v1alpha1.FlyteWorkflow, maxRounds int) (
+	[]Decision, *v1alpha1.FlyteWorkflow, error) {
+
+	wf := snapshot.DeepCopy()
+	var decisions []Decision
+	before := collectPhases(wf)
+	for round := 1; round <= maxRounds && !wf.GetExecutionStatus().IsTerminated(); round++ {
+		if err := h.evaluate(ctx, wf); err != nil {
+			return decisions, wf, err
+		}
+
+		after := collectPhases(wf)
+		for _, d := range diffPhases(round, before, after) {
+			logger.Infof(ctx, "Replay decision: %v", d)
+			decisions = append(decisions, d)
+		}
+
+		before = after
+		for _, s := range wf.Status.NodeStatus {
+			s.ResetDirty()
+		}
+	}
+
+	return decisions, wf, nil
+}
+
+// NewHarness creates a replay harness. The source datastore is only read from, all data written during replays is kept
+// in memory. Plugins and executors are configured from the propeller config.
+func NewHarness(ctx context.Context, source *storage.DataStore, scope promutils.Scope) (*Harness, error) {
+	store, err := newOverlayDataStore(source, scope.NewSubScope("store"))
+	if err != nil {
+		return nil, err
+	}
+
+	eventSink, err := events.NewLogSink()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := config.GetConfig()
+	enqueueWorkflow := func(workflowID v1alpha1.WorkflowID) {}
+	launcher := launchplan.NewFailFastLaunchPlanExecutor()
+	kubeClient := fakeKubeClient{
+		client: fake.NewClientBuilder().Build(),
+		cache:  &informertest.FakeInformers{},
+	}
+
+	nodeExecutor, err := nodes.NewExecutor(ctx, cfg.NodeConfig, store, enqueueWorkflow, eventSink, launcher, launcher,
+		cfg.MaxDatasetSizeBytes, storage.DataReference(cfg.DefaultRawOutputPrefix), kubeClient, catalog.NOOPCatalog{},
+		noRecoveryClient{}, scope.NewSubScope("node"))
+	if err != nil {
+		return nil, err
+	}
+
+	wfExecutor, err := workflow.NewExecutor(ctx, store, enqueueWorkflow, eventSink, &record.FakeRecorder{},
+		cfg.MetadataPrefix, nodeExecutor, scope.NewSubScope("workflow"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wfExecutor.Initialize(ctx); err != nil {
+		return nil, err
+	}
+
+	return &Harness{executor: wfExecutor}, nil
+}
@@ -0,0 +1,115 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func newSourceStore(t testing.TB) *storage.DataStore {
+	store, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	return store
+}
+
+func TestOverlayDataStore(t *testing.T) {
+	ctx := context.TODO()
+	source := newSourceStore(t)
+	assert.NoError(t, source.WriteRaw(ctx, "s3://bucket/existing", 1, storage.Options{}, bytes.NewReader([]byte("a"))))
+
+	store, err := newOverlayDataStore(source, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	t.Run("reads-through", func(t *testing.T) {
+		m, err := store.Head(ctx, "s3://bucket/existing")
+		assert.NoError(t, err)
+		assert.True(t, m.Exists())
+	})
+
+	t.Run("writes-stay-in-memory", func(t *testing.T) {
+		assert.NoError(t, store.WriteRaw(ctx, "s3://bucket/new", 1, storage.Options{}, bytes.NewReader([]byte("b"))))
+		assert.NoError(t, store.CopyRaw(ctx, "s3://bucket/existing", "s3://bucket/copy", storage.Options{}))
+
+		for _, ref := range []storage.DataReference{"s3://bucket/new", "s3://bucket/copy"} {
+			m, err := store.Head(ctx, ref)
+			assert.NoError(t, err)
+			assert.True(t, m.Exists())
+
+			m, err = source.Head(ctx, ref)
+			assert.NoError(t, err)
+			assert.False(t, m.Exists())
+		}
+	})
+}
+
+func TestHarness_Replay(t *testing.T) {
+	ctx := context.TODO()
+	harness, err := NewHarness(ctx, newSourceStore(t), promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	snapshot := &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "replay",
+			Namespace: "ns",
+		},
+		ExecutionID: v1alpha1.WorkflowExecutionIdentifier{
+			WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "replay"},
+		},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{
+			ID: "wf",
+			Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+				v1alpha1.StartNodeID: {ID: v1alpha1.StartNodeID, Kind: v1alpha1.NodeKindStart},
+				v1alpha1.EndNodeID:   {ID: v1alpha1.EndNodeID, Kind: v1alpha1.NodeKindEnd},
+			},
+			Connections: v1alpha1.Connections{
+				Downstream: map[v1alpha1.NodeID][]v1alpha1.NodeID{v1alpha1.StartNodeID: {v1alpha1.EndNodeID}},
+				Upstream:   map[v1alpha1.NodeID][]v1alpha1.NodeID{v1alpha1.EndNodeID: {v1alpha1.StartNodeID}},
+			},
+		},
+	}
+
+	decisions, final, err := harness.Replay(ctx, snapshot, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.WorkflowPhaseSuccess, final.Status.Phase, final.Status.Message)
+	assert.Equal(t, v1alpha1.WorkflowPhaseReady, snapshot.Status.Phase)
+
+	if assert.NotEmpty(t, decisions) {
+		assert.Equal(t, Decision{Round: 1, NodeID: workflowDecisionID, From: v1alpha1.WorkflowPhaseReady.String(),
+			To: v1alpha1.WorkflowPhaseRunning.String(), Message: "Workflow Started"}, decisions[0])
+		last := decisions[len(decisions)-1]
+		assert.Equal(t, v1alpha1.WorkflowPhaseSuccess.String(), last.To)
+	}
+
+	var endNodeSucceeded bool
+	for _, d := range decisions {
+		endNodeSucceeded = endNodeSucceeded || (d.NodeID == v1alpha1.EndNodeID && d.To == v1alpha1.NodePhaseSucceeded.String())
+	}
+
+	assert.True(t, endNodeSucceeded, "decisions: %v", decisions)
+}
+
+func TestCollectPhases(t *testing.T) {
+	wf := &v1alpha1.FlyteWorkflow{Status: v1alpha1.WorkflowStatus{
+		Phase: v1alpha1.WorkflowPhaseRunning,
+		NodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"n0": {
+				Phase: v1alpha1.NodePhaseRunning,
+				SubNodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+					"sub@1": {Phase: v1alpha1.NodePhaseQueued},
+				},
+			},
+		},
+	}}
+
+	phases := collectPhases(wf)
+	assert.Equal(t, v1alpha1.NodePhaseQueued.String(), phases["n0/sub"].phase)
+	assert.NotContains(t, phases, "n0/sub@1")
+}
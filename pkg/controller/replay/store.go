@@ -0,0 +1,70 @@
+package replay
+
+import (
+	"context"
+	"io"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+)
+
+// overlayRawStore serves reads from the data written during the replay and falls back to the source store for
+// everything else. Writes are kept in memory, so that a replay never modifies the source store.
+type overlayRawStore struct {
+	source  storage.RawStore
+	overlay storage.RawStore
+}
+
+func (s overlayRawStore) GetBaseContainerFQN(ctx context.Context) storage.DataReference {
+	return s.source.GetBaseContainerFQN(ctx)
+}
+
+func (s overlayRawStore) Head(ctx context.Context, reference storage.DataReference) (storage.Metadata, error) {
+	if m, err := s.overlay.Head(ctx, reference); err == nil && m.Exists() {
+		return m, nil
+	}
+
+	return s.source.Head(ctx, reference)
+}
+
+func (s overlayRawStore) ReadRaw(ctx context.Context, reference storage.DataReference) (io.ReadCloser, error) {
+	if m, err := s.overlay.Head(ctx, reference); err == nil && m.Exists() {
+		return s.overlay.ReadRaw(ctx, reference)
+	}
+
+	return s.source.ReadRaw(ctx, reference)
+}
+
+func (s overlayRawStore) WriteRaw(ctx context.Context, reference storage.DataReference, size int64, opts storage.Options,
+	raw io.Reader) error {
+
+	return s.overlay.WriteRaw(ctx, reference, size, opts, raw)
+}
+
+func (s overlayRawStore) CopyRaw(ctx context.Context, source, destination storage.DataReference, opts storage.Options) error {
+	reader, err := s.ReadRaw(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	return s.overlay.WriteRaw(ctx, destination, -1, opts, reader)
+}
+
+// Creates a datastore that reads through to the source datastore and keeps all writes in memory.
+func newOverlayDataStore(source *storage.DataStore, scope promutils.Scope) (*storage.DataStore, error) {
+	overlay, err := storage.NewInMemoryRawStore(&storage.Config{}, scope.NewSubScope("overlay"))
+	if err != nil {
+		return nil, err
+	}
+
+	rawStore := overlayRawStore{
+		source:  source,
+		overlay: overlay,
+	}
+
+	return storage.NewCompositeDataStore(storage.URLPathConstructor{}, storage.NewDefaultProtobufStore(rawStore, scope)), nil
+}
@@ -0,0 +1,40 @@
+package signedurl
+
+import (
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "signed-urls"
+
+var (
+	defaultConfig = &Config{
+		Enabled: false,
+		TTL:     config.Duration{Duration: time.Hour},
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls the signed URLs added to events, which let the console fetch outputs without bucket credentials.
+// Only s3 references are signed, with the credentials propeller finds in its environment.
+type Config struct {
+	Enabled        bool            `json:"enabled" pflag:",Enables adding signed URLs of outputs to task events."`
+	TTL            config.Duration `json:"ttl" pflag:",Duration for which signed URLs are valid."`
+	Region         string          `json:"region" pflag:",Region of the s3 buckets."`
+	Endpoint       string          `json:"endpoint" pflag:",Overrides the s3 endpoint, e.g. for minio."`
+	ForcePathStyle bool            `json:"force-path-style" pflag:",Uses path style URLs, required by some s3 compatible stores."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
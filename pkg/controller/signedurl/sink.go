@@ -0,0 +1,93 @@
+// Package signedurl adds time limited signed URLs of outputs to events, so that the console can fetch them without
+// credentials for the bucket. Only task events have room for them, in their custom info.
+package signedurl
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+const (
+	// OutputURLKey is the custom info key of the signed URL of the outputs of a task.
+	OutputURLKey = "signedOutputUrl"
+	// OutputURLExpiryKey is the custom info key of the time, in RFC 3339, after which the signed URL stops working.
+	OutputURLExpiryKey = "signedOutputUrlExpiresAt"
+)
+
+type sinkMetrics struct {
+	URLsSigned   labeled.Counter
+	SignFailures labeled.Counter
+}
+
+type eventSink struct {
+	events.EventSink
+	signer  URLSigner
+	ttl     time.Duration
+	metrics *sinkMetrics
+}
+
+func (s *eventSink) withSignedOutputURL(ctx context.Context, taskEvent *event.TaskExecutionEvent) *event.TaskExecutionEvent {
+	outputURI := taskEvent.GetOutputUri()
+	if len(outputURI) == 0 {
+		return taskEvent
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	signed, ok, err := s.signer.SignURL(ctx, storage.DataReference(outputURI), s.ttl)
+	if err != nil {
+		// The event is still sent, just without the signed URL.
+		logger.Warnf(ctx, "Failed to sign output URL [%v]. Error: %v", outputURI, err)
+		s.metrics.SignFailures.Inc(ctx)
+		return taskEvent
+	} else if !ok {
+		return taskEvent
+	}
+
+	annotated := proto.Clone(taskEvent).(*event.TaskExecutionEvent)
+	if annotated.CustomInfo == nil {
+		annotated.CustomInfo = &structpb.Struct{}
+	}
+
+	if annotated.CustomInfo.Fields == nil {
+		annotated.CustomInfo.Fields = map[string]*structpb.Value{}
+	}
+
+	annotated.CustomInfo.Fields[OutputURLKey] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: signed}}
+	annotated.CustomInfo.Fields[OutputURLExpiryKey] = &structpb.Value{
+		Kind: &structpb.Value_StringValue{StringValue: expiresAt.UTC().Format(time.RFC3339)},
+	}
+
+	s.metrics.URLsSigned.Inc(ctx)
+	return annotated
+}
+
+func (s *eventSink) Sink(ctx context.Context, message proto.Message) error {
+	if taskEvent, ok := message.(*event.TaskExecutionEvent); ok {
+		message = s.withSignedOutputURL(ctx, taskEvent)
+	}
+
+	return s.EventSink.Sink(ctx, message)
+}
+
+// NewEventSink wraps the given EventSink so that the outputs of task events are accompanied by a signed URL.
+func NewEventSink(sink events.EventSink, signer URLSigner, cfg *Config, scope promutils.Scope) events.EventSink {
+	return &eventSink{
+		EventSink: sink,
+		signer:    signer,
+		ttl:       cfg.TTL.Duration,
+		metrics: &sinkMetrics{
+			URLsSigned:   labeled.NewCounter("urls_signed", "Number of output URLs signed for events", scope),
+			SignFailures: labeled.NewCounter("sign_failures", "Number of output URLs that could not be signed", scope),
+		},
+	}
+}
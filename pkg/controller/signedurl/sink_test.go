@@ -0,0 +1,124 @@
+package signedurl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/testkit"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NamespaceKey)
+}
+
+type fakeSigner struct {
+	err error
+}
+
+func (f fakeSigner) SignURL(_ context.Context, reference storage.DataReference, ttl time.Duration) (string, bool, error) {
+	if !strings.HasPrefix(string(reference), "s3://") {
+		return "", false, nil
+	}
+
+	return fmt.Sprintf("https://signed/%v?ttl=%v", strings.TrimPrefix(string(reference), "s3://"), ttl), true, f.err
+}
+
+func newTaskEvent(outputURI string) *event.TaskExecutionEvent {
+	return &event.TaskExecutionEvent{
+		Phase:        core.TaskExecution_SUCCEEDED,
+		OutputResult: &event.TaskExecutionEvent_OutputUri{OutputUri: outputURI},
+	}
+}
+
+func TestEventSink_Sink(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &Config{Enabled: true, TTL: config.Duration{Duration: time.Minute}}
+
+	t.Run("signed", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		sink := NewEventSink(recorder, fakeSigner{}, cfg, promutils.NewTestScope())
+		original := newTaskEvent("s3://bucket/outputs.pb")
+
+		assert.NoError(t, sink.Sink(ctx, original))
+		if assert.Len(t, recorder.TaskEvents(), 1) {
+			fields := recorder.TaskEvents()[0].GetCustomInfo().GetFields()
+			assert.Equal(t, "https://signed/bucket/outputs.pb?ttl=1m0s", fields[OutputURLKey].GetStringValue())
+			assert.NotEmpty(t, fields[OutputURLExpiryKey].GetStringValue())
+		}
+
+		// The event of the caller is not modified.
+		assert.Nil(t, original.GetCustomInfo())
+	})
+
+	t.Run("unsupported store", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		sink := NewEventSink(recorder, fakeSigner{}, cfg, promutils.NewTestScope())
+
+		assert.NoError(t, sink.Sink(ctx, newTaskEvent("gs://bucket/outputs.pb")))
+		if assert.Len(t, recorder.TaskEvents(), 1) {
+			assert.Nil(t, recorder.TaskEvents()[0].GetCustomInfo())
+		}
+	})
+
+	t.Run("sign failure", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		sink := NewEventSink(recorder, fakeSigner{err: fmt.Errorf("no credentials")}, cfg, promutils.NewTestScope())
+
+		assert.NoError(t, sink.Sink(ctx, newTaskEvent("s3://bucket/outputs.pb")))
+		if assert.Len(t, recorder.TaskEvents(), 1) {
+			assert.Nil(t, recorder.TaskEvents()[0].GetCustomInfo())
+		}
+	})
+
+	t.Run("no outputs", func(t *testing.T) {
+		recorder := testkit.NewRecordingEventSink()
+		sink := NewEventSink(recorder, fakeSigner{}, cfg, promutils.NewTestScope())
+
+		assert.NoError(t, sink.Sink(ctx, &event.TaskExecutionEvent{Phase: core.TaskExecution_RUNNING}))
+		assert.NoError(t, sink.Sink(ctx, &event.WorkflowExecutionEvent{Phase: core.WorkflowExecution_RUNNING}))
+		assert.Len(t, recorder.TaskEvents(), 1)
+		assert.Len(t, recorder.WorkflowEvents(), 1)
+	})
+}
+
+func TestS3Signer_SignURL(t *testing.T) {
+	ctx := context.TODO()
+	for key, value := range map[string]string{"AWS_ACCESS_KEY_ID": "access", "AWS_SECRET_ACCESS_KEY": "secret"} {
+		previous, found := os.LookupEnv(key)
+		assert.NoError(t, os.Setenv(key, value))
+		defer func(key string) {
+			if found {
+				_ = os.Setenv(key, previous)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		}(key)
+	}
+
+	signer, err := NewS3Signer(&Config{Region: "us-east-1"})
+	assert.NoError(t, err)
+
+	signed, ok, err := signer.SignURL(ctx, "s3://bucket/metadata/outputs.pb", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Contains(t, signed, "bucket")
+	assert.Contains(t, signed, "metadata/outputs.pb")
+	assert.Contains(t, signed, "X-Amz-Expires=60")
+
+	_, ok, err = signer.SignURL(ctx, "gs://bucket/outputs.pb", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// StalledWorkflowThrottle lowers the re-evaluation frequency of workflows whose active nodes are all stalled, e.g.
+// waiting on resource quota or an image pull. Periodic resyncs of such workflows are enqueued at most once per
+// interval, which frees up workers for workflows that can make progress. Actual updates to a workflow and updates to
+// any of its sub-objects are never throttled.
+type StalledWorkflowThrottle struct {
+	interval     time.Duration
+	clock        clock.Clock
+	skipped      prometheus.Counter
+	lock         sync.Mutex
+	lastEnqueued map[string]time.Time
+}
+
+// ShouldEnqueue returns false if the periodic resync of the given workflow should be skipped.
+func (t *StalledWorkflowThrottle) ShouldEnqueue(w *v1alpha1.FlyteWorkflow, isResync bool) bool {
+	if t.interval <= 0 {
+		return true
+	}
+
+	key := w.GetK8sWorkflowID().String()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !w.Status.IsStalled() {
+		delete(t.lastEnqueued, key)
+		return true
+	}
+
+	now := t.clock.Now()
+	if last, ok := t.lastEnqueued[key]; ok && isResync && now.Sub(last) < t.interval {
+		t.skipped.Inc()
+		return false
+	}
+
+	t.lastEnqueued[key] = now
+	return true
+}
+
+// Forget drops any state tracked for the workflow with the given key.
+func (t *StalledWorkflowThrottle) Forget(key string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.lastEnqueued, key)
+}
+
+// ShedCache drops the state tracked for all workflows, which lets the next resync of every stalled workflow through.
+func (t *StalledWorkflowThrottle) ShedCache(_ context.Context) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.lastEnqueued = map[string]time.Time{}
+}
+
+func NewStalledWorkflowThrottle(interval time.Duration, clock clock.Clock, skipped prometheus.Counter) *StalledWorkflowThrottle {
+	return &StalledWorkflowThrottle{
+		interval:     interval,
+		clock:        clock,
+		skipped:      skipped,
+		lastEnqueued: map[string]time.Time{},
+	}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func newStalledTestWorkflow(stalledReason string) *v1alpha1.FlyteWorkflow {
+	return &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "wf"},
+		Status: v1alpha1.WorkflowStatus{
+			Phase: v1alpha1.WorkflowPhaseRunning,
+			NodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				"n1": {
+					Phase:          v1alpha1.NodePhaseRunning,
+					TaskNodeStatus: &v1alpha1.TaskNodeStatus{StalledReason: stalledReason},
+				},
+			},
+		},
+	}
+}
+
+func TestStalledWorkflowThrottle_ShouldEnqueue(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	skipped := promutils.NewTestScope().MustNewCounter("skipped", "")
+	throttle := NewStalledWorkflowThrottle(time.Minute, fakeClock, skipped)
+
+	t.Run("not-stalled", func(t *testing.T) {
+		w := newStalledTestWorkflow("")
+		assert.True(t, throttle.ShouldEnqueue(w, true))
+		assert.True(t, throttle.ShouldEnqueue(w, true))
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		w := newStalledTestWorkflow("ImagePullBackOff: Back-off pulling image")
+		assert.True(t, throttle.ShouldEnqueue(w, true))
+		assert.False(t, throttle.ShouldEnqueue(w, true))
+		// Updates are never throttled
+		assert.True(t, throttle.ShouldEnqueue(w, false))
+
+		fakeClock.Step(2 * time.Minute)
+		assert.True(t, throttle.ShouldEnqueue(w, true))
+		assert.False(t, throttle.ShouldEnqueue(w, true))
+		assert.Equal(t, float64(2), testutil.ToFloat64(skipped))
+
+		throttle.Forget(w.GetK8sWorkflowID().String())
+		assert.True(t, throttle.ShouldEnqueue(w, true))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := NewStalledWorkflowThrottle(0, fakeClock, skipped)
+		w := newStalledTestWorkflow("ResourceQuotaWait: quota exceeded")
+		assert.True(t, disabled.ShouldEnqueue(w, true))
+		assert.True(t, disabled.ShouldEnqueue(w, true))
+	})
+}
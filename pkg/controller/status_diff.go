@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+// Value of the fields of a status snapshot that are not set.
+const statusFieldUnset = "<unset>"
+
+type statusDiffMetrics struct {
+	ChangedFields  labeled.Counter
+	FlappingFields labeled.Counter
+}
+
+// statusSnapshot holds the value of every leaf field of a workflow status, by path, e.g. nodeStatus.n0.phase.
+type statusSnapshot map[string]string
+
+// StatusDiffingWorkflowExecutor diffs the status of each workflow before and after every round it evaluates, and reports
+// the fields that go back to a value they had in one of the previous rounds. It is a debug mode to find the handlers that
+// do not converge, it flattens the whole status twice per round.
+type StatusDiffingWorkflowExecutor struct {
+	executors.Workflow
+	rounds  int
+	metrics statusDiffMetrics
+	// Snapshots of the previous rounds by workflow, the oldest first. The least recently evaluated workflows lose theirs
+	// if there are too many.
+	history *lru.Cache
+}
+
+func (e *StatusDiffingWorkflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+	key := w.GetK8sWorkflowID().String()
+	before, err := newStatusSnapshot(&w.Status)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to snapshot the status of the workflow, skipping the diff. Error: %v", err)
+		return e.Workflow.HandleFlyteWorkflow(ctx, w)
+	}
+
+	handleErr := e.Workflow.HandleFlyteWorkflow(ctx, w)
+	if w.GetExecutionStatus().IsTerminated() {
+		e.history.Remove(key)
+		return handleErr
+	}
+
+	after, err := newStatusSnapshot(&w.Status)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to snapshot the status of the workflow, skipping the diff. Error: %v", err)
+		return handleErr
+	}
+
+	var previous []statusSnapshot
+	if h, ok := e.history.Get(key); ok {
+		previous = h.([]statusSnapshot)
+	}
+
+	e.diff(ctx, previous, before, after)
+
+	previous = append(previous, before)
+	if len(previous) > e.rounds {
+		previous = previous[len(previous)-e.rounds:]
+	}
+
+	e.history.Add(key, previous)
+	return handleErr
+}
+
+// Logs the fields the round changed, and reports those that went back to a value they had in one of the previous rounds
+// as flapping.
+func (e *StatusDiffingWorkflowExecutor) diff(ctx context.Context, previous []statusSnapshot, before, after statusSnapshot) {
+	changed := before.changedFields(after)
+	if len(changed) == 0 {
+		return
+	}
+
+	e.metrics.ChangedFields.Add(ctx, float64(len(changed)))
+	logger.Debugf(ctx, "Round changed [%d] status fields: %v", len(changed), changed)
+
+	for _, path := range changed {
+		value := after.get(path)
+		for i := len(previous) - 1; i >= 0; i-- {
+			if previous[i].get(path) == value {
+				e.metrics.FlappingFields.Inc(ctx)
+				logger.Warnf(ctx, "Status field [%v] flapped from [%v] back to [%v], which it had in one of the previous rounds",
+					path, before.get(path), value)
+				break
+			}
+		}
+	}
+}
+
+func (s statusSnapshot) get(path string) string {
+	if value, ok := s[path]; ok {
+		return value
+	}
+
+	return statusFieldUnset
+}
+
+// changedFields returns the sorted paths of the fields whose value differs in the other snapshot.
+func (s statusSnapshot) changedFields(other statusSnapshot) []string {
+	changed := make([]string, 0)
+	for path, value := range s {
+		if other.get(path) != value {
+			changed = append(changed, path)
+		}
+	}
+
+	for path := range other {
+		if _, ok := s[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+func newStatusSnapshot(status *v1alpha1.WorkflowStatus) (statusSnapshot, error) {
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, err
+	}
+
+	snapshot := make(statusSnapshot)
+	flattenStatus("", tree, snapshot)
+	return snapshot, nil
+}
+
+func flattenStatus(path string, node interface{}, snapshot statusSnapshot) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, child := range n {
+			flattenStatus(strings.TrimPrefix(path+"."+key, "."), child, snapshot)
+		}
+	case []interface{}:
+		for i, child := range n {
+			flattenStatus(fmt.Sprintf("%v[%d]", path, i), child, snapshot)
+		}
+	default:
+		snapshot[path] = fmt.Sprintf("%v", n)
+	}
+}
+
+// ShedCache drops the snapshots of the previous rounds of all the workflows.
+func (e *StatusDiffingWorkflowExecutor) ShedCache(ctx context.Context) {
+	logger.Infof(ctx, "Dropping the status snapshots of [%d] workflows", e.history.Len())
+	e.history.Purge()
+}
+
+// NewStatusDiffingWorkflowExecutor wraps the given executor to diff the status of the workflows it evaluates.
+func NewStatusDiffingWorkflowExecutor(cfg config.StatusDiffConfig, executor executors.Workflow, scope promutils.Scope) (*StatusDiffingWorkflowExecutor, error) {
+	history, err := lru.New(cfg.MaxWorkflows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusDiffingWorkflowExecutor{
+		Workflow: executor,
+		rounds:   cfg.Rounds,
+		metrics: statusDiffMetrics{
+			ChangedFields:  labeled.NewCounter("changed_fields", "Number of status fields changed by rounds", scope, labeled.EmitUnlabeledMetric),
+			FlappingFields: labeled.NewCounter("flapping_fields", "Number of status fields that went back to a value they had in one of the previous rounds", scope, labeled.EmitUnlabeledMetric),
+		},
+		history: history,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors/mocks"
+)
+
+func TestStatusDiffingWorkflowExecutor(t *testing.T) {
+	ctx := context.TODO()
+	w := &v1alpha1.FlyteWorkflow{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "wf"}}
+	w.Status.UpdatePhase(v1alpha1.WorkflowPhaseRunning, "", nil)
+
+	// Sets the message of the workflow in every round.
+	handleTo := func(message string) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			args.Get(1).(*v1alpha1.FlyteWorkflow).Status.SetMessage(message)
+		}
+	}
+
+	wfExec := &mocks.Workflow{}
+	e, err := NewStatusDiffingWorkflowExecutor(config.StatusDiffConfig{Rounds: 2, MaxWorkflows: 10}, wfExec,
+		promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	snapshot, err := newStatusSnapshot(&w.Status)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%v", int(v1alpha1.WorkflowPhaseRunning)), snapshot.get("phase"))
+	assert.Equal(t, statusFieldUnset, snapshot.get("message"))
+
+	for _, message := range []string{"waiting", "running", "waiting"} {
+		wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(handleTo(message)).Return(nil).Once()
+		assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+	}
+
+	// The message went back to waiting.
+	assert.Equal(t, float64(1), testutil.ToFloat64(e.metrics.FlappingFields.Counter))
+	assert.Equal(t, float64(3), testutil.ToFloat64(e.metrics.ChangedFields.Counter))
+
+	// Steady rounds change nothing.
+	wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Return(nil).Once()
+	assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+	assert.Equal(t, float64(1), testutil.ToFloat64(e.metrics.FlappingFields.Counter))
+	assert.Equal(t, 1, e.history.Len())
+
+	// The snapshots of terminated workflows are dropped.
+	wfExec.OnHandleFlyteWorkflowMatch(mock.Anything, w).Run(func(args mock.Arguments) {
+		args.Get(1).(*v1alpha1.FlyteWorkflow).Status.UpdatePhase(v1alpha1.WorkflowPhaseSuccess, "", nil)
+	}).Return(nil).Once()
+	assert.NoError(t, e.HandleFlyteWorkflow(ctx, w))
+	assert.Equal(t, 0, e.history.Len())
+}
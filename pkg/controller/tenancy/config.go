@@ -0,0 +1,56 @@
+package tenancy
+
+import (
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "tenancy"
+
+var (
+	defaultConfig = &Config{
+		Enabled: false,
+		Tenants: []Tenant{},
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config separates the data of tenants, i.e. projects or project domains, in the metadata store. The inputs, outputs
+// and other documents propeller writes for the executions of a tenant go to the container of the tenant, which is only
+// accessed with the credentials of the tenant. Executions of other projects or domains are denied access to it. The
+// credentials of a tenant are read once, when they are first needed, propeller has to be restarted to rotate them.
+type Config struct {
+	Enabled bool `json:"enabled" pflag:",Enables separating the data of tenants in the metadata store."`
+	// The most specific tenant of an execution applies, a tenant with a domain is more specific than one without.
+	Tenants []Tenant `json:"tenants" pflag:"-,Tenants and their containers in the metadata store."`
+}
+
+// Tenant is the project, or the domain of a project, whose data is kept in its own container.
+type Tenant struct {
+	Project string `json:"project"`
+	// Applies to all the domains of the project if empty.
+	Domain string `json:"domain,omitempty"`
+	// Container, e.g. the S3 bucket, the data of the tenant is written to.
+	Container string `json:"container"`
+	// Secret that holds the credentials of the tenant, the default credentials are used if it is not set.
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+}
+
+// SecretRef references a K8s secret whose keys are set in the stow config of the datastore of a tenant, e.g.
+// access_key_id and secret_key for S3.
+type SecretRef struct {
+	// Namespace of the secret, the namespace of the executions of the tenant, as mapped by the namespace mapping of
+	// propeller, if empty.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,273 @@
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
+)
+
+// Stow config keys of the legacy S3 connection config.
+const (
+	accessKeyIDKey = "access_key_id"
+	secretKeyKey   = "secret_key"
+)
+
+type datastoreMetrics struct {
+	AccessDenied      prometheus.Counter
+	CredentialsLoaded prometheus.Counter
+	CredentialsFailed prometheus.Counter
+}
+
+type tenantStoreKey struct {
+	tenant    int
+	namespace string
+}
+
+// tenantRawStore routes every call to the store of the tenant that owns the container of the reference, or to the
+// default store if no tenant owns it. Executions, as identified by the project and domain in the context, are denied
+// access to the containers of the tenants they do not belong to. Calls without an execution in the context, e.g. from
+// the garbage collector, are allowed.
+type tenantRawStore struct {
+	storage.RawStore
+	cfg        Config
+	storageCfg storage.Config
+	// Maps the project and domain of executions to the namespace their secrets are read from.
+	namespaceTemplate string
+	secrets           corev1.SecretsGetter
+	newRawStore       func(cfg *storage.Config, scope promutils.Scope) (storage.RawStore, error)
+	scope             promutils.Scope
+	lock              sync.Mutex
+	stores            map[tenantStoreKey]storage.RawStore
+	metrics           datastoreMetrics
+}
+
+// Returns the index of the most specific tenant of the project and domain, or -1 if there is none.
+func (s *tenantRawStore) tenantOf(project, domain string) int {
+	found := -1
+	for i, t := range s.cfg.Tenants {
+		if t.Project != project || (len(t.Domain) > 0 && t.Domain != domain) {
+			continue
+		}
+
+		if found < 0 || len(t.Domain) > 0 {
+			found = i
+		}
+	}
+
+	return found
+}
+
+// Returns the index of the tenant that owns the container, or -1 if there is none.
+func (s *tenantRawStore) ownerOf(container string) int {
+	for i, t := range s.cfg.Tenants {
+		if t.Container == container {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Returns the store to access the reference with in the given context.
+func (s *tenantRawStore) storeFor(ctx context.Context, reference storage.DataReference) (storage.RawStore, error) {
+	_, container, _, err := reference.Split()
+	if err != nil {
+		return nil, err
+	}
+
+	owner := s.ownerOf(container)
+	if owner < 0 {
+		return s.RawStore, nil
+	}
+
+	project, _ := ctx.Value(contextutils.ProjectKey).(string)
+	domain, _ := ctx.Value(contextutils.DomainKey).(string)
+	if len(project) > 0 && s.tenantOf(project, domain) != owner {
+		s.metrics.AccessDenied.Inc()
+		return nil, fmt.Errorf("executions of project [%v] domain [%v] are denied access to [%v]", project, domain, reference)
+	}
+
+	return s.getTenantStore(ctx, owner, project, domain)
+}
+
+// Returns the store of the tenant, creating it with the credentials in its secret the first time. Tenants whose secret
+// lives in the namespace of their executions get a store per namespace.
+func (s *tenantRawStore) getTenantStore(ctx context.Context, tenant int, project, domain string) (storage.RawStore, error) {
+	t := s.cfg.Tenants[tenant]
+	key := tenantStoreKey{tenant: tenant}
+	if t.SecretRef != nil {
+		key.namespace = t.SecretRef.Namespace
+		if len(key.namespace) == 0 {
+			if len(project) == 0 || len(domain) == 0 {
+				return nil, fmt.Errorf("the namespace of the secret of project [%v] is unknown without an execution", t.Project)
+			}
+
+			key.namespace = utils.GetNamespaceName(s.namespaceTemplate, project, domain)
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if store, ok := s.stores[key]; ok {
+		return store, nil
+	}
+
+	cfg := s.storageCfg
+	cfg.InitContainer = t.Container
+	if t.SecretRef != nil {
+		secret, err := s.secrets.Secrets(key.namespace).Get(ctx, t.SecretRef.Name, v1.GetOptions{})
+		if err != nil {
+			s.metrics.CredentialsFailed.Inc()
+			return nil, fmt.Errorf("failed to read the credentials of project [%v] from secret [%v/%v]: %w",
+				t.Project, key.namespace, t.SecretRef.Name, err)
+		}
+
+		if cfg.Stow != nil {
+			stowCfg := *cfg.Stow
+			stowCfg.Config = make(map[string]string, len(cfg.Stow.Config)+len(secret.Data))
+			for k, v := range cfg.Stow.Config {
+				stowCfg.Config[k] = v
+			}
+
+			for k, v := range secret.Data {
+				stowCfg.Config[k] = string(v)
+			}
+
+			cfg.Stow = &stowCfg
+		} else {
+			cfg.Connection.AuthType = "accesskey"
+			cfg.Connection.AccessKey = string(secret.Data[accessKeyIDKey])
+			cfg.Connection.SecretKey = string(secret.Data[secretKeyKey])
+		}
+	}
+
+	// Metric names cannot contain the names of projects, every store gets its own scope.
+	store, err := s.newRawStore(&cfg, s.scope.NewSubScope(fmt.Sprintf("tenant_%d", len(s.stores))))
+	if err != nil {
+		s.metrics.CredentialsFailed.Inc()
+		return nil, fmt.Errorf("failed to create the datastore of project [%v]: %w", t.Project, err)
+	}
+
+	logger.Infof(ctx, "Created the datastore of project [%v] domain [%v] for container [%v]", t.Project, t.Domain, t.Container)
+	s.metrics.CredentialsLoaded.Inc()
+	s.stores[key] = store
+	return store, nil
+}
+
+// GetBaseContainerFQN returns the container of the tenant of the execution in the context, so that the data of its
+// executions is written there.
+func (s *tenantRawStore) GetBaseContainerFQN(ctx context.Context) storage.DataReference {
+	project, _ := ctx.Value(contextutils.ProjectKey).(string)
+	domain, _ := ctx.Value(contextutils.DomainKey).(string)
+	tenant := s.tenantOf(project, domain)
+	if tenant < 0 {
+		return s.RawStore.GetBaseContainerFQN(ctx)
+	}
+
+	store, err := s.getTenantStore(ctx, tenant, project, domain)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get the datastore of the tenant, using the default container. Error: %v", err)
+		return s.RawStore.GetBaseContainerFQN(ctx)
+	}
+
+	return store.GetBaseContainerFQN(ctx)
+}
+
+func (s *tenantRawStore) Head(ctx context.Context, reference storage.DataReference) (storage.Metadata, error) {
+	store, err := s.storeFor(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Head(ctx, reference)
+}
+
+func (s *tenantRawStore) ReadRaw(ctx context.Context, reference storage.DataReference) (io.ReadCloser, error) {
+	store, err := s.storeFor(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.ReadRaw(ctx, reference)
+}
+
+func (s *tenantRawStore) WriteRaw(ctx context.Context, reference storage.DataReference, size int64, opts storage.Options,
+	raw io.Reader) error {
+
+	store, err := s.storeFor(ctx, reference)
+	if err != nil {
+		return err
+	}
+
+	return store.WriteRaw(ctx, reference, size, opts, raw)
+}
+
+// CopyRaw copies within the store of the destination, the source has to be readable with the same credentials.
+func (s *tenantRawStore) CopyRaw(ctx context.Context, source, destination storage.DataReference, opts storage.Options) error {
+	if _, err := s.storeFor(ctx, source); err != nil {
+		return err
+	}
+
+	store, err := s.storeFor(ctx, destination)
+	if err != nil {
+		return err
+	}
+
+	return store.CopyRaw(ctx, source, destination, opts)
+}
+
+// NewDataStore wraps the datastore so that the data of every tenant is read and written in its own container, with
+// its own credentials, based on the project and domain of the execution in the context. Every node of an execution
+// thus accesses the store of its tenant. The datastores of the tenants are created from the given storage config.
+func NewDataStore(cfg Config, namespaceMapping ctrlConfig.NamespaceMappingConfig, storageCfg *storage.Config,
+	store *storage.DataStore, secrets corev1.SecretsGetter, scope promutils.Scope) (*storage.DataStore, error) {
+
+	for _, t := range cfg.Tenants {
+		if len(t.Project) == 0 || len(t.Container) == 0 {
+			return nil, fmt.Errorf("tenants require a project and a container, found project [%v] container [%v]",
+				t.Project, t.Container)
+		}
+	}
+
+	if err := utils.ValidateNamespaceTemplate(namespaceMapping.Template); err != nil {
+		return nil, err
+	}
+
+	rawStore := &tenantRawStore{
+		RawStore:          store.ComposedProtobufStore,
+		cfg:               cfg,
+		storageCfg:        *storageCfg,
+		namespaceTemplate: namespaceMapping.Template,
+		secrets:           secrets,
+		newRawStore: func(cfg *storage.Config, scope promutils.Scope) (storage.RawStore, error) {
+			s, err := storage.NewDataStore(cfg, scope)
+			if err != nil {
+				return nil, err
+			}
+
+			return s.ComposedProtobufStore, nil
+		},
+		scope:  scope,
+		stores: map[tenantStoreKey]storage.RawStore{},
+		metrics: datastoreMetrics{
+			AccessDenied:      scope.MustNewCounter("access_denied", "Number of accesses denied to the data of another tenant"),
+			CredentialsLoaded: scope.MustNewCounter("credentials_loaded", "Number of times the credentials of a tenant were loaded"),
+			CredentialsFailed: scope.MustNewCounter("credentials_failed", "Number of failures to load the credentials of a tenant"),
+		},
+	}
+
+	return storage.NewCompositeDataStore(store.ReferenceConstructor, storage.NewDefaultProtobufStore(rawStore, scope)), nil
+}
@@ -0,0 +1,164 @@
+package tenancy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.NamespaceKey)
+}
+
+type fakeRawStore struct {
+	storage.RawStore
+	cfg storage.Config
+}
+
+func (s fakeRawStore) GetBaseContainerFQN(context.Context) storage.DataReference {
+	return storage.DataReference("s3://" + s.cfg.InitContainer)
+}
+
+func newTestDataStore(t *testing.T, cfg Config) (*storage.DataStore, *tenantRawStore) {
+	storageCfg := &storage.Config{
+		Type:          storage.TypeMemory,
+		InitContainer: "default",
+		Stow:          &storage.StowConfig{Kind: "s3", Config: map[string]string{"region": "us-east-1"}},
+	}
+	defaultStore, err := storage.NewDataStore(storageCfg, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	secrets := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Namespace: "a-production", Name: "creds"},
+		Data:       map[string][]byte{"access_key_id": []byte("id"), "secret_key": []byte("secret")},
+	}).CoreV1()
+
+	namespaceMapping := ctrlConfig.NamespaceMappingConfig{Template: "{{ project }}-{{ domain }}"}
+	store, err := NewDataStore(cfg, namespaceMapping, storageCfg, defaultStore, secrets, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	rawStore := store.ComposedProtobufStore.(storage.DefaultProtobufStore).RawStore.(*tenantRawStore)
+	rawStore.RawStore = fakeRawStore{RawStore: rawStore.RawStore, cfg: *storageCfg}
+	rawStore.newRawStore = func(cfg *storage.Config, scope promutils.Scope) (storage.RawStore, error) {
+		s, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		return fakeRawStore{RawStore: s.ComposedProtobufStore, cfg: *cfg}, nil
+	}
+
+	return store, rawStore
+}
+
+func TestNewDataStore(t *testing.T) {
+	ctx := context.TODO()
+	cfg := Config{
+		Enabled: true,
+		Tenants: []Tenant{
+			{Project: "a", Container: "tenant-a"},
+			{Project: "a", Domain: "production", Container: "tenant-a-prod", SecretRef: &SecretRef{Name: "creds"}},
+			{Project: "b", Container: "tenant-b"},
+		},
+	}
+
+	aDev := contextutils.WithProjectDomain(ctx, "a", "development")
+	aProd := contextutils.WithProjectDomain(ctx, "a", "production")
+	b := contextutils.WithProjectDomain(ctx, "b", "development")
+	c := contextutils.WithProjectDomain(ctx, "c", "development")
+
+	t.Run("base-container", func(t *testing.T) {
+		store, _ := newTestDataStore(t, cfg)
+		assert.Equal(t, storage.DataReference("s3://tenant-a"), store.GetBaseContainerFQN(aDev))
+		assert.Equal(t, storage.DataReference("s3://tenant-a-prod"), store.GetBaseContainerFQN(aProd))
+		assert.Equal(t, storage.DataReference("s3://tenant-b"), store.GetBaseContainerFQN(b))
+		assert.Equal(t, storage.DataReference("s3://default"), store.GetBaseContainerFQN(c))
+		assert.Equal(t, storage.DataReference("s3://default"), store.GetBaseContainerFQN(ctx))
+	})
+
+	t.Run("separated", func(t *testing.T) {
+		store, rawStore := newTestDataStore(t, cfg)
+		ref := storage.DataReference("s3://tenant-a/metadata/x")
+		assert.NoError(t, store.WriteRaw(aDev, ref, 3, storage.Options{}, bytes.NewReader([]byte("abc"))))
+
+		md, err := store.Head(aDev, ref)
+		assert.NoError(t, err)
+		assert.True(t, md.Exists())
+
+		// Only in the store of the tenant.
+		md, err = rawStore.RawStore.Head(ctx, ref)
+		assert.NoError(t, err)
+		assert.False(t, md.Exists())
+
+		// Without an execution, e.g. from the garbage collector.
+		md, err = store.Head(ctx, ref)
+		assert.NoError(t, err)
+		assert.True(t, md.Exists())
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		store, _ := newTestDataStore(t, cfg)
+		ref := storage.DataReference("s3://tenant-a/metadata/x")
+		for _, ctx := range []context.Context{aProd, b, c} {
+			_, err := store.ReadRaw(ctx, ref)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "denied")
+			assert.Error(t, store.WriteRaw(ctx, ref, 3, storage.Options{}, bytes.NewReader([]byte("abc"))))
+			assert.Error(t, store.CopyRaw(ctx, ref, "s3://default/x", storage.Options{}))
+		}
+	})
+
+	t.Run("default-container", func(t *testing.T) {
+		store, rawStore := newTestDataStore(t, cfg)
+		ref := storage.DataReference("s3://default/inputs.pb")
+		assert.NoError(t, store.WriteRaw(aDev, ref, 3, storage.Options{}, bytes.NewReader([]byte("abc"))))
+
+		md, err := rawStore.RawStore.Head(ctx, ref)
+		assert.NoError(t, err)
+		assert.True(t, md.Exists())
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		store, rawStore := newTestDataStore(t, cfg)
+		assert.Equal(t, storage.DataReference("s3://tenant-a-prod"), store.GetBaseContainerFQN(aProd))
+
+		tenantStore := rawStore.stores[tenantStoreKey{tenant: 1, namespace: "a-production"}].(fakeRawStore)
+		assert.Equal(t, map[string]string{"region": "us-east-1", "access_key_id": "id", "secret_key": "secret"},
+			tenantStore.cfg.Stow.Config)
+		// The default config is left untouched.
+		assert.Equal(t, map[string]string{"region": "us-east-1"}, rawStore.storageCfg.Stow.Config)
+	})
+
+	t.Run("missing-secret", func(t *testing.T) {
+		missing := Config{Tenants: []Tenant{{Project: "a", Container: "tenant-a", SecretRef: &SecretRef{Name: "missing"}}}}
+		store, _ := newTestDataStore(t, missing)
+		_, err := store.ReadRaw(aDev, "s3://tenant-a/x")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "a-development/missing")
+	})
+
+	t.Run("namespace-mapping", func(t *testing.T) {
+		missing := Config{Tenants: []Tenant{{Project: "a", Container: "tenant-a", SecretRef: &SecretRef{Name: "missing"}}}}
+		store, rawStore := newTestDataStore(t, missing)
+		rawStore.namespaceTemplate = "flyte-{{ domain }}"
+		_, err := store.ReadRaw(aDev, "s3://tenant-a/x")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "flyte-development/missing")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewDataStore(Config{Tenants: []Tenant{{Project: "a"}}}, ctrlConfig.NamespaceMappingConfig{}, &storage.Config{}, nil, nil, promutils.NewTestScope())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "tracing"
+
+var (
+	defaultConfig = &Config{
+		Enabled:               false,
+		TraceParentAnnotation: "flyte.org/traceparent",
+		OpenMetricsPath:       "/openmetrics",
+	}
+
+	configSection = ctrlConfig.MustRegisterSubSection(configSectionKey, defaultConfig)
+)
+
+// Config controls linking the latency metrics of propeller to the traces of the executions they measure. Propeller does
+// not emit traces itself, it picks up the trace context the execution was created with.
+type Config struct {
+	Enabled               bool   `json:"enabled" pflag:",Attaches the trace ID of executions as exemplars to the latency histograms."`
+	TraceParentAnnotation string `json:"trace-parent-annotation" pflag:",Annotation of FlyteWorkflows that holds the W3C traceparent of the execution."`
+	OpenMetricsPath       string `json:"open-metrics-path" pflag:",Path of the profiler port that serves the metrics in the OpenMetrics format, which is the only one that carries exemplars."`
+}
+
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return configSection.SetConfig(cfg)
+}
@@ -0,0 +1,101 @@
+// Package tracing links the latency metrics of propeller to the traces of the executions they measure, by attaching the
+// trace ID of the execution, along with its execution and node IDs, as exemplars to the observations of histograms.
+package tracing
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	traceIDLabel = "trace_id"
+	execIDLabel  = "exec_id"
+	nodeIDLabel  = "node_id"
+
+	invalidTraceID = "00000000000000000000000000000000"
+)
+
+// The execution tags of the context attached to exemplars, by priority.
+var executionTags = []struct {
+	label string
+	key   contextutils.Key
+}{
+	{label: execIDLabel, key: contextutils.ExecIDKey},
+	{label: nodeIDLabel, key: contextutils.NodeIDKey},
+}
+
+type traceIDKey struct{}
+
+// WithTraceID returns a context whose observations carry the given trace ID.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// GetTraceID returns the trace ID of the context, if it has one.
+func GetTraceID(ctx context.Context) (string, bool) {
+	traceID, ok := ctx.Value(traceIDKey{}).(string)
+	return traceID, ok && len(traceID) > 0
+}
+
+// ParseTraceParent returns the trace ID of a W3C traceparent, formatted as {version}-{trace-id}-{parent-id}-{flags}.
+func ParseTraceParent(traceParent string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[1]) != len(invalidTraceID) || parts[1] == invalidTraceID {
+		return "", false
+	}
+
+	for _, c := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return "", false
+		}
+	}
+
+	return parts[1], true
+}
+
+// WithWorkflowTrace returns a context that carries the trace ID of the given workflow, if tracing is enabled and the
+// workflow was created with a trace context.
+func WithWorkflowTrace(ctx context.Context, w v1.Object) context.Context {
+	cfg := GetConfig()
+	if !cfg.Enabled {
+		return ctx
+	}
+
+	if traceID, ok := ParseTraceParent(w.GetAnnotations()[cfg.TraceParentAnnotation]); ok {
+		return WithTraceID(ctx, traceID)
+	}
+
+	return ctx
+}
+
+// Observe records the value in the given observer, with the trace ID of the context and the execution and node IDs as
+// exemplar if the context has a trace ID. The IDs that do not fit in the exemplar are left out.
+func Observe(ctx context.Context, o prometheus.Observer, value float64) {
+	traceID, ok := GetTraceID(ctx)
+	exemplarObserver, isExemplarObserver := o.(prometheus.ExemplarObserver)
+	if !ok || !isExemplarObserver {
+		o.Observe(value)
+		return
+	}
+
+	labels := prometheus.Labels{traceIDLabel: traceID}
+	length := utf8.RuneCountInString(traceIDLabel + traceID)
+	for _, tag := range executionTags {
+		id := contextutils.Value(ctx, tag.key)
+		if len(id) == 0 {
+			continue
+		}
+
+		if l := utf8.RuneCountInString(tag.label + id); length+l <= prometheus.ExemplarMaxRunes {
+			labels[tag.label] = id
+			length += l
+		}
+	}
+
+	exemplarObserver.ObserveWithExemplar(value, labels)
+}
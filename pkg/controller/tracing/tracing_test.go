@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+type observer struct {
+	value    float64
+	exemplar prometheus.Labels
+}
+
+func (o *observer) Observe(value float64) {
+	o.value = value
+}
+
+func (o *observer) ObserveWithExemplar(value float64, exemplar prometheus.Labels) {
+	o.value = value
+	o.exemplar = exemplar
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceParent string
+		traceID     string
+		ok          bool
+	}{
+		{"valid", "00-" + traceID + "-00f067aa0ba902b7-01", traceID, true},
+		{"empty", "", "", false},
+		{"too few parts", "00-" + traceID, "", false},
+		{"short trace ID", "00-4bf92f3577b34da6-00f067aa0ba902b7-01", "", false},
+		{"uppercase trace ID", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "", false},
+		{"invalid trace ID", "00-" + invalidTraceID + "-00f067aa0ba902b7-01", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := ParseTraceParent(tt.traceParent)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.traceID, id)
+		})
+	}
+}
+
+func TestWithWorkflowTrace(t *testing.T) {
+	ctx := context.TODO()
+	w := &v1.ObjectMeta{Annotations: map[string]string{
+		defaultConfig.TraceParentAnnotation: "00-" + traceID + "-00f067aa0ba902b7-01",
+	}}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, ok := GetTraceID(WithWorkflowTrace(ctx, w))
+		assert.False(t, ok)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		cfg := GetConfig()
+		enabled := *cfg
+		enabled.Enabled = true
+		assert.NoError(t, SetConfig(&enabled))
+		defer func() { assert.NoError(t, SetConfig(cfg)) }()
+
+		id, ok := GetTraceID(WithWorkflowTrace(ctx, w))
+		assert.True(t, ok)
+		assert.Equal(t, traceID, id)
+
+		_, ok = GetTraceID(WithWorkflowTrace(ctx, &v1.ObjectMeta{}))
+		assert.False(t, ok)
+	})
+}
+
+func TestObserve(t *testing.T) {
+	ctx := contextutils.WithNodeID(context.TODO(), "n0")
+
+	t.Run("no trace", func(t *testing.T) {
+		o := &observer{}
+		Observe(ctx, o, 1)
+		assert.Equal(t, float64(1), o.value)
+		assert.Nil(t, o.exemplar)
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		o := &observer{}
+		Observe(WithTraceID(contextutils.WithExecutionID(ctx, "exec"), traceID), o, 1)
+		assert.Equal(t, float64(1), o.value)
+		assert.Equal(t, prometheus.Labels{traceIDLabel: traceID, execIDLabel: "exec", nodeIDLabel: "n0"}, o.exemplar)
+	})
+
+	t.Run("execution ID too long", func(t *testing.T) {
+		o := &observer{}
+		Observe(WithTraceID(contextutils.WithExecutionID(ctx, "an-execution-id-that-does-not-fit"), traceID), o, 1)
+		assert.Equal(t, prometheus.Labels{traceIDLabel: traceID, nodeIDLabel: "n0"}, o.exemplar)
+	})
+
+	t.Run("histogram", func(t *testing.T) {
+		h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency"})
+		assert.NotPanics(t, func() {
+			Observe(WithTraceID(contextutils.WithExecutionID(ctx, "exec"), traceID), h, 1)
+		})
+	})
+}
@@ -4,14 +4,16 @@ import (
 	"context"
 	"fmt"
 	"runtime/pprof"
+	"sync/atomic"
 	"time"
 
 	"github.com/flyteorg/flytestdlib/contextutils"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 type Handler interface {
@@ -21,6 +23,9 @@ type Handler interface {
 	Handle(ctx context.Context, namespace, key string) error
 }
 
+// How often a worker that is above the active worker limit checks whether it may resume.
+const limitedWorkerPollInterval = time.Second
+
 type workerPoolMetrics struct {
 	Scope            promutils.Scope
 	FreeWorkers      prometheus.Gauge
@@ -28,12 +33,36 @@ type workerPoolMetrics struct {
 	RoundError       prometheus.Counter
 	RoundSuccess     prometheus.Counter
 	WorkersRestarted prometheus.Counter
+	ActiveWorkers    prometheus.Gauge
 }
 
 type WorkerPool struct {
 	workQueue CompositeWorkQueue
 	metrics   workerPoolMetrics
 	handler   Handler
+	locks     *workflowLockRegistry
+	// activeLimit caps the number of workers that pick up work items, 0 means all workers are active.
+	activeLimit int32
+	// Number of started and free workers, mirrors of the worker gauges that can be read back by the load reporter.
+	workers     int32
+	freeWorkers int32
+	// Number and total latency of the rounds since the load reporter last collected them.
+	rounds       int64
+	roundLatency int64
+}
+
+// SetActiveWorkerLimit limits the number of workers that pick up new work items, the remaining workers idle until the
+// limit is raised again. Workers finish the item they are processing before they idle. A limit of 0 or less lifts it.
+func (w *WorkerPool) SetActiveWorkerLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	atomic.StoreInt32(&w.activeLimit, int32(limit))
+}
+
+func (w *WorkerPool) isLimited(worker int) bool {
+	limit := atomic.LoadInt32(&w.activeLimit)
+	return limit > 0 && int32(worker) >= limit
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
@@ -42,7 +71,11 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := w.workQueue.Get()
 
 	w.metrics.FreeWorkers.Dec()
-	defer w.metrics.FreeWorkers.Inc()
+	atomic.AddInt32(&w.freeWorkers, -1)
+	defer func() {
+		w.metrics.FreeWorkers.Inc()
+		atomic.AddInt32(&w.freeWorkers, 1)
+	}()
 
 	if shutdown {
 		return false
@@ -74,7 +107,12 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 		}
 
 		t := w.metrics.PerRoundTimer.Start()
-		defer t.Stop()
+		start := time.Now()
+		defer func() {
+			t.Stop()
+			atomic.AddInt64(&w.rounds, 1)
+			atomic.AddInt64(&w.roundLatency, int64(time.Since(start)))
+		}()
 
 		// Convert the namespace/name string into a distinct namespace and name
 		namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -84,6 +122,10 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 		}
 		ctx = contextutils.WithNamespace(ctx, namespace)
 		ctx = contextutils.WithExecutionID(ctx, name)
+		// The same key may be handed to another worker while this one processes it, e.g. when it is requeued into
+		// another queue of the composite workqueue. Wait for that worker, so the workflow is evaluated by one worker at a time.
+		unlock := w.locks.Lock(key)
+		defer unlock()
 		// Reconcile the Workflow
 		if err := w.handler.Handle(ctx, namespace, name); err != nil {
 			w.metrics.RoundError.Inc()
@@ -109,13 +151,44 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (w *WorkerPool) runWorker(ctx context.Context) {
+func (w *WorkerPool) runWorker(ctx context.Context, worker int) {
 	logger.Infof(ctx, "Started Worker")
 	defer logger.Infof(ctx, "Exiting Worker")
-	for w.processNextWorkItem(ctx) {
+	for {
+		if w.isLimited(worker) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(limitedWorkerPollInterval):
+				continue
+			}
+		}
+
+		w.metrics.ActiveWorkers.Inc()
+		ok := w.processNextWorkItem(ctx)
+		w.metrics.ActiveWorkers.Dec()
+		if !ok {
+			return
+		}
 	}
 }
 
+// Returns the number of started and free workers.
+func (w *WorkerPool) workerCounts() (workers, free int) {
+	return int(atomic.LoadInt32(&w.workers)), int(atomic.LoadInt32(&w.freeWorkers))
+}
+
+// Returns the number of rounds and their average latency since it was last called.
+func (w *WorkerPool) collectRounds() (rounds int64, avgLatency time.Duration) {
+	rounds = atomic.SwapInt64(&w.rounds, 0)
+	latency := atomic.SwapInt64(&w.roundLatency, 0)
+	if rounds == 0 {
+		return 0, 0
+	}
+
+	return rounds, time.Duration(latency / rounds)
+}
+
 func (w *WorkerPool) Initialize(ctx context.Context) error {
 	return w.handler.Initialize(ctx)
 }
@@ -142,13 +215,15 @@ func (w *WorkerPool) Run(ctx context.Context, threadiness int, synced ...cache.I
 	// Launch workers to process FlyteWorkflow resources
 	for i := 0; i < threadiness; i++ {
 		w.metrics.FreeWorkers.Inc()
+		atomic.AddInt32(&w.workers, 1)
+		atomic.AddInt32(&w.freeWorkers, 1)
 		logger.Infof(ctx, "Starting worker [%d]", i)
 		workerLabel := fmt.Sprintf("worker-%v", i)
-		go func() {
+		go func(worker int) {
 			workerCtx := contextutils.WithGoroutineLabel(ctx, workerLabel)
 			pprof.SetGoroutineLabels(workerCtx)
-			w.runWorker(workerCtx)
-		}()
+			w.runWorker(workerCtx, worker)
+		}(i)
 	}
 
 	w.workQueue.Start(ctx)
@@ -168,10 +243,12 @@ func NewWorkerPool(ctx context.Context, scope promutils.Scope, workQueue Composi
 		RoundSuccess:     roundScope.MustNewCounter("success_count", "Round succeeded"),
 		RoundError:       roundScope.MustNewCounter("error_count", "Round failed"),
 		WorkersRestarted: scope.MustNewCounter("workers_restarted", "Propeller worker-pool was restarted"),
+		ActiveWorkers:    scope.MustNewGauge("active_workers_count", "Number of workers that are not held back by the active worker limit"),
 	}
 	return &WorkerPool{
 		workQueue: workQueue,
 		metrics:   metrics,
 		handler:   handler,
+		locks:     newWorkflowLockRegistry(scope),
 	}
 }
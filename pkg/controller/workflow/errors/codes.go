@@ -3,11 +3,13 @@ package errors
 type ErrorCode string
 
 const (
-	IllegalStateError     ErrorCode = "IllegalStateError"
-	BadSpecificationError ErrorCode = "BadSpecificationError"
-	CausedByError         ErrorCode = "CausedByError"
-	RuntimeExecutionError ErrorCode = "RuntimeExecutionError"
-	EventRecordingError   ErrorCode = "ErrorRecordingError"
+	IllegalStateError           ErrorCode = "IllegalStateError"
+	BadSpecificationError       ErrorCode = "BadSpecificationError"
+	CausedByError               ErrorCode = "CausedByError"
+	RuntimeExecutionError       ErrorCode = "RuntimeExecutionError"
+	EventRecordingError         ErrorCode = "ErrorRecordingError"
+	UnsupportedSpecVersionError ErrorCode = "UnsupportedSpecVersionError"
+	SpecTamperedError           ErrorCode = "SpecTamperedError"
 )
 
 func (e ErrorCode) String() string {
@@ -34,6 +34,11 @@ func (w *WorkflowErrorWithCause) Cause() error {
 	return w.cause
 }
 
+// Unwrap returns the cause, so that errors.Is and errors.As look through workflow errors.
+func (w *WorkflowErrorWithCause) Unwrap() error {
+	return w.cause
+}
+
 func errorf(c ErrorCode, w v1alpha1.WorkflowID, msgFmt string, args ...interface{}) *WorkflowError {
 	return &WorkflowError{
 		Code:     c,
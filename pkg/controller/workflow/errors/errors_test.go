@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -30,6 +31,7 @@ func TestErrorfWithCause(t *testing.T) {
 	assert.Equal(t, "w1", e.Workflow)
 	assert.Equal(t, fmt.Sprintf("Message [%v]", msg), e.Message)
 	assert.Equal(t, cause, extErrors.Cause(e))
+	assert.Equal(t, cause, errors.Unwrap(e))
 	assert.Equal(t, "Workflow[w1] failed. IllegalStateError: Message [msg], caused by: Some Error", err.Error())
 }
 
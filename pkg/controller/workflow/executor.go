@@ -9,24 +9,51 @@ import (
 	eventsErr "github.com/flyteorg/flyteidl/clients/go/events/errors"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
 	"github.com/flyteorg/flytestdlib/storage"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	nodeErrors "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflow/errors"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytepropeller/pkg/utils"
 )
 
+const resourceUsageEventReason = "ResourceUsage"
+const resourceEscalationEventReason = "ResourceEscalation"
+const compilationWarningEventReason = "CompilationWarning"
+const unsupportedSpecVersionEventReason = "UnsupportedSpecVersion"
+const specTamperedEventReason = "SpecTampered"
+
+// Bounds the spec checksums cached for the workflows being evaluated. The checksums of the previous resource versions of a
+// workflow are never read again, they age out.
+const (
+	maxSpecChecksums = 10000
+	specChecksumTTL  = time.Hour
+)
+const nodeForcedEventReason = "NodeForced"
+const nodeSkippedEventReason = "NodeSkipped"
+const fanOutBackpressureEventReason = "FanOutBackpressure"
+
 type workflowMetrics struct {
 	AcceptedWorkflows         labeled.Counter
 	FailureDuration           labeled.StopWatch
 	SuccessDuration           labeled.StopWatch
 	IncompleteWorkflowAborted labeled.Counter
+	UnsupportedSpecVersion    labeled.Counter
+	SpecTampered              labeled.Counter
+	DeduplicatedEvents        labeled.Counter
+	// Counts the durations that would have been negative, because the clocks of propeller replicas are skewed, and were
+	// observed as zero instead.
+	NegativeDurations labeled.Counter
 
 	// Measures the time between when we receive service call to create an execution and when it has moved to running state.
 	AcceptanceLatency labeled.StopWatch
@@ -61,19 +88,38 @@ type workflowExecutor struct {
 	store           *storage.DataStore
 	wfRecorder      events.WorkflowEventRecorder
 	k8sRecorder     record.EventRecorder
-	metadataPrefix  storage.DataReference
+	// Relative to the base container of the store, which depends on the execution if the data of tenants is separated.
+	metadataPrefix  string
 	nodeExecutor    executors.Node
 	metrics         *workflowMetrics
+	// Whether workflows whose spec no longer matches its checksum are failed, rather than only warned about.
+	failOnSpecMismatch bool
+	// Checksums of the specs of workflows by their UID and resource version.
+	specChecksums *cache.LRUExpireCache
+}
+
+func constructMetadataPrefix(ctx context.Context, store *storage.DataStore, metadataPrefix string) (storage.DataReference, error) {
+	basePrefix := store.GetBaseContainerFQN(ctx)
+	if metadataPrefix == "" {
+		return basePrefix, nil
+	}
+
+	return store.ConstructReference(ctx, basePrefix, metadataPrefix)
 }
 
 func (c *workflowExecutor) constructWorkflowMetadataPrefix(ctx context.Context, w *v1alpha1.FlyteWorkflow) (storage.DataReference, error) {
+	basePrefix, err := constructMetadataPrefix(ctx, c.store, c.metadataPrefix)
+	if err != nil {
+		return "", err
+	}
+
 	if w.GetExecutionID().WorkflowExecutionIdentifier != nil {
 		execID := fmt.Sprintf("%v-%v-%v", w.GetExecutionID().GetProject(), w.GetExecutionID().GetDomain(), w.GetExecutionID().GetName())
-		return c.store.ConstructReference(ctx, c.metadataPrefix, execID)
+		return c.store.ConstructReference(ctx, basePrefix, execID)
 	}
 	// TODO should we use a random guid as the prefix? Otherwise we may get collisions
 	logger.Warningf(ctx, "Workflow has no ExecutionID. Using the name as the storage-prefix. This maybe unsafe!")
-	return c.store.ConstructReference(ctx, c.metadataPrefix, w.Name)
+	return c.store.ConstructReference(ctx, basePrefix, w.Name)
 }
 
 func (c *workflowExecutor) handleReadyWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow) (Status, error) {
@@ -209,7 +255,8 @@ func (c *workflowExecutor) handleFailingWorkflow(ctx context.Context, w *v1alpha
 	execErr := executionErrorOrDefault(w.GetExecutionStatus().GetExecutionError(), w.GetExecutionStatus().GetMessage())
 
 	// Best effort clean-up.
-	if err := c.cleanupRunningNodes(ctx, w, "Some node execution failed, auto-abort."); err != nil {
+	abortCtx := handler.WithAbortCause(ctx, handler.AbortCause{Kind: handler.AbortCauseWorkflowFailure, Reason: execErr.GetMessage()})
+	if err := c.cleanupRunningNodes(abortCtx, w, "Some node execution failed, auto-abort."); err != nil {
 		logger.Errorf(ctx, "Failed to propagate Abort for workflow:%v. Error: %v",
 			w.ExecutionID.WorkflowExecutionIdentifier, err)
 		return StatusFailing(execErr), err
@@ -290,14 +337,14 @@ func (c *workflowExecutor) TransitionToPhase(ctx context.Context, execID *core.W
 			wStatus.UpdatePhase(v1alpha1.WorkflowPhaseFailed, "", wfEvent.GetError())
 			wfEvent.OccurredAt = utils.GetProtoTime(wStatus.GetStoppedAt())
 			// Completion latency is only observed when a workflow completes successfully
-			c.metrics.FailureDuration.Observe(ctx, wStatus.GetStartedAt().Time, wStatus.GetStoppedAt().Time)
+			utils.ObserveDuration(ctx, c.metrics.FailureDuration, c.metrics.NegativeDurations, wStatus.GetStartedAt().Time, wStatus.GetStoppedAt().Time)
 		case v1alpha1.WorkflowPhaseSucceeding:
 			wfEvent.Phase = core.WorkflowExecution_SUCCEEDING
 			endNodeStatus := wStatus.GetNodeExecutionStatus(ctx, v1alpha1.EndNodeID)
 			// Workflow completion latency is recorded as the time it takes for the workflow to transition from end
 			// node started time to workflow success being sent to the control plane.
 			if endNodeStatus != nil && endNodeStatus.GetStartedAt() != nil {
-				c.metrics.CompletionLatency.Observe(ctx, endNodeStatus.GetStartedAt().Time, time.Now())
+				utils.ObserveDuration(ctx, c.metrics.CompletionLatency, c.metrics.NegativeDurations, endNodeStatus.GetStartedAt().Time, time.Now())
 			}
 
 			wStatus.UpdatePhase(v1alpha1.WorkflowPhaseSucceeding, "", nil)
@@ -312,11 +359,11 @@ func (c *workflowExecutor) TransitionToPhase(ctx context.Context, execID *core.W
 				}
 			}
 			wfEvent.OccurredAt = utils.GetProtoTime(wStatus.GetStoppedAt())
-			c.metrics.SuccessDuration.Observe(ctx, wStatus.GetStartedAt().Time, wStatus.GetStoppedAt().Time)
+			utils.ObserveDuration(ctx, c.metrics.SuccessDuration, c.metrics.NegativeDurations, wStatus.GetStartedAt().Time, wStatus.GetStoppedAt().Time)
 		case v1alpha1.WorkflowPhaseAborted:
 			wfEvent.Phase = core.WorkflowExecution_ABORTED
 			if wStatus.GetLastUpdatedAt() != nil {
-				c.metrics.CompletionLatency.Observe(ctx, wStatus.GetLastUpdatedAt().Time, time.Now())
+				utils.ObserveDuration(ctx, c.metrics.CompletionLatency, c.metrics.NegativeDurations, wStatus.GetLastUpdatedAt().Time, time.Now())
 			}
 			wStatus.UpdatePhase(v1alpha1.WorkflowPhaseAborted, "", nil)
 			wfEvent.OccurredAt = utils.GetProtoTime(wStatus.GetStoppedAt())
@@ -324,6 +371,14 @@ func (c *workflowExecutor) TransitionToPhase(ctx context.Context, execID *core.W
 			return errors.Errorf(errors.IllegalStateError, "", "Illegal transition from [%v] -> [%v]", wStatus.GetPhase().String(), toStatus.TransitionToPhase.String())
 		}
 
+		// The event may have been recorded already, by a previous transition that reported the same event phase.
+		if checkpoint := wStatus.GetEventCheckpoint(); checkpoint != nil && checkpoint.Phase == wfEvent.Phase {
+			logger.Infof(ctx, "Workflow event phase: %s was already recorded at [%v], skipping it", wfEvent.Phase.String(),
+				checkpoint.RecordedAt)
+			c.metrics.DeduplicatedEvents.Inc(ctx)
+			return nil
+		}
+
 		if recordingErr := c.IdempotentReportEvent(ctx, wfEvent); recordingErr != nil {
 			if eventsErr.IsAlreadyExists(recordingErr) {
 				logger.Warningf(ctx, "Failed to record workflowEvent, error [%s]. Trying to record state: %s. Ignoring this error!", recordingErr.Error(), wfEvent.Phase)
@@ -339,10 +394,194 @@ func (c *workflowExecutor) TransitionToPhase(ctx context.Context, execID *core.W
 			logger.Warningf(ctx, "Event recording failed. Error [%s]", recordingErr.Error())
 			return errors.Wrapf(errors.EventRecordingError, "", recordingErr, "failed to publish event")
 		}
+
+		wStatus.SetEventCheckpoint(&v1alpha1.WorkflowEventCheckpoint{Phase: wfEvent.Phase, RecordedAt: metav1.Now()})
 	}
 	return nil
 }
 
+// Emits the aggregated resource usage of all the nodes in the workflow once it has reached a terminal phase.
+func (c *workflowExecutor) recordResourceUsage(w *v1alpha1.FlyteWorkflow) {
+	if !w.GetExecutionStatus().IsTerminated() {
+		return
+	}
+
+	usage := w.Status.GetTotalResourceUsage()
+	if usage.IsZero() {
+		return
+	}
+
+	c.k8sRecorder.Event(w, corev1.EventTypeNormal, resourceUsageEventReason, fmt.Sprintf(
+		"Requested resources multiplied by runtime: cpu [%d millicore-seconds], memory [%d MiB-seconds], gpu [%d gpu-seconds]",
+		usage.CPUMilliCoreSeconds, usage.MemoryMiBSeconds, usage.GPUSeconds))
+}
+
+// Emits an event for every resource escalation of a node that has not been reported yet.
+func (c *workflowExecutor) recordResourceEscalations(w *v1alpha1.FlyteWorkflow, statuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) {
+	for nodeID, s := range statuses {
+		if e := s.GetResourceEscalation(); e != nil && !e.Reported {
+			c.k8sRecorder.Event(w, corev1.EventTypeNormal, resourceEscalationEventReason, fmt.Sprintf("Node [%s]: %s", v1alpha1.StripAttemptSuffix(nodeID), e.Reason))
+			e.Reported = true
+			s.SetDirty()
+		}
+
+		c.recordResourceEscalations(w, s.SubNodeStatus)
+	}
+}
+
+// Emits a warning event for every node forced by an operator that has not been reported yet, to audit who forced it and
+// why.
+func (c *workflowExecutor) recordNodeForces(w *v1alpha1.FlyteWorkflow, statuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) {
+	for nodeID, s := range statuses {
+		if f := s.GetForced(); f != nil && !f.Reported {
+			c.k8sRecorder.Event(w, corev1.EventTypeWarning, nodeForcedEventReason, fmt.Sprintf(
+				"Node [%s] forced to %s by [%s]: %s", v1alpha1.StripAttemptSuffix(nodeID), f.Phase, f.RequestedBy, f.Reason))
+			f.Reported = true
+			s.SetDirty()
+		}
+
+		c.recordNodeForces(w, s.SubNodeStatus)
+	}
+}
+
+// Emits a warning event for every node skipped by an operator that has not been reported yet.
+func (c *workflowExecutor) recordNodeSkips(w *v1alpha1.FlyteWorkflow, statuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) {
+	for nodeID, s := range statuses {
+		if skip := s.GetOperatorSkipped(); skip != nil && !skip.Reported {
+			c.k8sRecorder.Event(w, corev1.EventTypeWarning, nodeSkippedEventReason, fmt.Sprintf(
+				"Node [%s] skipped by [%s]: %s", v1alpha1.StripAttemptSuffix(nodeID), skip.RequestedBy, skip.Reason))
+			skip.Reported = true
+			s.SetDirty()
+		}
+
+		c.recordNodeSkips(w, s.SubNodeStatus)
+	}
+}
+
+// Emits a warning event for every dynamic node that started staggering the launch of its sub-nodes since the last round.
+func (c *workflowExecutor) recordFanOutBackpressure(w *v1alpha1.FlyteWorkflow, statuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) {
+	for nodeID, s := range statuses {
+		if b := s.GetFanOutBackpressure(); b != nil && !b.Reported {
+			c.k8sRecorder.Event(w, corev1.EventTypeWarning, fanOutBackpressureEventReason, fmt.Sprintf("Node [%s]: %s", v1alpha1.StripAttemptSuffix(nodeID), b.Reason))
+			b.Reported = true
+			s.SetDirty()
+		}
+
+		c.recordFanOutBackpressure(w, s.SubNodeStatus)
+	}
+}
+
+// Emits a warning event for every problem the compiler found in the workflow, once the workflow begins execution.
+func (c *workflowExecutor) recordCompilationWarnings(w *v1alpha1.FlyteWorkflow) {
+	for _, warning := range w.CompilationWarnings {
+		c.k8sRecorder.Event(w, corev1.EventTypeWarning, compilationWarningEventReason, fmt.Sprintf(
+			"Workflow [%s] %s: %s", warning.WorkflowID, warning.Code, warning.Message))
+	}
+}
+
+// Refuses to evaluate workflows whose spec version this propeller cannot run, it returns true if the workflow was
+// refused. Workflows that are too new are left untouched for a newer propeller, e.g. once a rolling upgrade completes.
+// Workflows that are too old are failed before they start, as no propeller will ever run them.
+func (c *workflowExecutor) refuseUnsupportedSpecVersion(ctx context.Context, w *v1alpha1.FlyteWorkflow) (bool, error) {
+	var msg string
+	switch w.GetSpecCompatibility() {
+	case v1alpha1.SpecTooNew:
+		msg = fmt.Sprintf("Workflow requires spec version [%d] but this propeller only supports up to [%d].",
+			w.GetMinSpecVersion(), v1alpha1.LatestSpecVersion)
+	case v1alpha1.SpecTooOld:
+		msg = fmt.Sprintf("Workflow was written with spec version [%d] but this propeller only supports [%d] and newer.",
+			w.GetSpecVersion(), v1alpha1.MinSupportedSpecVersion)
+	default:
+		return false, nil
+	}
+
+	logger.Warnf(ctx, msg)
+	c.metrics.UnsupportedSpecVersion.Inc(ctx)
+	c.k8sRecorder.Event(w, corev1.EventTypeWarning, unsupportedSpecVersionEventReason, msg)
+	if w.GetSpecCompatibility() == v1alpha1.SpecTooOld && w.GetExecutionStatus().GetPhase() == v1alpha1.WorkflowPhaseReady {
+		return true, c.TransitionToPhase(ctx, w.ExecutionID.WorkflowExecutionIdentifier, w.GetExecutionStatus(), StatusFailed(&core.ExecutionError{
+			Kind:    core.ExecutionError_SYSTEM,
+			Code:    errors.UnsupportedSpecVersionError.String(),
+			Message: msg,
+		}))
+	}
+
+	return true, nil
+}
+
+// Returns the checksum of the spec of the workflow. Checksums are cached by the resource version of the workflow, so that
+// the spec is only serialized again once the workflow was written.
+func (c *workflowExecutor) getSpecChecksum(w *v1alpha1.FlyteWorkflow) (string, error) {
+	key := string(w.GetUID()) + "@" + w.GetResourceVersion()
+	if len(w.GetResourceVersion()) > 0 {
+		if checksum, found := c.specChecksums.Get(key); found {
+			return checksum.(string), nil
+		}
+	}
+
+	checksum, err := w.ComputeSpecChecksum()
+	if err != nil {
+		return "", err
+	}
+
+	if len(w.GetResourceVersion()) > 0 {
+		c.specChecksums.Add(key, checksum, specChecksumTTL)
+	}
+
+	return checksum, nil
+}
+
+// Reports workflows whose spec was changed since their creation, e.g. by a manual edit, and fails them unless configured
+// to only warn, rather than traversing a spec the recorded status may no longer match. It returns true if the workflow
+// was failed. Workflows that are already failing are left to abort their running nodes.
+func (c *workflowExecutor) failTamperedSpec(ctx context.Context, w *v1alpha1.FlyteWorkflow) (bool, error) {
+	phase := w.GetExecutionStatus().GetPhase()
+	if (phase != v1alpha1.WorkflowPhaseReady && phase != v1alpha1.WorkflowPhaseRunning) || !w.ShouldVerifySpecChecksum() {
+		return false, nil
+	}
+
+	checksum, err := c.getSpecChecksum(w)
+	if err != nil || checksum == w.SpecChecksum {
+		return false, err
+	}
+
+	if !c.failOnSpecMismatch {
+		// The status records the modification that was reported, so that it is not reported again every round.
+		if w.Status.SpecMismatchChecksum == checksum {
+			return false, nil
+		}
+
+		c.metrics.SpecTampered.Inc(ctx)
+		msg := fmt.Sprintf("Workflow spec no longer matches its checksum [%s], it was modified after the workflow was "+
+			"created. Annotate the workflow with [%s=true] to silence this warning.", w.SpecChecksum,
+			v1alpha1.AllowSpecMutationAnnotation)
+		logger.Warnf(ctx, msg)
+		c.k8sRecorder.Event(w, corev1.EventTypeWarning, specTamperedEventReason, msg)
+		w.Status.SpecMismatchChecksum = checksum
+		return false, nil
+	}
+
+	c.metrics.SpecTampered.Inc(ctx)
+	msg := fmt.Sprintf("Workflow spec no longer matches its checksum [%s], it was modified after the workflow was "+
+		"created. Annotate the workflow with [%s=true] to run it anyway.", w.SpecChecksum, v1alpha1.AllowSpecMutationAnnotation)
+	logger.Errorf(ctx, msg)
+	c.k8sRecorder.Event(w, corev1.EventTypeWarning, specTamperedEventReason, msg)
+
+	execErr := &core.ExecutionError{
+		Kind:    core.ExecutionError_SYSTEM,
+		Code:    errors.SpecTamperedError.String(),
+		Message: msg,
+	}
+
+	// Workflows that have not started have nothing to abort.
+	status := StatusFailed(execErr)
+	if phase == v1alpha1.WorkflowPhaseRunning {
+		status = StatusFailing(execErr)
+	}
+
+	return true, c.TransitionToPhase(ctx, w.ExecutionID.WorkflowExecutionIdentifier, w.GetExecutionStatus(), status)
+}
+
 func (c *workflowExecutor) Initialize(ctx context.Context) error {
 	logger.Infof(ctx, "Initializing Core Workflow Executor")
 	return c.nodeExecutor.Initialize(ctx)
@@ -354,6 +593,16 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 
 	w.DataReferenceConstructor = c.store
 
+	if refused, err := c.refuseUnsupportedSpecVersion(ctx, w); refused || err != nil {
+		return err
+	}
+
+	if failed, err := c.failTamperedSpec(ctx, w); failed || err != nil {
+		return err
+	}
+
+	w.UpgradeSpec()
+
 	wStatus := w.GetExecutionStatus()
 	// Initialize the Status if not already initialized
 	switch wStatus.GetPhase() {
@@ -367,6 +616,7 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 			return err
 		}
 		c.k8sRecorder.Event(w, corev1.EventTypeNormal, v1alpha1.WorkflowPhaseRunning.String(), "Workflow began execution")
+		c.recordCompilationWarnings(w)
 
 		// TODO: Consider annotating with the newStatus.
 		acceptedAt := w.GetCreationTimestamp().Time
@@ -374,7 +624,7 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 			acceptedAt = w.AcceptedAt.Time
 		}
 
-		c.metrics.AcceptanceLatency.Observe(ctx, acceptedAt, time.Now())
+		utils.ObserveDuration(ctx, c.metrics.AcceptanceLatency, c.metrics.NegativeDurations, acceptedAt, time.Now())
 		return nil
 
 	case v1alpha1.WorkflowPhaseRunning:
@@ -383,6 +633,10 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 			logger.Warningf(ctx, "Error in handling running workflow [%v]", err.Error())
 			return err
 		}
+		c.recordResourceEscalations(w, w.Status.NodeStatus)
+		c.recordNodeForces(w, w.Status.NodeStatus)
+		c.recordNodeSkips(w, w.Status.NodeStatus)
+		c.recordFanOutBackpressure(w, w.Status.NodeStatus)
 		if err := c.TransitionToPhase(ctx, w.ExecutionID.WorkflowExecutionIdentifier, wStatus, newStatus); err != nil {
 			return err
 		}
@@ -394,6 +648,7 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 			return err
 		}
 		c.k8sRecorder.Event(w, corev1.EventTypeNormal, v1alpha1.WorkflowPhaseSuccess.String(), "Workflow completed.")
+		c.recordResourceUsage(w)
 		return nil
 	case v1alpha1.WorkflowPhaseFailing:
 		newStatus, err := c.handleFailingWorkflow(ctx, w)
@@ -404,6 +659,7 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 			return err
 		}
 		c.k8sRecorder.Event(w, corev1.EventTypeWarning, v1alpha1.WorkflowPhaseFailed.String(), "Workflow failed.")
+		c.recordResourceUsage(w)
 		return nil
 	case v1alpha1.WorkflowPhaseHandlingFailureNode:
 		newStatus, err := c.handleFailureNode(ctx, w)
@@ -414,6 +670,7 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 			return err
 		}
 		c.k8sRecorder.Event(w, corev1.EventTypeWarning, v1alpha1.WorkflowPhaseFailed.String(), "Workflow failed.")
+		c.recordResourceUsage(w)
 		return nil
 	default:
 		return errors.Errorf(errors.IllegalStateError, w.ID, "Unsupported state [%s] for workflow", w.GetExecutionStatus().GetPhase().String())
@@ -425,15 +682,24 @@ func (c *workflowExecutor) HandleAbortedWorkflow(ctx context.Context, w *v1alpha
 
 	if !w.Status.IsTerminated() {
 		reason := fmt.Sprintf("max number of system retry attempts [%d/%d] exhausted - system failure.", w.Status.FailedAttempts, maxRetries)
+		kind := handler.AbortCauseSystemFailure
 		c.metrics.IncompleteWorkflowAborted.Inc(ctx)
 		// Check of the workflow was deleted and that caused the abort
 		if w.GetDeletionTimestamp() != nil {
 			reason = "Workflow aborted."
+			kind = handler.AbortCauseUser
 		}
 
 		// We will always try to cleanup, even if we have extinguished all our retries
 		// TODO ABORT should have its separate set of retries
-		err := c.cleanupRunningNodes(ctx, w, reason)
+		err := c.cleanupRunningNodes(handler.WithAbortCause(ctx, handler.AbortCause{Kind: kind, Reason: reason}), w, reason)
+		if err != nil && nodeErrors.IsAbortInProgress(err) {
+			// Some resources are still terminating, the workflow stays as is until they are gone, or force deleted,
+			// without counting this round as a failed attempt.
+			logger.Infof(ctx, "Waiting for the nodes of workflow:%v to finish aborting. %v", w.ExecutionID.WorkflowExecutionIdentifier, err)
+			return nil
+		}
+
 		// Best effort clean-up.
 		if err != nil && w.Status.FailedAttempts <= maxRetries {
 			logger.Errorf(ctx, "Failed to propagate Abort for workflow:%v. Error: %v", w.ExecutionID.WorkflowExecutionIdentifier, err)
@@ -462,6 +728,8 @@ func (c *workflowExecutor) HandleAbortedWorkflow(ctx context.Context, w *v1alpha
 		if err := c.TransitionToPhase(ctx, w.ExecutionID.WorkflowExecutionIdentifier, w.GetExecutionStatus(), status); err != nil {
 			return err
 		}
+
+		c.recordResourceUsage(w)
 	}
 	return nil
 }
@@ -474,20 +742,16 @@ func (c *workflowExecutor) cleanupRunningNodes(ctx context.Context, w v1alpha1.E
 
 	execcontext := executors.NewExecutionContext(w, w, w, nil, executors.InitializeControlFlow())
 	if err := c.nodeExecutor.AbortHandler(ctx, execcontext, w, w, startNode, reason); err != nil {
-		return errors.Errorf(errors.CausedByError, w.GetID(), "Failed to propagate Abort for workflow. Error: %v", err)
+		return errors.Wrapf(errors.CausedByError, w.GetID(), err, "Failed to propagate Abort for workflow")
 	}
 
 	return nil
 }
 
 func NewExecutor(ctx context.Context, store *storage.DataStore, enQWorkflow v1alpha1.EnqueueWorkflow, eventSink events.EventSink, k8sEventRecorder record.EventRecorder, metadataPrefix string, nodeExecutor executors.Node, scope promutils.Scope) (executors.Workflow, error) {
-	basePrefix := store.GetBaseContainerFQN(ctx)
-	if metadataPrefix != "" {
-		var err error
-		basePrefix, err = store.ConstructReference(ctx, basePrefix, metadataPrefix)
-		if err != nil {
-			return nil, err
-		}
+	basePrefix, err := constructMetadataPrefix(ctx, store, metadataPrefix)
+	if err != nil {
+		return nil, err
 	}
 	logger.Infof(ctx, "Metadata will be stored in container path: [%s]", basePrefix)
 
@@ -499,8 +763,11 @@ func NewExecutor(ctx context.Context, store *storage.DataStore, enQWorkflow v1al
 		enqueueWorkflow: enQWorkflow,
 		wfRecorder:      events.NewWorkflowEventRecorder(eventSink, workflowScope),
 		k8sRecorder:     k8sEventRecorder,
-		metadataPrefix:  basePrefix,
+		metadataPrefix:  metadataPrefix,
 		metrics:         newMetrics(workflowScope),
+
+		failOnSpecMismatch: config.GetConfig().SpecChecksum.FailOnMismatch,
+		specChecksums:      cache.NewLRUExpireCache(maxSpecChecksums),
 	}, nil
 }
 
@@ -510,6 +777,10 @@ func newMetrics(workflowScope promutils.Scope) *workflowMetrics {
 		FailureDuration:           labeled.NewStopWatch("failure_duration", "Indicates the total execution time of a failed workflow.", time.Millisecond, workflowScope, labeled.EmitUnlabeledMetric),
 		SuccessDuration:           labeled.NewStopWatch("success_duration", "Indicates the total execution time of a successful workflow.", time.Millisecond, workflowScope, labeled.EmitUnlabeledMetric),
 		IncompleteWorkflowAborted: labeled.NewCounter("workflow_aborted", "Indicates an inprogress execution was aborted", workflowScope, labeled.EmitUnlabeledMetric),
+		UnsupportedSpecVersion:    labeled.NewCounter("unsupported_spec_version", "Number of rounds refused because this propeller cannot run the spec version of the workflow", workflowScope, labeled.EmitUnlabeledMetric),
+		SpecTampered:              labeled.NewCounter("spec_tampered", "Number of modifications of workflow specs observed after the workflows were created", workflowScope, labeled.EmitUnlabeledMetric),
+		NegativeDurations:         labeled.NewCounter("negative_durations", "Number of durations observed as zero because their end was before their start", workflowScope, labeled.EmitUnlabeledMetric),
+		DeduplicatedEvents:        labeled.NewCounter("deduplicated_events", "Number of workflow events skipped because they were recorded already", workflowScope, labeled.EmitUnlabeledMetric),
 		AcceptanceLatency:         labeled.NewStopWatch("acceptance_latency", "Delay between workflow creation and moving it to running state.", time.Millisecond, workflowScope, labeled.EmitUnlabeledMetric),
 		CompletionLatency:         labeled.NewStopWatch("completion_latency", "Measures the time between when the WF moved to succeeding/failing state and when it finally moved to a terminal state.", time.Millisecond, workflowScope, labeled.EmitUnlabeledMetric),
 	}
@@ -22,7 +22,6 @@ import (
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery"
 	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
-	"github.com/flyteorg/flytestdlib/logger"
 
 	eventsErr "github.com/flyteorg/flyteidl/clients/go/events/errors"
 
@@ -41,13 +40,17 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes"
+	nodeErrors "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	recoveryMocks "github.com/flyteorg/flytepropeller/pkg/controller/nodes/recovery/mocks"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 )
 
 var (
@@ -675,6 +678,12 @@ func TestWorkflowExecutor_HandleFlyteWorkflow_EventFailure(t *testing.T) {
 	})
 }
 
+func withAbortCause(kind handler.AbortCauseKind) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return handler.AbortCauseFromContext(ctx, "").Kind == kind
+	})
+}
+
 func TestWorkflowExecutor_HandleAbortedWorkflow(t *testing.T) {
 	ctx := context.TODO()
 
@@ -686,7 +695,7 @@ func TestWorkflowExecutor_HandleAbortedWorkflow(t *testing.T) {
 			metrics:      newMetrics(promutils.NewTestScope()),
 		}
 
-		nodeExec.OnAbortHandlerMatch(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("error"))
+		nodeExec.OnAbortHandlerMatch(withAbortCause(handler.AbortCauseUser), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("error"))
 
 		w := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
@@ -722,7 +731,7 @@ func TestWorkflowExecutor_HandleAbortedWorkflow(t *testing.T) {
 			metrics: newMetrics(promutils.NewTestScope()),
 		}
 
-		nodeExec.OnAbortHandlerMatch(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		nodeExec.OnAbortHandlerMatch(withAbortCause(handler.AbortCauseUser), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 		w := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
@@ -759,7 +768,7 @@ func TestWorkflowExecutor_HandleAbortedWorkflow(t *testing.T) {
 			metrics: newMetrics(promutils.NewTestScope()),
 		}
 
-		nodeExec.OnAbortHandlerMatch(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		nodeExec.OnAbortHandlerMatch(withAbortCause(handler.AbortCauseUser), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 		w := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
@@ -795,7 +804,7 @@ func TestWorkflowExecutor_HandleAbortedWorkflow(t *testing.T) {
 			metrics: newMetrics(promutils.NewTestScope()),
 		}
 
-		nodeExec.OnAbortHandlerMatch(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		nodeExec.OnAbortHandlerMatch(withAbortCause(handler.AbortCauseSystemFailure), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 		w := &v1alpha1.FlyteWorkflow{
 			Status: v1alpha1.WorkflowStatus{
@@ -822,7 +831,7 @@ func TestWorkflowExecutor_HandleAbortedWorkflow(t *testing.T) {
 			metrics:      newMetrics(promutils.NewTestScope()),
 		}
 
-		nodeExec.OnAbortHandlerMatch(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("err"))
+		nodeExec.OnAbortHandlerMatch(withAbortCause(handler.AbortCauseSystemFailure), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("err"))
 
 		w := &v1alpha1.FlyteWorkflow{
 			Status: v1alpha1.WorkflowStatus{
@@ -839,4 +848,304 @@ func TestWorkflowExecutor_HandleAbortedWorkflow(t *testing.T) {
 
 		assert.Equal(t, uint32(1), w.Status.FailedAttempts)
 	})
+
+	t.Run("abort-in-progress", func(t *testing.T) {
+		nodeExec := &mocks2.Node{}
+		wExec := &workflowExecutor{
+			nodeExecutor: nodeExec,
+			metrics:      newMetrics(promutils.NewTestScope()),
+		}
+
+		inProgress := nodeErrors.ErrorCollection{Errors: []error{
+			nodeErrors.Errorf(nodeErrors.AbortInProgress, "n1", "terminating"),
+		}}
+		nodeExec.OnAbortHandlerMatch(withAbortCause(handler.AbortCauseSystemFailure), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(inProgress)
+
+		// Even once the attempts are exhausted, the workflow waits for its resources to terminate.
+		w := &v1alpha1.FlyteWorkflow{
+			Status: v1alpha1.WorkflowStatus{
+				FailedAttempts: 6,
+			},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{
+				Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+					v1alpha1.StartNodeID: {},
+				},
+			},
+		}
+
+		assert.NoError(t, wExec.HandleAbortedWorkflow(ctx, w, 5))
+
+		assert.Equal(t, uint32(6), w.Status.FailedAttempts)
+		assert.False(t, w.Status.IsTerminated())
+	})
+}
+
+func TestWorkflowExecutor_RecordResourceEscalations(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	wExec := &workflowExecutor{k8sRecorder: recorder}
+
+	escalated := &v1alpha1.NodeStatus{
+		ResourceEscalation: &v1alpha1.ResourceEscalation{Reason: "attempt [0] was OOMKilled"},
+	}
+	w := &v1alpha1.FlyteWorkflow{
+		Status: v1alpha1.WorkflowStatus{
+			NodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				"n1": {SubNodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{"n1-1": escalated}},
+				"n2": {},
+			},
+		},
+	}
+
+	wExec.recordResourceEscalations(w, w.Status.NodeStatus)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Node [n1-1]: attempt [0] was OOMKilled")
+	assert.True(t, escalated.ResourceEscalation.Reported)
+	assert.True(t, escalated.IsDirty())
+
+	// Escalations are only reported once
+	wExec.recordResourceEscalations(w, w.Status.NodeStatus)
+	assert.Len(t, recorder.Events, 0)
+}
+
+func TestWorkflowExecutor_RecordFanOutBackpressure(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	wExec := &workflowExecutor{k8sRecorder: recorder}
+
+	throttled := &v1alpha1.NodeStatus{
+		FanOutBackpressure: &v1alpha1.FanOutBackpressure{Reason: "launching at most [2] of [10] waiting sub-nodes"},
+	}
+	w := &v1alpha1.FlyteWorkflow{
+		Status: v1alpha1.WorkflowStatus{
+			NodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				"n1": {SubNodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{"n1-1": throttled}},
+				"n2": {},
+			},
+		},
+	}
+
+	wExec.recordFanOutBackpressure(w, w.Status.NodeStatus)
+	assert.Len(t, recorder.Events, 1)
+	ev := <-recorder.Events
+	assert.Contains(t, ev, "Warning FanOutBackpressure")
+	assert.Contains(t, ev, "Node [n1-1]: launching at most [2] of [10] waiting sub-nodes")
+	assert.True(t, throttled.FanOutBackpressure.Reported)
+	assert.True(t, throttled.IsDirty())
+
+	wExec.recordFanOutBackpressure(w, w.Status.NodeStatus)
+	assert.Len(t, recorder.Events, 0)
+}
+
+func TestWorkflowExecutor_TransitionToPhase_EventCheckpoint(t *testing.T) {
+	ctx := context.TODO()
+	var evs []core.WorkflowExecution_Phase
+	wExec := &workflowExecutor{
+		wfRecorder: &events.MockRecorder{
+			RecordWorkflowEventCb: func(ctx context.Context, event *event.WorkflowExecutionEvent) error {
+				evs = append(evs, event.Phase)
+				return nil
+			},
+		},
+		metrics: newMetrics(promutils.NewTestScope()),
+	}
+
+	execErr := &core.ExecutionError{Code: "code", Message: "message"}
+	status := &v1alpha1.WorkflowStatus{}
+	status.UpdatePhase(v1alpha1.WorkflowPhaseRunning, "", nil)
+
+	assert.NoError(t, wExec.TransitionToPhase(ctx, nil, status, StatusFailureNode(execErr)))
+	assert.Equal(t, core.WorkflowExecution_FAILING, status.GetEventCheckpoint().Phase)
+
+	// Moving from handling the failure node to failing reports the failing event phase again, it is not recorded twice.
+	status.UpdatePhase(v1alpha1.WorkflowPhaseHandlingFailureNode, "", nil)
+	assert.NoError(t, wExec.TransitionToPhase(ctx, nil, status, StatusFailing(execErr)))
+	assert.Equal(t, v1alpha1.WorkflowPhaseFailing, status.GetPhase())
+
+	assert.NoError(t, wExec.TransitionToPhase(ctx, nil, status, StatusFailed(execErr)))
+	assert.Equal(t, core.WorkflowExecution_FAILED, status.GetEventCheckpoint().Phase)
+	assert.Equal(t, []core.WorkflowExecution_Phase{core.WorkflowExecution_FAILING, core.WorkflowExecution_FAILED}, evs)
+}
+
+func TestWorkflowExecutor_RecordCompilationWarnings(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	wExec := &workflowExecutor{k8sRecorder: recorder}
+
+	w := &v1alpha1.FlyteWorkflow{
+		CompilationWarnings: []v1alpha1.CompilationWarning{
+			{WorkflowID: "wf", NodeID: "n1", Code: "NodeNeverRuns", Message: "Node [n1] can never run."},
+		},
+	}
+
+	wExec.recordCompilationWarnings(w)
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning CompilationWarning Workflow [wf] NodeNeverRuns: Node [n1] can never run.", <-recorder.Events)
+}
+
+func TestWorkflowExecutor_RefuseUnsupportedSpecVersion(t *testing.T) {
+	ctx := context.TODO()
+	newExecutor := func(recorder record.EventRecorder) *workflowExecutor {
+		scope := promutils.NewTestScope()
+		return &workflowExecutor{
+			k8sRecorder:        recorder,
+			wfRecorder:         events.NewWorkflowEventRecorder(events.NewMockEventSink(), scope),
+			metrics:            newMetrics(scope),
+			failOnSpecMismatch: true,
+		}
+	}
+
+	t.Run("compatible", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		refused, err := newExecutor(recorder).refuseUnsupportedSpecVersion(ctx, &v1alpha1.FlyteWorkflow{})
+		assert.NoError(t, err)
+		assert.False(t, refused)
+		assert.Len(t, recorder.Events, 0)
+	})
+
+	t.Run("too-new", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		w := &v1alpha1.FlyteWorkflow{
+			SpecVersion:    v1alpha1.LatestSpecVersion + 1,
+			MinSpecVersion: v1alpha1.LatestSpecVersion + 1,
+		}
+		refused, err := newExecutor(recorder).refuseUnsupportedSpecVersion(ctx, w)
+		assert.NoError(t, err)
+		assert.True(t, refused)
+		assert.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning UnsupportedSpecVersion Workflow requires spec version")
+		// The workflow is left for a newer propeller.
+		assert.Equal(t, v1alpha1.WorkflowPhaseReady, w.GetExecutionStatus().GetPhase())
+	})
+
+	t.Run("too-old", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		w := &v1alpha1.FlyteWorkflow{
+			ExecutionID: v1alpha1.WorkflowExecutionIdentifier{WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{Name: "e1"}},
+			SpecVersion: v1alpha1.MinSupportedSpecVersion - 1,
+		}
+		refused, err := newExecutor(recorder).refuseUnsupportedSpecVersion(ctx, w)
+		assert.NoError(t, err)
+		assert.True(t, refused)
+		assert.Len(t, recorder.Events, 1)
+		assert.Equal(t, v1alpha1.WorkflowPhaseFailed, w.GetExecutionStatus().GetPhase())
+		assert.Equal(t, wfErrors.UnsupportedSpecVersionError.String(), w.GetExecutionStatus().GetExecutionError().GetCode())
+	})
+}
+
+func TestWorkflowExecutor_FailTamperedSpec(t *testing.T) {
+	ctx := context.TODO()
+	newExecutor := func(recorder record.EventRecorder) *workflowExecutor {
+		scope := promutils.NewTestScope()
+		return &workflowExecutor{
+			k8sRecorder:        recorder,
+			wfRecorder:         events.NewWorkflowEventRecorder(events.NewMockEventSink(), scope),
+			metrics:            newMetrics(scope),
+			failOnSpecMismatch: true,
+			specChecksums:      cache.NewLRUExpireCache(10),
+		}
+	}
+
+	newWorkflow := func(phase v1alpha1.WorkflowPhase) *v1alpha1.FlyteWorkflow {
+		w := &v1alpha1.FlyteWorkflow{
+			ExecutionID:  v1alpha1.WorkflowExecutionIdentifier{WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{Name: "e1"}},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{ID: "wf"},
+			Status:       v1alpha1.WorkflowStatus{Phase: phase},
+		}
+		checksum, err := w.ComputeSpecChecksum()
+		assert.NoError(t, err)
+		w.SpecChecksum = checksum
+		w.SpecChecksumSerializer = v1alpha1.SpecChecksumSerializer
+		return w
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		executor := newExecutor(recorder)
+		executor.failOnSpecMismatch = false
+		w := newWorkflow(v1alpha1.WorkflowPhaseRunning)
+		w.ID = "edited"
+		failed, err := executor.failTamperedSpec(ctx, w)
+		assert.NoError(t, err)
+		assert.False(t, failed)
+		assert.Contains(t, <-recorder.Events, "Warning SpecTampered Workflow spec no longer matches its checksum")
+		assert.Equal(t, v1alpha1.WorkflowPhaseRunning, w.GetExecutionStatus().GetPhase())
+		assert.NotEmpty(t, w.Status.SpecMismatchChecksum)
+
+		// The modification is only reported once, further modifications are reported again.
+		failed, err = executor.failTamperedSpec(ctx, w)
+		assert.NoError(t, err)
+		assert.False(t, failed)
+		assert.Len(t, recorder.Events, 0)
+
+		w.ID = "edited-again"
+		failed, err = executor.failTamperedSpec(ctx, w)
+		assert.NoError(t, err)
+		assert.False(t, failed)
+		assert.Len(t, recorder.Events, 1)
+	})
+
+	t.Run("cached-by-resource-version", func(t *testing.T) {
+		executor := newExecutor(record.NewFakeRecorder(10))
+		w := newWorkflow(v1alpha1.WorkflowPhaseRunning)
+		w.UID = "uid"
+		w.ResourceVersion = "1"
+		checksum, err := executor.getSpecChecksum(w)
+		assert.NoError(t, err)
+		assert.Equal(t, w.SpecChecksum, checksum)
+
+		// The spec of a resource version never changes, its checksum is not computed again.
+		w.ID = "edited"
+		checksum, err = executor.getSpecChecksum(w)
+		assert.NoError(t, err)
+		assert.Equal(t, w.SpecChecksum, checksum)
+
+		w.ResourceVersion = "2"
+		checksum, err = executor.getSpecChecksum(w)
+		assert.NoError(t, err)
+		assert.NotEqual(t, w.SpecChecksum, checksum)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		failed, err := newExecutor(recorder).failTamperedSpec(ctx, newWorkflow(v1alpha1.WorkflowPhaseRunning))
+		assert.NoError(t, err)
+		assert.False(t, failed)
+		assert.Len(t, recorder.Events, 0)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		w := newWorkflow(v1alpha1.WorkflowPhaseReady)
+		w.ID = "edited"
+		failed, err := newExecutor(recorder).failTamperedSpec(ctx, w)
+		assert.NoError(t, err)
+		assert.True(t, failed)
+		assert.Contains(t, <-recorder.Events, "Warning SpecTampered Workflow spec no longer matches its checksum")
+		assert.Equal(t, v1alpha1.WorkflowPhaseFailed, w.GetExecutionStatus().GetPhase())
+		assert.Equal(t, wfErrors.SpecTamperedError.String(), w.GetExecutionStatus().GetExecutionError().GetCode())
+	})
+
+	t.Run("running", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		w := newWorkflow(v1alpha1.WorkflowPhaseRunning)
+		w.ID = "edited"
+		failed, err := newExecutor(recorder).failTamperedSpec(ctx, w)
+		assert.NoError(t, err)
+		assert.True(t, failed)
+		assert.Equal(t, v1alpha1.WorkflowPhaseFailing, w.GetExecutionStatus().GetPhase())
+
+		// Failing workflows abort their nodes regardless.
+		failed, err = newExecutor(recorder).failTamperedSpec(ctx, w)
+		assert.NoError(t, err)
+		assert.False(t, failed)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		w := newWorkflow(v1alpha1.WorkflowPhaseRunning)
+		w.ID = "edited"
+		w.Annotations = map[string]string{v1alpha1.AllowSpecMutationAnnotation: "true"}
+		failed, err := newExecutor(recorder).failTamperedSpec(ctx, w)
+		assert.NoError(t, err)
+		assert.False(t, failed)
+		assert.Len(t, recorder.Events, 0)
+	})
 }
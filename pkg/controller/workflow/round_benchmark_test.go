@@ -0,0 +1,303 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/clients/go/coreutils"
+	"github.com/flyteorg/flyteidl/clients/go/events"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/ioutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/compiler"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/common"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes"
+	recoveryMocks "github.com/flyteorg/flytepropeller/pkg/controller/nodes/recovery/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/catalog"
+)
+
+// The benchmarks below measure the cost of a single evaluation round of synthetic workflows. Every benchmark fails if
+// the number of allocations per round exceeds its budget, so run them (make benchmark) when touching the evaluation
+// loop and update the budget in the same change if an increase is intended.
+
+const (
+	benchmarkTaskType        = "benchmark"
+	benchmarkDynamicTaskType = "benchmark-dynamic"
+	benchmarkDynamicNodesKey = "nodes"
+)
+
+var registerBenchmarkPlugin sync.Once
+
+// Succeeds every task on its first invocation. Regular tasks output y = x + 1, dynamic tasks generate as many
+// regular tasks as their config asks for.
+type benchmarkPlugin struct{}
+
+func (benchmarkPlugin) GetID() string {
+	return benchmarkTaskType
+}
+
+func (benchmarkPlugin) GetProperties() pluginCore.PluginProperties {
+	return pluginCore.PluginProperties{}
+}
+
+func (benchmarkPlugin) Handle(ctx context.Context, tCtx pluginCore.TaskExecutionContext) (pluginCore.Transition, error) {
+	tk, err := tCtx.TaskReader().Read(ctx)
+	if err != nil {
+		return pluginCore.UnknownTransition, err
+	}
+
+	if tk.Type == benchmarkDynamicTaskType {
+		count, err := strconv.Atoi(tk.Config[benchmarkDynamicNodesKey])
+		if err != nil {
+			return pluginCore.UnknownTransition, err
+		}
+
+		ref, err := tCtx.DataStore().ConstructReference(ctx, tCtx.OutputWriter().GetOutputPrefixPath(), ioutils.FuturesSuffix)
+		if err != nil {
+			return pluginCore.UnknownTransition, err
+		}
+
+		task := benchmarkTask()
+		djSpec := &core.DynamicJobSpec{
+			MinSuccesses: int64(count),
+			Tasks:        []*core.TaskTemplate{task},
+		}
+		for i := 0; i < count; i++ {
+			djSpec.Nodes = append(djSpec.Nodes, benchmarkTaskNode(fmt.Sprintf("dn%d", i), task.Id, constantBinding(int64(i))))
+		}
+
+		if err := tCtx.DataStore().WriteProtobuf(ctx, ref, storage.Options{}, djSpec); err != nil {
+			return pluginCore.UnknownTransition, err
+		}
+
+		return pluginCore.DoTransition(pluginCore.PhaseInfoSuccess(nil)), nil
+	}
+
+	inputs, err := tCtx.InputReader().Get(ctx)
+	if err != nil {
+		return pluginCore.UnknownTransition, err
+	}
+
+	x := inputs.Literals["x"].GetScalar().GetPrimitive().GetInteger()
+	outputs := &core.LiteralMap{Literals: map[string]*core.Literal{"y": coreutils.MustMakeLiteral(x + 1)}}
+	if err := tCtx.OutputWriter().Put(ctx, ioutils.NewInMemoryOutputReader(outputs, nil)); err != nil {
+		return pluginCore.UnknownTransition, err
+	}
+
+	return pluginCore.DoTransition(pluginCore.PhaseInfoSuccess(nil)), nil
+}
+
+func (benchmarkPlugin) Abort(_ context.Context, _ pluginCore.TaskExecutionContext) error {
+	return nil
+}
+
+func (benchmarkPlugin) Finalize(_ context.Context, _ pluginCore.TaskExecutionContext) error {
+	return nil
+}
+
+func integerVariables(names ...string) *core.VariableMap {
+	vars := &core.VariableMap{Variables: map[string]*core.Variable{}}
+	for _, name := range names {
+		vars.Variables[name] = &core.Variable{Type: &core.LiteralType{Type: &core.LiteralType_Simple{Simple: core.SimpleType_INTEGER}}}
+	}
+
+	return vars
+}
+
+func benchmarkTask() *core.TaskTemplate {
+	return &core.TaskTemplate{
+		Id:        &core.Identifier{ResourceType: core.ResourceType_TASK, Name: benchmarkTaskType, Version: "1"},
+		Type:      benchmarkTaskType,
+		Metadata:  &core.TaskMetadata{},
+		Interface: &core.TypedInterface{Inputs: integerVariables("x"), Outputs: integerVariables("y")},
+		Target:    &core.TaskTemplate_Container{Container: &core.Container{Image: "image://", Command: []string{"cmd"}}},
+	}
+}
+
+func benchmarkDynamicTask(width int) *core.TaskTemplate {
+	return &core.TaskTemplate{
+		Id:        &core.Identifier{ResourceType: core.ResourceType_TASK, Name: benchmarkDynamicTaskType, Version: "1"},
+		Type:      benchmarkDynamicTaskType,
+		Metadata:  &core.TaskMetadata{},
+		Interface: &core.TypedInterface{Inputs: integerVariables("x"), Outputs: integerVariables()},
+		Target:    &core.TaskTemplate_Container{Container: &core.Container{Image: "image://", Command: []string{"cmd"}}},
+		Config:    map[string]string{benchmarkDynamicNodesKey: strconv.Itoa(width)},
+	}
+}
+
+func constantBinding(x int64) *core.BindingData {
+	return &core.BindingData{Value: &core.BindingData_Scalar{Scalar: coreutils.MustMakeLiteral(x).GetScalar()}}
+}
+
+func promiseBinding(nodeID string) *core.BindingData {
+	return &core.BindingData{Value: &core.BindingData_Promise{Promise: &core.OutputReference{NodeId: nodeID, Var: "y"}}}
+}
+
+func benchmarkTaskNode(id string, taskID *core.Identifier, x *core.BindingData) *core.Node {
+	return &core.Node{
+		Id:       id,
+		Metadata: &core.NodeMetadata{Name: id},
+		Target: &core.Node_TaskNode{
+			TaskNode: &core.TaskNode{Reference: &core.TaskNode_ReferenceId{ReferenceId: taskID}},
+		},
+		Inputs: []*core.Binding{{Var: "x", Binding: x}},
+	}
+}
+
+// Compiles the nodes into a workflow and builds the FlyteWorkflow object propeller would evaluate.
+func buildBenchmarkWorkflow(b *testing.B, name string, tasks []*core.TaskTemplate, wfNodes []*core.Node) *v1alpha1.FlyteWorkflow {
+	compiledTasks := make([]*core.CompiledTask, 0, len(tasks))
+	for _, task := range tasks {
+		compiledTask, err := compiler.CompileTask(task)
+		require.NoError(b, err)
+		compiledTasks = append(compiledTasks, compiledTask)
+	}
+
+	wfTemplate := &core.WorkflowTemplate{
+		Id:        &core.Identifier{ResourceType: core.ResourceType_WORKFLOW, Name: name, Version: "1"},
+		Interface: &core.TypedInterface{Inputs: integerVariables(), Outputs: integerVariables()},
+		Nodes:     wfNodes,
+	}
+
+	closure, err := compiler.CompileWorkflow(wfTemplate, []*core.WorkflowTemplate{}, compiledTasks, []common.InterfaceProvider{})
+	require.NoError(b, err)
+
+	execID := &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: name}
+	wf, err := k8s.BuildFlyteWorkflow(closure, &core.LiteralMap{}, execID, "ns")
+	require.NoError(b, err)
+	wf.ExecutionID = v1alpha1.WorkflowExecutionIdentifier{WorkflowExecutionIdentifier: execID}
+	return wf
+}
+
+func wideFanOutWorkflow(b *testing.B, width int) *v1alpha1.FlyteWorkflow {
+	task := benchmarkTask()
+	wfNodes := make([]*core.Node, 0, width)
+	for i := 0; i < width; i++ {
+		wfNodes = append(wfNodes, benchmarkTaskNode(fmt.Sprintf("n%d", i), task.Id, constantBinding(int64(i))))
+	}
+
+	return buildBenchmarkWorkflow(b, "fan-out", []*core.TaskTemplate{task}, wfNodes)
+}
+
+func deepChainWorkflow(b *testing.B, depth int) *v1alpha1.FlyteWorkflow {
+	task := benchmarkTask()
+	wfNodes := []*core.Node{benchmarkTaskNode("n0", task.Id, constantBinding(0))}
+	for i := 1; i < depth; i++ {
+		wfNodes = append(wfNodes, benchmarkTaskNode(fmt.Sprintf("n%d", i), task.Id, promiseBinding(fmt.Sprintf("n%d", i-1))))
+	}
+
+	return buildBenchmarkWorkflow(b, "chain", []*core.TaskTemplate{task}, wfNodes)
+}
+
+func heavyDynamicWorkflow(b *testing.B, parents, width int) *v1alpha1.FlyteWorkflow {
+	task := benchmarkDynamicTask(width)
+	wfNodes := make([]*core.Node, 0, parents)
+	for i := 0; i < parents; i++ {
+		wfNodes = append(wfNodes, benchmarkTaskNode(fmt.Sprintf("d%d", i), task.Id, constantBinding(int64(i))))
+	}
+
+	return buildBenchmarkWorkflow(b, "dynamic", []*core.TaskTemplate{task}, wfNodes)
+}
+
+func newBenchmarkExecutor(b *testing.B) executors.Workflow {
+	registerBenchmarkPlugin.Do(func() {
+		pluginmachinery.PluginRegistry().RegisterCorePlugin(pluginCore.PluginEntry{
+			ID:                  benchmarkTaskType,
+			RegisteredTaskTypes: []pluginCore.TaskType{benchmarkTaskType, benchmarkDynamicTaskType},
+			LoadPlugin: func(_ context.Context, _ pluginCore.SetupContext) (pluginCore.Plugin, error) {
+				return benchmarkPlugin{}, nil
+			},
+		})
+	})
+
+	ctx := context.Background()
+	scope := promutils.NewTestScope()
+	store := createInmemoryDataStore(b, scope.NewSubScope("store"))
+	enqueueWorkflow := func(workflowId v1alpha1.WorkflowID) {}
+	eventSink := events.NewMockEventSink()
+	adminClient := launchplan.NewFailFastLaunchPlanExecutor()
+	nodeExec, err := nodes.NewExecutor(ctx, config.GetConfig().NodeConfig, store, enqueueWorkflow, eventSink, adminClient,
+		adminClient, maxOutputSize, "s3://bucket", fakeKubeClient, catalog.NOOPCatalog{}, &recoveryMocks.RecoveryClient{},
+		scope.NewSubScope("node"))
+	require.NoError(b, err)
+
+	executor, err := NewExecutor(ctx, store, enqueueWorkflow, eventSink, &record.FakeRecorder{}, "", nodeExec,
+		scope.NewSubScope("workflow"))
+	require.NoError(b, err)
+	require.NoError(b, executor.Initialize(ctx))
+	return executor
+}
+
+// Evaluates copies of the workflow until they succeed and reports the cost per round. Fails if a round allocates more
+// than the budget on average.
+func benchmarkRounds(b *testing.B, w *v1alpha1.FlyteWorkflow, allocsPerRoundBudget float64) {
+	ctx := context.Background()
+	executor := newBenchmarkExecutor(b)
+	const maxRounds = 1000
+
+	var before, after runtime.MemStats
+	rounds := 0
+	b.ReportAllocs()
+	b.ResetTimer()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		wf := w.DeepCopy()
+		for round := 0; round < maxRounds && !wf.GetExecutionStatus().IsTerminated(); round++ {
+			if err := executor.HandleFlyteWorkflow(ctx, wf); err != nil {
+				b.Fatalf("Evaluation failed in round [%d]. Error: %v", round, err)
+			}
+
+			for _, s := range wf.Status.NodeStatus {
+				s.ResetDirty()
+			}
+
+			rounds++
+		}
+
+		if wf.GetExecutionStatus().GetPhase() != v1alpha1.WorkflowPhaseSuccess {
+			b.Fatalf("Workflow ended in phase [%v]. Message: %v", wf.GetExecutionStatus().GetPhase(),
+				wf.GetExecutionStatus().GetMessage())
+		}
+	}
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	b.StopTimer()
+
+	allocsPerRound := float64(after.Mallocs-before.Mallocs) / float64(rounds)
+	b.ReportMetric(float64(rounds)/float64(b.N), "rounds/op")
+	b.ReportMetric(float64(elapsed.Nanoseconds())/float64(rounds), "ns/round")
+	b.ReportMetric(allocsPerRound, "allocs/round")
+	assert.LessOrEqual(b, allocsPerRound, allocsPerRoundBudget,
+		"Evaluation rounds allocate more than their budget. Update the budget if the increase is intended.")
+}
+
+func BenchmarkEvaluationRound_WideFanOut(b *testing.B) {
+	benchmarkRounds(b, wideFanOutWorkflow(b, 100), 30000)
+}
+
+func BenchmarkEvaluationRound_DeepChain(b *testing.B) {
+	benchmarkRounds(b, deepChainWorkflow(b, 50), 2500)
+}
+
+func BenchmarkEvaluationRound_HeavyDynamic(b *testing.B) {
+	benchmarkRounds(b, heavyDynamicWorkflow(b, 5, 20), 32000)
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type workflowLockMetrics struct {
+	Contention prometheus.Counter
+	WaitTime   promutils.StopWatch
+	Held       prometheus.Gauge
+}
+
+type workflowLock struct {
+	sync.Mutex
+	// Number of workers that hold or wait for the lock. The lock is removed from the registry when it drops to 0.
+	refs int
+}
+
+// workflowLockRegistry hands out one mutex per workflow key, so that a workflow is never evaluated by two workers of
+// the same replica at the same time. The workqueue already avoids this for a single queue, but a key that was requeued
+// into another queue of the composite workqueue while a worker processes it can otherwise be picked up concurrently.
+// Locks only exist while they are held or waited for, so the registry does not grow with the number of workflows.
+type workflowLockRegistry struct {
+	mutex   sync.Mutex
+	locks   map[string]*workflowLock
+	metrics workflowLockMetrics
+}
+
+// Lock blocks until the caller holds the lock of the key. The returned function releases it.
+func (r *workflowLockRegistry) Lock(key string) (unlock func()) {
+	r.mutex.Lock()
+	lock, ok := r.locks[key]
+	if !ok {
+		lock = &workflowLock{}
+		r.locks[key] = lock
+	}
+	lock.refs++
+	contended := lock.refs > 1
+	r.mutex.Unlock()
+
+	if contended {
+		r.metrics.Contention.Inc()
+		t := r.metrics.WaitTime.Start()
+		lock.Lock()
+		t.Stop()
+	} else {
+		lock.Lock()
+	}
+
+	r.metrics.Held.Inc()
+	return func() {
+		r.metrics.Held.Dec()
+		lock.Unlock()
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(r.locks, key)
+		}
+	}
+}
+
+func newWorkflowLockRegistry(scope promutils.Scope) *workflowLockRegistry {
+	lockScope := scope.NewSubScope("workflow_lock")
+	return &workflowLockRegistry{
+		locks: map[string]*workflowLock{},
+		metrics: workflowLockMetrics{
+			Contention: lockScope.MustNewCounter("contention_count", "Number of rounds that waited for another worker to finish evaluating the same workflow"),
+			WaitTime:   lockScope.MustNewStopWatch("wait_time", "Time rounds waited for another worker to finish evaluating the same workflow", time.Millisecond),
+			Held:       lockScope.MustNewGauge("held_count", "Number of workflows currently being evaluated"),
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowLockRegistry(t *testing.T) {
+	t.Run("same key is never held concurrently", func(t *testing.T) {
+		r := newWorkflowLockRegistry(promutils.NewTestScope())
+		var holders, maxHolders int32
+		wg := sync.WaitGroup{}
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock := r.Lock("ns/wf")
+				defer unlock()
+				current := atomic.AddInt32(&holders, 1)
+				if current > atomic.LoadInt32(&maxHolders) {
+					atomic.StoreInt32(&maxHolders, current)
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&holders, -1)
+			}()
+		}
+
+		wg.Wait()
+		assert.Equal(t, int32(1), maxHolders)
+		assert.Empty(t, r.locks)
+	})
+
+	t.Run("different keys do not block each other", func(t *testing.T) {
+		r := newWorkflowLockRegistry(promutils.NewTestScope())
+		unlock := r.Lock("ns/wf-1")
+		defer unlock()
+
+		done := make(chan struct{})
+		go func() {
+			r.Lock("ns/wf-2")()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "lock of another key was blocked")
+		}
+
+		assert.Len(t, r.locks, 1)
+	})
+}
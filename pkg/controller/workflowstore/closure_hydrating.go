@@ -0,0 +1,159 @@
+package workflowstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+)
+
+type closureHydratingMetrics struct {
+	cacheHit         prometheus.Counter
+	cacheMiss        prometheus.Counter
+	checksumMismatch prometheus.Counter
+}
+
+// A store that builds the spec, subworkflows and tasks of workflows that reference a compiled closure offloaded by
+// admin, rather than embedding them. The closures are trusted as compiled by admin, they are only verified against the
+// checksum of the reference. The built workflows are cached by URI and checksum, so that each closure is only read and
+// built once rather than in every round. The built fields are left out of the workflows written back.
+type closureHydrating struct {
+	w       FlyteWorkflow
+	store   *storage.DataStore
+	cache   *lru.Cache
+	metrics *closureHydratingMetrics
+}
+
+func (c *closureHydrating) hydrate(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+	ref := w.ClosureReference
+	key := fmt.Sprintf("%v@%v", ref.URI, ref.Checksum)
+	if built, ok := c.cache.Get(key); ok {
+		c.metrics.cacheHit.Inc()
+		attachClosure(w, built.(*v1alpha1.FlyteWorkflow))
+		return nil
+	}
+
+	c.metrics.cacheMiss.Inc()
+	reader, err := c.store.ReadRaw(ctx, ref.URI)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Debugf(ctx, "Failed to close workflow closure reader. Error: %v", closeErr)
+		}
+	}()
+
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(raw)
+	if checksum := hex.EncodeToString(sum[:]); checksum != ref.Checksum {
+		c.metrics.checksumMismatch.Inc()
+		return fmt.Errorf("checksum [%v] of workflow closure [%v] does not match the expected [%v]", checksum, ref.URI, ref.Checksum)
+	}
+
+	wfClosure := &core.CompiledWorkflowClosure{}
+	if err := proto.Unmarshal(raw, wfClosure); err != nil {
+		return err
+	}
+
+	built := &v1alpha1.FlyteWorkflow{}
+	if err := k8s.HydrateFlyteWorkflow(wfClosure, built); err != nil {
+		return fmt.Errorf("failed to build workflow from closure [%v], error: %w", ref.URI, err)
+	}
+
+	c.cache.Add(key, built)
+	attachClosure(w, built)
+	return nil
+}
+
+// Sets the spec, subworkflows and tasks of the workflow to those of the given one.
+func attachClosure(w, from *v1alpha1.FlyteWorkflow) {
+	w.WorkflowSpec = from.WorkflowSpec
+	w.SubWorkflows = from.SubWorkflows
+	w.Tasks = from.Tasks
+}
+
+// Returns a shallow copy of the workflow without the fields built from its closure, if it references one.
+func stripClosure(w *v1alpha1.FlyteWorkflow) *v1alpha1.FlyteWorkflow {
+	if w == nil || w.ClosureReference == nil {
+		return w
+	}
+
+	stripped := *w
+	attachClosure(&stripped, &v1alpha1.FlyteWorkflow{})
+	return &stripped
+}
+
+func (c *closureHydrating) Get(ctx context.Context, namespace, name string) (*v1alpha1.FlyteWorkflow, error) {
+	w, err := c.w.Get(ctx, namespace, name)
+	if err != nil || w == nil || w.ClosureReference == nil {
+		return w, err
+	}
+
+	if err := c.hydrate(ctx, w); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (c *closureHydrating) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	newWF, err = c.w.UpdateStatus(ctx, stripClosure(workflow), priorityClass)
+	if err != nil || newWF == nil || workflow.ClosureReference == nil {
+		return newWF, err
+	}
+
+	attachClosure(newWF, workflow)
+	return newWF, nil
+}
+
+func (c *closureHydrating) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	newWF, err = c.w.Update(ctx, stripClosure(workflow), priorityClass)
+	if err != nil || newWF == nil || workflow.ClosureReference == nil {
+		return newWF, err
+	}
+
+	attachClosure(newWF, workflow)
+	return newWF, nil
+}
+
+// NewClosureHydratingStore wraps the workflow store so that the workflows it returns are built from the compiled
+// closures they reference, which are read from the given data store.
+func NewClosureHydratingStore(workflowStore FlyteWorkflow, dataStore *storage.DataStore, cacheSize int,
+	scope promutils.Scope) (FlyteWorkflow, error) {
+
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &closureHydrating{
+		w:     workflowStore,
+		store: dataStore,
+		cache: cache,
+		metrics: &closureHydratingMetrics{
+			cacheHit:         scope.MustNewCounter("closure_cache_hit", "Number of workflow closures found in the cache"),
+			cacheMiss:        scope.MustNewCounter("closure_cache_miss", "Number of workflow closures read from the blob store"),
+			checksumMismatch: scope.MustNewCounter("closure_checksum_mismatch", "Number of workflow closures that did not match the checksum of their reference"),
+		},
+	}, nil
+}
@@ -0,0 +1,136 @@
+package workflowstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+// Returns copies of the workflows it stores, like the API server does.
+type copyingWorkflowStore struct {
+	*InmemoryWorkflowStore
+}
+
+func (c copyingWorkflowStore) Get(ctx context.Context, namespace, name string) (*v1alpha1.FlyteWorkflow, error) {
+	w, err := c.InmemoryWorkflowStore.Get(ctx, namespace, name)
+	return w.DeepCopy(), err
+}
+
+func (c copyingWorkflowStore) UpdateStatus(ctx context.Context, w *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	*v1alpha1.FlyteWorkflow, error) {
+	newWF, err := c.InmemoryWorkflowStore.UpdateStatus(ctx, w, priorityClass)
+	return newWF.DeepCopy(), err
+}
+
+func (c copyingWorkflowStore) Update(ctx context.Context, w *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	*v1alpha1.FlyteWorkflow, error) {
+	newWF, err := c.InmemoryWorkflowStore.Update(ctx, w, priorityClass)
+	return newWF.DeepCopy(), err
+}
+
+func TestClosureHydratingStore(t *testing.T) {
+	ctx := context.TODO()
+	dataStore, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	taskID := &core.Identifier{ResourceType: core.ResourceType_TASK, Project: "p", Domain: "d", Name: "t1", Version: "v"}
+	wfClosure := &core.CompiledWorkflowClosure{
+		Primary: &core.CompiledWorkflow{
+			Template: &core.WorkflowTemplate{
+				Id: &core.Identifier{ResourceType: core.ResourceType_WORKFLOW, Project: "p", Domain: "d", Name: "wf", Version: "v"},
+			},
+		},
+		Tasks: []*core.CompiledTask{{Template: &core.TaskTemplate{Id: taskID, Type: "container"}}},
+	}
+
+	raw, err := proto.Marshal(wfClosure)
+	assert.NoError(t, err)
+	uri := storage.DataReference("s3://bucket/closures/wf.pb")
+	assert.NoError(t, dataStore.WriteRaw(ctx, uri, int64(len(raw)), storage.Options{}, bytes.NewReader(raw)))
+	sum := sha256.Sum256(raw)
+	ref := &v1alpha1.WorkflowClosureReference{URI: uri, Checksum: hex.EncodeToString(sum[:])}
+
+	newStore := func(w *v1alpha1.FlyteWorkflow) (copyingWorkflowStore, *closureHydrating) {
+		inner := copyingWorkflowStore{NewInMemoryWorkflowStore()}
+		assert.NoError(t, inner.Create(ctx, w))
+		s, err := NewClosureHydratingStore(inner, dataStore, 10, promutils.NewTestScope())
+		assert.NoError(t, err)
+		return inner, s.(*closureHydrating)
+	}
+
+	t.Run("hydrated", func(t *testing.T) {
+		inner, s := newStore(&v1alpha1.FlyteWorkflow{
+			ObjectMeta:       v1.ObjectMeta{Namespace: "ns", Name: "a"},
+			ClosureReference: ref,
+		})
+
+		for i := 0; i < 2; i++ {
+			w, err := s.Get(ctx, "ns", "a")
+			assert.NoError(t, err)
+			if assert.NotNil(t, w.WorkflowSpec) {
+				assert.Equal(t, "p:d:wf", w.ID)
+			}
+			assert.Contains(t, w.Tasks, taskID.String())
+		}
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.cacheMiss))
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.cacheHit))
+
+		w, err := s.Get(ctx, "ns", "a")
+		assert.NoError(t, err)
+		newWF, err := s.UpdateStatus(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+		assert.NotNil(t, newWF.WorkflowSpec)
+		assert.NotEmpty(t, newWF.Tasks)
+
+		// The built fields are not written back.
+		written, err := inner.Get(ctx, "ns", "a")
+		assert.NoError(t, err)
+		assert.Nil(t, written.WorkflowSpec)
+		assert.Empty(t, written.Tasks)
+		assert.NotNil(t, w.WorkflowSpec)
+	})
+
+	t.Run("checksum-mismatch", func(t *testing.T) {
+		_, s := newStore(&v1alpha1.FlyteWorkflow{
+			ObjectMeta:       v1.ObjectMeta{Namespace: "ns", Name: "a"},
+			ClosureReference: &v1alpha1.WorkflowClosureReference{URI: uri, Checksum: "deadbeef"},
+		})
+
+		w, err := s.Get(ctx, "ns", "a")
+		assert.Error(t, err)
+		assert.Nil(t, w)
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.checksumMismatch))
+	})
+
+	t.Run("no-reference", func(t *testing.T) {
+		spec := &v1alpha1.WorkflowSpec{ID: "embedded"}
+		inner, s := newStore(&v1alpha1.FlyteWorkflow{
+			ObjectMeta:   v1.ObjectMeta{Namespace: "ns", Name: "a"},
+			WorkflowSpec: spec,
+		})
+
+		w, err := s.Get(ctx, "ns", "a")
+		assert.NoError(t, err)
+		assert.Equal(t, spec, w.WorkflowSpec)
+
+		_, err = s.Update(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+		written, err := inner.Get(ctx, "ns", "a")
+		assert.NoError(t, err)
+		assert.Equal(t, "embedded", written.ID)
+		assert.Equal(t, float64(0), testutil.ToFloat64(s.metrics.cacheMiss))
+	})
+}
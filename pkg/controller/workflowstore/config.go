@@ -21,7 +21,8 @@ const (
 // By default we will use the ResourceVersionCache example
 var (
 	defaultConfig = &Config{
-		Policy: PolicyResourceVersionCache,
+		Policy:           PolicyResourceVersionCache,
+		ClosureCacheSize: 1000,
 	}
 
 	configSection = ctrlConfig.MustRegisterSubSection("workflowStore", defaultConfig)
@@ -30,7 +31,8 @@ var (
 // Config for Workflow access in the controller.
 // Various policies are available like - InMemory, PassThrough, ResourceVersionCache
 type Config struct {
-	Policy Policy `json:"policy" pflag:",Workflow Store Policy to initialize"`
+	Policy           Policy `json:"policy" pflag:",Workflow Store Policy to initialize"`
+	ClosureCacheSize int    `json:"closure-cache-size" pflag:",Number of compiled workflow closures offloaded by admin to keep in memory."`
 }
 
 func GetConfig() *Config {
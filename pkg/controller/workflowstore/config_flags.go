@@ -51,5 +51,6 @@ func (Config) mustMarshalJSON(v json.Marshaler) string {
 func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags := pflag.NewFlagSet("Config", pflag.ExitOnError)
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "policy"), defaultConfig.Policy, "Workflow Store Policy to initialize")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "closure-cache-size"), defaultConfig.ClosureCacheSize, "Number of compiled workflow closures offloaded by admin to keep in memory.")
 	return cmdFlags
 }
@@ -113,4 +113,18 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_closure-cache-size", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("closure-cache-size", testValue)
+			if vInt, err := cmdFlags.GetInt("closure-cache-size"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.ClosureCacheSize)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
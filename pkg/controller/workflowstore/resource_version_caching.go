@@ -112,6 +112,15 @@ func (r *resourceVersionCaching) Update(ctx context.Context, workflow *v1alpha1.
 	return newWF, nil
 }
 
+// ShedCache forgets the last observed resource versions. Until a workflow is updated again, stale copies of it are no
+// longer detected and will be evaluated, any resulting update is rejected by the API server as a conflict.
+func (r *resourceVersionCaching) ShedCache(ctx context.Context) {
+	r.lastUpdatedResourceVersionCache.Range(func(key, _ interface{}) bool {
+		r.lastUpdatedResourceVersionCache.Delete(key)
+		return true
+	})
+}
+
 func NewResourceVersionCachingStore(_ context.Context, scope promutils.Scope, workflowStore FlyteWorkflow) FlyteWorkflow {
 	return &resourceVersionCaching{
 		w: workflowStore,
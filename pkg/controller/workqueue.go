@@ -4,10 +4,10 @@ import (
 	"context"
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 
 	"golang.org/x/time/rate"
 
-	"github.com/flyteorg/flytestdlib/logger"
 	// Setup workqueue metrics
 	_ "github.com/flyteorg/flytestdlib/promutils"
 	"k8s.io/client-go/util/workqueue"
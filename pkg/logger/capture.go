@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// LogBuffer keeps the last lines logged with a context it is attached to, at any level, so that they can be looked at
+// when something fails without raising the level of the logger.
+type LogBuffer struct {
+	lock  sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+type logBufferContextKey struct{}
+
+// WithLogBuffer returns a context whose log lines are also kept in the given buffer.
+func WithLogBuffer(ctx context.Context, buf *LogBuffer) context.Context {
+	return context.WithValue(ctx, logBufferContextKey{}, buf)
+}
+
+func getLogBuffer(ctx context.Context) *LogBuffer {
+	buf, _ := ctx.Value(logBufferContextKey{}).(*LogBuffer)
+	return buf
+}
+
+// NewLogBuffer creates a buffer that keeps the last size lines.
+func NewLogBuffer(size int) *LogBuffer {
+	if size < 1 {
+		size = 1
+	}
+
+	return &LogBuffer{lines: make([]string, size)}
+}
+
+func (b *LogBuffer) add(line string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Lines returns the lines in the buffer, oldest first.
+func (b *LogBuffer) Lines() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.full {
+		return append([]string(nil), b.lines[:b.next]...)
+	}
+
+	return append(append([]string(nil), b.lines[b.next:]...), b.lines[:b.next]...)
+}
+
+// String returns the lines in the buffer, oldest first, one per line.
+func (b *LogBuffer) String() string {
+	lines := b.Lines()
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Keeps the message in the buffer of the context, if any. The message is only formatted if there is a buffer.
+func capture(ctx context.Context, level logger.Level, msg func() string) {
+	buf := getLogBuffer(ctx)
+	if buf == nil {
+		return
+	}
+
+	buf.add(fmt.Sprintf("%s [%s] %s", time.Now().UTC().Format(time.RFC3339Nano), logrus.Level(level), msg()))
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogBuffer(t *testing.T) {
+	buf := NewLogBuffer(3)
+	assert.Empty(t, buf.Lines())
+	assert.Empty(t, buf.String())
+
+	for _, l := range []string{"a", "b"} {
+		buf.add(l)
+	}
+	assert.Equal(t, []string{"a", "b"}, buf.Lines())
+
+	for _, l := range []string{"c", "d", "e"} {
+		buf.add(l)
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, buf.Lines())
+	assert.Equal(t, "c\nd\ne\n", buf.String())
+}
+
+func TestCapture(t *testing.T) {
+	// Captured whatever the level of the logger.
+	buf := captureLogs(t, nil)
+	logs := NewLogBuffer(10)
+	ctx := WithLogBuffer(context.TODO(), logs)
+
+	Debugf(ctx, "debug %d", 1)
+	Warn(ctx, "warn")
+	Infof(context.TODO(), "not captured")
+
+	if lines := logs.Lines(); assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], "[debug] debug 1")
+		assert.Contains(t, lines[1], "[warning] warn")
+	}
+	assert.NotContains(t, buf.String(), "debug 1")
+}
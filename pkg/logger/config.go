@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/logger"
+)
+
+const SectionKey = "module-logger"
+
+var (
+	defaultConfig = &Config{
+		Levels: map[string]logger.Level{},
+	}
+
+	section = config.MustRegisterSection(SectionKey, defaultConfig)
+)
+
+// Config overrides the level of the flytestdlib logger config for some modules. It is read on every log call, so that
+// the levels can be changed at runtime by updating the config file.
+type Config struct {
+	// Levels by module, i.e. by the path of a package under pkg/, e.g. "controller/nodes/task". A module also applies to
+	// the packages under it, the most specific module wins.
+	Levels map[string]logger.Level `json:"levels" pflag:"-,Log levels by module, overriding the level of the logger config."`
+}
+
+func GetConfig() *Config {
+	return section.GetConfig().(*Config)
+}
+
+func SetConfig(cfg *Config) error {
+	return section.SetConfig(cfg)
+}
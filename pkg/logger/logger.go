@@ -0,0 +1,267 @@
+// Package logger logs like the flytestdlib logger, with the same config, adding the correlation keys of the execution
+// the context carries to the fields of every log line, and honoring the levels configured for the module logging. Lines
+// logged with a context that carries a LogBuffer are also kept in it, whatever their level.
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AttemptKey is the attempt of the node the context is about.
+	AttemptKey contextutils.Key = "attempt"
+
+	modulePrefix  = "github.com/flyteorg/flytepropeller/pkg/"
+	sourceCodeKey = "src"
+)
+
+// Correlation keys the flytestdlib logger does not log.
+var correlationKeys = []contextutils.Key{contextutils.ProjectKey, contextutils.DomainKey, AttemptKey}
+
+// Loggers of the modules that log at a more verbose level than the standard logger, by level. They log like the
+// standard logger, and are rebuilt once it is reconfigured, i.e. once the logger config is set and a new formatter
+// installed.
+var verboseLoggers = struct {
+	sync.Mutex
+	out       io.Writer
+	formatter logrus.Formatter
+	loggers   map[logrus.Level]*logrus.Logger
+}{}
+
+// Returns the logger that logs like the standard logger at the given level.
+func getVerboseLogger(level logrus.Level) *logrus.Logger {
+	verboseLoggers.Lock()
+	defer verboseLoggers.Unlock()
+
+	std := logrus.StandardLogger()
+	if verboseLoggers.out != std.Out || verboseLoggers.formatter != std.Formatter {
+		verboseLoggers.out = std.Out
+		verboseLoggers.formatter = std.Formatter
+		verboseLoggers.loggers = map[logrus.Level]*logrus.Logger{}
+	}
+
+	if l, ok := verboseLoggers.loggers[level]; ok {
+		return l
+	}
+
+	l := &logrus.Logger{
+		Out:       std.Out,
+		Formatter: std.Formatter,
+		Hooks:     std.Hooks,
+		Level:     level,
+		ExitFunc:  std.ExitFunc,
+	}
+
+	verboseLoggers.loggers[level] = l
+	return l
+}
+
+// WithAttempt returns a context with the attempt of its node set.
+func WithAttempt(ctx context.Context, attempt uint32) context.Context {
+	return context.WithValue(ctx, AttemptKey, strconv.FormatUint(uint64(attempt), 10))
+}
+
+// Returns the module of the function at the given pc, i.e. the path of its package under pkg/.
+func moduleOf(pc uintptr) string {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	// e.g. github.com/flyteorg/flytepropeller/pkg/controller/nodes/task.(*Handler).Abort
+	name := fn.Name()
+	if slash := strings.LastIndex(name, "/"); slash >= 0 {
+		if dot := strings.Index(name[slash:], "."); dot >= 0 {
+			name = name[:slash+dot]
+		}
+	}
+
+	return strings.TrimPrefix(name, modulePrefix)
+}
+
+// Returns the level of the most specific module configured for the given one.
+func levelOf(levels map[string]logger.Level, module string) (logger.Level, bool) {
+	level, longest, found := logger.Level(0), -1, false
+	for m, l := range levels {
+		if len(m) > longest && (module == m || strings.HasPrefix(module, m+"/")) {
+			level, longest, found = l, len(m), true
+		}
+	}
+
+	return level, found
+}
+
+// Returns the entry to log at the given level with, or false if the level is disabled for the module of the caller.
+func getEntry(ctx context.Context, level logger.Level) (*logrus.Entry, bool) {
+	cfg := logger.GetConfig()
+	if cfg.Mute {
+		return nil, false
+	}
+
+	threshold := cfg.Level
+	fields := contextutils.GetLogFields(ctx)
+	if levels := GetConfig().Levels; len(levels) > 0 || cfg.IncludeSourceCode {
+		// 0 is this function, 1 the logging function and 2 its caller.
+		pc, file, line, ok := runtime.Caller(2)
+		if ok {
+			if l, found := levelOf(levels, moduleOf(pc)); found {
+				threshold = l
+			}
+
+			if cfg.IncludeSourceCode {
+				fields[sourceCodeKey] = fmt.Sprintf("%v:%v", file[strings.LastIndex(file, "/")+1:], line)
+			}
+		}
+	}
+
+	if level > threshold {
+		return nil, false
+	}
+
+	for _, k := range correlationKeys {
+		if v := ctx.Value(k); v != nil {
+			fields[k.String()] = v
+		}
+	}
+
+	std := logrus.StandardLogger()
+	if logrus.Level(threshold) <= std.GetLevel() {
+		return std.WithFields(fields), true
+	}
+
+	// The module logs at a more verbose level than the standard logger.
+	return getVerboseLogger(logrus.Level(threshold)).WithFields(fields), true
+}
+
+// Debug logs a message at level Debug.
+func Debug(ctx context.Context, args ...interface{}) {
+	capture(ctx, logger.DebugLevel, func() string { return fmt.Sprint(args...) })
+	if e, ok := getEntry(ctx, logger.DebugLevel); ok {
+		e.Debug(args...)
+	}
+}
+
+// Debugf logs a formatted message at level Debug.
+func Debugf(ctx context.Context, format string, args ...interface{}) {
+	capture(ctx, logger.DebugLevel, func() string { return fmt.Sprintf(format, args...) })
+	if e, ok := getEntry(ctx, logger.DebugLevel); ok {
+		e.Debugf(format, args...)
+	}
+}
+
+// Info logs a message at level Info.
+func Info(ctx context.Context, args ...interface{}) {
+	capture(ctx, logger.InfoLevel, func() string { return fmt.Sprint(args...) })
+	if e, ok := getEntry(ctx, logger.InfoLevel); ok {
+		e.Info(args...)
+	}
+}
+
+// Infof logs a formatted message at level Info.
+func Infof(ctx context.Context, format string, args ...interface{}) {
+	capture(ctx, logger.InfoLevel, func() string { return fmt.Sprintf(format, args...) })
+	if e, ok := getEntry(ctx, logger.InfoLevel); ok {
+		e.Infof(format, args...)
+	}
+}
+
+// InfofNoCtx logs a formatted message at level Info, without the fields of a context.
+func InfofNoCtx(format string, args ...interface{}) {
+	if e, ok := getEntry(context.TODO(), logger.InfoLevel); ok {
+		e.Infof(format, args...)
+	}
+}
+
+// Warn logs a message at level Warn.
+func Warn(ctx context.Context, args ...interface{}) {
+	capture(ctx, logger.WarnLevel, func() string { return fmt.Sprint(args...) })
+	if e, ok := getEntry(ctx, logger.WarnLevel); ok {
+		e.Warn(args...)
+	}
+}
+
+// Warnf logs a formatted message at level Warn.
+func Warnf(ctx context.Context, format string, args ...interface{}) {
+	capture(ctx, logger.WarnLevel, func() string { return fmt.Sprintf(format, args...) })
+	if e, ok := getEntry(ctx, logger.WarnLevel); ok {
+		e.Warnf(format, args...)
+	}
+}
+
+// Warningf logs a formatted message at level Warn.
+func Warningf(ctx context.Context, format string, args ...interface{}) {
+	capture(ctx, logger.WarnLevel, func() string { return fmt.Sprintf(format, args...) })
+	if e, ok := getEntry(ctx, logger.WarnLevel); ok {
+		e.Warningf(format, args...)
+	}
+}
+
+// Error logs a message at level Error.
+func Error(ctx context.Context, args ...interface{}) {
+	capture(ctx, logger.ErrorLevel, func() string { return fmt.Sprint(args...) })
+	if e, ok := getEntry(ctx, logger.ErrorLevel); ok {
+		e.Error(args...)
+	}
+}
+
+// Errorf logs a formatted message at level Error.
+func Errorf(ctx context.Context, format string, args ...interface{}) {
+	capture(ctx, logger.ErrorLevel, func() string { return fmt.Sprintf(format, args...) })
+	if e, ok := getEntry(ctx, logger.ErrorLevel); ok {
+		e.Errorf(format, args...)
+	}
+}
+
+// Fatal logs a message at level Fatal, then exits. It exits even if the level is disabled.
+func Fatal(ctx context.Context, args ...interface{}) {
+	capture(ctx, logger.FatalLevel, func() string { return fmt.Sprint(args...) })
+	e, ok := getEntry(ctx, logger.FatalLevel)
+	if !ok {
+		e = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	e.Fatal(args...)
+}
+
+// Fatalf logs a formatted message at level Fatal, then exits. It exits even if the level is disabled.
+func Fatalf(ctx context.Context, format string, args ...interface{}) {
+	capture(ctx, logger.FatalLevel, func() string { return fmt.Sprintf(format, args...) })
+	e, ok := getEntry(ctx, logger.FatalLevel)
+	if !ok {
+		e = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	e.Fatalf(format, args...)
+}
+
+// Panic logs a message at level Panic, then panics. It panics even if the level is disabled.
+func Panic(ctx context.Context, args ...interface{}) {
+	capture(ctx, logger.PanicLevel, func() string { return fmt.Sprint(args...) })
+	e, ok := getEntry(ctx, logger.PanicLevel)
+	if !ok {
+		e = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	e.Panic(args...)
+}
+
+// Panicf logs a formatted message at level Panic, then panics. It panics even if the level is disabled.
+func Panicf(ctx context.Context, format string, args ...interface{}) {
+	capture(ctx, logger.PanicLevel, func() string { return fmt.Sprintf(format, args...) })
+	e, ok := getEntry(ctx, logger.PanicLevel)
+	if !ok {
+		e = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	e.Panicf(format, args...)
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func captureLogs(t *testing.T, levels map[string]logger.Level) *bytes.Buffer {
+	assert.NoError(t, logger.SetConfig(&logger.Config{Level: logger.InfoLevel, Formatter: logger.FormatterConfig{Type: logger.FormatterJSON}}))
+	assert.NoError(t, SetConfig(&Config{Levels: levels}))
+	buf := &bytes.Buffer{}
+	logrus.SetOutput(buf)
+	t.Cleanup(func() { logrus.SetOutput(os.Stderr) })
+	return buf
+}
+
+func TestCorrelationKeys(t *testing.T) {
+	buf := captureLogs(t, nil)
+	ctx := contextutils.WithExecutionID(context.TODO(), "exec")
+	ctx = contextutils.WithProjectDomain(ctx, "project", "domain")
+	ctx = WithAttempt(contextutils.WithNodeID(ctx, "n1"), 2)
+
+	Infof(ctx, "hello %s", "world")
+
+	line := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "hello world", line["msg"])
+	fields := line["json"].(map[string]interface{})
+	assert.Equal(t, "exec", fields["exec_id"])
+	assert.Equal(t, "n1", fields["node"])
+	assert.Equal(t, "2", fields["attempt"])
+	assert.Equal(t, "project", fields["project"])
+	assert.Equal(t, "domain", fields["domain"])
+}
+
+func TestModuleLevels(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("default", func(t *testing.T) {
+		buf := captureLogs(t, nil)
+		Debugf(ctx, "debug")
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("more-verbose", func(t *testing.T) {
+		buf := captureLogs(t, map[string]logger.Level{"logger": logger.DebugLevel})
+		Debugf(ctx, "debug")
+		assert.Contains(t, buf.String(), "debug")
+	})
+
+	t.Run("less-verbose", func(t *testing.T) {
+		buf := captureLogs(t, map[string]logger.Level{"logger": logger.ErrorLevel})
+		Infof(ctx, "info")
+		assert.Empty(t, buf.String())
+		Errorf(ctx, "error")
+		assert.Contains(t, buf.String(), "error")
+	})
+
+	t.Run("other-module", func(t *testing.T) {
+		buf := captureLogs(t, map[string]logger.Level{"controller": logger.DebugLevel})
+		Debugf(ctx, "debug")
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestVerboseLoggers(t *testing.T) {
+	captureLogs(t, nil)
+	debug := getVerboseLogger(logrus.DebugLevel)
+	assert.Equal(t, logrus.DebugLevel, debug.GetLevel())
+	assert.Same(t, debug, getVerboseLogger(logrus.DebugLevel))
+	assert.NotSame(t, debug, getVerboseLogger(logrus.TraceLevel))
+
+	buf := captureLogs(t, nil)
+	rebuilt := getVerboseLogger(logrus.DebugLevel)
+	assert.NotSame(t, debug, rebuilt)
+	assert.Equal(t, buf, rebuilt.Out)
+}
+
+func TestLevelOf(t *testing.T) {
+	levels := map[string]logger.Level{
+		"controller":                 logger.WarnLevel,
+		"controller/nodes":           logger.InfoLevel,
+		"controller/nodes/task":      logger.DebugLevel,
+		"controller/nodes/task/k8s2": logger.ErrorLevel,
+	}
+
+	for module, expected := range map[string]logger.Level{
+		"controller":                  logger.WarnLevel,
+		"controller/workflow":         logger.WarnLevel,
+		"controller/nodes/branch":     logger.InfoLevel,
+		"controller/nodes/task":       logger.DebugLevel,
+		"controller/nodes/task/k8s":   logger.DebugLevel,
+		"controller/nodes/task/k8s2x": logger.DebugLevel,
+	} {
+		level, found := levelOf(levels, module)
+		assert.True(t, found, module)
+		assert.Equal(t, expected, level, module)
+	}
+
+	_, found := levelOf(levels, "controllers")
+	assert.False(t, found)
+}
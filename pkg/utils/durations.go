@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+)
+
+// ClampedDuration returns the time elapsed from start to end, or zero if end is before start, e.g. because the
+// timestamps were taken by propeller replicas whose clocks are skewed. It also returns whether the duration was clamped.
+func ClampedDuration(start, end time.Time) (time.Duration, bool) {
+	if end.Before(start) {
+		return 0, true
+	}
+
+	return end.Sub(start), false
+}
+
+// ObserveDuration records the time elapsed from start to end in the stop watch. Durations that would be negative are
+// recorded as zero and counted in negative, rather than skewing the stop watch.
+func ObserveDuration(ctx context.Context, sw labeled.StopWatch, negative labeled.Counter, start, end time.Time) {
+	if _, clamped := ClampedDuration(start, end); clamped {
+		negative.Inc(ctx)
+		end = start
+	}
+
+	sw.Observe(ctx, start, end)
+}
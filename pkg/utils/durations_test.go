@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/contextutils"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/promutils/labeled"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	labeled.SetMetricKeys(contextutils.ProjectKey)
+}
+
+func TestClampedDuration(t *testing.T) {
+	start := time.Now()
+
+	d, clamped := ClampedDuration(start, start.Add(time.Minute))
+	assert.Equal(t, time.Minute, d)
+	assert.False(t, clamped)
+
+	d, clamped = ClampedDuration(start, start.Add(-time.Minute))
+	assert.Equal(t, time.Duration(0), d)
+	assert.True(t, clamped)
+}
+
+func TestObserveDuration(t *testing.T) {
+	ctx := context.TODO()
+	scope := promutils.NewTestScope()
+	sw := labeled.NewStopWatch("duration", "", time.Millisecond, scope, labeled.EmitUnlabeledMetric)
+	negative := labeled.NewCounter("negative", "", scope, labeled.EmitUnlabeledMetric)
+
+	start := time.Now()
+	ObserveDuration(ctx, sw, negative, start, start.Add(time.Second))
+	ObserveDuration(ctx, sw, negative, start, start.Add(-time.Second))
+	assert.Equal(t, float64(1), testutil.ToFloat64(negative.Counter))
+}
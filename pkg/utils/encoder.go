@@ -11,8 +11,42 @@ const specialEncoderKey = "abcdefghijklmnopqrstuvwxyz123456"
 
 var Base32Encoder = base32.NewEncoding(specialEncoderKey).WithPadding(base32.NoPadding)
 
+// Returns the fnv32a hash of the value, encoded as 7 characters that are valid in DNS-1123 labels.
+func shortHash(value string) string {
+	hasher := fnv.New32a()
+	// Using 32a an error can never happen
+	_, _ = hasher.Write([]byte(value)) // #nosec
+	return Base32Encoder.EncodeToString(hasher.Sum(nil))
+}
+
+// TruncateWithHash returns the value unchanged if it is not longer than maxLength. Longer values are truncated and
+// suffixed with a short hash of the whole value, so that values that only differ past the truncation stay distinct while
+// their beginning remains readable. Values are never longer than maxLength, which leaves only the hash, or part of it,
+// for a maxLength too small to fit a prefix.
+func TruncateWithHash(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+
+	hash := shortHash(value)
+	if maxLength <= len(hash)+1 {
+		return hash[:minInt(len(hash), maxLength)]
+	}
+
+	return strings.TrimRight(value[:maxLength-len(hash)-1], "-._") + "-" + hash
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
 // Creates a new UniqueID that is based on the inputID and of a specified length, if the given id is longer than the
-// maxLength.
+// maxLength. The IDs are persisted in the status of workflows and used to name their resources, e.g. pods, so that the
+// scheme must stay stable across versions of propeller.
 func FixedLengthUniqueID(inputID string, maxLength int) (string, error) {
 	if len(inputID) <= maxLength {
 		return inputID, nil
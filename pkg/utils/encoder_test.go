@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func TestFixedLengthUniqueID(t *testing.T) {
@@ -59,3 +61,58 @@ func TestFixedLengthUniqueIDForParts(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateWithHash(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxLength int
+		output    string
+	}{
+		{"fits", "exec-n0-0", 63, "exec-n0-0"},
+		{"exactFit", "exec-n0-0", 9, "exec-n0-0"},
+		{"truncated", "myproject-development-workflowsomething", 32, "myproject-development-wo-y43rhcq"},
+		{"trailingSeparatorTrimmed", "myproject-development-x-workflowsomething", 32, "myproject-development-x-ve4ttby"},
+		{"onlyHash", "myproject-development-workflowsomething", 8, "y43rhcq"},
+		{"partOfHash", "myproject-development-workflowsomething", 3, "y43"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := TruncateWithHash(test.input, test.maxLength)
+			assert.Equal(t, test.output, output)
+			assert.LessOrEqual(t, len(output), test.maxLength)
+		})
+	}
+}
+
+func TestTruncateWithHash_Collisions(t *testing.T) {
+	// Names of the resources of different executions, nodes and attempts that share a long prefix.
+	prefix := strings.Repeat("x", 60)
+	names := sets.NewString()
+	inputs := 0
+	for _, node := range []string{"n0", "n1", "n0-0-dn0", "n0-0-dn1"} {
+		for _, attempt := range []string{"0", "1", "10"} {
+			names.Insert(TruncateWithHash(prefix+"-"+node+"-"+attempt, 63))
+			inputs++
+		}
+	}
+
+	assert.Equal(t, inputs, names.Len())
+}
+
+func TestFixedLengthUniqueIDForParts_Collisions(t *testing.T) {
+	// Generated names of the attempts of nodes whose names are hashed, as they are too long for the max length.
+	execution := strings.Repeat("e", 40)
+	ids := sets.NewString()
+	inputs := 0
+	for _, node := range []string{"n0", "n1", "n0-0-dn0", "n0-0-dn1"} {
+		for _, attempt := range []string{"0", "1", "10"} {
+			id, err := FixedLengthUniqueIDForParts(50, execution, node, attempt)
+			assert.NoError(t, err)
+			ids.Insert(id)
+			inputs++
+		}
+	}
+
+	assert.Equal(t, inputs, ids.Len())
+}
@@ -104,12 +104,15 @@ func GetProtoTime(t *metav1.Time) *timestamp.Timestamp {
 	return ptypes.TimestampNow()
 }
 
-// SanitizeLabelValue ensures that the label value is a valid DNS-1123 string
+// SanitizeLabelValue ensures that the label value is a valid DNS-1123 string. Names that are too long are truncated with
+// a hash of the whole name, so that names that only differ past the truncation get distinct labels.
 func SanitizeLabelValue(name string) string {
-	name = strings.ToLower(name)
-	name = invalidDNS1123Characters.ReplaceAllString(name, "-")
-	if len(name) > validation.DNS1123LabelMaxLength {
-		name = name[0:validation.DNS1123LabelMaxLength]
+	sanitized := strings.ToLower(name)
+	sanitized = invalidDNS1123Characters.ReplaceAllString(sanitized, "-")
+	sanitized = strings.Trim(sanitized, "-")
+	if len(sanitized) > validation.DNS1123LabelMaxLength {
+		hash := shortHash(name)
+		sanitized = strings.Trim(sanitized[:validation.DNS1123LabelMaxLength-len(hash)-1], "-") + "-" + hash
 	}
-	return strings.Trim(name, "-")
+	return sanitized
 }
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var (
+	namespaceProjectPattern = regexp.MustCompile(`{{\s*project\s*}}`)
+	namespaceDomainPattern  = regexp.MustCompile(`{{\s*domain\s*}}`)
+)
+
+// GetNamespaceName returns the namespace of the executions of the project and domain, as mapped by the template, e.g.
+// "{{ project }}-{{ domain }}", "flyte-{{ domain }}" or a static namespace.
+func GetNamespaceName(template, project, domain string) string {
+	namespace := namespaceProjectPattern.ReplaceAllLiteralString(template, project)
+	return namespaceDomainPattern.ReplaceAllLiteralString(namespace, domain)
+}
+
+// ValidateNamespaceTemplate checks that the template maps a project and domain to a valid namespace.
+func ValidateNamespaceTemplate(template string) error {
+	if errs := validation.IsDNS1123Label(GetNamespaceName(template, "project", "domain")); len(errs) > 0 {
+		return fmt.Errorf("namespace template [%v] does not map to a valid namespace: %v", template, errs)
+	}
+
+	return nil
+}
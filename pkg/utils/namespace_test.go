@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNamespaceName(t *testing.T) {
+	assert.Equal(t, "flytesnacks-development", GetNamespaceName("{{ project }}-{{ domain }}", "flytesnacks", "development"))
+	assert.Equal(t, "flytesnacks", GetNamespaceName("{{project}}", "flytesnacks", "development"))
+	assert.Equal(t, "flyte-development", GetNamespaceName("flyte-{{  domain }}", "flytesnacks", "development"))
+	assert.Equal(t, "flyte", GetNamespaceName("flyte", "flytesnacks", "development"))
+}
+
+func TestValidateNamespaceTemplate(t *testing.T) {
+	assert.NoError(t, ValidateNamespaceTemplate("{{ project }}-{{ domain }}"))
+	assert.NoError(t, ValidateNamespaceTemplate("flyte"))
+	assert.Error(t, ValidateNamespaceTemplate("{{ project }}_{{ domain }}"))
+	assert.Error(t, ValidateNamespaceTemplate(""))
+}
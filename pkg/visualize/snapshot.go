@@ -0,0 +1,95 @@
+package visualize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+// GraphSnapshot describes the nodes of a workflow, the edges between them and their current phases. Only the nodes of
+// the workflow itself are described, nodes of its subworkflows and branches are described by their parent node.
+type GraphSnapshot struct {
+	WorkflowID string              `json:"workflowId"`
+	Phase      string              `json:"phase"`
+	Nodes      []GraphSnapshotNode `json:"nodes"`
+	Edges      []GraphSnapshotEdge `json:"edges"`
+}
+
+type GraphSnapshotNode struct {
+	ID    v1alpha1.NodeID `json:"id"`
+	Name  string          `json:"name,omitempty"`
+	Kind  string          `json:"kind"`
+	Phase string          `json:"phase"`
+}
+
+type GraphSnapshotEdge struct {
+	From v1alpha1.NodeID `json:"from"`
+	To   v1alpha1.NodeID `json:"to"`
+}
+
+// NewGraphSnapshot describes the current state of the workflow. Nodes and edges are sorted, so that snapshots of
+// workflows in the same state are equal.
+func NewGraphSnapshot(w *v1alpha1.FlyteWorkflow) *GraphSnapshot {
+	s := &GraphSnapshot{
+		WorkflowID: w.GetID(),
+		Phase:      w.GetExecutionStatus().GetPhase().String(),
+		Nodes:      make([]GraphSnapshotNode, 0, len(w.Nodes)),
+	}
+
+	for id, n := range w.Nodes {
+		phase := v1alpha1.NodePhaseNotYetStarted
+		if status, found := w.Status.NodeStatus[id]; found {
+			phase = status.GetPhase()
+		}
+
+		s.Nodes = append(s.Nodes, GraphSnapshotNode{ID: id, Name: n.GetName(), Kind: string(n.GetKind()), Phase: phase.String()})
+	}
+
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].ID < s.Nodes[j].ID })
+
+	for from, downstream := range w.GetConnections().Downstream {
+		for _, to := range downstream {
+			s.Edges = append(s.Edges, GraphSnapshotEdge{From: from, To: to})
+		}
+	}
+
+	sort.Slice(s.Edges, func(i, j int) bool {
+		if s.Edges[i].From != s.Edges[j].From {
+			return s.Edges[i].From < s.Edges[j].From
+		}
+
+		return s.Edges[i].To < s.Edges[j].To
+	})
+
+	return s
+}
+
+// Phases returns the phase of the workflow and of each of its nodes. Two snapshots of the same workflow only differ if
+// their phases differ.
+func (s *GraphSnapshot) Phases() string {
+	phases := make([]string, 0, len(s.Nodes)+1)
+	phases = append(phases, s.Phase)
+	for _, n := range s.Nodes {
+		phases = append(phases, fmt.Sprintf("%v=%v", n.ID, n.Phase))
+	}
+
+	return strings.Join(phases, ",")
+}
+
+// ToGraphViz returns the GraphViz https://www.graphviz.org/ representation of the snapshot, with every node labeled
+// with its phase.
+func (s *GraphSnapshot) ToGraphViz() string {
+	res := fmt.Sprintf("digraph G {rankdir=TB;workflow[label=\"Workflow Id: %v (%v)\"];node[style=filled];",
+		s.WorkflowID, s.Phase)
+	for _, n := range s.Nodes {
+		res += fmt.Sprintf("\"%v\" [label=\"%v(%v)\\n%v\"];", n.ID, n.ID, n.Kind, n.Phase)
+	}
+
+	for _, e := range s.Edges {
+		res += fmt.Sprintf("\"%v\" -> \"%v\";", e.From, e.To)
+	}
+
+	return res + "}"
+}
@@ -8,8 +8,8 @@ import (
 	"strings"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
-	"github.com/flyteorg/flytestdlib/logger"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -28,6 +28,22 @@ const (
 
 	// AWS SideCar Docker Container expects the mount to always be under /tmp
 	AWSInitContainerMountPath = "/tmp"
+
+	// AWSSecretJSONFieldPrefix is the prefix of a Secret.GroupVersion that requests a single field of a JSON secret
+	// payload to be extracted, e.g. a GroupVersion of "json:password" mounts only the password field of the payload.
+	AWSSecretJSONFieldPrefix = "json:"
+
+	// AWSSecretJSONFieldEnvVar and AWSSecretRawFilenameEnvVar tell the extraction container which field to extract from
+	// which file downloaded by the sidecar. The field is stored in the file named by AWSSecretFilenameEnvVar.
+	AWSSecretJSONFieldEnvVar   = "SECRET_JSON_FIELD"
+	AWSSecretRawFilenameEnvVar = "SECRET_RAW_FILENAME"
+
+	// The sidecar downloads the full payload to a file with this suffix when a field is extracted from it.
+	awsSecretRawFileSuffix = ".raw"
+
+	// Extracts the requested field, failing if it is missing, and removes the full payload afterwards.
+	awsSecretJSONExtractScript = `jq -re --arg field "$SECRET_JSON_FIELD" '.[$field]' "/tmp$SECRET_RAW_FILENAME" ` +
+		`> "/tmp$SECRET_FILENAME" && rm "/tmp$SECRET_RAW_FILENAME"`
 )
 
 var (
@@ -41,6 +57,9 @@ var (
 // The role/serviceaccount used to run the Pod must have permissions to pull the secret from AWS Secret Manager.
 // Otherwise, the Pod will fail with an init-error.
 // Files will be mounted on /etc/flyte/secrets/<SecretGroup>/<SecretKey>
+// If the Secret.GroupVersion is of the form json:<field>, the secret payload is expected to be a JSON object and only
+// the value of the given field is stored in the file. This adds a second init container, running the configured JSON
+// extractor image, for the secret.
 type AWSSecretManagerInjector struct {
 	cfg config.AWSSecretManagerConfig
 }
@@ -80,6 +99,9 @@ func (i AWSSecretManagerInjector) Inject(ctx context.Context, secret *core.Secre
 
 		p.Spec.Volumes = appendVolumeIfNotExists(p.Spec.Volumes, vol)
 		p.Spec.InitContainers = append(p.Spec.InitContainers, createAWSSidecarContainer(i.cfg, p, secret))
+		if field, ok := getAWSSecretJSONField(secret); ok {
+			p.Spec.InitContainers = append(p.Spec.InitContainers, createAWSJSONExtractorContainer(i.cfg, p, secret, field))
+		}
 
 		secretVolumeMount := corev1.VolumeMount{
 			Name:      AWSSecretsVolumeName,
@@ -119,7 +141,30 @@ func (i AWSSecretManagerInjector) Inject(ctx context.Context, secret *core.Secre
 	return p, true, nil
 }
 
+func formatAWSExtractorContainerName(index int) string {
+	return fmt.Sprintf("aws-extract-secret-%v", index)
+}
+
+// getAWSSecretJSONField returns the field to extract from the JSON secret payload, if the secret requests one.
+func getAWSSecretJSONField(secret *core.Secret) (string, bool) {
+	if !strings.HasPrefix(secret.GroupVersion, AWSSecretJSONFieldPrefix) {
+		return "", false
+	}
+
+	field := strings.TrimPrefix(secret.GroupVersion, AWSSecretJSONFieldPrefix)
+	return field, len(field) > 0
+}
+
+func formatAWSSecretFilename(secret *core.Secret) string {
+	return filepath.Join(string(filepath.Separator), strings.ToLower(secret.Group), strings.ToLower(secret.Key))
+}
+
 func createAWSSidecarContainer(cfg config.AWSSecretManagerConfig, p *corev1.Pod, secret *core.Secret) corev1.Container {
+	filename := formatAWSSecretFilename(secret)
+	if _, ok := getAWSSecretJSONField(secret); ok {
+		filename += awsSecretRawFileSuffix
+	}
+
 	return corev1.Container{
 		Image: cfg.SidecarImage,
 		// Create a unique name to allow multiple secrets to be mounted.
@@ -137,7 +182,39 @@ func createAWSSidecarContainer(cfg config.AWSSecretManagerConfig, p *corev1.Pod,
 			},
 			{
 				Name:  AWSSecretFilenameEnvVar,
-				Value: filepath.Join(string(filepath.Separator), strings.ToLower(secret.Group), strings.ToLower(secret.Key)),
+				Value: filename,
+			},
+		},
+		Resources: cfg.Resources,
+	}
+}
+
+// createAWSJSONExtractorContainer creates an init container that runs after the sidecar and replaces the full JSON
+// payload it downloaded with the value of a single field.
+func createAWSJSONExtractorContainer(cfg config.AWSSecretManagerConfig, p *corev1.Pod, secret *core.Secret, field string) corev1.Container {
+	filename := formatAWSSecretFilename(secret)
+	return corev1.Container{
+		Image:   cfg.JSONExtractorImage,
+		Name:    formatAWSExtractorContainerName(len(p.Spec.InitContainers)),
+		Command: []string{"sh", "-c", awsSecretJSONExtractScript},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      AWSSecretsVolumeName,
+				MountPath: AWSInitContainerMountPath,
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  AWSSecretJSONFieldEnvVar,
+				Value: field,
+			},
+			{
+				Name:  AWSSecretRawFilenameEnvVar,
+				Value: filename + awsSecretRawFileSuffix,
+			},
+			{
+				Name:  AWSSecretFilenameEnvVar,
+				Value: filename,
 			},
 		},
 		Resources: cfg.Resources,
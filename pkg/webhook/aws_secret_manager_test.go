@@ -80,3 +80,42 @@ func TestAWSSecretManagerInjector_Inject(t *testing.T) {
 		assert.Fail(t, "actual != expected", "Diff: %v", diff)
 	}
 }
+
+func TestAWSSecretManagerInjector_InjectJSONField(t *testing.T) {
+	injector := NewAWSSecretManagerInjector(config.DefaultConfig.AWSSecretManagerConfig)
+	p := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{},
+		},
+	}
+	inputSecret := &core.Secret{
+		Group:        "arn",
+		Key:          "name",
+		GroupVersion: "json:password",
+	}
+
+	actualP, injected, err := injector.Inject(context.Background(), inputSecret, p.DeepCopy())
+	assert.NoError(t, err)
+	assert.True(t, injected)
+	if !assert.Len(t, actualP.Spec.InitContainers, 2) {
+		return
+	}
+
+	sidecar := actualP.Spec.InitContainers[0]
+	assert.Equal(t, "aws-pull-secret-0", sidecar.Name)
+	assert.Contains(t, sidecar.Env, corev1.EnvVar{Name: AWSSecretFilenameEnvVar, Value: "/arn/name.raw"})
+
+	extractor := actualP.Spec.InitContainers[1]
+	assert.Equal(t, "aws-extract-secret-1", extractor.Name)
+	assert.Equal(t, config.DefaultConfig.AWSSecretManagerConfig.JSONExtractorImage, extractor.Image)
+	assert.Equal(t, []string{"sh", "-c", awsSecretJSONExtractScript}, extractor.Command)
+	assert.Contains(t, extractor.Env, corev1.EnvVar{Name: AWSSecretJSONFieldEnvVar, Value: "password"})
+	assert.Contains(t, extractor.Env, corev1.EnvVar{Name: AWSSecretRawFilenameEnvVar, Value: "/arn/name.raw"})
+	assert.Contains(t, extractor.Env, corev1.EnvVar{Name: AWSSecretFilenameEnvVar, Value: "/arn/name"})
+	assert.Contains(t, extractor.VolumeMounts, corev1.VolumeMount{Name: AWSSecretsVolumeName, MountPath: AWSInitContainerMountPath})
+
+	t.Run("empty field", func(t *testing.T) {
+		_, ok := getAWSSecretJSONField(&core.Secret{GroupVersion: "json:"})
+		assert.False(t, ok)
+	})
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/flyteorg/flytestdlib/config"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -18,7 +20,8 @@ var (
 		ListenPort:        9443,
 		SecretManagerType: SecretManagerTypeK8s,
 		AWSSecretManagerConfig: AWSSecretManagerConfig{
-			SidecarImage: "docker.io/amazon/aws-secrets-manager-secret-sidecar:v0.1.4",
+			SidecarImage:       "docker.io/amazon/aws-secrets-manager-secret-sidecar:v0.1.4",
+			JSONExtractorImage: "docker.io/stedolan/jq:latest",
 			Resources: corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{
 					corev1.ResourceMemory: resource.MustParse("500Mi"),
@@ -30,6 +33,32 @@ var (
 				},
 			},
 		},
+		SecretAccessPolicy: SecretAccessPolicyConfig{
+			Enabled:      false,
+			ProjectLabel: "project",
+		},
+		WorkloadIdentity: WorkloadIdentityConfig{
+			Enabled:                false,
+			Provider:               WorkloadIdentityProviderAWS,
+			ProjectLabel:           "project",
+			DomainLabel:            "domain",
+			OverrideAnnotation:     "flyte.org/iam-role",
+			AWSTokenAudience:       "sts.amazonaws.com",
+			AWSTokenExpirationSecs: 86400,
+		},
+		ServiceMesh: ServiceMeshConfig{
+			Enabled:                         false,
+			Mode:                            ServiceMeshModeQuit,
+			ProjectLabel:                    "project",
+			HoldApplicationUntilProxyStarts: true,
+			QuitURL:                         "http://127.0.0.1:15020/quitquitquit",
+			Shell:                           "/bin/sh",
+		},
+		SecretRefresh: SecretRefreshConfig{
+			Enabled:    false,
+			Annotation: "flyte.org/refresh-secrets",
+			Interval:   config.Duration{Duration: time.Minute},
+		},
 	}
 
 	configSection = config.MustRegisterSection("webhook", DefaultConfig)
@@ -52,18 +81,91 @@ const (
 )
 
 type Config struct {
-	MetricsPrefix          string                 `json:"metrics-prefix" pflag:",An optional prefix for all published metrics."`
-	CertDir                string                 `json:"certDir" pflag:",Certificate directory to use to write generated certs. Defaults to /etc/webhook/certs/"`
-	ListenPort             int                    `json:"listenPort" pflag:",The port to use to listen to webhook calls. Defaults to 9443"`
-	ServiceName            string                 `json:"serviceName" pflag:",The name of the webhook service."`
-	SecretName             string                 `json:"secretName" pflag:",Secret name to write generated certs to."`
-	SecretManagerType      SecretManagerType      `json:"secretManagerType" pflag:"-,Secret manager type to use if secrets are not found in global secrets."`
-	AWSSecretManagerConfig AWSSecretManagerConfig `json:"awsSecretManager" pflag:",AWS Secret Manager config."`
+	MetricsPrefix          string                   `json:"metrics-prefix" pflag:",An optional prefix for all published metrics."`
+	CertDir                string                   `json:"certDir" pflag:",Certificate directory to use to write generated certs. Defaults to /etc/webhook/certs/"`
+	ListenPort             int                      `json:"listenPort" pflag:",The port to use to listen to webhook calls. Defaults to 9443"`
+	ServiceName            string                   `json:"serviceName" pflag:",The name of the webhook service."`
+	SecretName             string                   `json:"secretName" pflag:",Secret name to write generated certs to."`
+	SecretManagerType      SecretManagerType        `json:"secretManagerType" pflag:"-,Secret manager type to use if secrets are not found in global secrets."`
+	AWSSecretManagerConfig AWSSecretManagerConfig   `json:"awsSecretManager" pflag:",AWS Secret Manager config."`
+	SecretAccessPolicy     SecretAccessPolicyConfig `json:"secretAccessPolicy" pflag:",Restricts the secret groups pods may request."`
+	WorkloadIdentity       WorkloadIdentityConfig   `json:"workloadIdentity" pflag:",Applies cloud identities to pods per project and domain."`
+	ServiceMesh            ServiceMeshConfig        `json:"serviceMesh" pflag:",Makes task pods terminate in namespaces where a service mesh injects a proxy."`
+	SecretRefresh          SecretRefreshConfig      `json:"secretRefresh" pflag:",Re-syncs the K8s secrets mounted as files in long-running pods when they rotate."`
+}
+
+// SecretRefreshConfig controls how the K8s secrets mounted as files in the pods that opt in with the annotation are
+// re-synced when they rotate. The kubelet only refreshes mounted secrets when it syncs a pod, which may take several
+// minutes after a secret changed. The webhook periodically looks for pods whose mounted secrets changed and updates an
+// annotation of theirs, which makes the kubelet sync them right away.
+type SecretRefreshConfig struct {
+	Enabled    bool            `json:"enabled" pflag:",Enables re-syncing the mounted secrets of the pods that opt in."`
+	Annotation string          `json:"annotation" pflag:",Pod annotation that opts pods in when set to true."`
+	Interval   config.Duration `json:"interval" pflag:",Interval at which the secrets of the pods are checked for changes."`
+}
+
+type ServiceMeshMode = string
+
+const (
+	// ServiceMeshModeQuit runs the primary container through a shell that asks the proxy to quit once it exits. The
+	// image of the primary container has to provide the shell and either curl or wget.
+	ServiceMeshModeQuit ServiceMeshMode = "quit"
+	// ServiceMeshModeNative asks the mesh to inject its proxy as a native sidecar, which Kubernetes stops on its own
+	// once the containers of the pod exit. It requires Kubernetes 1.28+ and Istio 1.20+.
+	ServiceMeshModeNative ServiceMeshMode = "native"
+)
+
+// ServiceMeshConfig makes task pods compatible with an Istio service mesh. The proxy injected by the mesh otherwise
+// keeps running after the task exits, and the pod never completes.
+type ServiceMeshConfig struct {
+	Enabled                         bool            `json:"enabled" pflag:",Enables the service mesh compatibility of task pods."`
+	Mode                            ServiceMeshMode `json:"mode" pflag:",How the proxy is stopped, either quit or native."`
+	ProjectLabel                    string          `json:"projectLabel" pflag:",Pod label that holds the project of the pod, it selects task pods."`
+	HoldApplicationUntilProxyStarts bool            `json:"holdApplicationUntilProxyStarts" pflag:",Delays the containers of the pod until the proxy is ready to serve their traffic."`
+	QuitURL                         string          `json:"quitURL" pflag:",Endpoint of the proxy that stops it, in the quit mode."`
+	Shell                           string          `json:"shell" pflag:",Shell that runs the primary container, in the quit mode."`
+}
+
+type WorkloadIdentityProvider = string
+
+const (
+	// WorkloadIdentityProviderAWS applies IAM roles for service accounts (IRSA).
+	WorkloadIdentityProviderAWS WorkloadIdentityProvider = "aws"
+	// WorkloadIdentityProviderGCP applies GCP workload identity service accounts.
+	WorkloadIdentityProviderGCP WorkloadIdentityProvider = "gcp"
+)
+
+// WorkloadIdentityConfig selects the cloud identity of a pod. The identity requested in the security context of the
+// execution, carried by the override annotation, takes precedence over the identity configured for the project and
+// domain of the pod, then for its project, then over the default identity.
+type WorkloadIdentityConfig struct {
+	Enabled                bool                     `json:"enabled" pflag:",Enables applying cloud identities to pods."`
+	Provider               WorkloadIdentityProvider `json:"provider" pflag:",Cloud provider of the identities, either aws or gcp."`
+	DefaultIdentity        string                   `json:"defaultIdentity" pflag:",Identity applied to pods that have no identity configured for their project and domain."`
+	Projects               map[string]string        `json:"projects" pflag:"-,Identity per project."`
+	ProjectDomains         map[string]string        `json:"projectDomains" pflag:"-,Identity per project and domain, keyed by <project>-<domain>."`
+	ProjectLabel           string                   `json:"projectLabel" pflag:",Pod label that holds the project of the pod."`
+	DomainLabel            string                   `json:"domainLabel" pflag:",Pod label that holds the domain of the pod."`
+	OverrideAnnotation     string                   `json:"overrideAnnotation" pflag:",Pod annotation that holds the identity requested by the execution."`
+	AWSTokenAudience       string                   `json:"awsTokenAudience" pflag:",Audience of the projected service account token used to assume IAM roles."`
+	AWSTokenExpirationSecs int64                    `json:"awsTokenExpirationSecs" pflag:",Expiration of the projected service account token used to assume IAM roles."`
+}
+
+// SecretAccessPolicyConfig lists the secret groups pods are allowed to request, per project and per namespace. A pod
+// may request a group if it is allowed by default, for its namespace or for its project. The group "*" allows all
+// groups.
+type SecretAccessPolicyConfig struct {
+	Enabled              bool                `json:"enabled" pflag:",Rejects pods that request secret groups that are not allowed for them."`
+	ProjectLabel         string              `json:"projectLabel" pflag:",Pod label that holds the project of the pod."`
+	DefaultAllowedGroups []string            `json:"defaultAllowedGroups" pflag:",Secret groups that all pods may request."`
+	Projects             map[string][]string `json:"projects" pflag:"-,Secret groups allowed per project."`
+	Namespaces           map[string][]string `json:"namespaces" pflag:"-,Secret groups allowed per namespace."`
 }
 
 type AWSSecretManagerConfig struct {
-	SidecarImage string                      `json:"sidecarImage" pflag:",Specifies the sidecar docker image to use"`
-	Resources    corev1.ResourceRequirements `json:"resources" pflag:"-,Specifies resource requirements for the init container."`
+	SidecarImage       string                      `json:"sidecarImage" pflag:",Specifies the sidecar docker image to use"`
+	JSONExtractorImage string                      `json:"jsonExtractorImage" pflag:",Specifies the docker image used to extract a field from JSON secrets, it must provide sh and jq."`
+	Resources          corev1.ResourceRequirements `json:"resources" pflag:"-,Specifies resource requirements for the init container."`
 }
 
 func GetConfig() *Config {
@@ -56,5 +56,26 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "serviceName"), DefaultConfig.ServiceName, "The name of the webhook service.")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "secretName"), DefaultConfig.SecretName, "Secret name to write generated certs to.")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "awsSecretManager.sidecarImage"), DefaultConfig.AWSSecretManagerConfig.SidecarImage, "Specifies the sidecar docker image to use")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "awsSecretManager.jsonExtractorImage"), DefaultConfig.AWSSecretManagerConfig.JSONExtractorImage, "Specifies the docker image used to extract a field from JSON secrets,  it must provide sh and jq.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "secretAccessPolicy.enabled"), DefaultConfig.SecretAccessPolicy.Enabled, "Rejects pods that request secret groups that are not allowed for them.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "secretAccessPolicy.projectLabel"), DefaultConfig.SecretAccessPolicy.ProjectLabel, "Pod label that holds the project of the pod.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "secretAccessPolicy.defaultAllowedGroups"), []string{}, "Secret groups that all pods may request.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "workloadIdentity.enabled"), DefaultConfig.WorkloadIdentity.Enabled, "Enables applying cloud identities to pods.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workloadIdentity.provider"), DefaultConfig.WorkloadIdentity.Provider, "Cloud provider of the identities,  either aws or gcp.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workloadIdentity.defaultIdentity"), DefaultConfig.WorkloadIdentity.DefaultIdentity, "Identity applied to pods that have no identity configured for their project and domain.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workloadIdentity.projectLabel"), DefaultConfig.WorkloadIdentity.ProjectLabel, "Pod label that holds the project of the pod.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workloadIdentity.domainLabel"), DefaultConfig.WorkloadIdentity.DomainLabel, "Pod label that holds the domain of the pod.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workloadIdentity.overrideAnnotation"), DefaultConfig.WorkloadIdentity.OverrideAnnotation, "Pod annotation that holds the identity requested by the execution.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workloadIdentity.awsTokenAudience"), DefaultConfig.WorkloadIdentity.AWSTokenAudience, "Audience of the projected service account token used to assume IAM roles.")
+	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "workloadIdentity.awsTokenExpirationSecs"), DefaultConfig.WorkloadIdentity.AWSTokenExpirationSecs, "Expiration of the projected service account token used to assume IAM roles.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "serviceMesh.enabled"), DefaultConfig.ServiceMesh.Enabled, "Enables the service mesh compatibility of task pods.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "serviceMesh.mode"), DefaultConfig.ServiceMesh.Mode, "How the proxy is stopped,  either quit or native.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "serviceMesh.projectLabel"), DefaultConfig.ServiceMesh.ProjectLabel, "Pod label that holds the project of the pod,  it selects task pods.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "serviceMesh.holdApplicationUntilProxyStarts"), DefaultConfig.ServiceMesh.HoldApplicationUntilProxyStarts, "Delays the containers of the pod until the proxy is ready to serve their traffic.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "serviceMesh.quitURL"), DefaultConfig.ServiceMesh.QuitURL, "Endpoint of the proxy that stops it,  in the quit mode.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "serviceMesh.shell"), DefaultConfig.ServiceMesh.Shell, "Shell that runs the primary container,  in the quit mode.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "secretRefresh.enabled"), DefaultConfig.SecretRefresh.Enabled, "Enables re-syncing the mounted secrets of the pods that opt in.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "secretRefresh.annotation"), DefaultConfig.SecretRefresh.Annotation, "Pod annotation that opts pods in when set to true.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "secretRefresh.interval"), DefaultConfig.SecretRefresh.Interval.String(), "Interval at which the secrets of the pods are checked for changes.")
 	return cmdFlags
 }
@@ -183,4 +183,298 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_awsSecretManager.jsonExtractorImage", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("awsSecretManager.jsonExtractorImage", testValue)
+			if vString, err := cmdFlags.GetString("awsSecretManager.jsonExtractorImage"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.AWSSecretManagerConfig.JSONExtractorImage)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_secretAccessPolicy.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("secretAccessPolicy.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("secretAccessPolicy.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.SecretAccessPolicy.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_secretAccessPolicy.projectLabel", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("secretAccessPolicy.projectLabel", testValue)
+			if vString, err := cmdFlags.GetString("secretAccessPolicy.projectLabel"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.SecretAccessPolicy.ProjectLabel)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_secretAccessPolicy.defaultAllowedGroups", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config("1,1", ",")
+
+			cmdFlags.Set("secretAccessPolicy.defaultAllowedGroups", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("secretAccessPolicy.defaultAllowedGroups"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.SecretAccessPolicy.DefaultAllowedGroups)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("workloadIdentity.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.WorkloadIdentity.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.provider", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.provider", testValue)
+			if vString, err := cmdFlags.GetString("workloadIdentity.provider"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.WorkloadIdentity.Provider)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.defaultIdentity", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.defaultIdentity", testValue)
+			if vString, err := cmdFlags.GetString("workloadIdentity.defaultIdentity"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.WorkloadIdentity.DefaultIdentity)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.projectLabel", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.projectLabel", testValue)
+			if vString, err := cmdFlags.GetString("workloadIdentity.projectLabel"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.WorkloadIdentity.ProjectLabel)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.domainLabel", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.domainLabel", testValue)
+			if vString, err := cmdFlags.GetString("workloadIdentity.domainLabel"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.WorkloadIdentity.DomainLabel)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.overrideAnnotation", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.overrideAnnotation", testValue)
+			if vString, err := cmdFlags.GetString("workloadIdentity.overrideAnnotation"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.WorkloadIdentity.OverrideAnnotation)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.awsTokenAudience", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.awsTokenAudience", testValue)
+			if vString, err := cmdFlags.GetString("workloadIdentity.awsTokenAudience"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.WorkloadIdentity.AWSTokenAudience)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_workloadIdentity.awsTokenExpirationSecs", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("workloadIdentity.awsTokenExpirationSecs", testValue)
+			if vInt64, err := cmdFlags.GetInt64("workloadIdentity.awsTokenExpirationSecs"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt64), &actual.WorkloadIdentity.AWSTokenExpirationSecs)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_serviceMesh.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("serviceMesh.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("serviceMesh.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.ServiceMesh.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_serviceMesh.mode", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("serviceMesh.mode", testValue)
+			if vString, err := cmdFlags.GetString("serviceMesh.mode"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ServiceMesh.Mode)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_serviceMesh.projectLabel", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("serviceMesh.projectLabel", testValue)
+			if vString, err := cmdFlags.GetString("serviceMesh.projectLabel"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ServiceMesh.ProjectLabel)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_serviceMesh.holdApplicationUntilProxyStarts", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("serviceMesh.holdApplicationUntilProxyStarts", testValue)
+			if vBool, err := cmdFlags.GetBool("serviceMesh.holdApplicationUntilProxyStarts"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.ServiceMesh.HoldApplicationUntilProxyStarts)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_serviceMesh.quitURL", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("serviceMesh.quitURL", testValue)
+			if vString, err := cmdFlags.GetString("serviceMesh.quitURL"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ServiceMesh.QuitURL)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_serviceMesh.shell", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("serviceMesh.shell", testValue)
+			if vString, err := cmdFlags.GetString("serviceMesh.shell"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ServiceMesh.Shell)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_secretRefresh.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("secretRefresh.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("secretRefresh.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.SecretRefresh.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_secretRefresh.annotation", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("secretRefresh.annotation", testValue)
+			if vString, err := cmdFlags.GetString("secretRefresh.annotation"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.SecretRefresh.Annotation)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_secretRefresh.interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := DefaultConfig.SecretRefresh.Interval.String()
+
+			cmdFlags.Set("secretRefresh.interval", testValue)
+			if vString, err := cmdFlags.GetString("secretRefresh.interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.SecretRefresh.Interval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
 
 	coreIdl "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
-	"github.com/flyteorg/flytestdlib/logger"
 	corev1 "k8s.io/api/core/v1"
 )
 
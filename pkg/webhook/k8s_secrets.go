@@ -6,10 +6,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
-	"github.com/flyteorg/flytestdlib/logger"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -24,12 +24,12 @@ var (
 
 // K8sSecretInjector allows injecting of secrets into pods by specifying either EnvVarSource or SecretVolumeSource in
 // the Pod Spec. It'll, by default, mount secrets as files into pods.
-// The current version does not allow mounting an entire secret object (with all keys inside it). It only supports mounting
-// a single key from the referenced secret object.
 // The secret.Group will be used to reference the k8s secret object, the Secret.Key will be used to reference a key inside
 // and the secret.Version will be ignored.
 // Environment variables will be named _FSEC_<SecretGroup>_<SecretKey>. Files will be mounted on
 // /etc/flyte/secrets/<SecretGroup>/<SecretKey>
+// If the Secret.Key is empty, the entire secret object is mounted, with one file per key in /etc/flyte/secrets/<SecretGroup>/
+// or one environment variable per key named _FSEC_<SecretGroup>_<Key>, where the key is not upper cased.
 type K8sSecretInjector struct {
 }
 
@@ -38,8 +38,8 @@ func (i K8sSecretInjector) Type() config.SecretManagerType {
 }
 
 func (i K8sSecretInjector) Inject(ctx context.Context, secret *core.Secret, p *corev1.Pod) (newP *corev1.Pod, injected bool, err error) {
-	if len(secret.Group) == 0 || len(secret.Key) == 0 {
-		return nil, false, fmt.Errorf("k8s Secrets Webhook require both key and group to be set. "+
+	if len(secret.Group) == 0 {
+		return nil, false, fmt.Errorf("k8s Secrets Webhook require group to be set. "+
 			"Secret: [%v]", secret)
 	}
 
@@ -76,9 +76,15 @@ func (i K8sSecretInjector) Inject(ctx context.Context, secret *core.Secret, p *c
 		p.Spec.InitContainers = AppendEnvVars(p.Spec.InitContainers, prefixEnvVar)
 		p.Spec.Containers = AppendEnvVars(p.Spec.Containers, prefixEnvVar)
 	case core.Secret_ENV_VAR:
-		envVar := CreateEnvVarForSecret(secret)
-		p.Spec.InitContainers = AppendEnvVars(p.Spec.InitContainers, envVar)
-		p.Spec.Containers = AppendEnvVars(p.Spec.Containers, envVar)
+		if len(secret.Key) == 0 {
+			envFrom := CreateEnvFromForSecret(secret)
+			p.Spec.InitContainers = AppendEnvFrom(p.Spec.InitContainers, envFrom)
+			p.Spec.Containers = AppendEnvFrom(p.Spec.Containers, envFrom)
+		} else {
+			envVar := CreateEnvVarForSecret(secret)
+			p.Spec.InitContainers = AppendEnvVars(p.Spec.InitContainers, envVar)
+			p.Spec.Containers = AppendEnvVars(p.Spec.Containers, envVar)
+		}
 
 		prefixEnvVar := corev1.EnvVar{
 			Name:  SecretEnvVarPrefix,
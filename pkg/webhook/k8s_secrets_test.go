@@ -130,6 +130,70 @@ func TestK8sSecretInjector_Inject(t *testing.T) {
 		},
 	}
 
+	successPodEnvAllKeys := corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{},
+			Containers: []corev1.Container{
+				{
+					Name: "container1",
+					EnvFrom: []corev1.EnvFromSource{
+						{
+							Prefix: "_FSEC_GROUP_",
+							SecretRef: &corev1.SecretEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "group",
+								},
+							},
+						},
+					},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "FLYTE_SECRETS_ENV_PREFIX",
+							Value: "_FSEC_",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	successPodFileEntireSecret := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{
+					Name: "m4zg54lql4pq",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "group",
+						},
+					},
+				},
+			},
+			InitContainers: []corev1.Container{},
+			Containers: []corev1.Container{
+				{
+					Name: "container1",
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "m4zg54lql4pq",
+							MountPath: "/etc/flyte/secrets/group",
+							ReadOnly:  true,
+						},
+					},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "FLYTE_SECRETS_DEFAULT_DIR",
+							Value: "/etc/flyte/secrets",
+						},
+						{
+							Name: "FLYTE_SECRETS_FILE_PREFIX",
+						},
+					},
+				},
+			},
+		},
+	}
+
 	ctx := context.Background()
 	type args struct {
 		secret *core.Secret
@@ -151,6 +215,12 @@ func TestK8sSecretInjector_Inject(t *testing.T) {
 		{name: "require file all keys", args: args{secret: &coreIdl.Secret{Key: "hello", MountRequirement: coreIdl.Secret_FILE},
 			p: inputPod.DeepCopy()},
 			want: &successPodFileAllKeys, wantErr: true},
+		{name: "require file entire secret", args: args{secret: &coreIdl.Secret{Group: "group", MountRequirement: coreIdl.Secret_FILE},
+			p: inputPod.DeepCopy()},
+			want: &successPodFileEntireSecret, wantErr: false},
+		{name: "require env entire secret", args: args{secret: &coreIdl.Secret{Group: "group", MountRequirement: coreIdl.Secret_ENV_VAR},
+			p: inputPod.DeepCopy()},
+			want: &successPodEnvAllKeys, wantErr: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,13 +38,13 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
 
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	"github.com/flyteorg/flytepropeller/pkg/utils/secrets"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/flytek8s"
-	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -91,6 +91,11 @@ func (pm *PodMutator) Handle(ctx context.Context, request admission.Request) adm
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
+	// Pods created through a controller may not have their namespace set yet.
+	if len(obj.Namespace) == 0 {
+		obj.Namespace = request.Namespace
+	}
+
 	newObj, changed, err := pm.Mutate(ctx, obj)
 	if err != nil {
 		return admission.Errored(http.StatusBadRequest, err)
@@ -210,24 +215,69 @@ func (pm PodMutator) CreateMutationWebhookConfiguration(namespace string) (*admi
 					"v1",
 					"v1beta1",
 				},
-				ObjectSelector: &metav1.LabelSelector{
-					MatchLabels: map[string]string{
-						secrets.PodLabel: secrets.PodLabelValue,
-					},
-				},
+				ObjectSelector: pm.getObjectSelector(),
 			}},
 	}
 
 	return mutateConfig, nil
 }
 
-func NewPodMutator(cfg *config.Config, scope promutils.Scope) *PodMutator {
-	return &PodMutator{
-		cfg: cfg,
-		Mutators: []MutatorConfig{
-			{
-				Mutator: NewSecretsMutator(cfg, scope.NewSubScope("secrets")),
+// getObjectSelector selects the pods that request secrets. If workload identities or the service mesh compatibility are
+// enabled, all task pods are selected instead, which all carry the label of their project.
+func (pm PodMutator) getObjectSelector() *metav1.LabelSelector {
+	projectLabel := ""
+	if pm.cfg.WorkloadIdentity.Enabled {
+		projectLabel = pm.cfg.WorkloadIdentity.ProjectLabel
+	} else if pm.cfg.ServiceMesh.Enabled {
+		projectLabel = pm.cfg.ServiceMesh.ProjectLabel
+	}
+
+	if len(projectLabel) > 0 {
+		return &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      projectLabel,
+					Operator: metav1.LabelSelectorOpExists,
+				},
 			},
+		}
+	}
+
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			secrets.PodLabel: secrets.PodLabelValue,
 		},
 	}
 }
+
+func NewPodMutator(cfg *config.Config, scope promutils.Scope) *PodMutator {
+	var mutators []MutatorConfig
+	if cfg.SecretAccessPolicy.Enabled {
+		// The policy has to reject pods before any of the requested secrets are injected.
+		mutators = append(mutators, MutatorConfig{
+			Mutator:  NewSecretAccessPolicy(cfg.SecretAccessPolicy, scope.NewSubScope("secret_policy")),
+			Required: true,
+		})
+	}
+
+	mutators = append(mutators, MutatorConfig{
+		Mutator: NewSecretsMutator(cfg, scope.NewSubScope("secrets")),
+	})
+
+	if cfg.WorkloadIdentity.Enabled {
+		mutators = append(mutators, MutatorConfig{
+			Mutator: NewWorkloadIdentityInjector(cfg.WorkloadIdentity),
+		})
+	}
+
+	if cfg.ServiceMesh.Enabled {
+		mutators = append(mutators, MutatorConfig{
+			Mutator: NewServiceMeshMutator(cfg.ServiceMesh),
+		})
+	}
+
+	return &PodMutator{
+		cfg:      cfg,
+		Mutators: mutators,
+	}
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	secretUtils "github.com/flyteorg/flytepropeller/pkg/utils/secrets"
+)
+
+const allowAllSecretGroups = "*"
+
+// SecretAccessPolicy rejects pods that request secret groups that are not allowed for their project or namespace. It
+// never changes the pod and should run as a required mutator, before any secret gets injected.
+type SecretAccessPolicy struct {
+	cfg      config.SecretAccessPolicyConfig
+	rejected prometheus.Counter
+}
+
+func (s SecretAccessPolicy) ID() string {
+	return "secret-access-policy"
+}
+
+func (s SecretAccessPolicy) allowedGroups(project, namespace string) sets.String {
+	allowed := sets.NewString(s.cfg.DefaultAllowedGroups...)
+	allowed.Insert(s.cfg.Namespaces[namespace]...)
+	if len(project) > 0 {
+		allowed.Insert(s.cfg.Projects[project]...)
+	}
+
+	return allowed
+}
+
+func (s *SecretAccessPolicy) Mutate(ctx context.Context, p *corev1.Pod) (newP *corev1.Pod, changed bool, err error) {
+	secrets, err := secretUtils.UnmarshalStringMapToSecrets(p.GetAnnotations())
+	if err != nil {
+		return p, false, err
+	}
+
+	if len(secrets) == 0 {
+		return p, false, nil
+	}
+
+	project := p.GetLabels()[s.cfg.ProjectLabel]
+	allowed := s.allowedGroups(project, p.GetNamespace())
+	if allowed.Has(allowAllSecretGroups) {
+		return p, false, nil
+	}
+
+	for _, secret := range secrets {
+		if !allowed.Has(secret.Group) {
+			s.rejected.Inc()
+			err = fmt.Errorf("secret group [%v] is not allowed for project [%v] in namespace [%v], allowed groups are %v",
+				secret.Group, project, p.GetNamespace(), allowed.List())
+			logger.Warn(ctx, err)
+			return p, false, err
+		}
+	}
+
+	return p, false, nil
+}
+
+// NewSecretAccessPolicy creates a SecretAccessPolicy that enforces the given allowlists.
+func NewSecretAccessPolicy(cfg config.SecretAccessPolicyConfig, scope promutils.Scope) *SecretAccessPolicy {
+	return &SecretAccessPolicy{
+		cfg:      cfg,
+		rejected: scope.MustNewCounter("rejected", "Number of pods rejected because they requested a secret group that is not allowed"),
+	}
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretUtils "github.com/flyteorg/flytepropeller/pkg/utils/secrets"
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+)
+
+func newPodWithSecrets(t *testing.T, namespace, project string, groups ...string) *corev1.Pod {
+	secrets := make([]*core.Secret, 0, len(groups))
+	for _, group := range groups {
+		secrets = append(secrets, &core.Secret{Group: group, Key: "key"})
+	}
+
+	annotations, err := secretUtils.MarshalSecretsToMapStrings(secrets)
+	assert.NoError(t, err)
+
+	return &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace:   namespace,
+			Labels:      map[string]string{"project": project},
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestSecretAccessPolicy_Mutate(t *testing.T) {
+	ctx := context.Background()
+	policy := NewSecretAccessPolicy(config.SecretAccessPolicyConfig{
+		Enabled:              true,
+		ProjectLabel:         "project",
+		DefaultAllowedGroups: []string{"shared"},
+		Projects: map[string][]string{
+			"flytesnacks": {"snacks"},
+			"admin":       {"*"},
+		},
+		Namespaces: map[string][]string{
+			"flytesnacks-production": {"prod-db"},
+		},
+	}, promutils.NewTestScope())
+
+	t.Run("no secrets", func(t *testing.T) {
+		_, changed, err := policy.Mutate(ctx, &corev1.Pod{})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		p := newPodWithSecrets(t, "flytesnacks-production", "flytesnacks", "shared", "snacks", "prod-db")
+		_, changed, err := policy.Mutate(ctx, p)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("namespace not allowed", func(t *testing.T) {
+		p := newPodWithSecrets(t, "flytesnacks-development", "flytesnacks", "prod-db")
+		_, _, err := policy.Mutate(ctx, p)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "secret group [prod-db] is not allowed for project [flytesnacks]")
+		}
+	})
+
+	t.Run("project not allowed", func(t *testing.T) {
+		p := newPodWithSecrets(t, "other-development", "other", "snacks")
+		_, _, err := policy.Mutate(ctx, p)
+		assert.Error(t, err)
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		p := newPodWithSecrets(t, "admin-development", "admin", "anything")
+		_, _, err := policy.Mutate(ctx, p)
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewPodMutator_SecretAccessPolicy(t *testing.T) {
+	cfg := &config.Config{
+		SecretAccessPolicy: config.SecretAccessPolicyConfig{
+			Enabled:      true,
+			ProjectLabel: "project",
+		},
+	}
+
+	pm := NewPodMutator(cfg, promutils.NewTestScope())
+	if assert.Len(t, pm.Mutators, 2) {
+		assert.Equal(t, "secret-access-policy", pm.Mutators[0].Mutator.ID())
+		assert.True(t, pm.Mutators[0].Required)
+	}
+
+	_, _, err := pm.Mutate(context.Background(), newPodWithSecrets(t, "ns", "project", "group"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	secretUtils "github.com/flyteorg/flytepropeller/pkg/utils/secrets"
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+)
+
+// Annotation of pods that holds a digest of the resource versions of the secrets mounted in them, as of the last time
+// they were refreshed.
+const SecretsVersionAnnotation = "flyte.org/secrets-version" // #nosec
+
+// SecretRefresher makes the kubelet re-sync the K8s secrets mounted as files in long-running pods soon after they
+// rotate, without running a sidecar in the pods. The kubelet refreshes mounted secrets whenever it syncs a pod, so the
+// refresher updates an annotation of the pods whose mounted secrets changed, which makes the kubelet sync them. Only the
+// pods that opt in with the configured annotation are refreshed. Secrets injected as environment variables, or copied
+// into the pod by the AWS secret manager, cannot be refreshed this way.
+type SecretRefresher struct {
+	cfg       config.SecretRefreshConfig
+	client    client.Client
+	refreshed prometheus.Counter
+	failures  prometheus.Counter
+}
+
+// Start refreshes the secrets of the pods at every interval, until the context is done.
+func (r *SecretRefresher) Start(ctx context.Context) error {
+	logger.Infof(ctx, "Refreshing the mounted secrets of pods annotated [%v] every [%v]", r.cfg.Annotation,
+		r.cfg.Interval.Duration)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.refresh(ctx); err != nil {
+			r.failures.Inc()
+			logger.Warnf(ctx, "Failed to refresh the mounted secrets of pods. Error: %v", err)
+		}
+	}, r.cfg.Interval.Duration)
+
+	return nil
+}
+
+func (r *SecretRefresher) refresh(ctx context.Context) error {
+	pods := &corev1.PodList{}
+	if err := r.client.List(ctx, pods, client.MatchingLabels{secretUtils.PodLabel: secretUtils.PodLabelValue}); err != nil {
+		return err
+	}
+
+	// Resource versions of the secrets mounted in the pods, by namespace and name.
+	resourceVersions := make(map[types.NamespacedName]string)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.GetAnnotations()[r.cfg.Annotation] != "true" || pod.Status.Phase == corev1.PodSucceeded ||
+			pod.Status.Phase == corev1.PodFailed || pod.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		version, err := r.getSecretsVersion(ctx, pod, resourceVersions)
+		if err != nil {
+			return err
+		}
+
+		if len(version) == 0 || pod.GetAnnotations()[SecretsVersionAnnotation] == version {
+			continue
+		}
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		pod.Annotations[SecretsVersionAnnotation] = version
+		if err := r.client.Patch(ctx, pod, patch); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		r.refreshed.Inc()
+		logger.Debugf(ctx, "Refreshed the mounted secrets of pod [%v/%v]", pod.GetNamespace(), pod.GetName())
+	}
+
+	return nil
+}
+
+// Returns a digest of the resource versions of the secrets mounted as volumes in the pod, empty if it mounts none.
+// Secrets that do not exist, e.g. optional ones, are left out.
+func (r *SecretRefresher) getSecretsVersion(ctx context.Context, pod *corev1.Pod,
+	resourceVersions map[types.NamespacedName]string) (string, error) {
+
+	versions := make([]string, 0, len(pod.Spec.Volumes))
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret == nil {
+			continue
+		}
+
+		key := types.NamespacedName{Namespace: pod.GetNamespace(), Name: volume.Secret.SecretName}
+		resourceVersion, found := resourceVersions[key]
+		if !found {
+			secret := &corev1.Secret{}
+			if err := r.client.Get(ctx, key, secret); err != nil && !k8serrors.IsNotFound(err) {
+				return "", err
+			}
+
+			resourceVersion = secret.GetResourceVersion()
+			resourceVersions[key] = resourceVersion
+		}
+
+		if len(resourceVersion) > 0 {
+			versions = append(versions, key.Name+"="+resourceVersion)
+		}
+	}
+
+	if len(versions) == 0 {
+		return "", nil
+	}
+
+	sort.Strings(versions)
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join(versions, ",")))
+	return fmt.Sprintf("%x", h.Sum64()), nil
+}
+
+// NewSecretRefresher creates a SecretRefresher that reads and updates pods and secrets with the given client.
+func NewSecretRefresher(cfg config.SecretRefreshConfig, c client.Client, scope promutils.Scope) *SecretRefresher {
+	return &SecretRefresher{
+		cfg:       cfg,
+		client:    c,
+		refreshed: scope.MustNewCounter("refreshed_pods", "Number of times pods were updated to re-sync their mounted secrets"),
+		failures:  scope.MustNewCounter("refresh_failures", "Number of times refreshing the mounted secrets of pods failed"),
+	}
+}
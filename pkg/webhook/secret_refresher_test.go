@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	coreIdl "github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretUtils "github.com/flyteorg/flytepropeller/pkg/utils/secrets"
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+)
+
+func TestSecretRefresher_refresh(t *testing.T) {
+	ctx := context.TODO()
+	cfg := config.DefaultConfig.SecretRefresh
+	mounted := &coreIdl.Secret{Group: "group", Key: "key", MountRequirement: coreIdl.Secret_FILE}
+
+	newPod := func(name string, optIn bool) *corev1.Pod {
+		annotations := map[string]string{}
+		if optIn {
+			annotations[cfg.Annotation] = "true"
+		}
+
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "ns",
+				Labels:      map[string]string{secretUtils.PodLabel: secretUtils.PodLabelValue},
+				Annotations: annotations,
+			},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					CreateVolumeForSecret(mounted),
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: mounted.Group, Namespace: "ns"},
+		Data:       map[string][]byte{mounted.Key: []byte("v1")},
+	}
+
+	c := fake.NewClientBuilder().WithObjects(secret, newPod("long-running", true), newPod("other", false)).Build()
+	r := NewSecretRefresher(cfg, c, promutils.NewTestScope())
+
+	getVersion := func(name string) string {
+		pod := &corev1.Pod{}
+		assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: name}, pod))
+		return pod.GetAnnotations()[SecretsVersionAnnotation]
+	}
+
+	assert.NoError(t, r.refresh(ctx))
+	version := getVersion("long-running")
+	assert.NotEmpty(t, version)
+	assert.Empty(t, getVersion("other"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.refreshed))
+
+	// Pods whose secrets did not change are left alone.
+	assert.NoError(t, r.refresh(ctx))
+	assert.Equal(t, version, getVersion("long-running"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.refreshed))
+
+	// Rotating the secret refreshes the pods that mount it.
+	secret.Data[mounted.Key] = []byte("v2")
+	assert.NoError(t, c.Update(ctx, secret))
+	assert.NoError(t, r.refresh(ctx))
+	assert.NotEqual(t, version, getVersion("long-running"))
+	assert.Empty(t, getVersion("other"))
+	assert.Equal(t, float64(2), testutil.ToFloat64(r.refreshed))
+}
@@ -7,11 +7,10 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/secretmanager"
 
-	"github.com/flyteorg/flytestdlib/logger"
-
 	"github.com/flyteorg/flytestdlib/promutils"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flytepropeller/pkg/logger"
 	secretUtils "github.com/flyteorg/flytepropeller/pkg/utils/secrets"
 
 	corev1 "k8s.io/api/core/v1"
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+)
+
+const (
+	// IstioProxyConfigAnnotation overrides the mesh config of the proxy injected into a pod.
+	IstioProxyConfigAnnotation = "proxy.istio.io/config"
+	// IstioNativeSidecarAnnotation asks Istio to inject its proxy as a native sidecar, i.e. a restartable init container.
+	IstioNativeSidecarAnnotation = "sidecar.istio.io/nativeSidecar"
+	// PrimaryContainerAnnotation names the primary container of pods that have several, set by the sidecar plugin.
+	PrimaryContainerAnnotation = "primary_container_name"
+
+	holdApplicationProxyConfig = `{"holdApplicationUntilProxyStarts": true}`
+	// Runs the original command, given as the positional parameters of the shell, then asks the proxy to quit. The exit
+	// code of the original command is kept, whether the proxy could be reached or not.
+	quitProxyScript = `"$0" "$@"; rc=$?; (curl -fsS -X POST %[1]v || wget -q -O /dev/null --post-data='' %[1]v) >/dev/null 2>&1; exit $rc`
+)
+
+// ServiceMeshMutator makes task pods terminate in namespaces where Istio injects its proxy into every pod. The proxy
+// otherwise keeps running once the task exits, and the pod hangs instead of completing. The webhook cannot tell if the
+// namespace of the pod is part of the mesh, the proxy is injected by a webhook of its own, so the mutations are harmless
+// to pods without proxies.
+type ServiceMeshMutator struct {
+	cfg config.ServiceMeshConfig
+}
+
+func (s ServiceMeshMutator) ID() string {
+	return "service-mesh"
+}
+
+func (s ServiceMeshMutator) Mutate(ctx context.Context, p *corev1.Pod) (newP *corev1.Pod, changed bool, err error) {
+	if s.cfg.HoldApplicationUntilProxyStarts {
+		// The mesh config of the pod is left untouched if it already has one.
+		if _, found := p.GetAnnotations()[IstioProxyConfigAnnotation]; !found {
+			setAnnotation(p, IstioProxyConfigAnnotation, holdApplicationProxyConfig)
+			changed = true
+		}
+	}
+
+	switch s.cfg.Mode {
+	case config.ServiceMeshModeNative:
+		setAnnotation(p, IstioNativeSidecarAnnotation, "true")
+		changed = true
+	case config.ServiceMeshModeQuit:
+		if s.wrapPrimaryContainer(ctx, p) {
+			changed = true
+		}
+	default:
+		return p, false, fmt.Errorf("unsupported service mesh mode [%v]", s.cfg.Mode)
+	}
+
+	return p, changed, nil
+}
+
+// getPrimaryContainer returns the container that runs the task, once it exits the pod is done.
+func getPrimaryContainer(p *corev1.Pod) *corev1.Container {
+	name, found := p.GetAnnotations()[PrimaryContainerAnnotation]
+	if !found {
+		if len(p.Spec.Containers) == 1 {
+			return &p.Spec.Containers[0]
+		}
+
+		return nil
+	}
+
+	for i := range p.Spec.Containers {
+		if p.Spec.Containers[i].Name == name {
+			return &p.Spec.Containers[i]
+		}
+	}
+
+	return nil
+}
+
+// wrapPrimaryContainer runs the command of the primary container through a shell that asks the proxy to quit once the
+// command exits. It returns whether the pod was changed.
+func (s ServiceMeshMutator) wrapPrimaryContainer(ctx context.Context, p *corev1.Pod) bool {
+	c := getPrimaryContainer(p)
+	if c == nil {
+		logger.Infof(ctx, "No primary container found in pod [%v/%v], the proxy will not be stopped", p.GetNamespace(),
+			p.GetName())
+		return false
+	}
+
+	// The entrypoint of the image is unknown here, it cannot be wrapped.
+	if len(c.Command) == 0 {
+		logger.Infof(ctx, "Primary container [%v] of pod [%v/%v] has no command, the proxy will not be stopped", c.Name,
+			p.GetNamespace(), p.GetName())
+		return false
+	}
+
+	script := fmt.Sprintf(quitProxyScript, s.cfg.QuitURL)
+	if len(c.Command) > 2 && c.Command[0] == s.cfg.Shell && c.Command[2] == script {
+		return false
+	}
+
+	command := make([]string, 0, len(c.Command)+len(c.Args)+3)
+	command = append(command, s.cfg.Shell, "-c", script)
+	command = append(command, c.Command...)
+	command = append(command, c.Args...)
+	c.Command = command
+	c.Args = nil
+	return true
+}
+
+// NewServiceMeshMutator creates a mutator that makes task pods compatible with a service mesh.
+func NewServiceMeshMutator(cfg config.ServiceMeshConfig) *ServiceMeshMutator {
+	return &ServiceMeshMutator{
+		cfg: cfg,
+	}
+}
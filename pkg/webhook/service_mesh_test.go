@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+)
+
+func TestServiceMeshMutator_Mutate(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig.ServiceMesh
+	cfg.Enabled = true
+
+	newPod := func(annotations map[string]string, containers ...corev1.Container) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Labels:      map[string]string{"project": "flytesnacks"},
+				Annotations: annotations,
+			},
+			Spec: corev1.PodSpec{
+				Containers: containers,
+			},
+		}
+	}
+
+	primary := corev1.Container{Name: "primary", Command: []string{"pyflyte-execute"}, Args: []string{"--task", "t"}}
+
+	t.Run("quit", func(t *testing.T) {
+		mutator := NewServiceMeshMutator(cfg)
+		p, changed, err := mutator.Mutate(ctx, newPod(nil, primary))
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, holdApplicationProxyConfig, p.Annotations[IstioProxyConfigAnnotation])
+		assert.Equal(t, []string{"/bin/sh", "-c"}, p.Spec.Containers[0].Command[:2])
+		assert.Contains(t, p.Spec.Containers[0].Command[2], "http://127.0.0.1:15020/quitquitquit")
+		assert.Equal(t, []string{"pyflyte-execute", "--task", "t"}, p.Spec.Containers[0].Command[3:])
+		assert.Empty(t, p.Spec.Containers[0].Args)
+
+		// Mutating the pod again, e.g. on reinvocation, leaves it unchanged.
+		wrapped := p.Spec.Containers[0].Command
+		p, changed, err = mutator.Mutate(ctx, p)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, wrapped, p.Spec.Containers[0].Command)
+	})
+
+	t.Run("primary container", func(t *testing.T) {
+		sidecar := corev1.Container{Name: "sidecar", Command: []string{"redis"}}
+		p, changed, err := NewServiceMeshMutator(cfg).Mutate(ctx, newPod(
+			map[string]string{PrimaryContainerAnnotation: "primary"}, sidecar, primary))
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, []string{"redis"}, p.Spec.Containers[0].Command)
+		assert.Equal(t, "/bin/sh", p.Spec.Containers[1].Command[0])
+	})
+
+	t.Run("unknown primary container", func(t *testing.T) {
+		noHold := cfg
+		noHold.HoldApplicationUntilProxyStarts = false
+		sidecar := corev1.Container{Name: "sidecar", Command: []string{"redis"}}
+		_, changed, err := NewServiceMeshMutator(noHold).Mutate(ctx, newPod(nil, sidecar, primary))
+		assert.NoError(t, err)
+		assert.False(t, changed)
+
+		_, changed, err = NewServiceMeshMutator(noHold).Mutate(ctx, newPod(nil, corev1.Container{Name: "primary"}))
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("existing proxy config", func(t *testing.T) {
+		p, _, err := NewServiceMeshMutator(cfg).Mutate(ctx, newPod(
+			map[string]string{IstioProxyConfigAnnotation: "{}"}, primary))
+		assert.NoError(t, err)
+		assert.Equal(t, "{}", p.Annotations[IstioProxyConfigAnnotation])
+	})
+
+	t.Run("native", func(t *testing.T) {
+		native := cfg
+		native.Mode = config.ServiceMeshModeNative
+		p, changed, err := NewServiceMeshMutator(native).Mutate(ctx, newPod(nil, primary))
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "true", p.Annotations[IstioNativeSidecarAnnotation])
+		assert.Equal(t, []string{"pyflyte-execute"}, p.Spec.Containers[0].Command)
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		unsupported := cfg
+		unsupported.Mode = "other"
+		_, _, err := NewServiceMeshMutator(unsupported).Mutate(ctx, newPod(nil, primary))
+		assert.Error(t, err)
+	})
+
+	t.Run("object selector", func(t *testing.T) {
+		podCfg := &config.Config{CertDir: "testdata", ServiceName: "my-service", ServiceMesh: cfg}
+		c, err := NewPodMutator(podCfg, promutils.NewTestScope()).CreateMutationWebhookConfiguration("ns")
+		assert.NoError(t, err)
+		assert.Equal(t, []v1.LabelSelectorRequirement{{Key: "project", Operator: v1.LabelSelectorOpExists}},
+			c.Webhooks[0].ObjectSelector.MatchExpressions)
+	})
+}
@@ -35,21 +35,40 @@ func CreateEnvVarForSecret(secret *core.Secret) corev1.EnvVar {
 	}
 }
 
+// CreateEnvFromForSecret exposes every key of the secret as an environment variable named
+// _FSEC_<SecretGroup>_<Key>.
+func CreateEnvFromForSecret(secret *core.Secret) corev1.EnvFromSource {
+	return corev1.EnvFromSource{
+		Prefix: strings.ToUpper(K8sDefaultEnvVarPrefix + secret.Group + EnvVarGroupKeySeparator),
+		SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: secret.Group,
+			},
+		},
+	}
+}
+
+// CreateVolumeForSecret creates a volume that projects the secret key, or all the keys of the secret if no key is set.
 func CreateVolumeForSecret(secret *core.Secret) corev1.Volume {
-	return corev1.Volume{
+	volume := corev1.Volume{
 		Name: utils.Base32Encoder.EncodeToString([]byte(secret.Group + EnvVarGroupKeySeparator + secret.Key + EnvVarGroupKeySeparator + secret.GroupVersion)),
 		VolumeSource: corev1.VolumeSource{
 			Secret: &corev1.SecretVolumeSource{
 				SecretName: secret.Group,
-				Items: []corev1.KeyToPath{
-					{
-						Key:  secret.Key,
-						Path: secret.Key,
-					},
-				},
 			},
 		},
 	}
+
+	if len(secret.Key) > 0 {
+		volume.Secret.Items = []corev1.KeyToPath{
+			{
+				Key:  secret.Key,
+				Path: secret.Key,
+			},
+		}
+	}
+
+	return volume
 }
 
 func CreateVolumeMountForSecret(volumeName string, secret *core.Secret) corev1.VolumeMount {
@@ -83,6 +102,29 @@ func AppendEnvVars(containers []corev1.Container, envVar corev1.EnvVar) []corev1
 	return res
 }
 
+func AppendEnvFrom(containers []corev1.Container, envFrom corev1.EnvFromSource) []corev1.Container {
+	res := make([]corev1.Container, 0, len(containers))
+	for _, c := range containers {
+		if !hasEnvFrom(c.EnvFrom, envFrom) {
+			c.EnvFrom = append(c.EnvFrom, envFrom)
+		}
+
+		res = append(res, c)
+	}
+
+	return res
+}
+
+func hasEnvFrom(sources []corev1.EnvFromSource, envFrom corev1.EnvFromSource) bool {
+	for _, s := range sources {
+		if s.Prefix == envFrom.Prefix && s.SecretRef != nil && envFrom.SecretRef != nil && s.SecretRef.Name == envFrom.SecretRef.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func appendVolumeIfNotExists(volumes []corev1.Volume, vol corev1.Volume) []corev1.Volume {
 	for _, v := range volumes {
 		if v.Name == vol.Name {
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/logger"
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+)
+
+const (
+	// AWSRoleArnAnnotation is the annotation EKS uses to map a service account to an IAM role.
+	AWSRoleArnAnnotation = "eks.amazonaws.com/role-arn"
+	// GCPServiceAccountAnnotation is the annotation GKE uses to map a service account to a GCP service account.
+	GCPServiceAccountAnnotation = "iam.gke.io/gcp-service-account"
+
+	AWSRoleArnEnvVar           = "AWS_ROLE_ARN"
+	AWSWebIdentityTokenEnvVar  = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	AWSWebIdentityTokenVolume  = "aws-iam-token"
+	AWSWebIdentityTokenDir     = "/var/run/secrets/eks.amazonaws.com/serviceaccount"
+	awsWebIdentityTokenRelPath = "token"
+)
+
+// WorkloadIdentityInjector applies the cloud identity configured for the project and domain of a pod, or requested by
+// its execution.
+// For AWS, it annotates the pod with the IAM role and injects the role and a projected service account token into all
+// containers, the same way the EKS pod identity webhook does for annotated service accounts, so that the AWS SDKs
+// assume the role. The trust policy of the role has to allow the service account of the pod.
+// For GCP, it annotates the pod with the GCP service account. GKE resolves workload identity through the Kubernetes
+// service account of the pod, which has to be bound to the same GCP service account.
+type WorkloadIdentityInjector struct {
+	cfg config.WorkloadIdentityConfig
+}
+
+func (w WorkloadIdentityInjector) ID() string {
+	return "workload-identity"
+}
+
+// getIdentity returns the identity the pod should run as, or an empty string if none is configured.
+func (w WorkloadIdentityInjector) getIdentity(p *corev1.Pod) string {
+	if identity := p.GetAnnotations()[w.cfg.OverrideAnnotation]; len(identity) > 0 {
+		return identity
+	}
+
+	project := p.GetLabels()[w.cfg.ProjectLabel]
+	domain := p.GetLabels()[w.cfg.DomainLabel]
+	if identity, found := w.cfg.ProjectDomains[fmt.Sprintf("%v-%v", project, domain)]; found {
+		return identity
+	}
+
+	if identity, found := w.cfg.Projects[project]; found {
+		return identity
+	}
+
+	return w.cfg.DefaultIdentity
+}
+
+func (w WorkloadIdentityInjector) Mutate(ctx context.Context, p *corev1.Pod) (newP *corev1.Pod, changed bool, err error) {
+	identity := w.getIdentity(p)
+	if len(identity) == 0 {
+		return p, false, nil
+	}
+
+	switch w.cfg.Provider {
+	case config.WorkloadIdentityProviderAWS:
+		setAnnotation(p, AWSRoleArnAnnotation, identity)
+		w.injectAWSWebIdentity(p, identity)
+	case config.WorkloadIdentityProviderGCP:
+		setAnnotation(p, GCPServiceAccountAnnotation, identity)
+	default:
+		return p, false, fmt.Errorf("unsupported workload identity provider [%v]", w.cfg.Provider)
+	}
+
+	logger.Debugf(ctx, "Applied identity [%v] to pod [%v/%v]", identity, p.GetNamespace(), p.GetName())
+	return p, true, nil
+}
+
+func setAnnotation(p *corev1.Pod, key, value string) {
+	if p.Annotations == nil {
+		p.Annotations = map[string]string{}
+	}
+
+	p.Annotations[key] = value
+}
+
+func (w WorkloadIdentityInjector) injectAWSWebIdentity(p *corev1.Pod, role string) {
+	expiration := w.cfg.AWSTokenExpirationSecs
+	p.Spec.Volumes = appendVolumeIfNotExists(p.Spec.Volumes, corev1.Volume{
+		Name: AWSWebIdentityTokenVolume,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          w.cfg.AWSTokenAudience,
+							ExpirationSeconds: &expiration,
+							Path:              awsWebIdentityTokenRelPath,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	mount := corev1.VolumeMount{
+		Name:      AWSWebIdentityTokenVolume,
+		ReadOnly:  true,
+		MountPath: AWSWebIdentityTokenDir,
+	}
+
+	envVars := []corev1.EnvVar{
+		{
+			Name:  AWSRoleArnEnvVar,
+			Value: role,
+		},
+		{
+			Name:  AWSWebIdentityTokenEnvVar,
+			Value: filepath.Join(AWSWebIdentityTokenDir, awsWebIdentityTokenRelPath),
+		},
+	}
+
+	p.Spec.InitContainers = AppendVolumeMounts(p.Spec.InitContainers, mount)
+	p.Spec.Containers = AppendVolumeMounts(p.Spec.Containers, mount)
+	for _, envVar := range envVars {
+		p.Spec.InitContainers = AppendEnvVars(p.Spec.InitContainers, envVar)
+		p.Spec.Containers = AppendEnvVars(p.Spec.Containers, envVar)
+	}
+}
+
+// NewWorkloadIdentityInjector creates a mutator that applies cloud identities to pods.
+func NewWorkloadIdentityInjector(cfg config.WorkloadIdentityConfig) *WorkloadIdentityInjector {
+	return &WorkloadIdentityInjector{
+		cfg: cfg,
+	}
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/webhook/config"
+)
+
+func TestWorkloadIdentityInjector_Mutate(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig.WorkloadIdentity
+	cfg.Enabled = true
+	cfg.DefaultIdentity = "default-role"
+	cfg.Projects = map[string]string{"flytesnacks": "snacks-role"}
+	cfg.ProjectDomains = map[string]string{"flytesnacks-production": "snacks-production-role"}
+
+	newPod := func(project, domain string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Labels:      map[string]string{"project": project, "domain": domain},
+				Annotations: annotations,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "primary"}},
+			},
+		}
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		injector := NewWorkloadIdentityInjector(cfg)
+		assert.Equal(t, "default-role", injector.getIdentity(newPod("other", "development", nil)))
+		assert.Equal(t, "snacks-role", injector.getIdentity(newPod("flytesnacks", "development", nil)))
+		assert.Equal(t, "snacks-production-role", injector.getIdentity(newPod("flytesnacks", "production", nil)))
+		assert.Equal(t, "override-role", injector.getIdentity(newPod("flytesnacks", "production",
+			map[string]string{"flyte.org/iam-role": "override-role"})))
+	})
+
+	t.Run("aws", func(t *testing.T) {
+		injector := NewWorkloadIdentityInjector(cfg)
+		p, changed, err := injector.Mutate(ctx, newPod("flytesnacks", "production", nil))
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "snacks-production-role", p.Annotations[AWSRoleArnAnnotation])
+		if assert.Len(t, p.Spec.Volumes, 1) {
+			projection := p.Spec.Volumes[0].Projected.Sources[0].ServiceAccountToken
+			assert.Equal(t, "sts.amazonaws.com", projection.Audience)
+			assert.Equal(t, int64(86400), *projection.ExpirationSeconds)
+		}
+
+		primary := p.Spec.Containers[0]
+		assert.Contains(t, primary.Env, corev1.EnvVar{Name: AWSRoleArnEnvVar, Value: "snacks-production-role"})
+		assert.Contains(t, primary.Env, corev1.EnvVar{Name: AWSWebIdentityTokenEnvVar,
+			Value: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token"})
+		assert.Contains(t, primary.VolumeMounts, corev1.VolumeMount{Name: AWSWebIdentityTokenVolume, ReadOnly: true,
+			MountPath: AWSWebIdentityTokenDir})
+	})
+
+	t.Run("gcp", func(t *testing.T) {
+		gcpCfg := cfg
+		gcpCfg.Provider = config.WorkloadIdentityProviderGCP
+		p, changed, err := NewWorkloadIdentityInjector(gcpCfg).Mutate(ctx, newPod("flytesnacks", "development", nil))
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "snacks-role", p.Annotations[GCPServiceAccountAnnotation])
+		assert.Empty(t, p.Spec.Volumes)
+	})
+
+	t.Run("no identity", func(t *testing.T) {
+		noDefault := cfg
+		noDefault.DefaultIdentity = ""
+		_, changed, err := NewWorkloadIdentityInjector(noDefault).Mutate(ctx, newPod("other", "development", nil))
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+}
+
+func TestPodMutator_ObjectSelector(t *testing.T) {
+	cfg := &config.Config{CertDir: "testdata", ServiceName: "my-service"}
+	c, err := NewPodMutator(cfg, promutils.NewTestScope()).CreateMutationWebhookConfiguration("ns")
+	assert.NoError(t, err)
+	assert.Equal(t, "true", c.Webhooks[0].ObjectSelector.MatchLabels["inject-flyte-secrets"])
+
+	cfg.WorkloadIdentity = config.DefaultConfig.WorkloadIdentity
+	cfg.WorkloadIdentity.Enabled = true
+	c, err = NewPodMutator(cfg, promutils.NewTestScope()).CreateMutationWebhookConfiguration("ns")
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.LabelSelectorRequirement{{Key: "project", Operator: v1.LabelSelectorOpExists}},
+		c.Webhooks[0].ObjectSelector.MatchExpressions)
+}